@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/audit"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// NewAuditCmd creates the audit command
+func NewAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Review the audit log of destructive operations",
+		Long: `Review the local audit log of destructive operations.
+
+Every k8s cleanup, docker clean and gitlab trigger run is recorded with
+who ran it, against which cluster/host/project, the affected resources,
+whether it was a dry-run, and the result.
+
+The log is append-only JSONL at ~/.devops-toolkit/audit.jsonl
+(override with audit.file in the config file).`,
+		Example: `  devops-toolkit audit --since 24h
+  devops-toolkit audit --command "k8s cleanup" --details
+  devops-toolkit audit --failed --output json`,
+		RunE: runAudit,
+	}
+
+	cmd.Flags().Duration("since", 0, "Only show entries newer than this (e.g. 1h, 168h)")
+	cmd.Flags().String("command", "", "Filter by command (e.g. \"k8s cleanup\")")
+	cmd.Flags().String("user", "", "Filter by user")
+	cmd.Flags().Bool("failed", false, "Show only failed or partial operations")
+	cmd.Flags().Bool("no-dry-run", false, "Hide dry-run entries")
+	cmd.Flags().Bool("details", false, "List the affected resources of each entry")
+	cmd.Flags().Int("limit", 50, "Maximum number of entries to show (0 for all)")
+
+	return cmd
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	since, _ := cmd.Flags().GetDuration("since")
+	command, _ := cmd.Flags().GetString("command")
+	user, _ := cmd.Flags().GetString("user")
+	failedOnly, _ := cmd.Flags().GetBool("failed")
+	noDryRun, _ := cmd.Flags().GetBool("no-dry-run")
+	details, _ := cmd.Flags().GetBool("details")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	entries, err := audit.Read()
+	if err != nil {
+		return err
+	}
+
+	var filtered []audit.Entry
+	for _, e := range entries {
+		if since > 0 && time.Since(e.Time) > since {
+			continue
+		}
+		if command != "" && !strings.Contains(e.Command, command) {
+			continue
+		}
+		if user != "" && e.User != user {
+			continue
+		}
+		if failedOnly && e.Result != audit.ResultFailed && e.Result != audit.ResultPartial {
+			continue
+		}
+		if noDryRun && e.DryRun {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+
+	// Newest first
+	for i, j := 0, len(filtered)-1; i < j; i, j = i+1, j-1 {
+		filtered[i], filtered[j] = filtered[j], filtered[i]
+	}
+	if limit > 0 && len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+
+	if output.IsStructured() {
+		return output.Render(filtered)
+	}
+
+	output.Header("Audit Log")
+	output.Muted("  " + audit.Path())
+	output.Newline()
+
+	if len(filtered) == 0 {
+		output.Info("No audit entries found")
+		return nil
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Destructive Operations",
+		Headers:    []string{"Time", "User", "Command", "Action", "Target", "Resources", "Result"},
+		ShowBorder: true,
+	})
+
+	for _, e := range filtered {
+		row := []string{
+			format.Timestamp(e.Time),
+			e.User,
+			e.Command,
+			e.Action,
+			output.Truncate(e.Target, 40),
+			fmt.Sprintf("%d", len(e.Resources)),
+			e.Result,
+		}
+		table.AddColoredRow(row, resultColors(e.Result))
+	}
+
+	table.Render()
+
+	if details {
+		for _, e := range filtered {
+			output.Newline()
+			output.Print(output.SubSection(fmt.Sprintf("%s  %s %s", format.Timestamp(e.Time), e.Command, e.Action)))
+			for _, r := range e.Resources {
+				output.Printf("  %s %s\n", output.MutedStyle.Render(output.IconBullet), r)
+			}
+			if e.Error != "" {
+				output.Printf("  %s %s\n", output.ErrorStyle.Render(output.IconError), e.Error)
+			}
+		}
+	}
+
+	output.Newline()
+	return nil
+}
+
+func resultColors(result string) []tablewriter.Colors {
+	var resultColor tablewriter.Colors
+	switch result {
+	case audit.ResultSuccess:
+		resultColor = tablewriter.Colors{tablewriter.FgGreenColor}
+	case audit.ResultPartial:
+		resultColor = tablewriter.Colors{tablewriter.FgYellowColor}
+	case audit.ResultFailed:
+		resultColor = tablewriter.Colors{tablewriter.FgRedColor}
+	default:
+		resultColor = tablewriter.Colors{tablewriter.FgHiBlackColor}
+	}
+
+	return []tablewriter.Colors{{}, {}, {}, {}, {}, {}, resultColor}
+}
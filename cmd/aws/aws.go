@@ -0,0 +1,41 @@
+package aws
+
+import (
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/aws"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// NewAWSCmd creates the aws command
+func NewAWSCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "aws",
+		Short: "AWS operations",
+		Long: `AWS operations.
+
+Uses the AWS SDK's default credential chain, so profiles, SSO, instance
+and task roles and environment variables all work.`,
+	}
+
+	// Add subcommands
+	cmd.AddCommand(newECRCmd())
+
+	// Persistent flags for aws commands
+	cmd.PersistentFlags().String("aws-profile", "", "AWS shared config profile (default from aws.profile or AWS_PROFILE)")
+	cmd.PersistentFlags().String("region", "", "AWS region (default from aws.region or the shared config)")
+
+	return cmd
+}
+
+func getClient(cmd *cobra.Command) (*aws.Client, error) {
+	profile := cmd.Flag("aws-profile").Value.String()
+	if profile == "" {
+		profile = viper.GetString("aws.profile")
+	}
+	region := cmd.Flag("region").Value.String()
+	if region == "" {
+		region = viper.GetString("aws.region")
+	}
+
+	return aws.NewClient(profile, region)
+}
@@ -0,0 +1,261 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/aws"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newECRCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ecr",
+		Short: "Amazon ECR repositories and images",
+	}
+
+	cmd.AddCommand(newReposCmd())
+	cmd.AddCommand(newImagesCmd())
+	cmd.AddCommand(newLifecycleCmd())
+	cmd.AddCommand(newCleanupCmd())
+
+	return cmd
+}
+
+func newReposCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "repos",
+		Aliases: []string{"repositories"},
+		Short:   "List ECR repositories",
+		Args:    cobra.NoArgs,
+		RunE:    runRepos,
+	}
+
+	return cmd
+}
+
+func newImagesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "images REPOSITORY",
+		Short: "List images with scan findings",
+		Long: `List the images of a repository, newest first, with their
+vulnerability scan findings.`,
+		Example: `  devops-toolkit aws ecr images api --region eu-west-1
+  devops-toolkit aws ecr images api --vulnerable`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.ECRRepositoryCompletion,
+		RunE:              runImages,
+	}
+
+	cmd.Flags().Bool("vulnerable", false, "Only show images with critical or high findings")
+	cmd.Flags().Int("limit", 0, "Show at most this many images (0 for all)")
+
+	return cmd
+}
+
+func runRepos(cmd *cobra.Command, args []string) error {
+	output.StartSpinner("Fetching ECR repositories...")
+
+	client, err := getClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to initialize AWS client")
+		return err
+	}
+
+	repos, err := client.ListRepositories(cmd.Context())
+	if err != nil {
+		output.SpinnerError("Failed to list repositories")
+		return fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Found %d repositories", len(repos)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(repos)
+	}
+
+	if len(repos) == 0 {
+		output.Info("No ECR repositories found")
+		return nil
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      fmt.Sprintf("ECR Repositories (%s)", client.Region()),
+		Headers:    []string{"Name", "URI", "Scan On Push", "Tags", "Age"},
+		ShowBorder: true,
+	})
+
+	var noScan int
+	for _, r := range repos {
+		scan := "yes"
+		scanColor := tablewriter.Colors{tablewriter.FgGreenColor}
+		if !r.ScanOnPush {
+			scan = "no"
+			scanColor = tablewriter.Colors{tablewriter.FgYellowColor}
+			noScan++
+		}
+
+		table.AddColoredRow([]string{
+			r.Name,
+			output.Truncate(r.URI, 70),
+			scan,
+			strings.ToLower(r.TagMutability),
+			format.Age(r.CreatedAt),
+		}, []tablewriter.Colors{
+			{tablewriter.FgCyanColor},
+			{tablewriter.FgHiBlackColor},
+			scanColor,
+			{},
+			{tablewriter.FgHiBlackColor},
+		})
+	}
+
+	table.Render()
+
+	if noScan > 0 {
+		output.Newline()
+		output.Warning(fmt.Sprintf("%d repositories do not scan images on push", noScan))
+	}
+
+	return nil
+}
+
+func runImages(cmd *cobra.Command, args []string) error {
+	output.StartSpinner("Fetching ECR images...")
+
+	client, err := getClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to initialize AWS client")
+		return err
+	}
+
+	vulnerableOnly, _ := cmd.Flags().GetBool("vulnerable")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	images, err := client.ListImages(cmd.Context(), args[0])
+	if err != nil {
+		output.SpinnerError("Failed to list images")
+		return fmt.Errorf("failed to list images: %w", err)
+	}
+
+	if vulnerableOnly {
+		var filtered []aws.Image
+		for _, img := range images {
+			if img.Critical()+img.High() > 0 {
+				filtered = append(filtered, img)
+			}
+		}
+		images = filtered
+	}
+	if limit > 0 && len(images) > limit {
+		images = images[:limit]
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Found %d images", len(images)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(images)
+	}
+
+	if len(images) == 0 {
+		output.Info("No images found")
+		return nil
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      fmt.Sprintf("ECR Images: %s", args[0]),
+		Headers:    []string{"Tags", "Digest", "Size", "Pushed", "Last Pull", "Scan", "Critical", "High", "Other"},
+		ShowBorder: true,
+	})
+
+	var vulnerable int
+	for _, img := range images {
+		tags := "<untagged>"
+		if len(img.Tags) > 0 {
+			tags = output.Truncate(strings.Join(img.Tags, ","), 40)
+		}
+
+		other := 0
+		for severity, n := range img.Findings {
+			if severity != "CRITICAL" && severity != "HIGH" {
+				other += n
+			}
+		}
+		if img.Critical()+img.High() > 0 {
+			vulnerable++
+		}
+
+		scan := strings.ToLower(img.ScanStatus)
+		if scan == "" {
+			scan = "none"
+		}
+
+		table.AddColoredRow([]string{
+			tags,
+			shortDigest(img.Digest),
+			format.Size(img.Size),
+			format.Age(img.PushedAt),
+			format.Age(img.LastPulledAt),
+			scan,
+			fmt.Sprintf("%d", img.Critical()),
+			fmt.Sprintf("%d", img.High()),
+			fmt.Sprintf("%d", other),
+		}, []tablewriter.Colors{
+			{tablewriter.FgCyanColor},
+			{tablewriter.FgHiBlackColor},
+			{},
+			{tablewriter.FgHiBlackColor},
+			{tablewriter.FgHiBlackColor},
+			scanColor(img.ScanStatus),
+			findingColor(img.Critical(), tablewriter.FgRedColor),
+			findingColor(img.High(), tablewriter.FgYellowColor),
+			{},
+		})
+	}
+
+	table.Render()
+
+	output.Newline()
+	if vulnerable > 0 {
+		output.Warning(fmt.Sprintf("%d images have critical or high findings", vulnerable))
+	} else {
+		output.Success("No critical or high findings")
+	}
+
+	return nil
+}
+
+func scanColor(status string) tablewriter.Colors {
+	switch status {
+	case "COMPLETE", "ACTIVE":
+		return tablewriter.Colors{tablewriter.FgGreenColor}
+	case "FAILED", "UNSUPPORTED_IMAGE":
+		return tablewriter.Colors{tablewriter.FgRedColor}
+	case "":
+		return tablewriter.Colors{tablewriter.FgHiBlackColor}
+	default:
+		return tablewriter.Colors{tablewriter.FgYellowColor}
+	}
+}
+
+func findingColor(n int, color int) tablewriter.Colors {
+	if n == 0 {
+		return tablewriter.Colors{tablewriter.FgHiBlackColor}
+	}
+	return tablewriter.Colors{color, tablewriter.Bold}
+}
+
+// shortDigest shortens sha256:abcdef... to its first 12 hex characters
+func shortDigest(digest string) string {
+	digest = strings.TrimPrefix(digest, "sha256:")
+	if len(digest) > 12 {
+		return digest[:12]
+	}
+	return digest
+}
@@ -0,0 +1,327 @@
+package aws
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/audit"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/aws"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/retention"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newLifecycleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lifecycle REPOSITORY",
+		Short: "Preview what a lifecycle policy would expire",
+		Long: `Dry-run an ECR lifecycle policy against the current images of a
+repository and list the images it would expire.
+
+Uses the repository's own policy, or --policy to try out a new one before
+putting it in place. Nothing is deleted.`,
+		Example: `  devops-toolkit aws ecr lifecycle api
+  devops-toolkit aws ecr lifecycle api --policy lifecycle.json`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.ECRRepositoryCompletion,
+		RunE:              runLifecycle,
+	}
+
+	cmd.Flags().String("policy", "", "Lifecycle policy JSON file to evaluate instead of the repository's policy")
+
+	return cmd
+}
+
+func newCleanupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cleanup [REPOSITORY...]",
+		Short: "Delete stale images",
+		Long: `Delete stale images from ECR repositories.
+
+An image is stale when it is beyond the newest --keep-last images and, if
+--older-than is set, was also pushed before that. Tags matching --keep-tags
+(glob patterns) are never deleted.`,
+		Example: `  devops-toolkit aws ecr cleanup api --keep-last 20
+  devops-toolkit aws ecr cleanup --all --older-than 2160h --keep-tags 'latest,v*'
+  devops-toolkit aws ecr cleanup api --untagged-only --dry-run=false`,
+		ValidArgsFunction: completion.ECRRepositoryCompletion,
+		RunE:              runCleanup,
+	}
+
+	cmd.Flags().Bool("all", false, "Clean up every repository in the region")
+	cmd.Flags().Int("keep-last", 10, "Always keep this many newest images per repository")
+	cmd.Flags().Duration("older-than", 0, "Only delete images pushed before this long ago (e.g. 720h)")
+	cmd.Flags().StringSlice("keep-tags", []string{"latest"}, "Never delete images with tags matching these patterns")
+	cmd.Flags().Bool("untagged-only", false, "Only delete untagged images")
+	cmd.Flags().Bool("dry-run", true, "Show what would be deleted without deleting")
+
+	return cmd
+}
+
+func runLifecycle(cmd *cobra.Command, args []string) error {
+	repo := args[0]
+	policyFile, _ := cmd.Flags().GetString("policy")
+
+	output.StartSpinner("Evaluating lifecycle policy...")
+
+	client, err := getClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to initialize AWS client")
+		return err
+	}
+	ctx := cmd.Context()
+
+	var policyText string
+	if policyFile != "" {
+		data, err := os.ReadFile(policyFile)
+		if err != nil {
+			output.SpinnerError("Failed to read policy")
+			return fmt.Errorf("failed to read policy: %w", err)
+		}
+		policyText = string(data)
+	} else {
+		policyText, err = client.GetLifecyclePolicy(ctx, repo)
+		if err != nil {
+			output.SpinnerError("Failed to fetch lifecycle policy")
+			return fmt.Errorf("failed to fetch lifecycle policy: %w", err)
+		}
+		if policyText == "" {
+			output.StopSpinner()
+			output.Warning(fmt.Sprintf("Repository %s has no lifecycle policy; images are kept forever", repo))
+			return nil
+		}
+	}
+
+	rules, err := aws.ParseLifecyclePolicy(policyText)
+	if err != nil {
+		output.SpinnerError("Invalid lifecycle policy")
+		return err
+	}
+
+	images, err := client.ListImages(ctx, repo)
+	if err != nil {
+		output.SpinnerError("Failed to list images")
+		return fmt.Errorf("failed to list images: %w", err)
+	}
+
+	decisions := retention.Evaluate(retentionImages(images), rules, time.Now())
+	expired := retention.Expired(decisions)
+	output.SpinnerSuccess(fmt.Sprintf("Evaluated %d rules against %d images", len(rules), len(images)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(decisions)
+	}
+
+	output.Print(output.Section("Rules"))
+	for _, r := range rules {
+		desc := r.Description
+		if desc == "" {
+			desc = "(no description)"
+		}
+		output.Printf("  %s %s\n", output.InfoStyle.Render(fmt.Sprintf("#%d", r.Priority)), desc)
+	}
+	output.Newline()
+
+	if len(expired) == 0 {
+		output.Success("The policy would not expire any images")
+		return nil
+	}
+
+	displayDecisions(fmt.Sprintf("Images Expired by Policy: %s", repo), expired)
+	output.Newline()
+	output.Info(fmt.Sprintf("%d of %d images would expire, reclaiming %s; ECR applies lifecycle policies itself within 24 hours",
+		len(expired), len(images), format.Size(totalSize(expired))))
+
+	return nil
+}
+
+func runCleanup(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	keepLast, _ := cmd.Flags().GetInt("keep-last")
+	olderThan, _ := cmd.Flags().GetDuration("older-than")
+	keepTags, _ := cmd.Flags().GetStringSlice("keep-tags")
+	untaggedOnly, _ := cmd.Flags().GetBool("untagged-only")
+
+	if len(args) == 0 && !all {
+		return fmt.Errorf("specify repositories or --all")
+	}
+	if keepLast <= 0 && olderThan == 0 && !untaggedOnly {
+		return fmt.Errorf("refusing to delete every image; set --keep-last, --older-than or --untagged-only")
+	}
+
+	policy := retention.Policy{
+		KeepLast:     keepLast,
+		MaxAge:       olderThan,
+		KeepTags:     keepTags,
+		UntaggedOnly: untaggedOnly,
+	}
+
+	client, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+
+	repos := args
+	if all {
+		output.StartSpinner("Fetching ECR repositories...")
+		list, err := client.ListRepositories(ctx)
+		if err != nil {
+			output.SpinnerError("Failed to list repositories")
+			return fmt.Errorf("failed to list repositories: %w", err)
+		}
+		output.StopSpinner()
+		repos = nil
+		for _, r := range list {
+			repos = append(repos, r.Name)
+		}
+	}
+
+	output.Header("ECR Cleanup")
+	if dryRun {
+		output.Info("Running in dry-run mode (no images will be deleted)")
+		output.Newline()
+	}
+
+	var results []retention.Decision
+	var totalDeleted int
+	var totalSpace int64
+
+	for _, repo := range repos {
+		output.StartSpinner(fmt.Sprintf("Evaluating %s...", repo))
+		images, err := client.ListImages(ctx, repo)
+		if err != nil {
+			output.SpinnerError(fmt.Sprintf("Failed to list images of %s", repo))
+			continue
+		}
+
+		expired := retention.Expired(retention.Evaluate(retentionImages(images), policy.Rules(), time.Now()))
+		output.StopSpinner()
+		results = append(results, expired...)
+
+		if len(expired) == 0 {
+			output.Success(fmt.Sprintf("%s: nothing to clean up", repo))
+			continue
+		}
+
+		output.Printf("\n%s %s: %d of %d images are stale (%s)\n",
+			output.InfoStyle.Render(output.IconInfo), repo, len(expired), len(images), format.Size(totalSize(expired)))
+
+		var digests, names []string
+		for _, d := range expired {
+			digests = append(digests, d.Digest)
+			names = append(names, imageName(d))
+			output.Printf("  %s %s %s\n", output.MutedStyle.Render(output.IconBullet),
+				imageName(d), output.MutedStyle.Render("("+d.Reason+")"))
+		}
+
+		if dryRun {
+			recordCleanup(client, names, true, 0, nil)
+			continue
+		}
+
+		deleted, err := client.DeleteImages(ctx, repo, digests)
+		if err != nil {
+			output.Error(fmt.Sprintf("Failed to delete some images: %v", err))
+		}
+		totalDeleted += deleted
+		if deleted == len(expired) {
+			totalSpace += totalSize(expired)
+		}
+		output.Successf("Deleted %d images from %s", deleted, repo)
+		recordCleanup(client, names, false, deleted, err)
+	}
+
+	if output.IsStructured() {
+		output.Newline()
+		return output.Render(results)
+	}
+
+	output.Newline()
+	if dryRun {
+		output.Info(fmt.Sprintf("Would delete %d images (%s). Run with --dry-run=false to delete.",
+			len(results), format.Size(totalSize(results))))
+	} else {
+		output.Successf("Deleted %d images, reclaimed %s", totalDeleted, format.Size(totalSpace))
+	}
+
+	return nil
+}
+
+// recordCleanup writes an ECR cleanup operation to the audit log
+func recordCleanup(client *aws.Client, resources []string, dryRun bool, deleted int, err error) {
+	entry := audit.Entry{
+		Command:   "aws ecr cleanup",
+		Action:    "delete-ecr-images",
+		Target:    "ecr/" + client.Region(),
+		Resources: resources,
+		DryRun:    dryRun,
+		Result:    audit.Result(dryRun, deleted, len(resources), err),
+	}
+	if auditErr := audit.Record(entry, err); auditErr != nil {
+		output.Warning(auditErr.Error())
+	}
+}
+
+func displayDecisions(title string, decisions []retention.Decision) {
+	table := output.NewTable(output.TableConfig{
+		Title:      title,
+		Headers:    []string{"Tags", "Digest", "Size", "Pushed", "Rule", "Reason"},
+		ShowBorder: true,
+	})
+
+	for _, d := range decisions {
+		tags := "<untagged>"
+		if len(d.Tags) > 0 {
+			tags = output.Truncate(strings.Join(d.Tags, ","), 40)
+		}
+		table.AddColoredRow([]string{
+			tags,
+			shortDigest(d.Digest),
+			format.Size(d.Size),
+			format.Age(d.PushedAt),
+			fmt.Sprintf("#%d", d.Rule),
+			d.Reason,
+		}, []tablewriter.Colors{
+			{tablewriter.FgCyanColor},
+			{tablewriter.FgHiBlackColor},
+			{},
+			{tablewriter.FgHiBlackColor},
+			{},
+			{tablewriter.FgYellowColor},
+		})
+	}
+
+	table.Render()
+}
+
+func retentionImages(images []aws.Image) []retention.Image {
+	result := make([]retention.Image, 0, len(images))
+	for _, img := range images {
+		result = append(result, img.Image)
+	}
+	return result
+}
+
+func totalSize(decisions []retention.Decision) int64 {
+	var total int64
+	for _, d := range decisions {
+		total += d.Size
+	}
+	return total
+}
+
+// imageName renders repo:tag, or repo@digest for untagged images
+func imageName(d retention.Decision) string {
+	if len(d.Tags) > 0 {
+		return d.Repository + ":" + strings.Join(d.Tags, ",")
+	}
+	return d.Repository + "@" + shortDigest(d.Digest)
+}
@@ -0,0 +1,194 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/bundle"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/logging"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// NewBundleCmd creates the bundle command
+func NewBundleCmd(version string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Collect diagnostics into a support bundle",
+		Long: `Collect diagnostics into a tarball to attach to bug reports.
+
+The bundle contains:
+  • Cluster info, node and pod health, pod and node listings
+  • Recent warning events
+  • Docker daemon info and containers
+  • The toolkit config, with tokens, passwords, secrets and webhooks redacted
+  • Debug logs of every API call made while collecting
+
+Sources that cannot be reached are listed in manifest.json instead of failing
+the bundle. Review the contents before sharing it.`,
+		Example: `  devops-toolkit bundle
+  devops-toolkit bundle -n payments -f /tmp/bundle.tar.gz
+  devops-toolkit bundle --skip-docker`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBundle(cmd, version)
+		},
+	}
+
+	cmd.Flags().StringP("file", "f", "", "Bundle path (default devops-toolkit-bundle-<timestamp>.tar.gz)")
+	cmd.Flags().StringP("namespace", "n", "", "Namespace to snapshot pods and events from (default all)")
+	cmd.Flags().String("kubeconfig", "", "Path to kubeconfig file")
+	cmd.Flags().StringP("context", "c", "", "Kubernetes context to use")
+	cmd.Flags().Int("events", 100, "Maximum number of warning events to include")
+	cmd.Flags().Bool("skip-k8s", false, "Do not collect Kubernetes diagnostics")
+	cmd.Flags().Bool("skip-docker", false, "Do not collect Docker diagnostics")
+
+	_ = cmd.RegisterFlagCompletionFunc("namespace", completion.NamespaceCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("context", completion.ContextCompletion)
+
+	return cmd
+}
+
+func runBundle(cmd *cobra.Command, version string) error {
+	path, _ := cmd.Flags().GetString("file")
+	skipK8s, _ := cmd.Flags().GetBool("skip-k8s")
+	skipDocker, _ := cmd.Flags().GetBool("skip-docker")
+
+	stamp := time.Now().Format("20060102-150405")
+	root := "devops-toolkit-bundle-" + stamp
+	if path == "" {
+		path = root + ".tar.gz"
+	}
+
+	// Capture debug logs of the collection itself unless they already go to --log-file
+	logFile, _ := cmd.Flags().GetString("log-file")
+	if logFile == "" {
+		tmp, err := os.CreateTemp("", "devops-toolkit-bundle-*.log")
+		if err != nil {
+			return fmt.Errorf("failed to create log file: %w", err)
+		}
+		tmp.Close()
+		defer os.Remove(tmp.Name())
+		logFile = tmp.Name()
+		if err := logging.Setup(viper.GetBool("verbose"), logFile); err != nil {
+			return err
+		}
+	}
+
+	b, err := bundle.Create(path, root)
+	if err != nil {
+		return err
+	}
+
+	output.StartSpinner("Collecting toolkit config...")
+	collectConfig(b)
+
+	if !skipK8s {
+		output.UpdateSpinner("Collecting Kubernetes diagnostics...")
+		collectKubernetes(cmd, b)
+	}
+	if !skipDocker {
+		output.UpdateSpinner("Collecting Docker diagnostics...")
+		collectDocker(cmd, b)
+	}
+
+	output.UpdateSpinner("Writing bundle...")
+	if err := b.AddFile("logs/debug.log", logFile); err != nil {
+		b.AddCollected("logs/debug.log", nil, err)
+	}
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+	if err := b.Close(version, cmd.CommandPath(), platform); err != nil {
+		output.SpinnerError("Failed to write bundle")
+		return err
+	}
+
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	output.SpinnerSuccess(fmt.Sprintf("Support bundle written to %s", path))
+
+	if errs := b.Errors(); len(errs) > 0 {
+		output.Newline()
+		output.Warning(fmt.Sprintf("%d items could not be collected (see manifest.json):", len(errs)))
+		names := make([]string, 0, len(errs))
+		for name := range errs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			output.Printf("  %s %s: %s\n", output.MutedStyle.Render(output.IconBullet), name, errs[name])
+		}
+	}
+	output.Newline()
+	output.Muted("Review the bundle before sharing it; config secrets are redacted but resource names are not.")
+	return nil
+}
+
+// collectConfig stores the effective config with sensitive values redacted
+func collectConfig(b *bundle.Bundle) {
+	data, err := bundle.RedactConfig(viper.AllSettings())
+	if err == nil {
+		err = b.Add("config/config.yaml", data)
+	}
+	if err != nil {
+		b.AddCollected("config/config.yaml", nil, err)
+	}
+	b.AddCollected("config/source.json", map[string]string{"file": viper.ConfigFileUsed()}, nil)
+}
+
+func collectKubernetes(cmd *cobra.Command, b *bundle.Bundle) {
+	client, err := k8s.NewClient(
+		cmd.Flag("kubeconfig").Value.String(),
+		cmd.Flag("context").Value.String(),
+	)
+	if err != nil {
+		b.AddCollected("k8s/cluster-info.json", nil, err)
+		return
+	}
+
+	ctx := cmd.Context()
+	namespace := cmd.Flag("namespace").Value.String()
+	eventLimit, _ := cmd.Flags().GetInt("events")
+
+	info, err := client.GetClusterInfo(ctx)
+	b.AddCollected("k8s/cluster-info.json", info, err)
+
+	nodeHealth, err := client.GetNodeHealth(ctx)
+	b.AddCollected("k8s/node-health.json", nodeHealth, err)
+
+	nodes, err := client.ListNodes(ctx)
+	b.AddCollected("k8s/nodes.json", nodes, err)
+
+	podHealth, err := client.GetPodHealth(ctx, namespace)
+	b.AddCollected("k8s/pod-health.json", podHealth, err)
+
+	pods, err := client.ListPods(ctx, namespace, "")
+	b.AddCollected("k8s/pods.json", pods, err)
+
+	events, err := client.GetWarningEvents(ctx, namespace, eventLimit)
+	b.AddCollected("k8s/warning-events.json", events, err)
+}
+
+func collectDocker(cmd *cobra.Command, b *bundle.Bundle) {
+	client, err := docker.NewClient()
+	if err != nil {
+		b.AddCollected("docker/host.json", nil, err)
+		return
+	}
+	defer client.Close()
+
+	ctx := cmd.Context()
+
+	host, err := client.GetHostInfo(ctx)
+	b.AddCollected("docker/host.json", host, err)
+
+	containers, err := client.ListContainers(ctx, true, "")
+	b.AddCollected("docker/containers.json", containers, err)
+}
@@ -2,14 +2,22 @@ package compliance
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/compliance"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/notify"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func newCheckCmd() *cobra.Command {
@@ -26,8 +34,10 @@ Targets:
 
 Examples:
   devops-toolkit compliance check k8s
+  devops-toolkit compliance check k8s --context prod-cluster
   devops-toolkit compliance check docker --image nginx:latest
-  devops-toolkit compliance check files --path ./manifests`,
+  devops-toolkit compliance check files --path ./manifests
+  devops-toolkit compliance check k8s --fail-on medium --max-failures 5`,
 		Args:              cobra.MinimumNArgs(1),
 		RunE:              runCheck,
 		SilenceUsage:      true, // Don't show usage on compliance failures
@@ -35,17 +45,34 @@ Examples:
 	}
 
 	cmd.Flags().String("image", "", "Docker image to check")
+	addImageFlags(cmd)
 	cmd.Flags().String("path", ".", "Path to files to check")
+	cmd.Flags().StringSlice("include", nil, "Only check files matching these globs (files target)")
+	cmd.Flags().StringSlice("exclude", nil, "Skip files matching these globs, in addition to .complianceignore (files target)")
+	cmd.Flags().Bool("no-cache", false, "Re-check every file instead of reusing cached results (files target)")
+	cmd.Flags().StringSlice("allowed-registries", nil, "Approved image registries or registry/org prefixes (default from compliance.allowed_registries)")
+	cmd.Flags().Bool("verify-provenance", false, "Query registries for image signatures and attestations")
 	cmd.Flags().StringP("namespace", "n", "", "Kubernetes namespace")
+	cmd.Flags().StringSlice("namespaces", nil, "Only check these namespaces, globs allowed (k8s target)")
+	cmd.Flags().StringSlice("exclude-namespaces", nil, "Skip these namespaces, globs allowed (k8s target)")
 	cmd.Flags().StringSlice("skip", nil, "Rules to skip")
 	cmd.Flags().StringSlice("only", nil, "Only run these rules")
 	cmd.Flags().String("severity", "", "Minimum severity to report (low, medium, high, critical)")
-	cmd.Flags().Bool("fail-on-warn", false, "Exit with error on warnings")
+	cmd.Flags().Bool("fail-on-warn", false, "Exit with error on warnings; same as --fail-on low")
+	cmd.Flags().String("fail-on", "high", "Minimum severity that fails the check (none, low, medium, high, critical)")
+	cmd.Flags().Int("max-failures", 0, "Number of failures at or above --fail-on to tolerate before failing")
+	cmd.Flags().Bool("notify", false, "Post the result to the configured Slack/Teams/webhook sinks")
+	addGroupingFlags(cmd)
 
 	// Register flag completions
 	_ = cmd.RegisterFlagCompletionFunc("namespace", completion.NamespaceCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("namespaces", completion.NamespaceCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("exclude-namespaces", completion.NamespaceCompletion)
 	_ = cmd.RegisterFlagCompletionFunc("image", completion.ImageCompletion)
 	_ = cmd.RegisterFlagCompletionFunc("severity", completion.SeverityCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("skip", completion.RuleCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("only", completion.RuleCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("fail-on", completion.FailOnCompletion)
 
 	return cmd
 }
@@ -53,42 +80,102 @@ Examples:
 func runCheck(cmd *cobra.Command, args []string) error {
 	target := strings.ToLower(args[0])
 
+	failOn, _ := cmd.Flags().GetString("fail-on")
+	switch failOn {
+	case "none", "low", "medium", "high", "critical":
+	default:
+		return exitcode.ConfigError(fmt.Errorf("invalid --fail-on value: %s (valid: none, low, medium, high, critical)", failOn))
+	}
+	// --fail-on-warn is shorthand for --fail-on low, so both together are ambiguous
+	if failOnWarn, _ := cmd.Flags().GetBool("fail-on-warn"); failOnWarn {
+		if cmd.Flags().Changed("fail-on") {
+			return exitcode.ConfigError(fmt.Errorf("--fail-on-warn cannot be combined with --fail-on"))
+		}
+		failOn = "low"
+	}
+
+	maxFailures, _ := cmd.Flags().GetInt("max-failures")
+	if maxFailures < 0 {
+		return exitcode.ConfigError(fmt.Errorf("invalid --max-failures value: %d (must be 0 or more)", maxFailures))
+	}
+
+	groupBy, owners, err := groupingOptions(cmd)
+	if err != nil {
+		return err
+	}
+
 	output.Header("Compliance Check")
 
 	skipRules, _ := cmd.Flags().GetStringSlice("skip")
 	onlyRules, _ := cmd.Flags().GetStringSlice("only")
 	minSeverity, _ := cmd.Flags().GetString("severity")
+	namespace, _ := cmd.Flags().GetString("namespace")
+	namespaces, _ := cmd.Flags().GetStringSlice("namespaces")
+	excludeNamespaces, _ := cmd.Flags().GetStringSlice("exclude-namespaces")
+	imageName, _ := cmd.Flags().GetString("image")
+	path, _ := cmd.Flags().GetString("path")
+	include, _ := cmd.Flags().GetStringSlice("include")
+	exclude, _ := cmd.Flags().GetStringSlice("exclude")
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	allowedRegistries, _ := cmd.Flags().GetStringSlice("allowed-registries")
+	if len(allowedRegistries) == 0 {
+		allowedRegistries = viper.GetStringSlice("compliance.allowed_registries")
+	}
+	verifyProvenance, _ := cmd.Flags().GetBool("verify-provenance")
 
 	opts := compliance.CheckOptions{
+		Namespace:   namespace,
+		Image:       imageName,
+		Path:        path,
+		Include:     include,
+		Exclude:     exclude,
 		SkipRules:   skipRules,
 		OnlyRules:   onlyRules,
 		MinSeverity: minSeverity,
+		NoCache:     noCache,
+
+		AllowedRegistries: allowedRegistries,
+		VerifyProvenance:  verifyProvenance,
+		Namespaces:        k8s.NamespaceSelector{Include: namespaces, Exclude: excludeNamespaces},
+	}
+	opts.GroupLabel, _ = cmd.Flags().GetString("group-label")
+	if err := imageOptions(cmd, &opts); err != nil {
+		return err
+	}
+	if verifyProvenance {
+		creds, err := registryCredentials()
+		if err != nil {
+			return err
+		}
+		opts.RegistryCredentials = creds
 	}
 
+	opts.Progress = output.UpdateSpinner
+
 	var results []compliance.CheckResult
-	var err error
+	var timings []checkerTiming
 
 	switch target {
 	case "k8s", "kubernetes":
-		namespace, _ := cmd.Flags().GetString("namespace")
-		opts.Namespace = namespace
 		output.StartSpinner("Checking Kubernetes resources...")
-		results, err = runK8sChecks(cmd.Context(), opts)
+		results, err = timeChecker(&timings, "Kubernetes", func() ([]compliance.CheckResult, error) {
+			return runK8sChecks(cmd, opts)
+		})
 	case "docker":
-		imageName, _ := cmd.Flags().GetString("image")
-		opts.Image = imageName
 		output.StartSpinner("Checking Docker resources...")
-		results, err = runDockerChecks(cmd.Context(), opts)
+		results, err = timeChecker(&timings, "Docker", func() ([]compliance.CheckResult, error) {
+			return runDockerChecks(cmd.Context(), opts)
+		})
 	case "files", "file":
-		path, _ := cmd.Flags().GetString("path")
-		opts.Path = path
 		output.StartSpinner("Checking configuration files...")
-		results, err = runFileChecks(cmd.Context(), opts)
+		results, err = timeChecker(&timings, "Files", func() ([]compliance.CheckResult, error) {
+			return runFileChecks(cmd.Context(), opts)
+		})
 	case "all":
 		output.StartSpinner("Running all compliance checks...")
-		results, err = runAllChecks(cmd.Context(), opts)
+		results, err = runAllChecks(cmd, opts, &timings)
 	default:
-		return fmt.Errorf("unknown target: %s", target)
+		return exitcode.ConfigError(fmt.Errorf("unknown target: %s", target))
 	}
 
 	if err != nil {
@@ -98,32 +185,39 @@ func runCheck(cmd *cobra.Command, args []string) error {
 
 	output.StopSpinner()
 	displayResults(results)
+	displayTimings(timings)
+	if groupBy != "" {
+		displayGroups(compliance.GroupResults(results, groupBy, owners), groupBy)
+	}
 
 	// Determine exit status
-	failOnWarn, _ := cmd.Flags().GetBool("fail-on-warn")
-	hasErrors := false
-	hasWarnings := false
-
-	for _, r := range results {
-		if r.Status == compliance.StatusFailed {
-			if r.Severity == "critical" || r.Severity == "high" {
-				hasErrors = true
-			} else {
-				hasWarnings = true
-			}
+	failures := countGatingFailures(results, failOn)
+	if notifyResult, _ := cmd.Flags().GetBool("notify"); notifyResult {
+		sendNotification(cmd.Context(), target, results, failures > maxFailures)
+		if owners != nil {
+			notifyOwners(cmd.Context(), target, results, owners, failOn)
 		}
 	}
-
-	if hasErrors || (failOnWarn && hasWarnings) {
-		return fmt.Errorf("compliance check failed")
+	if failures > maxFailures {
+		return exitcode.ChecksFailedf("compliance check failed: %d failures at or above %s severity (max %d)", failures, failOn, maxFailures)
 	}
 
 	return nil
 }
 
-func runK8sChecks(ctx context.Context, opts compliance.CheckOptions) ([]compliance.CheckResult, error) {
-	checker := compliance.NewK8sChecker(opts)
-	return checker.Run(ctx)
+// countGatingFailures counts failed results at or above the fail-on severity
+func countGatingFailures(results []compliance.CheckResult, failOn string) int {
+	if failOn == "none" {
+		return 0
+	}
+
+	count := 0
+	for _, r := range results {
+		if r.Status == compliance.StatusFailed && compliance.MeetsMinSeverity(r.Severity, failOn) {
+			count++
+		}
+	}
+	return count
 }
 
 func runDockerChecks(ctx context.Context, opts compliance.CheckOptions) ([]compliance.CheckResult, error) {
@@ -136,24 +230,79 @@ func runFileChecks(ctx context.Context, opts compliance.CheckOptions) ([]complia
 	return checker.Run(ctx)
 }
 
-func runAllChecks(ctx context.Context, opts compliance.CheckOptions) ([]compliance.CheckResult, error) {
+// runAllChecks runs every checker, timing each one into timings
+func runAllChecks(cmd *cobra.Command, opts compliance.CheckOptions, timings *[]checkerTiming) ([]compliance.CheckResult, error) {
+	ctx := cmd.Context()
 	var allResults []compliance.CheckResult
 
 	// K8s checks
-	k8sResults, _ := runK8sChecks(ctx, opts)
+	k8sResults, _ := timeChecker(timings, "Kubernetes", func() ([]compliance.CheckResult, error) {
+		return runK8sChecks(cmd, opts)
+	})
 	allResults = append(allResults, k8sResults...)
 
-	// Docker checks
-	dockerResults, _ := runDockerChecks(ctx, opts)
+	// Docker checks; without a daemon the Docker rules are reported as skipped
+	dockerResults, err := timeChecker(timings, "Docker", func() ([]compliance.CheckResult, error) {
+		return runDockerChecks(ctx, opts)
+	})
+	if errors.Is(err, compliance.ErrDockerUnavailable) {
+		output.StopSpinner()
+		output.Warningf("Skipping Docker checks: %v", err)
+		output.StartSpinner("Running all compliance checks...")
+		dockerResults = compliance.SkippedDockerResults(opts, "Docker daemon unavailable")
+	}
 	allResults = append(allResults, dockerResults...)
 
 	// File checks
-	fileResults, _ := runFileChecks(ctx, opts)
+	fileResults, _ := timeChecker(timings, "Files", func() ([]compliance.CheckResult, error) {
+		return runFileChecks(ctx, opts)
+	})
 	allResults = append(allResults, fileResults...)
 
 	return allResults, nil
 }
 
+// checkerTiming is how long one checker took and how many results it produced
+type checkerTiming struct {
+	Checker  string
+	Duration time.Duration
+	Results  int
+}
+
+// timeChecker runs a checker and appends its timing to timings
+func timeChecker(timings *[]checkerTiming, checker string, run func() ([]compliance.CheckResult, error)) ([]compliance.CheckResult, error) {
+	start := time.Now()
+	results, err := run()
+	*timings = append(*timings, checkerTiming{Checker: checker, Duration: time.Since(start), Results: len(results)})
+	return results, err
+}
+
+// displayTimings prints how long each checker took, slowest first
+func displayTimings(timings []checkerTiming) {
+	if len(timings) == 0 {
+		return
+	}
+	sorted := append([]checkerTiming(nil), timings...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Duration > sorted[j].Duration
+	})
+
+	var total time.Duration
+	for _, t := range sorted {
+		total += t.Duration
+	}
+
+	output.Print(output.Section("Timing"))
+	for _, t := range sorted {
+		output.Printf("  %-12s %8s  %s\n", t.Checker, format.Duration(t.Duration),
+			output.MutedStyle.Render(fmt.Sprintf("%d results", t.Results)))
+	}
+	if len(sorted) > 1 {
+		output.Printf("  %-12s %8s\n", "Total", format.Duration(total))
+	}
+	output.Newline()
+}
+
 func displayResults(results []compliance.CheckResult) {
 	if len(results) == 0 {
 		output.Success("No issues found!")
@@ -202,8 +351,8 @@ func displayResults(results []compliance.CheckResult) {
 					statusIcon,
 					severityBadge,
 					r.RuleID,
-					truncateString(r.Resource, 30),
-					truncateString(r.Message, 40),
+					output.Truncate(r.Resource, 30),
+					output.Truncate(r.Message, 40),
 				},
 				getCheckRowColors(r),
 			)
@@ -304,9 +453,72 @@ func getCheckRowColors(r compliance.CheckResult) []tablewriter.Colors {
 	}
 }
 
-func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
+// sendNotification posts the check summary with the most severe failures
+func sendNotification(ctx context.Context, target string, results []compliance.CheckResult, gateFailed bool) {
+	sinks, err := notify.Sinks()
+	if err != nil {
+		output.Warning(fmt.Sprintf("Notification failed: %v", err))
+		return
+	}
+	if len(sinks) == 0 {
+		output.Warning("No notification sinks configured (set notify.slack_webhook_url, SLACK_WEBHOOK_URL, ...)")
+		return
+	}
+
+	msg := complianceMessage(target, results, gateFailed)
+	if err := notify.Send(ctx, sinks, msg); err != nil {
+		output.Warning(fmt.Sprintf("Notification failed: %v", err))
+	}
+}
+
+// complianceMessage summarizes results with the score and the most severe failures
+func complianceMessage(target string, results []compliance.CheckResult, gateFailed bool) notify.Message {
+	var passed, counted int
+	var failed []compliance.CheckResult
+	for _, r := range results {
+		if r.Status == compliance.StatusSkipped {
+			continue
+		}
+		counted++
+		switch r.Status {
+		case compliance.StatusPassed:
+			passed++
+		case compliance.StatusFailed:
+			failed = append(failed, r)
+		}
+	}
+
+	score := 100.0
+	if counted > 0 {
+		score = float64(passed) / float64(counted) * 100
+	}
+
+	msg := notify.Message{
+		Title:  fmt.Sprintf("Compliance check %s passed", target),
+		Status: notify.StatusSuccess,
+		Fields: []notify.Field{
+			{Name: "Score", Value: fmt.Sprintf("%.1f%%", score)},
+			{Name: "Passed", Value: fmt.Sprintf("%d", passed)},
+			{Name: "Failed", Value: fmt.Sprintf("%d", len(failed))},
+		},
+	}
+	if gateFailed {
+		msg.Title = fmt.Sprintf("Compliance check %s failed", target)
+		msg.Status = notify.StatusFailure
 	}
-	return s[:maxLen-3] + "..."
+
+	sort.SliceStable(failed, func(i, j int) bool {
+		return !compliance.MeetsMinSeverity(failed[j].Severity, failed[i].Severity)
+	})
+	var lines []string
+	for i, r := range failed {
+		if i == 5 {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(failed)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("• [%s] %s: %s", r.Severity, r.RuleID, r.Resource))
+	}
+	msg.Text = strings.Join(lines, "\n")
+
+	return msg
 }
@@ -1,7 +1,15 @@
 package compliance
 
 import (
+	"fmt"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/compliance"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/secrets"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // NewComplianceCmd creates the compliance command
@@ -23,9 +31,84 @@ Supports checking:
 	cmd.AddCommand(newCheckCmd())
 	cmd.AddCommand(newReportCmd())
 	cmd.AddCommand(newPoliciesCmd())
+	cmd.AddCommand(newExplainCmd())
+	cmd.AddCommand(newTestCmd())
 
 	// Persistent flags
 	cmd.PersistentFlags().StringP("policy-dir", "d", "", "Directory containing policy files")
+	cmd.PersistentFlags().String("kubeconfig", "", "Path to kubeconfig file (k8s target)")
+	cmd.PersistentFlags().StringP("context", "c", "", "Kubernetes context to use (k8s target)")
+	_ = cmd.RegisterFlagCompletionFunc("policy-dir", completion.DirectoryCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("context", completion.ContextCompletion)
 
 	return cmd
 }
+
+// registryCredentials reads compliance.registry_credentials, resolving secret references
+func registryCredentials() (map[string]compliance.RegistryCredential, error) {
+	var entries []struct {
+		Registry string `mapstructure:"registry"`
+		Username string `mapstructure:"username"`
+		Password string `mapstructure:"password"`
+	}
+	if err := viper.UnmarshalKey("compliance.registry_credentials", &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse registry credentials: %w", err)
+	}
+
+	creds := make(map[string]compliance.RegistryCredential, len(entries))
+	for _, e := range entries {
+		username, err := secrets.Resolve(e.Username)
+		if err != nil {
+			return nil, err
+		}
+		password, err := secrets.Resolve(e.Password)
+		if err != nil {
+			return nil, err
+		}
+		creds[e.Registry] = compliance.RegistryCredential{Username: username, Password: password}
+	}
+	return creds, nil
+}
+
+// runK8sChecks runs the Kubernetes checks against the cluster selected by
+// --kubeconfig and --context, or the in-cluster config
+func runK8sChecks(cmd *cobra.Command, opts compliance.CheckOptions) ([]compliance.CheckResult, error) {
+	client, err := k8s.NewClient(
+		cmd.Flag("kubeconfig").Value.String(),
+		cmd.Flag("context").Value.String(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return compliance.NewK8sChecker(client, opts).Run(cmd.Context())
+}
+
+// addImageFlags adds the flags that widen the Docker image rules beyond --image
+func addImageFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("all-images", false, "Run the image rules against every image used by running containers (docker target)")
+	cmd.Flags().Bool("include-pods", false, "With --all-images, also check the images of Kubernetes pods (honors --namespace and --namespaces)")
+}
+
+// imageOptions sets opts.AllImages and, with --include-pods, collects the
+// images of the pods in scope
+func imageOptions(cmd *cobra.Command, opts *compliance.CheckOptions) error {
+	opts.AllImages, _ = cmd.Flags().GetBool("all-images")
+	includePods, _ := cmd.Flags().GetBool("include-pods")
+	if !includePods {
+		return nil
+	}
+	if !opts.AllImages {
+		return exitcode.ConfigError(fmt.Errorf("--include-pods requires --all-images"))
+	}
+
+	client, err := k8s.NewClient(
+		cmd.Flag("kubeconfig").Value.String(),
+		cmd.Flag("context").Value.String(),
+	)
+	if err != nil {
+		return err
+	}
+	client.SetNamespaceSelector(opts.Namespaces)
+	opts.PodImages, err = client.ListPodImages(cmd.Context(), opts.Namespace)
+	return err
+}
@@ -0,0 +1,104 @@
+package compliance
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/compliance"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// policyExplanation is the structured output of compliance explain
+type policyExplanation struct {
+	compliance.Policy
+	compliance.PolicyDoc
+}
+
+func newExplainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain <rule-id>",
+		Short: "Explain a compliance rule with remediation examples and references",
+		Long: `Show the full documentation of a built-in compliance rule: what it
+checks, why it matters, how to fix a failure with example snippets, and
+references to the CIS Benchmark and NSA/CISA hardening guide sections and
+vendor documentation it is based on.
+
+Rule IDs are case-insensitive; list them with 'compliance policies'.
+
+Examples:
+  devops-toolkit compliance explain K8S-SEC-002
+  devops-toolkit compliance explain docker-sec-007
+  devops-toolkit compliance explain GITLAB-SEC-004 --output json`,
+		Args:              cobra.ExactArgs(1),
+		RunE:              runExplain,
+		ValidArgsFunction: completion.RuleCompletion,
+	}
+
+	return cmd
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	policy, ok := compliance.LookupPolicy(args[0])
+	if !ok {
+		return exitcode.ConfigError(fmt.Errorf("unknown rule %q (list rules with 'compliance policies')", args[0]))
+	}
+	doc, _ := compliance.PolicyDocumentation(policy.ID)
+
+	if output.IsStructured() {
+		return output.Render(policyExplanation{Policy: policy, PolicyDoc: doc})
+	}
+
+	output.Header(policy.ID + ": " + policy.Name)
+	output.Print(output.KeyValue("Category", policy.Category))
+	output.Print(output.KeyValue("Severity", getSeverityBadge(policy.Severity)))
+	output.Print(output.KeyValue("Checked by", ruleCommand(policy.ID)))
+
+	output.Print(output.Section("Description"))
+	output.Printf("  %s\n", policy.Description)
+
+	if doc.Rationale != "" {
+		output.Print(output.Section("Why it matters"))
+		output.Printf("  %s\n", doc.Rationale)
+	}
+
+	output.Print(output.Section("Remediation"))
+	output.Printf("  %s\n", policy.Remediation)
+
+	for _, ex := range doc.Examples {
+		output.Newline()
+		output.Muted(fmt.Sprintf("  %s (%s)", ex.Title, ex.Language))
+		for _, line := range strings.Split(ex.Code, "\n") {
+			output.Printf("    %s\n", line)
+		}
+	}
+
+	if len(doc.References) > 0 {
+		output.Print(output.Section("References"))
+		for _, ref := range doc.References {
+			output.Printf("  %s %s\n", output.IconBullet, ref.Title)
+			output.Muted("    " + ref.URL)
+		}
+	}
+	output.Newline()
+
+	return nil
+}
+
+// ruleCommand returns the command that evaluates a rule, from its ID prefix
+func ruleCommand(id string) string {
+	switch {
+	case strings.HasPrefix(id, "K8S-"):
+		return "compliance check k8s"
+	case strings.HasPrefix(id, "DOCKER-"):
+		return "compliance check docker"
+	case strings.HasPrefix(id, "FILE-"):
+		return "compliance check files"
+	case strings.HasPrefix(id, "GITLAB-"):
+		return "gitlab audit"
+	default:
+		return "-"
+	}
+}
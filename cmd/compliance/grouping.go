@@ -0,0 +1,143 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/compliance"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/notify"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// groupByModes are the values accepted by --group-by
+var groupByModes = []string{compliance.GroupByNamespace, compliance.GroupByTeam}
+
+// addGroupingFlags adds the per-namespace and per-team grouping flags
+func addGroupingFlags(cmd *cobra.Command) {
+	cmd.Flags().String("group-by", "", "Score results per group: namespace (namespace or compose project) or team")
+	cmd.Flags().String("group-label", "", "Container label Docker results are grouped by (default com.docker.compose.project)")
+	cmd.Flags().String("owners", "", "Ownership file mapping namespaces to teams and Slack channels (default from compliance.owners_file)")
+
+	_ = cmd.RegisterFlagCompletionFunc("group-by", cobra.FixedCompletions(groupByModes, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.MarkFlagFilename("owners", "yaml", "yml")
+}
+
+// groupingOptions reads --group-by and loads the ownership file, if any
+func groupingOptions(cmd *cobra.Command) (string, *compliance.Owners, error) {
+	groupBy, _ := cmd.Flags().GetString("group-by")
+	switch groupBy {
+	case "", compliance.GroupByNamespace, compliance.GroupByTeam:
+	default:
+		return "", nil, exitcode.ConfigError(fmt.Errorf("invalid --group-by value: %s (valid: namespace, team)", groupBy))
+	}
+
+	file, _ := cmd.Flags().GetString("owners")
+	if file == "" {
+		file = viper.GetString("compliance.owners_file")
+	}
+	if file == "" {
+		if groupBy == compliance.GroupByTeam {
+			return "", nil, exitcode.ConfigError(fmt.Errorf("--group-by team needs an ownership file (set --owners or compliance.owners_file)"))
+		}
+		return groupBy, nil, nil
+	}
+
+	owners, err := compliance.LoadOwners(file)
+	if err != nil {
+		return "", nil, exitcode.ConfigError(err)
+	}
+	return groupBy, owners, nil
+}
+
+// displayGroups shows the score of each group, lowest first
+func displayGroups(groups []compliance.GroupSummary, groupBy string) {
+	headers := []string{"Namespace / Project", "Team", "Checks", "Passed", "Failed", "Score"}
+	if groupBy == compliance.GroupByTeam {
+		headers = []string{"Team", "Checks", "Passed", "Failed", "Score"}
+	}
+
+	output.Newline()
+	table := output.NewTable(output.TableConfig{
+		Title:      fmt.Sprintf("Compliance by %s", groupBy),
+		Headers:    headers,
+		ShowBorder: true,
+	})
+
+	for _, g := range groups {
+		name := groupName(g.Name, groupBy)
+		scoreColor := tablewriter.FgGreenColor
+		switch {
+		case g.Summary.Score < 70:
+			scoreColor = tablewriter.FgRedColor
+		case g.Summary.Score < 90:
+			scoreColor = tablewriter.FgYellowColor
+		}
+
+		row := []string{
+			fmt.Sprintf("%d", g.Summary.Total),
+			fmt.Sprintf("%d", g.Summary.Passed),
+			fmt.Sprintf("%d", g.Summary.Failed),
+			fmt.Sprintf("%.1f%%", g.Summary.Score),
+		}
+		colors := []tablewriter.Colors{{}, {tablewriter.FgGreenColor}, {tablewriter.FgRedColor}, {scoreColor}}
+		if groupBy == compliance.GroupByTeam {
+			row = append([]string{name}, row...)
+			colors = append([]tablewriter.Colors{{tablewriter.FgCyanColor}}, colors...)
+		} else {
+			row = append([]string{name, g.Team}, row...)
+			colors = append([]tablewriter.Colors{{tablewriter.FgCyanColor}, {tablewriter.FgHiBlackColor}}, colors...)
+		}
+		table.AddColoredRow(row, colors)
+	}
+
+	table.Render()
+}
+
+// groupName labels results that have no namespace, project or owner
+func groupName(name, groupBy string) string {
+	switch {
+	case name != "":
+		return name
+	case groupBy == compliance.GroupByTeam:
+		return "(unowned)"
+	default:
+		return "-"
+	}
+}
+
+// notifyOwners posts each team's own results to the team's Slack channel.
+// Teams without a channel or webhook, and unowned results, are only covered
+// by the summary sent to the default sinks.
+func notifyOwners(ctx context.Context, target string, results []compliance.CheckResult, owners *compliance.Owners, failOn string) {
+	byTeam := make(map[string][]compliance.CheckResult)
+	for _, r := range results {
+		if team := owners.TeamFor(r.Group); team != "" {
+			byTeam[team] = append(byTeam[team], r)
+		}
+	}
+
+	for team, teamResults := range byTeam {
+		settings := owners.Teams[team]
+		url := settings.SlackWebhookURL
+		if url == "" {
+			url = notify.SlackWebhookURL()
+		}
+		if url == "" || (settings.SlackChannel == "" && settings.SlackWebhookURL == "") {
+			continue
+		}
+
+		sink, err := notify.NewSink(notify.SinkConfig{Type: "slack", URL: url, Channel: settings.SlackChannel})
+		if err != nil {
+			output.Warning(fmt.Sprintf("Notification to %s failed: %v", team, err))
+			continue
+		}
+		msg := complianceMessage(fmt.Sprintf("%s (%s)", target, team), teamResults, countGatingFailures(teamResults, failOn) > 0)
+		if err := notify.Send(ctx, []notify.Sink{sink}, msg); err != nil {
+			output.Warning(fmt.Sprintf("Notification to %s failed: %v", team, err))
+		}
+	}
+}
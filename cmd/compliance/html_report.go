@@ -0,0 +1,298 @@
+package compliance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/compliance"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+)
+
+// htmlCategory is one collapsible section of the HTML report
+type htmlCategory struct {
+	Name    string
+	Failed  int
+	Results []compliance.CheckResult
+}
+
+// htmlReportData is what htmlReportTemplate renders
+type htmlReportData struct {
+	Report     compliance.Report
+	Generated  string
+	Categories []htmlCategory
+	Rules      []string
+	// JSON is the full report for the export button; encoding/json escapes
+	// <, > and & so it is safe inside a script element
+	JSON template.JS
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"statusIcon": func(status compliance.CheckStatus) string {
+		switch status {
+		case compliance.StatusFailed:
+			return "✗"
+		case compliance.StatusSkipped:
+			return "○"
+		default:
+			return "✓"
+		}
+	},
+	"groupName": groupName,
+	"severityClass": func(severity string) string {
+		switch severity {
+		case "critical", "high", "medium":
+			return severity
+		default:
+			return "low"
+		}
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Report.Title}}</title>
+    <style>
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+        body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; background: #0f172a; color: #e2e8f0; line-height: 1.6; }
+        .container { max-width: 1200px; margin: 0 auto; padding: 2rem; }
+        h1 { color: #7c3aed; margin-bottom: 0.5rem; }
+        .subtitle { color: #64748b; margin-bottom: 2rem; }
+        .summary { display: grid; grid-template-columns: repeat(auto-fit, minmax(150px, 1fr)); gap: 1rem; margin-bottom: 2rem; }
+        .stat { background: #1e293b; padding: 1.5rem; border-radius: 8px; text-align: center; }
+        .stat-value { font-size: 2rem; font-weight: bold; }
+        .stat-label { color: #64748b; font-size: 0.875rem; }
+        .passed { color: #10b981; }
+        .failed { color: #ef4444; }
+        .score-bar { height: 8px; background: #374151; border-radius: 4px; overflow: hidden; margin-top: 1rem; }
+        .score-fill { height: 100%; background: linear-gradient(90deg, #10b981, #7c3aed); }
+        .filters { display: flex; flex-wrap: wrap; gap: 0.75rem; align-items: center; margin-bottom: 1rem; }
+        .filters input, .filters select, .filters button { background: #1e293b; color: #e2e8f0; border: 1px solid #374151; border-radius: 6px; padding: 0.5rem 0.75rem; font-size: 0.875rem; }
+        .filters input { flex: 1; min-width: 220px; }
+        .filters button { cursor: pointer; }
+        .filters button:hover { border-color: #7c3aed; }
+        .count { color: #64748b; font-size: 0.875rem; margin-bottom: 1rem; }
+        details.category, details.groups { background: #1e293b; border-radius: 8px; margin-bottom: 1rem; overflow: hidden; }
+        details.category > summary, details.groups > summary { padding: 1rem; background: #334155; font-weight: bold; cursor: pointer; }
+        details.category > summary .muted { color: #94a3b8; font-weight: normal; margin-left: 0.5rem; }
+        table { width: 100%; border-collapse: collapse; }
+        th, td { padding: 0.75rem 1rem; text-align: left; border-bottom: 1px solid #374151; vertical-align: top; }
+        th { background: #1e293b; color: #94a3b8; font-weight: 500; }
+        .remediation { color: #94a3b8; font-size: 0.875rem; margin-top: 0.25rem; }
+        .badge { display: inline-block; padding: 0.25rem 0.5rem; border-radius: 4px; font-size: 0.75rem; font-weight: bold; }
+        .badge-critical { background: #ef4444; }
+        .badge-high { background: #f97316; }
+        .badge-medium { background: #f59e0b; color: #000; }
+        .badge-low { background: #06b6d4; }
+        .status-icon { width: 20px; text-align: center; }
+        .hidden { display: none; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>{{.Report.Title}}</h1>
+        <p class="subtitle">Generated: {{.Generated}}</p>
+
+        <div class="summary">
+            <div class="stat">
+                <div class="stat-value">{{.Report.Summary.Total}}</div>
+                <div class="stat-label">Total Checks</div>
+            </div>
+            <div class="stat">
+                <div class="stat-value passed">{{.Report.Summary.Passed}}</div>
+                <div class="stat-label">Passed</div>
+            </div>
+            <div class="stat">
+                <div class="stat-value failed">{{.Report.Summary.Failed}}</div>
+                <div class="stat-label">Failed</div>
+            </div>
+            <div class="stat">
+                <div class="stat-value">{{printf "%.1f%%" .Report.Summary.Score}}</div>
+                <div class="stat-label">Score</div>
+                <div class="score-bar"><div class="score-fill" style="width: {{printf "%.1f" .Report.Summary.Score}}%"></div></div>
+            </div>
+        </div>
+
+{{- if .Report.Groups}}
+        <details class="groups" open>
+            <summary>Score by {{.Report.GroupBy}}</summary>
+            <table>
+                <thead>
+                    <tr><th>{{.Report.GroupBy}}</th><th>Checks</th><th>Passed</th><th>Failed</th><th>Score</th></tr>
+                </thead>
+                <tbody>
+                {{- range .Report.Groups}}
+                    <tr>
+                        <td>{{groupName .Name $.Report.GroupBy}}{{if .Team}} <span class="remediation">({{.Team}})</span>{{end}}</td>
+                        <td>{{.Summary.Total}}</td>
+                        <td class="passed">{{.Summary.Passed}}</td>
+                        <td class="failed">{{.Summary.Failed}}</td>
+                        <td>{{printf "%.1f%%" .Summary.Score}}</td>
+                    </tr>
+                {{- end}}
+                </tbody>
+            </table>
+        </details>
+{{- end}}
+
+        <div class="filters">
+            <input id="search" type="search" placeholder="Search findings (resource, message, rule)...">
+            <select id="severity">
+                <option value="">All severities</option>
+                <option value="critical">Critical</option>
+                <option value="high">High and above</option>
+                <option value="medium">Medium and above</option>
+                <option value="low">Low and above</option>
+            </select>
+            <select id="status">
+                <option value="">All statuses</option>
+                <option value="failed">Failed</option>
+                <option value="passed">Passed</option>
+                <option value="skipped">Skipped</option>
+            </select>
+            <select id="rule">
+                <option value="">All rules</option>
+                {{- range .Rules}}
+                <option value="{{.}}">{{.}}</option>
+                {{- end}}
+            </select>
+            <button id="toggle" type="button">Collapse all</button>
+            <button id="export" type="button">Export JSON</button>
+        </div>
+        <p class="count" id="count"></p>
+{{range .Categories}}
+        <details class="category" open>
+            <summary>{{.Name}}<span class="muted">{{len .Results}} checks, {{.Failed}} failed</span></summary>
+            <table>
+                <thead>
+                    <tr>
+                        <th class="status-icon">Status</th>
+                        <th>Severity</th>
+                        <th>Rule</th>
+                        <th>Resource</th>
+                        <th>Message</th>
+                    </tr>
+                </thead>
+                <tbody>
+                {{- range .Results}}
+                    <tr class="finding" data-status="{{.Status}}" data-severity="{{.Severity}}" data-rule="{{.RuleID}}">
+                        <td class="status-icon {{if eq .Status "failed"}}failed{{else if eq .Status "passed"}}passed{{end}}">{{statusIcon .Status}}</td>
+                        <td><span class="badge badge-{{severityClass .Severity}}">{{.Severity}}</span></td>
+                        <td title="{{.RuleName}}">{{.RuleID}}</td>
+                        <td>{{.Resource}}</td>
+                        <td>{{.Message}}{{if .Remediation}}<div class="remediation">{{.Remediation}}</div>{{end}}</td>
+                    </tr>
+                {{- end}}
+                </tbody>
+            </table>
+        </details>
+{{end}}
+    </div>
+
+    <script type="application/json" id="report-data">{{.JSON}}</script>
+    <script>
+    (function () {
+        var levels = { low: 1, medium: 2, high: 3, critical: 4 };
+        var search = document.getElementById('search');
+        var severity = document.getElementById('severity');
+        var status = document.getElementById('status');
+        var rule = document.getElementById('rule');
+        var rows = Array.prototype.slice.call(document.querySelectorAll('tr.finding'));
+        rows.forEach(function (row) { row.dataset.text = row.textContent.toLowerCase(); });
+
+        function apply() {
+            var q = search.value.trim().toLowerCase();
+            var minLevel = levels[severity.value] || 0;
+            var shown = 0;
+            rows.forEach(function (row) {
+                var visible = (!q || row.dataset.text.indexOf(q) !== -1) &&
+                    (levels[row.dataset.severity] || 0) >= minLevel &&
+                    (!status.value || row.dataset.status === status.value) &&
+                    (!rule.value || row.dataset.rule === rule.value);
+                row.classList.toggle('hidden', !visible);
+                if (visible) { shown++; }
+            });
+            document.querySelectorAll('details.category').forEach(function (section) {
+                section.classList.toggle('hidden', !section.querySelector('tr.finding:not(.hidden)'));
+            });
+            document.getElementById('count').textContent = 'Showing ' + shown + ' of ' + rows.length + ' findings';
+        }
+
+        [search, severity, status, rule].forEach(function (el) { el.addEventListener('input', apply); });
+
+        document.getElementById('toggle').addEventListener('click', function () {
+            var sections = document.querySelectorAll('details.category, details.groups');
+            var open = this.textContent === 'Expand all';
+            sections.forEach(function (section) { section.open = open; });
+            this.textContent = open ? 'Collapse all' : 'Expand all';
+        });
+
+        document.getElementById('export').addEventListener('click', function () {
+            var data = document.getElementById('report-data').textContent;
+            var link = document.createElement('a');
+            link.href = URL.createObjectURL(new Blob([data], { type: 'application/json' }));
+            link.download = 'compliance-report.json';
+            link.click();
+            URL.revokeObjectURL(link.href);
+        });
+
+        apply();
+    })();
+    </script>
+</body>
+</html>
+`))
+
+// generateHTMLReport renders a self-contained page with search, filters by
+// severity, status and rule, collapsible categories and a JSON export
+func generateHTMLReport(report compliance.Report) (string, error) {
+	byCategory := make(map[string]*htmlCategory)
+	rules := make(map[string]bool)
+	for _, r := range report.Results {
+		cat, ok := byCategory[r.Category]
+		if !ok {
+			cat = &htmlCategory{Name: r.Category}
+			byCategory[r.Category] = cat
+		}
+		cat.Results = append(cat.Results, r)
+		if r.Status == compliance.StatusFailed {
+			cat.Failed++
+		}
+		rules[r.RuleID] = true
+	}
+
+	data := htmlReportData{
+		Report:    report,
+		Generated: format.Timestamp(report.GeneratedAt),
+	}
+	for _, cat := range byCategory {
+		cat.Results = failedFirst(cat.Results)
+		data.Categories = append(data.Categories, *cat)
+	}
+	// Categories with the most failures first
+	sort.Slice(data.Categories, func(i, j int) bool {
+		if data.Categories[i].Failed != data.Categories[j].Failed {
+			return data.Categories[i].Failed > data.Categories[j].Failed
+		}
+		return data.Categories[i].Name < data.Categories[j].Name
+	})
+	for rule := range rules {
+		data.Rules = append(data.Rules, rule)
+	}
+	sort.Strings(data.Rules)
+
+	raw, err := json.Marshal(report)
+	if err != nil {
+		return "", err
+	}
+	data.JSON = template.JS(raw)
+
+	var buf bytes.Buffer
+	if err := htmlReportTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return buf.String(), nil
+}
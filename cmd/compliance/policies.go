@@ -1,6 +1,7 @@
 package compliance
 
 import (
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/compliance"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
 	"github.com/olekukonko/tablewriter"
@@ -24,6 +25,10 @@ Shows:
 	cmd.Flags().String("category", "", "Filter by category")
 	cmd.Flags().String("severity", "", "Filter by severity")
 
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("category", completion.PolicyCategoryCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("severity", completion.SeverityCompletion)
+
 	return cmd
 }
 
@@ -78,7 +83,7 @@ func runPolicies(cmd *cobra.Command, args []string) error {
 					p.ID,
 					severityBadge,
 					p.Name,
-					truncateString(p.Description, 50),
+					output.Truncate(p.Description, 50),
 				},
 				getPolicyRowColors(p.Severity),
 			)
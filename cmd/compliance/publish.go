@@ -0,0 +1,205 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/compliance"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/confluence"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/secrets"
+	"github.com/spf13/viper"
+)
+
+// publishTargets are the destinations accepted by --publish
+var publishTargets = []string{"confluence", "gitlab-wiki"}
+
+// publishReport pushes the report to each target configured under compliance.publish
+func publishReport(ctx context.Context, targets []string, report compliance.Report) error {
+	for _, target := range targets {
+		output.StartSpinner(fmt.Sprintf("Publishing report to %s...", target))
+
+		var url string
+		var err error
+		switch target {
+		case "confluence":
+			url, err = publishConfluence(ctx, report)
+		case "gitlab-wiki":
+			url, err = publishGitLabWiki(ctx, report)
+		default:
+			err = exitcode.ConfigError(fmt.Errorf("unknown publish target: %s (valid: %s)", target, strings.Join(publishTargets, ", ")))
+		}
+		if err != nil {
+			output.SpinnerError(fmt.Sprintf("Failed to publish to %s", target))
+			return err
+		}
+		output.SpinnerSuccess(fmt.Sprintf("Published to %s", url))
+	}
+	return nil
+}
+
+// publishConfluence replaces the body of compliance.publish.confluence.page_id
+func publishConfluence(ctx context.Context, report compliance.Report) (string, error) {
+	baseURL := setting("CONFLUENCE_URL", "compliance.publish.confluence.url")
+	pageID := viper.GetString("compliance.publish.confluence.page_id")
+	if baseURL == "" || pageID == "" {
+		return "", exitcode.ConfigError(fmt.Errorf("set compliance.publish.confluence.url and page_id to publish to Confluence"))
+	}
+	token, err := secrets.Resolve(setting("CONFLUENCE_TOKEN", "compliance.publish.confluence.token"))
+	if err != nil {
+		return "", exitcode.ConfigError(err)
+	}
+
+	client, err := confluence.NewClient(baseURL, setting("CONFLUENCE_USER", "compliance.publish.confluence.user"), token)
+	if err != nil {
+		return "", exitcode.ConfigError(err)
+	}
+	message := fmt.Sprintf("Score %.1f%%, %d failed", report.Summary.Score, report.Summary.Failed)
+	return client.UpdatePage(ctx, pageID, viper.GetString("compliance.publish.confluence.title"), generateConfluenceReport(report), message)
+}
+
+// publishGitLabWiki writes the Markdown report to compliance.publish.gitlab_wiki.page
+func publishGitLabWiki(ctx context.Context, report compliance.Report) (string, error) {
+	project := viper.GetString("compliance.publish.gitlab_wiki.project")
+	if project == "" {
+		project = setting("GITLAB_PROJECT", "gitlab.project")
+	}
+	slug := viper.GetString("compliance.publish.gitlab_wiki.page")
+	if slug == "" {
+		slug = "compliance-report"
+	}
+	if project == "" {
+		return "", exitcode.ConfigError(fmt.Errorf("set compliance.publish.gitlab_wiki.project (or gitlab.project) to publish to a GitLab wiki"))
+	}
+
+	token := setting("GITLAB_TOKEN", "gitlab.token")
+	if token == "" {
+		return "", exitcode.ConfigError(fmt.Errorf("GitLab token required to publish (set GITLAB_TOKEN or gitlab.token)"))
+	}
+	token, err := secrets.Resolve(token)
+	if err != nil {
+		return "", exitcode.ConfigError(err)
+	}
+	url := setting("GITLAB_URL", "gitlab.url")
+	if url == "" {
+		url = "https://gitlab.com"
+	}
+
+	client, err := gitlabclient.NewClient(url, token)
+	if err != nil {
+		return "", err
+	}
+	if err := client.PublishWikiPage(ctx, project, slug, generateMarkdownReport(report)); err != nil {
+		return "", err
+	}
+	pageURL, err := client.WikiPageURL(ctx, project, slug)
+	if err != nil {
+		return project + " wiki: " + slug, nil
+	}
+	return pageURL, nil
+}
+
+// setting returns the environment variable if set, else the config key
+func setting(env, key string) string {
+	if v := os.Getenv(env); v != "" {
+		return v
+	}
+	return viper.GetString(key)
+}
+
+// failedFirst returns the results with failures first, most severe first
+func failedFirst(results []compliance.CheckResult) []compliance.CheckResult {
+	sorted := append([]compliance.CheckResult{}, results...)
+	rank := func(r compliance.CheckResult) int {
+		switch r.Status {
+		case compliance.StatusFailed:
+			return 0
+		case compliance.StatusSkipped:
+			return 2
+		default:
+			return 1
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if rank(sorted[i]) != rank(sorted[j]) {
+			return rank(sorted[i]) < rank(sorted[j])
+		}
+		return !compliance.MeetsMinSeverity(sorted[j].Severity, sorted[i].Severity)
+	})
+	return sorted
+}
+
+func generateMarkdownReport(report compliance.Report) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", report.Title)
+	fmt.Fprintf(&b, "_Generated %s_\n\n", format.Timestamp(report.GeneratedAt))
+	fmt.Fprintf(&b, "| Total | Passed | Failed | Skipped | Score |\n|---|---|---|---|---|\n")
+	fmt.Fprintf(&b, "| %d | %d | %d | %d | %.1f%% |\n\n",
+		report.Summary.Total, report.Summary.Passed, report.Summary.Failed, report.Summary.Skipped, report.Summary.Score)
+
+	if len(report.Groups) > 0 {
+		fmt.Fprintf(&b, "## Score by %s\n\n| %s | Checks | Passed | Failed | Score |\n|---|---|---|---|---|\n", report.GroupBy, report.GroupBy)
+		for _, g := range report.Groups {
+			fmt.Fprintf(&b, "| %s | %d | %d | %d | %.1f%% |\n",
+				groupName(g.Name, report.GroupBy), g.Summary.Total, g.Summary.Passed, g.Summary.Failed, g.Summary.Score)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(report.Results) == 0 {
+		b.WriteString("No checks matched.\n")
+		return b.String()
+	}
+
+	cell := func(s string) string {
+		return strings.ReplaceAll(strings.ReplaceAll(s, "|", "\\|"), "\n", " ")
+	}
+	b.WriteString("| Status | Severity | Rule | Resource | Message | Remediation |\n|---|---|---|---|---|---|\n")
+	for _, r := range failedFirst(report.Results) {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+			r.Status, r.Severity, cell(r.RuleID), cell(r.Resource), cell(r.Message), cell(r.Remediation))
+	}
+	return b.String()
+}
+
+// generateConfluenceReport renders the report in Confluence storage format,
+// which is XHTML restricted to what the Confluence editor supports
+func generateConfluenceReport(report compliance.Report) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<p><em>Generated %s</em></p>", html.EscapeString(format.Timestamp(report.GeneratedAt)))
+	b.WriteString("<table><tbody><tr><th>Total</th><th>Passed</th><th>Failed</th><th>Skipped</th><th>Score</th></tr>")
+	fmt.Fprintf(&b, "<tr><td>%d</td><td>%d</td><td>%d</td><td>%d</td><td>%.1f%%</td></tr></tbody></table>",
+		report.Summary.Total, report.Summary.Passed, report.Summary.Failed, report.Summary.Skipped, report.Summary.Score)
+
+	if len(report.Results) == 0 {
+		b.WriteString("<p>No checks matched.</p>")
+		return b.String()
+	}
+
+	b.WriteString("<table><tbody><tr><th>Status</th><th>Severity</th><th>Rule</th><th>Resource</th><th>Message</th><th>Remediation</th></tr>")
+	for _, r := range failedFirst(report.Results) {
+		color := "Green"
+		switch r.Status {
+		case compliance.StatusFailed:
+			color = "Red"
+		case compliance.StatusSkipped:
+			color = "Grey"
+		}
+		// The status lozenge is a built-in macro available on every edition
+		fmt.Fprintf(&b, `<tr><td><ac:structured-macro ac:name="status"><ac:parameter ac:name="colour">%s</ac:parameter><ac:parameter ac:name="title">%s</ac:parameter></ac:structured-macro></td>`,
+			color, html.EscapeString(string(r.Status)))
+		for _, v := range []string{r.Severity, r.RuleID, r.Resource, r.Message, r.Remediation} {
+			fmt.Fprintf(&b, "<td>%s</td>", html.EscapeString(v))
+		}
+		b.WriteString("</tr>")
+	}
+	b.WriteString("</tbody></table>")
+	return b.String()
+}
@@ -1,7 +1,6 @@
 package compliance
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -10,8 +9,12 @@ import (
 
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/compliance"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/schedule"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func newReportCmd() *cobra.Command {
@@ -31,38 +34,104 @@ Output formats:
   json      JSON format for programmatic use
   junit     JUnit XML format for CI integration
   html      HTML report for sharing
+  markdown  Markdown for wikis and merge requests
+
+Publishing:
+  --publish confluence   Replace the body of compliance.publish.confluence.page_id
+  --publish gitlab-wiki  Create or update compliance.publish.gitlab_wiki.page
+
+Publish targets are read from the config file, so each profile can point
+at its own page; tokens accept secret references.
+
+Grouping:
+  --group-by namespace   Score each namespace (k8s) or compose project (docker)
+  --group-by team        Score each team from the ownership file
+
+The ownership file (--owners or compliance.owners_file) maps namespaces and
+compose projects to teams, and teams to Slack channels; with --notify each
+team also gets its own failures in its channel.
+
+With --schedule the command keeps running and produces a report at every
+time matching the cron expression (minute hour day month weekday, or
+@daily, @weekly, ...). --output-file gets the run time appended, e.g.
+compliance-20240506-0700.html, and --notify / --publish fire every run.
 
 Examples:
   devops-toolkit compliance report                    Run all checks, output to console
   devops-toolkit compliance report k8s -f html -o report.html
   devops-toolkit compliance report docker -f json
-  devops-toolkit compliance report all -f junit -o results.xml`,
+  devops-toolkit compliance report all -f junit -o results.xml
+  devops-toolkit compliance report k8s --profile prod --publish confluence,gitlab-wiki
+  devops-toolkit compliance report -f html -o reports/compliance.html --notify --schedule "0 7 * * 1"
+  devops-toolkit compliance report k8s --group-by team --owners owners.yaml --notify`,
 		RunE:              runReport,
 		ValidArgsFunction: completion.ComplianceTargetCompletion,
 	}
 
-	cmd.Flags().StringP("format", "f", "table", "Output format (table, json, junit, html)")
+	cmd.Flags().StringP("format", "f", "table", "Output format (table, json, junit, html, markdown)")
+	cmd.Flags().StringSlice("publish", nil, "Publish the report to: confluence, gitlab-wiki")
+	cmd.Flags().Bool("notify", false, "Post the summary to the configured Slack/Teams/webhook sinks")
+	addGroupingFlags(cmd)
 	cmd.Flags().StringP("output-file", "o", "", "Output file path")
 	cmd.Flags().String("title", "Compliance Report", "Report title")
 	cmd.Flags().Bool("include-passed", true, "Include passed checks in report")
 	cmd.Flags().StringP("namespace", "n", "", "Kubernetes namespace (for k8s target)")
+	cmd.Flags().StringSlice("namespaces", nil, "Only check these namespaces, globs allowed (k8s target)")
+	cmd.Flags().StringSlice("exclude-namespaces", nil, "Skip these namespaces, globs allowed (k8s target)")
 	cmd.Flags().String("image", "", "Docker image to check (for docker target)")
+	addImageFlags(cmd)
+	cmd.Flags().String("path", ".", "Path to files to check (for files target)")
+	cmd.Flags().StringSlice("include", nil, "Only check files matching these globs (files target)")
+	cmd.Flags().StringSlice("exclude", nil, "Skip files matching these globs, in addition to .complianceignore (files target)")
+	cmd.Flags().Bool("no-cache", false, "Re-check every file instead of reusing cached results (files target)")
+	cmd.Flags().StringSlice("allowed-registries", nil, "Approved image registries or registry/org prefixes (default from compliance.allowed_registries)")
+	cmd.Flags().Bool("verify-provenance", false, "Query registries for image signatures and attestations")
+	cmd.Flags().StringSlice("skip", nil, "Rules to skip")
+	cmd.Flags().StringSlice("only", nil, "Only run these rules")
+	cmd.Flags().String("severity", "", "Minimum severity to report (low, medium, high, critical)")
 
 	// Register flag completions
 	_ = cmd.RegisterFlagCompletionFunc("format", completion.ReportFormatCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("publish", cobra.FixedCompletions(publishTargets, cobra.ShellCompDirectiveNoFileComp))
 	_ = cmd.RegisterFlagCompletionFunc("namespace", completion.NamespaceCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("namespaces", completion.NamespaceCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("exclude-namespaces", completion.NamespaceCompletion)
 	_ = cmd.RegisterFlagCompletionFunc("image", completion.ImageCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("severity", completion.SeverityCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("skip", completion.RuleCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("only", completion.RuleCompletion)
 
-	return cmd
+	return schedule.Schedulable(cmd)
 }
 
 func runReport(cmd *cobra.Command, args []string) error {
 	format, _ := cmd.Flags().GetString("format")
 	outputFile, _ := cmd.Flags().GetString("output-file")
+	outputFile = schedule.Stamp(outputFile)
 	title, _ := cmd.Flags().GetString("title")
 	includePassed, _ := cmd.Flags().GetBool("include-passed")
 	namespace, _ := cmd.Flags().GetString("namespace")
+	namespaces, _ := cmd.Flags().GetStringSlice("namespaces")
+	excludeNamespaces, _ := cmd.Flags().GetStringSlice("exclude-namespaces")
 	imageName, _ := cmd.Flags().GetString("image")
+	path, _ := cmd.Flags().GetString("path")
+	include, _ := cmd.Flags().GetStringSlice("include")
+	exclude, _ := cmd.Flags().GetStringSlice("exclude")
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	allowedRegistries, _ := cmd.Flags().GetStringSlice("allowed-registries")
+	if len(allowedRegistries) == 0 {
+		allowedRegistries = viper.GetStringSlice("compliance.allowed_registries")
+	}
+	verifyProvenance, _ := cmd.Flags().GetBool("verify-provenance")
+	skipRules, _ := cmd.Flags().GetStringSlice("skip")
+	onlyRules, _ := cmd.Flags().GetStringSlice("only")
+	minSeverity, _ := cmd.Flags().GetString("severity")
+	publishTo, _ := cmd.Flags().GetStringSlice("publish")
+	notifyResult, _ := cmd.Flags().GetBool("notify")
+	groupBy, owners, err := groupingOptions(cmd)
+	if err != nil {
+		return err
+	}
 
 	// Determine target (default to "all")
 	target := "all"
@@ -71,28 +140,58 @@ func runReport(cmd *cobra.Command, args []string) error {
 	}
 
 	opts := compliance.CheckOptions{
-		Namespace: namespace,
-		Image:     imageName,
+		Namespace:   namespace,
+		Image:       imageName,
+		Path:        path,
+		Include:     include,
+		Exclude:     exclude,
+		SkipRules:   skipRules,
+		OnlyRules:   onlyRules,
+		MinSeverity: minSeverity,
+		NoCache:     noCache,
+
+		AllowedRegistries: allowedRegistries,
+		VerifyProvenance:  verifyProvenance,
+		Namespaces:        k8s.NamespaceSelector{Include: namespaces, Exclude: excludeNamespaces},
+	}
+	opts.GroupLabel, _ = cmd.Flags().GetString("group-label")
+	if err := imageOptions(cmd, &opts); err != nil {
+		return err
 	}
+	if verifyProvenance {
+		creds, err := registryCredentials()
+		if err != nil {
+			return err
+		}
+		opts.RegistryCredentials = creds
+	}
+
+	opts.Progress = output.UpdateSpinner
 
 	var results []compliance.CheckResult
-	var err error
+	var timings []checkerTiming
 
 	switch target {
 	case "k8s", "kubernetes":
 		output.StartSpinner("Running Kubernetes compliance checks...")
-		results, err = runK8sChecks(context.Background(), opts)
+		results, err = timeChecker(&timings, "Kubernetes", func() ([]compliance.CheckResult, error) {
+			return runK8sChecks(cmd, opts)
+		})
 	case "docker":
 		output.StartSpinner("Running Docker compliance checks...")
-		results, err = runDockerChecks(context.Background(), opts)
+		results, err = timeChecker(&timings, "Docker", func() ([]compliance.CheckResult, error) {
+			return runDockerChecks(cmd.Context(), opts)
+		})
 	case "files", "file":
 		output.StartSpinner("Running file compliance checks...")
-		results, err = runFileChecks(context.Background(), opts)
+		results, err = timeChecker(&timings, "Files", func() ([]compliance.CheckResult, error) {
+			return runFileChecks(cmd.Context(), opts)
+		})
 	case "all":
 		output.StartSpinner("Running all compliance checks...")
-		results, err = runAllChecks(context.Background(), opts)
+		results, err = runAllChecks(cmd, opts, &timings)
 	default:
-		return fmt.Errorf("unknown target: %s (valid targets: k8s, docker, files, all)", target)
+		return exitcode.ConfigError(fmt.Errorf("unknown target: %s (valid targets: k8s, docker, files, all)", target))
 	}
 
 	if err != nil {
@@ -101,6 +200,7 @@ func runReport(cmd *cobra.Command, args []string) error {
 	}
 
 	output.SpinnerSuccess(fmt.Sprintf("Completed %d checks", len(results)))
+	allResults := results
 
 	// Filter results
 	if !includePassed {
@@ -117,23 +217,12 @@ func runReport(cmd *cobra.Command, args []string) error {
 	report := compliance.Report{
 		Title:       title,
 		GeneratedAt: time.Now(),
+		Summary:     compliance.Summarize(results),
 		Results:     results,
 	}
-
-	// Calculate summary
-	for _, r := range results {
-		switch r.Status {
-		case compliance.StatusPassed:
-			report.Summary.Passed++
-		case compliance.StatusFailed:
-			report.Summary.Failed++
-		case compliance.StatusSkipped:
-			report.Summary.Skipped++
-		}
-	}
-	report.Summary.Total = len(results)
-	if report.Summary.Total > 0 {
-		report.Summary.Score = float64(report.Summary.Passed) / float64(report.Summary.Total-report.Summary.Skipped) * 100
+	if groupBy != "" {
+		report.GroupBy = groupBy
+		report.Groups = compliance.GroupResults(results, groupBy, owners)
 	}
 
 	// Output based on format
@@ -149,23 +238,46 @@ func runReport(cmd *cobra.Command, args []string) error {
 	case "junit":
 		reportOutput = generateJUnitReport(report)
 	case "html":
-		reportOutput = generateHTMLReport(report)
+		reportOutput, err = generateHTMLReport(report)
+		if err != nil {
+			return err
+		}
+	case "markdown", "md":
+		reportOutput = generateMarkdownReport(report)
 	default: // table
 		displayResults(results)
-		return nil
+		displayTimings(timings)
+		if groupBy != "" {
+			displayGroups(report.Groups, groupBy)
+		}
 	}
 
-	// Write to file or stdout
-	if outputFile != "" {
-		err := os.WriteFile(outputFile, []byte(reportOutput), 0644)
-		if err != nil {
-			return fmt.Errorf("failed to write report: %w", err)
+	// Write to file or stdout; table output was already displayed
+	if reportOutput != "" {
+		if outputFile != "" {
+			err := os.WriteFile(outputFile, []byte(reportOutput), 0644)
+			if err != nil {
+				return fmt.Errorf("failed to write report: %w", err)
+			}
+			output.Successf("Report written to %s", outputFile)
+		} else {
+			fmt.Println(reportOutput)
 		}
-		output.Successf("Report written to %s", outputFile)
-	} else {
-		fmt.Println(reportOutput)
 	}
 
+	if notifyResult {
+		failed := false
+		for _, r := range allResults {
+			failed = failed || r.Status == compliance.StatusFailed
+		}
+		sendNotification(cmd.Context(), target, allResults, failed)
+		if owners != nil {
+			notifyOwners(cmd.Context(), target, allResults, owners, "low")
+		}
+	}
+	if len(publishTo) > 0 {
+		return publishReport(cmd.Context(), publishTo, report)
+	}
 	return nil
 }
 
@@ -216,146 +328,3 @@ func generateJUnitReport(report compliance.Report) string {
 	xml += `</testsuites>`
 	return xml
 }
-
-func generateHTMLReport(report compliance.Report) string {
-	// Generate a clean HTML report
-	html := `<!DOCTYPE html>
-<html lang="en">
-<head>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>%s</title>
-    <style>
-        * { margin: 0; padding: 0; box-sizing: border-box; }
-        body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; background: #0f172a; color: #e2e8f0; line-height: 1.6; }
-        .container { max-width: 1200px; margin: 0 auto; padding: 2rem; }
-        h1 { color: #7c3aed; margin-bottom: 0.5rem; }
-        .subtitle { color: #64748b; margin-bottom: 2rem; }
-        .summary { display: grid; grid-template-columns: repeat(auto-fit, minmax(150px, 1fr)); gap: 1rem; margin-bottom: 2rem; }
-        .stat { background: #1e293b; padding: 1.5rem; border-radius: 8px; text-align: center; }
-        .stat-value { font-size: 2rem; font-weight: bold; }
-        .stat-label { color: #64748b; font-size: 0.875rem; }
-        .passed { color: #10b981; }
-        .failed { color: #ef4444; }
-        .warning { color: #f59e0b; }
-        .score-bar { height: 8px; background: #374151; border-radius: 4px; overflow: hidden; margin-top: 1rem; }
-        .score-fill { height: 100%%; background: linear-gradient(90deg, #10b981, #7c3aed); }
-        .category { background: #1e293b; border-radius: 8px; margin-bottom: 1rem; overflow: hidden; }
-        .category-header { padding: 1rem; background: #334155; font-weight: bold; }
-        table { width: 100%%; border-collapse: collapse; }
-        th, td { padding: 0.75rem 1rem; text-align: left; border-bottom: 1px solid #374151; }
-        th { background: #1e293b; color: #94a3b8; font-weight: 500; }
-        .badge { display: inline-block; padding: 0.25rem 0.5rem; border-radius: 4px; font-size: 0.75rem; font-weight: bold; }
-        .badge-critical { background: #ef4444; }
-        .badge-high { background: #f97316; }
-        .badge-medium { background: #f59e0b; color: #000; }
-        .badge-low { background: #06b6d4; }
-        .status-icon { width: 20px; text-align: center; }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <h1>%s</h1>
-        <p class="subtitle">Generated: %s</p>
-        
-        <div class="summary">
-            <div class="stat">
-                <div class="stat-value">%d</div>
-                <div class="stat-label">Total Checks</div>
-            </div>
-            <div class="stat">
-                <div class="stat-value passed">%d</div>
-                <div class="stat-label">Passed</div>
-            </div>
-            <div class="stat">
-                <div class="stat-value failed">%d</div>
-                <div class="stat-label">Failed</div>
-            </div>
-            <div class="stat">
-                <div class="stat-value">%.1f%%</div>
-                <div class="stat-label">Score</div>
-                <div class="score-bar"><div class="score-fill" style="width: %.1f%%"></div></div>
-            </div>
-        </div>
-`
-
-	html = fmt.Sprintf(html,
-		report.Title,
-		report.Title,
-		report.GeneratedAt.Format("2006-01-02 15:04:05"),
-		report.Summary.Total,
-		report.Summary.Passed,
-		report.Summary.Failed,
-		report.Summary.Score,
-		report.Summary.Score,
-	)
-
-	// Group by category
-	byCategory := make(map[string][]compliance.CheckResult)
-	for _, r := range report.Results {
-		byCategory[r.Category] = append(byCategory[r.Category], r)
-	}
-
-	for category, results := range byCategory {
-		html += fmt.Sprintf(`
-        <div class="category">
-            <div class="category-header">%s</div>
-            <table>
-                <thead>
-                    <tr>
-                        <th class="status-icon">Status</th>
-                        <th>Severity</th>
-                        <th>Rule</th>
-                        <th>Resource</th>
-                        <th>Message</th>
-                    </tr>
-                </thead>
-                <tbody>
-`, category)
-
-		for _, r := range results {
-			statusIcon := "✓"
-			statusClass := "passed"
-			if r.Status == compliance.StatusFailed {
-				statusIcon = "✗"
-				statusClass = "failed"
-			} else if r.Status == compliance.StatusSkipped {
-				statusIcon = "○"
-				statusClass = ""
-			}
-
-			severityClass := "low"
-			switch r.Severity {
-			case "critical":
-				severityClass = "critical"
-			case "high":
-				severityClass = "high"
-			case "medium":
-				severityClass = "medium"
-			}
-
-			html += fmt.Sprintf(`
-                    <tr>
-                        <td class="status-icon %s">%s</td>
-                        <td><span class="badge badge-%s">%s</span></td>
-                        <td>%s</td>
-                        <td>%s</td>
-                        <td>%s</td>
-                    </tr>
-`, statusClass, statusIcon, severityClass, r.Severity, r.RuleID, r.Resource, r.Message)
-		}
-
-		html += `
-                </tbody>
-            </table>
-        </div>
-`
-	}
-
-	html += `
-    </div>
-</body>
-</html>`
-
-	return html
-}
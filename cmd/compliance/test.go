@@ -0,0 +1,161 @@
+package compliance
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/compliance"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newTestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Run policy unit tests against fixtures",
+		Long: `Run the policy unit tests in the policy directory.
+
+Every *_test.yaml file under --policy-dir (default compliance.policy_dir,
+else ./policies) lists fixtures and the findings they must produce, so rule
+changes can be tested before they run against a cluster:
+
+  tests:
+    - name: latest tag is flagged
+      fixture: fixtures/latest-tag.yaml
+      fail: [FILE-K8S-001]
+    - name: hardened deployment is clean
+      fixture: fixtures/hardened.yaml
+      exact: true                      # no rule may fail
+    - name: unpinned base image
+      fixture: fixtures/app.dockerfile
+      type: dockerfile                 # manifest, dockerfile or compose
+      fail: [FILE-DOCKER-005]
+      pass: [FILE-DOCKER-003]
+    - name: only approved registries
+      fixture: fixtures/external-image.yaml
+      allowed_registries: [ghcr.io/myorg]
+      fail: [FILE-IMG-002]
+
+Fixture paths are relative to the test file. The type is detected from the
+fixture name as compliance check files does, unless set.
+
+Only the built-in file rules (FILE-*) run against fixtures. Custom and Rego
+rules are not loaded yet: a test that names another rule fails, and .rego
+files under the policy directory are listed as not loaded.`,
+		Example: `  devops-toolkit compliance test --policy-dir ./policies
+  devops-toolkit compliance test --run latest
+  devops-toolkit compliance test --output json`,
+		Args: cobra.NoArgs,
+		RunE: runTest,
+	}
+
+	cmd.Flags().String("run", "", "Only run tests whose name matches this regular expression")
+
+	return cmd
+}
+
+func runTest(cmd *cobra.Command, args []string) error {
+	dir := cmd.Flag("policy-dir").Value.String()
+	if dir == "" {
+		dir = viper.GetString("compliance.policy_dir")
+	}
+	if dir == "" {
+		dir = "policies"
+	}
+	if strings.HasPrefix(dir, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, dir[2:])
+		}
+	}
+
+	var run *regexp.Regexp
+	if expr, _ := cmd.Flags().GetString("run"); expr != "" {
+		var err error
+		if run, err = regexp.Compile(expr); err != nil {
+			return exitcode.ConfigError(fmt.Errorf("invalid --run expression: %w", err))
+		}
+	}
+
+	results, err := compliance.RunPolicyTests(cmd.Context(), dir, run)
+	if err != nil {
+		return exitcode.ConfigError(err)
+	}
+	unloaded, err := compliance.UnloadedPolicyFiles(dir)
+	if err != nil {
+		return exitcode.ConfigError(err)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if !r.Passed {
+			failed++
+		}
+	}
+
+	if output.IsStructured() {
+		if err := output.Render(results); err != nil {
+			return err
+		}
+	} else {
+		displayTestResults(dir, results)
+	}
+	if len(unloaded) > 0 {
+		output.Warningf("%d Rego policies in %s were not loaded; compliance test only runs the built-in file rules", len(unloaded), dir)
+	}
+
+	if len(results) == 0 {
+		return exitcode.ConfigError(fmt.Errorf("no policy tests found in %s (looked for *_test.yaml)", dir))
+	}
+	if failed > 0 {
+		return exitcode.ChecksFailedf("%d of %d policy tests failed", failed, len(results))
+	}
+	return nil
+}
+
+func displayTestResults(dir string, results []compliance.PolicyTestResult) {
+	output.Header("Policy Tests")
+	if len(results) == 0 {
+		return
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Headers:    []string{"Status", "File", "Test", "Problems"},
+		ShowBorder: true,
+	})
+
+	passed := 0
+	for _, r := range results {
+		status := output.SuccessStyle.Render(output.IconSuccess)
+		statusColor := tablewriter.FgGreenColor
+		if r.Passed {
+			passed++
+		} else {
+			status = output.ErrorStyle.Render(output.IconError)
+			statusColor = tablewriter.FgRedColor
+		}
+
+		file := r.File
+		if rel, err := filepath.Rel(dir, r.File); err == nil {
+			file = rel
+		}
+
+		table.AddColoredRow(
+			[]string{status, file, r.Name, strings.Join(r.Problems, "; ")},
+			[]tablewriter.Colors{{statusColor}, {tablewriter.FgHiBlackColor}, {tablewriter.FgWhiteColor}, {tablewriter.FgRedColor}},
+		)
+	}
+	table.Render()
+
+	output.Newline()
+	if passed == len(results) {
+		output.Successf("All %d policy tests passed", len(results))
+	} else {
+		output.Errorf("%d of %d policy tests failed", len(results)-passed, len(results))
+	}
+}
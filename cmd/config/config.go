@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// NewConfigCmd creates the config command
+func NewConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Create and validate the config file",
+		Long: `Create and validate the toolkit config file.
+
+The config file (~/.devops-toolkit.yaml, or --config) holds backend
+connection settings, defaults, theme and named profiles.`,
+	}
+
+	cmd.AddCommand(newInitCmd())
+	cmd.AddCommand(newValidateCmd())
+
+	return cmd
+}
+
+// configPath returns the config file in use: --config, the file that was
+// loaded, or ~/.devops-toolkit.yaml
+func configPath(cmd *cobra.Command) (string, error) {
+	if path := cmd.Flag("config").Value.String(); path != "" {
+		return path, nil
+	}
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".devops-toolkit.yaml"), nil
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/secrets"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// fileConfig is the subset of the config file that config init writes
+type fileConfig struct {
+	GitLab     *gitlabConfig            `yaml:"gitlab,omitempty"`
+	Defaults   defaultsConfig           `yaml:"defaults"`
+	Kubernetes *kubernetesConfig        `yaml:"kubernetes,omitempty"`
+	Theme      themeConfig              `yaml:"theme"`
+	Profiles   map[string]profileConfig `yaml:"profiles,omitempty"`
+}
+
+type gitlabConfig struct {
+	URL     string `yaml:"url"`
+	Token   string `yaml:"token,omitempty"`
+	Project string `yaml:"project,omitempty"`
+}
+
+type defaultsConfig struct {
+	Output string `yaml:"output"`
+}
+
+type kubernetesConfig struct {
+	Context   string `yaml:"context,omitempty"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+type themeConfig struct {
+	Preset string `yaml:"preset"`
+}
+
+type profileConfig struct {
+	Kubernetes kubernetesConfig `yaml:"kubernetes"`
+}
+
+func newInitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Interactively write the config file",
+		Long: `Interactively write ~/.devops-toolkit.yaml (or --config).
+
+Asks for the GitLab URL, token reference and project, the default
+Kubernetes context and namespace, the color theme and optional profiles.
+Press Enter to accept the default shown in brackets.
+
+Tokens are best stored as secret references (vault:, awssm:, sops:) or
+left empty to use GITLAB_TOKEN; plaintext tokens are only written after
+confirmation. The file is created with 0600 permissions.`,
+		Example: `  devops-toolkit config init
+  devops-toolkit config init --config ./ci.yaml --force
+  devops-toolkit config init --defaults`,
+		RunE: runInit,
+	}
+
+	cmd.Flags().Bool("force", false, "Overwrite an existing config file")
+	cmd.Flags().Bool("defaults", false, "Write the defaults without prompting")
+
+	return cmd
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	force, _ := cmd.Flags().GetBool("force")
+	useDefaults, _ := cmd.Flags().GetBool("defaults")
+
+	path, err := configPath(cmd)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil && !force {
+		return exitcode.ConfigError(fmt.Errorf("%s already exists (use --force to overwrite, or config validate to check it)", path))
+	}
+
+	var in io.Reader = os.Stdin
+	if useDefaults {
+		in = strings.NewReader("")
+	}
+	p := &prompter{in: bufio.NewReader(in), interactive: !useDefaults}
+
+	output.Header("Config Init")
+	output.Muted(fmt.Sprintf("Writing %s. Press Enter to accept the default in brackets.", path))
+	output.Newline()
+
+	cfg := fileConfig{
+		Defaults: defaultsConfig{Output: "table"},
+		Theme:    themeConfig{Preset: "dark"},
+	}
+
+	// GitLab
+	output.Print(output.SubSection("GitLab"))
+	if p.confirm("Configure GitLab?", true) {
+		gl := &gitlabConfig{URL: p.ask("GitLab URL", "https://gitlab.com")}
+		output.Muted("  Token: a secret reference like vault:secret/data/ci#gitlab_token, or empty to use GITLAB_TOKEN")
+		gl.Token = p.ask("GitLab token reference", "")
+		if gl.Token != "" && !secrets.IsReference(gl.Token) &&
+			!p.confirm("That looks like a plaintext token. Store it in the config file anyway?", false) {
+			gl.Token = ""
+			output.Muted("  Token not stored; set GITLAB_TOKEN instead")
+		}
+		gl.Project = p.ask("Default project (group/name)", "")
+		cfg.GitLab = gl
+	}
+
+	// Kubernetes
+	output.Print(output.SubSection("Kubernetes"))
+	contexts, current := kubeContexts()
+	if len(contexts) > 0 {
+		output.Muted("  Contexts: " + strings.Join(contexts, ", "))
+	}
+	k := &kubernetesConfig{
+		Context:   p.ask("Default context (empty for kubeconfig current-context)", ""),
+		Namespace: p.ask("Default namespace (empty for all)", ""),
+	}
+	if k.Context == current {
+		k.Context = ""
+	}
+	if *k != (kubernetesConfig{}) {
+		cfg.Kubernetes = k
+	}
+
+	// Appearance
+	output.Print(output.SubSection("Appearance"))
+	cfg.Theme.Preset = p.choose("Theme", []string{"dark", "light", "mono"}, "dark")
+	cfg.Defaults.Output = p.choose("Default output", []string{"table", "json", "yaml"}, "table")
+
+	// Profiles
+	output.Print(output.SubSection("Profiles"))
+	output.Muted("  Profiles switch context and namespace together, e.g. --profile staging")
+	for _, name := range splitList(p.ask("Profile names (comma-separated, empty for none)", "")) {
+		if cfg.Profiles == nil {
+			cfg.Profiles = map[string]profileConfig{}
+		}
+		cfg.Profiles[name] = profileConfig{Kubernetes: kubernetesConfig{
+			Context:   p.ask(fmt.Sprintf("  %s: context", name), name),
+			Namespace: p.ask(fmt.Sprintf("  %s: namespace", name), ""),
+		}}
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	output.Newline()
+	output.Success(fmt.Sprintf("Config written to %s", path))
+	output.Muted("Run 'devops-toolkit config validate' to check connectivity to each backend.")
+	return nil
+}
+
+// kubeContexts returns the contexts in the default kubeconfig and the current one
+func kubeContexts() ([]string, string) {
+	config, err := clientcmd.NewDefaultClientConfigLoadingRules().Load()
+	if err != nil {
+		return nil, ""
+	}
+	names := make([]string, 0, len(config.Contexts))
+	for name := range config.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, config.CurrentContext
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// prompter reads answers line by line; at end of input every question takes its default
+type prompter struct {
+	in          *bufio.Reader
+	interactive bool
+}
+
+func (p *prompter) ask(question, def string) string {
+	if !p.interactive {
+		return def
+	}
+	if def != "" {
+		output.Printf("%s [%s]: ", question, def)
+	} else {
+		output.Printf("%s: ", question)
+	}
+
+	line, err := p.in.ReadString('\n')
+	if err != nil && line == "" {
+		p.interactive = false
+		output.Newline()
+	}
+	if line = strings.TrimSpace(line); line != "" {
+		return line
+	}
+	return def
+}
+
+func (p *prompter) confirm(question string, def bool) bool {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		switch strings.ToLower(p.ask(question+" ("+hint+")", "")) {
+		case "":
+			return def
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		}
+		output.Warning("Please answer y or n")
+	}
+}
+
+func (p *prompter) choose(question string, options []string, def string) string {
+	for {
+		answer := strings.ToLower(p.ask(fmt.Sprintf("%s (%s)", question, strings.Join(options, ", ")), def))
+		for _, opt := range options {
+			if answer == opt {
+				return opt
+			}
+		}
+		output.Warning(fmt.Sprintf("Choose one of: %s", strings.Join(options, ", ")))
+	}
+}
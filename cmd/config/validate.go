@@ -0,0 +1,402 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/aws"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/loki"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/notify"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/prometheus"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/secrets"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// Check statuses
+const (
+	statusOK      = "ok"
+	statusWarning = "warning"
+	statusFailed  = "failed"
+	statusSkipped = "skipped"
+)
+
+// backends are the checks config validate runs, in order
+var backends = []string{"config", "kubernetes", "docker", "gitlab", "prometheus", "loki", "aws", "notify"}
+
+// knownKeys are the top-level keys the toolkit reads from the config file
+var knownKeys = []string{
+	"absolute-time", "audit", "aws", "compliance", "defaults", "docker", "gitlab", "kubernetes", "loki",
+	"monitor", "net", "no-color", "notify", "output", "profile", "profiles", "prometheus", "terraform",
+	"theme", "timeout", "utc", "verbose",
+}
+
+// checkResult is the outcome of validating one backend
+type checkResult struct {
+	Backend string `json:"backend" yaml:"backend"`
+	Status  string `json:"status" yaml:"status"`
+	Detail  string `json:"detail" yaml:"detail"`
+	Hint    string `json:"hint,omitempty" yaml:"hint,omitempty"`
+}
+
+func newValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check the config file and connectivity to each backend",
+		Long: `Check that the config file parses and that every configured backend is reachable
+with the configured credentials.
+
+Checks:
+  • config      The file parses and has no unknown top-level keys
+  • kubernetes  The kubeconfig and context load and the API server answers
+  • docker      The daemon answers
+  • gitlab      The token is valid, has read_api and is not about to expire; the project exists
+  • prometheus  A query succeeds
+  • loki        The labels API answers for the configured tenant
+  • aws         The credentials resolve to an identity
+  • notify      Sink URLs and their secret references resolve
+
+Backends that are not configured are skipped. Failed checks include a hint
+on what to fix. Select a profile with --profile to validate it.`,
+		Example: `  devops-toolkit config validate
+  devops-toolkit config validate --profile prod
+  devops-toolkit config validate --backend gitlab,kubernetes --output json`,
+		RunE: runValidate,
+	}
+
+	cmd.Flags().StringSlice("backend", nil, "Only check these backends ("+strings.Join(backends, ", ")+")")
+	cmd.Flags().String("kubeconfig", "", "Path to kubeconfig file")
+	cmd.Flags().StringP("context", "c", "", "Kubernetes context to use")
+	cmd.Flags().String("host", "", "Docker daemon to check (default DOCKER_HOST or docker.host)")
+
+	_ = cmd.RegisterFlagCompletionFunc("backend", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return backends, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	selected, _ := cmd.Flags().GetStringSlice("backend")
+	for _, b := range selected {
+		if !contains(backends, b) {
+			return exitcode.ConfigError(fmt.Errorf("unknown backend %q (valid: %s)", b, strings.Join(backends, ", ")))
+		}
+	}
+
+	ctx := cmd.Context()
+	checks := map[string]func() checkResult{
+		"config":     func() checkResult { return checkConfigFile(cmd) },
+		"kubernetes": func() checkResult { return checkKubernetes(ctx, cmd) },
+		"docker":     func() checkResult { return checkDocker(ctx) },
+		"gitlab":     func() checkResult { return checkGitLab(ctx) },
+		"prometheus": func() checkResult { return checkPrometheus(ctx) },
+		"loki":       func() checkResult { return checkLoki(ctx) },
+		"aws":        func() checkResult { return checkAWS(ctx) },
+		"notify":     checkNotify,
+	}
+
+	var results []checkResult
+	for _, backend := range backends {
+		if len(selected) > 0 && !contains(selected, backend) {
+			continue
+		}
+		output.StartSpinner(fmt.Sprintf("Checking %s...", backend))
+		result := checks[backend]()
+		result.Backend = backend
+		output.StopSpinner()
+		results = append(results, result)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Status == statusFailed {
+			failed++
+		}
+	}
+
+	if output.IsStructured() {
+		if err := output.Render(results); err != nil {
+			return err
+		}
+	} else {
+		renderResults(results)
+	}
+
+	if failed > 0 {
+		return exitcode.ChecksFailedf("%d of %d config checks failed", failed, len(results))
+	}
+	return nil
+}
+
+func renderResults(results []checkResult) {
+	output.Header("Config Validation")
+
+	table := output.NewTable(output.TableConfig{
+		Title:   "Backends",
+		Headers: []string{"Backend", "Status", "Detail"},
+	})
+	for _, r := range results {
+		icon, color := output.IconSuccess, tablewriter.FgGreenColor
+		switch r.Status {
+		case statusWarning:
+			icon, color = output.IconWarning, tablewriter.FgYellowColor
+		case statusFailed:
+			icon, color = output.IconError, tablewriter.FgRedColor
+		case statusSkipped:
+			icon, color = output.IconDot, tablewriter.FgHiBlackColor
+		}
+		table.AddColoredRow(
+			[]string{r.Backend, icon + " " + r.Status, r.Detail},
+			[]tablewriter.Colors{{tablewriter.FgCyanColor}, {color}, {tablewriter.FgWhiteColor}},
+		)
+	}
+	table.Render()
+
+	var hinted bool
+	for _, r := range results {
+		if r.Hint == "" || r.Status == statusOK {
+			continue
+		}
+		if !hinted {
+			output.Newline()
+			output.Print(output.SubSection("How to fix"))
+			hinted = true
+		}
+		output.Printf("  %s %s: %s\n", output.MutedStyle.Render(output.IconBullet), r.Backend, r.Hint)
+	}
+	output.Newline()
+}
+
+// checkConfigFile parses the config file directly, since loading ignores errors
+func checkConfigFile(cmd *cobra.Command) checkResult {
+	path, err := configPath(cmd)
+	if err != nil {
+		return checkResult{Status: statusFailed, Detail: err.Error()}
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return checkResult{
+			Status: statusWarning,
+			Detail: fmt.Sprintf("%s not found; using flags and environment only", path),
+			Hint:   "run 'devops-toolkit config init' to create it",
+		}
+	}
+	if err != nil {
+		return checkResult{Status: statusFailed, Detail: err.Error(), Hint: "check the file permissions"}
+	}
+
+	var settings map[string]interface{}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return checkResult{
+			Status: statusFailed,
+			Detail: fmt.Sprintf("%s does not parse: %s", path, err),
+			Hint:   "fix the YAML syntax; settings from this file are currently ignored",
+		}
+	}
+
+	var unknown []string
+	for key := range settings {
+		if !contains(knownKeys, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return checkResult{
+			Status: statusWarning,
+			Detail: fmt.Sprintf("%s: unknown keys %s", path, strings.Join(unknown, ", ")),
+			Hint:   "check for typos; known keys are " + strings.Join(knownKeys, ", "),
+		}
+	}
+
+	detail := path
+	if profile := viper.GetString("profile"); profile != "" {
+		detail += fmt.Sprintf(" (profile %s)", profile)
+	}
+	return checkResult{Status: statusOK, Detail: detail}
+}
+
+func checkKubernetes(ctx context.Context, cmd *cobra.Command) checkResult {
+	client, err := k8s.NewClient(cmd.Flag("kubeconfig").Value.String(), cmd.Flag("context").Value.String())
+	if err != nil {
+		return checkResult{
+			Status: statusFailed,
+			Detail: err.Error(),
+			Hint:   "check kubernetes.kubeconfig and kubernetes.context; 'kubectl config get-contexts' lists valid contexts",
+		}
+	}
+
+	info, err := client.GetClusterInfo(ctx)
+	if err != nil {
+		return failure(err, fmt.Sprintf("cannot reach %s", client.Server()),
+			"check VPN/network access to the API server, and that your credentials have not expired (e.g. re-run your cloud login)")
+	}
+
+	detail := fmt.Sprintf("%s, Kubernetes %s", info.Server, info.K8sVersion)
+	if client.Context() != "" {
+		detail = fmt.Sprintf("context %s: %s", client.Context(), detail)
+	}
+	return checkResult{Status: statusOK, Detail: detail}
+}
+
+func checkDocker(ctx context.Context) checkResult {
+	client, err := docker.NewClient()
+	if err != nil {
+		return checkResult{Status: statusFailed, Detail: err.Error(), Hint: "check docker.host or DOCKER_HOST"}
+	}
+	defer client.Close()
+
+	host, err := client.GetHostInfo(ctx)
+	if err != nil {
+		return failure(err, fmt.Sprintf("cannot reach %s", client.Host()),
+			"start the Docker daemon, or point docker.host / DOCKER_HOST at a reachable daemon; skip with --backend if Docker is not used")
+	}
+	return checkResult{Status: statusOK, Detail: fmt.Sprintf("%s, Docker %s", host.Host, host.ServerVersion)}
+}
+
+func checkGitLab(ctx context.Context) checkResult {
+	token := setting("GITLAB_TOKEN", "gitlab.token")
+	if token == "" {
+		return checkResult{Status: statusSkipped, Detail: "no token configured", Hint: "set gitlab.token or GITLAB_TOKEN to use gitlab commands"}
+	}
+	token, err := secrets.Resolve(token)
+	if err != nil {
+		return checkResult{Status: statusFailed, Detail: err.Error(), Hint: "check that the secret store is reachable and you are logged in to it"}
+	}
+
+	url := setting("GITLAB_URL", "gitlab.url")
+	if url == "" {
+		url = "https://gitlab.com"
+	}
+	client, err := gitlabclient.NewClient(url, token)
+	if err != nil {
+		return checkResult{Status: statusFailed, Detail: err.Error(), Hint: "check gitlab.url"}
+	}
+
+	warnings, err := client.CheckToken(ctx, gitlabclient.ScopeReadAPI, 14*24*time.Hour)
+	if err != nil && !errors.Is(err, gitlabclient.ErrTokenUncheckable) {
+		return failure(err, fmt.Sprintf("token rejected by %s", url),
+			"create a token with the read_api scope (api for trigger and cleanup) and update gitlab.token")
+	}
+
+	detail := url
+	if project := setting("GITLAB_PROJECT", "gitlab.project"); project != "" {
+		info, err := client.GetProject(ctx, project)
+		if err != nil {
+			return failure(err, fmt.Sprintf("project %s: %s", project, err),
+				"check gitlab.project is the full group/name path and the token's user is a member")
+		}
+		detail += ", project " + info.PathWithNamespace
+	}
+
+	if len(warnings) > 0 {
+		return checkResult{Status: statusWarning, Detail: strings.Join(warnings, "; "), Hint: "rotate the token before it expires"}
+	}
+	return checkResult{Status: statusOK, Detail: detail}
+}
+
+func checkPrometheus(ctx context.Context) checkResult {
+	url := setting("PROMETHEUS_URL", "prometheus.url")
+	if url == "" {
+		return checkResult{Status: statusSkipped, Detail: "no URL configured"}
+	}
+	token, err := secrets.Resolve(setting("PROMETHEUS_TOKEN", "prometheus.token"))
+	if err != nil {
+		return checkResult{Status: statusFailed, Detail: err.Error(), Hint: "check that the secret store is reachable and you are logged in to it"}
+	}
+	client, err := prometheus.NewClient(url, token)
+	if err != nil {
+		return checkResult{Status: statusFailed, Detail: err.Error(), Hint: "set prometheus.url to the server root, e.g. http://prometheus.monitoring:9090"}
+	}
+	if _, err := client.Query(ctx, "vector(1)"); err != nil {
+		return failure(err, err.Error(), "check prometheus.url is reachable from here and prometheus.token is valid")
+	}
+	return checkResult{Status: statusOK, Detail: url}
+}
+
+func checkLoki(ctx context.Context) checkResult {
+	url := setting("LOKI_URL", "loki.url")
+	if url == "" {
+		return checkResult{Status: statusSkipped, Detail: "no URL configured"}
+	}
+	token, err := secrets.Resolve(setting("LOKI_TOKEN", "loki.token"))
+	if err != nil {
+		return checkResult{Status: statusFailed, Detail: err.Error(), Hint: "check that the secret store is reachable and you are logged in to it"}
+	}
+	client, err := loki.NewClient(url, token, viper.GetString("loki.org_id"))
+	if err != nil {
+		return checkResult{Status: statusFailed, Detail: err.Error(), Hint: "set loki.url to the server root, e.g. http://loki.monitoring:3100"}
+	}
+	labels, err := client.Labels(ctx)
+	if err != nil {
+		return failure(err, err.Error(), "check loki.url is reachable, loki.token is valid, and loki.org_id is set for multi-tenant Loki")
+	}
+	return checkResult{Status: statusOK, Detail: fmt.Sprintf("%s, %d labels", url, len(labels))}
+}
+
+func checkAWS(ctx context.Context) checkResult {
+	profile, region := viper.GetString("aws.profile"), viper.GetString("aws.region")
+	if profile == "" && region == "" && os.Getenv("AWS_PROFILE") == "" {
+		return checkResult{Status: statusSkipped, Detail: "no profile or region configured"}
+	}
+	client, err := aws.NewClient(profile, region)
+	if err != nil {
+		return checkResult{Status: statusFailed, Detail: err.Error(), Hint: "check aws.profile exists in ~/.aws/config"}
+	}
+	identity, err := client.GetCallerIdentity(ctx)
+	if err != nil {
+		return failure(err, err.Error(), "log in with 'aws sso login' or check aws.profile exists in ~/.aws/config")
+	}
+	return checkResult{Status: statusOK, Detail: fmt.Sprintf("%s in %s", identity.Arn, client.Region())}
+}
+
+func checkNotify() checkResult {
+	sinks, err := notify.Sinks()
+	if err != nil {
+		return checkResult{Status: statusFailed, Detail: err.Error(), Hint: "fix the notify section; sink URLs may be secret references"}
+	}
+	if len(sinks) == 0 {
+		return checkResult{Status: statusSkipped, Detail: "no sinks configured"}
+	}
+	return checkResult{Status: statusOK, Detail: fmt.Sprintf("%d sinks configured (not sent to)", len(sinks))}
+}
+
+// failure turns a backend error into a failed check, replacing the hint for
+// timeouts, which point at the network rather than the settings
+func failure(err error, detail, hint string) checkResult {
+	if exitcode.Classify(err).Code == exitcode.Timeout {
+		hint = "the backend did not answer in time; check network access or raise --timeout"
+	}
+	return checkResult{Status: statusFailed, Detail: detail, Hint: hint}
+}
+
+// setting returns the environment variable, or the config key when it is unset
+func setting(env, key string) string {
+	if v := os.Getenv(env); v != "" {
+		return v
+	}
+	return viper.GetString(key)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package docker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/compliance"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit <container>",
+		Short: "Security posture scorecard for a container",
+		Long: `Run the Docker compliance rules against a single container, including the
+Docker socket and sensitive host path mount rules, and check the security
+options it is running with:
+
+  • Seccomp profile (not unconfined)
+  • AppArmor profile (not unconfined)
+  • no-new-privileges
+
+Every rule is listed as passed or failed, with a score and letter grade.
+Use devops-toolkit compliance check docker to scan all containers.
+
+Examples:
+  devops-toolkit docker audit web
+  devops-toolkit docker audit web --severity high
+  devops-toolkit docker audit web --output json`,
+		Args:              cobra.ExactArgs(1),
+		RunE:              runAudit,
+		ValidArgsFunction: completion.ContainerCompletion,
+	}
+
+	cmd.Flags().StringSlice("skip", nil, "Rules to skip")
+	cmd.Flags().String("severity", "", "Minimum severity to report (low, medium, high, critical)")
+
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("skip", completion.RuleCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("severity", completion.SeverityCompletion)
+
+	return cmd
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	minSeverity, _ := cmd.Flags().GetString("severity")
+	skip, _ := cmd.Flags().GetStringSlice("skip")
+
+	output.StartSpinner(fmt.Sprintf("Auditing %s...", args[0]))
+
+	checker := compliance.NewDockerChecker(compliance.CheckOptions{
+		MinSeverity: minSeverity,
+		SkipRules:   skip,
+	})
+	card, err := checker.AuditContainer(cmd.Context(), args[0])
+	if err != nil {
+		output.SpinnerError("Audit failed")
+		return err
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Audited %s: %d of %d checks passed", card.Container, card.Summary.Passed, card.Summary.Total-card.Summary.Skipped))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(card)
+	}
+
+	output.Print(output.Section("Container"))
+	output.Printf("  %s\n", output.KeyValue("Name", card.Container))
+	output.Printf("  %s\n", output.KeyValue("ID", truncateID(card.ID)))
+	output.Printf("  %s\n", output.KeyValue("Image", card.Image))
+	output.Newline()
+
+	// Failures first, most severe first
+	results := card.Results
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Status != results[j].Status {
+			return auditStatusRank(results[i].Status) < auditStatusRank(results[j].Status)
+		}
+		return severityRank(results[i].Severity) > severityRank(results[j].Severity)
+	})
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Security Scorecard",
+		Headers:    []string{"Status", "Severity", "Rule", "Check", "Details"},
+		ShowBorder: true,
+	})
+
+	for _, r := range results {
+		statusColor := tablewriter.FgGreenColor
+		switch r.Status {
+		case compliance.StatusFailed:
+			statusColor = tablewriter.FgRedColor
+		case compliance.StatusSkipped:
+			statusColor = tablewriter.FgHiBlackColor
+		}
+		severityColor := tablewriter.FgCyanColor
+		switch r.Severity {
+		case "critical", "high":
+			severityColor = tablewriter.FgRedColor
+		case "medium":
+			severityColor = tablewriter.FgYellowColor
+		}
+
+		table.AddColoredRow(
+			[]string{
+				string(r.Status),
+				strings.ToUpper(r.Severity),
+				r.RuleID,
+				r.RuleName,
+				output.Truncate(r.Message, 50),
+			},
+			[]tablewriter.Colors{
+				{tablewriter.Bold, statusColor},
+				{severityColor},
+				{tablewriter.FgCyanColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgHiBlackColor},
+			},
+		)
+	}
+
+	table.Render()
+
+	var failed []compliance.CheckResult
+	for _, r := range results {
+		if r.Status == compliance.StatusFailed && r.Remediation != "" {
+			failed = append(failed, r)
+		}
+	}
+	if len(failed) > 0 {
+		output.Newline()
+		output.Print(output.Section("Remediation"))
+		for _, r := range failed {
+			output.Printf("  %s %s: %s\n", output.IconArrow, r.RuleID, r.Remediation)
+		}
+	}
+
+	output.Newline()
+	output.Print(output.Section("Score"))
+	bar := output.ProgressBar(int(card.Summary.Score), 100, 30)
+	output.Printf("  %s %.1f%% (grade %s)\n", bar, card.Summary.Score, card.Grade)
+	output.Newline()
+	return nil
+}
+
+func auditStatusRank(status compliance.CheckStatus) int {
+	switch status {
+	case compliance.StatusFailed:
+		return 0
+	case compliance.StatusWarning:
+		return 1
+	case compliance.StatusPassed:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case "critical":
+		return 4
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	case "low":
+		return 1
+	default:
+		return 0
+	}
+}
@@ -1,11 +1,15 @@
 package docker
 
 import (
-	"context"
 	"fmt"
 
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/audit"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/batch"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
@@ -20,7 +24,8 @@ Cleanup targets:
   • Dangling images
   • Unused networks
   • Build cache
-  • Unused volumes (with --volumes flag)`,
+  • Unused volumes (with --volumes flag; back them up first with
+    docker volume backup <volume>)`,
 		RunE: runClean,
 	}
 
@@ -46,7 +51,7 @@ func runClean(cmd *cobra.Command, args []string) error {
 	}
 	defer client.Close()
 
-	ctx := context.Background()
+	ctx := cmd.Context()
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	cleanContainers, _ := cmd.Flags().GetBool("containers")
 	cleanImages, _ := cmd.Flags().GetBool("images")
@@ -64,6 +69,7 @@ func runClean(cmd *cobra.Command, args []string) error {
 	}
 
 	var totalSpaceReclaimed int64
+	var failures []cleanupFailure
 
 	// Clean stopped containers
 	if cleanContainers {
@@ -76,18 +82,21 @@ func runClean(cmd *cobra.Command, args []string) error {
 			if len(containers) > 0 {
 				output.Printf("\n%s Found %d stopped containers:\n",
 					output.InfoStyle.Render(output.IconInfo), len(containers))
+				var names []string
 				for _, c := range containers {
+					names = append(names, c.Name)
 					output.Printf("  %s %s (%s)\n",
 						output.MutedStyle.Render(output.IconBullet),
 						c.Name, truncateID(c.ID))
 				}
 				if !dryRun {
 					deleted, space, err := client.RemoveContainers(ctx, containers)
-					if err != nil {
-						output.Error(fmt.Sprintf("Failed to remove some containers: %v", err))
-					}
+					failures = append(failures, cleanupFailures("container", err)...)
 					totalSpaceReclaimed += space
 					output.Successf("Removed %d containers", deleted)
+					recordClean(client, "remove-stopped-containers", names, false, deleted, err)
+				} else {
+					recordClean(client, "remove-stopped-containers", names, true, 0, nil)
 				}
 			} else {
 				output.Success("No stopped containers found")
@@ -116,25 +125,28 @@ func runClean(cmd *cobra.Command, args []string) error {
 
 				output.Printf("\n%s Found %d %s images (%s):\n",
 					output.InfoStyle.Render(output.IconInfo),
-					len(images), label, formatSize(totalSize))
+					len(images), label, format.Size(totalSize))
 
+				var names []string
 				for _, img := range images {
 					name := img.Repository
 					if img.Tag != "" && img.Tag != "<none>" {
 						name = fmt.Sprintf("%s:%s", img.Repository, img.Tag)
 					}
+					names = append(names, name+" ("+truncateID(img.ID)+")")
 					output.Printf("  %s %s (%s)\n",
 						output.MutedStyle.Render(output.IconBullet),
-						name, formatSize(img.Size))
+						name, format.Size(img.Size))
 				}
 
 				if !dryRun {
 					deleted, space, err := client.RemoveImages(ctx, images)
-					if err != nil {
-						output.Error(fmt.Sprintf("Failed to remove some images: %v", err))
-					}
+					failures = append(failures, cleanupFailures("image", err)...)
 					totalSpaceReclaimed += space
-					output.Successf("Removed %d images, reclaimed %s", deleted, formatSize(space))
+					output.Successf("Removed %d images, reclaimed %s", deleted, format.Size(space))
+					recordClean(client, "remove-"+label+"-images", names, false, deleted, err)
+				} else {
+					recordClean(client, "remove-"+label+"-images", names, true, 0, nil)
 				}
 			} else {
 				output.Success("No unused images found")
@@ -153,16 +165,19 @@ func runClean(cmd *cobra.Command, args []string) error {
 			if len(networks) > 0 {
 				output.Printf("\n%s Found %d unused networks:\n",
 					output.InfoStyle.Render(output.IconInfo), len(networks))
+				var names []string
 				for _, n := range networks {
+					names = append(names, n.Name)
 					output.Printf("  %s %s\n",
 						output.MutedStyle.Render(output.IconBullet), n.Name)
 				}
 				if !dryRun {
 					deleted, err := client.RemoveNetworks(ctx, networks)
-					if err != nil {
-						output.Error(fmt.Sprintf("Failed to remove some networks: %v", err))
-					}
+					failures = append(failures, cleanupFailures("network", err)...)
 					output.Successf("Removed %d networks", deleted)
+					recordClean(client, "remove-unused-networks", names, false, deleted, err)
+				} else {
+					recordClean(client, "remove-unused-networks", names, true, 0, nil)
 				}
 			} else {
 				output.Success("No unused networks found")
@@ -186,21 +201,25 @@ func runClean(cmd *cobra.Command, args []string) error {
 
 				output.Printf("\n%s Found %d unused volumes (%s):\n",
 					output.WarningStyle.Render(output.IconWarning),
-					len(volumes), formatSize(totalSize))
+					len(volumes), format.Size(totalSize))
 
+				var names []string
 				for _, v := range volumes {
+					names = append(names, v.Name)
 					output.Printf("  %s %s (%s)\n",
 						output.WarningStyle.Render(output.IconBullet),
-						v.Name, formatSize(v.Size))
+						v.Name, format.Size(v.Size))
 				}
 
 				if !dryRun {
 					deleted, space, err := client.RemoveVolumes(ctx, volumes)
-					if err != nil {
-						output.Error(fmt.Sprintf("Failed to remove some volumes: %v", err))
-					}
+					failures = append(failures, cleanupFailures("volume", err)...)
 					totalSpaceReclaimed += space
-					output.Successf("Removed %d volumes, reclaimed %s", deleted, formatSize(space))
+					output.Successf("Removed %d volumes, reclaimed %s", deleted, format.Size(space))
+					recordClean(client, "remove-unused-volumes", names, false, deleted, err)
+				} else {
+					output.Muted("  Back up volumes first with: devops-toolkit docker volume backup <volume>")
+					recordClean(client, "remove-unused-volumes", names, true, 0, nil)
 				}
 			} else {
 				output.Success("No unused volumes found")
@@ -218,16 +237,20 @@ func runClean(cmd *cobra.Command, args []string) error {
 			output.StopSpinner()
 			if cacheSize > 0 {
 				output.Printf("\n%s Build cache using %s\n",
-					output.InfoStyle.Render(output.IconInfo), formatSize(cacheSize))
+					output.InfoStyle.Render(output.IconInfo), format.Size(cacheSize))
 
 				if !dryRun {
 					reclaimed, err := client.PruneBuildCache(ctx)
 					if err != nil {
 						output.Error(fmt.Sprintf("Failed to prune build cache: %v", err))
+						recordClean(client, "prune-build-cache", []string{"build cache"}, false, 0, err)
 					} else {
 						totalSpaceReclaimed += reclaimed
-						output.Successf("Cleared build cache, reclaimed %s", formatSize(reclaimed))
+						output.Successf("Cleared build cache, reclaimed %s", format.Size(reclaimed))
+						recordClean(client, "prune-build-cache", []string{"build cache"}, false, 1, nil)
 					}
+				} else {
+					recordClean(client, "prune-build-cache", []string{"build cache"}, true, 0, nil)
 				}
 			} else {
 				output.Success("Build cache is empty")
@@ -243,9 +266,66 @@ func runClean(cmd *cobra.Command, args []string) error {
 	if dryRun {
 		output.Info("Dry-run complete. Use --dry-run=false to actually delete resources.")
 	} else {
-		output.Successf("Cleanup complete! Reclaimed %s of disk space.", formatSize(totalSpaceReclaimed))
+		output.Successf("Cleanup complete! Reclaimed %s of disk space.", format.Size(totalSpaceReclaimed))
+	}
+
+	if len(failures) > 0 {
+		output.Newline()
+		printCleanupFailures(failures)
+		output.Newline()
+		return exitcode.PartialError(fmt.Errorf("%d resources could not be removed", len(failures)))
 	}
 
 	output.Newline()
 	return nil
 }
+
+// recordClean writes a cleanup step to the audit log
+func recordClean(client *docker.Client, action string, resources []string, dryRun bool, removed int, err error) {
+	entry := audit.Entry{
+		Command:   "docker clean",
+		Action:    action,
+		Target:    client.Host(),
+		Resources: resources,
+		DryRun:    dryRun,
+		Result:    audit.Result(dryRun, removed, len(resources), err),
+	}
+	if auditErr := audit.Record(entry, err); auditErr != nil {
+		output.Warning(auditErr.Error())
+	}
+}
+
+// cleanupFailure is an item that could not be removed
+type cleanupFailure struct {
+	Kind string
+	batch.Failure
+}
+
+// cleanupFailures tags the per-item failures of a removal with their kind
+func cleanupFailures(kind string, err error) []cleanupFailure {
+	var result []cleanupFailure
+	for _, f := range batch.Failures(err, "all "+kind+"s") {
+		result = append(result, cleanupFailure{Kind: kind, Failure: f})
+	}
+	if len(result) > 0 {
+		output.Warningf("%d %ss could not be removed", len(result), kind)
+	}
+	return result
+}
+
+// printCleanupFailures renders what was not cleaned and why
+func printCleanupFailures(failures []cleanupFailure) {
+	table := output.NewTable(output.TableConfig{
+		Title:      "Not Cleaned",
+		Headers:    []string{"Kind", "Item", "Reason"},
+		ShowBorder: true,
+	})
+	for _, f := range failures {
+		table.AddColoredRow([]string{f.Kind, output.Truncate(f.Item, 50), output.Truncate(f.Err.Error(), 70)}, []tablewriter.Colors{
+			{tablewriter.FgHiBlackColor},
+			{tablewriter.FgCyanColor},
+			{tablewriter.FgRedColor},
+		})
+	}
+	table.Render()
+}
@@ -0,0 +1,220 @@
+package docker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newComposeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compose",
+		Short: "Docker Compose project operations",
+		Long: `Inspect services started by docker compose.
+
+Containers are grouped by their com.docker.compose.project and
+com.docker.compose.service labels, so no compose file is needed.`,
+	}
+
+	cmd.AddCommand(newComposeHealthCmd())
+
+	return cmd
+}
+
+func newComposeHealthCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "health",
+		Short: "Show health of compose services",
+		Long: `Show each compose service with its replica count, health status and
+published endpoints.
+
+A service is unhealthy when any replica reports an unhealthy health check
+or has stopped with a non-zero exit code or is restarting. Replicas that
+exited with code 0, such as init or migration services, count as completed.
+
+The command exits non-zero if any service is unhealthy or still starting,
+so it can be used as a smoke test after docker compose up. Use --wait to
+give health checks time to pass before failing.
+
+Examples:
+  devops-toolkit docker compose health
+  devops-toolkit docker compose health -p shop
+  devops-toolkit docker compose health -p shop --wait 2m
+  devops-toolkit docker compose health --output json`,
+		SilenceUsage: true,
+		RunE:         runComposeHealth,
+	}
+
+	cmd.Flags().StringP("project", "p", "", "Only show services of this compose project")
+	cmd.Flags().Duration("wait", 0, "Wait up to this long for all services to become healthy")
+	cmd.Flags().Duration("interval", 5*time.Second, "Poll interval while waiting")
+
+	return cmd
+}
+
+func runComposeHealth(cmd *cobra.Command, args []string) error {
+	project, _ := cmd.Flags().GetString("project")
+	wait, _ := cmd.Flags().GetDuration("wait")
+	interval, _ := cmd.Flags().GetDuration("interval")
+
+	output.StartSpinner("Checking compose services...")
+
+	client, err := docker.NewClient()
+	if err != nil {
+		output.SpinnerError("Failed to connect to Docker")
+		return fmt.Errorf("failed to create docker client: %w", err)
+	}
+	defer client.Close()
+
+	ctx := cmd.Context()
+	deadline := time.Now().Add(wait)
+	var services []docker.ComposeService
+	for {
+		services, err = client.ComposeServices(ctx, project)
+		if err != nil {
+			output.SpinnerError("Failed to list compose services")
+			return err
+		}
+		pending := countComposeStatus(services, docker.ServiceUnhealthy) + countComposeStatus(services, docker.ServiceStarting)
+		if pending == 0 || len(services) == 0 || !time.Now().Add(interval).Before(deadline) {
+			break
+		}
+		output.UpdateSpinner(fmt.Sprintf("Waiting for %d of %d services to become healthy...", pending, len(services)))
+		select {
+		case <-ctx.Done():
+			output.SpinnerError("Canceled while waiting for services")
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+
+	if len(services) == 0 {
+		output.SpinnerError("No compose services found")
+		if project != "" {
+			return fmt.Errorf("no containers found for compose project %q", project)
+		}
+		return fmt.Errorf("no compose-managed containers found")
+	}
+
+	unhealthy := countComposeStatus(services, docker.ServiceUnhealthy)
+	starting := countComposeStatus(services, docker.ServiceStarting)
+	if unhealthy+starting > 0 {
+		output.SpinnerError(fmt.Sprintf("%d of %d services are not healthy", unhealthy+starting, len(services)))
+	} else {
+		output.SpinnerSuccess(fmt.Sprintf("All %d services are healthy", len(services)))
+	}
+	output.Newline()
+
+	if output.IsStructured() {
+		if err := output.Render(services); err != nil {
+			return err
+		}
+		return composeHealthError(unhealthy, starting, len(services))
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Compose Services",
+		Headers:    []string{"Project", "Service", "Replicas", "Health", "Endpoints"},
+		ShowBorder: true,
+	})
+
+	for _, svc := range services {
+		healthColor := tablewriter.Colors{tablewriter.Bold, tablewriter.FgGreenColor}
+		switch svc.Status {
+		case docker.ServiceUnhealthy:
+			healthColor = tablewriter.Colors{tablewriter.Bold, tablewriter.FgRedColor}
+		case docker.ServiceStarting:
+			healthColor = tablewriter.Colors{tablewriter.Bold, tablewriter.FgYellowColor}
+		}
+
+		table.AddColoredRow(
+			[]string{
+				svc.Project,
+				svc.Service,
+				composeReplicas(svc),
+				composeHealthDetail(svc),
+				valueOr(strings.Join(svc.Endpoints, ", "), "-"),
+			},
+			[]tablewriter.Colors{
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgCyanColor},
+				{tablewriter.Bold},
+				healthColor,
+				{tablewriter.FgWhiteColor},
+			},
+		)
+	}
+
+	table.Render()
+
+	for _, svc := range services {
+		if svc.Status != docker.ServiceUnhealthy {
+			continue
+		}
+		output.Newline()
+		output.Print(output.Section(svc.Project + "/" + svc.Service))
+		for _, name := range svc.Containers {
+			output.Printf("  %s %s\n", output.IconBullet, name)
+		}
+		output.Muted(fmt.Sprintf("  Check logs with: devops-toolkit docker logs %s --level error", svc.Containers[0]))
+	}
+
+	output.Newline()
+	return composeHealthError(unhealthy, starting, len(services))
+}
+
+// composeReplicas shows running replicas out of all replicas, plus cleanly exited ones
+func composeReplicas(svc docker.ComposeService) string {
+	replicas := fmt.Sprintf("%d/%d", svc.Running, svc.Replicas)
+	if svc.Completed > 0 {
+		replicas += fmt.Sprintf(" (%d completed)", svc.Completed)
+	}
+	return replicas
+}
+
+// composeHealthDetail shows the service status with per-replica health counts
+func composeHealthDetail(svc docker.ComposeService) string {
+	var parts []string
+	if svc.Unhealthy > 0 {
+		parts = append(parts, fmt.Sprintf("%d unhealthy", svc.Unhealthy))
+	}
+	if svc.Failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", svc.Failed))
+	}
+	if svc.Starting > 0 {
+		parts = append(parts, fmt.Sprintf("%d starting", svc.Starting))
+	}
+	if svc.Healthy > 0 && svc.Healthy < svc.Replicas {
+		parts = append(parts, fmt.Sprintf("%d healthy", svc.Healthy))
+	}
+	if len(parts) == 0 {
+		return svc.Status
+	}
+	return fmt.Sprintf("%s (%s)", svc.Status, strings.Join(parts, ", "))
+}
+
+func countComposeStatus(services []docker.ComposeService, status string) int {
+	count := 0
+	for _, svc := range services {
+		if svc.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func composeHealthError(unhealthy, starting, total int) error {
+	switch {
+	case unhealthy > 0:
+		return exitcode.ChecksFailedf("%d of %d compose services unhealthy", unhealthy, total)
+	case starting > 0:
+		return exitcode.ChecksFailedf("%d of %d compose services still starting", starting, total)
+	}
+	return nil
+}
@@ -1,12 +1,13 @@
 package docker
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/run"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
@@ -22,19 +23,27 @@ Features:
   • Color-coded status indicators
   • Resource usage display
   • Port mapping visualization
-  • Health check status`,
+  • Health check status
+  • GPU allocation with --wide (--gpus or the nvidia runtime)`,
 		RunE: runContainers,
 	}
 
 	cmd.Flags().BoolP("all", "a", false, "Show all containers (including stopped)")
 	cmd.Flags().Bool("wide", false, "Show additional information")
-	cmd.Flags().StringP("filter", "f", "", "Filter containers (name, status, label)")
+	cmd.Flags().StringP("filter", "f", "", "Filter containers, e.g. status=exited,name=web,label=app=api")
 	cmd.Flags().Bool("size", false, "Show container sizes")
+	cmd.Flags().BoolP("quiet", "q", false, "Only print container IDs")
 
-	return cmd
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("filter", completion.ContainerFilterCompletion)
+
+	return output.Watchable(cmd)
 }
 
 func runContainers(cmd *cobra.Command, args []string) error {
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		output.SetQuiet()
+	}
 	output.StartSpinner("Fetching containers...")
 
 	client, err := docker.NewClient()
@@ -44,20 +53,37 @@ func runContainers(cmd *cobra.Command, args []string) error {
 	}
 	defer client.Close()
 
-	ctx := context.Background()
+	ctx := cmd.Context()
 	showAll, _ := cmd.Flags().GetBool("all")
 	wide, _ := cmd.Flags().GetBool("wide")
 	showSize, _ := cmd.Flags().GetBool("size")
+	filter, _ := cmd.Flags().GetString("filter")
 
-	containers, err := client.ListContainers(ctx, showAll)
+	containers, err := run.Containers(ctx, client, run.ContainerOptions{
+		All:    showAll,
+		Filter: filter,
+		GPUs:   (wide || output.IsStructured()) && !output.IsQuiet(),
+	})
 	if err != nil {
 		output.SpinnerError("Failed to list containers")
-		return fmt.Errorf("failed to list containers: %w", err)
+		return err
 	}
 
 	output.SpinnerSuccess(fmt.Sprintf("Found %d containers", len(containers)))
 	output.Newline()
 
+	if output.IsQuiet() {
+		ids := make([]string, len(containers))
+		for i, container := range containers {
+			ids[i] = truncateID(container.ID)
+		}
+		return output.PrintIDs(ids)
+	}
+
+	if output.IsStructured() {
+		return output.Render(containers)
+	}
+
 	if len(containers) == 0 {
 		output.Info("No containers found")
 		return nil
@@ -66,7 +92,7 @@ func runContainers(cmd *cobra.Command, args []string) error {
 	// Build table
 	headers := []string{"Container ID", "Image", "Status", "Ports", "Name"}
 	if wide {
-		headers = append(headers, "Command", "Created")
+		headers = append(headers, "Command", "Created", "GPUs")
 	}
 	if showSize {
 		headers = append(headers, "Size")
@@ -95,14 +121,14 @@ func runContainers(cmd *cobra.Command, args []string) error {
 
 		row := []string{
 			truncateID(container.ID),
-			truncateImage(container.Image),
+			output.Truncate(container.Image, 35),
 			status,
 			ports,
 			strings.TrimPrefix(container.Name, "/"),
 		}
 
 		if wide {
-			row = append(row, truncate(container.Command, 30), container.Created)
+			row = append(row, output.Truncate(container.Command, 30), container.Created, container.GPU.String())
 		}
 		if showSize {
 			row = append(row, container.Size)
@@ -127,19 +153,12 @@ func runContainers(cmd *cobra.Command, args []string) error {
 }
 
 func truncateID(id string) string {
-	if len(id) > 12 {
+	if len(id) > 12 && !output.NoTrunc() {
 		return id[:12]
 	}
 	return id
 }
 
-func truncateImage(image string) string {
-	if len(image) > 35 {
-		return image[:32] + "..."
-	}
-	return image
-}
-
 func formatPorts(ports []docker.PortMapping) string {
 	if len(ports) == 0 {
 		return "-"
@@ -154,11 +173,7 @@ func formatPorts(ports []docker.PortMapping) string {
 		}
 	}
 
-	result := strings.Join(mappings, ", ")
-	if len(result) > 30 {
-		return result[:27] + "..."
-	}
-	return result
+	return output.Truncate(strings.Join(mappings, ", "), 30)
 }
 
 func getContainerRowColors(container docker.ContainerInfo, wide, showSize bool) []tablewriter.Colors {
@@ -193,6 +208,7 @@ func getContainerRowColors(container docker.ContainerInfo, wide, showSize bool)
 		colors = append(colors,
 			tablewriter.Colors{tablewriter.FgHiBlackColor}, // Command
 			tablewriter.Colors{tablewriter.FgHiBlackColor}, // Created
+			tablewriter.Colors{tablewriter.FgGreenColor},   // GPUs
 		)
 	}
 	if showSize {
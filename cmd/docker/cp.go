@@ -0,0 +1,99 @@
+package docker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newCpCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cp <container:path> <local-path> | <local-path> <container:path>",
+		Short: "Copy files between a container and the local filesystem",
+		Long: `Copy files or directories between a container and the local filesystem,
+with progress. Directories are copied recursively.
+
+As with docker cp, if the destination is an existing directory the source
+is copied into it; otherwise it is copied to the destination path.
+
+Examples:
+  devops-toolkit docker cp web:/var/log/nginx ./nginx-logs
+  devops-toolkit docker cp web:/etc/nginx/nginx.conf .
+  devops-toolkit docker cp ./debug.sh web:/tmp/`,
+		Args: cobra.ExactArgs(2),
+		RunE: runCp,
+	}
+
+	return cmd
+}
+
+func runCp(cmd *cobra.Command, args []string) error {
+	srcContainer, srcPath := splitContainerPath(args[0])
+	dstContainer, dstPath := splitContainerPath(args[1])
+
+	switch {
+	case srcContainer != "" && dstContainer != "":
+		return fmt.Errorf("copying between containers is not supported")
+	case srcContainer == "" && dstContainer == "":
+		return fmt.Errorf("one of source or destination must be a container path (container:path)")
+	}
+
+	client, err := docker.NewClient()
+	if err != nil {
+		output.Error("Failed to connect to Docker")
+		return fmt.Errorf("failed to create docker client: %w", err)
+	}
+	defer client.Close()
+
+	ctx := cmd.Context()
+	output.StartSpinner(fmt.Sprintf("Copying %s to %s...", args[0], args[1]))
+	progress := func(copied, total int64) {
+		msg := fmt.Sprintf("Copying %s to %s  %s", args[0], args[1], format.Size(copied))
+		if total > 0 {
+			msg = fmt.Sprintf("Copying %s to %s  %s %s / %s", args[0], args[1],
+				output.ProgressBar(int(copied/1024), int(total/1024), 20), format.Size(copied), format.Size(total))
+		}
+		output.UpdateSpinner(msg)
+	}
+
+	var stats docker.CopyStats
+	if srcContainer != "" {
+		stats, err = client.CopyFromContainer(ctx, srcContainer, srcPath, dstPath, progress)
+	} else {
+		stats, err = client.CopyToContainer(ctx, dstContainer, srcPath, dstPath, progress)
+	}
+	if err != nil {
+		output.SpinnerError("Copy failed")
+		return err
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Copied %s to %s", args[0], args[1]))
+
+	if output.IsStructured() {
+		return output.Render(stats)
+	}
+
+	output.Printf("  %s\n", output.KeyValue("Files", fmt.Sprintf("%d", stats.Files)))
+	if stats.Dirs > 0 {
+		output.Printf("  %s\n", output.KeyValue("Directories", fmt.Sprintf("%d", stats.Dirs)))
+	}
+	output.Printf("  %s\n", output.KeyValue("Size", format.Size(stats.Bytes)))
+	return nil
+}
+
+// splitContainerPath splits "container:path"; local paths return an empty container
+func splitContainerPath(arg string) (string, string) {
+	// Local paths that contain a colon must start with / or . to be unambiguous
+	if strings.HasPrefix(arg, "/") || strings.HasPrefix(arg, ".") {
+		return "", arg
+	}
+	container, path, ok := strings.Cut(arg, ":")
+	if !ok {
+		return "", arg
+	}
+	return container, path
+}
@@ -20,9 +20,20 @@ including container stats, image analysis, and cleanup tools.`,
 	cmd.AddCommand(newContainersCmd())
 	cmd.AddCommand(newImagesCmd())
 	cmd.AddCommand(newStatsCmd())
+	cmd.AddCommand(newFlappingCmd())
 	cmd.AddCommand(newCleanCmd())
 	cmd.AddCommand(newInspectCmd())
+	cmd.AddCommand(newAuditCmd())
+	cmd.AddCommand(newDriftCmd())
+	cmd.AddCommand(newInspectDiffCmd())
 	cmd.AddCommand(newLogsCmd())
+	cmd.AddCommand(newLogUsageCmd())
+	cmd.AddCommand(newPullCmd())
+	cmd.AddCommand(newPromoteCmd())
+	cmd.AddCommand(newCpCmd())
+	cmd.AddCommand(newVolumeCmd())
+	cmd.AddCommand(newHostCmd())
+	cmd.AddCommand(newComposeCmd())
 
 	// Persistent flags
 	cmd.PersistentFlags().StringP("host", "H", "", "Docker host to connect to")
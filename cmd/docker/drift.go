@@ -0,0 +1,131 @@
+package docker
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newDriftCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drift <container>",
+		Short: "Show files changed in a container since it started from its image",
+		Long: `List the files a container has added, changed or deleted relative to its
+image, as docker diff does, and flag changes that may indicate compromise:
+
+  • binaries added or modified in /bin, /sbin, /usr/bin, /usr/local/bin, ...
+  • modified shared libraries (.so files under /lib and /usr/lib)
+  • new or modified setuid and setgid files
+
+Containers should be immutable; a running service rarely has a reason to
+change its own executables. The same check runs as rule DOCKER-SEC-009 in
+docker audit and compliance check docker.
+
+Examples:
+  devops-toolkit docker drift web
+  devops-toolkit docker drift web --indicators-only
+  devops-toolkit docker drift web --output json`,
+		Args:              cobra.ExactArgs(1),
+		RunE:              runDrift,
+		ValidArgsFunction: completion.ContainerCompletion,
+	}
+
+	cmd.Flags().Bool("indicators-only", false, "Only list changes flagged as potential compromise indicators")
+
+	return cmd
+}
+
+func runDrift(cmd *cobra.Command, args []string) error {
+	indicatorsOnly, _ := cmd.Flags().GetBool("indicators-only")
+
+	output.StartSpinner(fmt.Sprintf("Diffing %s against its image...", args[0]))
+
+	client, err := docker.NewClient()
+	if err != nil {
+		output.SpinnerError("Failed to connect to Docker")
+		return fmt.Errorf("failed to create docker client: %w", err)
+	}
+	defer client.Close()
+
+	report, err := client.Drift(cmd.Context(), args[0])
+	if err != nil {
+		output.SpinnerError("Drift analysis failed")
+		return err
+	}
+
+	if indicatorsOnly {
+		var flagged []docker.FileChange
+		for _, ch := range report.Changes {
+			if ch.Indicator != "" {
+				flagged = append(flagged, ch)
+			}
+		}
+		report.Changes = flagged
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Found %d changes in %s", report.Added+report.Changed+report.Deleted, report.Container))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(report)
+	}
+
+	output.Print(output.Section("Container"))
+	output.Printf("  %s\n", output.KeyValue("Name", report.Container))
+	output.Printf("  %s\n", output.KeyValue("ID", truncateID(report.ID)))
+	output.Printf("  %s\n", output.KeyValue("Image", report.Image))
+	output.Printf("  %s\n", output.KeyValue("Changes", fmt.Sprintf("%d added, %d changed, %d deleted", report.Added, report.Changed, report.Deleted)))
+	output.Newline()
+
+	if len(report.Changes) > 0 {
+		// Indicators first, then by path
+		changes := report.Changes
+		sort.SliceStable(changes, func(i, j int) bool {
+			return changes[i].Indicator != "" && changes[j].Indicator == ""
+		})
+
+		table := output.NewTable(output.TableConfig{
+			Title:      "Filesystem Changes",
+			Headers:    []string{"Kind", "Path", "Mode", "Indicator"},
+			ShowBorder: true,
+		})
+		for _, ch := range changes {
+			kindColor := tablewriter.FgYellowColor
+			switch ch.Kind {
+			case docker.DriftAdded:
+				kindColor = tablewriter.FgGreenColor
+			case docker.DriftDeleted:
+				kindColor = tablewriter.FgRedColor
+			}
+			table.AddColoredRow(
+				[]string{ch.Kind, ch.Path, ch.Mode, ch.Indicator},
+				[]tablewriter.Colors{
+					{kindColor},
+					{tablewriter.FgWhiteColor},
+					{tablewriter.FgHiBlackColor},
+					{tablewriter.Bold, tablewriter.FgRedColor},
+				},
+			)
+		}
+		table.Render()
+		output.Newline()
+	}
+
+	if report.Unchecked > 0 {
+		output.Warning(fmt.Sprintf("%d paths were not checked for setuid bits (too many changes)", report.Unchecked))
+	}
+	switch {
+	case report.Indicators > 0:
+		output.Errorf("%d changes look like potential compromise indicators; compare against a fresh container from %s", report.Indicators, report.Image)
+	case report.Added+report.Changed+report.Deleted == 0:
+		output.Success("No changes: the container filesystem matches its image")
+	default:
+		output.Success("No modified binaries or new setuid files")
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package docker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newFlappingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "flapping",
+		Short: "Find containers stuck in restart loops",
+		Long: `Identify containers that keep exiting and being restarted.
+
+A container is flapping when it is currently restarting, died at least
+--min-restarts times within --window, or has a restart count of at least
+--min-restarts and was last started within --window.
+
+For each container the exit codes seen in the window, whether it was
+OOM-killed and its last stderr lines are shown.
+
+Die events come from the daemon's in-memory event history, which is
+lost when the daemon restarts.
+
+Examples:
+  devops-toolkit docker flapping
+  devops-toolkit docker flapping --window 6h --min-restarts 5
+  devops-toolkit docker flapping --output json`,
+		RunE: runFlapping,
+	}
+
+	cmd.Flags().Duration("window", time.Hour, "Count restarts within this duration")
+	cmd.Flags().Int("min-restarts", 3, "Restarts within the window that count as flapping")
+	cmd.Flags().Int("stderr-lines", 5, "Trailing stderr lines to show per container (0 disables)")
+
+	return cmd
+}
+
+func runFlapping(cmd *cobra.Command, args []string) error {
+	window, _ := cmd.Flags().GetDuration("window")
+	minRestarts, _ := cmd.Flags().GetInt("min-restarts")
+	stderrLines, _ := cmd.Flags().GetInt("stderr-lines")
+
+	output.StartSpinner("Looking for restart loops...")
+
+	client, err := docker.NewClient()
+	if err != nil {
+		output.SpinnerError("Failed to connect to Docker")
+		return fmt.Errorf("failed to create docker client: %w", err)
+	}
+	defer client.Close()
+
+	flapping, err := client.FindFlapping(cmd.Context(), docker.FlappingOptions{
+		Window:      window,
+		MinRestarts: minRestarts,
+		StderrLines: stderrLines,
+	})
+	if err != nil {
+		output.SpinnerError("Failed to analyze restarts")
+		return err
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Found %d flapping containers", len(flapping)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(flapping)
+	}
+
+	if len(flapping) == 0 {
+		output.Success(fmt.Sprintf("No containers restarted %d or more times in the last %s", minRestarts, window))
+		return nil
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      fmt.Sprintf("Flapping Containers (last %s)", window),
+		Headers:    []string{"Container", "State", "Restarts", "Dies", "Exit Codes", "Last Exit", "Policy", "Last Start"},
+		ShowBorder: true,
+	})
+
+	for _, fc := range flapping {
+		stateColor := tablewriter.Colors{tablewriter.FgYellowColor}
+		if fc.State == "restarting" || fc.State == "exited" {
+			stateColor = tablewriter.Colors{tablewriter.Bold, tablewriter.FgRedColor}
+		}
+
+		codes := make([]string, len(fc.ExitCodes))
+		for i, code := range fc.ExitCodes {
+			codes[i] = fmt.Sprintf("%d", code)
+		}
+
+		table.AddColoredRow(
+			[]string{
+				output.Truncate(fc.Name, 30),
+				fc.State,
+				fmt.Sprintf("%d", fc.RestartCount),
+				fmt.Sprintf("%d", fc.Dies),
+				valueOr(strings.Join(codes, ","), "-"),
+				exitCodeHint(fc.LastExitCode, fc.OOMKilled),
+				valueOr(fc.RestartPolicy, "no"),
+				format.Ago(fc.StartedAt),
+			},
+			[]tablewriter.Colors{
+				{tablewriter.FgCyanColor},
+				stateColor,
+				{tablewriter.Bold},
+				{tablewriter.Bold, tablewriter.FgRedColor},
+				{tablewriter.FgYellowColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgHiBlackColor},
+			},
+		)
+	}
+
+	table.Render()
+
+	for _, fc := range flapping {
+		if len(fc.LastStderr) == 0 && fc.Error == "" {
+			continue
+		}
+		output.Newline()
+		output.Print(output.Section(fc.Name))
+		if fc.Error != "" {
+			output.Printf("  %s %s\n", output.ErrorStyle.Render(output.IconError), fc.Error)
+		}
+		for _, line := range fc.LastStderr {
+			output.Printf("  %s\n", output.MutedStyle.Render(output.Truncate(line, 120)))
+		}
+	}
+
+	output.Newline()
+	output.Muted("  Inspect full logs with: devops-toolkit docker logs <container> --level error")
+	output.Newline()
+	return nil
+}
+
+// exitCodeHint annotates common exit codes with their usual cause
+func exitCodeHint(code int, oomKilled bool) string {
+	switch {
+	case oomKilled:
+		return fmt.Sprintf("%d (OOM killed)", code)
+	case code == 0:
+		return "0"
+	case code == 125:
+		return "125 (docker run error)"
+	case code == 126:
+		return "126 (not executable)"
+	case code == 127:
+		return "127 (command not found)"
+	case code == 137:
+		return "137 (SIGKILL)"
+	case code == 139:
+		return "139 (segfault)"
+	case code == 143:
+		return "143 (SIGTERM)"
+	default:
+		return fmt.Sprintf("%d (app error)", code)
+	}
+}
+
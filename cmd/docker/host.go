@@ -0,0 +1,147 @@
+package docker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newHostCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "host",
+		Short: "Show Docker daemon and host resource overview",
+		Long: `Summarize the Docker daemon and host in a health-style table.
+
+Shows:
+  • Daemon version, OS and kernel
+  • Container and image counts
+  • Storage driver, cgroup driver and default runtime
+  • Live-restore (containers keep running across daemon restarts)
+  • Host CPU and memory pressure from running containers
+  • Warnings reported by the daemon
+
+CPU and memory pressure are the combined usage of running containers
+relative to the host's CPUs and memory, sampled once.
+
+Examples:
+  devops-toolkit docker host
+  devops-toolkit docker host --output json`,
+		RunE: runHost,
+	}
+
+	return cmd
+}
+
+func runHost(cmd *cobra.Command, args []string) error {
+	output.StartSpinner("Reading Docker host info...")
+
+	client, err := docker.NewClient()
+	if err != nil {
+		output.SpinnerError("Failed to connect to Docker")
+		return fmt.Errorf("failed to create docker client: %w", err)
+	}
+	defer client.Close()
+
+	host, err := client.GetHostInfo(cmd.Context())
+	if err != nil {
+		output.SpinnerError("Failed to read Docker host info")
+		return err
+	}
+
+	output.SpinnerSuccess("Connected to Docker daemon")
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(host)
+	}
+
+	output.Header(fmt.Sprintf("Docker Host: %s", host.Host))
+	output.Printf("  %s\n", output.KeyValue("OS", host.OS))
+	output.Printf("  %s\n", output.KeyValue("Kernel", host.Kernel))
+	output.Printf("  %s\n", output.KeyValue("Architecture", host.Architecture))
+	output.Printf("  %s\n", output.KeyValue("Resources", fmt.Sprintf("%d CPUs, %s memory", host.NCPU, format.Size(host.MemTotal))))
+	output.Newline()
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Docker Host Summary",
+		Headers:    []string{"Component", "Status", "Details"},
+		ShowBorder: true,
+	})
+
+	table.AddColoredRow(output.StatusRow("Daemon", fmt.Sprintf("%s Running", output.IconSuccess),
+		fmt.Sprintf("Docker %s, API %s", host.ServerVersion, host.APIVersion)))
+
+	row, colors := output.StatusRow("Containers", fmt.Sprintf("%s %d running", output.IconSuccess, host.Running),
+		fmt.Sprintf("Total: %d, Running: %d, Paused: %d, Stopped: %d", host.Containers, host.Running, host.Paused, host.Stopped))
+	if host.Paused > 0 {
+		row[1] = fmt.Sprintf("%s %d paused", output.IconWarning, host.Paused)
+		colors[1] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgYellowColor}
+	}
+	table.AddColoredRow(row, colors)
+
+	table.AddColoredRow(output.StatusRow("Images", fmt.Sprintf("%s OK", output.IconSuccess), fmt.Sprintf("%d images", host.Images)))
+
+	table.AddColoredRow(output.StatusRow("Storage", fmt.Sprintf("%s OK", output.IconSuccess),
+		fmt.Sprintf("%s, root %s", host.StorageDriver, host.RootDir)))
+
+	cgroup := host.CgroupDriver
+	if host.CgroupVersion != "" {
+		cgroup += ", v" + host.CgroupVersion
+	}
+	table.AddColoredRow(output.StatusRow("Cgroups", fmt.Sprintf("%s OK", output.IconSuccess), cgroup))
+
+	table.AddColoredRow(output.StatusRow("Runtime", fmt.Sprintf("%s OK", output.IconSuccess),
+		fmt.Sprintf("default %s, logging %s", host.DefaultRuntime, host.LoggingDriver)))
+
+	if host.LiveRestore {
+		table.AddColoredRow(output.StatusRow("Live Restore", fmt.Sprintf("%s OK", output.IconSuccess), "Enabled; containers survive daemon restarts"))
+	} else {
+		table.AddColoredRow(output.StatusRow("Live Restore", fmt.Sprintf("%s Warn", output.IconWarning), "Disabled; containers stop when the daemon restarts"))
+	}
+
+	table.AddColoredRow(pressureRow("CPU Pressure", host.CPUPercent, fmt.Sprintf("Containers using %.1f%% of %d CPUs", host.CPUPercent, host.NCPU)))
+	table.AddColoredRow(pressureRow("Memory Pressure", host.MemoryPercent,
+		fmt.Sprintf("%s of %s", format.Size(host.MemoryUsage), format.Size(host.MemTotal))))
+
+	if len(host.Warnings) > 0 {
+		table.AddColoredRow(output.StatusRow("Daemon Warnings", fmt.Sprintf("%s %d warnings", output.IconWarning, len(host.Warnings)),
+			"See below"))
+	}
+
+	table.Render()
+
+	if len(host.Warnings) > 0 {
+		output.Newline()
+		output.Print(output.Section("Daemon Warnings"))
+		for _, warning := range host.Warnings {
+			output.Printf("  %s %s\n", output.WarningStyle.Render(output.IconWarning), strings.TrimPrefix(warning, "WARNING: "))
+		}
+	}
+
+	output.Newline()
+	return nil
+}
+
+// pressureRow renders a utilization row colored by how close it is to capacity
+func pressureRow(component string, percent float64, details string) ([]string, []tablewriter.Colors) {
+	icon := output.IconSuccess
+	switch {
+	case percent > 90:
+		icon = output.IconError
+	case percent > 70:
+		icon = output.IconWarning
+	}
+
+	row := []string{component, fmt.Sprintf("%s %.0f%%", icon, percent), details}
+	colors := []tablewriter.Colors{
+		{tablewriter.FgHiWhiteColor},
+		{tablewriter.Bold, getResourceColorByPercent(percent)},
+		{tablewriter.FgHiBlackColor},
+	}
+	return row, colors
+}
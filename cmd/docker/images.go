@@ -1,13 +1,14 @@
 package docker
 
 import (
-	"context"
 	"fmt"
 	"sort"
 
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/resultcache"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
@@ -30,6 +31,8 @@ Features:
 	cmd.Flags().Bool("dangling", false, "Show only dangling images")
 	cmd.Flags().StringP("sort", "s", "size", "Sort by: name, size, created")
 	cmd.Flags().Bool("digest", false, "Show image digests")
+	cmd.Flags().Duration("cache", 0, "Serve the last result up to this old, marked STALE, when the daemon is unreachable")
+	cmd.Flags().BoolP("quiet", "q", false, "Only print image IDs")
 
 	// Register flag completions
 	_ = cmd.RegisterFlagCompletionFunc("sort", completion.ImageSortCompletion)
@@ -38,6 +41,9 @@ Features:
 }
 
 func runImages(cmd *cobra.Command, args []string) error {
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		output.SetQuiet()
+	}
 	output.StartSpinner("Fetching images...")
 
 	client, err := docker.NewClient()
@@ -47,22 +53,34 @@ func runImages(cmd *cobra.Command, args []string) error {
 	}
 	defer client.Close()
 
-	ctx := context.Background()
+	ctx := cmd.Context()
 	showAll, _ := cmd.Flags().GetBool("all")
 	danglingOnly, _ := cmd.Flags().GetBool("dangling")
 	sortBy, _ := cmd.Flags().GetString("sort")
 	showDigest, _ := cmd.Flags().GetBool("digest")
+	cacheAge, _ := cmd.Flags().GetDuration("cache")
 
+	cacheKey := resultcache.Key("docker images", client.Host(), fmt.Sprint(showAll), fmt.Sprint(danglingOnly))
 	images, err := client.ListImages(ctx, showAll, danglingOnly)
+	stale := ""
 	if err != nil {
-		output.SpinnerError("Failed to list images")
-		return fmt.Errorf("failed to list images: %w", err)
+		if stale = resultcache.Fallback(cacheKey, cacheAge, &images, err); stale == "" {
+			output.SpinnerError("Failed to list images")
+			return fmt.Errorf("failed to list images: %w", err)
+		}
+	} else if cacheAge > 0 {
+		_ = resultcache.Save(cacheKey, images)
 	}
 
-	output.SpinnerSuccess(fmt.Sprintf("Found %d images", len(images)))
+	if stale != "" {
+		output.StopSpinner()
+		output.Warning(stale)
+	} else {
+		output.SpinnerSuccess(fmt.Sprintf("Found %d images", len(images)))
+	}
 	output.Newline()
 
-	if len(images) == 0 {
+	if len(images) == 0 && !output.IsStructured() {
 		output.Info("No images found")
 		return nil
 	}
@@ -70,6 +88,18 @@ func runImages(cmd *cobra.Command, args []string) error {
 	// Sort images
 	sortImages(images, sortBy)
 
+	if output.IsQuiet() {
+		ids := make([]string, len(images))
+		for i, img := range images {
+			ids[i] = truncateID(img.ID)
+		}
+		return output.PrintIDs(ids)
+	}
+
+	if output.IsStructured() {
+		return output.RenderStale(images, stale != "")
+	}
+
 	// Calculate total size
 	var totalSize int64
 	var danglingCount int
@@ -106,15 +136,11 @@ func runImages(cmd *cobra.Command, args []string) error {
 			tag,
 			truncateID(img.ID),
 			img.Created,
-			formatSize(img.Size),
+			format.Size(img.Size),
 		}
 
 		if showDigest {
-			digest := img.Digest
-			if len(digest) > 20 {
-				digest = digest[:17] + "..."
-			}
-			row = append(row, digest)
+			row = append(row, output.Truncate(img.Digest, 20))
 		}
 
 		colors := getImageRowColors(img, showDigest)
@@ -127,7 +153,7 @@ func runImages(cmd *cobra.Command, args []string) error {
 	output.Newline()
 	output.Print(output.Section("Image Summary"))
 	output.Printf("  Total Images: %d\n", len(images))
-	output.Printf("  Total Size: %s\n", formatSize(totalSize))
+	output.Printf("  Total Size: %s\n", format.Size(totalSize))
 	if danglingCount > 0 {
 		output.Printf("  %s Dangling: %d (reclaimable space)\n",
 			output.WarningStyle.Render(output.IconWarning), danglingCount)
@@ -162,7 +188,7 @@ func runImages(cmd *cobra.Command, args []string) error {
 			break
 		}
 		bar := output.ProgressBar(int(float64(rs.size)/float64(totalSize)*100), 100, 20)
-		output.Printf("    %s: %s %s\n", rs.name, formatSize(rs.size), bar)
+		output.Printf("    %s: %s %s\n", rs.name, format.Size(rs.size), bar)
 	}
 
 	output.Newline()
@@ -182,25 +208,6 @@ func sortImages(images []docker.ImageInfo, sortBy string) {
 	})
 }
 
-func formatSize(bytes int64) string {
-	const (
-		KB = 1024
-		MB = KB * 1024
-		GB = MB * 1024
-	)
-
-	switch {
-	case bytes >= GB:
-		return fmt.Sprintf("%.2f GB", float64(bytes)/float64(GB))
-	case bytes >= MB:
-		return fmt.Sprintf("%.1f MB", float64(bytes)/float64(MB))
-	case bytes >= KB:
-		return fmt.Sprintf("%.1f KB", float64(bytes)/float64(KB))
-	default:
-		return fmt.Sprintf("%d B", bytes)
-	}
-}
-
 func getImageRowColors(img docker.ImageInfo, showDigest bool) []tablewriter.Colors {
 	var repoColor, tagColor int
 
@@ -1,12 +1,12 @@
 package docker
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -48,7 +48,7 @@ func runInspect(cmd *cobra.Command, args []string) error {
 	}
 	defer client.Close()
 
-	ctx := context.Background()
+	ctx := cmd.Context()
 	showEnv, _ := cmd.Flags().GetBool("env")
 	showMounts, _ := cmd.Flags().GetBool("mounts")
 	showNetwork, _ := cmd.Flags().GetBool("network")
@@ -69,6 +69,11 @@ func runInspect(cmd *cobra.Command, args []string) error {
 	output.SpinnerSuccess("Container found")
 	output.Newline()
 
+	if output.IsStructured() {
+		info.Env = maskSensitiveEnv(info.Env)
+		return output.Render(info)
+	}
+
 	// Basic info
 	output.Header(fmt.Sprintf("Container: %s", info.Name))
 
@@ -103,6 +108,21 @@ func runInspect(cmd *cobra.Command, args []string) error {
 		output.Printf("  Entrypoint: %s\n", info.Entrypoint)
 	}
 
+	// Limits
+	output.Newline()
+	output.Print(output.Section("Resource Limits"))
+	output.Printf("  %s\n", output.KeyValue("Memory", formatLimit(info.Limits.Memory, format.Size)))
+	output.Printf("  %s\n", output.KeyValue("CPUs", formatLimit(info.Limits.NanoCPUs, formatCPUs)))
+	if info.Limits.PidsLimit > 0 {
+		output.Printf("  %s\n", output.KeyValue("PIDs", fmt.Sprintf("%d", info.Limits.PidsLimit)))
+	}
+	if gpu := info.Limits.GPU; gpu != nil {
+		output.Printf("  %s\n", output.KeyValue("GPUs", fmt.Sprintf("%s (%s via %s)", gpu.String(), gpu.Driver, gpu.Source)))
+	}
+	if info.Limits.RestartPolicy != "" {
+		output.Printf("  %s\n", output.KeyValue("Restart Policy", info.Limits.RestartPolicy))
+	}
+
 	// Ports
 	if len(info.Ports) > 0 {
 		output.Newline()
@@ -178,7 +198,7 @@ func runInspect(cmd *cobra.Command, args []string) error {
 			}
 			output.Printf("  %s: %s\n",
 				output.MutedStyle.Render(key),
-				truncate(value, 50))
+				output.Truncate(value, 50))
 			count++
 		}
 	}
@@ -206,9 +226,26 @@ func isSensitiveEnv(name string) bool {
 	return false
 }
 
-func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
+// maskSensitiveEnv returns env with the values of sensitive variables masked
+func maskSensitiveEnv(env []string) []string {
+	masked := make([]string, len(env))
+	for i, e := range env {
+		masked[i] = e
+		if name, _, ok := strings.Cut(e, "="); ok && isSensitiveEnv(name) {
+			masked[i] = name + "=********"
+		}
+	}
+	return masked
+}
+
+// formatLimit formats a resource limit, where zero means unlimited
+func formatLimit(value int64, format func(int64) string) string {
+	if value <= 0 {
+		return "unlimited"
 	}
-	return s[:maxLen-3] + "..."
+	return format(value)
+}
+
+func formatCPUs(nanoCPUs int64) string {
+	return fmt.Sprintf("%.2f", float64(nanoCPUs)/1e9)
 }
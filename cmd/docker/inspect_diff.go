@@ -0,0 +1,234 @@
+package docker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newInspectDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect-diff <container-a> <container-b>",
+		Short: "Compare the configuration of two containers",
+		Long: `Compare two containers' image, environment, mounts, ports and resource
+limits, showing only the settings that differ.
+
+Useful when the same service works in one container but not another.
+For a full snapshot of a single container use inspect --output json.
+
+Values of sensitive environment variables (passwords, tokens, keys) are
+masked; a differing secret is reported without revealing either value.
+
+Examples:
+  devops-toolkit docker inspect-diff web-1 web-2
+  devops-toolkit docker inspect-diff web-1 web-2 --output json`,
+		Args:              cobra.ExactArgs(2),
+		RunE:              runInspectDiff,
+		ValidArgsFunction: completion.ContainerCompletion,
+	}
+
+	return cmd
+}
+
+// inspectDifference is one setting that differs between two containers
+type inspectDifference struct {
+	Section string `json:"section"`
+	Key     string `json:"key"`
+	A       string `json:"a"`
+	B       string `json:"b"`
+}
+
+func runInspectDiff(cmd *cobra.Command, args []string) error {
+	output.StartSpinner(fmt.Sprintf("Inspecting %s and %s...", args[0], args[1]))
+
+	client, err := docker.NewClient()
+	if err != nil {
+		output.SpinnerError("Failed to connect to Docker")
+		return fmt.Errorf("failed to create docker client: %w", err)
+	}
+	defer client.Close()
+
+	ctx := cmd.Context()
+	a, err := client.InspectContainer(ctx, args[0])
+	if err != nil {
+		output.SpinnerError("Failed to inspect container")
+		return fmt.Errorf("failed to inspect container %s: %w", args[0], err)
+	}
+	b, err := client.InspectContainer(ctx, args[1])
+	if err != nil {
+		output.SpinnerError("Failed to inspect container")
+		return fmt.Errorf("failed to inspect container %s: %w", args[1], err)
+	}
+
+	diffs := diffContainers(a, b)
+	output.SpinnerSuccess(fmt.Sprintf("Found %d differences", len(diffs)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(diffs)
+	}
+
+	if len(diffs) == 0 {
+		output.Success(fmt.Sprintf("%s and %s have identical image, env, mounts, ports and limits", a.Name, b.Name))
+		return nil
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      fmt.Sprintf("%s vs %s", a.Name, b.Name),
+		Headers:    []string{"Section", "Key", a.Name, b.Name},
+		ShowBorder: true,
+	})
+
+	for _, d := range diffs {
+		aColor := tablewriter.Colors{tablewriter.FgYellowColor}
+		bColor := tablewriter.Colors{tablewriter.FgYellowColor}
+		if d.A == "-" {
+			aColor = tablewriter.Colors{tablewriter.FgHiBlackColor}
+			bColor = tablewriter.Colors{tablewriter.FgGreenColor}
+		}
+		if d.B == "-" {
+			aColor = tablewriter.Colors{tablewriter.FgRedColor}
+			bColor = tablewriter.Colors{tablewriter.FgHiBlackColor}
+		}
+		table.AddColoredRow(
+			[]string{d.Section, d.Key, output.Truncate(d.A, 40), output.Truncate(d.B, 40)},
+			[]tablewriter.Colors{
+				{tablewriter.FgCyanColor},
+				{tablewriter.FgWhiteColor},
+				aColor,
+				bColor,
+			},
+		)
+	}
+
+	table.Render()
+	output.Newline()
+	return nil
+}
+
+// diffContainers compares the settings that most often explain behavior differences
+func diffContainers(a, b *docker.ContainerDetails) []inspectDifference {
+	var diffs []inspectDifference
+
+	diffs = append(diffs, diffValues("Image", map[string]string{
+		"image":      a.Image,
+		"command":    a.Command,
+		"entrypoint": a.Entrypoint,
+		"platform":   a.Platform,
+	}, map[string]string{
+		"image":      b.Image,
+		"command":    b.Command,
+		"entrypoint": b.Entrypoint,
+		"platform":   b.Platform,
+	})...)
+
+	envA, envB := envMap(a.Env), envMap(b.Env)
+	for _, d := range diffValues("Env", envA, envB) {
+		if isSensitiveEnv(d.Key) {
+			d.A, d.B = maskValue(d.A), maskValue(d.B)
+		}
+		diffs = append(diffs, d)
+	}
+
+	diffs = append(diffs, diffValues("Mounts", mountMap(a.Mounts), mountMap(b.Mounts))...)
+	diffs = append(diffs, diffValues("Ports", portMap(a.Ports), portMap(b.Ports))...)
+	diffs = append(diffs, diffValues("Limits", limitMap(a.Limits), limitMap(b.Limits))...)
+
+	return diffs
+}
+
+// diffValues returns keys whose values differ, using "-" for a missing key
+func diffValues(section string, a, b map[string]string) []inspectDifference {
+	keys := make(map[string]bool)
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var diffs []inspectDifference
+	for _, k := range sorted {
+		va, okA := a[k]
+		vb, okB := b[k]
+		if okA && okB && va == vb {
+			continue
+		}
+		if !okA {
+			va = "-"
+		}
+		if !okB {
+			vb = "-"
+		}
+		diffs = append(diffs, inspectDifference{Section: section, Key: k, A: va, B: vb})
+	}
+	return diffs
+}
+
+func envMap(env []string) map[string]string {
+	m := make(map[string]string, len(env))
+	for _, e := range env {
+		name, value, _ := strings.Cut(e, "=")
+		m[name] = value
+	}
+	return m
+}
+
+func mountMap(mounts []docker.MountInfo) map[string]string {
+	m := make(map[string]string, len(mounts))
+	for _, mount := range mounts {
+		rw := "rw"
+		if !mount.RW {
+			rw = "ro"
+		}
+		source := mount.Source
+		if mount.Name != "" {
+			source = mount.Name
+		}
+		m[mount.Destination] = fmt.Sprintf("%s %s (%s)", mount.Type, source, rw)
+	}
+	return m
+}
+
+func portMap(ports []docker.PortMapping) map[string]string {
+	m := make(map[string]string, len(ports))
+	for _, port := range ports {
+		published := "not published"
+		if port.PublicPort > 0 {
+			published = fmt.Sprintf("%s:%d", port.IP, port.PublicPort)
+		}
+		m[fmt.Sprintf("%d/%s", port.PrivatePort, port.Type)] = published
+	}
+	return m
+}
+
+func limitMap(limits docker.ResourceLimits) map[string]string {
+	return map[string]string{
+		"memory":             formatLimit(limits.Memory, format.Size),
+		"memory reservation": formatLimit(limits.MemoryReservation, format.Size),
+		"cpus":               formatLimit(limits.NanoCPUs, formatCPUs),
+		"cpu shares":         fmt.Sprintf("%d", limits.CPUShares),
+		"pids":               formatLimit(limits.PidsLimit, func(v int64) string { return fmt.Sprintf("%d", v) }),
+		"restart policy":     limits.RestartPolicy,
+		"gpus":               limits.GPU.String(),
+	}
+}
+
+func maskValue(value string) string {
+	if value == "-" {
+		return value
+	}
+	return "********"
+}
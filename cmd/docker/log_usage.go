@@ -0,0 +1,151 @@
+package docker
+
+import (
+	"fmt"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newLogUsageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "log-usage",
+		Short: "Show container log sizes on disk and rotation settings",
+		Long: `List containers by the size of their log files on disk, including rotated
+files, with the log driver and rotation options in effect.
+
+Containers using the json-file driver without max-size grow their logs
+without limit and are flagged. Set rotation per container with
+--log-opt max-size=10m --log-opt max-file=3, or for all new containers
+in /etc/docker/daemon.json under "log-opts".
+
+Sizes are read by a short-lived helper container that mounts the
+daemon's containers directory read-only.
+
+Examples:
+  devops-toolkit docker log-usage
+  devops-toolkit docker log-usage --output json`,
+		RunE: runLogUsage,
+	}
+
+	cmd.Flags().String("image", docker.DefaultHelperImage, "Helper image used to read log file sizes")
+
+	return cmd
+}
+
+func runLogUsage(cmd *cobra.Command, args []string) error {
+	image, _ := cmd.Flags().GetString("image")
+
+	output.StartSpinner("Measuring container logs...")
+
+	client, err := docker.NewClient()
+	if err != nil {
+		output.SpinnerError("Failed to connect to Docker")
+		return fmt.Errorf("failed to create docker client: %w", err)
+	}
+	defer client.Close()
+
+	report, err := client.GetLogUsage(cmd.Context(), image)
+	if err != nil {
+		output.SpinnerError("Failed to measure container logs")
+		return err
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Found %d containers", len(report.Containers)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(report)
+	}
+
+	if report.SizeError != "" {
+		output.Warning("Log sizes unavailable: " + report.SizeError)
+		output.Newline()
+	}
+
+	if len(report.Containers) == 0 {
+		output.Info("No containers found")
+		return nil
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Container Log Usage",
+		Headers:    []string{"Container", "State", "Driver", "Size", "Files", "Max Size", "Max Files"},
+		ShowBorder: true,
+	})
+
+	var total int64
+	var unrotated []docker.LogUsage
+	for _, usage := range report.Containers {
+		total += usage.Size
+
+		maxSize, maxFile := valueOr(usage.MaxSize, "-"), valueOr(usage.MaxFile, "-")
+		rotationColor := tablewriter.Colors{tablewriter.FgGreenColor}
+		nameColor := tablewriter.Colors{tablewriter.FgCyanColor}
+		if usage.Unrotated() {
+			unrotated = append(unrotated, usage)
+			maxSize = "none"
+			rotationColor = tablewriter.Colors{tablewriter.Bold, tablewriter.FgYellowColor}
+			nameColor = tablewriter.Colors{tablewriter.FgYellowColor}
+		}
+
+		size := "-"
+		if report.SizeError == "" {
+			size = format.Size(usage.Size)
+		}
+
+		table.AddColoredRow(
+			[]string{
+				output.Truncate(usage.Name, 30),
+				usage.State,
+				usage.Driver,
+				size,
+				fmt.Sprintf("%d", usage.Files),
+				maxSize,
+				maxFile,
+			},
+			[]tablewriter.Colors{
+				nameColor,
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.Bold},
+				{tablewriter.FgHiBlackColor},
+				rotationColor,
+				{tablewriter.FgHiBlackColor},
+			},
+		)
+	}
+
+	table.Render()
+
+	output.Newline()
+	output.Print(output.Section("Summary"))
+	if report.SizeError == "" {
+		output.Printf("  %s\n", output.KeyValue("Total Log Size", format.Size(total)))
+	}
+	if len(unrotated) > 0 {
+		var size int64
+		for _, usage := range unrotated {
+			size += usage.Size
+		}
+		output.Printf("  %s %d containers use json-file without max-size (%s of logs)\n",
+			output.WarningStyle.Render(output.IconWarning), len(unrotated), format.Size(size))
+		output.Muted(`  Set "log-opts": {"max-size": "10m", "max-file": "3"} in /etc/docker/daemon.json`)
+		output.Muted("  and recreate the containers, or pass --log-opt max-size=10m to docker run")
+	} else {
+		output.Success("All containers have log rotation configured")
+	}
+
+	output.Newline()
+	return nil
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -1,8 +1,11 @@
 package docker
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
@@ -20,21 +23,32 @@ Features:
   • Error/warning highlighting
   • JSON log parsing
   • Timestamp formatting
-  • Log level filtering`,
+  • Log level filtering
+  • Export to a file for tickets (--export)
+
+Exports always include timestamps. Use --tail 0 to export all lines.
+
+Examples:
+  devops-toolkit docker logs web --level error
+  devops-toolkit docker logs web --tail 0 --since 2h --export web.jsonl
+  devops-toolkit docker logs web --export web.log --format text`,
 		Args:              cobra.ExactArgs(1),
 		RunE:              runLogs,
 		ValidArgsFunction: completion.RunningContainerCompletion,
 	}
 
-	cmd.Flags().IntP("tail", "n", 100, "Number of lines to show")
+	cmd.Flags().IntP("tail", "n", 100, "Number of lines to show (0 for all)")
 	cmd.Flags().BoolP("follow", "f", false, "Follow log output")
 	cmd.Flags().Bool("timestamps", false, "Show timestamps")
 	cmd.Flags().String("since", "", "Show logs since timestamp (e.g. 2023-01-01T00:00:00)")
 	cmd.Flags().String("until", "", "Show logs until timestamp")
 	cmd.Flags().String("level", "", "Filter by log level (error, warn, info, debug)")
+	cmd.Flags().String("export", "", "Write logs to this file instead of the terminal")
+	cmd.Flags().String("format", "jsonl", "Export format (jsonl, text)")
 
 	// Register flag completions
 	_ = cmd.RegisterFlagCompletionFunc("level", completion.LogLevelCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("format", completion.LogExportFormatCompletion)
 
 	return cmd
 }
@@ -49,13 +63,15 @@ func runLogs(cmd *cobra.Command, args []string) error {
 	}
 	defer client.Close()
 
-	ctx := context.Background()
+	ctx := cmd.Context()
 	tail, _ := cmd.Flags().GetInt("tail")
 	follow, _ := cmd.Flags().GetBool("follow")
 	timestamps, _ := cmd.Flags().GetBool("timestamps")
 	since, _ := cmd.Flags().GetString("since")
 	until, _ := cmd.Flags().GetString("until")
 	level, _ := cmd.Flags().GetString("level")
+	exportFile, _ := cmd.Flags().GetString("export")
+	format, _ := cmd.Flags().GetString("format")
 
 	opts := docker.LogOptions{
 		Tail:       tail,
@@ -66,6 +82,14 @@ func runLogs(cmd *cobra.Command, args []string) error {
 		Level:      level,
 	}
 
+	if exportFile != "" {
+		if follow {
+			return fmt.Errorf("--follow cannot be used with --export")
+		}
+		opts.Timestamps = true
+		return exportLogs(ctx, client, containerID, opts, exportFile, format)
+	}
+
 	output.Header(fmt.Sprintf("Logs: %s", containerID))
 
 	if follow {
@@ -97,19 +121,52 @@ func printLogLine(line docker.LogLine) {
 	}
 
 	// Color based on detected level
-	var content string
-	switch line.Level {
-	case "error", "fatal", "panic":
-		content = output.ErrorStyle.Render(line.Content)
-	case "warn", "warning":
-		content = output.WarningStyle.Render(line.Content)
-	case "info":
-		content = output.InfoStyle.Render(line.Content)
-	case "debug", "trace":
-		content = output.MutedStyle.Render(line.Content)
-	default:
-		content = line.Content
+	fmt.Printf("%s%s\n", prefix, output.HighlightLog(line.Level, line.Content))
+}
+
+// exportLogs writes container logs to a file as JSON lines or plain text
+func exportLogs(ctx context.Context, client *docker.Client, containerID string, opts docker.LogOptions, file, format string) error {
+	if format != "jsonl" && format != "text" {
+		return fmt.Errorf("unsupported export format %q (use jsonl or text)", format)
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", file, err)
 	}
+	defer f.Close()
 
-	fmt.Printf("%s%s\n", prefix, content)
+	output.StartSpinner(fmt.Sprintf("Exporting logs of %s...", containerID))
+
+	w := bufio.NewWriter(f)
+	encoder := json.NewEncoder(w)
+	lines := 0
+	var writeErr error
+	err = client.StreamLogs(ctx, containerID, opts, func(line docker.LogLine) {
+		if writeErr != nil {
+			return
+		}
+		if format == "jsonl" {
+			writeErr = encoder.Encode(line)
+		} else {
+			_, writeErr = fmt.Fprintf(w, "%s %s %s\n", line.Timestamp, line.Stream, line.Content)
+		}
+		lines++
+		if lines%1000 == 0 {
+			output.UpdateSpinner(fmt.Sprintf("Exporting logs of %s... %d lines", containerID, lines))
+		}
+	})
+	if err == nil {
+		err = writeErr
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		output.SpinnerError("Export failed")
+		return fmt.Errorf("failed to export logs: %w", err)
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Exported %d lines to %s", lines, file))
+	return nil
 }
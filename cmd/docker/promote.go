@@ -0,0 +1,106 @@
+package docker
+
+import (
+	"fmt"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/audit"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newPromoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote <image> --to <registry/repo:tag>",
+		Short: "Retag and push an image to another repository",
+		Long: `Promote an image by retagging it and pushing it to a target repository,
+e.g. from a staging registry to production.
+
+The source image is used from the local image store when present, and
+pulled otherwise (--pull always pulls). Registry credentials for both
+sides are read from the Docker CLI config, including credential helpers,
+as written by docker login.
+
+With --verify the registry is queried after the push to confirm it
+serves the pushed digest for the target tag. The digest-pinned reference
+is printed for use in manifests.
+
+Examples:
+  devops-toolkit docker promote registry.staging/app:1.4.2 --to registry.prod/app:1.4.2
+  devops-toolkit docker promote app:latest --to ghcr.io/org/app:v2 --pull --verify
+  devops-toolkit docker promote app:1.4.2 --to registry.prod/app:1.4.2 --output json`,
+		Args:              cobra.ExactArgs(1),
+		RunE:              runPromote,
+		ValidArgsFunction: completion.ImageCompletion,
+	}
+
+	cmd.Flags().String("to", "", "Target image reference (registry/repo:tag)")
+	cmd.Flags().Bool("pull", false, "Always pull the source image instead of using a local copy")
+	cmd.Flags().Bool("verify", false, "Verify the registry serves the pushed digest")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+func runPromote(cmd *cobra.Command, args []string) error {
+	source := args[0]
+	target, _ := cmd.Flags().GetString("to")
+	pull, _ := cmd.Flags().GetBool("pull")
+	verify, _ := cmd.Flags().GetBool("verify")
+
+	client, err := docker.NewClient()
+	if err != nil {
+		output.Error("Failed to connect to Docker")
+		return fmt.Errorf("failed to create docker client: %w", err)
+	}
+	defer client.Close()
+
+	output.StartSpinner(fmt.Sprintf("Promoting %s to %s...", source, target))
+
+	opts := docker.PromoteOptions{Pull: pull, Verify: verify}
+	result, err := client.PromoteImage(cmd.Context(), source, target, opts, func(status string) {
+		output.UpdateSpinner(status + "...")
+	})
+	recordPromote(client, source, target, err)
+	if err != nil {
+		output.SpinnerError("Promotion failed")
+		return err
+	}
+	output.SpinnerSuccess(fmt.Sprintf("Promoted %s to %s", source, result.Target))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(result)
+	}
+
+	output.Print(output.Section("Promotion"))
+	output.Printf("  %s\n", output.KeyValue("Source", result.Source))
+	if result.Pulled {
+		output.Printf("  %s\n", output.KeyValue("Source Digest", result.SourceDigest))
+	}
+	output.Printf("  %s\n", output.KeyValue("Target", result.Target))
+	output.Printf("  %s\n", output.KeyValue("Digest", result.Digest))
+	output.Printf("  %s\n", output.KeyValue("Pinned", result.Pinned))
+	if result.Verified {
+		output.Newline()
+		output.Success("Registry serves the pushed digest")
+	}
+
+	output.Newline()
+	return nil
+}
+
+// recordPromote writes an image promotion to the audit log
+func recordPromote(client *docker.Client, source, target string, err error) {
+	entry := audit.Entry{
+		Command:   "docker promote",
+		Action:    "push",
+		Target:    client.Host(),
+		Resources: []string{source + " -> " + target},
+		Result:    audit.Result(false, 0, 1, err),
+	}
+	if auditErr := audit.Record(entry, err); auditErr != nil {
+		output.Warning(auditErr.Error())
+	}
+}
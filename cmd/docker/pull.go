@@ -0,0 +1,147 @@
+package docker
+
+import (
+	"fmt"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newPullCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pull <image>",
+		Short: "Pull an image with layer progress and digest pinning",
+		Long: `Pull an image, showing download progress per layer, and print the
+resolved content digest.
+
+Registry credentials are read from the Docker CLI config, including
+credential helpers, as written by docker login.
+
+Use --pin to print only the image reference pinned by digest
+(e.g. nginx@sha256:...), ready to paste into manifests or compose files.
+
+Examples:
+  devops-toolkit docker pull nginx:1.27
+  devops-toolkit docker pull ghcr.io/org/app:v2 --platform linux/arm64
+  devops-toolkit docker pull redis:7 --pin`,
+		Args: cobra.ExactArgs(1),
+		RunE: runPull,
+	}
+
+	cmd.Flags().Bool("pin", false, "Print only the digest-pinned image reference")
+	cmd.Flags().String("platform", "", "Pull for a specific platform (e.g. linux/amd64)")
+
+	return cmd
+}
+
+func runPull(cmd *cobra.Command, args []string) error {
+	pin, _ := cmd.Flags().GetBool("pin")
+	platform, _ := cmd.Flags().GetString("platform")
+
+	client, err := docker.NewClient()
+	if err != nil {
+		output.Error("Failed to connect to Docker")
+		return fmt.Errorf("failed to create docker client: %w", err)
+	}
+	defer client.Close()
+
+	auth, err := docker.RegistryAuth(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	output.StartSpinner(fmt.Sprintf("Pulling %s...", args[0]))
+
+	opts := docker.PullOptions{Platform: platform, RegistryAuth: auth}
+	result, err := client.PullImage(cmd.Context(), args[0], opts, func(layers []docker.PullLayer) {
+		output.UpdateSpinner(fmt.Sprintf("Pulling %s %s", args[0], pullProgress(layers)))
+	})
+	if err != nil {
+		output.SpinnerError(fmt.Sprintf("Failed to pull %s", args[0]))
+		return err
+	}
+
+	if pin {
+		output.StopSpinner()
+		if result.Pinned == "" {
+			return fmt.Errorf("no digest available for %s", result.Reference)
+		}
+		fmt.Println(result.Pinned)
+		return nil
+	}
+
+	if result.UpToDate {
+		output.SpinnerSuccess(fmt.Sprintf("%s is up to date", result.Reference))
+	} else {
+		output.SpinnerSuccess(fmt.Sprintf("Pulled %s", result.Reference))
+	}
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(result)
+	}
+
+	if len(result.Layers) > 0 {
+		table := output.NewTable(output.TableConfig{
+			Title:      "Layers",
+			Headers:    []string{"Layer", "Size", "Status"},
+			ShowBorder: true,
+		})
+		for _, layer := range result.Layers {
+			size := "-"
+			if layer.Total > 0 {
+				size = format.Size(layer.Total)
+			}
+			statusColor := tablewriter.Colors{tablewriter.FgGreenColor}
+			if layer.Status == "Already exists" {
+				statusColor = tablewriter.Colors{tablewriter.FgHiBlackColor}
+			}
+			table.AddColoredRow(
+				[]string{layer.ID, size, layer.Status},
+				[]tablewriter.Colors{
+					{tablewriter.FgCyanColor},
+					{tablewriter.FgWhiteColor},
+					statusColor,
+				},
+			)
+		}
+		table.Render()
+		output.Newline()
+	}
+
+	output.Print(output.Section("Image"))
+	output.Printf("  %s\n", output.KeyValue("Reference", result.Reference))
+	output.Printf("  %s\n", output.KeyValue("ID", truncateID(result.ID)))
+	output.Printf("  %s\n", output.KeyValue("Size", format.Size(result.Size)))
+	if result.Digest != "" {
+		output.Printf("  %s\n", output.KeyValue("Digest", result.Digest))
+		output.Printf("  %s\n", output.KeyValue("Pinned", result.Pinned))
+	} else {
+		output.Warning("No registry digest reported for this image")
+	}
+
+	output.Newline()
+	return nil
+}
+
+// pullProgress renders overall download progress across all layers
+func pullProgress(layers []docker.PullLayer) string {
+	var current, total int64
+	done := 0
+	for _, layer := range layers {
+		current += layer.Current
+		total += layer.Total
+		if layer.Done() {
+			done++
+		}
+	}
+
+	bar := output.ProgressBar(done, len(layers), 20)
+	if total > 0 {
+		bar = output.ProgressBar(int(current/1024), int(total/1024), 20)
+	}
+	return fmt.Sprintf("%s  %d/%d layers", bar, done, len(layers))
+}
@@ -1,13 +1,19 @@
 package docker
 
 import (
-	"context"
 	"fmt"
+	"os"
 
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/prometheus"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/run"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/secrets"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func newStatsCmd() *cobra.Command {
@@ -20,17 +26,37 @@ Features:
   • CPU and Memory usage with progress bars
   • Network I/O statistics
   • Block I/O statistics
-  • PIDs count`,
+  • PIDs count
+  • GPU allocation for containers started with --gpus or the nvidia runtime
+
+With --prometheus-url, P95 CPU and memory over --window are read from
+cAdvisor metrics and used for alerts instead of the instantaneous values.`,
+		Example: `  devops-toolkit docker stats
+  devops-toolkit docker stats --prometheus-url http://prometheus:9090 --window 7d`,
 		RunE: runStats,
 	}
 
-	cmd.Flags().Bool("no-stream", true, "Disable streaming stats (show once)")
-	cmd.Flags().StringP("format", "f", "table", "Output format (table, json)")
+	cmd.Flags().Bool("no-stream", true, "Show stats once")
+	_ = cmd.Flags().MarkDeprecated("no-stream", "stats are shown once by default; use --watch to refresh them")
+	cmd.Flags().StringP("format", "f", "table", "Output format (table, json, yaml); same as --output")
+	cmd.Flags().String("prometheus-url", "", "Prometheus URL for historical usage (default from PROMETHEUS_URL or prometheus.url)")
+	cmd.Flags().String("window", prometheus.DefaultWindow, "Look-back window for historical usage (Prometheus duration)")
+
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("format", completion.OutputFormatCompletion)
 
-	return cmd
+	return output.Watchable(cmd)
 }
 
 func runStats(cmd *cobra.Command, args []string) error {
+	// The local --format flag predates the global --output flag and still overrides it
+	if cmd.Flags().Changed("format") {
+		format, _ := cmd.Flags().GetString("format")
+		if err := output.SetFormat(format); err != nil {
+			return err
+		}
+	}
+
 	output.StartSpinner("Fetching container stats...")
 
 	client, err := docker.NewClient()
@@ -40,35 +66,50 @@ func runStats(cmd *cobra.Command, args []string) error {
 	}
 	defer client.Close()
 
-	ctx := context.Background()
+	ctx := cmd.Context()
 
-	// Get running containers
-	containers, err := client.ListContainers(ctx, false)
+	// Historical usage from Prometheus
+	promClient, err := getPrometheusClient(cmd)
 	if err != nil {
-		output.SpinnerError("Failed to list containers")
-		return fmt.Errorf("failed to list containers: %w", err)
+		output.SpinnerError("Invalid Prometheus configuration")
+		return err
 	}
-
-	if len(containers) == 0 {
-		output.SpinnerError("No running containers")
-		output.Info("No running containers to show stats for")
-		return nil
+	window, _ := cmd.Flags().GetString("window")
+	if promClient != nil {
+		output.UpdateSpinner(fmt.Sprintf("Fetching container stats and P95 usage over %s...", window))
 	}
 
-	// Get stats for each container
-	stats, err := client.GetContainerStats(ctx, containers)
+	report, err := run.Stats(ctx, client, run.StatsOptions{Prometheus: promClient, Window: window})
 	if err != nil {
 		output.SpinnerError("Failed to get stats")
-		return fmt.Errorf("failed to get container stats: %w", err)
+		return err
+	}
+	stats, historical, hasGPUs := report.Stats, report.Historical, report.HasGPUs
+
+	if len(stats) == 0 && !output.IsStructured() {
+		output.SpinnerError("No running containers")
+		output.Info("No running containers to show stats for")
+		return nil
 	}
 
 	output.SpinnerSuccess(fmt.Sprintf("Stats for %d containers", len(stats)))
 	output.Newline()
 
+	if output.IsStructured() {
+		return output.Render(stats)
+	}
+
 	// Build table
+	headers := []string{"Container", "CPU %", "Memory", "Mem %", "Net I/O", "Block I/O", "PIDs"}
+	if historical {
+		headers = append(headers, "CPU P95", "Mem P95")
+	}
+	if hasGPUs {
+		headers = append(headers, "GPUs")
+	}
 	table := output.NewTable(output.TableConfig{
 		Title:      "Container Statistics",
-		Headers:    []string{"Container", "CPU %", "Memory", "Mem %", "Net I/O", "Block I/O", "PIDs"},
+		Headers:    headers,
 		ShowBorder: true,
 	})
 
@@ -78,22 +119,31 @@ func runStats(cmd *cobra.Command, args []string) error {
 	for _, stat := range stats {
 		cpuPercent := fmt.Sprintf("%.1f%%", stat.CPUPercent)
 		memPercent := fmt.Sprintf("%.1f%%", stat.MemoryPercent)
-		memUsage := fmt.Sprintf("%s / %s", formatSize(stat.MemoryUsage), formatSize(stat.MemoryLimit))
-		netIO := fmt.Sprintf("%s / %s", formatSize(stat.NetInput), formatSize(stat.NetOutput))
-		blockIO := fmt.Sprintf("%s / %s", formatSize(stat.BlockInput), formatSize(stat.BlockOutput))
-
-		table.AddColoredRow(
-			[]string{
-				truncateName(stat.Name, 20),
-				cpuPercent,
-				memUsage,
-				memPercent,
-				netIO,
-				blockIO,
-				fmt.Sprintf("%d", stat.PIDs),
-			},
-			getStatsRowColors(stat),
-		)
+		memUsage := fmt.Sprintf("%s / %s", format.Size(stat.MemoryUsage), format.Size(stat.MemoryLimit))
+		netIO := fmt.Sprintf("%s / %s", format.Size(stat.NetInput), format.Size(stat.NetOutput))
+		blockIO := fmt.Sprintf("%s / %s", format.Size(stat.BlockInput), format.Size(stat.BlockOutput))
+
+		row := []string{
+			output.Truncate(stat.Name, 20),
+			cpuPercent,
+			memUsage,
+			memPercent,
+			netIO,
+			blockIO,
+			fmt.Sprintf("%d", stat.PIDs),
+		}
+		colors := getStatsRowColors(stat)
+		if historical {
+			row = append(row, fmt.Sprintf("%.1f%%", stat.CPUP95), format.Size(stat.MemoryP95))
+			colors = append(colors,
+				tablewriter.Colors{getResourceColorByPercent(stat.CPUP95)},
+				tablewriter.Colors{getResourceColorByPercent(memoryP95Percent(stat))})
+		}
+		if hasGPUs {
+			row = append(row, stat.GPU.String())
+			colors = append(colors, tablewriter.Colors{tablewriter.FgGreenColor})
+		}
+		table.AddColoredRow(row, colors)
 
 		totalCPU += stat.CPUPercent
 		totalMemPercent += stat.MemoryPercent
@@ -108,34 +158,43 @@ func runStats(cmd *cobra.Command, args []string) error {
 	output.Print(output.Section("Resource Summary"))
 	output.Printf("  Total CPU: %.1f%%\n", totalCPU)
 	output.Printf("  Total Memory: %s / %s (%.1f%%)\n",
-		formatSize(totalMemUsage),
-		formatSize(totalMemLimit),
+		format.Size(totalMemUsage),
+		format.Size(totalMemLimit),
 		totalMemPercent/float64(len(stats)))
 
-	// Alerts for high usage
+	// Alerts for high usage, on P95 rather than a single sample when available
 	output.Newline()
 	hasAlerts := false
 	for _, stat := range stats {
-		if stat.CPUPercent > 80 {
+		cpu, mem, label := stat.CPUPercent, stat.MemoryPercent, ""
+		if historical && stat.MemoryP95 > 0 {
+			cpu, mem, label = stat.CPUP95, memoryP95Percent(stat), fmt.Sprintf(" P95 over %s", window)
+		}
+
+		if cpu > 80 {
 			if !hasAlerts {
 				output.Print(output.Section("Alerts"))
 				hasAlerts = true
 			}
-			output.Printf("  %s %s: High CPU usage (%.1f%%)\n",
+			output.Printf("  %s %s: High CPU usage (%.1f%%%s)\n",
 				output.WarningStyle.Render(output.IconWarning),
-				stat.Name, stat.CPUPercent)
+				stat.Name, cpu, label)
 		}
-		if stat.MemoryPercent > 80 {
+		if mem > 80 {
 			if !hasAlerts {
 				output.Print(output.Section("Alerts"))
 				hasAlerts = true
 			}
-			output.Printf("  %s %s: High memory usage (%.1f%%)\n",
+			output.Printf("  %s %s: High memory usage (%.1f%%%s)\n",
 				output.WarningStyle.Render(output.IconWarning),
-				stat.Name, stat.MemoryPercent)
+				stat.Name, mem, label)
 		}
 	}
 
+	if historical {
+		printMemoryRightSizing(stats, window)
+	}
+
 	if !hasAlerts {
 		output.Success("All containers within normal resource limits")
 	}
@@ -144,11 +203,56 @@ func runStats(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func truncateName(name string, maxLen int) string {
-	if len(name) <= maxLen {
-		return name
+// memoryP95Percent returns P95 memory as a percentage of the container's limit
+func memoryP95Percent(stat docker.ContainerStats) float64 {
+	if stat.MemoryLimit == 0 {
+		return 0
+	}
+	return float64(stat.MemoryP95) / float64(stat.MemoryLimit) * 100
+}
+
+// printMemoryRightSizing suggests memory limits for containers that use a fraction of theirs
+func printMemoryRightSizing(stats []docker.ContainerStats, window string) {
+	printed := false
+	for _, stat := range stats {
+		if stat.MemoryP95 == 0 || memoryP95Percent(stat) >= 25 {
+			continue
+		}
+		if !printed {
+			output.Print(output.Section("Right-Sizing"))
+			printed = true
+		}
+		output.Printf("  %s %s: P95 memory over %s is %s of %s; a limit of %s would do\n",
+			output.InfoStyle.Render(output.IconInfo), stat.Name, window,
+			format.Size(stat.MemoryP95), format.Size(stat.MemoryLimit), format.Size(stat.MemoryP95*3/2))
+	}
+	if printed {
+		output.Newline()
+	}
+}
+
+// getPrometheusClient returns a Prometheus client, or nil when no URL is configured
+func getPrometheusClient(cmd *cobra.Command) (*prometheus.Client, error) {
+	url, _ := cmd.Flags().GetString("prometheus-url")
+	if url == "" {
+		url = os.Getenv("PROMETHEUS_URL")
+	}
+	if url == "" {
+		url = viper.GetString("prometheus.url")
+	}
+	if url == "" {
+		return nil, nil
+	}
+
+	token := os.Getenv("PROMETHEUS_TOKEN")
+	if token == "" {
+		token = viper.GetString("prometheus.token")
+	}
+	token, err := secrets.Resolve(token)
+	if err != nil {
+		return nil, err
 	}
-	return name[:maxLen-3] + "..."
+	return prometheus.NewClient(url, token)
 }
 
 func getStatsRowColors(stat docker.ContainerStats) []tablewriter.Colors {
@@ -3,6 +3,7 @@ package gitlab
 import (
 	"fmt"
 
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
 	"github.com/olekukonko/tablewriter"
@@ -47,7 +48,7 @@ func runArtifacts(cmd *cobra.Command, args []string) error {
 
 	if jobID > 0 {
 		// Get artifacts for specific job
-		artifact, err := client.GetJobArtifacts(projectID, jobID)
+		artifact, err := client.GetJobArtifacts(cmd.Context(), projectID, jobID)
 		if err != nil {
 			output.SpinnerError("Failed to fetch artifacts")
 			return fmt.Errorf("failed to get artifacts: %w", err)
@@ -57,7 +58,7 @@ func runArtifacts(cmd *cobra.Command, args []string) error {
 		}
 	} else if pipelineID > 0 {
 		// Get all artifacts from pipeline
-		artifacts, err = client.ListPipelineArtifacts(projectID, pipelineID)
+		artifacts, err = client.ListPipelineArtifacts(cmd.Context(), projectID, pipelineID)
 		if err != nil {
 			output.SpinnerError("Failed to fetch artifacts")
 			return fmt.Errorf("failed to list artifacts: %w", err)
@@ -70,6 +71,10 @@ func runArtifacts(cmd *cobra.Command, args []string) error {
 	output.SpinnerSuccess(fmt.Sprintf("Found %d artifacts", len(artifacts)))
 	output.Newline()
 
+	if output.IsStructured() {
+		return output.Render(artifacts)
+	}
+
 	if len(artifacts) == 0 {
 		output.Info("No artifacts found")
 		return nil
@@ -90,7 +95,7 @@ func runArtifacts(cmd *cobra.Command, args []string) error {
 			[]string{
 				art.JobName,
 				art.Filename,
-				formatArtifactSize(art.Size),
+				format.Size(art.Size),
 				art.ExpireAt,
 			},
 			[]tablewriter.Colors{
@@ -106,27 +111,9 @@ func runArtifacts(cmd *cobra.Command, args []string) error {
 
 	// Summary
 	output.Newline()
-	output.Printf("Total artifact size: %s\n", formatArtifactSize(totalSize))
+	output.Printf("Total artifact size: %s\n", format.Size(totalSize))
 	output.Newline()
 
 	return nil
 }
 
-func formatArtifactSize(bytes int64) string {
-	const (
-		KB = 1024
-		MB = KB * 1024
-		GB = MB * 1024
-	)
-
-	switch {
-	case bytes >= GB:
-		return fmt.Sprintf("%.2f GB", float64(bytes)/float64(GB))
-	case bytes >= MB:
-		return fmt.Sprintf("%.1f MB", float64(bytes)/float64(MB))
-	case bytes >= KB:
-		return fmt.Sprintf("%.1f KB", float64(bytes)/float64(KB))
-	default:
-		return fmt.Sprintf("%d B", bytes)
-	}
-}
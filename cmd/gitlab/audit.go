@@ -0,0 +1,207 @@
+package gitlab
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/compliance"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Audit branch protection, approvals and tokens",
+		Long: `Review the security settings of a project, or of every project in a group,
+and report compliance-style findings:
+
+  • Default branch protection, force push and direct push access
+  • Required approvals, CODEOWNERS approval and self-approval
+  • Approval reset on push and per-MR overrides
+  • Push rules rejecting secret files
+  • Scopes and expiry of project access tokens and the token in use
+
+Settings the token cannot read, or that require a higher GitLab tier,
+are reported as skipped. Rule IDs work with --skip and --only like
+compliance check.
+
+Examples:
+  devops-toolkit gitlab audit -p group/app
+  devops-toolkit gitlab audit --group platform
+  devops-toolkit gitlab audit --group platform --severity high --all
+  devops-toolkit gitlab audit -p group/app --output json`,
+		RunE: runAudit,
+	}
+
+	cmd.Flags().String("group", "", "Audit every project in this group and its subgroups")
+	cmd.Flags().Bool("all", false, "Also show passed checks")
+	cmd.Flags().StringSlice("skip", nil, "Rules to skip")
+	cmd.Flags().StringSlice("only", nil, "Only run these rules")
+	cmd.Flags().String("severity", "", "Minimum severity to report (low, medium, high, critical)")
+
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("skip", completion.RuleCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("only", completion.RuleCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("severity", completion.SeverityCompletion)
+
+	return cmd
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	group, _ := cmd.Flags().GetString("group")
+	showAll, _ := cmd.Flags().GetBool("all")
+	skip, _ := cmd.Flags().GetStringSlice("skip")
+	only, _ := cmd.Flags().GetStringSlice("only")
+	severity, _ := cmd.Flags().GetString("severity")
+
+	output.StartSpinner("Connecting to GitLab...")
+
+	var projects []string
+	client, err := newClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to connect to GitLab")
+		return err
+	}
+	if group != "" {
+		output.UpdateSpinner(fmt.Sprintf("Listing projects in %s...", group))
+		projects, err = client.ListGroupProjects(cmd.Context(), group)
+		if err != nil {
+			output.SpinnerError("Failed to list group projects")
+			return err
+		}
+	} else {
+		projectID, err := resolveProject(cmd)
+		if err != nil {
+			output.SpinnerError("No project to audit")
+			return err
+		}
+		projects = []string{projectID}
+	}
+
+	output.UpdateSpinner(fmt.Sprintf("Auditing %d projects...", len(projects)))
+	checker := compliance.NewGitLabChecker(client, projects, compliance.CheckOptions{
+		SkipRules:   skip,
+		OnlyRules:   only,
+		MinSeverity: severity,
+	})
+	results, err := checker.Run(cmd.Context())
+	if err != nil {
+		output.SpinnerError("Audit failed")
+		return err
+	}
+
+	var passed, failed, warnings, skipped int
+	for _, r := range results {
+		switch r.Status {
+		case compliance.StatusPassed:
+			passed++
+		case compliance.StatusFailed:
+			failed++
+		case compliance.StatusWarning:
+			warnings++
+		case compliance.StatusSkipped:
+			skipped++
+		}
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Audited %d projects: %d findings", len(projects), failed+warnings))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(results)
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "GitLab Audit",
+		Headers:    []string{"Status", "Severity", "Rule", "Resource", "Finding"},
+		ShowBorder: true,
+	})
+
+	rows := 0
+	for _, r := range results {
+		if r.Status == compliance.StatusPassed && !showAll {
+			continue
+		}
+		rows++
+
+		statusColor := tablewriter.FgGreenColor
+		switch r.Status {
+		case compliance.StatusFailed:
+			statusColor = tablewriter.FgRedColor
+		case compliance.StatusWarning:
+			statusColor = tablewriter.FgYellowColor
+		case compliance.StatusSkipped:
+			statusColor = tablewriter.FgHiBlackColor
+		}
+		severityColor := tablewriter.FgCyanColor
+		switch r.Severity {
+		case "critical", "high":
+			severityColor = tablewriter.FgRedColor
+		case "medium":
+			severityColor = tablewriter.FgYellowColor
+		}
+
+		table.AddColoredRow(
+			[]string{
+				string(r.Status),
+				strings.ToUpper(r.Severity),
+				r.RuleID,
+				output.Truncate(r.Resource, 40),
+				r.Message,
+			},
+			[]tablewriter.Colors{
+				{tablewriter.Bold, statusColor},
+				{severityColor},
+				{tablewriter.FgCyanColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgHiBlackColor},
+			},
+		)
+	}
+
+	if rows > 0 {
+		table.Render()
+	} else {
+		output.Success("No findings")
+	}
+
+	// Remediation, once per rule
+	seen := make(map[string]bool)
+	var remediations []compliance.CheckResult
+	for _, r := range results {
+		if r.Status == compliance.StatusFailed && r.Remediation != "" && !seen[r.RuleID] {
+			seen[r.RuleID] = true
+			remediations = append(remediations, r)
+		}
+	}
+	if len(remediations) > 0 {
+		output.Newline()
+		output.Print(output.Section("Remediation"))
+		for _, r := range remediations {
+			output.Printf("  %s %s: %s\n", output.IconArrow, r.RuleID, r.Remediation)
+		}
+	}
+
+	output.Newline()
+	output.Print(output.Section("Summary"))
+	output.Printf("  %s Passed: %d\n", output.SuccessStyle.Render(output.IconSuccess), passed)
+	if failed > 0 {
+		output.Printf("  %s Failed: %d\n", output.ErrorStyle.Render(output.IconError), failed)
+	}
+	if warnings > 0 {
+		output.Printf("  %s Warnings: %d\n", output.WarningStyle.Render(output.IconWarning), warnings)
+	}
+	if skipped > 0 {
+		output.Printf("  %s Skipped: %d\n", output.MutedStyle.Render(output.IconCross), skipped)
+	}
+	if scored := len(results) - skipped; scored > 0 {
+		score := float64(passed) / float64(scored) * 100
+		output.Printf("\n  Score: %s %.1f%%\n", output.ProgressBar(int(score), 100, 30), score)
+	}
+
+	output.Newline()
+	return nil
+}
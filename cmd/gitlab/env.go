@@ -0,0 +1,154 @@
+package gitlab
+
+import (
+	"fmt"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newEnvCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "env",
+		Aliases: []string{"environment"},
+		Short:   "Environment deployment operations",
+		Long: `Inspect what is deployed to GitLab environments.
+
+Deployments are read from the project's deployment history, so any
+pipeline job with an environment: keyword is tracked.`,
+	}
+
+	cmd.AddCommand(newEnvDiffCmd())
+
+	return cmd
+}
+
+func newEnvDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <source> <target>",
+		Short: "Show what is deployed to one environment but not another",
+		Long: `Compare the latest successful deployments of two environments and list
+the commits and merged merge requests deployed to the source environment
+that have not reached the target yet.
+
+Commits deployed to the target but missing from the source, such as
+hotfixes that were not merged back, are listed separately.
+
+Examples:
+  devops-toolkit gitlab env diff staging production -p group/app
+  devops-toolkit gitlab env diff staging production --commits
+  devops-toolkit gitlab env diff staging production --output json`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completion.EnvironmentCompletion,
+		RunE:              runEnvDiff,
+	}
+
+	cmd.Flags().Bool("commits", false, "List every pending commit, not only merge requests")
+
+	return cmd
+}
+
+func runEnvDiff(cmd *cobra.Command, args []string) error {
+	source, target := args[0], args[1]
+	showCommits, _ := cmd.Flags().GetBool("commits")
+
+	output.StartSpinner(fmt.Sprintf("Comparing %s with %s...", source, target))
+
+	client, projectID, err := getClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to connect to GitLab")
+		return err
+	}
+
+	diff, err := client.DiffEnvironments(cmd.Context(), projectID, source, target)
+	if err != nil {
+		output.SpinnerError("Failed to compare environments")
+		return err
+	}
+
+	if len(diff.Pending) == 0 {
+		output.SpinnerSuccess(fmt.Sprintf("%s has everything deployed to %s", target, source))
+	} else {
+		output.SpinnerSuccess(fmt.Sprintf("%d commits in %s are not in %s", len(diff.Pending), source, target))
+	}
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(diff)
+	}
+
+	output.Print(output.Section("Deployments"))
+	for _, d := range []gitlabclient.DeploymentInfo{diff.Source, diff.Target} {
+		output.Printf("  %s\n", output.KeyValue(d.Environment,
+			fmt.Sprintf("%s @ %s by %s, %s", d.Ref, shortSHA(d.SHA), valueOrDash(d.User), format.Timestamp(d.CreatedAt))))
+	}
+	output.Newline()
+
+	if len(diff.MergeRequests) > 0 {
+		table := output.NewTable(output.TableConfig{
+			Title:      fmt.Sprintf("Merge Requests in %s, not in %s", source, target),
+			Headers:    []string{"MR", "Title", "Author", "Merged"},
+			ShowBorder: true,
+		})
+		for _, mr := range diff.MergeRequests {
+			merged := "-"
+			if mr.MergedAt != nil {
+				merged = mr.MergedAt.Format("2006-01-02")
+			}
+			table.AddColoredRow(
+				[]string{fmt.Sprintf("!%d", mr.IID), mr.Title, mr.Author, merged},
+				[]tablewriter.Colors{
+					{tablewriter.FgCyanColor},
+					{tablewriter.FgWhiteColor},
+					{tablewriter.FgYellowColor},
+					{tablewriter.FgHiBlackColor},
+				},
+			)
+		}
+		table.Render()
+		output.Newline()
+	} else if len(diff.Pending) > 0 {
+		output.Info("No merged merge requests found for the pending commits")
+		output.Newline()
+	}
+
+	if showCommits && len(diff.Pending) > 0 {
+		printCommits(fmt.Sprintf("Commits in %s, not in %s", source, target), diff.Pending)
+	}
+
+	if len(diff.TargetOnly) > 0 {
+		output.Warning(fmt.Sprintf("%d commits in %s are not in %s (hotfixes not merged back?)", len(diff.TargetOnly), target, source))
+		output.Newline()
+		printCommits(fmt.Sprintf("Commits in %s, not in %s", target, source), diff.TargetOnly)
+	}
+
+	if len(diff.Pending) == 0 && len(diff.TargetOnly) == 0 {
+		output.Success(fmt.Sprintf("%s and %s run the same commit", source, target))
+		output.Newline()
+	}
+
+	return nil
+}
+
+// printCommits lists commits with their short SHA, author and title
+func printCommits(title string, commits []gitlabclient.CommitInfo) {
+	output.Print(output.Section(title))
+	for _, c := range commits {
+		output.Printf("  %s %s %s\n",
+			output.InfoStyle.Render(shortSHA(c.SHA)),
+			c.Title,
+			output.MutedStyle.Render("("+c.Author+")"))
+	}
+	output.Newline()
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -19,14 +19,24 @@ with beautiful output and powerful filtering options.`,
 	// Add subcommands
 	cmd.AddCommand(newPipelinesCmd())
 	cmd.AddCommand(newJobsCmd())
+	cmd.AddCommand(newJobTrendsCmd())
 	cmd.AddCommand(newTriggerCmd())
+	cmd.AddCommand(newLintCmd())
 	cmd.AddCommand(newArtifactsCmd())
 	cmd.AddCommand(newStatusCmd())
+	cmd.AddCommand(newEnvCmd())
+	cmd.AddCommand(newIssuesCmd())
+	cmd.AddCommand(newIncidentsCmd())
+	cmd.AddCommand(newListenCmd())
+	cmd.AddCommand(newAuditCmd())
+	cmd.AddCommand(newMinutesCmd())
 
 	// Persistent flags
 	cmd.PersistentFlags().String("token", "", "GitLab access token (or set GITLAB_TOKEN)")
 	cmd.PersistentFlags().String("url", "https://gitlab.com", "GitLab instance URL")
 	cmd.PersistentFlags().StringP("project", "p", "", "Project ID or path")
+	cmd.PersistentFlags().Int("token-expiry-warning", 14, "Warn when the token expires within this many days")
+	cmd.PersistentFlags().Bool("skip-token-check", false, "Skip the token scope and expiry check")
 
 	return cmd
 }
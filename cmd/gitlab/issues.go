@@ -0,0 +1,241 @@
+package gitlab
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newIssuesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "issues",
+		Aliases: []string{"issue"},
+		Short:   "List project issues",
+		Long: `List open issues of a project, newest first.
+
+Examples:
+  devops-toolkit gitlab issues -p group/app
+  devops-toolkit gitlab issues -l bug -l backend
+  devops-toolkit gitlab issues --assignee none --milestone "v2.0"
+  devops-toolkit gitlab issues --assignee alice --state all -n 50`,
+		RunE: runIssues,
+	}
+
+	addIssueFilterFlags(cmd)
+	cmd.Flags().String("milestone", "", "Filter by milestone title")
+
+	return cmd
+}
+
+func newIncidentsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "incidents",
+		Aliases: []string{"incident"},
+		Short:   "List open incidents by severity and age",
+		Long: `List open incidents of a project, most severe first and then oldest first.
+
+The REST API does not return incident severity, so it is read from
+severity::<level> labels (critical, high, medium, low or 1-4) or S1-S4
+labels; incidents without one are shown as unknown.
+
+Examples:
+  devops-toolkit gitlab incidents -p group/app
+  devops-toolkit gitlab incidents --assignee none
+  devops-toolkit gitlab incidents --state all -n 20 --output json`,
+		RunE: runIncidents,
+	}
+
+	addIssueFilterFlags(cmd)
+
+	return cmd
+}
+
+func addIssueFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayP("label", "l", nil, "Filter by label (repeatable, all must match)")
+	cmd.Flags().String("assignee", "", "Filter by assignee username, or none/any")
+	cmd.Flags().StringP("state", "s", "opened", "Issue state (opened, closed, all)")
+	cmd.Flags().IntP("limit", "n", 30, "Maximum number of issues to show")
+
+	_ = cmd.RegisterFlagCompletionFunc("state", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"opened", "closed", "all"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+func issueFilter(cmd *cobra.Command) gitlabclient.IssueFilter {
+	labels, _ := cmd.Flags().GetStringArray("label")
+	assignee, _ := cmd.Flags().GetString("assignee")
+	state, _ := cmd.Flags().GetString("state")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	filter := gitlabclient.IssueFilter{
+		State:    state,
+		Labels:   labels,
+		Assignee: assignee,
+		Limit:    limit,
+	}
+	if cmd.Flags().Lookup("milestone") != nil {
+		filter.Milestone, _ = cmd.Flags().GetString("milestone")
+	}
+	return filter
+}
+
+func runIssues(cmd *cobra.Command, args []string) error {
+	output.StartSpinner("Fetching issues...")
+
+	client, projectID, err := getClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to connect to GitLab")
+		return err
+	}
+
+	issues, err := client.ListIssues(cmd.Context(), projectID, issueFilter(cmd))
+	if err != nil {
+		output.SpinnerError("Failed to fetch issues")
+		return err
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Found %d issues", len(issues)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(issues)
+	}
+
+	if len(issues) == 0 {
+		output.Info("No issues found matching the criteria")
+		return nil
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Issues",
+		Headers:    []string{"ID", "Title", "Labels", "Assignees", "Milestone", "Age"},
+		ShowBorder: true,
+	})
+
+	for _, issue := range issues {
+		title := issue.Title
+		if issue.Type != "issue" {
+			title = fmt.Sprintf("[%s] %s", issue.Type, title)
+		}
+		stateColor := tablewriter.FgCyanColor
+		if issue.State == "closed" {
+			stateColor = tablewriter.FgHiBlackColor
+		}
+		table.AddColoredRow(
+			[]string{
+				fmt.Sprintf("#%d", issue.IID),
+				output.Truncate(title, 50),
+				strings.Join(issue.Labels, ", "),
+				joinOrDash(issue.Assignees),
+				valueOrDash(issue.Milestone),
+				format.Age(issue.CreatedAt),
+			},
+			[]tablewriter.Colors{
+				{stateColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgYellowColor},
+				{tablewriter.FgCyanColor},
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgWhiteColor},
+			},
+		)
+	}
+
+	table.Render()
+	return nil
+}
+
+func runIncidents(cmd *cobra.Command, args []string) error {
+	output.StartSpinner("Fetching incidents...")
+
+	client, projectID, err := getClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to connect to GitLab")
+		return err
+	}
+
+	incidents, err := client.ListIncidents(cmd.Context(), projectID, issueFilter(cmd))
+	if err != nil {
+		output.SpinnerError("Failed to fetch incidents")
+		return err
+	}
+
+	unassigned := 0
+	for _, inc := range incidents {
+		if len(inc.Assignees) == 0 && inc.State == "opened" {
+			unassigned++
+		}
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Found %d incidents", len(incidents)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(incidents)
+	}
+
+	if len(incidents) == 0 {
+		output.Success("No incidents")
+		return nil
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Incidents",
+		Headers:    []string{"ID", "Severity", "Title", "Assignees", "Age", "Last Update"},
+		ShowBorder: true,
+	})
+
+	for _, inc := range incidents {
+		severityColor := tablewriter.FgHiBlackColor
+		switch inc.Severity {
+		case "critical", "high":
+			severityColor = tablewriter.FgRedColor
+		case "medium":
+			severityColor = tablewriter.FgYellowColor
+		case "low":
+			severityColor = tablewriter.FgCyanColor
+		}
+		assigneeColor := tablewriter.FgCyanColor
+		if len(inc.Assignees) == 0 {
+			assigneeColor = tablewriter.FgRedColor
+		}
+		table.AddColoredRow(
+			[]string{
+				fmt.Sprintf("#%d", inc.IID),
+				strings.ToUpper(inc.Severity),
+				output.Truncate(inc.Title, 50),
+				joinOrDash(inc.Assignees),
+				format.Age(inc.CreatedAt),
+				format.Age(inc.UpdatedAt),
+			},
+			[]tablewriter.Colors{
+				{tablewriter.FgCyanColor},
+				{tablewriter.Bold, severityColor},
+				{tablewriter.FgWhiteColor},
+				{assigneeColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgHiBlackColor},
+			},
+		)
+	}
+
+	table.Render()
+
+	if unassigned > 0 {
+		output.Newline()
+		output.Warning(fmt.Sprintf("%d open incidents have no assignee", unassigned))
+	}
+	return nil
+}
+
+func joinOrDash(items []string) string {
+	if len(items) == 0 {
+		return "-"
+	}
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,186 @@
+package gitlab
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newJobTrendsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "job-trends",
+		Short: "Analyze job duration trends across recent pipelines",
+		Long: `Gather the durations of jobs across the last --pipelines finished pipelines
+and report median, P95 and the trend of each job.
+
+Only successful runs are measured; failed runs are counted separately.
+The median of the newest --recent runs is compared with the median of
+the runs before them, and jobs that got at least --threshold percent
+slower are flagged. Without --job, every job in the pipelines is analyzed.
+
+Examples:
+  devops-toolkit gitlab job-trends -p group/app --job build
+  devops-toolkit gitlab job-trends --job build --job test -r main -n 100
+  devops-toolkit gitlab job-trends --threshold 10 --recent 10
+  devops-toolkit gitlab job-trends --job build --output json`,
+		SilenceUsage: true,
+		RunE:         runJobTrends,
+	}
+
+	cmd.Flags().StringSlice("job", nil, "Job names to analyze (default all)")
+	cmd.Flags().StringP("ref", "r", "", "Only use pipelines on this branch or tag")
+	cmd.Flags().IntP("pipelines", "n", 50, "Number of recent pipelines to analyze")
+	cmd.Flags().Int("recent", 5, "Newest runs compared against the older ones")
+	cmd.Flags().Float64("threshold", 20, "Percent slowdown that flags a job")
+	cmd.Flags().Bool("fail-on-regression", false, "Exit non-zero when a job got slower")
+
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("ref", completion.BranchCompletion)
+
+	return cmd
+}
+
+func runJobTrends(cmd *cobra.Command, args []string) error {
+	jobs, _ := cmd.Flags().GetStringSlice("job")
+	ref, _ := cmd.Flags().GetString("ref")
+	pipelines, _ := cmd.Flags().GetInt("pipelines")
+	recent, _ := cmd.Flags().GetInt("recent")
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	failOnRegression, _ := cmd.Flags().GetBool("fail-on-regression")
+
+	if pipelines <= 0 {
+		return fmt.Errorf("--pipelines must be positive")
+	}
+
+	output.StartSpinner("Connecting to GitLab...")
+
+	client, projectID, err := getClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to connect to GitLab")
+		return err
+	}
+
+	output.UpdateSpinner(fmt.Sprintf("Reading jobs of the last %d pipelines...", pipelines))
+	runs, err := client.JobRuns(cmd.Context(), projectID, ref, pipelines, jobs)
+	if err != nil {
+		output.SpinnerError("Failed to read job durations")
+		return err
+	}
+
+	names := make([]string, 0, len(runs))
+	for name := range runs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	trends := make([]gitlabclient.JobTrend, 0, len(names))
+	slower := 0
+	for _, name := range names {
+		trend := gitlabclient.NewJobTrend(name, runs[name], recent, threshold)
+		if trend.Slower {
+			slower++
+		}
+		trends = append(trends, trend)
+	}
+	for _, name := range jobs {
+		if _, ok := runs[name]; !ok {
+			trends = append(trends, gitlabclient.JobTrend{Job: name})
+		}
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Analyzed %d jobs: %d slower", len(names), slower))
+	output.Newline()
+
+	var regressionErr error
+	if failOnRegression && slower > 0 {
+		regressionErr = exitcode.ChecksFailedf("%d jobs got at least %.0f%% slower", slower, threshold)
+	}
+
+	if output.IsStructured() {
+		if err := output.Render(trends); err != nil {
+			return err
+		}
+		return regressionErr
+	}
+
+	if len(trends) == 0 {
+		output.Info("No finished jobs found")
+		return nil
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Job Duration Trends",
+		Headers:    []string{"Job", "Runs", "Failed", "Median", "P95", "Range", "Recent", "Change"},
+		ShowBorder: true,
+	})
+
+	for _, t := range trends {
+		change := "-"
+		changeColor := tablewriter.FgHiBlackColor
+		if t.Baseline > 0 {
+			change = fmt.Sprintf("%+.0f%%", t.Change)
+			switch {
+			case t.Slower:
+				change = output.IconWarning + " " + change
+				changeColor = tablewriter.FgRedColor
+			case t.Change <= -threshold:
+				changeColor = tablewriter.FgGreenColor
+			default:
+				changeColor = tablewriter.FgWhiteColor
+			}
+		}
+		failedColor := tablewriter.FgHiBlackColor
+		if t.Failed > 0 {
+			failedColor = tablewriter.FgRedColor
+		}
+
+		rangeStr := "-"
+		if t.Runs > 0 {
+			rangeStr = format.Seconds(t.Fastest) + " - " + format.Seconds(t.Slowest)
+		}
+
+		table.AddColoredRow(
+			[]string{
+				t.Job,
+				fmt.Sprintf("%d", t.Runs),
+				fmt.Sprintf("%d", t.Failed),
+				format.Seconds(t.Median),
+				format.Seconds(t.P95),
+				rangeStr,
+				format.Seconds(t.Recent),
+				change,
+			},
+			[]tablewriter.Colors{
+				{tablewriter.FgCyanColor},
+				{tablewriter.FgWhiteColor},
+				{failedColor},
+				{tablewriter.Bold},
+				{tablewriter.FgYellowColor},
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.Bold, changeColor},
+			},
+		)
+	}
+
+	table.Render()
+
+	output.Newline()
+	if slower > 0 {
+		output.Warning(fmt.Sprintf("%d jobs are at least %.0f%% slower over the last %d runs", slower, threshold, recent))
+	} else {
+		output.Success(fmt.Sprintf("No job got %.0f%% slower over the last %d runs", threshold, recent))
+	}
+	output.Muted(fmt.Sprintf("  Change compares the median of the newest %d successful runs with the older ones", recent))
+	output.Newline()
+
+	return regressionErr
+}
+
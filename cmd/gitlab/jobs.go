@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
 	"github.com/olekukonko/tablewriter"
@@ -30,6 +31,9 @@ Features:
 	cmd.Flags().String("stage", "", "Filter by stage")
 	cmd.Flags().Bool("failed", false, "Show only failed jobs")
 
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("status", completion.JobStatusCompletion)
+
 	return cmd
 }
 
@@ -55,7 +59,7 @@ func runJobs(cmd *cobra.Command, args []string) error {
 		status = "failed"
 	}
 
-	jobs, err := client.ListPipelineJobs(projectID, pipelineID, gitlabclient.JobFilter{
+	jobs, err := client.ListPipelineJobs(cmd.Context(), projectID, pipelineID, gitlabclient.JobFilter{
 		Status: status,
 		Stage:  stage,
 	})
@@ -67,6 +71,10 @@ func runJobs(cmd *cobra.Command, args []string) error {
 	output.SpinnerSuccess(fmt.Sprintf("Found %d jobs", len(jobs)))
 	output.Newline()
 
+	if output.IsStructured() {
+		return output.Render(jobs)
+	}
+
 	if len(jobs) == 0 {
 		output.Info("No jobs found matching the criteria")
 		return nil
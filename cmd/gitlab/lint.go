@@ -0,0 +1,134 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// defaultCIFile is the CI configuration linted when no file is given
+const defaultCIFile = ".gitlab-ci.yml"
+
+func newLintCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint [file]",
+		Short: "Validate a local .gitlab-ci.yml with the CI Lint API",
+		Long: `Validate a local CI configuration with the project's CI Lint API before
+pushing it. The file defaults to .gitlab-ci.yml in the current directory.
+
+Validation runs in the context of the project, so include:local,
+include:project and include:template are resolved against the repository
+at --ref. With --dry-run a pipeline creation is simulated, which also
+catches errors in rules, needs and workflow. Use --merged to print the
+configuration with all includes expanded.
+
+Exits non-zero when the configuration is invalid.
+
+Examples:
+  devops-toolkit gitlab lint -p group/app
+  devops-toolkit gitlab lint ci/pipeline.yml -p group/app --ref develop
+  devops-toolkit gitlab lint --dry-run --merged
+  devops-toolkit gitlab lint --output json`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE:         runLint,
+		Annotations:  map[string]string{scopeAnnotation: gitlabclient.ScopeAPI},
+	}
+
+	cmd.Flags().StringP("ref", "r", "", "Branch or tag to resolve includes and simulate on (default branch if empty)")
+	cmd.Flags().Bool("dry-run", false, "Simulate pipeline creation to also validate rules and needs")
+	cmd.Flags().Bool("merged", false, "Show the merged configuration with includes expanded")
+
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("ref", completion.BranchCompletion)
+
+	return cmd
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	ref, _ := cmd.Flags().GetString("ref")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	showMerged, _ := cmd.Flags().GetBool("merged")
+
+	file := defaultCIFile
+	if len(args) > 0 {
+		file = args[0]
+	}
+
+	output.StartSpinner(fmt.Sprintf("Linting %s...", file))
+
+	client, projectID, err := getClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to connect to GitLab")
+		return err
+	}
+
+	result, err := lintFile(cmd.Context(), client, projectID, file, ref, dryRun)
+	if err != nil {
+		output.SpinnerError("Lint failed")
+		return err
+	}
+
+	if result.Valid {
+		output.SpinnerSuccess(fmt.Sprintf("%s is valid", file))
+	} else {
+		output.SpinnerError(fmt.Sprintf("%s is invalid", file))
+	}
+	output.Newline()
+
+	if output.IsStructured() {
+		if err := output.Render(result); err != nil {
+			return err
+		}
+		return lintError(file, result)
+	}
+
+	printLintResult(result)
+
+	if showMerged && result.MergedYAML != "" {
+		output.Print(output.Section("Merged Configuration"))
+		output.Print(result.MergedYAML)
+	}
+
+	return lintError(file, result)
+}
+
+// lintFile reads a CI configuration file and validates it in the project context
+func lintFile(ctx context.Context, client *gitlabclient.Client, projectID, file, ref string, dryRun bool) (*gitlabclient.LintResult, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return client.LintConfig(ctx, projectID, string(content), ref, dryRun)
+}
+
+// printLintResult lists the errors and warnings of a lint result
+func printLintResult(result *gitlabclient.LintResult) {
+	if len(result.Errors) > 0 {
+		output.Print(output.Section("Errors"))
+		for _, e := range result.Errors {
+			output.Printf("  %s %s\n", output.ErrorStyle.Render(output.IconError), e)
+		}
+		output.Newline()
+	}
+	if len(result.Warnings) > 0 {
+		output.Print(output.Section("Warnings"))
+		for _, w := range result.Warnings {
+			output.Printf("  %s %s\n", output.WarningStyle.Render(output.IconWarning), w)
+		}
+		output.Newline()
+	}
+}
+
+func lintError(file string, result *gitlabclient.LintResult) error {
+	if result.Valid {
+		return nil
+	}
+	return exitcode.ChecksFailedf("%s is invalid: %d errors", file, len(result.Errors))
+}
@@ -0,0 +1,251 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/notify"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/secrets"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newListenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "listen",
+		Short: "Receive pipeline, job and deployment webhooks as a live feed",
+		Long: `Run a webhook receiver and print pipeline, job and deployment events as
+they arrive, color-coded by status.
+
+Point a project or group webhook at http://<host>:<port>/ with pipeline,
+job and deployment events enabled. Requests must carry the secret token
+from --secret, GITLAB_WEBHOOK_SECRET or gitlab.webhook_secret in the
+config file; without one, every request is accepted.
+
+With --forward, finished events are also posted to the configured
+Slack/Teams/webhook notification sinks.
+
+Examples:
+  devops-toolkit gitlab listen --port 8080 --secret s3cret
+  devops-toolkit gitlab listen --events pipeline,deployment --forward
+  devops-toolkit gitlab listen --forward --failures-only
+  devops-toolkit gitlab listen --output json`,
+		SilenceUsage: true,
+		RunE:         runListen,
+	}
+
+	cmd.Flags().Int("port", 8080, "Port to listen on")
+	cmd.Flags().String("bind", "", "Address to bind to (default all interfaces)")
+	cmd.Flags().String("secret", "", "Secret token GitLab sends in X-Gitlab-Token")
+	cmd.Flags().StringSlice("events", []string{gitlabclient.EventPipeline, gitlabclient.EventJob, gitlabclient.EventDeployment}, "Event kinds to show (pipeline, job, deployment)")
+	cmd.Flags().Bool("forward", false, "Forward finished events to the configured notification sinks")
+	cmd.Flags().Bool("failures-only", false, "Only show and forward failed events")
+
+	_ = cmd.RegisterFlagCompletionFunc("events", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{gitlabclient.EventPipeline, gitlabclient.EventJob, gitlabclient.EventDeployment}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func runListen(cmd *cobra.Command, args []string) error {
+	port, _ := cmd.Flags().GetInt("port")
+	bind, _ := cmd.Flags().GetString("bind")
+	secret, _ := cmd.Flags().GetString("secret")
+	events, _ := cmd.Flags().GetStringSlice("events")
+	forward, _ := cmd.Flags().GetBool("forward")
+	failuresOnly, _ := cmd.Flags().GetBool("failures-only")
+
+	if secret == "" {
+		secret = os.Getenv("GITLAB_WEBHOOK_SECRET")
+	}
+	if secret == "" {
+		secret = viper.GetString("gitlab.webhook_secret")
+	}
+	secret, err := secrets.Resolve(secret)
+	if err != nil {
+		return err
+	}
+
+	kinds := make(map[string]bool, len(events))
+	for _, e := range events {
+		e = strings.ToLower(strings.TrimSpace(e))
+		switch e {
+		case gitlabclient.EventPipeline, gitlabclient.EventJob, gitlabclient.EventDeployment:
+			kinds[e] = true
+		default:
+			return fmt.Errorf("unknown event kind %q (use pipeline, job or deployment)", e)
+		}
+	}
+
+	var sinks []notify.Sink
+	if forward {
+		sinks, err = notify.Sinks()
+		if err != nil {
+			return err
+		}
+		if len(sinks) == 0 {
+			return fmt.Errorf("--forward needs a notification sink (set notify.slack_webhook_url, SLACK_WEBHOOK_URL, ...)")
+		}
+	}
+
+	ctx := cmd.Context()
+
+	// Events are printed and forwarded one at a time, in arrival order
+	queue := make(chan gitlabclient.WebhookEvent, 64)
+	handler := gitlabclient.WebhookHandler(secret, func(event gitlabclient.WebhookEvent) {
+		if !kinds[event.Kind] || (failuresOnly && event.Status != "failed") {
+			return
+		}
+		select {
+		case queue <- event:
+		default:
+			output.Warning(fmt.Sprintf("Dropped %s event #%d, feed is falling behind", event.Kind, event.ID))
+		}
+	})
+
+	server := &http.Server{
+		Addr:              net.JoinHostPort(bind, strconv.Itoa(port)),
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+
+	output.Info(fmt.Sprintf("Listening for GitLab webhooks on %s (Ctrl+C to stop)", server.Addr))
+	if secret == "" {
+		output.Warning("No secret token configured; every request is accepted")
+	}
+	if forward {
+		output.Muted(fmt.Sprintf("  Forwarding finished events to %d notification sinks", len(sinks)))
+	}
+	output.Newline()
+
+	received := 0
+	for {
+		select {
+		case event := <-queue:
+			received++
+			if output.IsStructured() {
+				if err := output.Render(event); err != nil {
+					output.Warning(err.Error())
+				}
+			} else {
+				printWebhookEvent(event)
+			}
+			if forward && event.Finished() {
+				if err := notify.Send(ctx, sinks, webhookNotification(event)); err != nil {
+					output.Warning(fmt.Sprintf("Notification failed: %v", err))
+				}
+			}
+		case err := <-errCh:
+			if errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
+			return fmt.Errorf("failed to listen on %s: %w", server.Addr, err)
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+			output.Newline()
+			output.Info(fmt.Sprintf("Stopped after %d events", received))
+			return nil
+		}
+	}
+}
+
+// printWebhookEvent prints one line per event, colored by status
+func printWebhookEvent(e gitlabclient.WebhookEvent) {
+	var subject string
+	switch e.Kind {
+	case gitlabclient.EventPipeline:
+		subject = fmt.Sprintf("pipeline #%d", e.ID)
+	case gitlabclient.EventJob:
+		subject = fmt.Sprintf("job %s (%s) #%d", e.Name, e.Stage, e.ID)
+	case gitlabclient.EventDeployment:
+		subject = fmt.Sprintf("deploy to %s", e.Name)
+	}
+
+	status := e.Status
+	switch e.Status {
+	case "success":
+		status = output.SuccessStyle.Render(status)
+	case "failed":
+		status = output.ErrorStyle.Render(status)
+	case "running":
+		status = output.InfoStyle.Render(status)
+	case "canceled", "skipped":
+		status = output.MutedStyle.Render(status)
+	default:
+		status = output.WarningStyle.Render(status)
+	}
+
+	line := fmt.Sprintf("%s %s %s %s %s",
+		output.MutedStyle.Render(e.ReceivedAt.Format("15:04:05")),
+		getPipelineStatusIcon(e.Status),
+		output.InfoStyle.Render(e.Project),
+		subject,
+		status)
+	if e.Ref != "" {
+		line += output.MutedStyle.Render(fmt.Sprintf(" on %s@%s", e.Ref, shortSHA(e.SHA)))
+	}
+	if e.Finished() && e.Duration > 0 {
+		line += output.MutedStyle.Render(" in " + format.Seconds(e.Duration))
+	}
+	if e.User != "" {
+		line += output.MutedStyle.Render(" by " + e.User)
+	}
+	if e.Reason != "" {
+		line += " " + output.ErrorStyle.Render("("+e.Reason+")")
+	}
+	output.Print(line)
+}
+
+// webhookNotification converts a finished event into a notification
+func webhookNotification(e gitlabclient.WebhookEvent) notify.Message {
+	status := notify.StatusFailure
+	if e.Status == "success" {
+		status = notify.StatusSuccess
+	}
+
+	title := fmt.Sprintf("Pipeline #%d on %s: %s", e.ID, e.Ref, e.Status)
+	switch e.Kind {
+	case gitlabclient.EventJob:
+		title = fmt.Sprintf("Job %s on %s: %s", e.Name, e.Ref, e.Status)
+	case gitlabclient.EventDeployment:
+		title = fmt.Sprintf("Deployment to %s: %s", e.Name, e.Status)
+	}
+
+	fields := []notify.Field{
+		{Name: "Project", Value: e.Project},
+		{Name: "Ref", Value: e.Ref},
+		{Name: "Commit", Value: shortSHA(e.SHA)},
+	}
+	if e.Duration > 0 {
+		fields = append(fields, notify.Field{Name: "Duration", Value: format.Seconds(e.Duration)})
+	}
+	if e.Reason != "" {
+		fields = append(fields, notify.Field{Name: "Reason", Value: e.Reason})
+	}
+
+	return notify.Message{
+		Title:     title,
+		Status:    status,
+		Link:      e.URL,
+		Fields:    fields,
+		Timestamp: e.ReceivedAt,
+	}
+}
@@ -0,0 +1,205 @@
+package gitlab
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/batch"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newMinutesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "minutes",
+		Short: "Show CI minutes usage against quota with a forecast",
+		Long: `Show shared runner CI minutes used by a project, or by every project in a
+group, against the monthly quota of the top-level group.
+
+Usage is the duration of finished jobs that ran on shared runners,
+multiplied by --cost-factor. Jobs on project or group runners do not
+count towards the quota. The forecast extrapolates the daily rate over
+the trailing --days to the date the quota runs out, if that is before
+the monthly reset.
+
+The quota is read from the group's shared runner minutes limit (admin
+access) unless --quota is given. With -p, only that project's usage is
+counted against the group-wide quota; use --group for the full picture.
+
+Examples:
+  devops-toolkit gitlab minutes -p group/app
+  devops-toolkit gitlab minutes --group platform
+  devops-toolkit gitlab minutes --group platform --quota 10000 --cost-factor 2
+  devops-toolkit gitlab minutes --group platform --output json`,
+		RunE: runMinutes,
+	}
+
+	cmd.Flags().String("group", "", "Sum usage of every project in this group and its subgroups")
+	cmd.Flags().Int("days", 30, "Trailing days used for the daily rate")
+	cmd.Flags().Int("quota", 0, "Monthly minutes quota (default from the group settings)")
+	cmd.Flags().Float64("cost-factor", 1, "Runner cost factor applied to job durations")
+
+	return cmd
+}
+
+func runMinutes(cmd *cobra.Command, args []string) error {
+	group, _ := cmd.Flags().GetString("group")
+	days, _ := cmd.Flags().GetInt("days")
+	quotaFlag, _ := cmd.Flags().GetInt("quota")
+	costFactor, _ := cmd.Flags().GetFloat64("cost-factor")
+
+	if days <= 0 {
+		return fmt.Errorf("--days must be positive")
+	}
+
+	output.StartSpinner("Connecting to GitLab...")
+
+	client, err := newClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to connect to GitLab")
+		return err
+	}
+
+	var projects []string
+	projectID := ""
+	if group != "" {
+		output.UpdateSpinner(fmt.Sprintf("Listing projects in %s...", group))
+		projects, err = client.ListGroupProjects(cmd.Context(), group)
+		if err != nil {
+			output.SpinnerError("Failed to list group projects")
+			return err
+		}
+	} else {
+		projectID, err = resolveProject(cmd)
+		if err != nil {
+			output.SpinnerError("No project given")
+			return err
+		}
+		projects = []string{projectID}
+	}
+
+	var quota *gitlabclient.MinutesQuota
+	if quotaFlag > 0 {
+		quota = &gitlabclient.MinutesQuota{Namespace: group, Limit: quotaFlag}
+		if group == "" {
+			quota.Namespace = projectID
+		}
+	} else {
+		output.UpdateSpinner("Reading minutes quota...")
+		quota, err = client.NamespaceQuota(cmd.Context(), projectID, group)
+		if err != nil {
+			// Reading group limits needs admin access; usage is still useful without them
+			quota = nil
+		}
+	}
+
+	now := time.Now()
+	windowStart := now.AddDate(0, 0, -days)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	output.UpdateSpinner(fmt.Sprintf("Summing job durations in %d projects...", len(projects)))
+	usages := make([]gitlabclient.MinutesUsage, len(projects))
+	errs := batch.Run(len(projects), batch.DefaultWorkers, func(i int) error {
+		usage, err := client.ProjectMinutes(cmd.Context(), projects[i], windowStart, monthStart, costFactor)
+		if err != nil {
+			return err
+		}
+		usages[i] = *usage
+		return nil
+	})
+	if err := batch.Collect(projects, errs); err != nil {
+		output.SpinnerError("Failed to read job durations")
+		return err
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].MonthToDate > usages[j].MonthToDate
+	})
+	report := gitlabclient.NewMinutesReport(quota, usages, days, now)
+
+	output.SpinnerSuccess(fmt.Sprintf("%.0f minutes used this month", report.MonthToDate))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(report)
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "CI Minutes (shared runners)",
+		Headers:    []string{"Project", "This Month", fmt.Sprintf("Last %dd", days), "Jobs", "Share"},
+		ShowBorder: true,
+	})
+
+	for _, u := range report.Projects {
+		if u.Jobs == 0 && group != "" {
+			continue
+		}
+		share := 0.0
+		if report.MonthToDate > 0 {
+			share = u.MonthToDate / report.MonthToDate * 100
+		}
+		table.AddColoredRow(
+			[]string{
+				u.Project,
+				fmt.Sprintf("%.0f", u.MonthToDate),
+				fmt.Sprintf("%.0f", u.Trailing),
+				fmt.Sprintf("%d", u.Jobs),
+				fmt.Sprintf("%.1f%%", share),
+			},
+			[]tablewriter.Colors{
+				{tablewriter.FgCyanColor},
+				{tablewriter.Bold},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgYellowColor},
+			},
+		)
+	}
+
+	table.Render()
+
+	output.Newline()
+	output.Print(output.Section("Quota"))
+	output.Printf("  %s\n", output.KeyValue("Used This Month", fmt.Sprintf("%.0f minutes", report.MonthToDate)))
+	output.Printf("  %s\n", output.KeyValue("Daily Rate", fmt.Sprintf("%.0f minutes/day (last %d days)", report.DailyRate, days)))
+	output.Printf("  %s\n", output.KeyValue("Resets", report.ResetsAt.Format("2006-01-02")))
+
+	if report.Quota == nil || report.Quota.Limit == 0 {
+		output.Newline()
+		output.Muted("  No quota found (unlimited, or reading group limits needs admin access); set --quota to forecast")
+		output.Newline()
+		return nil
+	}
+
+	used := int(report.MonthToDate)
+	if used > report.Quota.Limit {
+		used = report.Quota.Limit
+	}
+	output.Printf("  %s\n", output.KeyValue("Quota", fmt.Sprintf("%d minutes (%s)", report.Quota.Limit, report.Quota.Namespace)))
+	output.Printf("  %s\n", output.KeyValue("Remaining", fmt.Sprintf("%.0f minutes", report.Remaining)))
+	output.Printf("\n  Usage: %s\n", output.ProgressBar(used, report.Quota.Limit, 30))
+
+	output.Newline()
+	switch {
+	case report.Remaining == 0:
+		output.Printf("  %s Quota exhausted; jobs on shared runners will not start until %s\n",
+			output.ErrorStyle.Render(output.IconError), report.ResetsAt.Format("2006-01-02"))
+	case report.ExhaustedAt != nil:
+		output.Printf("  %s At the current rate the quota runs out on %s, %s before the reset\n",
+			output.WarningStyle.Render(output.IconWarning), report.ExhaustedAt.Format("2006-01-02"),
+			format.Days(report.ResetsAt.Sub(*report.ExhaustedAt)))
+	default:
+		output.Success("At the current rate the quota lasts until the monthly reset")
+	}
+	if group == "" {
+		output.Muted("  Only this project's usage is counted; other projects in the group share the quota")
+	}
+
+	output.Newline()
+	return nil
+}
+
@@ -2,11 +2,13 @@ package gitlab
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/resultcache"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
@@ -30,14 +32,20 @@ Features:
 	cmd.Flags().StringP("ref", "r", "", "Filter by branch/tag ref")
 	cmd.Flags().IntP("limit", "n", 20, "Number of pipelines to show")
 	cmd.Flags().Bool("all", false, "Show pipelines from all branches")
+	cmd.Flags().Duration("cache", 0, "Serve the last result up to this old, marked STALE, when GitLab is unreachable")
+	cmd.Flags().BoolP("quiet", "q", false, "Only print pipeline IDs")
 
 	// Register flag completions
 	_ = cmd.RegisterFlagCompletionFunc("status", completion.PipelineStatusCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("ref", completion.BranchCompletion)
 
-	return cmd
+	return output.Watchable(cmd)
 }
 
 func runPipelines(cmd *cobra.Command, args []string) error {
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		output.SetQuiet()
+	}
 	output.StartSpinner("Fetching pipelines...")
 
 	client, projectID, err := getClient(cmd)
@@ -49,20 +57,44 @@ func runPipelines(cmd *cobra.Command, args []string) error {
 	status, _ := cmd.Flags().GetString("status")
 	ref, _ := cmd.Flags().GetString("ref")
 	limit, _ := cmd.Flags().GetInt("limit")
+	cacheAge, _ := cmd.Flags().GetDuration("cache")
 
-	pipelines, err := client.ListPipelines(projectID, gitlabclient.PipelineFilter{
+	cacheKey := resultcache.Key("gitlab pipelines", client.Host(), projectID, status, ref, fmt.Sprint(limit))
+	pipelines, err := client.ListPipelines(cmd.Context(), projectID, gitlabclient.PipelineFilter{
 		Status: status,
 		Ref:    ref,
 		Limit:  limit,
 	})
+	stale := ""
 	if err != nil {
-		output.SpinnerError("Failed to fetch pipelines")
-		return fmt.Errorf("failed to list pipelines: %w", err)
+		if stale = resultcache.Fallback(cacheKey, cacheAge, &pipelines, err); stale == "" {
+			output.SpinnerError("Failed to fetch pipelines")
+			return fmt.Errorf("failed to list pipelines: %w", err)
+		}
+	} else if cacheAge > 0 {
+		_ = resultcache.Save(cacheKey, pipelines)
 	}
 
-	output.SpinnerSuccess(fmt.Sprintf("Found %d pipelines", len(pipelines)))
+	if stale != "" {
+		output.StopSpinner()
+		output.Warning(stale)
+	} else {
+		output.SpinnerSuccess(fmt.Sprintf("Found %d pipelines", len(pipelines)))
+	}
 	output.Newline()
 
+	if output.IsQuiet() {
+		ids := make([]string, len(pipelines))
+		for i, p := range pipelines {
+			ids[i] = strconv.Itoa(p.ID)
+		}
+		return output.PrintIDs(ids)
+	}
+
+	if output.IsStructured() {
+		return output.RenderStale(pipelines, stale != "")
+	}
+
 	if len(pipelines) == 0 {
 		output.Info("No pipelines found matching the criteria")
 		return nil
@@ -89,10 +121,7 @@ func runPipelines(cmd *cobra.Command, args []string) error {
 			commit = commit[:8]
 		}
 
-		ref := pl.Ref
-		if len(ref) > 20 {
-			ref = ref[:17] + "..."
-		}
+		ref := output.Truncate(pl.Ref, 20)
 
 		table.AddColoredRow(
 			[]string{
@@ -100,7 +129,7 @@ func runPipelines(cmd *cobra.Command, args []string) error {
 				status,
 				ref,
 				commit,
-				formatDuration(pl.CreatedAt),
+				pl.CreatedAt,
 				pl.Duration,
 			},
 			getPipelineRowColors(pl.Status),
@@ -164,12 +193,3 @@ func getPipelineRowColors(status string) []tablewriter.Colors {
 		{tablewriter.FgWhiteColor},      // Duration
 	}
 }
-
-func formatDuration(timeStr string) string {
-	// Parse and format time
-	if timeStr == "" {
-		return "-"
-	}
-	// Simplified - just return the time string
-	return timeStr
-}
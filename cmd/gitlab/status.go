@@ -1,11 +1,15 @@
 package gitlab
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/secrets"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -39,7 +43,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get project info
-	project, err := client.GetProject(projectID)
+	project, err := client.GetProject(cmd.Context(), projectID)
 	if err != nil {
 		output.SpinnerError("Failed to fetch project")
 		return fmt.Errorf("failed to get project: %w", err)
@@ -57,7 +61,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	output.Newline()
 	output.Print(output.Section("Latest Pipeline"))
 
-	latestPipeline, err := client.GetLatestPipeline(projectID, project.DefaultBranch)
+	latestPipeline, err := client.GetLatestPipeline(cmd.Context(), projectID, project.DefaultBranch)
 	if err != nil {
 		output.Warning("No pipelines found")
 	} else {
@@ -68,7 +72,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		output.Printf("     Duration: %s\n", latestPipeline.Duration)
 
 		// Get jobs for this pipeline
-		jobs, _ := client.ListPipelineJobs(projectID, latestPipeline.ID, gitlabclient.JobFilter{})
+		jobs, _ := client.ListPipelineJobs(cmd.Context(), projectID, latestPipeline.ID, gitlabclient.JobFilter{})
 		if len(jobs) > 0 {
 			output.Newline()
 			output.Print(output.SubSection("Jobs"))
@@ -84,7 +88,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	output.Newline()
 	output.Print(output.Section("Pipeline Statistics (Last 30 Days)"))
 
-	stats, err := client.GetPipelineStats(projectID)
+	stats, err := client.GetPipelineStats(cmd.Context(), projectID)
 	if err == nil {
 		total := stats.Success + stats.Failed + stats.Other
 		successRate := float64(0)
@@ -112,7 +116,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	output.Newline()
 	output.Print(output.Section("Environments"))
 
-	environments, err := client.ListEnvironments(projectID)
+	environments, err := client.ListEnvironments(cmd.Context(), projectID)
 	if err == nil && len(environments) > 0 {
 		for _, env := range environments {
 			icon := output.SuccessStyle.Render(output.IconSuccess)
@@ -138,6 +142,41 @@ func runStatus(cmd *cobra.Command, args []string) error {
 }
 
 func getClient(cmd *cobra.Command) (*gitlabclient.Client, string, error) {
+	client, err := newClient(cmd)
+	if err != nil {
+		return nil, "", err
+	}
+
+	projectID, err := resolveProject(cmd)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return client, projectID, nil
+}
+
+// resolveProject returns the project from the flag, environment, config or git remote
+func resolveProject(cmd *cobra.Command) (string, error) {
+	projectID := cmd.Flag("project").Value.String()
+	if projectID == "" {
+		projectID = os.Getenv("GITLAB_PROJECT")
+	}
+	if projectID == "" {
+		projectID = viper.GetString("gitlab.project")
+	}
+	if projectID == "" {
+		// Try to detect from git remote
+		projectID = detectProjectFromGit()
+	}
+	if projectID == "" {
+		return "", exitcode.ConfigError(fmt.Errorf("project ID required (use --project flag or GITLAB_PROJECT env)"))
+	}
+
+	return projectID, nil
+}
+
+// newClient creates a GitLab client from the token and URL flags, environment or config
+func newClient(cmd *cobra.Command) (*gitlabclient.Client, error) {
 	token := cmd.Flag("token").Value.String()
 	if token == "" {
 		token = os.Getenv("GITLAB_TOKEN")
@@ -146,10 +185,18 @@ func getClient(cmd *cobra.Command) (*gitlabclient.Client, string, error) {
 		token = viper.GetString("gitlab.token")
 	}
 	if token == "" {
-		return nil, "", fmt.Errorf("GitLab token required (use --token flag or GITLAB_TOKEN env)")
+		return nil, exitcode.ConfigError(fmt.Errorf("GitLab token required (use --token flag or GITLAB_TOKEN env)"))
+	}
+	token, err := secrets.Resolve(token)
+	if err != nil {
+		return nil, exitcode.ConfigError(err)
 	}
 
-	url := cmd.Flag("url").Value.String()
+	// The flag has a default, so only an explicit value takes precedence
+	var url string
+	if cmd.Flags().Changed("url") {
+		url = cmd.Flag("url").Value.String()
+	}
 	if url == "" {
 		url = os.Getenv("GITLAB_URL")
 	}
@@ -160,27 +207,43 @@ func getClient(cmd *cobra.Command) (*gitlabclient.Client, string, error) {
 		url = "https://gitlab.com"
 	}
 
-	projectID := cmd.Flag("project").Value.String()
-	if projectID == "" {
-		projectID = os.Getenv("GITLAB_PROJECT")
+	client, err := gitlabclient.NewClient(url, token)
+	if err != nil {
+		return nil, err
 	}
-	if projectID == "" {
-		projectID = viper.GetString("gitlab.project")
+	if err := checkToken(cmd, client); err != nil {
+		return nil, err
 	}
-	if projectID == "" {
-		// Try to detect from git remote
-		projectID = detectProjectFromGit()
+	return client, nil
+}
+
+// scopeAnnotation marks commands that need a token scope other than read_api
+const scopeAnnotation = "gitlab_scope"
+
+// checkToken verifies the token has the scope the command needs and warns when it
+// expires soon. Tokens that cannot inspect themselves, like CI job tokens, are not checked.
+func checkToken(cmd *cobra.Command, client *gitlabclient.Client) error {
+	if skip, _ := cmd.Flags().GetBool("skip-token-check"); skip {
+		return nil
 	}
-	if projectID == "" {
-		return nil, "", fmt.Errorf("project ID required (use --project flag or GITLAB_PROJECT env)")
+	warnDays, _ := cmd.Flags().GetInt("token-expiry-warning")
+
+	required := gitlabclient.ScopeReadAPI
+	if scope, ok := cmd.Annotations[scopeAnnotation]; ok {
+		required = scope
 	}
 
-	client, err := gitlabclient.NewClient(url, token)
+	warnings, err := client.CheckToken(cmd.Context(), required, time.Duration(warnDays)*24*time.Hour)
+	if errors.Is(err, gitlabclient.ErrTokenUncheckable) {
+		return nil
+	}
 	if err != nil {
-		return nil, "", err
+		return err
 	}
-
-	return client, projectID, nil
+	for _, w := range warnings {
+		output.SpinnerWarning(w)
+	}
+	return nil
 }
 
 func detectProjectFromGit() string {
@@ -1,8 +1,14 @@
 package gitlab
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/audit"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/notify"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -15,15 +21,21 @@ func newTriggerCmd() *cobra.Command {
 
 Examples:
   devops-toolkit gitlab trigger -p myproject -r main
-  devops-toolkit gitlab trigger -p myproject -r main -v KEY=value`,
-		RunE: runTrigger,
+  devops-toolkit gitlab trigger -p myproject -r main -v KEY=value
+  devops-toolkit gitlab trigger -p myproject -r main --validate-first`,
+		RunE:        runTrigger,
+		Annotations: map[string]string{scopeAnnotation: gitlabclient.ScopeAPI},
 	}
 
 	cmd.Flags().StringP("ref", "r", "", "Branch or tag to run pipeline on (required)")
 	cmd.Flags().StringArrayP("variable", "v", nil, "Pipeline variables (KEY=value)")
 	cmd.Flags().Bool("wait", false, "Wait for pipeline to complete")
+	cmd.Flags().Bool("notify", false, "Post the final pipeline status to the configured Slack/Teams/webhook sinks (with --wait)")
+	cmd.Flags().Bool("validate-first", false, "Lint the local CI configuration on --ref and refuse to trigger if it is invalid")
+	cmd.Flags().String("ci-file", defaultCIFile, "CI configuration linted by --validate-first")
 
 	_ = cmd.MarkFlagRequired("ref")
+	_ = cmd.RegisterFlagCompletionFunc("ref", completion.BranchCompletion)
 
 	return cmd
 }
@@ -32,6 +44,12 @@ func runTrigger(cmd *cobra.Command, args []string) error {
 	ref, _ := cmd.Flags().GetString("ref")
 	variables, _ := cmd.Flags().GetStringArray("variable")
 	wait, _ := cmd.Flags().GetBool("wait")
+	notifyResult, _ := cmd.Flags().GetBool("notify")
+	validateFirst, _ := cmd.Flags().GetBool("validate-first")
+	ciFile, _ := cmd.Flags().GetString("ci-file")
+	if notifyResult && !wait {
+		return exitcode.ConfigError(fmt.Errorf("--notify requires --wait"))
+	}
 
 	output.StartSpinner(fmt.Sprintf("Triggering pipeline on %s...", ref))
 
@@ -41,6 +59,23 @@ func runTrigger(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if validateFirst {
+		output.UpdateSpinner(fmt.Sprintf("Linting %s...", ciFile))
+		result, err := lintFile(cmd.Context(), client, projectID, ciFile, ref, true)
+		if err != nil {
+			output.SpinnerError("Lint failed")
+			return err
+		}
+		if !result.Valid {
+			output.SpinnerError(fmt.Sprintf("%s is invalid, pipeline not triggered", ciFile))
+			output.Newline()
+			printLintResult(result)
+			cmd.SilenceUsage = true
+			return lintError(ciFile, result)
+		}
+		output.UpdateSpinner(fmt.Sprintf("Triggering pipeline on %s...", ref))
+	}
+
 	// Parse variables
 	vars := make(map[string]string)
 	for _, v := range variables {
@@ -50,7 +85,30 @@ func runTrigger(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	pipeline, err := client.TriggerPipeline(projectID, ref, vars)
+	pipeline, err := client.TriggerPipeline(cmd.Context(), projectID, ref, vars)
+
+	// Variable values may be secrets, so only their names are audited
+	resources := []string{"ref " + ref}
+	if pipeline != nil {
+		resources[0] = fmt.Sprintf("pipeline #%d on %s", pipeline.ID, ref)
+	}
+	for k := range vars {
+		resources = append(resources, "variable "+k)
+	}
+	result := audit.ResultSuccess
+	if err != nil {
+		result = audit.ResultFailed
+	}
+	if auditErr := audit.Record(audit.Entry{
+		Command:   "gitlab trigger",
+		Action:    "trigger-pipeline",
+		Target:    client.Host() + "/" + projectID,
+		Resources: resources,
+		Result:    result,
+	}, err); auditErr != nil {
+		output.Warning(auditErr.Error())
+	}
+
 	if err != nil {
 		output.SpinnerError("Failed to trigger pipeline")
 		return fmt.Errorf("failed to trigger pipeline: %w", err)
@@ -79,7 +137,7 @@ func runTrigger(cmd *cobra.Command, args []string) error {
 	if wait {
 		output.StartSpinner("Waiting for pipeline to complete...")
 
-		finalPipeline, err := client.WaitForPipeline(projectID, pipeline.ID)
+		finalPipeline, err := client.WaitForPipeline(cmd.Context(), projectID, pipeline.ID)
 		if err != nil {
 			output.SpinnerError("Error waiting for pipeline")
 			return err
@@ -94,6 +152,13 @@ func runTrigger(cmd *cobra.Command, args []string) error {
 			output.StopSpinner()
 			output.Warning(fmt.Sprintf("Pipeline ended with status: %s", finalPipeline.Status))
 		}
+
+		if notifyResult {
+			sendPipelineNotification(cmd.Context(), projectID, finalPipeline)
+		}
+		if finalPipeline.Status == "failed" {
+			return exitcode.ChecksFailedf("pipeline #%d failed", finalPipeline.ID)
+		}
 	}
 
 	return nil
@@ -107,3 +172,43 @@ func splitVar(v string) []string {
 	}
 	return []string{v}
 }
+
+// sendPipelineNotification posts the final pipeline status
+func sendPipelineNotification(ctx context.Context, projectID string, pipeline *gitlabclient.PipelineInfo) {
+	sinks, err := notify.Sinks()
+	if err != nil {
+		output.Warning(fmt.Sprintf("Notification failed: %v", err))
+		return
+	}
+	if len(sinks) == 0 {
+		output.Warning("No notification sinks configured (set notify.slack_webhook_url, SLACK_WEBHOOK_URL, ...)")
+		return
+	}
+
+	status := notify.StatusFailure
+	if pipeline.Status == "success" || pipeline.Status == "passed" {
+		status = notify.StatusSuccess
+	}
+
+	msg := notify.Message{
+		Title:  fmt.Sprintf("Pipeline #%d on %s: %s", pipeline.ID, pipeline.Ref, pipeline.Status),
+		Status: status,
+		Link:   pipeline.WebURL,
+		Fields: []notify.Field{
+			{Name: "Project", Value: projectID},
+			{Name: "Ref", Value: pipeline.Ref},
+			{Name: "Commit", Value: shortSHA(pipeline.SHA)},
+			{Name: "Duration", Value: pipeline.Duration},
+		},
+	}
+	if err := notify.Send(ctx, sinks, msg); err != nil {
+		output.Warning(fmt.Sprintf("Notification failed: %v", err))
+	}
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
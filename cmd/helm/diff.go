@@ -0,0 +1,108 @@
+package helm
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/helm"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff RELEASE",
+		Short: "Show user-supplied values changes between revisions",
+		Long: `Show how the user-supplied values of a release changed between two
+revisions. By default the latest revision is compared with the one before it.`,
+		Example: `  devops-toolkit helm diff api -n payments
+  devops-toolkit helm diff api -n payments --from 3 --to 5`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.HelmReleaseCompletion,
+		RunE:              runDiff,
+	}
+
+	cmd.Flags().Int("from", 0, "Base revision (default: the revision before --to)")
+	cmd.Flags().Int("to", 0, "Target revision (default: latest)")
+
+	return cmd
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	output.StartSpinner("Fetching release revisions...")
+
+	client, err := getClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return err
+	}
+
+	ctx := cmd.Context()
+	namespace := releaseNamespace(cmd)
+	fromRev, _ := cmd.Flags().GetInt("from")
+	toRev, _ := cmd.Flags().GetInt("to")
+
+	to, err := client.GetRevision(ctx, namespace, args[0], toRev)
+	if err != nil {
+		output.SpinnerError("Failed to fetch release")
+		return err
+	}
+
+	if fromRev == 0 {
+		fromRev = to.Revision - 1
+	}
+	if fromRev < 1 {
+		output.StopSpinner()
+		return fmt.Errorf("release %q has no revision before %d to compare with", args[0], to.Revision)
+	}
+
+	from, err := client.GetRevision(ctx, namespace, args[0], fromRev)
+	if err != nil {
+		output.SpinnerError("Failed to fetch release")
+		return err
+	}
+
+	changes := helm.DiffValues(from.Values, to.Values)
+	output.SpinnerSuccess(fmt.Sprintf("Compared revision %d with %d", from.Revision, to.Revision))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(changes)
+	}
+
+	output.Header(fmt.Sprintf("Values: %s revision %d → %d", args[0], from.Revision, to.Revision))
+	if from.ChartVersion != to.ChartVersion {
+		output.Printf("  %s\n", output.KeyValue("Chart", fmt.Sprintf("%s-%s → %s-%s", from.Chart, from.ChartVersion, to.Chart, to.ChartVersion)))
+		output.Newline()
+	}
+
+	if len(changes) == 0 {
+		output.Success("No user-supplied values changed")
+		return nil
+	}
+
+	for _, c := range changes {
+		switch c.Type {
+		case "added":
+			output.Printf("  %s %s: %s\n", output.SuccessStyle.Render("+"), c.Key, formatValue(c.New))
+		case "removed":
+			output.Printf("  %s %s: %s\n", output.ErrorStyle.Render("-"), c.Key, formatValue(c.Old))
+		default:
+			output.Printf("  %s %s: %s %s %s\n", output.WarningStyle.Render("~"), c.Key,
+				formatValue(c.Old), output.MutedStyle.Render(output.IconArrow), formatValue(c.New))
+		}
+	}
+
+	output.Newline()
+	return nil
+}
+
+// formatValue renders a values entry compactly as JSON
+func formatValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
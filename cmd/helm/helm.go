@@ -0,0 +1,59 @@
+package helm
+
+import (
+	"fmt"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/helm"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+// NewHelmCmd creates the helm command
+func NewHelmCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "helm",
+		Short: "Helm release management",
+		Long: `Helm release management and troubleshooting.
+
+Reads releases straight from the cluster's Helm storage, so no helm
+binary is needed except for rollback.`,
+	}
+
+	// Add subcommands
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newHistoryCmd())
+	cmd.AddCommand(newDiffCmd())
+	cmd.AddCommand(newRollbackCmd())
+
+	// Persistent flags for helm commands
+	cmd.PersistentFlags().StringP("namespace", "n", "", "Kubernetes namespace (default: all namespaces for list, default otherwise)")
+	cmd.PersistentFlags().StringP("context", "c", "", "Kubernetes context to use")
+	cmd.PersistentFlags().String("kubeconfig", "", "Path to kubeconfig file")
+
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("namespace", completion.NamespaceCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("context", completion.ContextCompletion)
+
+	return cmd
+}
+
+func getClient(cmd *cobra.Command) (*helm.Client, error) {
+	kubeconfig := cmd.Flag("kubeconfig").Value.String()
+	kubeContext := cmd.Flag("context").Value.String()
+
+	kc, err := k8s.NewClient(kubeconfig, kubeContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	return helm.NewClient(kc, kubeconfig, kubeContext)
+}
+
+// releaseNamespace returns the namespace for single-release commands
+func releaseNamespace(cmd *cobra.Command) string {
+	if ns := cmd.Flag("namespace").Value.String(); ns != "" {
+		return ns
+	}
+	return "default"
+}
@@ -0,0 +1,82 @@
+package helm
+
+import (
+	"fmt"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/helm"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "history RELEASE",
+		Short:             "Show the revision history of a release",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.HelmReleaseCompletion,
+		RunE:              runHistory,
+	}
+
+	return cmd
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	output.StartSpinner("Fetching release history...")
+
+	client, err := getClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return err
+	}
+
+	namespace := releaseNamespace(cmd)
+	history, err := client.History(cmd.Context(), namespace, args[0])
+	if err != nil {
+		output.SpinnerError("Failed to fetch release history")
+		return err
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Found %d revisions", len(history)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(history)
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      fmt.Sprintf("Release History: %s/%s", namespace, args[0]),
+		Headers:    []string{"Revision", "Updated", "Status", "Chart", "App Version", "Description"},
+		ShowBorder: true,
+	})
+
+	for _, rel := range history {
+		row := []string{
+			fmt.Sprintf("%d", rel.Revision),
+			format.Timestamp(rel.Updated),
+			rel.Status,
+			rel.Chart + "-" + rel.ChartVersion,
+			rel.AppVersion,
+			output.Truncate(rel.Description, 50),
+		}
+
+		statusColor := tablewriter.Colors{tablewriter.FgHiBlackColor}
+		switch {
+		case rel.Status == helm.StatusDeployed:
+			statusColor = tablewriter.Colors{tablewriter.FgGreenColor, tablewriter.Bold}
+		case rel.Status == helm.StatusFailed:
+			statusColor = tablewriter.Colors{tablewriter.FgRedColor, tablewriter.Bold}
+		case rel.IsPending():
+			statusColor = tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold}
+		}
+
+		table.AddColoredRow(row, []tablewriter.Colors{{}, {tablewriter.FgHiBlackColor}, statusColor, {}, {}, {}})
+	}
+
+	table.Render()
+	output.Newline()
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package helm
+
+import (
+	"fmt"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/helm"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls", "releases"},
+		Short:   "List Helm releases with status and drift",
+		Long: `List Helm releases with their status.
+
+Features:
+  • Failed and stuck (pending-*) releases highlighted
+  • Optional drift check against live cluster resources
+    (deleted resources, container images changed outside Helm)`,
+		Example: `  devops-toolkit helm list
+  devops-toolkit helm list -n payments --drift
+  devops-toolkit helm list --problems`,
+		RunE: runList,
+	}
+
+	cmd.Flags().Bool("problems", false, "Show only failed or pending releases")
+	cmd.Flags().Bool("drift", false, "Compare release manifests with live resources")
+
+	return cmd
+}
+
+// releaseRow is a release with its drift, for structured output
+type releaseRow struct {
+	helm.Release
+	Drift []helm.DriftItem `json:"drift,omitempty"`
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	output.StartSpinner("Fetching Helm releases...")
+
+	client, err := getClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return err
+	}
+
+	ctx := cmd.Context()
+	namespace := cmd.Flag("namespace").Value.String()
+	problemsOnly, _ := cmd.Flags().GetBool("problems")
+	checkDrift, _ := cmd.Flags().GetBool("drift")
+
+	releases, err := client.ListReleases(ctx, namespace)
+	if err != nil {
+		output.SpinnerError("Failed to fetch releases")
+		return fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	if problemsOnly {
+		var filtered []helm.Release
+		for _, rel := range releases {
+			if rel.IsProblem() {
+				filtered = append(filtered, rel)
+			}
+		}
+		releases = filtered
+	}
+
+	rows := make([]releaseRow, 0, len(releases))
+	for _, rel := range releases {
+		row := releaseRow{Release: rel}
+		if checkDrift {
+			output.UpdateSpinner(fmt.Sprintf("Checking drift of %s/%s...", rel.Namespace, rel.Name))
+			drift, err := client.CheckDrift(ctx, rel)
+			if err != nil {
+				output.SpinnerError("Failed to check drift")
+				return fmt.Errorf("failed to check drift of %s: %w", rel.Name, err)
+			}
+			row.Drift = drift
+		}
+		row.Values = nil
+		rows = append(rows, row)
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Found %d releases", len(rows)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(rows)
+	}
+
+	if len(rows) == 0 {
+		if problemsOnly {
+			output.Success("No failed or pending releases found!")
+		} else {
+			output.Info("No Helm releases found")
+		}
+		return nil
+	}
+
+	headers := []string{"Namespace", "Name", "Revision", "Status", "Chart", "App Version", "Updated"}
+	if checkDrift {
+		headers = append(headers, "Drift")
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Helm Releases",
+		Headers:    headers,
+		ShowBorder: true,
+	})
+
+	var problems, drifted int
+	for _, r := range rows {
+		row := []string{
+			r.Namespace,
+			r.Name,
+			fmt.Sprintf("%d", r.Revision),
+			r.Status,
+			r.Chart + "-" + r.ChartVersion,
+			r.AppVersion,
+			format.Age(r.Updated),
+		}
+		if checkDrift {
+			drift := "none"
+			if len(r.Drift) > 0 {
+				drift = fmt.Sprintf("%d resources", len(r.Drift))
+				drifted++
+			}
+			row = append(row, drift)
+		}
+		if r.IsProblem() {
+			problems++
+		}
+
+		table.AddColoredRow(row, releaseRowColors(r, checkDrift))
+	}
+
+	table.Render()
+
+	// Drift details
+	if drifted > 0 {
+		output.Newline()
+		output.Print(output.Section("Drift"))
+		for _, r := range rows {
+			for _, d := range r.Drift {
+				output.Printf("  %s %s/%s %s/%s: %s\n",
+					output.WarningStyle.Render(output.IconWarning),
+					r.Namespace, r.Name, d.Kind, d.Name, d.Issue)
+			}
+		}
+	}
+
+	output.Newline()
+	if problems > 0 {
+		output.Warning(fmt.Sprintf("%d releases are failed or stuck pending; inspect with 'helm history' and consider 'helm rollback'", problems))
+	} else {
+		output.Success("All releases deployed")
+	}
+
+	return nil
+}
+
+func releaseRowColors(r releaseRow, withDrift bool) []tablewriter.Colors {
+	statusColor := tablewriter.Colors{tablewriter.FgGreenColor}
+	switch {
+	case r.Status == helm.StatusFailed:
+		statusColor = tablewriter.Colors{tablewriter.FgRedColor, tablewriter.Bold}
+	case r.IsPending():
+		statusColor = tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold}
+	case r.Status != helm.StatusDeployed:
+		statusColor = tablewriter.Colors{tablewriter.FgHiBlackColor}
+	}
+
+	colors := []tablewriter.Colors{
+		{tablewriter.FgHiBlackColor},
+		{tablewriter.FgCyanColor},
+		{},
+		statusColor,
+		{},
+		{},
+		{tablewriter.FgHiBlackColor},
+	}
+	if withDrift {
+		driftColor := tablewriter.Colors{tablewriter.FgGreenColor}
+		if len(r.Drift) > 0 {
+			driftColor = tablewriter.Colors{tablewriter.FgYellowColor}
+		}
+		colors = append(colors, driftColor)
+	}
+	return colors
+}
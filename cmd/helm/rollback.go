@@ -0,0 +1,88 @@
+package helm
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/audit"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newRollbackCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback RELEASE [REVISION]",
+		Short: "Roll a release back to a previous revision",
+		Long: `Roll a release back to a previous revision (default: the one before
+the current revision) and wait for its resources to become ready. Bounded by
+--timeout when set, otherwise five minutes like the helm CLI.`,
+		Example: `  devops-toolkit helm rollback api -n payments
+  devops-toolkit helm rollback api 3 -n payments --dry-run`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completion.HelmReleaseCompletion,
+		RunE:              runRollback,
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Simulate the rollback")
+
+	return cmd
+}
+
+func runRollback(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	revision := 0
+	if len(args) == 2 {
+		rev, err := strconv.Atoi(args[1])
+		if err != nil || rev < 1 {
+			return exitcode.ConfigError(fmt.Errorf("invalid revision %q", args[1]))
+		}
+		revision = rev
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	namespace := releaseNamespace(cmd)
+
+	client, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	current, err := client.GetRevision(ctx, namespace, name, 0)
+	if err != nil {
+		return err
+	}
+
+	target := "previous revision"
+	if revision > 0 {
+		target = fmt.Sprintf("revision %d", revision)
+	}
+	output.StartSpinner(fmt.Sprintf("Rolling back %s/%s (revision %d) to %s...", namespace, name, current.Revision, target))
+
+	err = client.Rollback(ctx, namespace, name, revision, dryRun)
+
+	entry := audit.Entry{
+		Command:   "helm rollback",
+		Action:    "rollback-release",
+		Target:    client.Server(),
+		Resources: []string{fmt.Sprintf("%s/%s revision %d to %s", namespace, name, current.Revision, target)},
+		DryRun:    dryRun,
+		Result:    audit.Result(dryRun, 0, 1, err),
+	}
+	if auditErr := audit.Record(entry, err); auditErr != nil {
+		output.Warning(auditErr.Error())
+	}
+
+	if err != nil {
+		output.SpinnerError("Rollback failed")
+		return err
+	}
+
+	if dryRun {
+		output.SpinnerSuccess("Dry-run rollback succeeded")
+	} else {
+		output.SpinnerSuccess(fmt.Sprintf("Rolled back %s to %s", name, target))
+	}
+	return nil
+}
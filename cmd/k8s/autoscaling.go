@@ -0,0 +1,247 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newAutoscalingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "autoscaling",
+		Short: "Show node autoscaler status, scale-up blockers and pending pods",
+		Long: `Show what the node autoscaler is doing and why pods are still pending.
+
+Reads the cluster-autoscaler status config map (kube-system/cluster-autoscaler-status)
+and Karpenter NodeClaims, whichever are installed, and shows:
+
+  • Node group health, size limits and backoff (cluster-autoscaler)
+  • NodeClaims that have not become ready nodes (Karpenter)
+  • Unschedulable pods with the latest autoscaler event about each
+  • Recent scale-up, scale-down and disruption events
+  • Blockers: groups at max size or in backoff, stuck NodeClaims and
+    NotTriggerScaleUp reasons
+
+Examples:
+  devops-toolkit k8s autoscaling
+  devops-toolkit k8s autoscaling -n batch --since 3h
+  devops-toolkit k8s autoscaling --output json`,
+		Args: cobra.NoArgs,
+		RunE: runAutoscaling,
+	}
+
+	cmd.Flags().Duration("since", time.Hour, "How far back to look for scale events")
+	cmd.Flags().Int("limit", 20, "Maximum number of scale events to show")
+	cmd.Flags().Bool("all-claims", false, "Show ready NodeClaims too, not only those still provisioning")
+
+	return cmd
+}
+
+func runAutoscaling(cmd *cobra.Command, args []string) error {
+	since, _ := cmd.Flags().GetDuration("since")
+	limit, _ := cmd.Flags().GetInt("limit")
+	allClaims, _ := cmd.Flags().GetBool("all-claims")
+
+	output.StartSpinner("Reading autoscaler status...")
+
+	client, err := k8s.NewClient(
+		cmd.Flag("kubeconfig").Value.String(),
+		cmd.Flag("context").Value.String(),
+	)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return err
+	}
+
+	report, err := client.GetAutoscaling(cmd.Context(), cmd.Flag("namespace").Value.String(), since)
+	if err != nil {
+		output.SpinnerError("Failed to read autoscaler status")
+		return err
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Found %d pending pods and %d scale events", len(report.PendingPods), len(report.Events)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(report)
+	}
+
+	output.Print(output.Section("Node Autoscaling"))
+	autoscalers := "none found"
+	if len(report.Autoscalers) > 0 {
+		autoscalers = strings.Join(report.Autoscalers, ", ")
+	}
+	output.Printf("  %s\n", output.KeyValue("Autoscaler", autoscalers))
+	if ca := report.ClusterAutoscaler; ca != nil {
+		output.Printf("  %s\n", output.KeyValue("Health", ca.Health))
+		output.Printf("  %s\n", output.KeyValue("Scale up", ca.ScaleUp))
+		output.Printf("  %s\n", output.KeyValue("Scale down", ca.ScaleDown))
+		if ca.Time != "" {
+			output.Printf("  %s\n", output.KeyValue("Updated", ca.Time))
+		}
+	}
+	output.Newline()
+
+	if ca := report.ClusterAutoscaler; ca != nil && len(ca.NodeGroups) > 0 {
+		displayNodeGroups(ca.NodeGroups)
+	}
+	if len(report.NodeClaims) > 0 {
+		displayNodeClaims(report.NodeClaims, allClaims)
+	}
+
+	if len(report.PendingPods) > 0 {
+		table := output.NewTable(output.TableConfig{
+			Title:      "Unschedulable Pods",
+			Headers:    []string{"Namespace", "Pod", "Pending", "Scheduler", "Autoscaler"},
+			ShowBorder: true,
+		})
+		for _, p := range report.PendingPods {
+			autoscaler := p.Autoscaler
+			if autoscaler == "" {
+				autoscaler = "-"
+			}
+			table.AddColoredRow(
+				[]string{p.Namespace, p.Name, format.Age(p.Since), output.Truncate(p.Reason, 50), output.Truncate(autoscaler, 50)},
+				[]tablewriter.Colors{
+					{tablewriter.FgHiBlackColor},
+					{tablewriter.FgCyanColor},
+					{tablewriter.FgYellowColor},
+					{tablewriter.FgWhiteColor},
+					{tablewriter.FgWhiteColor},
+				},
+			)
+		}
+		table.Render()
+		output.Newline()
+	}
+
+	if len(report.Events) > 0 {
+		events := report.Events
+		if limit > 0 && len(events) > limit {
+			events = events[:limit]
+		}
+		table := output.NewTable(output.TableConfig{
+			Title:      "Scale Events",
+			Headers:    []string{"Age", "Reason", "Object", "Message"},
+			ShowBorder: true,
+		})
+		for _, e := range events {
+			reasonColor := tablewriter.FgGreenColor
+			if e.Type == "Warning" {
+				reasonColor = tablewriter.FgYellowColor
+			}
+			object := strings.ToLower(e.Kind) + "/" + e.Object
+			if e.Kind == "Pod" {
+				object = e.Namespace + "/" + e.Object
+			}
+			table.AddColoredRow(
+				[]string{format.Age(e.LastTimestamp), e.Reason, output.Truncate(object, 40), output.Truncate(e.Message, 60)},
+				[]tablewriter.Colors{
+					{tablewriter.FgHiBlackColor},
+					{reasonColor},
+					{tablewriter.FgCyanColor},
+					{tablewriter.FgWhiteColor},
+				},
+			)
+		}
+		table.Render()
+		output.Newline()
+	}
+
+	if len(report.Blockers) > 0 {
+		output.Print(output.Section("Scale-up Blockers"))
+		for _, b := range report.Blockers {
+			output.Printf("  %s %s\n", output.ErrorStyle.Render(output.IconError), b)
+		}
+		output.Newline()
+	} else if len(report.PendingPods) == 0 {
+		output.Success("No unschedulable pods and no scale-up blockers")
+	}
+	return nil
+}
+
+func displayNodeGroups(groups []k8s.NodeGroupState) {
+	table := output.NewTable(output.TableConfig{
+		Title:      "Node Groups (cluster-autoscaler)",
+		Headers:    []string{"Name", "Health", "Ready", "Target", "Min", "Max", "Scale Up"},
+		ShowBorder: true,
+	})
+	for _, g := range groups {
+		healthColor := tablewriter.FgGreenColor
+		if g.Health != "Healthy" {
+			healthColor = tablewriter.FgRedColor
+		}
+		scaleUpColor := tablewriter.FgWhiteColor
+		switch {
+		case g.ScaleUp == "Backoff":
+			scaleUpColor = tablewriter.FgRedColor
+		case g.ScaleUp == "InProgress":
+			scaleUpColor = tablewriter.FgYellowColor
+		}
+		maxColor := tablewriter.FgWhiteColor
+		if g.AtMax() {
+			maxColor = tablewriter.FgYellowColor
+		}
+		table.AddColoredRow(
+			[]string{g.Name, g.Health, fmt.Sprintf("%d", g.Ready), fmt.Sprintf("%d", g.Target),
+				fmt.Sprintf("%d", g.MinSize), fmt.Sprintf("%d", g.MaxSize), g.ScaleUp},
+			[]tablewriter.Colors{
+				{tablewriter.FgCyanColor},
+				{healthColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgHiBlackColor},
+				{maxColor},
+				{scaleUpColor},
+			},
+		)
+	}
+	table.Render()
+	output.Newline()
+}
+
+func displayNodeClaims(claims []k8s.NodeClaimInfo, all bool) {
+	ready := 0
+	table := output.NewTable(output.TableConfig{
+		Title:      "NodeClaims (Karpenter)",
+		Headers:    []string{"Name", "NodePool", "Instance Type", "Node", "Phase", "Age", "Message"},
+		ShowBorder: true,
+	})
+	rows := 0
+	for _, c := range claims {
+		if c.Ready {
+			ready++
+			if !all {
+				continue
+			}
+		}
+		phaseColor := tablewriter.FgGreenColor
+		if !c.Ready {
+			phaseColor = tablewriter.FgYellowColor
+		}
+		table.AddColoredRow(
+			[]string{c.Name, c.NodePool, c.InstanceType, c.Node, c.Phase, format.Age(c.Created), output.Truncate(c.Message, 50)},
+			[]tablewriter.Colors{
+				{tablewriter.FgCyanColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgWhiteColor},
+				{phaseColor},
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgWhiteColor},
+			},
+		)
+		rows++
+	}
+	if rows > 0 {
+		table.Render()
+	}
+	output.Muted(fmt.Sprintf("  %d of %d NodeClaims ready", ready, len(claims)))
+	output.Newline()
+}
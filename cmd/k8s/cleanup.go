@@ -4,8 +4,13 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/audit"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/batch"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
@@ -20,7 +25,11 @@ Cleanup targets:
   • Evicted pods
   • Orphaned ReplicaSets
   • Completed Jobs
-  • Unused ConfigMaps/Secrets (optional)`,
+  • Unreferenced Secrets (optional, see k8s secrets-report)
+
+When completed Jobs pile up, cleanup suggests successfulJobsHistoryLimit and
+ttlSecondsAfterFinished settings (with kubectl patch commands for CronJobs)
+so they are garbage collected automatically.`,
 		RunE: runCleanup,
 	}
 
@@ -30,7 +39,13 @@ Cleanup targets:
 	cmd.Flags().Bool("evicted-pods", true, "Clean up evicted pods")
 	cmd.Flags().Bool("completed-jobs", true, "Clean up completed jobs")
 	cmd.Flags().Bool("orphan-rs", false, "Clean up orphaned ReplicaSets")
+	cmd.Flags().Bool("unused-secrets", false, "Clean up secrets no pod, service account or ingress references")
+	cmd.Flags().Int("ttl-threshold", 10, "Suggest TTL/history limits when a CronJob or namespace has this many completed Jobs")
+	cmd.Flags().Int("ttl-seconds", 86400, "ttlSecondsAfterFinished used in suggested patches")
 	cmd.Flags().Bool("force", false, "Skip confirmation")
+	cmd.Flags().Bool("evict", false, "Remove pods through the Eviction API so PodDisruptionBudgets are respected")
+	cmd.Flags().Int64("grace-period", -1, "Seconds given to pods to terminate (-1 uses the pod's own setting)")
+	addNamespaceSelectorFlags(cmd)
 
 	return cmd
 }
@@ -46,8 +61,9 @@ func runCleanup(cmd *cobra.Command, args []string) error {
 		output.SpinnerError("Failed to connect to cluster")
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
+	client.SetNamespaceSelector(namespaceSelector(cmd))
 
-	ctx := context.Background()
+	ctx := cmd.Context()
 	namespace := cmd.Flag("namespace").Value.String()
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	cleanCompleted, _ := cmd.Flags().GetBool("completed-pods")
@@ -55,6 +71,16 @@ func runCleanup(cmd *cobra.Command, args []string) error {
 	cleanEvicted, _ := cmd.Flags().GetBool("evicted-pods")
 	cleanJobs, _ := cmd.Flags().GetBool("completed-jobs")
 	cleanOrphanRS, _ := cmd.Flags().GetBool("orphan-rs")
+	cleanSecrets, _ := cmd.Flags().GetBool("unused-secrets")
+	ttlThreshold, _ := cmd.Flags().GetInt("ttl-threshold")
+	ttlSeconds, _ := cmd.Flags().GetInt("ttl-seconds")
+	evict, _ := cmd.Flags().GetBool("evict")
+	gracePeriod, _ := cmd.Flags().GetInt64("grace-period")
+
+	deleteOpts := k8s.DeleteOptions{Evict: evict}
+	if gracePeriod >= 0 {
+		deleteOpts.GracePeriod = &gracePeriod
+	}
 
 	output.StopSpinner()
 	output.Header("Cluster Cleanup")
@@ -65,6 +91,7 @@ func runCleanup(cmd *cobra.Command, args []string) error {
 	}
 
 	var totalCleaned int
+	var failures []cleanupFailure
 
 	// Find and clean completed pods
 	if cleanCompleted {
@@ -72,20 +99,24 @@ func runCleanup(cmd *cobra.Command, args []string) error {
 		pods, err := client.FindCompletedPods(ctx, namespace)
 		if err != nil {
 			output.SpinnerError("Failed to find completed pods")
+			failures = append(failures, findFailure("pod", err))
 		} else {
 			output.StopSpinner()
 			if len(pods) > 0 {
 				output.Printf("\n%s Found %d completed pods:\n", output.InfoStyle.Render(output.IconInfo), len(pods))
+				var names []string
 				for _, pod := range pods {
+					names = append(names, pod.Namespace+"/"+pod.Name)
 					output.Printf("  %s %s/%s\n", output.MutedStyle.Render(output.IconBullet), pod.Namespace, pod.Name)
 				}
 				if !dryRun {
-					deleted, err := client.DeletePods(ctx, pods)
-					if err != nil {
-						output.Error(fmt.Sprintf("Failed to delete some pods: %v", err))
-					}
+					deleted, err := client.DeletePods(ctx, pods, deleteOpts)
+					failures = append(failures, cleanupFailures("pod", err)...)
 					totalCleaned += deleted
 					output.Successf("Deleted %d completed pods", deleted)
+					recordCleanup(client, "delete-completed-pods", names, false, deleted, err)
+				} else {
+					recordCleanup(client, "delete-completed-pods", names, true, 0, nil)
 				}
 			} else {
 				output.Success("No completed pods found")
@@ -99,22 +130,26 @@ func runCleanup(cmd *cobra.Command, args []string) error {
 		pods, err := client.FindFailedPods(ctx, namespace)
 		if err != nil {
 			output.SpinnerError("Failed to find failed pods")
+			failures = append(failures, findFailure("pod", err))
 		} else {
 			output.StopSpinner()
 			if len(pods) > 0 {
 				output.Printf("\n%s Found %d failed pods:\n", output.WarningStyle.Render(output.IconWarning), len(pods))
+				var names []string
 				for _, pod := range pods {
+					names = append(names, pod.Namespace+"/"+pod.Name)
 					output.Printf("  %s %s/%s (%s)\n",
 						output.ErrorStyle.Render(output.IconBullet),
 						pod.Namespace, pod.Name, pod.Status)
 				}
 				if !dryRun {
-					deleted, err := client.DeletePods(ctx, pods)
-					if err != nil {
-						output.Error(fmt.Sprintf("Failed to delete some pods: %v", err))
-					}
+					deleted, err := client.DeletePods(ctx, pods, deleteOpts)
+					failures = append(failures, cleanupFailures("pod", err)...)
 					totalCleaned += deleted
 					output.Successf("Deleted %d failed pods", deleted)
+					recordCleanup(client, "delete-failed-pods", names, false, deleted, err)
+				} else {
+					recordCleanup(client, "delete-failed-pods", names, true, 0, nil)
 				}
 			} else {
 				output.Success("No failed pods found")
@@ -128,22 +163,26 @@ func runCleanup(cmd *cobra.Command, args []string) error {
 		pods, err := client.FindEvictedPods(ctx, namespace)
 		if err != nil {
 			output.SpinnerError("Failed to find evicted pods")
+			failures = append(failures, findFailure("pod", err))
 		} else {
 			output.StopSpinner()
 			if len(pods) > 0 {
 				output.Printf("\n%s Found %d evicted pods:\n", output.WarningStyle.Render(output.IconWarning), len(pods))
+				var names []string
 				for _, pod := range pods {
+					names = append(names, pod.Namespace+"/"+pod.Name)
 					output.Printf("  %s %s/%s\n",
 						output.MutedStyle.Render(output.IconBullet),
 						pod.Namespace, pod.Name)
 				}
 				if !dryRun {
-					deleted, err := client.DeletePods(ctx, pods)
-					if err != nil {
-						output.Error(fmt.Sprintf("Failed to delete some pods: %v", err))
-					}
+					deleted, err := client.DeletePods(ctx, pods, deleteOpts)
+					failures = append(failures, cleanupFailures("pod", err)...)
 					totalCleaned += deleted
 					output.Successf("Deleted %d evicted pods", deleted)
+					recordCleanup(client, "delete-evicted-pods", names, false, deleted, err)
+				} else {
+					recordCleanup(client, "delete-evicted-pods", names, true, 0, nil)
 				}
 			} else {
 				output.Success("No evicted pods found")
@@ -157,22 +196,29 @@ func runCleanup(cmd *cobra.Command, args []string) error {
 		jobs, err := client.FindCompletedJobs(ctx, namespace)
 		if err != nil {
 			output.SpinnerError("Failed to find completed jobs")
+			failures = append(failures, findFailure("job", err))
 		} else {
 			output.StopSpinner()
 			if len(jobs) > 0 {
 				output.Printf("\n%s Found %d completed jobs:\n", output.InfoStyle.Render(output.IconInfo), len(jobs))
+				var names []string
 				for _, job := range jobs {
+					names = append(names, job.Namespace+"/"+job.Name)
 					output.Printf("  %s %s/%s\n",
 						output.MutedStyle.Render(output.IconBullet),
 						job.Namespace, job.Name)
 				}
 				if !dryRun {
 					deleted, err := client.DeleteJobs(ctx, jobs)
-					if err != nil {
-						output.Error(fmt.Sprintf("Failed to delete some jobs: %v", err))
-					}
+					failures = append(failures, cleanupFailures("job", err)...)
 					totalCleaned += deleted
 					output.Successf("Deleted %d completed jobs", deleted)
+					recordCleanup(client, "delete-completed-jobs", names, false, deleted, err)
+				} else {
+					recordCleanup(client, "delete-completed-jobs", names, true, 0, nil)
+				}
+				if ttlThreshold > 0 && len(jobs) >= ttlThreshold {
+					printJobTTLSuggestions(ctx, client, jobs, ttlThreshold, ttlSeconds)
 				}
 			} else {
 				output.Success("No completed jobs found")
@@ -186,22 +232,26 @@ func runCleanup(cmd *cobra.Command, args []string) error {
 		replicaSets, err := client.FindOrphanedReplicaSets(ctx, namespace)
 		if err != nil {
 			output.SpinnerError("Failed to find orphaned ReplicaSets")
+			failures = append(failures, findFailure("replicaset", err))
 		} else {
 			output.StopSpinner()
 			if len(replicaSets) > 0 {
 				output.Printf("\n%s Found %d orphaned ReplicaSets:\n", output.InfoStyle.Render(output.IconInfo), len(replicaSets))
+				var names []string
 				for _, rs := range replicaSets {
+					names = append(names, rs.Namespace+"/"+rs.Name)
 					output.Printf("  %s %s/%s\n",
 						output.MutedStyle.Render(output.IconBullet),
 						rs.Namespace, rs.Name)
 				}
 				if !dryRun {
 					deleted, err := client.DeleteReplicaSets(ctx, replicaSets)
-					if err != nil {
-						output.Error(fmt.Sprintf("Failed to delete some ReplicaSets: %v", err))
-					}
+					failures = append(failures, cleanupFailures("replicaset", err)...)
 					totalCleaned += deleted
 					output.Successf("Deleted %d orphaned ReplicaSets", deleted)
+					recordCleanup(client, "delete-orphaned-replicasets", names, false, deleted, err)
+				} else {
+					recordCleanup(client, "delete-orphaned-replicasets", names, true, 0, nil)
 				}
 			} else {
 				output.Success("No orphaned ReplicaSets found")
@@ -209,6 +259,45 @@ func runCleanup(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Find and clean unreferenced secrets
+	if cleanSecrets {
+		output.StartSpinner("Finding unreferenced secrets...")
+		report, err := client.SecretsReport(ctx, namespace)
+		if err != nil {
+			output.SpinnerError("Failed to find unreferenced secrets")
+			failures = append(failures, findFailure("secret", err))
+		} else {
+			output.StopSpinner()
+			var secrets []k8s.SecretUsage
+			for _, secret := range report {
+				if secret.Unreferenced {
+					secrets = append(secrets, secret)
+				}
+			}
+			if len(secrets) > 0 {
+				output.Printf("\n%s Found %d unreferenced secrets:\n", output.WarningStyle.Render(output.IconWarning), len(secrets))
+				var names []string
+				for _, secret := range secrets {
+					names = append(names, secret.Namespace+"/"+secret.Name)
+					output.Printf("  %s %s/%s (%s, %s old)\n",
+						output.MutedStyle.Render(output.IconBullet),
+						secret.Namespace, secret.Name, secret.Type, format.Age(secret.Created))
+				}
+				if !dryRun {
+					deleted, err := client.DeleteSecrets(ctx, secrets)
+					failures = append(failures, cleanupFailures("secret", err)...)
+					totalCleaned += deleted
+					output.Successf("Deleted %d unreferenced secrets", deleted)
+					recordCleanup(client, "delete-unreferenced-secrets", names, false, deleted, err)
+				} else {
+					recordCleanup(client, "delete-unreferenced-secrets", names, true, 0, nil)
+				}
+			} else {
+				output.Success("No unreferenced secrets found")
+			}
+		}
+	}
+
 	// Summary
 	output.Newline()
 	output.Print(output.Divider(50))
@@ -220,6 +309,118 @@ func runCleanup(cmd *cobra.Command, args []string) error {
 		output.Successf("Cleanup complete! Removed %d resources.", totalCleaned)
 	}
 
+	if len(failures) > 0 {
+		output.Newline()
+		printCleanupFailures(failures)
+		output.Newline()
+		return exitcode.PartialError(fmt.Errorf("%d cleanup items failed", len(failures)))
+	}
+
 	output.Newline()
 	return nil
 }
+
+// printJobTTLSuggestions explains how to stop completed Jobs from accumulating
+func printJobTTLSuggestions(ctx context.Context, client *k8s.Client, jobs []k8s.JobInfo, threshold, ttlSeconds int) {
+	suggestions, err := client.SuggestJobTTL(ctx, jobs, threshold, ttlSeconds)
+	if err != nil {
+		output.Warning("Could not check Job TTL settings: " + err.Error())
+		return
+	}
+	if len(suggestions) == 0 {
+		return
+	}
+
+	output.Newline()
+	table := output.NewTable(output.TableConfig{
+		Title:      "Prevent Job Accumulation",
+		Headers:    []string{"Kind", "Namespace", "Name", "Completed", "Issue", "Remediation"},
+		ShowBorder: true,
+	})
+	var patches []string
+	for _, s := range suggestions {
+		name := s.Name
+		if name == "" {
+			name = "-"
+		}
+		table.AddColoredRow(
+			[]string{s.Kind, s.Namespace, name, fmt.Sprintf("%d", s.CompletedJobs), s.Issue, output.Truncate(s.Remediation, 60)},
+			[]tablewriter.Colors{
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgCyanColor},
+				{tablewriter.Bold, tablewriter.FgYellowColor},
+				{tablewriter.FgYellowColor},
+				{tablewriter.FgWhiteColor},
+			},
+		)
+		if s.Patch != "" {
+			patches = append(patches, s.Patch)
+		}
+	}
+	table.Render()
+
+	if len(patches) > 0 {
+		output.Newline()
+		output.Muted("  Apply in the source manifests too, or the next deploy reverts these patches:")
+		for _, patch := range patches {
+			output.Printf("  %s %s\n", output.MutedStyle.Render(output.IconArrow), patch)
+		}
+	}
+}
+
+// recordCleanup writes a cleanup step to the audit log
+func recordCleanup(client *k8s.Client, action string, resources []string, dryRun bool, deleted int, err error) {
+	entry := audit.Entry{
+		Command:   "k8s cleanup",
+		Action:    action,
+		Target:    client.Server(),
+		Resources: resources,
+		DryRun:    dryRun,
+		Result:    audit.Result(dryRun, deleted, len(resources), err),
+	}
+	if auditErr := audit.Record(entry, err); auditErr != nil {
+		output.Warning(auditErr.Error())
+	}
+}
+
+// cleanupFailure is an item that could not be listed or removed
+type cleanupFailure struct {
+	Kind string
+	batch.Failure
+}
+
+// cleanupFailures tags the per-item failures of a removal with their kind
+func cleanupFailures(kind string, err error) []cleanupFailure {
+	var result []cleanupFailure
+	for _, f := range batch.Failures(err, "all "+kind+"s") {
+		result = append(result, cleanupFailure{Kind: kind, Failure: f})
+	}
+	if len(result) > 0 {
+		output.Warningf("%d %ss could not be removed", len(result), kind)
+	}
+	return result
+}
+
+// findFailure records a finder whose list failed, so a Forbidden list is
+// reported instead of looking like there is nothing to clean up
+func findFailure(kind string, err error) cleanupFailure {
+	return cleanupFailure{Kind: kind, Failure: batch.Failure{Item: "all " + kind + "s", Err: err}}
+}
+
+// printCleanupFailures renders what was not cleaned and why
+func printCleanupFailures(failures []cleanupFailure) {
+	table := output.NewTable(output.TableConfig{
+		Title:      "Not Cleaned",
+		Headers:    []string{"Kind", "Item", "Reason"},
+		ShowBorder: true,
+	})
+	for _, f := range failures {
+		table.AddColoredRow([]string{f.Kind, output.Truncate(f.Item, 50), output.Truncate(f.Err.Error(), 70)}, []tablewriter.Colors{
+			{tablewriter.FgHiBlackColor},
+			{tablewriter.FgCyanColor},
+			{tablewriter.FgRedColor},
+		})
+	}
+	table.Render()
+}
@@ -0,0 +1,119 @@
+package k8s
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newDebugCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug <pod> [-- command...]",
+		Short: "Attach an ephemeral debug container to a running pod",
+		Long: `Add an ephemeral debug container to a running pod and attach to it.
+
+The debug container shares the pod's network and IPC namespaces and the
+process namespace of the target container, so tools like ps, netstat and
+nslookup work against the application even when its image is distroless.
+The target's filesystem is reachable at /proc/1/root.
+
+Ephemeral containers cannot be removed from a pod: the container stops
+when you exit its shell and stays listed until the pod is replaced.
+Requires Kubernetes 1.23+.
+
+Examples:
+  devops-toolkit k8s debug api-7d9f -n shop
+  devops-toolkit k8s debug shop/api-7d9f --image nicolaka/netshoot --target api
+  devops-toolkit k8s debug api-7d9f -n shop --no-attach -- sleep 3600`,
+		Args:              cobra.MinimumNArgs(1),
+		RunE:              runDebug,
+		ValidArgsFunction: completion.PodCompletion,
+	}
+
+	cmd.Flags().String("image", "busybox:1.36", "Debug container image")
+	cmd.Flags().String("target", "", "Container whose processes are shared (default: the pod's first container)")
+	cmd.Flags().String("name", "", "Debug container name (default: debugger-<random>)")
+	cmd.Flags().Bool("no-attach", false, "Start the container without attaching and print the attach command")
+
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("target", completion.ContainerInPodCompletion)
+
+	return cmd
+}
+
+func runDebug(cmd *cobra.Command, args []string) error {
+	image, _ := cmd.Flags().GetString("image")
+	target, _ := cmd.Flags().GetString("target")
+	name, _ := cmd.Flags().GetString("name")
+	noAttach, _ := cmd.Flags().GetBool("no-attach")
+
+	namespace := cmd.Flag("namespace").Value.String()
+	pod := args[0]
+	if ns, podName, ok := strings.Cut(pod, "/"); ok {
+		namespace, pod = ns, podName
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	output.StartSpinner(fmt.Sprintf("Adding debug container to %s/%s...", namespace, pod))
+
+	client, err := k8s.NewClient(
+		cmd.Flag("kubeconfig").Value.String(),
+		cmd.Flag("context").Value.String(),
+	)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return err
+	}
+
+	dc, err := client.AddDebugContainer(cmd.Context(), k8s.DebugContainerOptions{
+		Namespace: namespace,
+		Pod:       pod,
+		Image:     image,
+		Name:      name,
+		Target:    target,
+		Command:   args[1:],
+	})
+	if err != nil {
+		output.SpinnerError("Failed to add debug container")
+		return err
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Debug container %s running in %s/%s (sharing processes with %s)", dc.Name, dc.Namespace, dc.Pod, dc.Target))
+
+	if output.IsStructured() {
+		return output.Render(dc)
+	}
+
+	attach := client.AttachCommand(dc)
+	if noAttach {
+		output.Newline()
+		output.Printf("  %s %s\n", output.IconArrow, attach)
+		printDebugCleanup(dc)
+		return nil
+	}
+
+	output.Muted("  Target filesystem: /proc/1/root. Exit the shell to stop the container.")
+	output.Newline()
+	if err := client.AttachDebugContainer(cmd.Context(), dc, output.IsTerminal(os.Stdin)); err != nil {
+		output.Warning(fmt.Sprintf("Attach failed; attach manually with: %s", attach))
+		return err
+	}
+	printDebugCleanup(dc)
+	return nil
+}
+
+// printDebugCleanup explains that ephemeral containers outlive the session
+func printDebugCleanup(dc *k8s.DebugContainer) {
+	output.Newline()
+	output.Print(output.Section("Cleanup"))
+	output.Muted(fmt.Sprintf("  %s stays in the pod spec until the pod is replaced; it stops when its command exits.", dc.Name))
+	output.Muted("  To remove it, restart the workload or delete the pod:")
+	output.Printf("  %s kubectl delete pod %s -n %s\n", output.MutedStyle.Render(output.IconArrow), dc.Pod, dc.Namespace)
+}
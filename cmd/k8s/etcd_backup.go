@@ -0,0 +1,288 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/aws"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/etcd"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// etcdBackupResult is the structured output of etcd-backup
+type etcdBackupResult struct {
+	Source   string             `json:"source"`
+	Snapshot *etcd.SnapshotInfo `json:"snapshot"`
+	Checksum string             `json:"checksum_file"`
+	S3URI    string             `json:"s3_uri,omitempty"`
+}
+
+func newEtcdBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "etcd-backup",
+		Short: "Take and verify an etcd snapshot",
+		Long: `Take an etcd snapshot for self-hosted clusters, store it with a
+timestamped name on local disk or S3 and verify its integrity hash.
+
+By default the snapshot is taken by exec'ing etcdctl inside an etcd pod
+in kube-system (kubeadm layout) and copied out with kubectl cp, which
+needs tar in the etcd image. Use --endpoints to run the local etcdctl
+against etcd directly instead, e.g. on a control-plane node.
+
+Managed control planes (EKS, GKE, AKS) do not expose etcd.
+
+Examples:
+  # Snapshot through the etcd pod into ./backups
+  devops-toolkit k8s etcd-backup --output-dir ./backups
+
+  # Talk to etcd directly and upload to S3
+  devops-toolkit k8s etcd-backup --endpoints https://10.0.0.10:2379 \
+    --cacert /etc/kubernetes/pki/etcd/ca.crt \
+    --cert /etc/kubernetes/pki/etcd/server.crt \
+    --key /etc/kubernetes/pki/etcd/server.key \
+    --s3 s3://cluster-backups/etcd`,
+		RunE: runEtcdBackup,
+	}
+
+	cmd.Flags().String("pod", "", "etcd pod to exec into (default: first ready etcd pod)")
+	cmd.Flags().StringSlice("endpoints", nil, "Talk to these etcd endpoints with the local etcdctl instead of exec'ing into a pod")
+	cmd.Flags().String("cacert", "", "CA certificate for --endpoints")
+	cmd.Flags().String("cert", "", "Client certificate for --endpoints")
+	cmd.Flags().String("key", "", "Client key for --endpoints")
+	cmd.Flags().String("output-dir", ".", "Directory to store the snapshot in")
+	cmd.Flags().String("name", "", "Cluster name used in the snapshot file name (default: kube context)")
+	cmd.Flags().String("s3", "", "Also upload the snapshot to this S3 prefix (s3://bucket/prefix)")
+	cmd.Flags().Bool("delete-local", false, "Remove the local copy after a successful S3 upload")
+	cmd.Flags().Duration("timeout", 5*time.Minute, "Timeout for taking and copying the snapshot")
+
+	// Register flag completions
+	_ = cmd.MarkFlagFilename("cacert", "crt", "pem")
+	_ = cmd.MarkFlagFilename("cert", "crt", "pem")
+	_ = cmd.MarkFlagFilename("key", "key", "pem")
+	_ = cmd.MarkFlagDirname("output-dir")
+
+	return cmd
+}
+
+func runEtcdBackup(cmd *cobra.Command, args []string) error {
+	endpoints, _ := cmd.Flags().GetStringSlice("endpoints")
+	outputDir, _ := cmd.Flags().GetString("output-dir")
+	name, _ := cmd.Flags().GetString("name")
+	s3URI, _ := cmd.Flags().GetString("s3")
+	deleteLocal, _ := cmd.Flags().GetBool("delete-local")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	if deleteLocal && s3URI == "" {
+		return fmt.Errorf("--delete-local requires --s3")
+	}
+	if s3URI != "" {
+		if _, _, err := aws.ParseS3URI(s3URI); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+	defer cancel()
+
+	result := etcdBackupResult{}
+	var localPath string
+
+	if len(endpoints) > 0 {
+		cacert, _ := cmd.Flags().GetString("cacert")
+		cert, _ := cmd.Flags().GetString("cert")
+		key, _ := cmd.Flags().GetString("key")
+
+		localPath = filepath.Join(outputDir, etcd.SnapshotName(name, time.Now()))
+		result.Source = strings.Join(endpoints, ",")
+
+		output.StartSpinner(fmt.Sprintf("Saving snapshot from %s...", result.Source))
+		err := etcd.Save(ctx, etcd.Options{Endpoints: endpoints, CACert: cacert, Cert: cert, Key: key}, localPath)
+		if err != nil {
+			output.SpinnerError("Snapshot failed")
+			return err
+		}
+		output.SpinnerSuccess("Snapshot saved")
+	} else {
+		path, source, err := snapshotFromPod(ctx, cmd, outputDir, name)
+		if err != nil {
+			return err
+		}
+		localPath = path
+		result.Source = source
+	}
+
+	output.StartSpinner("Verifying snapshot...")
+	info, err := etcd.Verify(localPath)
+	if err != nil {
+		output.SpinnerError("Snapshot verification failed")
+		return fmt.Errorf("snapshot %s failed verification: %w", localPath, err)
+	}
+	result.Snapshot = info
+
+	checksum, err := etcd.WriteChecksum(info)
+	if err != nil {
+		output.SpinnerError("Failed to write checksum")
+		return fmt.Errorf("failed to write checksum: %w", err)
+	}
+	result.Checksum = checksum
+	if info.Verified {
+		output.SpinnerSuccess("Snapshot integrity hash verified")
+	} else {
+		output.StopSpinner()
+		output.Warning("Snapshot has no integrity hash; only the database header was checked")
+	}
+
+	if s3URI != "" {
+		uri, err := uploadSnapshot(ctx, info, checksum, s3URI)
+		if err != nil {
+			return err
+		}
+		result.S3URI = uri
+
+		if deleteLocal {
+			_ = os.Remove(localPath)
+			_ = os.Remove(checksum)
+		}
+	}
+
+	if output.IsStructured() {
+		return output.Render(result)
+	}
+
+	output.Newline()
+	output.Print(output.Section("etcd Backup"))
+	output.Printf("  %s\n", output.KeyValue("Source", result.Source))
+	if !deleteLocal {
+		output.Printf("  %s\n", output.KeyValue("Snapshot", info.Path))
+	}
+	output.Printf("  %s\n", output.KeyValue("Size", format.Quantity(info.Size)))
+	output.Printf("  %s\n", output.KeyValue("SHA-256", info.SHA256))
+	if result.S3URI != "" {
+		output.Printf("  %s\n", output.KeyValue("S3", result.S3URI))
+	}
+	output.Newline()
+	return nil
+}
+
+// snapshotFromPod runs etcdctl inside an etcd pod and copies the snapshot to outputDir
+func snapshotFromPod(ctx context.Context, cmd *cobra.Command, outputDir, name string) (string, string, error) {
+	podName, _ := cmd.Flags().GetString("pod")
+
+	output.StartSpinner("Looking for etcd pods...")
+
+	client, err := k8s.NewClient(
+		cmd.Flag("kubeconfig").Value.String(),
+		cmd.Flag("context").Value.String(),
+	)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return "", "", fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	members, err := client.ListEtcdPods(ctx)
+	if err != nil {
+		output.SpinnerError("Failed to list etcd pods")
+		return "", "", fmt.Errorf("failed to list etcd pods: %w", err)
+	}
+
+	var member *k8s.EtcdPod
+	for i := range members {
+		if (podName != "" && members[i].Name == podName) || (podName == "" && members[i].Ready) {
+			member = &members[i]
+			break
+		}
+	}
+	if member == nil {
+		output.SpinnerError("No etcd pod found")
+		if podName != "" {
+			return "", "", fmt.Errorf("etcd pod %s not found in kube-system", podName)
+		}
+		return "", "", fmt.Errorf("no ready etcd pod found in kube-system (managed control plane? use --endpoints for external etcd)")
+	}
+
+	if name == "" {
+		name = client.Context()
+	}
+	fileName := etcd.SnapshotName(name, time.Now())
+	remotePath := path.Join(member.DataDir, fileName)
+	localPath := filepath.Join(outputDir, fileName)
+	source := fmt.Sprintf("%s/%s", member.Namespace, member.Name)
+
+	output.UpdateSpinner(fmt.Sprintf("Saving snapshot in %s...", source))
+
+	opts := etcd.Options{
+		Endpoints: []string{member.Endpoint},
+		CACert:    member.CACert,
+		Cert:      member.Cert,
+		Key:       member.Key,
+	}
+	command := append([]string{"etcdctl"}, opts.Args()...)
+	command = append(command, "snapshot", "save", remotePath)
+
+	out, code, err := client.Exec(ctx, member.Namespace, member.Name, member.Container, command)
+	if err != nil || code != 0 {
+		output.SpinnerError("Snapshot failed")
+		if err != nil {
+			return "", "", err
+		}
+		return "", "", fmt.Errorf("etcdctl snapshot save failed: %s", out)
+	}
+
+	output.UpdateSpinner("Copying snapshot...")
+	if err := client.CopyFromPod(ctx, member.Namespace, member.Name, member.Container, remotePath, localPath); err != nil {
+		output.SpinnerError("Failed to copy snapshot")
+		return "", "", fmt.Errorf("%w (snapshot left at %s on node %s)", err, remotePath, member.Node)
+	}
+	output.SpinnerSuccess(fmt.Sprintf("Snapshot saved from %s", source))
+
+	if _, code, err := client.Exec(ctx, member.Namespace, member.Name, member.Container, []string{"rm", "-f", remotePath}); err != nil || code != 0 {
+		output.Warning(fmt.Sprintf("Could not remove %s on node %s; delete it manually", remotePath, member.Node))
+	}
+
+	return localPath, source, nil
+}
+
+// uploadSnapshot copies the snapshot and its checksum to S3 and checks the stored size
+func uploadSnapshot(ctx context.Context, info *etcd.SnapshotInfo, checksum, s3URI string) (string, error) {
+	bucket, prefix, _ := aws.ParseS3URI(s3URI)
+	key := strings.TrimSuffix(prefix, "/")
+	if key != "" {
+		key += "/"
+	}
+	key += filepath.Base(info.Path)
+
+	client, err := aws.NewClient(viper.GetString("aws.profile"), viper.GetString("aws.region"))
+	if err != nil {
+		return "", err
+	}
+
+	output.StartSpinner(fmt.Sprintf("Uploading to s3://%s/%s...", bucket, key))
+	obj, err := client.UploadFile(ctx, info.Path, bucket, key)
+	if err != nil {
+		output.SpinnerError("Upload failed")
+		return "", fmt.Errorf("failed to upload snapshot: %w", err)
+	}
+	if obj.ContentLength != info.Size {
+		output.SpinnerError("Upload verification failed")
+		return "", fmt.Errorf("uploaded object is %d bytes, expected %d", obj.ContentLength, info.Size)
+	}
+	if _, err := client.UploadFile(ctx, checksum, bucket, key+".sha256"); err != nil {
+		output.SpinnerError("Upload failed")
+		return "", fmt.Errorf("failed to upload checksum: %w", err)
+	}
+	output.SpinnerSuccess("Uploaded to S3")
+
+	return fmt.Sprintf("s3://%s/%s", bucket, key), nil
+}
@@ -1,10 +1,11 @@
 package k8s
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
 	"github.com/olekukonko/tablewriter"
@@ -28,10 +29,15 @@ Features:
 	cmd.Flags().String("type", "", "Filter by event type (Normal, Warning)")
 	cmd.Flags().String("reason", "", "Filter by reason")
 	cmd.Flags().String("object", "", "Filter by object name")
+	cmd.Flags().Bool("exact", false, "Match --reason and --object exactly, filtered by the API server")
+	cmd.Flags().Duration("since", 0, "Only show events seen within this duration (e.g. 30m, 2h)")
 	cmd.Flags().Int("limit", 50, "Maximum number of events to show")
 	cmd.Flags().Bool("watch", false, "Watch for new events")
 	cmd.Flags().Bool("warnings-only", false, "Show only warning events")
 
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("type", completion.EventTypeCompletion)
+
 	return cmd
 }
 
@@ -47,11 +53,13 @@ func runEvents(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
 
-	ctx := context.Background()
+	ctx := cmd.Context()
 	namespace := cmd.Flag("namespace").Value.String()
 	eventType, _ := cmd.Flags().GetString("type")
 	reason, _ := cmd.Flags().GetString("reason")
 	objectFilter, _ := cmd.Flags().GetString("object")
+	exact, _ := cmd.Flags().GetBool("exact")
+	since, _ := cmd.Flags().GetDuration("since")
 	limit, _ := cmd.Flags().GetInt("limit")
 	warningsOnly, _ := cmd.Flags().GetBool("warnings-only")
 
@@ -63,6 +71,8 @@ func runEvents(cmd *cobra.Command, args []string) error {
 		Type:   eventType,
 		Reason: reason,
 		Object: objectFilter,
+		Exact:  exact,
+		Since:  since,
 		Limit:  limit,
 	})
 	if err != nil {
@@ -73,6 +83,10 @@ func runEvents(cmd *cobra.Command, args []string) error {
 	output.SpinnerSuccess(fmt.Sprintf("Found %d events", len(events)))
 	output.Newline()
 
+	if output.IsStructured() {
+		return output.Render(events)
+	}
+
 	if len(events) == 0 {
 		output.Info("No events found matching the criteria")
 		return nil
@@ -97,15 +111,19 @@ func runEvents(cmd *cobra.Command, args []string) error {
 	})
 
 	for _, event := range events {
-		age := formatAge(event.LastTimestamp)
+		age := format.Age(event.LastTimestamp)
 		object := fmt.Sprintf("%s/%s", strings.ToLower(event.Kind), event.Object)
+		message := event.Message
+		if event.Count > 1 {
+			message = fmt.Sprintf("%s (x%d)", message, event.Count)
+		}
 
 		row := []string{
 			age,
 			event.Type,
 			event.Reason,
-			truncate(object, 40),
-			truncate(event.Message, 60),
+			output.Truncate(object, 40),
+			output.Truncate(message, 60),
 		}
 
 		colors := getEventRowColors(event)
@@ -0,0 +1,239 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newEvictionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "evictions",
+		Short: "Summarize evicted and preempted pods",
+		Long: `Report pods that were evicted or preempted, grouped by node and reason.
+
+Reasons:
+  • NodePressure    kubelet evicted the pod because the node ran low on memory, disk or PIDs
+  • Preemption      the scheduler preempted the pod for a higher-priority pod
+  • EvictionAPI     the pod was evicted through the Eviction API (e.g. kubectl drain)
+  • NoExecuteTaint  a NoExecute taint removed the pod from the node
+
+Each group shows the node conditions (MemoryPressure, DiskPressure, NotReady, ...)
+that were in effect at the time of the most recent eviction.
+
+Events expire after an hour by default, so older preemptions are only reported
+while the preempted pod object still exists.
+
+Examples:
+  devops-toolkit k8s evictions
+  devops-toolkit k8s evictions --since 2h --details
+  devops-toolkit k8s evictions -n batch --output json`,
+		RunE: runEvictions,
+	}
+
+	cmd.Flags().Duration("since", 24*time.Hour, "Only include evictions within this duration")
+	cmd.Flags().Bool("details", false, "List every evicted pod")
+	addNamespaceSelectorFlags(cmd)
+
+	return cmd
+}
+
+// evictionGroup aggregates evictions on one node for one reason
+type evictionGroup struct {
+	Node       string
+	Reason     string
+	Count      int
+	Resources  []string
+	Namespaces []string
+	Conditions []string
+	Last       time.Time
+}
+
+func runEvictions(cmd *cobra.Command, args []string) error {
+	since, _ := cmd.Flags().GetDuration("since")
+	showDetails, _ := cmd.Flags().GetBool("details")
+
+	output.StartSpinner("Collecting evictions...")
+
+	client, err := k8s.NewClient(
+		cmd.Flag("kubeconfig").Value.String(),
+		cmd.Flag("context").Value.String(),
+	)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	client.SetNamespaceSelector(namespaceSelector(cmd))
+
+	namespace := cmd.Flag("namespace").Value.String()
+	records, err := client.Evictions(cmd.Context(), namespace, time.Now().Add(-since))
+	if err != nil {
+		output.SpinnerError("Failed to collect evictions")
+		return err
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Found %d evicted or preempted pods", len(records)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(records)
+	}
+
+	if len(records) == 0 {
+		output.Success(fmt.Sprintf("No evictions or preemptions in the last %s", since))
+		return nil
+	}
+
+	groups := groupEvictions(records)
+
+	table := output.NewTable(output.TableConfig{
+		Title:      fmt.Sprintf("Evictions by Node (last %s)", since),
+		Headers:    []string{"Node", "Reason", "Pods", "Resource", "Namespaces", "Node Conditions", "Last"},
+		ShowBorder: true,
+	})
+
+	for _, g := range groups {
+		conditions := "-"
+		conditionColor := tablewriter.Colors{tablewriter.FgHiBlackColor}
+		if len(g.Conditions) > 0 {
+			conditions = strings.Join(g.Conditions, ",")
+			conditionColor = tablewriter.Colors{tablewriter.FgRedColor}
+		}
+		resources := "-"
+		if len(g.Resources) > 0 {
+			resources = strings.Join(g.Resources, ",")
+		}
+
+		table.AddColoredRow(
+			[]string{
+				g.Node,
+				g.Reason,
+				fmt.Sprintf("%d", g.Count),
+				resources,
+				output.Truncate(strings.Join(g.Namespaces, ","), 30),
+				conditions,
+				format.Age(g.Last),
+			},
+			[]tablewriter.Colors{
+				{tablewriter.FgCyanColor},
+				{tablewriter.Bold, evictionReasonColor(g.Reason)},
+				{tablewriter.Bold},
+				{tablewriter.FgYellowColor},
+				{tablewriter.FgHiBlackColor},
+				conditionColor,
+				{tablewriter.FgHiBlackColor},
+			},
+		)
+	}
+
+	table.Render()
+
+	if showDetails {
+		output.Newline()
+		renderEvictionDetails(records)
+	}
+
+	output.Newline()
+	output.Print(output.Section("Summary"))
+	byReason := make(map[string]int)
+	for _, rec := range records {
+		byReason[rec.Reason]++
+	}
+	for _, reason := range []string{k8s.EvictionNodePressure, k8s.EvictionPreemption, k8s.EvictionAPI, k8s.EvictionTaint, k8s.EvictionOther} {
+		if byReason[reason] > 0 {
+			output.Printf("  %s\n", output.KeyValue(reason, fmt.Sprintf("%d", byReason[reason])))
+		}
+	}
+
+	output.Newline()
+	return nil
+}
+
+// groupEvictions groups records by node and reason, busiest groups first
+func groupEvictions(records []k8s.EvictionRecord) []*evictionGroup {
+	index := make(map[string]*evictionGroup)
+	var groups []*evictionGroup
+	for _, rec := range records {
+		node := rec.Node
+		if node == "" {
+			node = "(unknown)"
+		}
+		key := node + "/" + rec.Reason
+		g, ok := index[key]
+		if !ok {
+			g = &evictionGroup{Node: node, Reason: rec.Reason}
+			index[key] = g
+			groups = append(groups, g)
+		}
+
+		g.Count++
+		g.Resources = appendUnique(g.Resources, rec.Resource)
+		g.Namespaces = appendUnique(g.Namespaces, rec.Namespace)
+		if rec.Time.After(g.Last) {
+			g.Last = rec.Time
+			g.Conditions = rec.NodeConditions
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Node < groups[j].Node
+	})
+	return groups
+}
+
+func renderEvictionDetails(records []k8s.EvictionRecord) {
+	table := output.NewTable(output.TableConfig{
+		Title:      "Evicted Pods",
+		Headers:    []string{"Age", "Namespace", "Pod", "Node", "Reason", "Message"},
+		ShowBorder: true,
+	})
+
+	for _, rec := range records {
+		table.AddColoredRow(
+			[]string{format.Age(rec.Time), rec.Namespace, output.Truncate(rec.Pod, 40), rec.Node, rec.Reason, output.Truncate(rec.Message, 50)},
+			[]tablewriter.Colors{
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgCyanColor},
+				{tablewriter.FgWhiteColor},
+				{evictionReasonColor(rec.Reason)},
+				{tablewriter.FgWhiteColor},
+			},
+		)
+	}
+
+	table.Render()
+}
+
+func evictionReasonColor(reason string) int {
+	switch reason {
+	case k8s.EvictionNodePressure:
+		return tablewriter.FgRedColor
+	case k8s.EvictionPreemption:
+		return tablewriter.FgMagentaColor
+	default:
+		return tablewriter.FgYellowColor
+	}
+}
+
+func appendUnique(values []string, value string) []string {
+	if value == "" {
+		return values
+	}
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
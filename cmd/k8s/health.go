@@ -1,12 +1,15 @@
 package k8s
 
 import (
-	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/helm"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/run"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
@@ -20,42 +23,83 @@ func newHealthCmd() *cobra.Command {
 Checks:
   • Node status and resource utilization
   • Pod health across namespaces
+  • Deployment rollout state (paused deployments are not counted as unhealthy)
   • PersistentVolumeClaim status
+  • Helm release status (failed / pending-upgrade)
   • Certificate expiration
   • Component status
-  • Recent warning events`,
+  • Restart storms (many pods restarting together) with likely trigger
+  • Recent warning events
+
+EKS, GKE and AKS clusters are detected from node metadata and nodes are
+grouped by node pool. Binaries built with the eks, gke or aks tags also show
+the control plane version against the latest available, node pool
+auto-upgrade settings and maintenance windows. EKS details come from the AWS
+SDK's default credential chain; GKE and AKS details come from the gcloud or
+az CLI, which must be installed and logged in. Without credentials the cloud
+section reports the error and the rest of the check still runs.`,
 		RunE: runHealth,
 	}
 
-	cmd.Flags().Bool("watch", false, "Watch for changes")
-	cmd.Flags().Duration("interval", 5*time.Second, "Watch interval")
+	cmd.Flags().Bool("details", false, "Show per-deployment status")
+	cmd.Flags().Duration("storm-window", 5*time.Minute, "Window in which pod restarts count as a restart storm")
+	cmd.Flags().Int("storm-min-pods", 5, "Distinct pods restarting within the window to report a restart storm")
+	addNamespaceSelectorFlags(cmd)
 
-	return cmd
+	return output.Watchable(cmd)
 }
 
 func runHealth(cmd *cobra.Command, args []string) error {
 	output.StartSpinner("Connecting to cluster...")
 
-	client, err := k8s.NewClient(
-		cmd.Flag("kubeconfig").Value.String(),
-		cmd.Flag("context").Value.String(),
-	)
+	kubeconfig := cmd.Flag("kubeconfig").Value.String()
+	kubeContext := cmd.Flag("context").Value.String()
+	client, err := k8s.NewClient(kubeconfig, kubeContext)
 	if err != nil {
 		output.SpinnerError("Failed to connect to cluster")
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
+	selector := namespaceSelector(cmd)
+	client.SetNamespaceSelector(selector)
 
-	ctx := context.Background()
+	showDetails, _ := cmd.Flags().GetBool("details")
+	stormWindow, _ := cmd.Flags().GetDuration("storm-window")
+	stormMinPods, _ := cmd.Flags().GetInt("storm-min-pods")
 
 	output.SpinnerSuccess("Connected to cluster")
 	output.Newline()
 
+	output.StartSpinner("Checking cluster health...")
+	report := run.Health(cmd.Context(), client, run.HealthOptions{
+		Namespace:    cmd.Flag("namespace").Value.String(),
+		Namespaces:   selector,
+		Kubeconfig:   kubeconfig,
+		Context:      kubeContext,
+		StormWindow:  stormWindow,
+		StormMinPods: stormMinPods,
+		EventLimit:   10,
+		Progress:     output.UpdateSpinner,
+	})
+	output.StopSpinner()
+
+	if output.IsStructured() {
+		return output.Render(report)
+	}
+
 	// Get cluster info
-	clusterInfo, err := client.GetClusterInfo(ctx)
-	if err != nil {
-		output.Warning("Could not get cluster info: " + err.Error())
+	clusterInfo := report.Cluster
+	if msg, ok := report.Errors[run.SectionCluster]; ok {
+		output.Warning("Could not get cluster info: " + msg)
 	} else {
 		output.Header(fmt.Sprintf("Cluster: %s", clusterInfo.Name))
+		if clusterInfo.Provider != "" {
+			output.Printf("  %s\n", output.KeyValue("Provider", strings.ToUpper(clusterInfo.Provider)))
+			output.Printf("  %s\n", output.KeyValue("Version", clusterInfo.K8sVersion))
+			if clusterInfo.CloudError != "" {
+				output.Muted("  Cloud details unavailable: " + clusterInfo.CloudError)
+			}
+			output.Newline()
+		}
 	}
 
 	// Create health summary table
@@ -65,26 +109,21 @@ func runHealth(cmd *cobra.Command, args []string) error {
 		ShowBorder: true,
 	})
 
-	// Check nodes
-	output.StartSpinner("Checking nodes...")
-	nodeHealth, err := client.GetNodeHealth(ctx)
-	if err != nil {
-		output.SpinnerError("Failed to check nodes")
-	} else {
-		output.StopSpinner()
+	// Managed control plane
+	if clusterInfo != nil && clusterInfo.Cloud != nil {
+		row, colors := controlPlaneRow(clusterInfo.Cloud)
+		healthTable.AddColoredRow(row, colors)
+	}
+
+	// Nodes
+	if nodeHealth := report.Nodes; nodeHealth != nil {
 		status := fmt.Sprintf("%s %d/%d Ready", getStatusIcon(nodeHealth.Healthy), nodeHealth.Ready, nodeHealth.Total)
 		row, colors := output.StatusRow("Nodes", getHealthStatus(nodeHealth.Healthy), status)
 		healthTable.AddColoredRow(row, colors)
 	}
 
-	// Check pods
-	output.StartSpinner("Checking pods...")
-	namespace := cmd.Flag("namespace").Value.String()
-	podHealth, err := client.GetPodHealth(ctx, namespace)
-	if err != nil {
-		output.SpinnerError("Failed to check pods")
-	} else {
-		output.StopSpinner()
+	// Pods
+	if podHealth := report.Pods; podHealth != nil {
 		details := fmt.Sprintf("Running: %d, Pending: %d, Failed: %d",
 			podHealth.Running, podHealth.Pending, podHealth.Failed)
 		var status string
@@ -99,13 +138,8 @@ func runHealth(cmd *cobra.Command, args []string) error {
 		healthTable.AddColoredRow(row, colors)
 	}
 
-	// Check PVCs
-	output.StartSpinner("Checking persistent volumes...")
-	pvcHealth, err := client.GetPVCHealth(ctx, namespace)
-	if err != nil {
-		output.SpinnerError("Failed to check PVCs")
-	} else {
-		output.StopSpinner()
+	// PVCs
+	if pvcHealth := report.PVCs; pvcHealth != nil {
 		healthy := pvcHealth.Pending == 0
 		details := fmt.Sprintf("Bound: %d, Pending: %d", pvcHealth.Bound, pvcHealth.Pending)
 		status := fmt.Sprintf("%s %s", getStatusIcon(healthy), getHealthStatus(healthy))
@@ -113,46 +147,141 @@ func runHealth(cmd *cobra.Command, args []string) error {
 		healthTable.AddColoredRow(row, colors)
 	}
 
-	// Check deployments
-	output.StartSpinner("Checking deployments...")
-	deployHealth, err := client.GetDeploymentHealth(ctx, namespace)
-	if err != nil {
-		output.SpinnerError("Failed to check deployments")
-	} else {
-		output.StopSpinner()
-		healthy := deployHealth.Unavailable == 0
+	// Deployments
+	deployHealth := report.Deployments
+	if deployHealth != nil {
+		healthy := deployHealth.Unavailable == 0 && deployHealth.Stalled == 0
 		details := fmt.Sprintf("Ready: %d/%d, Unavailable: %d",
-			deployHealth.Ready, deployHealth.Total, deployHealth.Unavailable)
+			deployHealth.Ready, deployHealth.Total-deployHealth.Paused, deployHealth.Unavailable)
+		if deployHealth.Paused > 0 {
+			details += fmt.Sprintf(", Paused: %d", deployHealth.Paused)
+		}
+		if deployHealth.Stalled > 0 {
+			details += fmt.Sprintf(", Deadline exceeded: %d", deployHealth.Stalled)
+		}
 		status := fmt.Sprintf("%s %s", getStatusIcon(healthy), getHealthStatus(healthy))
 		row, colors := output.StatusRow("Deployments", status, details)
 		healthTable.AddColoredRow(row, colors)
 	}
 
-	// Check services
-	output.StartSpinner("Checking services...")
-	svcHealth, err := client.GetServiceHealth(ctx, namespace)
-	if err != nil {
-		output.SpinnerError("Failed to check services")
-	} else {
-		output.StopSpinner()
+	// Services
+	if svcHealth := report.Services; svcHealth != nil {
 		details := fmt.Sprintf("ClusterIP: %d, LoadBalancer: %d, NodePort: %d",
 			svcHealth.ClusterIP, svcHealth.LoadBalancer, svcHealth.NodePort)
 		row, colors := output.StatusRow("Services", fmt.Sprintf("%s OK", output.IconSuccess), details)
 		healthTable.AddColoredRow(row, colors)
 	}
 
+	// Helm releases
+	problemReleases := report.ProblemReleases()
+	if len(report.HelmReleases) > 0 {
+		var failed, pending int
+		for _, rel := range problemReleases {
+			if rel.Status == helm.StatusFailed {
+				failed++
+			} else {
+				pending++
+			}
+		}
+
+		var status string
+		switch {
+		case failed > 0:
+			status = fmt.Sprintf("%s %d Failed", output.IconError, failed)
+		case pending > 0:
+			status = fmt.Sprintf("%s %d Pending", output.IconWarning, pending)
+		default:
+			status = fmt.Sprintf("%s Healthy", output.IconSuccess)
+		}
+		details := fmt.Sprintf("Releases: %d, Failed: %d, Pending: %d", len(report.HelmReleases), failed, pending)
+		row, colors := output.StatusRow("Helm Releases", status, details)
+		healthTable.AddColoredRow(row, colors)
+	}
+
+	// Restart storms
+	storms := report.Storms
+	if _, failed := report.Errors[run.SectionStorms]; !failed {
+		status := fmt.Sprintf("%s None", output.IconSuccess)
+		details := fmt.Sprintf("No %d+ pods restarting within %s in the last hour", stormMinPods, stormWindow)
+		if len(storms) > 0 {
+			status = fmt.Sprintf("%s %d Detected", output.IconError, len(storms))
+			var namespaces []string
+			for _, storm := range storms {
+				namespaces = append(namespaces, storm.Namespace)
+			}
+			details = "Namespaces: " + strings.Join(namespaces, ", ")
+		}
+		row, colors := output.StatusRow("Restart Storms", status, details)
+		healthTable.AddColoredRow(row, colors)
+	}
+
+	// Sections that could not be checked
+	for _, section := range []struct{ key, label string }{
+		{run.SectionNodes, "nodes"},
+		{run.SectionPods, "pods"},
+		{run.SectionPVCs, "PVCs"},
+		{run.SectionDeployments, "deployments"},
+		{run.SectionServices, "services"},
+		{run.SectionHelm, "Helm releases"},
+		{run.SectionStorms, "restart storms"},
+	} {
+		if msg, ok := report.Errors[section.key]; ok {
+			output.Warning(fmt.Sprintf("Failed to check %s: %s", section.label, msg))
+		}
+	}
+
 	output.Newline()
 	healthTable.Render()
 
+	if len(storms) > 0 {
+		output.Newline()
+		renderRestartStorms(storms)
+	}
+
+	if len(problemReleases) > 0 {
+		releaseTable := output.NewTable(output.TableConfig{
+			Title:      "Unhealthy Helm Releases",
+			Headers:    []string{"Namespace", "Release", "Revision", "Status", "Updated", "Description"},
+			ShowBorder: true,
+		})
+
+		for _, rel := range problemReleases {
+			statusColor := tablewriter.Colors{tablewriter.Bold, tablewriter.FgYellowColor}
+			if rel.Status == helm.StatusFailed {
+				statusColor = tablewriter.Colors{tablewriter.Bold, tablewriter.FgRedColor}
+			}
+			releaseTable.AddColoredRow(
+				[]string{rel.Namespace, rel.Name, fmt.Sprintf("%d", rel.Revision), rel.Status, format.Age(rel.Updated), output.Truncate(rel.Description, 50)},
+				[]tablewriter.Colors{
+					{tablewriter.FgHiBlackColor},
+					{tablewriter.FgCyanColor},
+					{},
+					statusColor,
+					{tablewriter.FgHiBlackColor},
+					{tablewriter.FgWhiteColor},
+				},
+			)
+		}
+
+		output.Newline()
+		releaseTable.Render()
+	}
+
+	if showDetails && deployHealth != nil && len(deployHealth.Deployments) > 0 {
+		output.Newline()
+		renderDeploymentDetails(deployHealth.Deployments)
+	}
+
+	if clusterInfo != nil && len(clusterInfo.NodePools) > 0 {
+		output.Newline()
+		renderNodePools(clusterInfo)
+	}
+
 	// Resource utilization
 	output.Newline()
-	output.StartSpinner("Getting resource utilization...")
-	resources, err := client.GetResourceUtilization(ctx)
-	if err != nil {
-		output.SpinnerError("Could not get resource utilization (metrics-server may not be installed)")
+	if resources := report.Resources; resources == nil {
+		output.Warning("Could not get resource utilization (metrics-server may not be installed)")
 	} else {
-		output.StopSpinner()
-
 		resourceTable := output.NewTable(output.TableConfig{
 			Title:      "Resource Utilization",
 			Headers:    []string{"Resource", "Used", "Capacity", "Utilization"},
@@ -170,7 +299,7 @@ func runHealth(cmd *cobra.Command, args []string) error {
 			getUtilColors(cpuUtil),
 		)
 		resourceTable.AddColoredRow(
-			[]string{"Memory", formatBytes(resources.MemoryUsed), formatBytes(resources.MemoryCapacity), memBar},
+			[]string{"Memory", format.Quantity(resources.MemoryUsed), format.Quantity(resources.MemoryCapacity), memBar},
 			getUtilColors(memUtil),
 		)
 
@@ -180,45 +309,179 @@ func runHealth(cmd *cobra.Command, args []string) error {
 
 	// Recent warning events
 	output.Newline()
-	output.StartSpinner("Getting recent events...")
-	events, err := client.GetWarningEvents(ctx, namespace, 10)
-	if err != nil {
-		output.SpinnerError("Failed to get events")
-	} else {
-		output.StopSpinner()
-		if len(events) > 0 {
-			eventTable := output.NewTable(output.TableConfig{
-				Title:      "Recent Warning Events",
-				Headers:    []string{"Age", "Type", "Object", "Reason", "Message"},
-				ShowBorder: true,
-			})
-
-			for _, event := range events {
-				age := formatAge(event.LastTimestamp)
-				eventTable.AddColoredRow(
-					[]string{age, event.Type, event.Object, event.Reason, truncate(event.Message, 50)},
-					[]tablewriter.Colors{
-						{tablewriter.FgHiBlackColor},
-						{tablewriter.FgYellowColor},
-						{tablewriter.FgCyanColor},
-						{tablewriter.FgYellowColor},
-						{tablewriter.FgWhiteColor},
-					},
-				)
-			}
+	if msg, ok := report.Errors[run.SectionEvents]; ok {
+		output.Warning("Failed to get events: " + msg)
+	} else if len(report.Events) > 0 {
+		eventTable := output.NewTable(output.TableConfig{
+			Title:      "Recent Warning Events",
+			Headers:    []string{"Age", "Type", "Object", "Reason", "Message"},
+			ShowBorder: true,
+		})
 
-			output.Newline()
-			eventTable.Render()
-		} else {
-			output.Newline()
-			output.Success("No warning events in the last hour")
+		for _, event := range report.Events {
+			age := format.Age(event.LastTimestamp)
+			eventTable.AddColoredRow(
+				[]string{age, event.Type, event.Object, event.Reason, output.Truncate(event.Message, 50)},
+				[]tablewriter.Colors{
+					{tablewriter.FgHiBlackColor},
+					{tablewriter.FgYellowColor},
+					{tablewriter.FgCyanColor},
+					{tablewriter.FgYellowColor},
+					{tablewriter.FgWhiteColor},
+				},
+			)
 		}
+
+		output.Newline()
+		eventTable.Render()
+	} else {
+		output.Newline()
+		output.Success("No warning events in the last hour")
 	}
 
 	output.Newline()
 	return nil
 }
 
+// renderRestartStorms prints each restart storm with the likely trigger
+func renderRestartStorms(storms []k8s.RestartStorm) {
+	table := output.NewTable(output.TableConfig{
+		Title:      "Restart Storms",
+		Headers:    []string{"Namespace", "Pods", "Restarts", "Started", "Span", "Nodes", "Likely Trigger"},
+		ShowBorder: true,
+	})
+
+	for _, storm := range storms {
+		trigger := "unknown (check application logs and dependencies)"
+		triggerColor := tablewriter.Colors{tablewriter.FgHiBlackColor}
+		if len(storm.Triggers) > 0 {
+			trigger = strings.Join(storm.Triggers, "; ")
+			triggerColor = tablewriter.Colors{tablewriter.FgYellowColor}
+		}
+
+		table.AddColoredRow(
+			[]string{
+				storm.Namespace,
+				fmt.Sprintf("%d", len(storm.Pods)),
+				fmt.Sprintf("%d", storm.Restarts),
+				format.Ago(storm.Start),
+				storm.End.Sub(storm.Start).Round(time.Second).String(),
+				output.Truncate(strings.Join(storm.Nodes, ","), 30),
+				output.Truncate(trigger, 60),
+			},
+			[]tablewriter.Colors{
+				{tablewriter.FgCyanColor},
+				{tablewriter.Bold, tablewriter.FgRedColor},
+				{tablewriter.FgRedColor},
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgWhiteColor},
+				triggerColor,
+			},
+		)
+	}
+
+	table.Render()
+}
+
+// controlPlaneRow summarizes the managed control plane version and upgrade schedule
+func controlPlaneRow(cloud *k8s.CloudDetails) ([]string, []tablewriter.Colors) {
+	var details []string
+	status := fmt.Sprintf("%s Up to date", output.IconSuccess)
+	if cloud.UpgradeAvailable() {
+		status = fmt.Sprintf("%s Upgrade available", output.IconWarning)
+		details = append(details, fmt.Sprintf("%s → %s", cloud.ControlPlaneVersion, cloud.LatestVersion))
+	} else {
+		details = append(details, cloud.ControlPlaneVersion)
+	}
+	if cloud.AutoUpgrade != "" {
+		upgrade := cloud.AutoUpgrade
+		if !cloud.AutoUpgradeAt.IsZero() {
+			upgrade += " " + cloud.AutoUpgradeAt.Format("2006-01-02")
+		}
+		details = append(details, upgrade)
+	}
+	if cloud.MaintenanceWindow != "" {
+		details = append(details, "window: "+cloud.MaintenanceWindow)
+	}
+
+	row, colors := output.StatusRow("Control Plane", status, strings.Join(details, ", "))
+	if cloud.UpgradeAvailable() {
+		colors[1] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgYellowColor}
+	} else {
+		colors[1] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgGreenColor}
+	}
+	return row, colors
+}
+
+// renderNodePools prints node groups / node pools, with cloud settings when available
+func renderNodePools(info *k8s.ClusterInfo) {
+	headers := []string{"Node Pool", "Nodes", "Kubelet", "Instance Types", "Zones"}
+	cloudPools := make(map[string]k8s.CloudNodePool)
+	if info.Cloud != nil {
+		headers = append(headers, "Auto-Upgrade", "Status")
+		for _, np := range info.Cloud.NodePools {
+			cloudPools[np.Name] = np
+		}
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Node Pools",
+		Headers:    headers,
+		ShowBorder: true,
+	})
+
+	for _, pool := range info.NodePools {
+		readyColor := tablewriter.Colors{tablewriter.FgGreenColor}
+		if pool.Ready < pool.Nodes {
+			readyColor = tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold}
+		}
+
+		// Kubelets behind the control plane minor version are due an upgrade
+		versionColor := tablewriter.Colors{}
+		for _, v := range pool.Versions {
+			if k8s.CompareVersions(k8s.MinorVersion(v), k8s.MinorVersion(info.K8sVersion)) < 0 {
+				versionColor = tablewriter.Colors{tablewriter.FgYellowColor}
+			}
+		}
+
+		row := []string{
+			pool.Name,
+			fmt.Sprintf("%d/%d", pool.Ready, pool.Nodes),
+			strings.Join(pool.Versions, ","),
+			output.Truncate(strings.Join(pool.InstanceTypes, ","), 30),
+			output.Truncate(strings.Join(pool.Zones, ","), 40),
+		}
+		colors := []tablewriter.Colors{
+			{tablewriter.FgCyanColor},
+			readyColor,
+			versionColor,
+			{},
+			{tablewriter.FgHiBlackColor},
+		}
+
+		if info.Cloud != nil {
+			autoUpgrade, status := "-", "-"
+			autoColor := tablewriter.Colors{tablewriter.FgHiBlackColor}
+			if np, ok := cloudPools[pool.Name]; ok {
+				status = np.Status
+				autoUpgrade = "off"
+				autoColor = tablewriter.Colors{tablewriter.FgYellowColor}
+				if np.AutoUpgrade {
+					autoUpgrade = "on"
+					autoColor = tablewriter.Colors{tablewriter.FgGreenColor}
+				}
+			}
+			row = append(row, autoUpgrade, status)
+			colors = append(colors, autoColor, tablewriter.Colors{})
+		}
+
+		table.AddColoredRow(row, colors)
+	}
+
+	table.Render()
+}
+
 func getStatusIcon(healthy bool) string {
 	if healthy {
 		return output.IconSuccess
@@ -234,15 +497,7 @@ func getHealthStatus(healthy bool) string {
 }
 
 func getUtilColors(util float64) []tablewriter.Colors {
-	var statusColor int
-	switch {
-	case util > 90:
-		statusColor = tablewriter.FgRedColor
-	case util > 70:
-		statusColor = tablewriter.FgYellowColor
-	default:
-		statusColor = tablewriter.FgGreenColor
-	}
+	statusColor := utilColor(util)
 
 	return []tablewriter.Colors{
 		{tablewriter.FgCyanColor},
@@ -252,46 +507,61 @@ func getUtilColors(util float64) []tablewriter.Colors {
 	}
 }
 
-func formatBytes(bytes int64) string {
-	const (
-		KB = 1024
-		MB = KB * 1024
-		GB = MB * 1024
-	)
+// renderDeploymentDetails lists every deployment with its rollout state
+func renderDeploymentDetails(deployments []k8s.DeploymentStatus) {
+	table := output.NewTable(output.TableConfig{
+		Title:      "Deployments",
+		Headers:    []string{"Namespace", "Deployment", "Ready", "Up-to-date", "Available", "State", "Message"},
+		ShowBorder: true,
+	})
 
-	switch {
-	case bytes >= GB:
-		return fmt.Sprintf("%.1fGi", float64(bytes)/float64(GB))
-	case bytes >= MB:
-		return fmt.Sprintf("%.1fMi", float64(bytes)/float64(MB))
-	case bytes >= KB:
-		return fmt.Sprintf("%.1fKi", float64(bytes)/float64(KB))
-	default:
-		return fmt.Sprintf("%dB", bytes)
-	}
-}
+	for _, d := range deployments {
+		var stateColor int
+		switch d.State {
+		case k8s.DeploymentHealthy:
+			stateColor = tablewriter.FgGreenColor
+		case k8s.DeploymentPaused:
+			stateColor = tablewriter.FgHiBlackColor
+		case k8s.DeploymentProgressing:
+			stateColor = tablewriter.FgCyanColor
+		case k8s.DeploymentStalled:
+			stateColor = tablewriter.FgRedColor
+		default:
+			stateColor = tablewriter.FgYellowColor
+		}
 
-func formatAge(t time.Time) string {
-	if t.IsZero() {
-		return "unknown"
+		table.AddColoredRow(
+			[]string{
+				d.Namespace,
+				d.Name,
+				fmt.Sprintf("%d/%d", d.Ready, d.Desired),
+				fmt.Sprintf("%d", d.Updated),
+				fmt.Sprintf("%d", d.Available),
+				d.State,
+				output.Truncate(d.Message, 50),
+			},
+			[]tablewriter.Colors{
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgCyanColor},
+				{},
+				{},
+				{},
+				{tablewriter.Bold, stateColor},
+				{tablewriter.FgWhiteColor},
+			},
+		)
 	}
 
-	d := time.Since(t)
-	switch {
-	case d < time.Minute:
-		return fmt.Sprintf("%ds", int(d.Seconds()))
-	case d < time.Hour:
-		return fmt.Sprintf("%dm", int(d.Minutes()))
-	case d < 24*time.Hour:
-		return fmt.Sprintf("%dh", int(d.Hours()))
-	default:
-		return fmt.Sprintf("%dd", int(d.Hours()/24))
-	}
+	table.Render()
 }
 
-func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
+func utilColor(util float64) int {
+	switch {
+	case util > 90:
+		return tablewriter.FgRedColor
+	case util > 70:
+		return tablewriter.FgYellowColor
+	default:
+		return tablewriter.FgGreenColor
 	}
-	return s[:maxLen-3] + "..."
 }
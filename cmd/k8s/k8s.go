@@ -2,6 +2,7 @@ package k8s
 
 import (
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
 	"github.com/spf13/cobra"
 )
 
@@ -23,7 +24,22 @@ with beautiful, informative output that goes beyond kubectl.`,
 	cmd.AddCommand(newNodesCmd())
 	cmd.AddCommand(newCleanupCmd())
 	cmd.AddCommand(newResourcesCmd())
+	cmd.AddCommand(newQoSCmd())
 	cmd.AddCommand(newEventsCmd())
+	cmd.AddCommand(newEvictionsCmd())
+	cmd.AddCommand(newAutoscalingCmd())
+	cmd.AddCommand(newTimelineCmd())
+	cmd.AddCommand(newEtcdBackupCmd())
+	cmd.AddCommand(newKubeconfigAuditCmd())
+	cmd.AddCommand(newAuditAnalyzeCmd())
+	cmd.AddCommand(newMakeKubeconfigCmd())
+	cmd.AddCommand(newSecretsReportCmd())
+	cmd.AddCommand(newTopCmd())
+	cmd.AddCommand(newRolloutsCmd())
+	cmd.AddCommand(newRunJobCmd())
+	cmd.AddCommand(newDebugCmd())
+	cmd.AddCommand(newNodeShellCmd())
+	cmd.AddCommand(newTreeCmd())
 
 	// Persistent flags for k8s commands
 	cmd.PersistentFlags().StringP("namespace", "n", "", "Kubernetes namespace (default: all namespaces)")
@@ -36,3 +52,19 @@ with beautiful, informative output that goes beyond kubectl.`,
 
 	return cmd
 }
+
+// addNamespaceSelectorFlags registers --namespaces and --exclude-namespaces on a cluster-wide command
+func addNamespaceSelectorFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("namespaces", nil, "Only include these namespaces (globs allowed, e.g. team-*)")
+	cmd.Flags().StringSlice("exclude-namespaces", nil, "Skip these namespaces (globs allowed)")
+
+	_ = cmd.RegisterFlagCompletionFunc("namespaces", completion.NamespaceCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("exclude-namespaces", completion.NamespaceCompletion)
+}
+
+// namespaceSelector reads the selector registered by addNamespaceSelectorFlags
+func namespaceSelector(cmd *cobra.Command) k8s.NamespaceSelector {
+	include, _ := cmd.Flags().GetStringSlice("namespaces")
+	exclude, _ := cmd.Flags().GetStringSlice("exclude-namespaces")
+	return k8s.NamespaceSelector{Include: include, Exclude: exclude}
+}
@@ -0,0 +1,184 @@
+package k8s
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newKubeconfigAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "kubeconfig-audit",
+		Short: "Audit kubeconfig files for credential and hygiene issues",
+		Long: `Inspect kubeconfig files for problems that accumulate over time.
+
+Checks:
+  • Embedded client certificates that are expired, near expiry or long-lived
+  • Plaintext bearer tokens and basic-auth passwords
+  • Duplicate clusters pointing at the same server
+  • Contexts, clusters and users that are dangling or unused
+  • Servers that no longer accept connections
+
+Examples:
+  # Audit the files kubectl would load (KUBECONFIG or ~/.kube/config)
+  devops-toolkit k8s kubeconfig-audit
+
+  # Audit specific files offline and print cleanup commands
+  devops-toolkit k8s kubeconfig-audit -f old.yaml -f ci.yaml --skip-connectivity --suggest-cleanup`,
+		RunE: runKubeconfigAudit,
+	}
+
+	cmd.Flags().StringSliceP("file", "f", nil, "Kubeconfig files to audit (default: --kubeconfig, KUBECONFIG or ~/.kube/config)")
+	cmd.Flags().Int("warn-days", 30, "Warn when a client certificate expires within this many days")
+	cmd.Flags().Bool("skip-connectivity", false, "Do not check whether cluster servers are reachable")
+	cmd.Flags().Duration("timeout", 3*time.Second, "Connection timeout per server")
+	cmd.Flags().Bool("suggest-cleanup", false, "Print kubectl commands that remove stale entries")
+
+	// Register flag completions
+	_ = cmd.MarkFlagFilename("file", "yaml", "yml", "conf", "config")
+
+	return cmd
+}
+
+func runKubeconfigAudit(cmd *cobra.Command, args []string) error {
+	files, _ := cmd.Flags().GetStringSlice("file")
+	warnDays, _ := cmd.Flags().GetInt("warn-days")
+	skipConnectivity, _ := cmd.Flags().GetBool("skip-connectivity")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	suggestCleanup, _ := cmd.Flags().GetBool("suggest-cleanup")
+
+	if len(files) == 0 {
+		if kubeconfig := cmd.Flag("kubeconfig").Value.String(); kubeconfig != "" {
+			files = []string{kubeconfig}
+		} else {
+			files = k8s.KubeconfigPaths()
+		}
+	}
+
+	output.StartSpinner("Auditing kubeconfig files...")
+
+	ctx := cmd.Context()
+	opts := k8s.KubeconfigAuditOptions{
+		WarnDays:          warnDays,
+		CheckConnectivity: !skipConnectivity,
+		Timeout:           timeout,
+	}
+
+	var findings []k8s.KubeconfigFinding
+	for _, file := range files {
+		result, err := k8s.AuditKubeconfig(ctx, file, opts)
+		if err != nil {
+			output.SpinnerError("Failed to audit kubeconfig")
+			return err
+		}
+		findings = append(findings, result...)
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Audited %d kubeconfig files", len(files)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(findings)
+	}
+
+	if len(findings) == 0 {
+		output.Success("No kubeconfig issues found")
+		return nil
+	}
+
+	headers := []string{"Severity", "Kind", "Name", "Check", "Message"}
+	if len(files) > 1 {
+		headers = append([]string{"File"}, headers...)
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Kubeconfig Audit",
+		Headers:    headers,
+		ShowBorder: true,
+	})
+
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[f.Severity]++
+
+		row := []string{f.Severity, f.Kind, output.Truncate(f.Name, 30), f.Check, output.Truncate(f.Message, 60)}
+		colors := []tablewriter.Colors{
+			{tablewriter.Bold, findingColor(f.Severity)},
+			{tablewriter.FgHiBlackColor},
+			{tablewriter.FgCyanColor},
+			{tablewriter.FgWhiteColor},
+			{tablewriter.FgWhiteColor},
+		}
+		if len(files) > 1 {
+			row = append([]string{output.Truncate(f.File, 30)}, row...)
+			colors = append([]tablewriter.Colors{{tablewriter.FgHiBlackColor}}, colors...)
+		}
+		table.AddColoredRow(row, colors)
+	}
+
+	table.Render()
+
+	output.Newline()
+	output.Print(output.Section("Summary"))
+	if counts[k8s.FindingCritical] > 0 {
+		output.Printf("  %s Critical: %d\n", output.ErrorStyle.Render(output.IconError), counts[k8s.FindingCritical])
+	}
+	if counts[k8s.FindingWarning] > 0 {
+		output.Printf("  %s Warning: %d\n", output.WarningStyle.Render(output.IconWarning), counts[k8s.FindingWarning])
+	}
+	if counts[k8s.FindingInfo] > 0 {
+		output.Printf("  %s Info: %d\n", output.InfoStyle.Render(output.IconInfo), counts[k8s.FindingInfo])
+	}
+
+	if suggestCleanup {
+		printKubeconfigCleanup(findings, len(files) > 1 || cmd.Flags().Changed("file"))
+	}
+
+	output.Newline()
+	return nil
+}
+
+// printKubeconfigCleanup prints the deduplicated cleanup commands for the findings
+func printKubeconfigCleanup(findings []k8s.KubeconfigFinding, withFile bool) {
+	seen := make(map[string]bool)
+	var commands []string
+	for _, f := range findings {
+		if f.Cleanup == "" {
+			continue
+		}
+		command := f.Cleanup
+		if withFile {
+			command += " --kubeconfig " + f.File
+		}
+		if !seen[command] {
+			seen[command] = true
+			commands = append(commands, command)
+		}
+	}
+
+	output.Newline()
+	output.Print(output.Section("Suggested Cleanup"))
+	if len(commands) == 0 {
+		output.Muted("  Nothing to clean up")
+		return
+	}
+	output.Muted("  Review before running; back up the kubeconfig first")
+	for _, command := range commands {
+		output.Printf("  %s %s\n", output.MutedStyle.Render(output.IconArrow), command)
+	}
+}
+
+func findingColor(severity string) int {
+	switch severity {
+	case k8s.FindingCritical:
+		return tablewriter.FgRedColor
+	case k8s.FindingWarning:
+		return tablewriter.FgYellowColor
+	default:
+		return tablewriter.FgBlueColor
+	}
+}
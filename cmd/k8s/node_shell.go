@@ -0,0 +1,107 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newNodeShellCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "node-shell <node>",
+		Short: "Open a root shell on a node through a privileged pod",
+		Long: `Start a privileged pod pinned to a node, enter the host's mount, UTS,
+IPC, network and PID namespaces with nsenter and attach a login shell.
+The pod is deleted when the shell exits.
+
+This gives full root access to the node, the same as SSH as root: use it
+for node-level debugging (kubelet and container runtime logs, disk and
+network issues) when SSH is not available. The pod tolerates every taint,
+runs in kube-system unless -n is set, and is killed by the kubelet after an
+hour if cleanup is interrupted. Requires a namespace whose Pod Security
+level allows privileged pods.
+
+Examples:
+  devops-toolkit k8s node-shell ip-10-0-1-23.ec2.internal
+  devops-toolkit k8s node-shell worker-3 --yes`,
+		Args:              cobra.ExactArgs(1),
+		RunE:              runNodeShell,
+		ValidArgsFunction: completion.NodeCompletion,
+	}
+
+	cmd.Flags().String("image", "busybox:1.36", "Image providing nsenter and sh")
+	cmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+
+	return cmd
+}
+
+func runNodeShell(cmd *cobra.Command, args []string) error {
+	image, _ := cmd.Flags().GetString("image")
+	yes, _ := cmd.Flags().GetBool("yes")
+	node := args[0]
+
+	namespace := cmd.Flag("namespace").Value.String()
+	if namespace == "" {
+		namespace = "kube-system"
+	}
+
+	output.Warning(fmt.Sprintf("This starts a privileged pod with root access to node %s.", node))
+	output.Muted("  Everything you run affects the host directly: processes, files, network and the kubelet.")
+	if !yes {
+		if !output.IsTerminal(os.Stdin) {
+			return exitcode.ConfigError(fmt.Errorf("refusing to start a node shell without confirmation; pass --yes"))
+		}
+		if !confirmNodeShell(node) {
+			output.Info("Aborted")
+			return nil
+		}
+	}
+
+	output.StartSpinner(fmt.Sprintf("Starting node shell pod on %s...", node))
+
+	client, err := k8s.NewClient(
+		cmd.Flag("kubeconfig").Value.String(),
+		cmd.Flag("context").Value.String(),
+	)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return err
+	}
+
+	pod, err := client.CreateNodeShellPod(cmd.Context(), namespace, node, image)
+	if err != nil {
+		output.SpinnerError("Failed to start node shell pod")
+		return err
+	}
+	// Delete the pod however the shell ends, including Ctrl-C
+	defer func() {
+		if err := client.DeletePod(context.Background(), namespace, pod); err != nil {
+			output.Warning(fmt.Sprintf("Failed to delete pod %s/%s: %v", namespace, pod, err))
+			output.Printf("  %s kubectl delete pod %s -n %s\n", output.IconArrow, pod, namespace)
+			return
+		}
+		output.Successf("Deleted node shell pod %s/%s", namespace, pod)
+	}()
+
+	output.SpinnerSuccess(fmt.Sprintf("Pod %s/%s running on %s", namespace, pod, node))
+	output.Muted("  Exit the shell to remove the pod.")
+	output.Newline()
+
+	return client.NodeShell(cmd.Context(), namespace, pod, output.IsTerminal(os.Stdin))
+}
+
+// confirmNodeShell asks the user to type the node name, so a shell on the
+// wrong node cannot be opened by reflex
+func confirmNodeShell(node string) bool {
+	output.Printf("Type the node name to continue: ")
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(line) == node
+}
@@ -1,11 +1,12 @@
 package k8s
 
 import (
-	"context"
 	"fmt"
 
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/resultcache"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
@@ -26,8 +27,9 @@ Features:
 
 	cmd.Flags().Bool("wide", false, "Show additional information")
 	cmd.Flags().Bool("resources", false, "Show detailed resource info")
+	cmd.Flags().Duration("cache", 0, "Serve the last result up to this old, marked STALE, when the cluster is unreachable")
 
-	return cmd
+	return output.Watchable(cmd)
 }
 
 func runNodes(cmd *cobra.Command, args []string) error {
@@ -42,19 +44,35 @@ func runNodes(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
 
-	ctx := context.Background()
+	ctx := cmd.Context()
 	wide, _ := cmd.Flags().GetBool("wide")
 	showResources, _ := cmd.Flags().GetBool("resources")
+	cacheAge, _ := cmd.Flags().GetDuration("cache")
 
+	cacheKey := resultcache.Key("k8s nodes", client.Server())
 	nodes, err := client.ListNodes(ctx)
+	stale := ""
 	if err != nil {
-		output.SpinnerError("Failed to fetch nodes")
-		return fmt.Errorf("failed to list nodes: %w", err)
+		if stale = resultcache.Fallback(cacheKey, cacheAge, &nodes, err); stale == "" {
+			output.SpinnerError("Failed to fetch nodes")
+			return fmt.Errorf("failed to list nodes: %w", err)
+		}
+	} else if cacheAge > 0 {
+		_ = resultcache.Save(cacheKey, nodes)
 	}
 
-	output.SpinnerSuccess(fmt.Sprintf("Found %d nodes", len(nodes)))
+	if stale != "" {
+		output.StopSpinner()
+		output.Warning(stale)
+	} else {
+		output.SpinnerSuccess(fmt.Sprintf("Found %d nodes", len(nodes)))
+	}
 	output.Newline()
 
+	if output.IsStructured() {
+		return output.RenderStale(nodes, stale != "")
+	}
+
 	// Build headers
 	headers := []string{"Name", "Status", "Roles", "Age", "Version"}
 	if showResources {
@@ -87,7 +105,7 @@ func runNodes(cmd *cobra.Command, args []string) error {
 			node.Name,
 			fmt.Sprintf("%s %s", statusIcon, status),
 			node.Roles,
-			formatAge(node.CreationTime),
+			format.Age(node.CreationTime),
 			node.KubeletVersion,
 		}
 
@@ -1,16 +1,19 @@
 package k8s
 
 import (
-	"context"
 	"fmt"
-	"sort"
+	"os"
 	"strings"
 
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/resultcache"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/run"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func newPodsCmd() *cobra.Command {
@@ -33,14 +36,20 @@ Features:
 	cmd.Flags().Bool("wide", false, "Show additional information")
 	cmd.Flags().StringP("sort", "s", "name", "Sort by: name, status, age, restarts, namespace")
 	cmd.Flags().StringP("label", "l", "", "Label selector")
+	cmd.Flags().Duration("cache", 0, "Serve the last result up to this old, marked STALE, when the cluster is unreachable")
+	cmd.Flags().BoolP("quiet", "q", false, "Only print pod names (namespace/name with -A)")
 
 	// Register flag completions
 	_ = cmd.RegisterFlagCompletionFunc("sort", completion.PodSortCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("label", completion.LabelCompletion)
 
-	return cmd
+	return output.Watchable(cmd)
 }
 
 func runPods(cmd *cobra.Command, args []string) error {
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		output.SetQuiet()
+	}
 	output.StartSpinner("Fetching pods...")
 
 	client, err := k8s.NewClient(
@@ -52,37 +61,42 @@ func runPods(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
 
-	ctx := context.Background()
+	ctx := cmd.Context()
 	namespace := cmd.Flag("namespace").Value.String()
 	allNamespaces, _ := cmd.Flags().GetBool("all-namespaces")
 	problemsOnly, _ := cmd.Flags().GetBool("problems")
 	wide, _ := cmd.Flags().GetBool("wide")
 	sortBy, _ := cmd.Flags().GetString("sort")
 	labelSelector, _ := cmd.Flags().GetString("label")
+	cacheAge, _ := cmd.Flags().GetDuration("cache")
 
 	if allNamespaces {
 		namespace = ""
 	}
 
+	cacheKey := resultcache.Key("k8s pods", client.Server(), namespace, labelSelector)
 	pods, err := client.ListPods(ctx, namespace, labelSelector)
+	stale := ""
 	if err != nil {
-		output.SpinnerError("Failed to fetch pods")
-		return fmt.Errorf("failed to list pods: %w", err)
+		if stale = resultcache.Fallback(cacheKey, cacheAge, &pods, err); stale == "" {
+			output.SpinnerError("Failed to fetch pods")
+			return fmt.Errorf("failed to list pods: %w", err)
+		}
+	} else if cacheAge > 0 {
+		_ = resultcache.Save(cacheKey, pods)
 	}
 
-	output.SpinnerSuccess(fmt.Sprintf("Found %d pods", len(pods)))
+	if stale != "" {
+		output.StopSpinner()
+		output.Warning(stale)
+	} else {
+		output.SpinnerSuccess(fmt.Sprintf("Found %d pods", len(pods)))
+	}
 	output.Newline()
 
-	// Filter problematic pods if requested
+	pods = run.SelectPods(pods, run.PodOptions{ProblemsOnly: problemsOnly, SortBy: sortBy})
 	if problemsOnly {
-		var filtered []k8s.PodInfo
-		for _, pod := range pods {
-			if isProblemPod(pod) {
-				filtered = append(filtered, pod)
-			}
-		}
-		pods = filtered
-		if len(pods) == 0 {
+		if len(pods) == 0 && !output.IsStructured() {
 			output.Success("No problematic pods found!")
 			return nil
 		}
@@ -90,8 +104,20 @@ func runPods(cmd *cobra.Command, args []string) error {
 		output.Newline()
 	}
 
-	// Sort pods
-	sortPods(pods, sortBy)
+	if output.IsQuiet() {
+		names := make([]string, len(pods))
+		for i, pod := range pods {
+			names[i] = pod.Name
+			if allNamespaces {
+				names[i] = pod.Namespace + "/" + pod.Name
+			}
+		}
+		return output.PrintIDs(names)
+	}
+
+	if output.IsStructured() {
+		return output.RenderStale(pods, stale != "")
+	}
 
 	// Build table
 	headers := []string{"Namespace", "Name", "Ready", "Status", "Restarts", "Age"}
@@ -114,7 +140,7 @@ func runPods(cmd *cobra.Command, args []string) error {
 	for _, pod := range pods {
 		ready := fmt.Sprintf("%d/%d", pod.ReadyContainers, pod.TotalContainers)
 		restarts := fmt.Sprintf("%d", pod.Restarts)
-		age := formatAge(pod.CreationTime)
+		age := format.Age(pod.CreationTime)
 
 		row := []string{pod.Namespace, pod.Name, ready, pod.Status, restarts, age}
 		if wide {
@@ -131,40 +157,29 @@ func runPods(cmd *cobra.Command, args []string) error {
 	output.Newline()
 	printPodSummary(statusCounts)
 
+	if problemsOnly {
+		printLokiHints(pods)
+	}
+
 	return nil
 }
 
-func isProblemPod(pod k8s.PodInfo) bool {
-	problemStatuses := []string{
-		"CrashLoopBackOff", "Error", "Failed", "ImagePullBackOff",
-		"ErrImagePull", "Pending", "Unknown", "Terminating",
-	}
-	for _, status := range problemStatuses {
-		if strings.Contains(pod.Status, status) {
-			return true
-		}
+// printLokiHints suggests logs queries when Loki is configured
+func printLokiHints(pods []k8s.PodInfo) {
+	if os.Getenv("LOKI_URL") == "" && viper.GetString("loki.url") == "" {
+		return
 	}
-	return pod.Restarts > 5 || pod.ReadyContainers < pod.TotalContainers
-}
 
-func sortPods(pods []k8s.PodInfo, sortBy string) {
-	sort.Slice(pods, func(i, j int) bool {
-		switch sortBy {
-		case "status":
-			return pods[i].Status < pods[j].Status
-		case "age":
-			return pods[i].CreationTime.After(pods[j].CreationTime)
-		case "restarts":
-			return pods[i].Restarts > pods[j].Restarts
-		case "namespace":
-			if pods[i].Namespace == pods[j].Namespace {
-				return pods[i].Name < pods[j].Name
-			}
-			return pods[i].Namespace < pods[j].Namespace
-		default: // name
-			return pods[i].Name < pods[j].Name
+	output.Newline()
+	output.Print(output.Section("Logs (including previous restarts)"))
+	for i, pod := range pods {
+		if i == 5 {
+			output.Muted(fmt.Sprintf("  ... and %d more", len(pods)-i))
+			break
 		}
-	})
+		output.Printf("  %s devops-toolkit logs query --pod %s -n %s --since 1h\n",
+			output.IconArrow, pod.Name, pod.Namespace)
+	}
 }
 
 func getPodRowColors(pod k8s.PodInfo, wide bool) []tablewriter.Colors {
@@ -0,0 +1,196 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newQoSCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "qos",
+		Short: "Show pod QoS classes and priority classes per namespace",
+		Long: `Summarize running and pending pods by QoS class (Guaranteed, Burstable,
+BestEffort) and priority class for each namespace.
+
+BestEffort pods have no requests or limits and are the first to be evicted
+under node pressure. Critical namespaces containing BestEffort pods are
+highlighted; a namespace is critical when it matches --critical-namespaces
+or runs pods at or above --critical-priority.
+
+Pair with compliance rules K8S-RES-001..004 to find the containers that
+are missing requests and limits.
+
+Examples:
+  devops-toolkit k8s qos
+  devops-toolkit k8s qos --critical-namespaces 'kube-system,prod-*'
+  devops-toolkit k8s qos --best-effort-only --output json`,
+		RunE: runQoS,
+	}
+
+	cmd.Flags().StringSlice("critical-namespaces", []string{"kube-system"}, "Namespaces treated as critical (globs allowed)")
+	cmd.Flags().Int32("critical-priority", 1000000, "Namespaces with pods at or above this priority are treated as critical")
+	cmd.Flags().Bool("best-effort-only", false, "Only show namespaces with BestEffort pods")
+	addNamespaceSelectorFlags(cmd)
+
+	return cmd
+}
+
+// qosRow is a namespace summary with its criticality for rendering
+type qosRow struct {
+	k8s.QoSSummary
+	Critical bool `json:"critical"`
+}
+
+func runQoS(cmd *cobra.Command, args []string) error {
+	criticalNamespaces, _ := cmd.Flags().GetStringSlice("critical-namespaces")
+	criticalPriority, _ := cmd.Flags().GetInt32("critical-priority")
+	bestEffortOnly, _ := cmd.Flags().GetBool("best-effort-only")
+
+	output.StartSpinner("Collecting pod QoS classes...")
+
+	client, err := k8s.NewClient(
+		cmd.Flag("kubeconfig").Value.String(),
+		cmd.Flag("context").Value.String(),
+	)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	client.SetNamespaceSelector(namespaceSelector(cmd))
+
+	summaries, err := client.QoSDistribution(cmd.Context(), cmd.Flag("namespace").Value.String())
+	if err != nil {
+		output.SpinnerError("Failed to collect QoS classes")
+		return err
+	}
+
+	critical := k8s.NamespaceSelector{Include: criticalNamespaces}
+	var rows []qosRow
+	for _, s := range summaries {
+		if bestEffortOnly && s.BestEffort == 0 {
+			continue
+		}
+		rows = append(rows, qosRow{
+			QoSSummary: s,
+			Critical:   (len(criticalNamespaces) > 0 && critical.Matches(s.Namespace)) || s.MaxPriority >= criticalPriority,
+		})
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Summarized %d namespaces", len(rows)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(rows)
+	}
+
+	if len(rows) == 0 {
+		output.Info("No pods found")
+		return nil
+	}
+
+	// Critical namespaces with BestEffort pods first, then by BestEffort share
+	sort.SliceStable(rows, func(i, j int) bool {
+		ai, aj := rows[i].Critical && rows[i].BestEffort > 0, rows[j].Critical && rows[j].BestEffort > 0
+		if ai != aj {
+			return ai
+		}
+		return rows[i].BestEffortPercent() > rows[j].BestEffortPercent()
+	})
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "QoS and Priority by Namespace",
+		Headers:    []string{"Namespace", "Pods", "Guaranteed", "Burstable", "BestEffort", "BestEffort %", "Priority Classes"},
+		ShowBorder: true,
+	})
+
+	var atRisk []string
+	totals := make(map[string]int)
+	for _, row := range rows {
+		totals["Guaranteed"] += row.Guaranteed
+		totals["Burstable"] += row.Burstable
+		totals["BestEffort"] += row.BestEffort
+
+		nsColor := tablewriter.Colors{tablewriter.FgCyanColor}
+		bestEffortColor := tablewriter.Colors{tablewriter.FgGreenColor}
+		switch {
+		case row.Critical && row.BestEffort > 0:
+			atRisk = append(atRisk, row.Namespace)
+			nsColor = tablewriter.Colors{tablewriter.Bold, tablewriter.FgRedColor}
+			bestEffortColor = tablewriter.Colors{tablewriter.Bold, tablewriter.FgRedColor}
+		case row.BestEffortPercent() > 50:
+			bestEffortColor = tablewriter.Colors{tablewriter.FgYellowColor}
+		case row.BestEffort > 0:
+			bestEffortColor = tablewriter.Colors{tablewriter.FgWhiteColor}
+		}
+
+		name := row.Namespace
+		if row.Critical {
+			name += " *"
+		}
+
+		table.AddColoredRow(
+			[]string{
+				name,
+				fmt.Sprintf("%d", row.Pods),
+				fmt.Sprintf("%d", row.Guaranteed),
+				fmt.Sprintf("%d", row.Burstable),
+				fmt.Sprintf("%d", row.BestEffort),
+				fmt.Sprintf("%.0f%%", row.BestEffortPercent()),
+				output.Truncate(formatPriorityClasses(row.PriorityClasses), 50),
+			},
+			[]tablewriter.Colors{
+				nsColor,
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgGreenColor},
+				{tablewriter.FgWhiteColor},
+				bestEffortColor,
+				bestEffortColor,
+				{tablewriter.FgHiBlackColor},
+			},
+		)
+	}
+
+	table.Render()
+	output.Muted("  * critical namespace")
+
+	output.Newline()
+	output.Print(output.Section("Summary"))
+	for _, class := range []string{"Guaranteed", "Burstable", "BestEffort"} {
+		output.Printf("  %s\n", output.KeyValue(class, fmt.Sprintf("%d", totals[class])))
+	}
+	if len(atRisk) > 0 {
+		output.Newline()
+		output.Warningf("Critical namespaces with BestEffort pods: %s", strings.Join(atRisk, ", "))
+		output.Muted("  These pods are evicted first under node pressure; set requests and limits")
+		output.Muted("  (devops-toolkit compliance check k8s --only K8S-RES-001,K8S-RES-002,K8S-RES-003,K8S-RES-004)")
+	}
+
+	output.Newline()
+	return nil
+}
+
+// formatPriorityClasses renders class counts, most used first
+func formatPriorityClasses(classes map[string]int) string {
+	names := make([]string, 0, len(classes))
+	for name := range classes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if classes[names[i]] != classes[names[j]] {
+			return classes[names[i]] > classes[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%d", name, classes[name]))
+	}
+	return strings.Join(parts, ", ")
+}
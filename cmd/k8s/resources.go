@@ -2,12 +2,24 @@ package k8s
 
 import (
 	"context"
+	"encoding/csv"
 	"fmt"
-
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/prometheus"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/secrets"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func newResourcesCmd() *cobra.Command {
@@ -21,12 +33,40 @@ Shows:
   • CPU and Memory requests vs limits
   • Actual usage (requires metrics-server)
   • Over-provisioned resources
-  • Resource quotas`,
+  • Resource quotas
+
+With --top-pods, pods are ranked by usage (from metrics-server, or requests
+when it is not installed), by requested capacity left unused, and by
+missing requests. --view picks one of these.
+
+With --prometheus-url, pod usage is the P95 over --window from cAdvisor
+metrics instead of requests, and a right-sizing table suggests requests
+(P95 plus 20% headroom) for over- and under-provisioned pods.
+
+With --format csv or json, the per-namespace report (requests, limits, pod
+counts by phase and top workloads) is written for chargeback spreadsheets
+instead of the tables.`,
+		Example: `  devops-toolkit k8s resources --top-pods
+  devops-toolkit k8s resources --top-pods --view over-requested
+  devops-toolkit k8s resources -n payments --prometheus-url http://prometheus:9090
+  devops-toolkit k8s resources --prometheus-url http://prometheus:9090 --window 14d
+  devops-toolkit k8s resources --format csv -o namespaces.csv`,
 		RunE: runResources,
 	}
 
 	cmd.Flags().Bool("top-pods", false, "Show top resource consuming pods")
 	cmd.Flags().Int("limit", 10, "Number of top pods to show")
+	cmd.Flags().String("view", "all", "Top pods view: usage, over-requested, no-requests, all")
+	cmd.Flags().String("prometheus-url", "", "Prometheus URL for historical usage (default from PROMETHEUS_URL or prometheus.url)")
+	cmd.Flags().String("window", prometheus.DefaultWindow, "Look-back window for historical usage (Prometheus duration)")
+	cmd.Flags().StringP("format", "f", "table", "Output format (table, csv, json)")
+	cmd.Flags().StringP("output-file", "o", "", "Write the namespace report to a file")
+	cmd.Flags().Int("top-workloads", 3, "Workloads listed per namespace in the namespace report")
+	addNamespaceSelectorFlags(cmd)
+
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("format", completion.ResourcesFormatCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("view", completion.TopPodsViewCompletion)
 
 	return cmd
 }
@@ -42,11 +82,39 @@ func runResources(cmd *cobra.Command, args []string) error {
 		output.SpinnerError("Failed to connect to cluster")
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
+	client.SetNamespaceSelector(namespaceSelector(cmd))
 
-	ctx := context.Background()
+	ctx := cmd.Context()
 	namespace := cmd.Flag("namespace").Value.String()
 	showTopPods, _ := cmd.Flags().GetBool("top-pods")
 	limit, _ := cmd.Flags().GetInt("limit")
+	view, _ := cmd.Flags().GetString("view")
+	window, _ := cmd.Flags().GetString("window")
+	reportFormat, _ := cmd.Flags().GetString("format")
+	outputFile, _ := cmd.Flags().GetString("output-file")
+	topWorkloads, _ := cmd.Flags().GetInt("top-workloads")
+
+	switch reportFormat {
+	case "table", "csv", "json":
+	default:
+		output.SpinnerError("Invalid format")
+		return exitcode.ConfigError(fmt.Errorf("invalid --format value: %s (valid: table, csv, json)", reportFormat))
+	}
+	switch view {
+	case "all", "usage", "over-requested", "no-requests":
+	default:
+		output.SpinnerError("Invalid view")
+		return exitcode.ConfigError(fmt.Errorf("invalid --view value: %s (valid: usage, over-requested, no-requests, all)", view))
+	}
+
+	if reportFormat != "table" || outputFile != "" || output.IsStructured() {
+		return exportNamespaceResources(ctx, client, namespace, reportFormat, outputFile, topWorkloads)
+	}
+
+	promClient, err := getPrometheusClient(cmd)
+	if err != nil {
+		return err
+	}
 
 	output.StopSpinner()
 	output.Header("Resource Usage")
@@ -86,9 +154,9 @@ func runResources(cmd *cobra.Command, args []string) error {
 	summaryTable.AddColoredRow(
 		[]string{
 			"Memory",
-			fmt.Sprintf("%s (%.1f%%)", formatBytes(clusterRes.MemoryRequests), memReqPercent),
-			fmt.Sprintf("%s (%.1f%%)", formatBytes(clusterRes.MemoryLimits), memLimPercent),
-			formatBytes(clusterRes.MemoryAllocatable),
+			fmt.Sprintf("%s (%.1f%%)", format.Quantity(clusterRes.MemoryRequests), memReqPercent),
+			fmt.Sprintf("%s (%.1f%%)", format.Quantity(clusterRes.MemoryLimits), memLimPercent),
+			format.Quantity(clusterRes.MemoryAllocatable),
 			output.ProgressBar(int(memReqPercent), 100, 20),
 		},
 		getResourceRowColors(memReqPercent),
@@ -111,7 +179,7 @@ func runResources(cmd *cobra.Command, args []string) error {
 	if namespace == "" {
 		output.Newline()
 		output.StartSpinner("Getting namespace breakdown...")
-		nsResources, err := client.GetNamespaceResources(ctx)
+		nsResources, err := client.GetNamespaceResources(ctx, topWorkloads)
 		if err != nil {
 			output.SpinnerError("Failed to get namespace resources")
 		} else {
@@ -119,7 +187,7 @@ func runResources(cmd *cobra.Command, args []string) error {
 
 			nsTable := output.NewTable(output.TableConfig{
 				Title:      "Resource Usage by Namespace",
-				Headers:    []string{"Namespace", "Pods", "CPU Requests", "Memory Requests", "CPU %", "Mem %"},
+				Headers:    []string{"Namespace", "Pods", "CPU Req/Lim", "Memory Req/Lim", "CPU %", "Mem %", "Top Workload"},
 				ShowBorder: true,
 			})
 
@@ -127,14 +195,21 @@ func runResources(cmd *cobra.Command, args []string) error {
 				cpuPercent := float64(ns.CPURequests) / float64(clusterRes.CPUAllocatable) * 100
 				memPercent := float64(ns.MemoryRequests) / float64(clusterRes.MemoryAllocatable) * 100
 
+				topWorkload := "-"
+				if len(ns.TopWorkloads) > 0 {
+					w := ns.TopWorkloads[0]
+					topWorkload = fmt.Sprintf("%s/%s", strings.ToLower(w.Kind), w.Name)
+				}
+
 				nsTable.AddColoredRow(
 					[]string{
 						ns.Namespace,
-						fmt.Sprintf("%d", ns.PodCount),
-						fmt.Sprintf("%dm", ns.CPURequests),
-						formatBytes(ns.MemoryRequests),
+						fmt.Sprintf("%d/%d", ns.Running, ns.PodCount),
+						fmt.Sprintf("%dm/%dm", ns.CPURequests, ns.CPULimits),
+						fmt.Sprintf("%s/%s", format.Quantity(ns.MemoryRequests), format.Quantity(ns.MemoryLimits)),
 						fmt.Sprintf("%.1f%%", cpuPercent),
 						fmt.Sprintf("%.1f%%", memPercent),
+						output.Truncate(topWorkload, 35),
 					},
 					[]tablewriter.Colors{
 						{tablewriter.FgCyanColor},
@@ -143,6 +218,7 @@ func runResources(cmd *cobra.Command, args []string) error {
 						{tablewriter.FgWhiteColor},
 						{getResourceColorInt(cpuPercent)},
 						{getResourceColorInt(memPercent)},
+						{tablewriter.FgHiBlackColor},
 					},
 				)
 			}
@@ -152,92 +228,465 @@ func runResources(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Historical usage from Prometheus
+	var podUsage []k8s.PodResourceUsage
+	if promClient != nil {
+		output.Newline()
+		output.StartSpinner(fmt.Sprintf("Querying Prometheus for P95 usage over %s...", window))
+		podUsage, err = historicalPodUsage(ctx, client, promClient, namespace, window)
+		if err != nil {
+			output.SpinnerError("Failed to query Prometheus")
+			return err
+		}
+		output.SpinnerSuccess(fmt.Sprintf("P95 usage for %d pods from %s", len(podUsage), promClient.URL()))
+	}
+
 	// Top resource consuming pods
 	if showTopPods {
 		output.Newline()
 		output.StartSpinner("Getting top pods...")
-		topPods, err := client.GetTopPods(ctx, namespace, limit)
+		var topPods *k8s.TopPods
+		if promClient != nil {
+			topPods = k8s.RankPods(podUsage, limit)
+		} else {
+			topPods, err = client.GetTopPods(ctx, namespace, limit)
+		}
 		if err != nil {
-			output.SpinnerError("Failed to get top pods (metrics-server required)")
+			output.SpinnerError("Failed to get top pods")
 		} else {
 			output.StopSpinner()
 
-			// CPU top
-			cpuTable := output.NewTable(output.TableConfig{
-				Title:      "Top Pods by CPU",
-				Headers:    []string{"#", "Namespace", "Pod", "CPU Usage", "CPU Request", "Utilization"},
-				ShowBorder: true,
-			})
+			measured := false
+			for _, pod := range topPods.ByCPU {
+				measured = measured || pod.HasUsage
+			}
+			usageSuffix := ""
+			switch {
+			case promClient != nil:
+				usageSuffix = fmt.Sprintf(" (P95 over %s)", window)
+			case !measured:
+				usageSuffix = " (requests; metrics-server unavailable)"
+			}
 
-			for i, pod := range topPods.ByCPU {
-				utilPercent := 0.0
-				if pod.CPURequest > 0 {
-					utilPercent = float64(pod.CPUUsage) / float64(pod.CPURequest) * 100
+			if view == "all" || view == "usage" {
+
+				// CPU top
+				cpuTable := output.NewTable(output.TableConfig{
+					Title:      "Top Pods by CPU" + usageSuffix,
+					Headers:    []string{"#", "Namespace", "Pod", "CPU Usage", "CPU Request", "Utilization"},
+					ShowBorder: true,
+				})
+
+				for i, pod := range topPods.ByCPU {
+					utilPercent := 0.0
+					if pod.CPURequest > 0 {
+						utilPercent = float64(pod.CPUUsage) / float64(pod.CPURequest) * 100
+					}
+					cpuTable.AddColoredRow(
+						[]string{
+							fmt.Sprintf("%d", i+1),
+							pod.Namespace,
+							pod.Name,
+							fmt.Sprintf("%dm", pod.CPUUsage),
+							fmt.Sprintf("%dm", pod.CPURequest),
+							output.ProgressBar(int(utilPercent), 100, 15),
+						},
+						[]tablewriter.Colors{
+							{tablewriter.FgHiBlackColor},
+							{tablewriter.FgCyanColor},
+							{tablewriter.FgWhiteColor},
+							{tablewriter.FgYellowColor},
+							{tablewriter.FgHiBlackColor},
+							{getResourceColorInt(utilPercent)},
+						},
+					)
 				}
-				cpuTable.AddColoredRow(
-					[]string{
-						fmt.Sprintf("%d", i+1),
-						pod.Namespace,
-						pod.Name,
-						fmt.Sprintf("%dm", pod.CPUUsage),
-						fmt.Sprintf("%dm", pod.CPURequest),
-						output.ProgressBar(int(utilPercent), 100, 15),
-					},
-					[]tablewriter.Colors{
-						{tablewriter.FgHiBlackColor},
-						{tablewriter.FgCyanColor},
-						{tablewriter.FgWhiteColor},
-						{tablewriter.FgYellowColor},
-						{tablewriter.FgHiBlackColor},
-						{getResourceColorInt(utilPercent)},
-					},
-				)
+
+				output.Newline()
+				cpuTable.Render()
+
+				// Memory top
+				memTable := output.NewTable(output.TableConfig{
+					Title:      "Top Pods by Memory" + usageSuffix,
+					Headers:    []string{"#", "Namespace", "Pod", "Mem Usage", "Mem Request", "Utilization"},
+					ShowBorder: true,
+				})
+
+				for i, pod := range topPods.ByMemory {
+					utilPercent := 0.0
+					if pod.MemoryRequest > 0 {
+						utilPercent = float64(pod.MemoryUsage) / float64(pod.MemoryRequest) * 100
+					}
+					memTable.AddColoredRow(
+						[]string{
+							fmt.Sprintf("%d", i+1),
+							pod.Namespace,
+							pod.Name,
+							format.Quantity(pod.MemoryUsage),
+							format.Quantity(pod.MemoryRequest),
+							output.ProgressBar(int(utilPercent), 100, 15),
+						},
+						[]tablewriter.Colors{
+							{tablewriter.FgHiBlackColor},
+							{tablewriter.FgCyanColor},
+							{tablewriter.FgWhiteColor},
+							{tablewriter.FgYellowColor},
+							{tablewriter.FgHiBlackColor},
+							{getResourceColorInt(utilPercent)},
+						},
+					)
+				}
+
+				output.Newline()
+				memTable.Render()
 			}
 
-			output.Newline()
-			cpuTable.Render()
+			if view == "all" || view == "over-requested" {
+				output.Newline()
+				renderOverRequested(topPods.OverRequested, measured, usageSuffix)
+			}
+			if view == "all" || view == "no-requests" {
+				output.Newline()
+				renderNoRequests(topPods.NoRequests, measured)
+			}
+		}
+	}
 
-			// Memory top
-			memTable := output.NewTable(output.TableConfig{
-				Title:      "Top Pods by Memory",
-				Headers:    []string{"#", "Namespace", "Pod", "Mem Usage", "Mem Request", "Utilization"},
-				ShowBorder: true,
-			})
+	if promClient != nil {
+		output.Newline()
+		renderRightSizing(podUsage, window, limit)
+	}
 
-			for i, pod := range topPods.ByMemory {
-				utilPercent := 0.0
-				if pod.MemoryRequest > 0 {
-					utilPercent = float64(pod.MemoryUsage) / float64(pod.MemoryRequest) * 100
-				}
-				memTable.AddColoredRow(
-					[]string{
-						fmt.Sprintf("%d", i+1),
-						pod.Namespace,
-						pod.Name,
-						formatBytes(pod.MemoryUsage),
-						formatBytes(pod.MemoryRequest),
-						output.ProgressBar(int(utilPercent), 100, 15),
-					},
-					[]tablewriter.Colors{
-						{tablewriter.FgHiBlackColor},
-						{tablewriter.FgCyanColor},
-						{tablewriter.FgWhiteColor},
-						{tablewriter.FgYellowColor},
-						{tablewriter.FgHiBlackColor},
-						{getResourceColorInt(utilPercent)},
-					},
-				)
+	output.Newline()
+	return nil
+}
+
+// exportNamespaceResources writes the per-namespace report as CSV or structured output
+func exportNamespaceResources(ctx context.Context, client *k8s.Client, namespace, reportFormat, outputFile string, topWorkloads int) error {
+	nsResources, err := client.GetNamespaceResources(ctx, topWorkloads)
+	if err != nil {
+		output.SpinnerError("Failed to get namespace resources")
+		return fmt.Errorf("failed to get namespace resources: %w", err)
+	}
+	if namespace != "" {
+		var filtered []k8s.NamespaceResources
+		for _, ns := range nsResources {
+			if ns.Namespace == namespace {
+				filtered = append(filtered, ns)
 			}
+		}
+		nsResources = filtered
+	}
+	output.StopSpinner()
 
-			output.Newline()
-			memTable.Render()
+	w := os.Stdout
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", outputFile, err)
 		}
+		defer f.Close()
+		w = f
 	}
 
-	output.Newline()
+	if reportFormat == "csv" {
+		err = writeNamespaceCSV(w, nsResources)
+	} else {
+		err = output.RenderTo(w, nsResources)
+	}
+	if err != nil {
+		return err
+	}
+
+	if outputFile != "" {
+		output.Successf("Wrote %d namespaces to %s", len(nsResources), outputFile)
+	}
 	return nil
 }
 
+// writeNamespaceCSV writes one row per namespace; top workloads share a single cell
+func writeNamespaceCSV(w io.Writer, namespaces []k8s.NamespaceResources) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{
+		"namespace", "pods", "running", "pending", "succeeded", "failed",
+		"cpu_requests_millicores", "cpu_limits_millicores",
+		"memory_requests_bytes", "memory_limits_bytes", "top_workloads",
+	})
+
+	for _, ns := range namespaces {
+		var workloads []string
+		for _, wl := range ns.TopWorkloads {
+			workloads = append(workloads, fmt.Sprintf("%s/%s=%dm/%s",
+				strings.ToLower(wl.Kind), wl.Name, wl.CPURequests, format.Quantity(wl.MemoryRequests)))
+		}
+		_ = cw.Write([]string{
+			ns.Namespace,
+			strconv.Itoa(ns.PodCount),
+			strconv.Itoa(ns.Running),
+			strconv.Itoa(ns.Pending),
+			strconv.Itoa(ns.Succeeded),
+			strconv.Itoa(ns.Failed),
+			strconv.FormatInt(ns.CPURequests, 10),
+			strconv.FormatInt(ns.CPULimits, 10),
+			strconv.FormatInt(ns.MemoryRequests, 10),
+			strconv.FormatInt(ns.MemoryLimits, 10),
+			strings.Join(workloads, "; "),
+		})
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// rightSizingHeadroom is added on top of P95 usage when suggesting requests
+const rightSizingHeadroom = 1.2
+
+// historicalPodUsage replaces request-based usage with P95 usage from Prometheus.
+// Pods without samples (e.g. just started) are dropped.
+func historicalPodUsage(ctx context.Context, client *k8s.Client, promClient *prometheus.Client, namespace, window string) ([]k8s.PodResourceUsage, error) {
+	pods, err := client.ListPodResources(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	p95, err := promClient.PodUsage(ctx, namespace, window)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []k8s.PodResourceUsage
+	for _, pod := range pods {
+		u, ok := p95[prometheus.PodKey{Namespace: pod.Namespace, Name: pod.Name}]
+		if !ok {
+			continue
+		}
+		pod.CPUUsage = u.CPUMillicores
+		pod.MemoryUsage = u.MemoryBytes
+		pod.HasUsage = true
+		result = append(result, pod)
+	}
+	return result, nil
+}
+
+// renderOverRequested lists the pods reserving the most capacity they don't use
+func renderOverRequested(pods []k8s.PodResourceUsage, measured bool, usageSuffix string) {
+	if !measured {
+		output.Muted("Over-requested pods need measured usage: install metrics-server or pass --prometheus-url")
+		return
+	}
+	if len(pods) == 0 {
+		output.Success("No pod requests more than it uses")
+		return
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Most Over-Requested Pods" + usageSuffix,
+		Headers:    []string{"#", "Namespace", "Pod", "CPU Req", "CPU Used", "CPU Unused", "Mem Req", "Mem Used", "Mem Unused"},
+		ShowBorder: true,
+	})
+
+	var unusedCPU, unusedMem int64
+	for i, pod := range pods {
+		unusedCPU += pod.UnusedCPU()
+		unusedMem += pod.UnusedMemory()
+		table.AddColoredRow(
+			[]string{
+				fmt.Sprintf("%d", i+1),
+				pod.Namespace,
+				output.Truncate(pod.Name, 40),
+				fmt.Sprintf("%dm", pod.CPURequest),
+				fmt.Sprintf("%dm", pod.CPUUsage),
+				fmt.Sprintf("%dm", pod.UnusedCPU()),
+				format.Quantity(pod.MemoryRequest),
+				format.Quantity(pod.MemoryUsage),
+				format.Quantity(pod.UnusedMemory()),
+			},
+			[]tablewriter.Colors{
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgCyanColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgYellowColor},
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgYellowColor},
+			},
+		)
+	}
+	table.Render()
+	output.Muted(fmt.Sprintf("  Reclaimable from these pods: %dm CPU, %s memory", unusedCPU, format.Quantity(unusedMem)))
+}
+
+// renderNoRequests lists pods the scheduler can't account for
+func renderNoRequests(pods []k8s.PodResourceUsage, measured bool) {
+	if len(pods) == 0 {
+		output.Success("Every running pod sets CPU and memory requests")
+		return
+	}
+
+	headers := []string{"#", "Namespace", "Pod", "CPU Req", "Mem Req"}
+	if measured {
+		headers = append(headers, "CPU Used", "Mem Used")
+	}
+	table := output.NewTable(output.TableConfig{
+		Title:      "Pods Without Requests",
+		Headers:    headers,
+		ShowBorder: true,
+	})
+
+	missing := func(v int64, s string) (string, tablewriter.Colors) {
+		if v == 0 {
+			return "none", tablewriter.Colors{tablewriter.FgRedColor}
+		}
+		return s, tablewriter.Colors{tablewriter.FgHiBlackColor}
+	}
+	for i, pod := range pods {
+		cpu, cpuColor := missing(pod.CPURequest, fmt.Sprintf("%dm", pod.CPURequest))
+		mem, memColor := missing(pod.MemoryRequest, format.Quantity(pod.MemoryRequest))
+		row := []string{fmt.Sprintf("%d", i+1), pod.Namespace, output.Truncate(pod.Name, 40), cpu, mem}
+		colors := []tablewriter.Colors{
+			{tablewriter.FgHiBlackColor},
+			{tablewriter.FgCyanColor},
+			{tablewriter.FgWhiteColor},
+			cpuColor,
+			memColor,
+		}
+		if measured {
+			row = append(row, fmt.Sprintf("%dm", pod.CPUUsage), format.Quantity(pod.MemoryUsage))
+			colors = append(colors, tablewriter.Colors{tablewriter.FgYellowColor}, tablewriter.Colors{tablewriter.FgYellowColor})
+		}
+		table.AddColoredRow(row, colors)
+	}
+	table.Render()
+}
+
+// renderRightSizing lists pods whose requests are far from their P95 usage
+func renderRightSizing(pods []k8s.PodResourceUsage, window string, limit int) {
+	type suggestion struct {
+		pod       k8s.PodResourceUsage
+		cpu, mem  int64
+		verdict   string
+		wastedCPU int64
+	}
+
+	var suggestions []suggestion
+	for _, pod := range pods {
+		cpu := max(int64(float64(pod.CPUUsage)*rightSizingHeadroom), 10)
+		mem := max(int64(float64(pod.MemoryUsage)*rightSizingHeadroom), 16*1024*1024)
+
+		var verdict string
+		switch {
+		case pod.CPURequest == 0 || pod.MemoryRequest == 0:
+			verdict = "no requests"
+		case pod.CPUUsage > pod.CPURequest || pod.MemoryUsage > pod.MemoryRequest:
+			verdict = "under-provisioned"
+		case pod.CPUUsage*2 < pod.CPURequest || pod.MemoryUsage*2 < pod.MemoryRequest:
+			verdict = "over-provisioned"
+		default:
+			continue
+		}
+
+		suggestions = append(suggestions, suggestion{
+			pod:       pod,
+			cpu:       cpu,
+			mem:       mem,
+			verdict:   verdict,
+			wastedCPU: pod.CPURequest - pod.CPUUsage,
+		})
+	}
+
+	if len(suggestions) == 0 {
+		output.Success(fmt.Sprintf("All pod requests are within 2x of P95 usage over %s", window))
+		return
+	}
+
+	// Biggest absolute mismatch first, in either direction
+	sort.Slice(suggestions, func(i, j int) bool {
+		return abs(suggestions[i].wastedCPU) > abs(suggestions[j].wastedCPU)
+	})
+
+	table := output.NewTable(output.TableConfig{
+		Title:      fmt.Sprintf("Right-Sizing (P95 over %s)", window),
+		Headers:    []string{"Namespace", "Pod", "CPU Req", "CPU P95", "Suggest", "Mem Req", "Mem P95", "Suggest", "Verdict"},
+		ShowBorder: true,
+	})
+
+	for i, sg := range suggestions {
+		if i >= limit {
+			break
+		}
+		verdictColor := tablewriter.FgYellowColor
+		if sg.verdict == "under-provisioned" {
+			verdictColor = tablewriter.FgRedColor
+		}
+
+		table.AddColoredRow(
+			[]string{
+				sg.pod.Namespace,
+				output.Truncate(sg.pod.Name, 40),
+				fmt.Sprintf("%dm", sg.pod.CPURequest),
+				fmt.Sprintf("%dm", sg.pod.CPUUsage),
+				fmt.Sprintf("%dm", sg.cpu),
+				format.Quantity(sg.pod.MemoryRequest),
+				format.Quantity(sg.pod.MemoryUsage),
+				format.Quantity(sg.mem),
+				sg.verdict,
+			},
+			[]tablewriter.Colors{
+				{tablewriter.FgCyanColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgGreenColor},
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgGreenColor},
+				{verdictColor},
+			},
+		)
+	}
+
+	table.Render()
+	if len(suggestions) > limit {
+		output.Muted(fmt.Sprintf("  ... and %d more (raise --limit to see them)", len(suggestions)-limit))
+	}
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// getPrometheusClient returns a Prometheus client, or nil when no URL is configured
+func getPrometheusClient(cmd *cobra.Command) (*prometheus.Client, error) {
+	url, _ := cmd.Flags().GetString("prometheus-url")
+	if url == "" {
+		url = os.Getenv("PROMETHEUS_URL")
+	}
+	if url == "" {
+		url = viper.GetString("prometheus.url")
+	}
+	if url == "" {
+		return nil, nil
+	}
+
+	token := os.Getenv("PROMETHEUS_TOKEN")
+	if token == "" {
+		token = viper.GetString("prometheus.token")
+	}
+	token, err := secrets.Resolve(token)
+	if err != nil {
+		return nil, err
+	}
+	return prometheus.NewClient(url, token)
+}
+
 func getResourceRowColors(percent float64) []tablewriter.Colors {
 	color := getResourceColorInt(percent)
 	return []tablewriter.Colors{
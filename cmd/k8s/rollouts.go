@@ -0,0 +1,349 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newRolloutsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rollouts",
+		Aliases: []string{"rollout", "canaries"},
+		Short:   "Inspect and control canary and blue/green rollouts",
+		Long: `List progressive delivery rollouts managed by Argo Rollouts (Rollout) or
+Flagger (Canary), with their phase, canary weight and step.
+
+Subcommands show the analysis runs of one rollout and promote or abort
+Argo Rollouts. Flagger canaries are promoted and rolled back through their
+confirm-promotion and rollback webhooks, not through the Canary resource.
+
+Examples:
+  devops-toolkit k8s rollouts
+  devops-toolkit k8s rollouts -n shop
+  devops-toolkit k8s rollouts get checkout -n shop
+  devops-toolkit k8s rollouts promote checkout -n shop
+  devops-toolkit k8s rollouts abort checkout -n shop`,
+		Args: cobra.NoArgs,
+		RunE: runRollouts,
+	}
+
+	cmd.AddCommand(newRolloutsGetCmd())
+	cmd.AddCommand(newRolloutsPromoteCmd())
+	cmd.AddCommand(newRolloutsAbortCmd())
+
+	return cmd
+}
+
+func newRolloutsGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <name>",
+		Short: "Show a rollout with its analysis run results",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runRolloutsGet,
+	}
+}
+
+func newRolloutsPromoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote <name>",
+		Short: "Resume a paused Argo Rollout or skip its current step",
+		Long: `Promote an Argo Rollout: resume it when paused, otherwise skip the
+current canary step. With --full, skip all remaining steps and analysis
+and shift all traffic to the new version.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runRolloutsPromote,
+	}
+
+	cmd.Flags().Bool("full", false, "Skip all remaining steps and analysis")
+
+	return cmd
+}
+
+func newRolloutsAbortCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "abort <name>",
+		Short: "Abort an Argo Rollout and return traffic to the stable version",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runRolloutsAbort,
+	}
+}
+
+func newRolloutsClient(cmd *cobra.Command) (*k8s.Client, error) {
+	return k8s.NewClient(
+		cmd.Flag("kubeconfig").Value.String(),
+		cmd.Flag("context").Value.String(),
+	)
+}
+
+// rolloutNamespace is --namespace, or default for commands on one rollout
+func rolloutNamespace(cmd *cobra.Command) string {
+	if namespace := cmd.Flag("namespace").Value.String(); namespace != "" {
+		return namespace
+	}
+	return "default"
+}
+
+func runRollouts(cmd *cobra.Command, args []string) error {
+	output.StartSpinner("Fetching rollouts...")
+
+	client, err := newRolloutsClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return err
+	}
+
+	rollouts, err := client.ListRollouts(cmd.Context(), cmd.Flag("namespace").Value.String())
+	if err != nil {
+		output.SpinnerError("Failed to list rollouts")
+		return err
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Found %d rollouts", len(rollouts)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(rollouts)
+	}
+
+	if len(rollouts) == 0 {
+		output.Info("No Argo Rollouts or Flagger canaries found")
+		return nil
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Rollouts",
+		Headers:    []string{"Namespace", "Name", "Controller", "Strategy", "Phase", "Weight", "Step", "Ready", "Message"},
+		ShowBorder: true,
+	})
+	for _, r := range rollouts {
+		table.AddColoredRow(
+			[]string{
+				r.Namespace,
+				r.Name,
+				r.Controller,
+				r.Strategy,
+				rolloutPhase(r),
+				rolloutWeight(r),
+				rolloutStep(r),
+				rolloutReady(r),
+				output.Truncate(r.Message, 40),
+			},
+			[]tablewriter.Colors{
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgCyanColor},
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgWhiteColor},
+				{rolloutPhaseColor(r)},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgHiBlackColor},
+			},
+		)
+	}
+	table.Render()
+	return nil
+}
+
+func runRolloutsGet(cmd *cobra.Command, args []string) error {
+	namespace := rolloutNamespace(cmd)
+	output.StartSpinner(fmt.Sprintf("Fetching rollout %s...", args[0]))
+
+	client, err := newRolloutsClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return err
+	}
+
+	r, err := client.GetRollout(cmd.Context(), namespace, args[0])
+	if err != nil {
+		output.SpinnerError("Failed to get rollout")
+		return err
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Rollout %s is %s", r.Name, rolloutPhase(*r)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(r)
+	}
+
+	output.Print(output.Section("Rollout"))
+	output.Printf("  %s\n", output.KeyValue("Name", r.Namespace+"/"+r.Name))
+	output.Printf("  %s\n", output.KeyValue("Controller", r.Controller))
+	output.Printf("  %s\n", output.KeyValue("Strategy", r.Strategy))
+	output.Printf("  %s\n", output.KeyValue("Phase", rolloutPhase(*r)))
+	if r.Strategy == k8s.StrategyCanary {
+		output.Printf("  %s\n", output.KeyValue("Canary weight", rolloutWeight(*r)))
+		output.Printf("  %s\n", output.KeyValue("Step", rolloutStep(*r)))
+	}
+	if r.Controller == k8s.RolloutControllerArgo {
+		output.Printf("  %s\n", output.KeyValue("Ready", rolloutReady(*r)))
+	}
+	if r.FailedChecks > 0 {
+		output.Printf("  %s\n", output.KeyValue("Failed checks", fmt.Sprintf("%d", r.FailedChecks)))
+	}
+	if r.Message != "" {
+		output.Printf("  %s\n", output.KeyValue("Message", r.Message))
+	}
+	output.Newline()
+
+	if len(r.Analysis) > 0 {
+		table := output.NewTable(output.TableConfig{
+			Title:      "Analysis Runs",
+			Headers:    []string{"Run", "Phase", "Age", "Metric", "Result", "Successful", "Failed", "Last Value"},
+			ShowBorder: true,
+		})
+		for _, run := range r.Analysis {
+			if len(run.Metrics) == 0 {
+				table.AddColoredRow(
+					[]string{run.Name, run.Phase, format.Age(run.Started), "-", "-", "-", "-", output.Truncate(run.Message, 30)},
+					[]tablewriter.Colors{{tablewriter.FgCyanColor}, {analysisPhaseColor(run.Phase)}},
+				)
+				continue
+			}
+			for i, m := range run.Metrics {
+				name, phase, age := "", "", ""
+				if i == 0 {
+					name, phase, age = run.Name, run.Phase, format.Age(run.Started)
+				}
+				table.AddColoredRow(
+					[]string{name, phase, age, m.Name, m.Phase, fmt.Sprintf("%d", m.Successful), fmt.Sprintf("%d", m.Failed), output.Truncate(m.Value, 30)},
+					[]tablewriter.Colors{
+						{tablewriter.FgCyanColor},
+						{analysisPhaseColor(run.Phase)},
+						{tablewriter.FgHiBlackColor},
+						{tablewriter.FgWhiteColor},
+						{analysisPhaseColor(m.Phase)},
+						{tablewriter.FgGreenColor},
+						{tablewriter.FgRedColor},
+						{tablewriter.FgHiBlackColor},
+					},
+				)
+			}
+		}
+		table.Render()
+		output.Newline()
+	}
+
+	if r.Controller == k8s.RolloutControllerArgo && !r.Aborted && r.Phase != "Healthy" {
+		output.Print(output.Section("Actions"))
+		output.Printf("  %s devops-toolkit k8s rollouts promote %s -n %s\n", output.IconArrow, r.Name, r.Namespace)
+		output.Printf("  %s devops-toolkit k8s rollouts promote %s -n %s --full\n", output.IconArrow, r.Name, r.Namespace)
+		output.Printf("  %s devops-toolkit k8s rollouts abort %s -n %s\n", output.IconArrow, r.Name, r.Namespace)
+		output.Newline()
+	}
+	return nil
+}
+
+func runRolloutsPromote(cmd *cobra.Command, args []string) error {
+	full, _ := cmd.Flags().GetBool("full")
+	namespace := rolloutNamespace(cmd)
+
+	output.StartSpinner(fmt.Sprintf("Promoting %s...", args[0]))
+
+	client, err := newRolloutsClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return err
+	}
+
+	if err := client.PromoteRollout(cmd.Context(), namespace, args[0], full); err != nil {
+		output.SpinnerError("Promote failed")
+		return err
+	}
+
+	if full {
+		output.SpinnerSuccess(fmt.Sprintf("Fully promoted %s/%s", namespace, args[0]))
+	} else {
+		output.SpinnerSuccess(fmt.Sprintf("Promoted %s/%s to the next step", namespace, args[0]))
+	}
+	return nil
+}
+
+func runRolloutsAbort(cmd *cobra.Command, args []string) error {
+	namespace := rolloutNamespace(cmd)
+
+	output.StartSpinner(fmt.Sprintf("Aborting %s...", args[0]))
+
+	client, err := newRolloutsClient(cmd)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return err
+	}
+
+	if err := client.AbortRollout(cmd.Context(), namespace, args[0]); err != nil {
+		output.SpinnerError("Abort failed")
+		return err
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Aborted %s/%s; traffic is returning to the stable version", namespace, args[0]))
+	return nil
+}
+
+// rolloutPhase adds the paused and aborted states to the controller phase
+func rolloutPhase(r k8s.RolloutInfo) string {
+	switch {
+	case r.Aborted && r.Controller == k8s.RolloutControllerArgo:
+		return "Aborted"
+	case r.Paused && r.Phase != "Paused" && !strings.HasPrefix(r.Phase, "Waiting"):
+		return r.Phase + " (paused)"
+	case r.Phase == "":
+		return "-"
+	default:
+		return r.Phase
+	}
+}
+
+func rolloutPhaseColor(r k8s.RolloutInfo) int {
+	switch {
+	case r.Aborted, r.Phase == "Degraded", r.Phase == "Failed":
+		return tablewriter.FgRedColor
+	case r.Paused, r.Phase == "Progressing":
+		return tablewriter.FgYellowColor
+	case r.Phase == "Healthy", r.Phase == "Succeeded", r.Phase == "Initialized":
+		return tablewriter.FgGreenColor
+	default:
+		return tablewriter.FgWhiteColor
+	}
+}
+
+func rolloutWeight(r k8s.RolloutInfo) string {
+	if r.Strategy != k8s.StrategyCanary {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", r.Weight)
+}
+
+func rolloutStep(r k8s.RolloutInfo) string {
+	if r.Steps == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", r.Step, r.Steps)
+}
+
+func rolloutReady(r k8s.RolloutInfo) string {
+	if r.Controller != k8s.RolloutControllerArgo {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", r.Ready, r.Desired)
+}
+
+func analysisPhaseColor(phase string) int {
+	switch phase {
+	case "Successful":
+		return tablewriter.FgGreenColor
+	case "Failed", "Error":
+		return tablewriter.FgRedColor
+	case "Inconclusive", "Running", "Pending":
+		return tablewriter.FgYellowColor
+	default:
+		return tablewriter.FgWhiteColor
+	}
+}
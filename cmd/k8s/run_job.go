@@ -0,0 +1,176 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newRunJobCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run-job --image <image> [-- args...]",
+		Short: "Run a one-off Job, stream its logs and exit with its exit code",
+		Long: `Create a Job for an ad-hoc task, stream its logs until it finishes, exit
+with the container's exit code and delete the Job.
+
+A safer replacement for kubectl run one-liners in scripts. Defaults:
+  • no retries (--backoff-limit 0) and a 1h deadline
+  • requests of 100m CPU and 256Mi memory, with memory also the limit
+  • ttlSecondsAfterFinished, so the Job is removed even if this command
+    is killed before it cleans up
+
+Arguments after -- are passed to the image entrypoint, or replace it with
+--command. Retried pods are streamed one after another.
+
+Examples:
+  devops-toolkit k8s run-job --image busybox -- sh -c 'nslookup api.shop'
+  devops-toolkit k8s run-job -n shop --image myapp:1.4 --command -- ./manage.py migrate
+  devops-toolkit k8s run-job --image postgres:16 --env PGHOST=db --memory 1Gi --command -- pg_dump shop`,
+		Args: cobra.ArbitraryArgs,
+		RunE: runRunJob,
+	}
+
+	cmd.Flags().String("image", "", "Container image to run (required)")
+	cmd.Flags().String("name", "", "Job name (default: generated)")
+	cmd.Flags().Bool("command", false, "Use the arguments as the container command instead of arguments to the entrypoint")
+	cmd.Flags().StringArray("env", nil, "Environment variable KEY=VALUE (repeatable)")
+	cmd.Flags().String("cpu", "100m", "CPU request")
+	cmd.Flags().String("memory", "256Mi", "Memory request and limit")
+	cmd.Flags().Int32("backoff-limit", 0, "Retries before the job is marked failed")
+	cmd.Flags().Duration("deadline", time.Hour, "Maximum run time before the job is killed")
+	cmd.Flags().Duration("ttl", 10*time.Minute, "Delete the finished job after this long if it is not cleaned up")
+	cmd.Flags().String("service-account", "", "ServiceAccount the pod runs as")
+	cmd.Flags().Bool("keep", false, "Keep the job after it finishes (still removed after --ttl)")
+
+	_ = cmd.MarkFlagRequired("image")
+
+	return cmd
+}
+
+func runRunJob(cmd *cobra.Command, args []string) error {
+	image, _ := cmd.Flags().GetString("image")
+	name, _ := cmd.Flags().GetString("name")
+	command, _ := cmd.Flags().GetBool("command")
+	envPairs, _ := cmd.Flags().GetStringArray("env")
+	cpu, _ := cmd.Flags().GetString("cpu")
+	memory, _ := cmd.Flags().GetString("memory")
+	backoffLimit, _ := cmd.Flags().GetInt32("backoff-limit")
+	deadline, _ := cmd.Flags().GetDuration("deadline")
+	ttl, _ := cmd.Flags().GetDuration("ttl")
+	serviceAccount, _ := cmd.Flags().GetString("service-account")
+	keep, _ := cmd.Flags().GetBool("keep")
+
+	if command && len(args) == 0 {
+		return exitcode.ConfigError(fmt.Errorf("--command needs the command after --"))
+	}
+	env, err := k8s.ParseEnv(envPairs)
+	if err != nil {
+		return exitcode.ConfigError(err)
+	}
+
+	namespace := cmd.Flag("namespace").Value.String()
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	output.StartSpinner("Creating job...")
+
+	client, err := k8s.NewClient(
+		cmd.Flag("kubeconfig").Value.String(),
+		cmd.Flag("context").Value.String(),
+	)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return err
+	}
+
+	ctx := cmd.Context()
+	jobName, err := client.CreateJob(ctx, k8s.RunJobOptions{
+		Namespace:      namespace,
+		Name:           name,
+		Image:          image,
+		Args:           args,
+		Command:        command,
+		Env:            env,
+		CPU:            cpu,
+		Memory:         memory,
+		BackoffLimit:   backoffLimit,
+		TTL:            ttl,
+		ActiveDeadline: deadline,
+		ServiceAccount: serviceAccount,
+	})
+	if err != nil {
+		output.SpinnerError("Failed to create job")
+		return err
+	}
+	if !keep {
+		// Clean up on success, failure and Ctrl-C alike
+		defer func() {
+			if err := client.DeleteJob(context.Background(), namespace, jobName); err != nil {
+				output.Warning(fmt.Sprintf("Failed to delete job %s/%s: %v", namespace, jobName, err))
+			}
+		}()
+	}
+
+	output.UpdateSpinner(fmt.Sprintf("Waiting for job %s/%s to start...", namespace, jobName))
+
+	// Logs go to stdout unless it carries the structured result
+	var logs io.Writer = os.Stdout
+	if output.IsStructured() {
+		logs = os.Stderr
+	}
+	result, err := client.FollowJob(ctx, namespace, jobName, &startWriter{w: logs, onStart: func() {
+		output.SpinnerSuccess(fmt.Sprintf("Job %s/%s started", namespace, jobName))
+	}})
+	output.StopSpinner()
+	if err != nil {
+		return err
+	}
+
+	if output.IsStructured() {
+		if err := output.Render(result); err != nil {
+			return err
+		}
+	} else if result.Succeeded {
+		output.Successf("Job %s completed in %s", jobName, format.Duration(result.Duration))
+	}
+
+	if !result.Succeeded {
+		reason := ""
+		if result.Reason != "" {
+			reason = " (" + result.Reason + ")"
+		}
+		if result.Reason == "DeadlineExceeded" {
+			return exitcode.CommandFailed(exitcode.Timeout, fmt.Errorf("job %s exceeded its %s deadline", jobName, deadline))
+		}
+		if result.ExitCode > 0 {
+			return exitcode.CommandFailed(result.ExitCode, fmt.Errorf("job %s failed with exit code %d%s", jobName, result.ExitCode, reason))
+		}
+		return fmt.Errorf("job %s failed%s", jobName, reason)
+	}
+	return nil
+}
+
+// startWriter runs onStart before the first write, so the spinner is
+// replaced by the job's output as soon as it produces any
+type startWriter struct {
+	w       io.Writer
+	onStart func()
+	started bool
+}
+
+func (s *startWriter) Write(p []byte) (int, error) {
+	if !s.started {
+		s.started = true
+		s.onStart()
+	}
+	return s.w.Write(p)
+}
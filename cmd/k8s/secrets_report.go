@@ -0,0 +1,184 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newSecretsReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secrets-report",
+		Short: "Report secret usage, age and TLS expiry",
+		Long: `List secrets with their type, age and the workloads, service accounts and
+ingresses that reference them.
+
+Highlights:
+  • Unreferenced secrets (remove with k8s cleanup --unused-secrets)
+  • TLS secrets whose certificate is expired or nearing expiry
+  • Old secrets that are due for rotation
+
+Service account tokens and Helm release secrets are managed by controllers
+and are never reported as unreferenced. Secrets read by external tools or
+CRDs (e.g. cert-manager, operators) may appear unreferenced; review before
+deleting. The same checks run in compliance as K8S-SECRET-001/002.
+
+Examples:
+  devops-toolkit k8s secrets-report
+  devops-toolkit k8s secrets-report -n payments --unreferenced
+  devops-toolkit k8s secrets-report --expiring 14 --rotate-after 90`,
+		RunE: runSecretsReport,
+	}
+
+	cmd.Flags().Bool("unreferenced", false, "Only show unreferenced secrets")
+	cmd.Flags().Int("expiring", 30, "Flag TLS certificates expiring within this many days")
+	cmd.Flags().Int("rotate-after", 180, "Flag secrets older than this many days for rotation (0 disables)")
+	cmd.Flags().Bool("include-managed", false, "Include service account tokens and Helm release secrets")
+	addNamespaceSelectorFlags(cmd)
+
+	return cmd
+}
+
+func runSecretsReport(cmd *cobra.Command, args []string) error {
+	onlyUnreferenced, _ := cmd.Flags().GetBool("unreferenced")
+	expiringDays, _ := cmd.Flags().GetInt("expiring")
+	rotateDays, _ := cmd.Flags().GetInt("rotate-after")
+	includeManaged, _ := cmd.Flags().GetBool("include-managed")
+
+	output.StartSpinner("Collecting secrets...")
+
+	client, err := k8s.NewClient(
+		cmd.Flag("kubeconfig").Value.String(),
+		cmd.Flag("context").Value.String(),
+	)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	client.SetNamespaceSelector(namespaceSelector(cmd))
+
+	report, err := client.SecretsReport(cmd.Context(), cmd.Flag("namespace").Value.String())
+	if err != nil {
+		output.SpinnerError("Failed to collect secrets")
+		return err
+	}
+
+	var secrets []k8s.SecretUsage
+	for _, secret := range report {
+		if secret.Managed && !includeManaged {
+			continue
+		}
+		if onlyUnreferenced && !secret.Unreferenced {
+			continue
+		}
+		secrets = append(secrets, secret)
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Found %d secrets", len(secrets)))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(secrets)
+	}
+
+	if len(secrets) == 0 {
+		output.Info("No secrets found")
+		return nil
+	}
+
+	expiryWindow := time.Duration(expiringDays) * 24 * time.Hour
+	rotateAge := time.Duration(rotateDays) * 24 * time.Hour
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Secrets",
+		Headers:    []string{"Namespace", "Name", "Type", "Keys", "Age", "Used By", "TLS Expiry"},
+		ShowBorder: true,
+	})
+
+	var unreferenced, expiring, expired, stale int
+	for _, secret := range secrets {
+		usedBy := strings.Join(secret.UsedBy, ",")
+		usedByColor := tablewriter.Colors{tablewriter.FgWhiteColor}
+		switch {
+		case secret.Managed:
+			usedBy = "(managed)"
+			usedByColor = tablewriter.Colors{tablewriter.FgHiBlackColor}
+		case secret.Unreferenced:
+			unreferenced++
+			usedBy = "unreferenced"
+			usedByColor = tablewriter.Colors{tablewriter.FgYellowColor}
+		}
+
+		ageColor := tablewriter.Colors{tablewriter.FgHiBlackColor}
+		if rotateDays > 0 && !secret.Managed && time.Since(secret.Created) > rotateAge {
+			stale++
+			ageColor = tablewriter.Colors{tablewriter.FgYellowColor}
+		}
+
+		expiry := "-"
+		expiryColor := tablewriter.Colors{tablewriter.FgHiBlackColor}
+		if !secret.TLSNotAfter.IsZero() {
+			days := int(time.Until(secret.TLSNotAfter).Hours() / 24)
+			expiry = fmt.Sprintf("%s (%dd)", secret.TLSNotAfter.Format("2006-01-02"), days)
+			expiryColor = tablewriter.Colors{tablewriter.FgGreenColor}
+			switch {
+			case time.Now().After(secret.TLSNotAfter):
+				expired++
+				expiry = "EXPIRED " + secret.TLSNotAfter.Format("2006-01-02")
+				expiryColor = tablewriter.Colors{tablewriter.Bold, tablewriter.FgRedColor}
+			case secret.TLSExpiresWithin(expiryWindow):
+				expiring++
+				expiryColor = tablewriter.Colors{tablewriter.Bold, tablewriter.FgYellowColor}
+			}
+		}
+
+		table.AddColoredRow(
+			[]string{
+				secret.Namespace,
+				output.Truncate(secret.Name, 40),
+				output.Truncate(secret.Type, 30),
+				fmt.Sprintf("%d", secret.Keys),
+				format.Age(secret.Created),
+				output.Truncate(usedBy, 40),
+				expiry,
+			},
+			[]tablewriter.Colors{
+				{tablewriter.FgHiBlackColor},
+				{tablewriter.FgCyanColor},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgHiBlackColor},
+				ageColor,
+				usedByColor,
+				expiryColor,
+			},
+		)
+	}
+
+	table.Render()
+
+	output.Newline()
+	output.Print(output.Section("Summary"))
+	output.Printf("  %s\n", output.KeyValue("Secrets", fmt.Sprintf("%d", len(secrets))))
+	if expired > 0 {
+		output.Printf("  %s %d TLS certificates expired\n", output.ErrorStyle.Render(output.IconError), expired)
+	}
+	if expiring > 0 {
+		output.Printf("  %s %d TLS certificates expire within %d days\n", output.WarningStyle.Render(output.IconWarning), expiring, expiringDays)
+	}
+	if stale > 0 {
+		output.Printf("  %s %d secrets older than %d days (consider rotating)\n", output.WarningStyle.Render(output.IconWarning), stale, rotateDays)
+	}
+	if unreferenced > 0 {
+		output.Printf("  %s %d unreferenced secrets\n", output.InfoStyle.Render(output.IconInfo), unreferenced)
+		output.Muted("  Review, then remove with: devops-toolkit k8s cleanup --unused-secrets --dry-run=false")
+	}
+
+	output.Newline()
+	return nil
+}
@@ -0,0 +1,310 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/secrets"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newTimelineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "timeline",
+		Short: "Build a chronological incident timeline",
+		Long: `Merge events, container restarts, deployment rollouts, node condition
+changes and (optionally) GitLab deployments into a single chronological
+timeline for post-incident reviews.
+
+Examples:
+  # Everything that happened in the last two hours
+  devops-toolkit k8s timeline --since 2h
+
+  # Include GitLab deployments and export for the incident review
+  devops-toolkit k8s timeline -n payments --since 6h \
+    --gitlab-project group/payments --format markdown -o timeline.md`,
+		RunE: runTimeline,
+	}
+
+	cmd.Flags().Duration("since", 2*time.Hour, "How far back the timeline goes")
+	cmd.Flags().StringP("format", "f", "table", "Output format (table, markdown)")
+	cmd.Flags().StringP("output-file", "o", "", "Write the timeline to a file")
+	cmd.Flags().Bool("warnings-only", false, "Only include warnings, failed restarts and unhealthy node conditions")
+	cmd.Flags().String("gitlab-project", "", "Include deployments of this GitLab project (ID or path)")
+	cmd.Flags().String("environment", "", "Only include GitLab deployments to this environment")
+
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("format", completion.TimelineFormatCompletion)
+
+	return cmd
+}
+
+func runTimeline(cmd *cobra.Command, args []string) error {
+	since, _ := cmd.Flags().GetDuration("since")
+	format, _ := cmd.Flags().GetString("format")
+	outputFile, _ := cmd.Flags().GetString("output-file")
+	warningsOnly, _ := cmd.Flags().GetBool("warnings-only")
+	project, _ := cmd.Flags().GetString("gitlab-project")
+	environment, _ := cmd.Flags().GetString("environment")
+
+	if since <= 0 {
+		return fmt.Errorf("--since must be positive")
+	}
+
+	output.StartSpinner("Building timeline...")
+
+	client, err := k8s.NewClient(
+		cmd.Flag("kubeconfig").Value.String(),
+		cmd.Flag("context").Value.String(),
+	)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	ctx := cmd.Context()
+	namespace := cmd.Flag("namespace").Value.String()
+	start := time.Now().Add(-since)
+
+	entries, err := client.Timeline(ctx, namespace, start)
+	if err != nil {
+		output.SpinnerError("Failed to build timeline")
+		return err
+	}
+
+	if project != "" {
+		deploys, err := gitlabDeployments(cmd.Context(), project, environment, start)
+		if err != nil {
+			output.SpinnerError("Failed to fetch GitLab deployments")
+			return err
+		}
+		entries = append(entries, deploys...)
+		k8s.SortTimeline(entries)
+	}
+
+	if warningsOnly {
+		var filtered []k8s.TimelineEntry
+		for _, e := range entries {
+			if e.Warning {
+				filtered = append(filtered, e)
+			}
+		}
+		entries = filtered
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Found %d timeline entries", len(entries)))
+	output.Newline()
+
+	if format == "markdown" || format == "md" {
+		report := timelineMarkdown(entries, start, namespace)
+		if outputFile != "" {
+			if err := os.WriteFile(outputFile, []byte(report), 0644); err != nil {
+				return fmt.Errorf("failed to write timeline: %w", err)
+			}
+			output.Successf("Timeline written to %s", outputFile)
+			return nil
+		}
+		fmt.Println(report)
+		return nil
+	}
+
+	if output.IsStructured() {
+		return output.Render(entries)
+	}
+
+	if len(entries) == 0 {
+		output.Info(fmt.Sprintf("Nothing happened in the last %s", since))
+		return nil
+	}
+
+	displayTimeline(entries)
+	return nil
+}
+
+// gitlabDeployments converts GitLab deployments into timeline entries
+func gitlabDeployments(ctx context.Context, project, environment string, since time.Time) ([]k8s.TimelineEntry, error) {
+	token := os.Getenv("GITLAB_TOKEN")
+	if token == "" {
+		token = viper.GetString("gitlab.token")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("GitLab token required for --gitlab-project (set GITLAB_TOKEN)")
+	}
+	token, err := secrets.Resolve(token)
+	if err != nil {
+		return nil, err
+	}
+
+	url := os.Getenv("GITLAB_URL")
+	if url == "" {
+		url = viper.GetString("gitlab.url")
+	}
+	if url == "" {
+		url = "https://gitlab.com"
+	}
+
+	client, err := gitlabclient.NewClient(url, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitLab client: %w", err)
+	}
+
+	deployments, err := client.ListDeployments(ctx, project, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	var entries []k8s.TimelineEntry
+	for _, d := range deployments {
+		if environment != "" && d.Environment != environment {
+			continue
+		}
+
+		message := fmt.Sprintf("%s@%s", d.Ref, shortSHA(d.SHA))
+		if d.User != "" {
+			message += " by " + d.User
+		}
+
+		entries = append(entries, k8s.TimelineEntry{
+			Time:    d.CreatedAt,
+			Source:  k8s.TimelineDeploy,
+			Kind:    "Environment",
+			Object:  d.Environment,
+			Reason:  d.Status,
+			Message: message,
+			Warning: d.Status == "failed" || d.Status == "canceled",
+		})
+	}
+
+	return entries, nil
+}
+
+func displayTimeline(entries []k8s.TimelineEntry) {
+	table := output.NewTable(output.TableConfig{
+		Title:      "Incident Timeline",
+		Headers:    []string{"Time", "Source", "Object", "Reason", "Details"},
+		ShowBorder: true,
+	})
+
+	counts := make(map[string]int)
+	warnings := 0
+	for _, e := range entries {
+		counts[e.Source]++
+		if e.Warning {
+			warnings++
+		}
+
+		row := []string{
+			format.TimestampLayout(e.Time, "15:04:05"),
+			e.Source,
+			output.Truncate(timelineObject(e), 40),
+			e.Reason,
+			output.Truncate(e.Message, 60),
+		}
+
+		detailColor := tablewriter.FgWhiteColor
+		if e.Warning {
+			detailColor = tablewriter.FgYellowColor
+		}
+
+		table.AddColoredRow(row, []tablewriter.Colors{
+			{tablewriter.FgHiBlackColor},
+			{timelineSourceColor(e.Source)},
+			{tablewriter.FgCyanColor},
+			{detailColor},
+			{detailColor},
+		})
+	}
+
+	table.Render()
+
+	output.Newline()
+	output.Print(output.Section("Timeline Summary"))
+	for _, source := range []string{k8s.TimelineEvent, k8s.TimelineRestart, k8s.TimelineRollout, k8s.TimelineNode, k8s.TimelineDeploy} {
+		if counts[source] > 0 {
+			output.Printf("  %s\n", output.KeyValue(source, fmt.Sprintf("%d", counts[source])))
+		}
+	}
+	if warnings > 0 {
+		output.Printf("  %s %d entries need attention\n", output.WarningStyle.Render(output.IconWarning), warnings)
+	}
+	output.Newline()
+}
+
+func timelineSourceColor(source string) int {
+	switch source {
+	case k8s.TimelineRestart:
+		return tablewriter.FgRedColor
+	case k8s.TimelineRollout, k8s.TimelineDeploy:
+		return tablewriter.FgMagentaColor
+	case k8s.TimelineNode:
+		return tablewriter.FgYellowColor
+	default:
+		return tablewriter.FgBlueColor
+	}
+}
+
+func timelineObject(e k8s.TimelineEntry) string {
+	object := fmt.Sprintf("%s/%s", strings.ToLower(e.Kind), e.Object)
+	if e.Namespace != "" {
+		object = e.Namespace + "/" + object
+	}
+	return object
+}
+
+func timelineMarkdown(entries []k8s.TimelineEntry, start time.Time, namespace string) string {
+	var sb strings.Builder
+
+	scope := "all namespaces"
+	if namespace != "" {
+		scope = "namespace `" + namespace + "`"
+	}
+
+	sb.WriteString("# Incident Timeline\n\n")
+	sb.WriteString(fmt.Sprintf("Window: %s to %s (%s)\n\n",
+		start.UTC().Format(time.RFC3339), time.Now().UTC().Format(time.RFC3339), scope))
+
+	if len(entries) == 0 {
+		sb.WriteString("_No activity recorded in this window._\n")
+		return sb.String()
+	}
+
+	sb.WriteString("| Time (UTC) | Source | Object | Reason | Details |\n")
+	sb.WriteString("|------------|--------|--------|--------|---------|\n")
+	for _, e := range entries {
+		reason := markdownCell(e.Reason)
+		if e.Warning {
+			reason = "⚠️ " + reason
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | `%s` | %s | %s |\n",
+			e.Time.UTC().Format("2006-01-02 15:04:05"),
+			e.Source,
+			timelineObject(e),
+			reason,
+			markdownCell(e.Message)))
+	}
+
+	return sb.String()
+}
+
+// markdownCell keeps free text from breaking the table layout
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
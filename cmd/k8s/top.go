@@ -0,0 +1,192 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newTopCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "top",
+		Short: "Show live resource usage",
+		Long:  `Display live resource usage from the metrics API (metrics-server).`,
+	}
+
+	cmd.AddCommand(newTopNodesCmd())
+
+	return cmd
+}
+
+func newTopNodesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "nodes",
+		Aliases: []string{"node", "no"},
+		Short:   "Show node CPU/memory usage against allocatable",
+		Long: `Display per-node CPU and memory usage against allocatable capacity,
+together with pod counts and pressure conditions.
+
+Usage comes from the metrics API; without metrics-server the pod counts
+and conditions are still shown.
+
+Examples:
+  devops-toolkit k8s top nodes
+  devops-toolkit k8s top nodes --sort-by memory
+  devops-toolkit k8s top nodes -l node-role.kubernetes.io/worker`,
+		RunE: runTopNodes,
+	}
+
+	cmd.Flags().String("sort-by", "cpu", "Sort by cpu, memory, pods or name")
+	cmd.Flags().StringP("selector", "l", "", "Node label selector")
+
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("sort-by", completion.TopSortCompletion)
+
+	return cmd
+}
+
+func runTopNodes(cmd *cobra.Command, args []string) error {
+	sortBy, _ := cmd.Flags().GetString("sort-by")
+	selector, _ := cmd.Flags().GetString("selector")
+
+	switch sortBy {
+	case "cpu", "memory", "pods", "name":
+	default:
+		return exitcode.ConfigError(fmt.Errorf("invalid --sort-by value: %s (valid: cpu, memory, pods, name)", sortBy))
+	}
+
+	output.StartSpinner("Fetching node usage...")
+
+	client, err := k8s.NewClient(
+		cmd.Flag("kubeconfig").Value.String(),
+		cmd.Flag("context").Value.String(),
+	)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	nodes, err := client.TopNodes(cmd.Context(), selector)
+	if err != nil {
+		output.SpinnerError("Failed to fetch node usage")
+		return fmt.Errorf("failed to get node usage: %w", err)
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("Found %d nodes", len(nodes)))
+	output.Newline()
+
+	sortNodeUsage(nodes, sortBy)
+
+	if output.IsStructured() {
+		return output.Render(nodes)
+	}
+
+	if len(nodes) == 0 {
+		output.Info("No nodes found")
+		return nil
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Node Usage",
+		Headers:    []string{"Node", "Status", "CPU", "CPU %", "Memory", "Memory %", "Pods", "Pressure"},
+		ShowBorder: true,
+	})
+
+	hasMetrics := false
+	for _, n := range nodes {
+		status := "Ready"
+		statusColor := tablewriter.FgGreenColor
+		if !n.Ready {
+			status = "NotReady"
+			statusColor = tablewriter.FgRedColor
+		}
+		if n.Unschedulable {
+			status += ",Cordoned"
+			statusColor = tablewriter.FgYellowColor
+		}
+
+		cpu, cpuPct, mem, memPct := "-", "-", "-", "-"
+		if n.HasMetrics {
+			hasMetrics = true
+			cpu = fmt.Sprintf("%dm/%dm", n.CPUUsage, n.CPUAllocatable)
+			cpuPct = output.ProgressBar(int(n.CPUPercent), 100, 10)
+			mem = fmt.Sprintf("%s/%s", format.Quantity(n.MemoryUsage), format.Quantity(n.MemoryAllocatable))
+			memPct = output.ProgressBar(int(n.MemoryPercent), 100, 10)
+		}
+
+		pressure := nodePressure(n)
+		pressureColor := tablewriter.FgHiBlackColor
+		if pressure != "-" {
+			pressureColor = tablewriter.FgRedColor
+		}
+
+		podPct := 0.0
+		if n.PodCapacity > 0 {
+			podPct = float64(n.Pods) / float64(n.PodCapacity) * 100
+		}
+
+		table.AddColoredRow(
+			[]string{n.Name, status, cpu, cpuPct, mem, memPct, fmt.Sprintf("%d/%d", n.Pods, n.PodCapacity), pressure},
+			[]tablewriter.Colors{
+				{tablewriter.FgCyanColor},
+				{tablewriter.Bold, statusColor},
+				{tablewriter.FgWhiteColor},
+				{utilColor(n.CPUPercent)},
+				{tablewriter.FgWhiteColor},
+				{utilColor(n.MemoryPercent)},
+				{utilColor(podPct)},
+				{pressureColor},
+			},
+		)
+	}
+
+	table.Render()
+
+	if !hasMetrics {
+		output.Newline()
+		output.Warning("Metrics API not available; install metrics-server to see live usage")
+	}
+
+	output.Newline()
+	return nil
+}
+
+func sortNodeUsage(nodes []k8s.NodeUsage, sortBy string) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		switch sortBy {
+		case "memory":
+			return nodes[i].MemoryPercent > nodes[j].MemoryPercent
+		case "pods":
+			return nodes[i].Pods > nodes[j].Pods
+		case "name":
+			return nodes[i].Name < nodes[j].Name
+		default:
+			return nodes[i].CPUPercent > nodes[j].CPUPercent
+		}
+	})
+}
+
+func nodePressure(n k8s.NodeUsage) string {
+	var conditions []string
+	if n.MemoryPressure {
+		conditions = append(conditions, "Memory")
+	}
+	if n.DiskPressure {
+		conditions = append(conditions, "Disk")
+	}
+	if n.PIDPressure {
+		conditions = append(conditions, "PID")
+	}
+	if len(conditions) == 0 {
+		return "-"
+	}
+	return strings.Join(conditions, ",")
+}
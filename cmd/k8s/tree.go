@@ -0,0 +1,149 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newTreeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tree <kind>/<name> | <kind> <name>",
+		Short: "Show the ownership tree of a workload with the status of each object",
+		Long: `Follow ownerReferences up from an object to its top-level owner and
+down to everything that owner controls, for example
+CronJob → Job → Pod or Deployment → ReplicaSet → Pod, and show the status
+of each object in one view.
+
+Starting from a pod shows its siblings and owners; starting from a
+Deployment shows its ReplicaSets and their pods. Scaled-down ReplicaSets
+are hidden unless --all is set.
+
+Supported kinds: cronjob, deployment, statefulset, daemonset, replicaset,
+job and pod (and their kubectl short names). Owners of other kinds, such
+as Argo Rollouts, are shown by name.
+
+Examples:
+  devops-toolkit k8s tree deploy/api -n shop
+  devops-toolkit k8s tree pod api-7d9f-x2k4q -n shop
+  devops-toolkit k8s tree cronjob/nightly-report --all`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: runTree,
+	}
+
+	cmd.Flags().Bool("all", false, "Include scaled-down ReplicaSets")
+
+	return cmd
+}
+
+func runTree(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+
+	resource, name := args[0], ""
+	if len(args) == 2 {
+		name = args[1]
+	} else if r, n, ok := strings.Cut(args[0], "/"); ok {
+		resource, name = r, n
+	}
+	if name == "" {
+		return exitcode.ConfigError(fmt.Errorf("expected <kind>/<name> or <kind> <name>"))
+	}
+	kind, err := k8s.TreeKind(resource)
+	if err != nil {
+		return exitcode.ConfigError(err)
+	}
+
+	namespace := cmd.Flag("namespace").Value.String()
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	output.StartSpinner(fmt.Sprintf("Building ownership tree for %s/%s...", strings.ToLower(kind), name))
+
+	client, err := k8s.NewClient(
+		cmd.Flag("kubeconfig").Value.String(),
+		cmd.Flag("context").Value.String(),
+	)
+	if err != nil {
+		output.SpinnerError("Failed to connect to cluster")
+		return err
+	}
+
+	root, err := client.GetOwnershipTree(cmd.Context(), namespace, kind, name)
+	if err != nil {
+		output.SpinnerError("Failed to build ownership tree")
+		return err
+	}
+
+	hidden := 0
+	if !all {
+		hidden = pruneScaledDown(root)
+	}
+
+	output.SpinnerSuccess(fmt.Sprintf("%s/%s in namespace %s", root.Kind, root.Name, namespace))
+	output.Newline()
+
+	if output.IsStructured() {
+		return output.Render(root)
+	}
+
+	output.PrintTree(treeNode(root))
+	output.Newline()
+	if hidden > 0 {
+		output.Muted(fmt.Sprintf("  %d scaled-down ReplicaSets hidden (use --all to show them)", hidden))
+	}
+	return nil
+}
+
+// pruneScaledDown removes inactive ReplicaSets without pods, unless they are
+// the object the tree was requested for, and returns how many were removed
+func pruneScaledDown(node *k8s.ObjectNode) int {
+	removed := 0
+	kept := node.Children[:0]
+	for _, child := range node.Children {
+		if child.Kind == "ReplicaSet" && child.Health == k8s.TreeInactive && len(child.Children) == 0 && !child.Target {
+			removed++
+			continue
+		}
+		removed += pruneScaledDown(child)
+		kept = append(kept, child)
+	}
+	node.Children = kept
+	return removed
+}
+
+func treeNode(node *k8s.ObjectNode) output.TreeNode {
+	label := fmt.Sprintf("%s %s/%s  %s", treeHealthIcon(node.Health), node.Kind, node.Name, output.MutedStyle.Render(node.Status))
+	if !node.Created.IsZero() {
+		label += output.MutedStyle.Render(", " + format.Age(node.Created))
+	}
+	if node.Target {
+		label += "  " + output.InfoStyle.Render(output.IconArrow+" requested")
+	}
+
+	tn := output.TreeNode{Label: label}
+	for _, child := range node.Children {
+		tn.Children = append(tn.Children, treeNode(child))
+	}
+	return tn
+}
+
+func treeHealthIcon(health string) string {
+	switch health {
+	case k8s.TreeHealthy:
+		return output.SuccessStyle.Render(output.IconSuccess)
+	case k8s.TreeWarning:
+		return output.WarningStyle.Render(output.IconWarning)
+	case k8s.TreeFailed:
+		return output.ErrorStyle.Render(output.IconError)
+	case k8s.TreeInactive:
+		return output.MutedStyle.Render(output.IconPending)
+	default:
+		return output.InfoStyle.Render(output.IconInfo)
+	}
+}
@@ -0,0 +1,57 @@
+package logs
+
+import (
+	"os"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/loki"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/secrets"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// NewLogsCmd creates the logs command
+func NewLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Centralized log querying",
+		Long: `Query centralized logs (Loki).
+
+Unlike 'docker logs', centralized logs survive container restarts and
+rescheduling, so they show why a crash-looping pod died.`,
+	}
+
+	// Add subcommands
+	cmd.AddCommand(newQueryCmd())
+
+	// Persistent flags for logs commands
+	cmd.PersistentFlags().String("loki-url", "", "Loki URL (default from LOKI_URL or loki.url)")
+	cmd.PersistentFlags().String("org-id", "", "Loki tenant (X-Scope-OrgID) for multi-tenant setups (default from loki.org_id)")
+
+	return cmd
+}
+
+func getClient(cmd *cobra.Command) (*loki.Client, error) {
+	url := cmd.Flag("loki-url").Value.String()
+	if url == "" {
+		url = os.Getenv("LOKI_URL")
+	}
+	if url == "" {
+		url = viper.GetString("loki.url")
+	}
+
+	orgID := cmd.Flag("org-id").Value.String()
+	if orgID == "" {
+		orgID = viper.GetString("loki.org_id")
+	}
+
+	token := os.Getenv("LOKI_TOKEN")
+	if token == "" {
+		token = viper.GetString("loki.token")
+	}
+	token, err := secrets.Resolve(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return loki.NewClient(url, token, orgID)
+}
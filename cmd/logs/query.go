@@ -0,0 +1,148 @@
+package logs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/loki"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func newQueryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query [LOGQL]",
+		Short: "Run a LogQL query with level highlighting",
+		Long: `Run a LogQL log query against Loki and print the most recent lines,
+oldest first, highlighted by log level.
+
+Instead of writing LogQL, use --pod (with -n and --container) to select a
+pod's logs, including those of containers that have since restarted.`,
+		Example: `  devops-toolkit logs query '{namespace="payments", app="api"} |= "timeout"'
+  devops-toolkit logs query --pod api-7d9f8b-x2k4q -n payments --since 2h
+  devops-toolkit logs query --pod api-7d9f8b-x2k4q -n payments --level error --grep timeout`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runQuery,
+	}
+
+	cmd.Flags().StringP("namespace", "n", "", "Namespace of --pod")
+	cmd.Flags().String("pod", "", "Query the logs of this pod")
+	cmd.Flags().String("container", "", "Only this container of --pod")
+	cmd.Flags().String("grep", "", "Only lines containing this text")
+	cmd.Flags().Duration("since", time.Hour, "How far back to query")
+	cmd.Flags().Int("limit", 500, "Maximum number of lines")
+	cmd.Flags().String("level", "", "Filter by log level (error, warn, info, debug)")
+	cmd.Flags().Bool("timestamps", true, "Show timestamps")
+	cmd.Flags().Bool("labels", false, "Prefix lines with their stream labels")
+
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("namespace", completion.NamespaceCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("pod", completion.PodCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("level", completion.LogLevelCompletion)
+
+	return cmd
+}
+
+func runQuery(cmd *cobra.Command, args []string) error {
+	namespace, _ := cmd.Flags().GetString("namespace")
+	pod, _ := cmd.Flags().GetString("pod")
+	container, _ := cmd.Flags().GetString("container")
+	grep, _ := cmd.Flags().GetString("grep")
+	since, _ := cmd.Flags().GetDuration("since")
+	limit, _ := cmd.Flags().GetInt("limit")
+	level, _ := cmd.Flags().GetString("level")
+	timestamps, _ := cmd.Flags().GetBool("timestamps")
+	showLabels, _ := cmd.Flags().GetBool("labels")
+
+	var query string
+	switch {
+	case len(args) == 1 && pod != "":
+		return fmt.Errorf("use either a LogQL query or --pod, not both")
+	case len(args) == 1:
+		query = args[0]
+	case pod != "":
+		query = loki.PodSelector(namespace, pod, container)
+	default:
+		return fmt.Errorf("specify a LogQL query or --pod")
+	}
+	if grep != "" {
+		query += fmt.Sprintf(" |= %q", grep)
+	}
+
+	client, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	output.StartSpinner("Querying Loki...")
+	end := time.Now()
+	entries, err := client.QueryRange(cmd.Context(), query, loki.QueryOptions{
+		Start: end.Add(-since),
+		End:   end,
+		Limit: limit,
+		Level: level,
+	})
+	if err != nil {
+		output.SpinnerError("Query failed")
+		return err
+	}
+	output.SpinnerSuccess(fmt.Sprintf("Found %d lines", len(entries)))
+
+	if output.IsStructured() {
+		return output.Render(entries)
+	}
+
+	output.Header(fmt.Sprintf("Logs: %s", query))
+	if len(entries) == 0 {
+		output.Info(fmt.Sprintf("No lines in the last %s", since))
+		return nil
+	}
+
+	for _, e := range entries {
+		printEntry(e, timestamps, showLabels)
+	}
+
+	if len(entries) >= limit {
+		output.Newline()
+		output.Muted(fmt.Sprintf("Showing the most recent %d lines; raise --limit or narrow --since for more", limit))
+	}
+
+	return nil
+}
+
+func printEntry(e loki.Entry, timestamps, showLabels bool) {
+	var prefix string
+
+	// Timestamp
+	if timestamps {
+		prefix = output.MutedStyle.Render(format.TimestampLayout(e.Timestamp, "2006-01-02 15:04:05.000")) + " "
+	}
+
+	// Stream labels
+	if showLabels {
+		prefix += output.InfoStyle.Render(formatLabels(e.Labels)) + " "
+	} else if c := e.Labels["container"]; c != "" {
+		prefix += output.MutedStyle.Render(c) + " "
+	}
+
+	// Color based on detected level
+	fmt.Printf("%s%s\n", prefix, output.HighlightLog(e.Level, e.Line))
+}
+
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, labels[k]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,340 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/alerting"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/notify"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/prometheus"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/secrets"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// NewMonitorCmd creates the monitor command
+func NewMonitorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "monitor",
+		Short: "Evaluate alert rules once or continuously",
+		Long: `Evaluate threshold rules from a YAML rules file and notify sinks.
+
+Rule types:
+  node_not_ready     A node is not Ready
+  pod_restarts       A pod restarted more than threshold times (namespace optional)
+  cpu_percent        Node CPU above threshold% (Prometheus node-exporter,
+                     or cluster CPU requests without Prometheus)
+  pipeline_failed    The latest pipeline on ref (default main) failed
+  compliance_score   Compliance score of target (k8s, docker, files) below threshold
+  http_check         url fails its expected status/contains, or is slower than threshold ms
+
+One-shot mode prints the firing alerts and notifies sinks of all of them.
+Daemon mode re-evaluates every interval and only notifies when an alert
+starts firing or resolves.`,
+		Example: `  devops-toolkit monitor --rules alerts.yaml
+  devops-toolkit monitor --rules alerts.yaml --fail-on-alert
+  devops-toolkit monitor --rules alerts.yaml --daemon --interval 30s`,
+		RunE:         runMonitor,
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringP("rules", "r", "", "Rules file (default from monitor.rules)")
+	cmd.Flags().Bool("daemon", false, "Keep evaluating every interval until interrupted")
+	cmd.Flags().Duration("interval", 0, "Evaluation interval in daemon mode (default from the rules file, or 1m)")
+	cmd.Flags().Bool("fail-on-alert", false, "Exit with error when any alert fires (one-shot mode)")
+	cmd.Flags().Bool("no-notify", false, "Print alerts without notifying sinks")
+	cmd.Flags().String("kubeconfig", "", "Path to kubeconfig file")
+	cmd.Flags().StringP("context", "c", "", "Kubernetes context to use")
+	cmd.Flags().String("prometheus-url", "", "Prometheus URL for node CPU (default from PROMETHEUS_URL or prometheus.url)")
+
+	// Register flag completions
+	_ = cmd.MarkFlagFilename("rules", "yaml", "yml")
+	_ = cmd.RegisterFlagCompletionFunc("context", completion.ContextCompletion)
+
+	return cmd
+}
+
+func runMonitor(cmd *cobra.Command, args []string) error {
+	rulesFile, _ := cmd.Flags().GetString("rules")
+	if rulesFile == "" {
+		rulesFile = viper.GetString("monitor.rules")
+	}
+	if rulesFile == "" {
+		return fmt.Errorf("rules file required (use --rules or monitor.rules in the config file)")
+	}
+
+	cfg, err := alerting.LoadConfig(rulesFile)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Rules) == 0 {
+		return fmt.Errorf("no rules in %s", rulesFile)
+	}
+
+	daemon, _ := cmd.Flags().GetBool("daemon")
+	failOnAlert, _ := cmd.Flags().GetBool("fail-on-alert")
+	noNotify, _ := cmd.Flags().GetBool("no-notify")
+	if interval, _ := cmd.Flags().GetDuration("interval"); interval > 0 {
+		cfg.Interval = interval
+	}
+
+	var sinks []notify.Sink
+	if !noNotify {
+		// Sinks from the config file or env, plus those of the rules file
+		sinks, err = notify.Sinks()
+		if err != nil {
+			return err
+		}
+		for _, sc := range cfg.Sinks {
+			sink, err := notify.NewSink(sc)
+			if err != nil {
+				return err
+			}
+			sinks = append(sinks, sink)
+		}
+	}
+
+	engine, err := newEngine(cmd, cfg.Rules)
+	if err != nil {
+		return err
+	}
+
+	if daemon {
+		return runDaemon(cmd.Context(), engine, cfg, sinks)
+	}
+	return runOnce(cmd.Context(), engine, cfg, sinks, failOnAlert)
+}
+
+func runOnce(ctx context.Context, engine *alerting.Engine, cfg *alerting.Config, sinks []notify.Sink, failOnAlert bool) error {
+	output.StartSpinner(fmt.Sprintf("Evaluating %d rules...", len(cfg.Rules)))
+	alerts, errs := engine.Evaluate(ctx, cfg.Rules)
+	if len(alerts) > 0 {
+		output.SpinnerError(fmt.Sprintf("%d alerts firing", len(alerts)))
+	} else {
+		output.SpinnerSuccess("No alerts firing")
+	}
+
+	for _, a := range alerts {
+		if err := notify.Send(ctx, sinks, a.Notification(notify.StatusFiring)); err != nil {
+			output.Warning(fmt.Sprintf("Notification failed: %v", err))
+		}
+	}
+
+	if output.IsStructured() {
+		if err := output.Render(alerts); err != nil {
+			return err
+		}
+	} else {
+		output.Newline()
+		printAlerts(alerts)
+		printRuleErrors(errs)
+	}
+
+	if failOnAlert && len(alerts) > 0 {
+		return exitcode.ChecksFailedf("%d alerts firing", len(alerts))
+	}
+	return nil
+}
+
+func runDaemon(ctx context.Context, engine *alerting.Engine, cfg *alerting.Config, sinks []notify.Sink) error {
+
+	output.Info(fmt.Sprintf("Monitoring %d rules every %s (Ctrl+C to stop)", len(cfg.Rules), cfg.Interval))
+	output.Newline()
+
+	tracker := alerting.NewTracker()
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		alerts, errs := engine.Evaluate(ctx, cfg.Rules)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		errored := make([]string, 0, len(errs))
+		for _, e := range errs {
+			errored = append(errored, e.Rule)
+		}
+		fired, resolved := tracker.Update(alerts, errored)
+
+		for _, a := range fired {
+			printTransition(a, notify.StatusFiring)
+			if err := notify.Send(ctx, sinks, a.Notification(notify.StatusFiring)); err != nil {
+				output.Warning(fmt.Sprintf("Notification failed: %v", err))
+			}
+		}
+		for _, a := range resolved {
+			printTransition(a, notify.StatusResolved)
+			if err := notify.Send(ctx, sinks, a.Notification(notify.StatusResolved)); err != nil {
+				output.Warning(fmt.Sprintf("Notification failed: %v", err))
+			}
+		}
+		for _, e := range errs {
+			output.Warning(fmt.Sprintf("%s %v", time.Now().Format("15:04:05"), e))
+		}
+
+		select {
+		case <-ctx.Done():
+			output.Newline()
+			output.Info(fmt.Sprintf("Stopped with %d alerts firing", len(tracker.Active())))
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// newEngine creates only the clients the rules need
+func newEngine(cmd *cobra.Command, rules []alerting.Rule) (*alerting.Engine, error) {
+	engine := &alerting.Engine{}
+
+	var needsK8s, needsGitLab, needsCPU bool
+	for _, r := range rules {
+		needsK8s = needsK8s || r.NeedsKubernetes()
+		needsGitLab = needsGitLab || r.NeedsGitLab()
+		needsCPU = needsCPU || r.Type == alerting.TypeCPUPercent
+	}
+
+	if needsK8s {
+		client, err := k8s.NewClient(
+			cmd.Flag("kubeconfig").Value.String(),
+			cmd.Flag("context").Value.String(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+		}
+		engine.K8s = client
+	}
+
+	if needsGitLab {
+		client, project, err := getGitLabClient()
+		if err != nil {
+			return nil, err
+		}
+		engine.GitLab = client
+		engine.DefaultProject = project
+	}
+
+	if needsCPU {
+		url := cmd.Flag("prometheus-url").Value.String()
+		if url == "" {
+			url = os.Getenv("PROMETHEUS_URL")
+		}
+		if url == "" {
+			url = viper.GetString("prometheus.url")
+		}
+		if url != "" {
+			token := os.Getenv("PROMETHEUS_TOKEN")
+			if token == "" {
+				token = viper.GetString("prometheus.token")
+			}
+			token, err := secrets.Resolve(token)
+			if err != nil {
+				return nil, err
+			}
+			client, err := prometheus.NewClient(url, token)
+			if err != nil {
+				return nil, err
+			}
+			engine.Prometheus = client
+		}
+	}
+
+	return engine, nil
+}
+
+func getGitLabClient() (*gitlabclient.Client, string, error) {
+	token := os.Getenv("GITLAB_TOKEN")
+	if token == "" {
+		token = viper.GetString("gitlab.token")
+	}
+	if token == "" {
+		return nil, "", fmt.Errorf("GitLab token required for pipeline rules (set GITLAB_TOKEN)")
+	}
+	token, err := secrets.Resolve(token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	url := os.Getenv("GITLAB_URL")
+	if url == "" {
+		url = viper.GetString("gitlab.url")
+	}
+	if url == "" {
+		url = "https://gitlab.com"
+	}
+
+	project := os.Getenv("GITLAB_PROJECT")
+	if project == "" {
+		project = viper.GetString("gitlab.project")
+	}
+
+	client, err := gitlabclient.NewClient(url, token)
+	if err != nil {
+		return nil, "", err
+	}
+	return client, project, nil
+}
+
+func printAlerts(alerts []alerting.Alert) {
+	if len(alerts) == 0 {
+		output.Success("All rules passing")
+		return
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Firing Alerts",
+		Headers:    []string{"Severity", "Rule", "Resource", "Message"},
+		ShowBorder: true,
+	})
+
+	for _, a := range alerts {
+		color := tablewriter.FgYellowColor
+		if a.Severity == alerting.SeverityCritical {
+			color = tablewriter.FgRedColor
+		}
+		table.AddColoredRow(
+			[]string{a.Severity, a.Rule, a.Resource, a.Message},
+			[]tablewriter.Colors{
+				{color, tablewriter.Bold},
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgCyanColor},
+				{tablewriter.FgWhiteColor},
+			},
+		)
+	}
+
+	table.Render()
+}
+
+func printRuleErrors(errs []*alerting.RuleError) {
+	if len(errs) == 0 {
+		return
+	}
+	output.Newline()
+	for _, e := range errs {
+		output.Warning(e.Error())
+	}
+}
+
+func printTransition(a alerting.Alert, status string) {
+	ts := output.MutedStyle.Render(time.Now().Format("15:04:05"))
+
+	if status == notify.StatusResolved {
+		output.Printf("%s %s %s %s\n", ts, output.SuccessStyle.Render(output.IconSuccess+" RESOLVED"), a.Rule, a.Resource)
+		return
+	}
+
+	style := output.WarningStyle
+	if a.Severity == alerting.SeverityCritical {
+		style = output.ErrorStyle
+	}
+	output.Printf("%s %s %s %s\n", ts, style.Render(output.IconError+" FIRING"), a.Rule, a.Message)
+}
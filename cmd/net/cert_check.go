@@ -0,0 +1,239 @@
+package net
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/netcheck"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// maxConcurrentChecks bounds parallel connections in batch mode
+const maxConcurrentChecks = 10
+
+func newCertCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cert-check [HOST[:PORT]...]",
+		Short: "Check TLS certificates of endpoints",
+		Long: `Check the TLS certificates served by endpoints (port 443 by default).
+
+Reports:
+  • Expiry countdown of the certificate and its intermediates
+  • Chains that do not lead to a trusted root (e.g. missing intermediates)
+  • Hostname mismatches against the SANs
+  • Weak signature algorithms (SHA-1, MD5) and small keys
+  • TLS versions older than 1.2
+
+Targets can also be read from a file (one per line, # comments allowed).`,
+		Example: `  devops-toolkit net cert-check example.com api.example.com:8443
+  devops-toolkit net cert-check --file endpoints.txt --warn-days 21 --crit-days 7
+  devops-toolkit net cert-check 10.0.0.5:443 --server-name internal.example.com --chain
+  devops-toolkit net cert-check --file endpoints.txt --output json --fail-on warning`,
+		RunE:         runCertCheck,
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringP("file", "f", "", "Read targets from a file")
+	cmd.Flags().String("server-name", "", "SNI and hostname to check, when it differs from the target host")
+	cmd.Flags().Int("warn-days", 30, "Warn when a certificate expires within this many days")
+	cmd.Flags().Int("crit-days", 7, "Critical when a certificate expires within this many days")
+	cmd.Flags().Duration("timeout", 5*time.Second, "Connection timeout per endpoint")
+	cmd.Flags().Bool("chain", false, "Show every certificate of the served chain")
+	cmd.Flags().String("fail-on", "critical", "Exit with error at this status or worse (none, warning, critical, error)")
+
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("fail-on", completion.CheckStatusCompletion)
+
+	return cmd
+}
+
+func runCertCheck(cmd *cobra.Command, args []string) error {
+	file, _ := cmd.Flags().GetString("file")
+	serverName, _ := cmd.Flags().GetString("server-name")
+	warnDays, _ := cmd.Flags().GetInt("warn-days")
+	critDays, _ := cmd.Flags().GetInt("crit-days")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	showChain, _ := cmd.Flags().GetBool("chain")
+	failOn, _ := cmd.Flags().GetString("fail-on")
+
+	if err := validateFailOn(failOn); err != nil {
+		return err
+	}
+
+	targets := args
+	if file != "" {
+		fileTargets, err := netcheck.ReadTargets(file)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, fileTargets...)
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("specify at least one HOST[:PORT] or --file")
+	}
+
+	opts := netcheck.CertOptions{
+		ServerName: serverName,
+		Timeout:    timeout,
+		WarnDays:   warnDays,
+		CritDays:   critDays,
+	}
+
+	output.StartSpinner(fmt.Sprintf("Checking %d endpoints...", len(targets)))
+	results := checkCerts(cmd.Context(), targets, opts)
+	output.StopSpinner()
+
+	if output.IsStructured() {
+		if err := output.Render(results); err != nil {
+			return err
+		}
+	} else {
+		displayCertResults(results, showChain)
+	}
+
+	var failing int
+	for _, r := range results {
+		if netcheck.AtLeast(r.Status, failOn) {
+			failing++
+		}
+	}
+	if failing > 0 {
+		return exitcode.ChecksFailedf("%d of %d endpoints at %s or worse", failing, len(results), failOn)
+	}
+	return nil
+}
+
+// checkCerts checks targets concurrently, keeping their order
+func checkCerts(ctx context.Context, targets []string, opts netcheck.CertOptions) []netcheck.CertResult {
+	results := make([]netcheck.CertResult, len(targets))
+	sem := make(chan struct{}, maxConcurrentChecks)
+	var wg sync.WaitGroup
+
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			checkCtx, cancel := context.WithTimeout(ctx, opts.Timeout+5*time.Second)
+			defer cancel()
+			results[i] = netcheck.CheckCert(checkCtx, target, opts)
+		}(i, target)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func displayCertResults(results []netcheck.CertResult, showChain bool) {
+	table := output.NewTable(output.TableConfig{
+		Title:      "TLS Certificates",
+		Headers:    []string{"Endpoint", "Subject", "Issuer", "Expires", "Days Left", "TLS", "Status"},
+		ShowBorder: true,
+	})
+
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.Status]++
+
+		row := []string{r.Target, "-", "-", "-", "-", "-", r.Status}
+		if len(r.Chain) > 0 {
+			leaf := r.Chain[0]
+			row = []string{
+				r.Target,
+				output.Truncate(leaf.Subject, 30),
+				output.Truncate(leaf.Issuer, 25),
+				leaf.NotAfter.Format("2006-01-02"),
+				fmt.Sprintf("%d", r.DaysLeft),
+				strings.TrimPrefix(r.TLSVersion, "TLS "),
+				r.Status,
+			}
+		}
+
+		table.AddColoredRow(row, []tablewriter.Colors{
+			{tablewriter.FgCyanColor},
+			{tablewriter.FgWhiteColor},
+			{tablewriter.FgWhiteColor},
+			{tablewriter.FgWhiteColor},
+			{statusColor(r.Status)},
+			{tablewriter.FgWhiteColor},
+			{statusColor(r.Status), tablewriter.Bold},
+		})
+	}
+
+	table.Render()
+
+	// Findings
+	for _, r := range results {
+		if r.Error == "" && len(r.Issues) == 0 {
+			continue
+		}
+		output.Print(output.Section(r.Target))
+		if r.Error != "" {
+			output.Printf("  %s %s\n", output.ErrorStyle.Render(output.IconError), r.Error)
+		}
+		for _, issue := range r.Issues {
+			output.Printf("  %s %s\n", issueIcon(issue.Severity), issue.Message)
+		}
+	}
+
+	if showChain {
+		for _, r := range results {
+			if len(r.Chain) == 0 {
+				continue
+			}
+			output.Print(output.Section("Chain: " + r.Target))
+			for i, c := range r.Chain {
+				output.Printf("  %d. %s\n", i, output.InfoStyle.Render(c.Subject))
+				output.Printf("     %s\n", output.KeyValue("Issuer", c.Issuer))
+				output.Printf("     %s\n", output.KeyValue("Valid", fmt.Sprintf("%s → %s (%d days left)",
+					c.NotBefore.Format("2006-01-02"), c.NotAfter.Format("2006-01-02"), c.DaysLeft)))
+				output.Printf("     %s\n", output.KeyValue("Signature", c.SignatureAlgorithm))
+				output.Printf("     %s\n", output.KeyValue("Key", c.PublicKey))
+				if len(c.DNSNames) > 0 {
+					output.Printf("     %s\n", output.KeyValue("SANs", strings.Join(c.DNSNames, ", ")))
+				}
+			}
+		}
+	}
+
+	// Summary
+	output.Newline()
+	printStatusCounts(counts)
+	output.Newline()
+}
+
+func validateFailOn(failOn string) error {
+	switch failOn {
+	case "none", netcheck.StatusWarning, netcheck.StatusCritical, netcheck.StatusError:
+		return nil
+	default:
+		return exitcode.ConfigError(fmt.Errorf("invalid --fail-on value: %s (valid: none, warning, critical, error)", failOn))
+	}
+}
+
+func statusColor(status string) int {
+	switch status {
+	case netcheck.StatusOK:
+		return tablewriter.FgGreenColor
+	case netcheck.StatusWarning:
+		return tablewriter.FgYellowColor
+	default:
+		return tablewriter.FgRedColor
+	}
+}
+
+func issueIcon(severity string) string {
+	if severity == netcheck.StatusWarning {
+		return output.WarningStyle.Render(output.IconWarning)
+	}
+	return output.ErrorStyle.Render(output.IconError)
+}
@@ -0,0 +1,292 @@
+package net
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/alerting"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/netcheck"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/notify"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newHTTPCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "http-check [URL...]",
+		Short: "Probe URLs for status, latency and content",
+		Long: `Probe URLs and report status code, latency and body content.
+
+URLs come from arguments, or from net.http_checks in the config file, where
+each check can set its own method, headers, expected status, body substring
+and latency limit. Flags apply to URLs given as arguments.
+
+With --daemon the checks repeat every interval, and failures that start or
+recover are sent to the configured notification sinks, like monitor alerts.
+The same probe is available to monitor rules as type http_check.`,
+		Example: `  devops-toolkit net http-check https://example.com/healthz
+  devops-toolkit net http-check https://api.example.com/ready --contains '"status":"ok"' --max-latency 500ms
+  devops-toolkit net http-check --output json --fail-on warning
+  devops-toolkit net http-check --daemon --interval 30s`,
+		RunE:         runHTTPCheck,
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().String("method", "GET", "HTTP method")
+	cmd.Flags().StringArrayP("header", "H", nil, "Request header (Name: value)")
+	cmd.Flags().IntSlice("expect-status", nil, "Accepted status codes (default 2xx and 3xx)")
+	cmd.Flags().String("contains", "", "Text the response body must contain")
+	cmd.Flags().Duration("max-latency", 0, "Warn when a response is slower than this")
+	cmd.Flags().Duration("timeout", 10*time.Second, "Request timeout")
+	cmd.Flags().Bool("insecure", false, "Skip TLS certificate verification")
+	cmd.Flags().Bool("daemon", false, "Keep probing every interval until interrupted")
+	cmd.Flags().Duration("interval", time.Minute, "Probe interval in daemon mode")
+	cmd.Flags().String("fail-on", "critical", "Exit with error at this status or worse (none, warning, critical, error)")
+
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("fail-on", completion.CheckStatusCompletion)
+
+	return cmd
+}
+
+func runHTTPCheck(cmd *cobra.Command, args []string) error {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	daemon, _ := cmd.Flags().GetBool("daemon")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	failOn, _ := cmd.Flags().GetString("fail-on")
+
+	if err := validateFailOn(failOn); err != nil {
+		return err
+	}
+
+	checks, err := httpChecks(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	if daemon {
+		return runHTTPDaemon(cmd.Context(), checks, timeout, interval)
+	}
+
+	output.StartSpinner(fmt.Sprintf("Probing %d URLs...", len(checks)))
+	results := probeAll(cmd.Context(), checks, timeout)
+	output.StopSpinner()
+
+	if output.IsStructured() {
+		if err := output.Render(results); err != nil {
+			return err
+		}
+	} else {
+		displayHTTPResults(results)
+	}
+
+	var failing int
+	for _, r := range results {
+		if netcheck.AtLeast(r.Status, failOn) {
+			failing++
+		}
+	}
+	if failing > 0 {
+		return exitcode.ChecksFailedf("%d of %d URLs at %s or worse", failing, len(results), failOn)
+	}
+	return nil
+}
+
+// httpChecks builds checks from the arguments and flags, or from net.http_checks
+func httpChecks(cmd *cobra.Command, args []string) ([]netcheck.HTTPCheck, error) {
+	if len(args) == 0 {
+		var checks []netcheck.HTTPCheck
+		if err := viper.UnmarshalKey("net.http_checks", &checks); err != nil {
+			return nil, fmt.Errorf("failed to parse net.http_checks: %w", err)
+		}
+		if len(checks) == 0 {
+			return nil, fmt.Errorf("specify URLs or configure net.http_checks")
+		}
+		for _, c := range checks {
+			if c.URL == "" {
+				return nil, fmt.Errorf("net.http_checks: check %q has no url", c.Name)
+			}
+		}
+		return checks, nil
+	}
+
+	method, _ := cmd.Flags().GetString("method")
+	headers, _ := cmd.Flags().GetStringArray("header")
+	expectStatus, _ := cmd.Flags().GetIntSlice("expect-status")
+	contains, _ := cmd.Flags().GetString("contains")
+	maxLatency, _ := cmd.Flags().GetDuration("max-latency")
+	insecure, _ := cmd.Flags().GetBool("insecure")
+
+	headerMap := make(map[string]string)
+	for _, h := range headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok {
+			return nil, exitcode.ConfigError(fmt.Errorf("invalid header %q (use Name: value)", h))
+		}
+		headerMap[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	checks := make([]netcheck.HTTPCheck, 0, len(args))
+	for _, url := range args {
+		if !strings.Contains(url, "://") {
+			url = "https://" + url
+		}
+		checks = append(checks, netcheck.HTTPCheck{
+			URL:          url,
+			Method:       method,
+			Header:       headerMap,
+			ExpectStatus: expectStatus,
+			Contains:     contains,
+			MaxLatency:   maxLatency,
+			Insecure:     insecure,
+		})
+	}
+	return checks, nil
+}
+
+// probeAll runs checks concurrently, keeping their order
+func probeAll(ctx context.Context, checks []netcheck.HTTPCheck, timeout time.Duration) []netcheck.HTTPResult {
+	results := make([]netcheck.HTTPResult, len(checks))
+	sem := make(chan struct{}, maxConcurrentChecks)
+	var wg sync.WaitGroup
+
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check netcheck.HTTPCheck) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = netcheck.CheckHTTP(ctx, check, timeout)
+		}(i, check)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func runHTTPDaemon(ctx context.Context, checks []netcheck.HTTPCheck, timeout, interval time.Duration) error {
+	sinks, err := notify.Sinks()
+	if err != nil {
+		return err
+	}
+
+	output.Info(fmt.Sprintf("Probing %d URLs every %s (Ctrl+C to stop)", len(checks), interval))
+	if len(sinks) == 0 {
+		output.Muted("No notification sinks configured; failures are only printed")
+	}
+	output.Newline()
+
+	tracker := alerting.NewTracker()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		results := probeAll(ctx, checks, timeout)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		fired, resolved := tracker.Update(httpAlerts(results), nil)
+		for _, a := range fired {
+			output.Printf("%s %s %s\n", output.MutedStyle.Render(time.Now().Format("15:04:05")),
+				output.ErrorStyle.Render(output.IconError+" DOWN"), a.Message)
+			if err := notify.Send(ctx, sinks, a.Notification(notify.StatusFiring)); err != nil {
+				output.Warning(fmt.Sprintf("Notification failed: %v", err))
+			}
+		}
+		for _, a := range resolved {
+			output.Printf("%s %s %s\n", output.MutedStyle.Render(time.Now().Format("15:04:05")),
+				output.SuccessStyle.Render(output.IconSuccess+" UP"), a.Resource)
+			if err := notify.Send(ctx, sinks, a.Notification(notify.StatusResolved)); err != nil {
+				output.Warning(fmt.Sprintf("Notification failed: %v", err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			output.Newline()
+			output.Info(fmt.Sprintf("Stopped with %d URLs failing", len(tracker.Active())))
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// httpAlerts turns failing results into alerts for the tracker
+func httpAlerts(results []netcheck.HTTPResult) []alerting.Alert {
+	var alerts []alerting.Alert
+	for _, r := range results {
+		if r.Status == netcheck.StatusOK {
+			continue
+		}
+		severity := alerting.SeverityCritical
+		if r.Status == netcheck.StatusWarning {
+			severity = alerting.SeverityWarning
+		}
+		alerts = append(alerts, alerting.Alert{
+			Rule:     "http-check",
+			Type:     alerting.TypeHTTPCheck,
+			Severity: severity,
+			Resource: r.URL,
+			Message:  fmt.Sprintf("%s: %s", r.Name, r.Summary()),
+			Value:    float64(r.Latency.Milliseconds()),
+			Link:     r.URL,
+			Since:    r.CheckedAt,
+		})
+	}
+	return alerts
+}
+
+func displayHTTPResults(results []netcheck.HTTPResult) {
+	table := output.NewTable(output.TableConfig{
+		Title:      "HTTP Checks",
+		Headers:    []string{"Name", "URL", "Code", "Latency", "Status", "Details"},
+		ShowBorder: true,
+	})
+
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.Status]++
+
+		code := "-"
+		if r.StatusCode > 0 {
+			code = fmt.Sprintf("%d", r.StatusCode)
+		}
+		name := r.Name
+		if name == r.URL {
+			name = "-"
+		}
+
+		table.AddColoredRow(
+			[]string{
+				output.Truncate(name, 20),
+				output.Truncate(r.URL, 45),
+				code,
+				r.Latency.Round(time.Millisecond).String(),
+				r.Status,
+				output.Truncate(r.Summary(), 50),
+			},
+			[]tablewriter.Colors{
+				{tablewriter.FgWhiteColor},
+				{tablewriter.FgCyanColor},
+				{statusColor(r.Status)},
+				{tablewriter.FgWhiteColor},
+				{statusColor(r.Status), tablewriter.Bold},
+				{tablewriter.FgWhiteColor},
+			},
+		)
+	}
+
+	table.Render()
+
+	output.Newline()
+	printStatusCounts(counts)
+	output.Newline()
+}
@@ -0,0 +1,227 @@
+package net
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/netcheck"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// matrixConfig is the format of --file and net.matrix
+type matrixConfig struct {
+	Sources []string `yaml:"sources" mapstructure:"sources"`
+	Targets []string `yaml:"targets" mapstructure:"targets"`
+}
+
+func newMatrixCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "matrix",
+		Short: "Test TCP connectivity between sources and targets",
+		Long: `Test TCP connectivity from each source to each target and render a
+reachability matrix, to debug NetworkPolicies, security groups and firewalls.
+
+Sources:
+  local                               This machine
+  pod:<namespace>/<pod>[/<container>] An existing pod (via Kubernetes exec)
+  debug:<namespace>[/<key=value,...>] A temporary pod with these labels, so
+                                      NetworkPolicies select it like the real
+                                      workload; deleted afterwards
+  docker:<container>                  A running container (via docker exec)
+
+Remote sources probe with nc, or bash's /dev/tcp when nc is missing.
+Sources and targets come from flags, --file, or net.matrix in the config file.`,
+		Example: `  devops-toolkit net matrix --from local --from debug:payments/app=api \
+    --to postgres.db.svc:5432 --to redis.cache.svc:6379
+  devops-toolkit net matrix --from pod:payments/api-7d9f8b-x2k4q --to 10.0.3.12:443
+  devops-toolkit net matrix --file connectivity.yaml --output json`,
+		RunE:         runMatrix,
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringArray("from", nil, "Source (repeatable): local, pod:ns/pod, debug:ns/labels, docker:container")
+	cmd.Flags().StringArray("to", nil, "Target host:port (repeatable)")
+	cmd.Flags().StringP("file", "f", "", "YAML file with sources and targets lists")
+	cmd.Flags().Duration("timeout", 3*time.Second, "Connection timeout per probe")
+	cmd.Flags().String("debug-image", netcheck.DefaultDebugImage, "Image for debug: sources (needs nc or bash)")
+	cmd.Flags().Bool("fail-on-unreachable", false, "Exit with error when any target is unreachable")
+	cmd.Flags().String("kubeconfig", "", "Path to kubeconfig file")
+	cmd.Flags().StringP("context", "c", "", "Kubernetes context to use")
+
+	// Register flag completions
+	_ = cmd.MarkFlagFilename("file", "yaml", "yml")
+	_ = cmd.RegisterFlagCompletionFunc("context", completion.ContextCompletion)
+
+	return cmd
+}
+
+func runMatrix(cmd *cobra.Command, args []string) error {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	debugImage, _ := cmd.Flags().GetString("debug-image")
+	failOnUnreachable, _ := cmd.Flags().GetBool("fail-on-unreachable")
+
+	cfg, err := loadMatrixConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	var sources []netcheck.Source
+	var needsK8s, needsDocker bool
+	for _, spec := range cfg.Sources {
+		src, err := netcheck.ParseSource(spec)
+		if err != nil {
+			return err
+		}
+		needsK8s = needsK8s || src.Kind == netcheck.SourcePod || src.Kind == netcheck.SourceDebug
+		needsDocker = needsDocker || src.Kind == netcheck.SourceDocker
+		sources = append(sources, src)
+	}
+
+	var targets []netcheck.Target
+	for _, spec := range cfg.Targets {
+		target, err := netcheck.ParseTarget(spec)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, target)
+	}
+
+	runner := &netcheck.MatrixRunner{DebugImage: debugImage, Timeout: timeout}
+	if needsK8s {
+		runner.K8s, err = k8s.NewClient(
+			cmd.Flag("kubeconfig").Value.String(),
+			cmd.Flag("context").Value.String(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create kubernetes client: %w", err)
+		}
+	}
+	if needsDocker {
+		runner.Docker, err = docker.NewClient()
+		if err != nil {
+			return err
+		}
+		defer runner.Docker.Close()
+	}
+
+	output.StartSpinner(fmt.Sprintf("Probing %d targets from %d sources...", len(targets), len(sources)))
+	matrix, err := runner.Run(cmd.Context(), sources, targets)
+	if err != nil {
+		output.SpinnerError("Probe failed")
+		return err
+	}
+	output.StopSpinner()
+
+	if output.IsStructured() {
+		if err := output.Render(matrix); err != nil {
+			return err
+		}
+	} else {
+		displayMatrix(matrix)
+	}
+
+	if failOnUnreachable {
+		var unreachable int
+		for _, row := range matrix.Results {
+			for _, r := range row {
+				if !r.Reachable {
+					unreachable++
+				}
+			}
+		}
+		if unreachable > 0 {
+			return exitcode.ChecksFailedf("%d connections unreachable", unreachable)
+		}
+	}
+	return nil
+}
+
+// loadMatrixConfig merges --from/--to with --file, falling back to net.matrix
+func loadMatrixConfig(cmd *cobra.Command) (*matrixConfig, error) {
+	from, _ := cmd.Flags().GetStringArray("from")
+	to, _ := cmd.Flags().GetStringArray("to")
+	file, _ := cmd.Flags().GetString("file")
+
+	cfg := &matrixConfig{Sources: from, Targets: to}
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read matrix file: %w", err)
+		}
+		var fileCfg matrixConfig
+		if err := yaml.Unmarshal(data, &fileCfg); err != nil {
+			return nil, fmt.Errorf("failed to parse matrix file %s: %w", file, err)
+		}
+		cfg.Sources = append(cfg.Sources, fileCfg.Sources...)
+		cfg.Targets = append(cfg.Targets, fileCfg.Targets...)
+	}
+
+	if len(cfg.Sources) == 0 && len(cfg.Targets) == 0 {
+		if err := viper.UnmarshalKey("net.matrix", cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse net.matrix: %w", err)
+		}
+	}
+	if len(cfg.Sources) == 0 {
+		cfg.Sources = []string{netcheck.SourceLocal}
+	}
+	if len(cfg.Targets) == 0 {
+		return nil, fmt.Errorf("specify targets with --to, --file or net.matrix")
+	}
+	return cfg, nil
+}
+
+func displayMatrix(m *netcheck.Matrix) {
+	headers := append([]string{"Source \\ Target"}, m.Targets...)
+	table := output.NewTable(output.TableConfig{
+		Title:      "Connectivity Matrix",
+		Headers:    headers,
+		ShowBorder: true,
+	})
+
+	var failures []netcheck.ProbeResult
+	var reachable, total int
+	for i, row := range m.Results {
+		cells := []string{m.Sources[i]}
+		colors := []tablewriter.Colors{{tablewriter.FgCyanColor}}
+		for _, r := range row {
+			total++
+			if r.Reachable {
+				reachable++
+				cell := output.IconSuccess
+				if r.Latency > 0 {
+					cell += " " + r.Latency.Round(time.Millisecond).String()
+				}
+				cells = append(cells, cell)
+				colors = append(colors, tablewriter.Colors{tablewriter.FgGreenColor})
+			} else {
+				failures = append(failures, r)
+				cells = append(cells, output.IconError)
+				colors = append(colors, tablewriter.Colors{tablewriter.FgRedColor, tablewriter.Bold})
+			}
+		}
+		table.AddColoredRow(cells, colors)
+	}
+
+	table.Render()
+
+	if len(failures) > 0 {
+		output.Print(output.Section("Unreachable"))
+		for _, f := range failures {
+			output.Printf("  %s %s %s %s: %s\n", output.ErrorStyle.Render(output.IconError),
+				f.Source, output.IconArrow, f.Target, output.MutedStyle.Render(f.Error))
+		}
+	}
+
+	output.Newline()
+	output.Printf("  %d/%d connections reachable\n", reachable, total)
+	output.Newline()
+}
@@ -0,0 +1,35 @@
+package net
+
+import (
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/netcheck"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// NewNetCmd creates the net command
+func NewNetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "net",
+		Short: "Network and endpoint checks",
+		Long: `Network and endpoint checks for the services you run.
+
+Commands are safe to run from cron or CI: they support JSON output
+and exit with an error when a threshold is crossed.`,
+	}
+
+	// Add subcommands
+	cmd.AddCommand(newCertCheckCmd())
+	cmd.AddCommand(newHTTPCheckCmd())
+	cmd.AddCommand(newMatrixCmd())
+
+	return cmd
+}
+
+// printStatusCounts prints a one-line summary of result statuses
+func printStatusCounts(counts map[string]int) {
+	output.Printf("  %s OK: %d   %s Warning: %d   %s Critical: %d   %s Error: %d\n",
+		output.SuccessStyle.Render(output.IconSuccess), counts[netcheck.StatusOK],
+		output.WarningStyle.Render(output.IconWarning), counts[netcheck.StatusWarning],
+		output.ErrorStyle.Render(output.IconError), counts[netcheck.StatusCritical],
+		output.MutedStyle.Render(output.IconCross), counts[netcheck.StatusError])
+}
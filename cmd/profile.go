@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/logging"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// flagConfigKeys maps connection flags to the config keys they default from
+var flagConfigKeys = map[string]string{
+	"kubeconfig": "kubernetes.kubeconfig",
+	"context":    "kubernetes.context",
+	"namespace":  "kubernetes.namespace",
+	"host":       "docker.host",
+}
+
+// applyProfile overlays profiles.<name> onto the top-level config, so a
+// profile can set any key the config file supports (kubernetes.context,
+// gitlab.url, docker.host, ...)
+func applyProfile(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	profile := viper.Sub("profiles." + name)
+	if profile == nil {
+		return fmt.Errorf("profile %q not found in config (define it under profiles.%s)", name, name)
+	}
+
+	// Merged at the config file layer: the profile beats top-level config
+	// but flags the user passes still win, which viper.Set would override
+	if err := viper.MergeConfigMap(profile.AllSettings()); err != nil {
+		return fmt.Errorf("failed to apply profile %q: %w", name, err)
+	}
+	logging.Debug("applied profile", "profile", name, "keys", profile.AllKeys())
+
+	return nil
+}
+
+// applyConfigDefaults fills connection flags the user did not set from the
+// config file (including the active profile)
+func applyConfigDefaults(cmd *cobra.Command) error {
+	for name, key := range flagConfigKeys {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed || !viper.IsSet(key) {
+			continue
+		}
+		if err := flag.Value.Set(viper.GetString(key)); err != nil {
+			return fmt.Errorf("invalid %s in config: %w", key, err)
+		}
+	}
+
+	// The docker client resolves its daemon from the environment
+	if flag := cmd.Flags().Lookup("host"); flag != nil && flag.Value.String() != "" {
+		if err := os.Setenv("DOCKER_HOST", flag.Value.String()); err != nil {
+			return fmt.Errorf("failed to set docker host: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+func TestApplyProfilePrecedence(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("output", "table", "")
+	flags.Bool("verbose", false, "")
+	if err := flags.Parse([]string{"--output", "json"}); err != nil {
+		t.Fatal(err)
+	}
+	_ = viper.BindPFlag("output", flags.Lookup("output"))
+	_ = viper.BindPFlag("verbose", flags.Lookup("verbose"))
+
+	if err := viper.MergeConfigMap(map[string]interface{}{
+		"kubernetes": map[string]interface{}{"context": "dev", "namespace": "default"},
+		"profiles": map[string]interface{}{
+			"prod": map[string]interface{}{
+				"output":     "yaml",
+				"verbose":    true,
+				"kubernetes": map[string]interface{}{"context": "prod-eu"},
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := applyProfile("prod"); err != nil {
+		t.Fatalf("applyProfile: %v", err)
+	}
+
+	tests := []struct {
+		key  string
+		want interface{}
+	}{
+		{"output", "json"},                  // explicit flag beats the profile
+		{"verbose", true},                   // unset flag falls back to the profile
+		{"kubernetes.context", "prod-eu"},   // profile beats top-level config
+		{"kubernetes.namespace", "default"}, // keys the profile omits are kept
+	}
+	for _, tt := range tests {
+		if got := viper.Get(tt.key); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+
+	if err := applyProfile("missing"); err == nil {
+		t.Error("applyProfile accepted an undefined profile")
+	}
+}
@@ -1,12 +1,32 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/SiavashBeheshti/devops-toolkit/cmd/audit"
+	"github.com/SiavashBeheshti/devops-toolkit/cmd/aws"
+	"github.com/SiavashBeheshti/devops-toolkit/cmd/bundle"
 	"github.com/SiavashBeheshti/devops-toolkit/cmd/compliance"
+	"github.com/SiavashBeheshti/devops-toolkit/cmd/config"
 	"github.com/SiavashBeheshti/devops-toolkit/cmd/docker"
 	"github.com/SiavashBeheshti/devops-toolkit/cmd/gitlab"
+	"github.com/SiavashBeheshti/devops-toolkit/cmd/helm"
 	"github.com/SiavashBeheshti/devops-toolkit/cmd/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/cmd/logs"
+	"github.com/SiavashBeheshti/devops-toolkit/cmd/monitor"
+	"github.com/SiavashBeheshti/devops-toolkit/cmd/net"
+	"github.com/SiavashBeheshti/devops-toolkit/cmd/tf"
+	auditlog "github.com/SiavashBeheshti/devops-toolkit/pkg/audit"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/logging"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/notify"
 	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -15,6 +35,9 @@ import (
 var (
 	cfgFile string
 	version = "0.1.0"
+
+	// cancelTimeout releases the --timeout context once the command returns
+	cancelTimeout context.CancelFunc = func() {}
 )
 
 // rootCmd represents the base command
@@ -33,40 +56,184 @@ Examples:
   devops-toolkit k8s health          Check Kubernetes cluster health
   devops-toolkit docker stats        Show container statistics
   devops-toolkit gitlab pipelines    List GitLab pipelines
-  devops-toolkit compliance check    Run compliance checks`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+  devops-toolkit compliance check    Run compliance checks
+
+Exit codes:
+  0    success
+  1    unclassified error
+  2    invalid flags, config or credentials
+  3    cluster, daemon or API unreachable
+  4    checks ran and found failures
+  5    partial failure (some items failed)
+  124  timed out (--timeout)
+  130  interrupted`,
+	SilenceErrors: true,
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		return exitcode.ConfigError(output.CheckTableQuery())
+	},
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := applyProfile(viper.GetString("profile")); err != nil {
+			return exitcode.ConfigError(err)
+		}
+		if err := applyConfigDefaults(cmd); err != nil {
+			return exitcode.ConfigError(err)
+		}
+
+		// Flag wins over the config file default
+		outputFormat := viper.GetString("output")
+		if !cmd.Flags().Changed("output") && viper.IsSet("defaults.output") {
+			outputFormat = viper.GetString("defaults.output")
+		}
+		if err := output.SetFormat(outputFormat); err != nil {
+			return exitcode.ConfigError(err)
+		}
+
+		var theme output.ThemeConfig
+		if err := viper.UnmarshalKey("theme", &theme); err != nil {
+			return exitcode.ConfigError(fmt.Errorf("failed to parse theme config: %w", err))
+		}
+		if err := output.ApplyTheme(theme); err != nil {
+			return exitcode.ConfigError(err)
+		}
+		output.ConfigureColor(viper.GetBool("no-color"))
+		output.SetNoTrunc(viper.GetBool("no-trunc"))
+		if err := format.Configure(viper.GetBool("utc"), viper.GetBool("absolute-time"), viper.GetString("defaults.size_units")); err != nil {
+			return exitcode.ConfigError(err)
+		}
+
+		// Commands with their own --sort-by or --filter shadow the global flags
+		sortBy, _ := cmd.Root().PersistentFlags().GetString("sort-by")
+		filters, _ := cmd.Root().PersistentFlags().GetStringArray("filter")
+		if err := output.SetTableQuery(sortBy, filters); err != nil {
+			return exitcode.ConfigError(err)
+		}
+
+		auditlog.SetPath(viper.GetString("audit.file"))
+
+		var notifyConfig notify.Config
+		if err := viper.UnmarshalKey("notify", &notifyConfig); err != nil {
+			return exitcode.ConfigError(fmt.Errorf("failed to parse notify config: %w", err))
+		}
+		notify.Configure(notifyConfig)
+
+		logFile, _ := cmd.Flags().GetString("log-file")
+		if err := logging.Setup(viper.GetBool("verbose"), logFile); err != nil {
+			return exitcode.ConfigError(err)
+		}
+		logging.Debug("starting command", "command", cmd.CommandPath(), "version", version, "config", viper.ConfigFileUsed())
+
+		if timeout := viper.GetDuration("timeout"); timeout > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			cancelTimeout = cancel
+			cmd.SetContext(ctx)
+		}
+
 		// Show banner only for root command without subcommands
 		if cmd.Name() == "devops-toolkit" && len(args) == 0 {
 			output.Banner("DevOps Toolkit", "v"+version, "A powerful CLI for DevOps operations")
 		}
+		return nil
 	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, cancel := context.WithCancel(context.Background())
+	handleSignals(cancel)
+
+	err := rootCmd.ExecuteContext(ctx)
+	cancelTimeout()
+	cancel()
+	output.StopSpinner()
+
 	if err != nil {
-		os.Exit(1)
+		coded := exitcode.Classify(err)
+		reportError(coded)
+		os.Exit(coded.Code)
 	}
 }
 
+// reportError prints the failure to stderr, as a JSON object carrying the exit
+// code and failure class when structured output is selected
+func reportError(err *exitcode.Error) {
+	if output.IsStructured() {
+		_ = json.NewEncoder(os.Stderr).Encode(map[string]interface{}{
+			"error": map[string]interface{}{
+				"code":    err.Code,
+				"class":   err.Class(),
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, "Error:", err)
+	if err.Code == exitcode.Timeout && viper.GetDuration("timeout") > 0 {
+		output.Error(fmt.Sprintf("Timed out after %s (raise --timeout)", viper.GetDuration("timeout")))
+	}
+}
+
+// handleSignals cancels the command context on the first SIGINT or SIGTERM so
+// commands can stop cleanly, and exits on the second for calls that ignore it
+func handleSignals(cancel context.CancelFunc) {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		cancel()
+		<-signals
+		output.StopSpinner()
+		os.Exit(130)
+	}()
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.devops-toolkit.yaml)")
-	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
-	rootCmd.PersistentFlags().String("output", "table", "output format (table, json, yaml)")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output (debug logs of API calls and config resolution on stderr)")
+	rootCmd.PersistentFlags().String("log-file", "", "append JSON debug logs to this file")
+	rootCmd.PersistentFlags().String("output", "table", "output format (table, json, yaml, custom-columns=..., go-template=...)")
+	rootCmd.PersistentFlags().String("profile", "", "named config profile to use (or set DEVOPS_PROFILE)")
+	rootCmd.PersistentFlags().Bool("no-color", false, "disable colors, spinners and progress bars (also NO_COLOR)")
+	rootCmd.PersistentFlags().String("sort-by", "", "sort table rows by this column, prefix with - for descending (e.g. -restarts)")
+	rootCmd.PersistentFlags().StringArray("filter", nil, "only show table rows matching column=value, column!=value, column>value or column<value (repeatable)")
+	rootCmd.PersistentFlags().Bool("utc", false, "show timestamps in UTC instead of local time")
+	rootCmd.PersistentFlags().Bool("no-trunc", false, "show long values in full, wrapping table cells to the terminal width instead of cutting them")
+	rootCmd.PersistentFlags().Bool("absolute-time", false, "show timestamps instead of relative ages like 5m or 3d")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "cancel the command after this long, e.g. 30s or 5m (default no timeout)")
+
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return exitcode.ConfigError(err)
+	})
 
 	// Bind flags to viper
 	_ = viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	_ = viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+	_ = viper.BindPFlag("no-color", rootCmd.PersistentFlags().Lookup("no-color"))
+	_ = viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
+	_ = viper.BindPFlag("timeout", rootCmd.PersistentFlags().Lookup("timeout"))
+	_ = viper.BindPFlag("utc", rootCmd.PersistentFlags().Lookup("utc"))
+	_ = viper.BindPFlag("absolute-time", rootCmd.PersistentFlags().Lookup("absolute-time"))
+	_ = viper.BindPFlag("no-trunc", rootCmd.PersistentFlags().Lookup("no-trunc"))
+	_ = rootCmd.RegisterFlagCompletionFunc("output", completion.OutputFormatCompletion)
+	_ = rootCmd.RegisterFlagCompletionFunc("profile", completion.ProfileCompletion)
 
 	// Add subcommands
 	rootCmd.AddCommand(k8s.NewK8sCmd())
+	rootCmd.AddCommand(helm.NewHelmCmd())
 	rootCmd.AddCommand(docker.NewDockerCmd())
 	rootCmd.AddCommand(gitlab.NewGitLabCmd())
+	rootCmd.AddCommand(tf.NewTfCmd())
+	rootCmd.AddCommand(aws.NewAWSCmd())
+	rootCmd.AddCommand(logs.NewLogsCmd())
+	rootCmd.AddCommand(monitor.NewMonitorCmd())
+	rootCmd.AddCommand(net.NewNetCmd())
 	rootCmd.AddCommand(compliance.NewComplianceCmd())
+	rootCmd.AddCommand(audit.NewAuditCmd())
+	rootCmd.AddCommand(bundle.NewBundleCmd(version))
+	rootCmd.AddCommand(config.NewConfigCmd())
 	rootCmd.AddCommand(newCompletionCmd())
 	rootCmd.AddCommand(versionCmd)
 }
@@ -0,0 +1,147 @@
+package tf
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/terraform"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newDriftCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drift [PLAN]",
+		Short: "Report resources that drifted from state",
+		Long: `Report resources changed or deleted outside of terraform.
+
+Without PLAN, runs 'terraform plan -refresh-only' in --dir (read-only: the
+state is neither locked nor written). PLAN may instead be the JSON or
+binary output of an existing refresh-only plan.`,
+		Example: `  devops-toolkit tf drift --dir infra/prod
+  devops-toolkit tf drift refresh.json
+  devops-toolkit tf drift --dir infra/prod --fail-on-drift`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runDrift,
+	}
+
+	cmd.Flags().String("dir", ".", "Terraform working directory")
+	cmd.Flags().Bool("fail-on-drift", false, "Exit with an error when drift is found")
+
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("dir", completion.DirectoryCompletion)
+
+	return cmd
+}
+
+func runDrift(cmd *cobra.Command, args []string) error {
+	dir, _ := cmd.Flags().GetString("dir")
+	failOnDrift, _ := cmd.Flags().GetBool("fail-on-drift")
+
+	var (
+		plan *terraform.Plan
+		err  error
+	)
+	if len(args) == 1 {
+		data, readErr := os.ReadFile(args[0])
+		if readErr != nil {
+			return fmt.Errorf("failed to read plan: %w", readErr)
+		}
+		plan, err = terraform.LoadPlan(cmd.Context(), data, args[0])
+		if err != nil {
+			return err
+		}
+	} else {
+		output.StartSpinner("Refreshing state against real infrastructure...")
+		plan, err = terraform.RefreshOnlyPlan(cmd.Context(), dir)
+		if err != nil {
+			output.SpinnerError("Refresh-only plan failed")
+			return err
+		}
+		output.SpinnerSuccess("Refresh-only plan complete")
+		output.Newline()
+	}
+
+	drifted := terraform.DetectDrift(plan)
+
+	if output.IsStructured() {
+		if err := output.Render(drifted); err != nil {
+			return err
+		}
+		return driftError(drifted, failOnDrift)
+	}
+
+	if len(drifted) == 0 {
+		output.Success("No drift detected. Infrastructure matches the state.")
+		return nil
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Drifted Resources",
+		Headers:    []string{"Address", "Type", "Drift", "Attributes"},
+		ShowBorder: true,
+	})
+
+	for _, d := range drifted {
+		drift := "changed"
+		driftColor := tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold}
+		attrs := fmt.Sprintf("%d", len(d.Attributes))
+		if d.Deleted() {
+			drift = "deleted"
+			driftColor = tablewriter.Colors{tablewriter.FgRedColor, tablewriter.Bold}
+			attrs = "-"
+		}
+
+		row := []string{output.Truncate(d.Address, 60), d.Type, drift, attrs}
+		table.AddColoredRow(row, []tablewriter.Colors{
+			{tablewriter.FgCyanColor},
+			{tablewriter.FgHiBlackColor},
+			driftColor,
+			{},
+		})
+	}
+
+	table.Render()
+
+	// Attribute details
+	output.Newline()
+	output.Print(output.Section("Changed Attributes"))
+	for _, d := range drifted {
+		if len(d.Attributes) == 0 {
+			continue
+		}
+		output.Printf("  %s\n", output.InfoStyle.Render(d.Address))
+		for _, a := range d.Attributes {
+			output.Printf("    %s %s: %s %s %s\n", output.WarningStyle.Render("~"), a.Path,
+				formatAttribute(a.Before), output.MutedStyle.Render(output.IconArrow), formatAttribute(a.After))
+		}
+	}
+
+	output.Newline()
+	output.Warning(fmt.Sprintf("%d resources drifted; run 'terraform apply -refresh-only' to accept or 'terraform apply' to revert", len(drifted)))
+
+	return driftError(drifted, failOnDrift)
+}
+
+func driftError(drifted []terraform.DriftedResource, failOnDrift bool) error {
+	if failOnDrift && len(drifted) > 0 {
+		return exitcode.ChecksFailedf("%d resources drifted from state", len(drifted))
+	}
+	return nil
+}
+
+// formatAttribute renders an attribute value compactly as JSON
+func formatAttribute(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return output.Truncate(string(data), 60)
+}
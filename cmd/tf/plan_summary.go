@@ -0,0 +1,262 @@
+package tf
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/terraform"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newPlanSummaryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan-summary [PLAN]",
+		Short: "Summarize a terraform plan",
+		Long: `Summarize a terraform plan by resource type and highlight risky destroys.
+
+PLAN is either plan JSON ('terraform show -json plan.tfplan') or a binary
+plan file, which is rendered with the terraform binary. Reads stdin when
+PLAN is omitted or "-".
+
+A destroy or replace is risky when the resource type contains one of the
+--risky substrings (databases, buckets, volumes, keys, clusters, ...).
+
+Output formats:
+  table      Console summary (default)
+  markdown   Markdown for merge request comments
+  junit      JUnit XML, one test case per change, risky destroys fail`,
+		Example: `  terraform show -json plan.tfplan | devops-toolkit tf plan-summary
+  devops-toolkit tf plan-summary plan.tfplan
+  devops-toolkit tf plan-summary plan.json -f markdown -o plan.md
+  devops-toolkit tf plan-summary plan.json -f junit -o plan.xml --fail-on-risky`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runPlanSummary,
+	}
+
+	cmd.Flags().StringP("format", "f", "table", "Output format (table, markdown, junit)")
+	cmd.Flags().StringP("output-file", "o", "", "Output file path")
+	cmd.Flags().StringSlice("risky", nil, "Resource type substrings treated as risky to destroy (default from terraform.risky_types)")
+	cmd.Flags().Bool("fail-on-risky", false, "Exit with an error when the plan destroys risky resources")
+
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("format", completion.PlanFormatCompletion)
+
+	return cmd
+}
+
+func runPlanSummary(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	outputFile, _ := cmd.Flags().GetString("output-file")
+	failOnRisky, _ := cmd.Flags().GetBool("fail-on-risky")
+	riskyTypes, _ := cmd.Flags().GetStringSlice("risky")
+	if len(riskyTypes) == 0 {
+		riskyTypes = viper.GetStringSlice("terraform.risky_types")
+	}
+	if len(riskyTypes) == 0 {
+		riskyTypes = terraform.DefaultRiskyTypes
+	}
+
+	var (
+		data []byte
+		path string
+		err  error
+	)
+	if len(args) == 0 || args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		path = args[0]
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	plan, err := terraform.LoadPlan(cmd.Context(), data, path)
+	if err != nil {
+		return err
+	}
+	summary := terraform.Summarize(plan, riskyTypes)
+
+	var report string
+	switch format {
+	case "markdown", "md":
+		report = generateMarkdown(summary)
+	case "junit":
+		report = generateJUnit(summary)
+	default: // table
+		if output.IsStructured() {
+			if err := output.Render(summary); err != nil {
+				return err
+			}
+		} else {
+			displaySummary(summary)
+		}
+		return riskyError(summary, failOnRisky)
+	}
+
+	if outputFile != "" {
+		if err := os.WriteFile(outputFile, []byte(report), 0644); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		output.Successf("Report written to %s", outputFile)
+	} else {
+		fmt.Println(report)
+	}
+
+	return riskyError(summary, failOnRisky)
+}
+
+func riskyError(summary terraform.Summary, failOnRisky bool) error {
+	if failOnRisky && len(summary.Risky) > 0 {
+		return exitcode.ChecksFailedf("plan destroys %d risky resources", len(summary.Risky))
+	}
+	return nil
+}
+
+func displaySummary(summary terraform.Summary) {
+	output.Header("Terraform Plan Summary")
+
+	if !summary.HasChanges() {
+		output.Success("No changes. Infrastructure matches the configuration.")
+		output.Newline()
+		return
+	}
+
+	output.Printf("  %s  %s  %s  %s\n",
+		output.SuccessStyle.Render(fmt.Sprintf("+%d to create", summary.Create)),
+		output.WarningStyle.Render(fmt.Sprintf("~%d to update", summary.Update)),
+		output.ErrorStyle.Render(fmt.Sprintf("-%d to destroy", summary.Delete)),
+		output.ErrorStyle.Render(fmt.Sprintf("±%d to replace", summary.Replace)))
+	if summary.OutputsChanged > 0 {
+		output.Printf("  %s\n", output.MutedStyle.Render(fmt.Sprintf("%d outputs changed", summary.OutputsChanged)))
+	}
+	output.Newline()
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Changes by Resource Type",
+		Headers:    []string{"Type", "Create", "Update", "Destroy", "Replace"},
+		ShowBorder: true,
+	})
+
+	for _, ts := range summary.ByType {
+		row := []string{
+			ts.Type,
+			countCell(ts.Create),
+			countCell(ts.Update),
+			countCell(ts.Delete),
+			countCell(ts.Replace),
+		}
+		table.AddColoredRow(row, []tablewriter.Colors{
+			{tablewriter.FgCyanColor},
+			countColor(ts.Create, tablewriter.FgGreenColor),
+			countColor(ts.Update, tablewriter.FgYellowColor),
+			countColor(ts.Delete, tablewriter.FgRedColor),
+			countColor(ts.Replace, tablewriter.FgRedColor),
+		})
+	}
+
+	table.Render()
+
+	if len(summary.Risky) > 0 {
+		output.Newline()
+		output.Print(output.Section("Risky Destroys"))
+		for _, c := range summary.Risky {
+			detail := c.Action
+			if c.Reason != "" {
+				detail += ", " + c.Reason
+			}
+			output.Printf("  %s %s %s\n",
+				output.ErrorStyle.Render(output.IconError),
+				output.Truncate(c.Address, 80),
+				output.MutedStyle.Render("("+detail+")"))
+		}
+		output.Newline()
+		output.Warning(fmt.Sprintf("%d risky resources will be destroyed; review before applying", len(summary.Risky)))
+	} else {
+		output.Newline()
+		output.Success("No risky destroys")
+	}
+}
+
+func countCell(n int) string {
+	if n == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+func countColor(n int, color int) tablewriter.Colors {
+	if n == 0 {
+		return tablewriter.Colors{tablewriter.FgHiBlackColor}
+	}
+	return tablewriter.Colors{color, tablewriter.Bold}
+}
+
+func generateMarkdown(summary terraform.Summary) string {
+	var sb strings.Builder
+
+	sb.WriteString("### Terraform Plan Summary\n\n")
+	if !summary.HasChanges() {
+		sb.WriteString("No changes. Infrastructure matches the configuration.\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("**%d** to create, **%d** to update, **%d** to destroy, **%d** to replace\n\n",
+		summary.Create, summary.Update, summary.Delete, summary.Replace))
+
+	if len(summary.Risky) > 0 {
+		sb.WriteString(fmt.Sprintf(":warning: **%d risky destroys**\n\n", len(summary.Risky)))
+		for _, c := range summary.Risky {
+			sb.WriteString(fmt.Sprintf("- `%s` (%s)\n", c.Address, c.Action))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("| Type | Create | Update | Destroy | Replace |\n")
+	sb.WriteString("|------|-------:|-------:|--------:|--------:|\n")
+	for _, ts := range summary.ByType {
+		sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s | %s |\n",
+			ts.Type, countCell(ts.Create), countCell(ts.Update), countCell(ts.Delete), countCell(ts.Replace)))
+	}
+
+	sb.WriteString("\n<details><summary>All changes</summary>\n\n")
+	for _, c := range summary.Changes {
+		sb.WriteString(fmt.Sprintf("- %s `%s`\n", c.Action, c.Address))
+	}
+	sb.WriteString("\n</details>\n")
+
+	return sb.String()
+}
+
+func generateJUnit(summary terraform.Summary) string {
+	// JUnit XML format for CI integration
+	var sb strings.Builder
+
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	sb.WriteString(fmt.Sprintf(`<testsuites name="Terraform Plan" tests="%d" failures="%d" time="0">`+"\n",
+		len(summary.Changes), len(summary.Risky)))
+	sb.WriteString(fmt.Sprintf(`  <testsuite name="terraform-plan" tests="%d" failures="%d">`+"\n",
+		len(summary.Changes), len(summary.Risky)))
+
+	for _, c := range summary.Changes {
+		sb.WriteString(fmt.Sprintf(`    <testcase name="%s %s" classname="%s">`+"\n",
+			html.EscapeString(c.Action), html.EscapeString(c.Address), html.EscapeString(c.Type)))
+		if c.Risky {
+			msg := html.EscapeString(fmt.Sprintf("%s: %s of a stateful resource", c.Address, c.Action))
+			sb.WriteString(fmt.Sprintf(`      <failure message="%s" type="risky-destroy">%s</failure>`+"\n", msg, msg))
+		}
+		sb.WriteString("    </testcase>\n")
+	}
+
+	sb.WriteString("  </testsuite>\n")
+	sb.WriteString("</testsuites>")
+	return sb.String()
+}
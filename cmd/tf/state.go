@@ -0,0 +1,225 @@
+package tf
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/completion"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/terraform"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// sensitiveKeys are attribute name fragments masked by state show
+var sensitiveKeys = []string{"password", "secret", "token", "private_key", "access_key", "certificate_key"}
+
+func newStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Inspect terraform state",
+		Long: `Inspect terraform state.
+
+Reads state with 'terraform show -json' in --dir, or from --file, which may
+be 'terraform show -json' output or a .tfstate file (e.g. from
+'terraform state pull').`,
+	}
+
+	cmd.AddCommand(newStateListCmd())
+	cmd.AddCommand(newStateShowCmd())
+
+	cmd.PersistentFlags().String("dir", ".", "Terraform working directory")
+	cmd.PersistentFlags().String("file", "", "Read state from a JSON file instead of running terraform")
+
+	// Register flag completions
+	_ = cmd.RegisterFlagCompletionFunc("dir", completion.DirectoryCompletion)
+
+	return cmd
+}
+
+func newStateListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List resources in state",
+		Example: `  devops-toolkit tf state ls --dir infra/prod
+  devops-toolkit tf state ls --type aws_s3_bucket
+  devops-toolkit tf state ls --file terraform.tfstate --module module.network`,
+		Args: cobra.NoArgs,
+		RunE: runStateList,
+	}
+
+	cmd.Flags().String("type", "", "Only list resources whose type contains this string")
+	cmd.Flags().String("module", "", "Only list resources in this module address")
+	cmd.Flags().Bool("data", false, "Include data sources")
+
+	return cmd
+}
+
+func newStateShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "show ADDRESS",
+		Short:             "Show the attributes of a resource in state",
+		Example:           `  devops-toolkit tf state show aws_db_instance.main --dir infra/prod`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.TerraformAddressCompletion,
+		RunE:              runStateShow,
+	}
+
+	cmd.Flags().Bool("show-sensitive", false, "Show attributes that look like secrets")
+
+	return cmd
+}
+
+func loadState(cmd *cobra.Command) (*terraform.State, error) {
+	file, _ := cmd.Flags().GetString("file")
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read state: %w", err)
+		}
+		return terraform.LoadState(data)
+	}
+
+	dir, _ := cmd.Flags().GetString("dir")
+	output.StartSpinner("Reading terraform state...")
+	state, err := terraform.ReadState(cmd.Context(), dir)
+	if err != nil {
+		output.SpinnerError("Failed to read state")
+		return nil, err
+	}
+	output.StopSpinner()
+	return state, nil
+}
+
+func runStateList(cmd *cobra.Command, args []string) error {
+	typeFilter, _ := cmd.Flags().GetString("type")
+	module, _ := cmd.Flags().GetString("module")
+	includeData, _ := cmd.Flags().GetBool("data")
+
+	state, err := loadState(cmd)
+	if err != nil {
+		return err
+	}
+
+	var resources []terraform.Resource
+	for _, r := range state.Resources {
+		if r.Mode == "data" && !includeData {
+			continue
+		}
+		if typeFilter != "" && !strings.Contains(r.Type, typeFilter) {
+			continue
+		}
+		if module != "" && r.Module != module {
+			continue
+		}
+		r.Values = nil
+		resources = append(resources, r)
+	}
+
+	if output.IsStructured() {
+		return output.Render(resources)
+	}
+
+	if len(resources) == 0 {
+		output.Info("No resources found in state")
+		return nil
+	}
+
+	table := output.NewTable(output.TableConfig{
+		Title:      "Terraform State",
+		Headers:    []string{"Address", "Type", "Provider"},
+		ShowBorder: true,
+	})
+
+	byType := make(map[string]int)
+	for _, r := range resources {
+		byType[r.Type]++
+		table.AddColoredRow([]string{
+			output.Truncate(r.Address, 70),
+			r.Type,
+			r.Provider,
+		}, []tablewriter.Colors{
+			{tablewriter.FgCyanColor},
+			{},
+			{tablewriter.FgHiBlackColor},
+		})
+	}
+
+	table.Render()
+	output.Newline()
+	output.Info(fmt.Sprintf("%d resources of %d types", len(resources), len(byType)))
+
+	return nil
+}
+
+func runStateShow(cmd *cobra.Command, args []string) error {
+	showSensitive, _ := cmd.Flags().GetBool("show-sensitive")
+
+	state, err := loadState(cmd)
+	if err != nil {
+		return err
+	}
+
+	resource, ok := state.Find(args[0])
+	if !ok {
+		return fmt.Errorf("resource %q not found in state", args[0])
+	}
+
+	if !showSensitive {
+		masked := *resource
+		masked.Values = maskSensitive(resource.Values)
+		resource = &masked
+	}
+
+	if output.IsStructured() {
+		return output.Render(resource)
+	}
+
+	output.Header(resource.Address)
+	output.Printf("  %s\n", output.KeyValue("Type", resource.Type))
+	output.Printf("  %s\n", output.KeyValue("Provider", resource.Provider))
+	if resource.Module != "" {
+		output.Printf("  %s\n", output.KeyValue("Module", resource.Module))
+	}
+	output.Newline()
+
+	output.Print(output.Section("Attributes"))
+	keys := make([]string, 0, len(resource.Values))
+	for k := range resource.Values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := resource.Values[k]
+		if v == nil {
+			continue
+		}
+		output.Printf("  %s\n", output.KeyValue(k, formatAttribute(v)))
+	}
+	output.Newline()
+
+	return nil
+}
+
+// maskSensitive returns a copy of values with secret-looking attributes hidden
+func maskSensitive(values map[string]interface{}) map[string]interface{} {
+	masked := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		masked[k] = v
+		if v == nil || v == "" {
+			continue
+		}
+		lower := strings.ToLower(k)
+		for _, s := range sensitiveKeys {
+			if strings.Contains(lower, s) {
+				masked[k] = "(sensitive)"
+				break
+			}
+		}
+	}
+	return masked
+}
@@ -0,0 +1,25 @@
+package tf
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewTfCmd creates the tf command
+func NewTfCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "tf",
+		Aliases: []string{"terraform"},
+		Short:   "Terraform operations",
+		Long: `Terraform plan review and state helpers.
+
+Works on the JSON produced by 'terraform show -json', so it fits into
+existing CI pipelines without extra credentials.`,
+	}
+
+	// Add subcommands
+	cmd.AddCommand(newPlanSummaryCmd())
+	cmd.AddCommand(newDriftCmd())
+	cmd.AddCommand(newStateCmd())
+
+	return cmd
+}
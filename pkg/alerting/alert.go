@@ -0,0 +1,124 @@
+package alerting
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/notify"
+)
+
+// Alert is a rule that currently fires for a resource
+type Alert struct {
+	Rule      string    `json:"rule"`
+	Type      string    `json:"type"`
+	Severity  string    `json:"severity"`
+	Resource  string    `json:"resource"`
+	Message   string    `json:"message"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Link      string    `json:"link,omitempty"`
+	Since     time.Time `json:"since"`
+}
+
+// Key identifies the alert across evaluations
+func (a Alert) Key() string {
+	return a.Rule + "/" + a.Resource
+}
+
+// Notification converts the alert into a notification with the given status
+func (a Alert) Notification(status string) notify.Message {
+	label := "FIRING"
+	if status == notify.StatusResolved {
+		label = "RESOLVED"
+	}
+
+	fields := []notify.Field{
+		{Name: "Rule", Value: a.Rule},
+		{Name: "Resource", Value: a.Resource},
+		{Name: "Severity", Value: a.Severity},
+	}
+	if a.Threshold > 0 {
+		fields = append(fields,
+			notify.Field{Name: "Value", Value: fmt.Sprintf("%.1f", a.Value)},
+			notify.Field{Name: "Threshold", Value: fmt.Sprintf("%.1f", a.Threshold)},
+		)
+	}
+	if status == notify.StatusResolved {
+		fields = append(fields, notify.Field{Name: "Duration", Value: time.Since(a.Since).Round(time.Second).String()})
+	}
+
+	return notify.Message{
+		Title:    fmt.Sprintf("[%s] %s: %s", label, a.Rule, a.Resource),
+		Text:     a.Message,
+		Status:   status,
+		Severity: a.Severity,
+		Link:     a.Link,
+		Fields:   fields,
+	}
+}
+
+// Tracker remembers firing alerts between evaluations so only changes are notified
+type Tracker struct {
+	active map[string]Alert
+}
+
+// NewTracker creates an empty tracker
+func NewTracker() *Tracker {
+	return &Tracker{active: make(map[string]Alert)}
+}
+
+// Update records an evaluation and returns newly firing and resolved alerts.
+// Alerts of rules in errored are kept as they are, since their state is unknown.
+func (t *Tracker) Update(alerts []Alert, errored []string) (fired, resolved []Alert) {
+	skip := make(map[string]bool, len(errored))
+	for _, name := range errored {
+		skip[name] = true
+	}
+
+	current := make(map[string]bool, len(alerts))
+	for _, a := range alerts {
+		current[a.Key()] = true
+		if prev, ok := t.active[a.Key()]; ok {
+			a.Since = prev.Since
+			t.active[a.Key()] = a
+			continue
+		}
+		t.active[a.Key()] = a
+		fired = append(fired, a)
+	}
+
+	for key, a := range t.active {
+		if current[key] || skip[a.Rule] {
+			continue
+		}
+		delete(t.active, key)
+		resolved = append(resolved, a)
+	}
+	sortAlerts(resolved)
+
+	return fired, resolved
+}
+
+// Active returns the alerts currently firing
+func (t *Tracker) Active() []Alert {
+	alerts := make([]Alert, 0, len(t.active))
+	for _, a := range t.active {
+		alerts = append(alerts, a)
+	}
+	sortAlerts(alerts)
+	return alerts
+}
+
+// sortAlerts orders alerts by severity, then rule and resource
+func sortAlerts(alerts []Alert) {
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Severity != alerts[j].Severity {
+			return alerts[i].Severity == SeverityCritical
+		}
+		if alerts[i].Rule != alerts[j].Rule {
+			return alerts[i].Rule < alerts[j].Rule
+		}
+		return alerts[i].Resource < alerts[j].Resource
+	})
+}
@@ -0,0 +1,282 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/compliance"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/netcheck"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/prometheus"
+)
+
+// Engine evaluates rules; clients a rule does not need may be nil
+type Engine struct {
+	K8s        *k8s.Client
+	GitLab     *gitlabclient.Client
+	Prometheus *prometheus.Client
+
+	// DefaultProject is used by pipeline rules without a project
+	DefaultProject string
+}
+
+// RuleError is a rule that could not be evaluated
+type RuleError struct {
+	Rule string
+	Err  error
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("rule %s: %v", e.Rule, e.Err)
+}
+
+// evaluation caches cluster lookups shared by rules within one run
+type evaluation struct {
+	engine *Engine
+	nodes  []k8s.NodeInfo
+	pods   map[string][]k8s.PodInfo
+}
+
+// Evaluate runs every rule and returns the firing alerts
+func (e *Engine) Evaluate(ctx context.Context, rules []Rule) ([]Alert, []*RuleError) {
+	ev := &evaluation{engine: e, pods: make(map[string][]k8s.PodInfo)}
+	now := time.Now()
+
+	var alerts []Alert
+	var errs []*RuleError
+	for _, rule := range rules {
+		fired, err := ev.evaluate(ctx, rule)
+		if err != nil {
+			errs = append(errs, &RuleError{Rule: rule.Name, Err: err})
+			continue
+		}
+		for i := range fired {
+			fired[i].Rule = rule.Name
+			fired[i].Type = rule.Type
+			fired[i].Severity = rule.Severity
+			fired[i].Threshold = rule.Threshold
+			fired[i].Since = now
+		}
+		alerts = append(alerts, fired...)
+	}
+
+	sortAlerts(alerts)
+	return alerts, errs
+}
+
+func (ev *evaluation) evaluate(ctx context.Context, rule Rule) ([]Alert, error) {
+	if rule.NeedsKubernetes() && ev.engine.K8s == nil {
+		return nil, fmt.Errorf("kubernetes client not configured")
+	}
+	if rule.NeedsGitLab() && ev.engine.GitLab == nil {
+		return nil, fmt.Errorf("GitLab client not configured (set GITLAB_TOKEN)")
+	}
+
+	switch rule.Type {
+	case TypeNodeNotReady:
+		return ev.nodeNotReady(ctx)
+	case TypePodRestarts:
+		return ev.podRestarts(ctx, rule)
+	case TypeCPUPercent:
+		return ev.cpuPercent(ctx, rule)
+	case TypePipelineFailed:
+		return ev.pipelineFailed(ctx, rule)
+	case TypeComplianceScore:
+		return ev.complianceScore(ctx, rule)
+	case TypeHTTPCheck:
+		return ev.httpCheck(ctx, rule)
+	default:
+		return nil, fmt.Errorf("unknown type %q", rule.Type)
+	}
+}
+
+func (ev *evaluation) listNodes(ctx context.Context) ([]k8s.NodeInfo, error) {
+	if ev.nodes == nil {
+		nodes, err := ev.engine.K8s.ListNodes(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list nodes: %w", err)
+		}
+		ev.nodes = nodes
+	}
+	return ev.nodes, nil
+}
+
+func (ev *evaluation) nodeNotReady(ctx context.Context) ([]Alert, error) {
+	nodes, err := ev.listNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []Alert
+	for _, node := range nodes {
+		if node.Ready {
+			continue
+		}
+		alerts = append(alerts, Alert{
+			Resource: "node/" + node.Name,
+			Message:  fmt.Sprintf("Node %s is not ready", node.Name),
+		})
+	}
+	return alerts, nil
+}
+
+func (ev *evaluation) podRestarts(ctx context.Context, rule Rule) ([]Alert, error) {
+	pods, ok := ev.pods[rule.Namespace]
+	if !ok {
+		var err error
+		pods, err = ev.engine.K8s.ListPods(ctx, rule.Namespace, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+		ev.pods[rule.Namespace] = pods
+	}
+
+	var alerts []Alert
+	for _, pod := range pods {
+		if float64(pod.Restarts) <= rule.Threshold {
+			continue
+		}
+		alerts = append(alerts, Alert{
+			Resource: fmt.Sprintf("pod/%s/%s", pod.Namespace, pod.Name),
+			Message:  fmt.Sprintf("Pod %s/%s restarted %d times (%s)", pod.Namespace, pod.Name, pod.Restarts, pod.Status),
+			Value:    float64(pod.Restarts),
+		})
+	}
+	return alerts, nil
+}
+
+// cpuPercent uses node-exporter metrics when Prometheus is configured, otherwise cluster CPU requests
+func (ev *evaluation) cpuPercent(ctx context.Context, rule Rule) ([]Alert, error) {
+	var alerts []Alert
+
+	if ev.engine.Prometheus != nil {
+		usage, err := ev.engine.Prometheus.NodeCPUPercent(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for instance, pct := range usage {
+			if pct <= rule.Threshold {
+				continue
+			}
+			alerts = append(alerts, Alert{
+				Resource: "node/" + instance,
+				Message:  fmt.Sprintf("CPU usage on %s is %.0f%%", instance, pct),
+				Value:    pct,
+			})
+		}
+		return alerts, nil
+	}
+
+	util, err := ev.engine.K8s.GetResourceUtilization(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource utilization: %w", err)
+	}
+	if util.CPUCapacity == 0 {
+		return nil, nil
+	}
+	pct := float64(util.CPUUsed) / float64(util.CPUCapacity) * 100
+	if pct > rule.Threshold {
+		alerts = append(alerts, Alert{
+			Resource: "cluster",
+			Message:  fmt.Sprintf("Cluster CPU requests are at %.0f%% of capacity", pct),
+			Value:    pct,
+		})
+	}
+	return alerts, nil
+}
+
+func (ev *evaluation) pipelineFailed(ctx context.Context, rule Rule) ([]Alert, error) {
+	project := rule.Project
+	if project == "" {
+		project = ev.engine.DefaultProject
+	}
+	if project == "" {
+		return nil, fmt.Errorf("project is required (set it on the rule or GITLAB_PROJECT)")
+	}
+
+	pipelines, err := ev.engine.GitLab.ListPipelines(ctx, project, gitlabclient.PipelineFilter{Ref: rule.Ref, Limit: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipelines: %w", err)
+	}
+	if len(pipelines) == 0 || pipelines[0].Status != "failed" {
+		return nil, nil
+	}
+
+	pl := pipelines[0]
+	return []Alert{{
+		Resource: fmt.Sprintf("%s@%s", project, rule.Ref),
+		Message:  fmt.Sprintf("Pipeline #%d on %s failed", pl.ID, rule.Ref),
+		Value:    float64(pl.ID),
+		Link:     pl.WebURL,
+	}}, nil
+}
+
+func (ev *evaluation) complianceScore(ctx context.Context, rule Rule) ([]Alert, error) {
+	opts := compliance.CheckOptions{Namespace: rule.Namespace, Path: rule.Path}
+	if opts.Path == "" {
+		opts.Path = "."
+	}
+
+	var results []compliance.CheckResult
+	var err error
+	switch rule.Target {
+	case "docker":
+		results, err = compliance.NewDockerChecker(opts).Run(ctx)
+	case "files":
+		results, err = compliance.NewFileChecker(opts).Run(ctx)
+	default:
+		results, err = compliance.NewK8sChecker(ev.engine.K8s, opts).Run(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("compliance check failed: %w", err)
+	}
+
+	var passed, counted int
+	for _, r := range results {
+		if r.Status == compliance.StatusSkipped {
+			continue
+		}
+		counted++
+		if r.Status == compliance.StatusPassed {
+			passed++
+		}
+	}
+	if counted == 0 {
+		return nil, nil
+	}
+
+	score := float64(passed) / float64(counted) * 100
+	if score >= rule.Threshold {
+		return nil, nil
+	}
+	return []Alert{{
+		Resource: "compliance/" + rule.Target,
+		Message:  fmt.Sprintf("Compliance score for %s is %.1f%% (%d/%d checks passed)", rule.Target, score, passed, counted),
+		Value:    score,
+	}}, nil
+}
+
+// httpCheckTimeout bounds a single http_check probe
+const httpCheckTimeout = 10 * time.Second
+
+func (ev *evaluation) httpCheck(ctx context.Context, rule Rule) ([]Alert, error) {
+	result := netcheck.CheckHTTP(ctx, netcheck.HTTPCheck{
+		Name:         rule.Name,
+		URL:          rule.URL,
+		ExpectStatus: rule.ExpectStatus,
+		Contains:     rule.Contains,
+		MaxLatency:   time.Duration(rule.Threshold) * time.Millisecond,
+	}, httpCheckTimeout)
+	if result.Status == netcheck.StatusOK {
+		return nil, nil
+	}
+
+	return []Alert{{
+		Resource: rule.URL,
+		Message:  fmt.Sprintf("%s: %s", rule.URL, result.Summary()),
+		Value:    float64(result.Latency.Milliseconds()),
+		Link:     rule.URL,
+	}}, nil
+}
@@ -0,0 +1,161 @@
+package alerting
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/notify"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule types
+const (
+	TypeNodeNotReady    = "node_not_ready"
+	TypePodRestarts     = "pod_restarts"
+	TypeCPUPercent      = "cpu_percent"
+	TypePipelineFailed  = "pipeline_failed"
+	TypeComplianceScore = "compliance_score"
+	TypeHTTPCheck       = "http_check"
+)
+
+// Severities
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// RuleTypes lists the supported rule types
+var RuleTypes = []string{
+	TypeNodeNotReady,
+	TypePodRestarts,
+	TypeCPUPercent,
+	TypePipelineFailed,
+	TypeComplianceScore,
+	TypeHTTPCheck,
+}
+
+// DefaultInterval is the evaluation interval in daemon mode
+const DefaultInterval = time.Minute
+
+// Rule is a threshold evaluated against the cluster, CI or compliance results
+type Rule struct {
+	Name      string  `yaml:"name" json:"name"`
+	Type      string  `yaml:"type" json:"type"`
+	Threshold float64 `yaml:"threshold" json:"threshold"`
+	Severity  string  `yaml:"severity" json:"severity"`
+
+	// Namespace limits pod_restarts (and the k8s compliance target)
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	// Project and Ref select the pipeline for pipeline_failed
+	Project string `yaml:"project,omitempty" json:"project,omitempty"`
+	Ref     string `yaml:"ref,omitempty" json:"ref,omitempty"`
+	// Target (k8s, docker, files) and Path select compliance_score checks
+	Target string `yaml:"target,omitempty" json:"target,omitempty"`
+	Path   string `yaml:"path,omitempty" json:"path,omitempty"`
+	// URL, ExpectStatus and Contains configure http_check; Threshold is its max latency in ms
+	URL          string `yaml:"url,omitempty" json:"url,omitempty"`
+	ExpectStatus []int  `yaml:"expect_status,omitempty" json:"expect_status,omitempty"`
+	Contains     string `yaml:"contains,omitempty" json:"contains,omitempty"`
+}
+
+// Config is a rules file
+type Config struct {
+	Interval time.Duration       `yaml:"interval"`
+	Rules    []Rule              `yaml:"rules"`
+	Sinks    []notify.SinkConfig `yaml:"sinks"`
+}
+
+// LoadConfig reads and validates a rules file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse rules file %s: %w", path, err)
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+
+	seen := make(map[string]bool)
+	for i := range cfg.Rules {
+		r := &cfg.Rules[i]
+		if err := r.normalize(); err != nil {
+			return nil, fmt.Errorf("%s: rule %d: %w", path, i+1, err)
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("%s: duplicate rule name %q", path, r.Name)
+		}
+		seen[r.Name] = true
+	}
+
+	return &cfg, nil
+}
+
+// normalize validates the rule and fills in defaults
+func (r *Rule) normalize() error {
+	if r.Name == "" {
+		r.Name = r.Type
+	}
+	if r.Severity == "" {
+		r.Severity = SeverityWarning
+	}
+	if r.Severity != SeverityWarning && r.Severity != SeverityCritical {
+		return fmt.Errorf("invalid severity %q (valid: warning, critical)", r.Severity)
+	}
+
+	switch r.Type {
+	case TypeNodeNotReady:
+	case TypePodRestarts:
+		if r.Threshold <= 0 {
+			r.Threshold = 5
+		}
+	case TypeCPUPercent:
+		if r.Threshold <= 0 || r.Threshold > 100 {
+			return fmt.Errorf("cpu_percent needs a threshold between 1 and 100")
+		}
+	case TypePipelineFailed:
+		if r.Ref == "" {
+			r.Ref = "main"
+		}
+	case TypeComplianceScore:
+		if r.Threshold <= 0 || r.Threshold > 100 {
+			return fmt.Errorf("compliance_score needs a threshold between 1 and 100")
+		}
+		if r.Target == "" {
+			r.Target = "k8s"
+		}
+		if r.Target != "k8s" && r.Target != "docker" && r.Target != "files" {
+			return fmt.Errorf("invalid compliance target %q (valid: k8s, docker, files)", r.Target)
+		}
+	case TypeHTTPCheck:
+		if r.URL == "" {
+			return fmt.Errorf("http_check needs a url")
+		}
+	case "":
+		return fmt.Errorf("type is required")
+	default:
+		return fmt.Errorf("unknown type %q", r.Type)
+	}
+	return nil
+}
+
+// NeedsKubernetes reports whether the rule reads from the cluster
+func (r Rule) NeedsKubernetes() bool {
+	switch r.Type {
+	case TypeNodeNotReady, TypePodRestarts, TypeCPUPercent:
+		return true
+	case TypeComplianceScore:
+		return r.Target != "docker" && r.Target != "files"
+	}
+	return false
+}
+
+// NeedsGitLab reports whether the rule reads from GitLab
+func (r Rule) NeedsGitLab() bool {
+	return r.Type == TypePipelineFailed
+}
@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Results recorded for an operation
+const (
+	ResultDryRun  = "dry-run"
+	ResultSuccess = "success"
+	ResultPartial = "partial"
+	ResultFailed  = "failed"
+)
+
+// Entry is one destructive operation in the audit log
+type Entry struct {
+	Time      time.Time `json:"time"`
+	User      string    `json:"user"`
+	Host      string    `json:"host"`
+	Command   string    `json:"command"`
+	Action    string    `json:"action"`
+	Target    string    `json:"target"`
+	Resources []string  `json:"resources"`
+	DryRun    bool      `json:"dry_run"`
+	Result    string    `json:"result"`
+	Error     string    `json:"error,omitempty"`
+}
+
+var path string
+
+// SetPath overrides the audit log location (default ~/.devops-toolkit/audit.jsonl)
+func SetPath(p string) {
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			p = filepath.Join(home, rest)
+		}
+	}
+	path = p
+}
+
+// Path returns the audit log location
+func Path() string {
+	if path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "devops-toolkit-audit.jsonl")
+	}
+	return filepath.Join(home, ".devops-toolkit", "audit.jsonl")
+}
+
+// Result derives the outcome of an operation that completed done of total items
+func Result(dryRun bool, done, total int, err error) string {
+	switch {
+	case dryRun:
+		return ResultDryRun
+	case err == nil:
+		return ResultSuccess
+	case done > 0 && done < total:
+		return ResultPartial
+	default:
+		return ResultFailed
+	}
+}
+
+// Record appends an entry to the audit log, filling in the time, user and host
+func Record(e Entry, opErr error) error {
+	e.Time = time.Now().UTC()
+	if u, err := user.Current(); err == nil {
+		e.User = u.Username
+	}
+	e.Host, _ = os.Hostname()
+	if opErr != nil {
+		e.Error = opErr.Error()
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	p := Path()
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Read returns all entries in the audit log, oldest first.
+// A missing log is not an error; malformed lines are skipped.
+func Read() ([]Entry, error) {
+	f, err := os.Open(Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,104 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+)
+
+// ecrAPI is the part of the ECR client the toolkit uses
+type ecrAPI interface {
+	ecr.DescribeRepositoriesAPIClient
+	ecr.DescribeImagesAPIClient
+	GetLifecyclePolicy(ctx context.Context, params *ecr.GetLifecyclePolicyInput, optFns ...func(*ecr.Options)) (*ecr.GetLifecyclePolicyOutput, error)
+	BatchDeleteImage(ctx context.Context, params *ecr.BatchDeleteImageInput, optFns ...func(*ecr.Options)) (*ecr.BatchDeleteImageOutput, error)
+}
+
+// eksAPI is the part of the EKS client the toolkit uses
+type eksAPI interface {
+	eks.ListNodegroupsAPIClient
+	eks.DescribeClusterVersionsAPIClient
+	DescribeCluster(ctx context.Context, params *eks.DescribeClusterInput, optFns ...func(*eks.Options)) (*eks.DescribeClusterOutput, error)
+	DescribeNodegroup(ctx context.Context, params *eks.DescribeNodegroupInput, optFns ...func(*eks.Options)) (*eks.DescribeNodegroupOutput, error)
+}
+
+// s3API is the part of the S3 client the toolkit uses
+type s3API interface {
+	manager.UploadAPIClient
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+}
+
+// secretsManagerAPI is the part of the Secrets Manager client the toolkit uses
+type secretsManagerAPI interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// stsAPI is the part of the STS client the toolkit uses
+type stsAPI interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
+// Client calls AWS through the SDK, using its default credential chain
+// (environment, shared config and SSO profiles, instance and task roles)
+type Client struct {
+	region string
+
+	ecr            ecrAPI
+	eks            eksAPI
+	s3             s3API
+	secretsManager secretsManagerAPI
+	sts            stsAPI
+}
+
+// NewClient creates a client for the given profile and region (empty for SDK defaults)
+func NewClient(profile, region string) (*Client, error) {
+	var opts []func(*config.LoadOptions) error
+	if profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
+	}
+	if region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	return &Client{
+		region:         cfg.Region,
+		ecr:            ecr.NewFromConfig(cfg),
+		eks:            eks.NewFromConfig(cfg),
+		s3:             s3.NewFromConfig(cfg),
+		secretsManager: secretsmanager.NewFromConfig(cfg),
+		sts:            sts.NewFromConfig(cfg),
+	}, nil
+}
+
+// Region returns the resolved region, or "default" when none is configured
+func (c *Client) Region() string {
+	if c.region == "" {
+		return "default"
+	}
+	return c.region
+}
+
+// IsNotFound reports whether err is an AWS "...NotFoundException" or an S3 "NotFound"
+func IsNotFound(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	code := apiErr.ErrorCode()
+	return strings.HasSuffix(code, "NotFoundException") || code == "NotFound" || code == "NoSuchKey"
+}
@@ -0,0 +1,270 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smtypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+// fakeECR serves repositories and images one per page and records deletions
+type fakeECR struct {
+	ecrAPI
+
+	repositories []ecrtypes.Repository
+	images       []ecrtypes.ImageDetail
+	policies     map[string]string
+	batches      [][]string
+}
+
+// page returns the item at the position in token and the token for the next one
+func page(token *string, n int) (int, *string) {
+	i := 0
+	if token != nil {
+		fmt.Sscan(*token, &i)
+	}
+	if i+1 < n {
+		return i, awssdk.String(fmt.Sprint(i + 1))
+	}
+	return i, nil
+}
+
+func (f *fakeECR) DescribeRepositories(ctx context.Context, in *ecr.DescribeRepositoriesInput, _ ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error) {
+	i, next := page(in.NextToken, len(f.repositories))
+	return &ecr.DescribeRepositoriesOutput{Repositories: f.repositories[i : i+1], NextToken: next}, nil
+}
+
+func (f *fakeECR) DescribeImages(ctx context.Context, in *ecr.DescribeImagesInput, _ ...func(*ecr.Options)) (*ecr.DescribeImagesOutput, error) {
+	i, next := page(in.NextToken, len(f.images))
+	return &ecr.DescribeImagesOutput{ImageDetails: f.images[i : i+1], NextToken: next}, nil
+}
+
+func (f *fakeECR) GetLifecyclePolicy(ctx context.Context, in *ecr.GetLifecyclePolicyInput, _ ...func(*ecr.Options)) (*ecr.GetLifecyclePolicyOutput, error) {
+	text, ok := f.policies[*in.RepositoryName]
+	if !ok {
+		return nil, &ecrtypes.LifecyclePolicyNotFoundException{Message: awssdk.String("no policy")}
+	}
+	return &ecr.GetLifecyclePolicyOutput{LifecyclePolicyText: awssdk.String(text)}, nil
+}
+
+func (f *fakeECR) BatchDeleteImage(ctx context.Context, in *ecr.BatchDeleteImageInput, _ ...func(*ecr.Options)) (*ecr.BatchDeleteImageOutput, error) {
+	var batch []string
+	out := &ecr.BatchDeleteImageOutput{}
+	for _, id := range in.ImageIds {
+		digest := *id.ImageDigest
+		batch = append(batch, digest)
+		if digest == "sha256:locked" {
+			out.Failures = append(out.Failures, ecrtypes.ImageFailure{ImageId: &id, FailureReason: awssdk.String("image is referenced by a manifest list")})
+			continue
+		}
+		// Each tag of a deleted image is reported separately
+		out.ImageIds = append(out.ImageIds, id, id)
+	}
+	f.batches = append(f.batches, batch)
+	return out, nil
+}
+
+func TestListRepositories(t *testing.T) {
+	api := &fakeECR{repositories: []ecrtypes.Repository{
+		{RepositoryName: awssdk.String("web"), ImageTagMutability: ecrtypes.ImageTagMutabilityImmutable,
+			ImageScanningConfiguration: &ecrtypes.ImageScanningConfiguration{ScanOnPush: true}},
+		{RepositoryName: awssdk.String("api")},
+	}}
+	c := &Client{ecr: api}
+
+	repos, err := c.ListRepositories(context.Background())
+	if err != nil {
+		t.Fatalf("ListRepositories: %v", err)
+	}
+	if len(repos) != 2 || repos[0].Name != "api" || repos[1].Name != "web" {
+		t.Fatalf("repos = %+v, want api and web from both pages, sorted", repos)
+	}
+	if !repos[1].ScanOnPush || repos[1].TagMutability != "IMMUTABLE" {
+		t.Errorf("web = %+v, want scan on push and IMMUTABLE tags", repos[1])
+	}
+}
+
+func TestListImages(t *testing.T) {
+	pushed := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	api := &fakeECR{images: []ecrtypes.ImageDetail{
+		{ImageDigest: awssdk.String("sha256:old"), ImagePushedAt: awssdk.Time(pushed.AddDate(0, -1, 0))},
+		{
+			ImageDigest:      awssdk.String("sha256:new"),
+			ImageTags:        []string{"v2", "latest"},
+			ImagePushedAt:    awssdk.Time(pushed),
+			ImageSizeInBytes: awssdk.Int64(52428800),
+			ImageScanStatus:  &ecrtypes.ImageScanStatus{Status: ecrtypes.ScanStatusComplete},
+			ImageScanFindingsSummary: &ecrtypes.ImageScanFindingsSummary{
+				FindingSeverityCounts: map[string]int32{"CRITICAL": 2, "HIGH": 5},
+			},
+		},
+	}}
+	c := &Client{ecr: api}
+
+	images, err := c.ListImages(context.Background(), "web")
+	if err != nil {
+		t.Fatalf("ListImages: %v", err)
+	}
+	if len(images) != 2 || images[0].Digest != "sha256:new" {
+		t.Fatalf("images = %+v, want both pages, newest first", images)
+	}
+	img := images[0]
+	if img.Repository != "web" || img.Size != 52428800 || !img.PushedAt.Equal(pushed) || len(img.Tags) != 2 {
+		t.Errorf("image = %+v", img)
+	}
+	if img.ScanStatus != "COMPLETE" || img.Critical() != 2 || img.High() != 5 {
+		t.Errorf("scan = %s, %d critical, %d high, want COMPLETE, 2, 5", img.ScanStatus, img.Critical(), img.High())
+	}
+	if images[1].Findings != nil || !images[1].LastPulledAt.IsZero() {
+		t.Errorf("unscanned image = %+v, want no findings and no pull time", images[1])
+	}
+}
+
+func TestGetLifecyclePolicy(t *testing.T) {
+	c := &Client{ecr: &fakeECR{policies: map[string]string{"web": `{"rules":[]}`}}}
+
+	text, err := c.GetLifecyclePolicy(context.Background(), "web")
+	if err != nil || text != `{"rules":[]}` {
+		t.Errorf("GetLifecyclePolicy(web) = %q, %v", text, err)
+	}
+	text, err = c.GetLifecyclePolicy(context.Background(), "api")
+	if err != nil || text != "" {
+		t.Errorf("GetLifecyclePolicy(api) = %q, %v, want no policy and no error", text, err)
+	}
+}
+
+func TestDeleteImages(t *testing.T) {
+	var digests []string
+	for i := 0; i < 230; i++ {
+		digests = append(digests, fmt.Sprintf("sha256:%03d", i))
+	}
+	digests[150] = "sha256:locked"
+
+	api := &fakeECR{}
+	c := &Client{ecr: api}
+	deleted, err := c.DeleteImages(context.Background(), "web", digests)
+
+	if len(api.batches) != 3 || len(api.batches[0]) != 100 || len(api.batches[2]) != 30 {
+		t.Errorf("got %d batches, want 100, 100 and 30 digests", len(api.batches))
+	}
+	if deleted != 229 {
+		t.Errorf("deleted = %d, want 229 (each image once, however many tags)", deleted)
+	}
+	if err == nil || err.Error() != "failed to delete 1 images: sha256:locked: image is referenced by a manifest list" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+type fakeEKS struct {
+	eksAPI
+	nodegroups map[string]ekstypes.Nodegroup
+}
+
+func (f *fakeEKS) ListNodegroups(ctx context.Context, in *eks.ListNodegroupsInput, _ ...func(*eks.Options)) (*eks.ListNodegroupsOutput, error) {
+	if in.NextToken == nil {
+		return &eks.ListNodegroupsOutput{Nodegroups: []string{"system"}, NextToken: awssdk.String("1")}, nil
+	}
+	return &eks.ListNodegroupsOutput{Nodegroups: []string{"workers"}}, nil
+}
+
+func (f *fakeEKS) DescribeNodegroup(ctx context.Context, in *eks.DescribeNodegroupInput, _ ...func(*eks.Options)) (*eks.DescribeNodegroupOutput, error) {
+	ng := f.nodegroups[*in.NodegroupName]
+	return &eks.DescribeNodegroupOutput{Nodegroup: &ng}, nil
+}
+
+func TestListEKSNodegroups(t *testing.T) {
+	c := &Client{eks: &fakeEKS{nodegroups: map[string]ekstypes.Nodegroup{
+		"system": {NodegroupName: awssdk.String("system"), Version: awssdk.String("1.29"), Status: ekstypes.NodegroupStatusActive,
+			InstanceTypes: []string{"m6i.large"}, ScalingConfig: &ekstypes.NodegroupScalingConfig{DesiredSize: awssdk.Int32(3)}},
+		"workers": {NodegroupName: awssdk.String("workers"), Version: awssdk.String("1.28"), Status: ekstypes.NodegroupStatusUpdating},
+	}}}
+
+	nodegroups, err := c.ListEKSNodegroups(context.Background(), "prod")
+	if err != nil {
+		t.Fatalf("ListEKSNodegroups: %v", err)
+	}
+	if len(nodegroups) != 2 {
+		t.Fatalf("nodegroups = %+v, want both pages", nodegroups)
+	}
+	if ng := nodegroups[0]; ng.Name != "system" || ng.DesiredSize != 3 || ng.Status != "ACTIVE" || ng.InstanceTypes[0] != "m6i.large" {
+		t.Errorf("system = %+v", ng)
+	}
+	if ng := nodegroups[1]; ng.Version != "1.28" || ng.DesiredSize != 0 {
+		t.Errorf("workers = %+v, want version 1.28 and no scaling config", ng)
+	}
+}
+
+type fakeSecretsManager struct {
+	secrets map[string]*string
+}
+
+func (f *fakeSecretsManager) GetSecretValue(ctx context.Context, in *secretsmanager.GetSecretValueInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	value, ok := f.secrets[*in.SecretId]
+	if !ok {
+		return nil, &smtypes.ResourceNotFoundException{Message: awssdk.String("secret not found")}
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: value}, nil
+}
+
+func TestGetSecretValue(t *testing.T) {
+	c := &Client{secretsManager: &fakeSecretsManager{secrets: map[string]*string{
+		"db":     awssdk.String(`{"password":"hunter2"}`),
+		"binary": nil,
+	}}}
+
+	if value, err := c.GetSecretValue(context.Background(), "db"); err != nil || value != `{"password":"hunter2"}` {
+		t.Errorf("GetSecretValue(db) = %q, %v", value, err)
+	}
+	if _, err := c.GetSecretValue(context.Background(), "binary"); err == nil {
+		t.Error("GetSecretValue accepted a secret without a string value")
+	}
+	_, err := c.GetSecretValue(context.Background(), "missing")
+	if !IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false", err)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&ecrtypes.RepositoryNotFoundException{}, true},
+		{fmt.Errorf("wrapped: %w", &ecrtypes.LifecyclePolicyNotFoundException{}), true},
+		{&ecrtypes.InvalidParameterException{}, false},
+		{errors.New("RepositoryNotFoundException"), false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := IsNotFound(tt.err); got != tt.want {
+			t.Errorf("IsNotFound(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestParseS3URI(t *testing.T) {
+	tests := []struct {
+		uri, bucket, key string
+		wantErr          bool
+	}{
+		{"s3://backups/etcd/prod", "backups", "etcd/prod", false},
+		{"s3://backups", "backups", "", false},
+		{"s3:///etcd", "", "", true},
+		{"backups/etcd", "", "", true},
+	}
+	for _, tt := range tests {
+		bucket, key, err := ParseS3URI(tt.uri)
+		if (err != nil) != tt.wantErr || bucket != tt.bucket || key != tt.key {
+			t.Errorf("ParseS3URI(%q) = %q, %q, %v", tt.uri, bucket, key, err)
+		}
+	}
+}
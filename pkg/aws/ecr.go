@@ -0,0 +1,211 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/retention"
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+)
+
+// Repository is an ECR repository
+type Repository struct {
+	Name          string    `json:"name"`
+	URI           string    `json:"uri"`
+	CreatedAt     time.Time `json:"created_at"`
+	ScanOnPush    bool      `json:"scan_on_push"`
+	TagMutability string    `json:"tag_mutability"`
+}
+
+// Image is an ECR image with its scan findings
+type Image struct {
+	retention.Image
+	LastPulledAt time.Time      `json:"last_pulled_at,omitempty"`
+	ScanStatus   string         `json:"scan_status,omitempty"`
+	Findings     map[string]int `json:"findings,omitempty"`
+}
+
+// Critical returns the number of critical findings
+func (i Image) Critical() int {
+	return i.Findings["CRITICAL"]
+}
+
+// High returns the number of high findings
+func (i Image) High() int {
+	return i.Findings["HIGH"]
+}
+
+// ListRepositories lists the ECR repositories in the region
+func (c *Client) ListRepositories(ctx context.Context) ([]Repository, error) {
+	var repos []Repository
+	pages := ecr.NewDescribeRepositoriesPaginator(c.ecr, &ecr.DescribeRepositoriesInput{})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range page.Repositories {
+			repo := Repository{
+				Name:          awssdk.ToString(r.RepositoryName),
+				URI:           awssdk.ToString(r.RepositoryUri),
+				CreatedAt:     awssdk.ToTime(r.CreatedAt),
+				TagMutability: string(r.ImageTagMutability),
+			}
+			if r.ImageScanningConfiguration != nil {
+				repo.ScanOnPush = r.ImageScanningConfiguration.ScanOnPush
+			}
+			repos = append(repos, repo)
+		}
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].Name < repos[j].Name })
+	return repos, nil
+}
+
+// ListImages lists the images of a repository, newest first
+func (c *Client) ListImages(ctx context.Context, repository string) ([]Image, error) {
+	var images []Image
+	pages := ecr.NewDescribeImagesPaginator(c.ecr, &ecr.DescribeImagesInput{RepositoryName: awssdk.String(repository)})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range page.ImageDetails {
+			img := Image{
+				Image: retention.Image{
+					Repository: repository,
+					Digest:     awssdk.ToString(d.ImageDigest),
+					Tags:       d.ImageTags,
+					PushedAt:   awssdk.ToTime(d.ImagePushedAt),
+					Size:       awssdk.ToInt64(d.ImageSizeInBytes),
+				},
+				LastPulledAt: awssdk.ToTime(d.LastRecordedPullTime),
+			}
+			if d.ImageScanStatus != nil {
+				img.ScanStatus = string(d.ImageScanStatus.Status)
+			}
+			if d.ImageScanFindingsSummary != nil {
+				img.Findings = make(map[string]int, len(d.ImageScanFindingsSummary.FindingSeverityCounts))
+				for severity, count := range d.ImageScanFindingsSummary.FindingSeverityCounts {
+					img.Findings[severity] = int(count)
+				}
+			}
+			images = append(images, img)
+		}
+	}
+	sort.Slice(images, func(i, j int) bool { return images[i].PushedAt.After(images[j].PushedAt) })
+	return images, nil
+}
+
+// GetLifecyclePolicy returns the repository's lifecycle policy text, or "" if none is set
+func (c *Client) GetLifecyclePolicy(ctx context.Context, repository string) (string, error) {
+	resp, err := c.ecr.GetLifecyclePolicy(ctx, &ecr.GetLifecyclePolicyInput{RepositoryName: awssdk.String(repository)})
+	if IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return awssdk.ToString(resp.LifecyclePolicyText), nil
+}
+
+// DeleteImages deletes images by digest and returns how many were removed
+func (c *Client) DeleteImages(ctx context.Context, repository string, digests []string) (int, error) {
+	deleted := 0
+	var failures []string
+
+	// BatchDeleteImage accepts at most 100 image IDs per call
+	for start := 0; start < len(digests); start += 100 {
+		end := start + 100
+		if end > len(digests) {
+			end = len(digests)
+		}
+
+		ids := make([]ecrtypes.ImageIdentifier, 0, end-start)
+		for _, digest := range digests[start:end] {
+			ids = append(ids, ecrtypes.ImageIdentifier{ImageDigest: awssdk.String(digest)})
+		}
+
+		resp, err := c.ecr.BatchDeleteImage(ctx, &ecr.BatchDeleteImageInput{
+			RepositoryName: awssdk.String(repository),
+			ImageIds:       ids,
+		})
+		if err != nil {
+			return deleted, err
+		}
+
+		// A multi-tag image is reported once per tag
+		seen := make(map[string]bool)
+		for _, id := range resp.ImageIds {
+			seen[awssdk.ToString(id.ImageDigest)] = true
+		}
+		deleted += len(seen)
+		for _, f := range resp.Failures {
+			var digest string
+			if f.ImageId != nil {
+				digest = awssdk.ToString(f.ImageId.ImageDigest)
+			}
+			failures = append(failures, fmt.Sprintf("%s: %s", digest, awssdk.ToString(f.FailureReason)))
+		}
+	}
+
+	if len(failures) > 0 {
+		return deleted, fmt.Errorf("failed to delete %d images: %s", len(failures), failures[0])
+	}
+	return deleted, nil
+}
+
+// lifecyclePolicy mirrors the ECR lifecycle policy document
+type lifecyclePolicy struct {
+	Rules []struct {
+		RulePriority int    `json:"rulePriority"`
+		Description  string `json:"description"`
+		Selection    struct {
+			TagStatus      string   `json:"tagStatus"`
+			TagPrefixList  []string `json:"tagPrefixList"`
+			TagPatternList []string `json:"tagPatternList"`
+			CountType      string   `json:"countType"`
+			CountUnit      string   `json:"countUnit"`
+			CountNumber    int      `json:"countNumber"`
+		} `json:"selection"`
+	} `json:"rules"`
+}
+
+// ParseLifecyclePolicy converts an ECR lifecycle policy into retention rules
+func ParseLifecyclePolicy(text string) ([]retention.Rule, error) {
+	var policy lifecyclePolicy
+	if err := json.Unmarshal([]byte(text), &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse lifecycle policy: %w", err)
+	}
+
+	rules := make([]retention.Rule, 0, len(policy.Rules))
+	for _, r := range policy.Rules {
+		rule := retention.Rule{
+			Priority:    r.RulePriority,
+			Description: r.Description,
+			TagStatus:   r.Selection.TagStatus,
+			TagPrefixes: r.Selection.TagPrefixList,
+			TagPatterns: r.Selection.TagPatternList,
+		}
+
+		switch r.Selection.CountType {
+		case "imageCountMoreThan":
+			rule.KeepLast = r.Selection.CountNumber
+		case "sinceImagePushed":
+			if r.Selection.CountUnit != "" && r.Selection.CountUnit != "days" {
+				return nil, fmt.Errorf("rule %d: unsupported count unit %q", r.RulePriority, r.Selection.CountUnit)
+			}
+			rule.MaxAge = time.Duration(r.Selection.CountNumber) * 24 * time.Hour
+		default:
+			return nil, fmt.Errorf("rule %d: unsupported count type %q", r.RulePriority, r.Selection.CountType)
+		}
+
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,123 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+)
+
+// EKSCluster is the subset of an EKS cluster description the toolkit uses
+type EKSCluster struct {
+	Name            string
+	Version         string
+	PlatformVersion string
+	Status          string
+	SupportType     string
+}
+
+// EKSNodegroup is a managed node group
+type EKSNodegroup struct {
+	Name           string
+	Version        string
+	ReleaseVersion string
+	InstanceTypes  []string
+	DesiredSize    int
+	Status         string
+}
+
+// EKSVersion is a Kubernetes version offered by EKS with its support dates
+type EKSVersion struct {
+	Version                  string
+	Status                   string
+	Default                  bool
+	EndOfStandardSupportDate time.Time
+	EndOfExtendedSupportDate time.Time
+}
+
+// DescribeEKSCluster describes an EKS cluster
+func (c *Client) DescribeEKSCluster(ctx context.Context, name string) (*EKSCluster, error) {
+	resp, err := c.eks.DescribeCluster(ctx, &eks.DescribeClusterInput{Name: awssdk.String(name)})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Cluster == nil {
+		return nil, fmt.Errorf("EKS cluster %s has no description", name)
+	}
+
+	cluster := &EKSCluster{
+		Name:            awssdk.ToString(resp.Cluster.Name),
+		Version:         awssdk.ToString(resp.Cluster.Version),
+		PlatformVersion: awssdk.ToString(resp.Cluster.PlatformVersion),
+		Status:          string(resp.Cluster.Status),
+	}
+	if resp.Cluster.UpgradePolicy != nil {
+		cluster.SupportType = string(resp.Cluster.UpgradePolicy.SupportType)
+	}
+	return cluster, nil
+}
+
+// ListEKSNodegroups describes the managed node groups of a cluster
+func (c *Client) ListEKSNodegroups(ctx context.Context, cluster string) ([]EKSNodegroup, error) {
+	var names []string
+	pages := eks.NewListNodegroupsPaginator(c.eks, &eks.ListNodegroupsInput{ClusterName: awssdk.String(cluster)})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, page.Nodegroups...)
+	}
+
+	nodegroups := make([]EKSNodegroup, 0, len(names))
+	for _, name := range names {
+		resp, err := c.eks.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
+			ClusterName:   awssdk.String(cluster),
+			NodegroupName: awssdk.String(name),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if resp.Nodegroup == nil {
+			continue
+		}
+
+		ng := resp.Nodegroup
+		nodegroup := EKSNodegroup{
+			Name:           awssdk.ToString(ng.NodegroupName),
+			Version:        awssdk.ToString(ng.Version),
+			ReleaseVersion: awssdk.ToString(ng.ReleaseVersion),
+			InstanceTypes:  ng.InstanceTypes,
+			Status:         string(ng.Status),
+		}
+		if ng.ScalingConfig != nil {
+			nodegroup.DesiredSize = int(awssdk.ToInt32(ng.ScalingConfig.DesiredSize))
+		}
+		nodegroups = append(nodegroups, nodegroup)
+	}
+	return nodegroups, nil
+}
+
+// ListEKSVersions lists the Kubernetes versions EKS currently offers
+func (c *Client) ListEKSVersions(ctx context.Context) ([]EKSVersion, error) {
+	var versions []EKSVersion
+	pages := eks.NewDescribeClusterVersionsPaginator(c.eks, &eks.DescribeClusterVersionsInput{})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range page.ClusterVersions {
+			versions = append(versions, EKSVersion{
+				Version:                  awssdk.ToString(v.ClusterVersion),
+				Status:                   string(v.VersionStatus),
+				Default:                  v.DefaultVersion,
+				EndOfStandardSupportDate: awssdk.ToTime(v.EndOfStandardSupportDate),
+				EndOfExtendedSupportDate: awssdk.ToTime(v.EndOfExtendedSupportDate),
+			})
+		}
+	}
+	return versions, nil
+}
@@ -0,0 +1,63 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Object is the metadata of an uploaded object
+type S3Object struct {
+	Bucket        string `json:"bucket"`
+	Key           string `json:"key"`
+	ContentLength int64  `json:"ContentLength"`
+	ETag          string `json:"ETag"`
+}
+
+// ParseS3URI splits s3://bucket/key into bucket and key
+func ParseS3URI(uri string) (string, string, error) {
+	rest, ok := strings.CutPrefix(uri, "s3://")
+	if !ok {
+		return "", "", fmt.Errorf("invalid S3 URI %q (expected s3://bucket/prefix)", uri)
+	}
+	bucket, key, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("invalid S3 URI %q: missing bucket", uri)
+	}
+	return bucket, key, nil
+}
+
+// UploadFile copies a local file to s3://bucket/key and returns the stored object's metadata.
+// Large files are uploaded in parts.
+func (c *Client) UploadFile(ctx context.Context, path, bucket, key string) (*S3Object, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	uploader := manager.NewUploader(c.s3)
+	if _, err := uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket: awssdk.String(bucket),
+		Key:    awssdk.String(key),
+		Body:   f,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to upload %s to s3://%s/%s: %w", path, bucket, key, err)
+	}
+
+	head, err := c.s3.HeadObject(ctx, &s3.HeadObjectInput{Bucket: awssdk.String(bucket), Key: awssdk.String(key)})
+	if err != nil {
+		return nil, err
+	}
+	return &S3Object{
+		Bucket:        bucket,
+		Key:           key,
+		ContentLength: awssdk.ToInt64(head.ContentLength),
+		ETag:          awssdk.ToString(head.ETag),
+	}, nil
+}
@@ -0,0 +1,21 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// GetSecretValue returns the SecretString of a Secrets Manager secret
+func (c *Client) GetSecretValue(ctx context.Context, secretID string) (string, error) {
+	resp, err := c.secretsManager.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: awssdk.String(secretID)})
+	if err != nil {
+		return "", err
+	}
+	if awssdk.ToString(resp.SecretString) == "" {
+		return "", fmt.Errorf("secret %s has no string value", secretID)
+	}
+	return *resp.SecretString, nil
+}
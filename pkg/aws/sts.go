@@ -0,0 +1,28 @@
+package aws
+
+import (
+	"context"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Identity is the account and principal the credentials resolve to
+type Identity struct {
+	Account string `json:"Account"`
+	Arn     string `json:"Arn"`
+	UserID  string `json:"UserId"`
+}
+
+// GetCallerIdentity returns who the configured credentials authenticate as
+func (c *Client) GetCallerIdentity(ctx context.Context) (*Identity, error) {
+	resp, err := c.sts.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{
+		Account: awssdk.ToString(resp.Account),
+		Arn:     awssdk.ToString(resp.Arn),
+		UserID:  awssdk.ToString(resp.UserId),
+	}, nil
+}
@@ -0,0 +1,93 @@
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultWorkers bounds how many API calls a bulk operation makes at once
+const DefaultWorkers = 8
+
+// Failure records an item that could not be processed
+type Failure struct {
+	Item string
+	Err  error
+}
+
+func (f Failure) Error() string {
+	return fmt.Sprintf("%s: %v", f.Item, f.Err)
+}
+
+// Errors collects the per-item failures of a bulk operation
+type Errors []Failure
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, f := range e {
+		msgs = append(msgs, f.Error())
+	}
+	return fmt.Sprintf("%d items failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Run calls fn for every index in [0, n) on at most workers goroutines and returns each call's error
+func Run(n, workers int, fn func(i int) error) []error {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+
+	errs := make([]error, n)
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// Collect pairs the errors returned by Run with item names; it returns nil when all items succeeded
+func Collect(items []string, errs []error) error {
+	var failures Errors
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, Failure{Item: items[i], Err: err})
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return failures
+}
+
+// Succeeded counts the items that did not fail
+func Succeeded(errs []error) int {
+	count := 0
+	for _, err := range errs {
+		if err == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// Failures extracts the per-item failures from an error returned by a bulk operation.
+// Other errors are reported as a single failure of the whole operation.
+func Failures(err error, operation string) Errors {
+	if err == nil {
+		return nil
+	}
+	var failures Errors
+	if errors.As(err, &failures) {
+		return failures
+	}
+	return Errors{{Item: operation, Err: err}}
+}
@@ -0,0 +1,178 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted replaces the value of sensitive config keys
+const Redacted = "[REDACTED]"
+
+// Bundle writes a gzipped tarball of diagnostics under a single top-level directory
+type Bundle struct {
+	file *os.File
+	gz   *gzip.Writer
+	tw   *tar.Writer
+	root string
+	now  time.Time
+
+	files  []string
+	errors map[string]string
+}
+
+// Manifest describes a bundle; it is written last as manifest.json
+type Manifest struct {
+	Version   string            `json:"version"`
+	CreatedAt time.Time         `json:"created_at"`
+	Platform  string            `json:"platform"`
+	Command   string            `json:"command"`
+	Files     []string          `json:"files"`
+	Errors    map[string]string `json:"errors,omitempty"`
+}
+
+// Create starts a bundle at path; entries are stored under root/ in the archive
+func Create(path, root string) (*Bundle, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bundle: %w", err)
+	}
+	gz := gzip.NewWriter(f)
+	return &Bundle{
+		file:   f,
+		gz:     gz,
+		tw:     tar.NewWriter(gz),
+		root:   root,
+		now:    time.Now(),
+		errors: map[string]string{},
+	}, nil
+}
+
+// Add stores data as name
+func (b *Bundle) Add(name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    path.Join(b.root, name),
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: b.now,
+	}
+	if err := b.tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+	if _, err := b.tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+	b.files = append(b.files, name)
+	return nil
+}
+
+// AddJSON stores v as indented JSON
+func (b *Bundle) AddJSON(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	return b.Add(name, append(data, '\n'))
+}
+
+// AddFile copies a file from disk; a missing file is skipped
+func (b *Bundle) AddFile(name, src string) error {
+	data, err := os.ReadFile(src)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	return b.Add(name, data)
+}
+
+// AddCollected stores v, or records err in the manifest when it could not be collected
+func (b *Bundle) AddCollected(name string, v interface{}, err error) {
+	if err == nil {
+		err = b.AddJSON(name, v)
+	}
+	if err != nil {
+		b.errors[name] = err.Error()
+	}
+}
+
+// Errors returns the items that could not be collected, keyed by file name
+func (b *Bundle) Errors() map[string]string {
+	return b.errors
+}
+
+// Close writes the manifest and finishes the archive
+func (b *Bundle) Close(version, command, platform string) error {
+	files := append([]string(nil), b.files...)
+	sort.Strings(files)
+	manifest := Manifest{
+		Version:   version,
+		CreatedAt: b.now,
+		Platform:  platform,
+		Command:   command,
+		Files:     files,
+		Errors:    b.errors,
+	}
+	err := b.AddJSON("manifest.json", manifest)
+
+	for _, closer := range []func() error{b.tw.Close, b.gz.Close, b.file.Close} {
+		if cerr := closer(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to finish bundle: %w", cerr)
+		}
+	}
+	return err
+}
+
+// RedactConfig renders settings as YAML with the values of sensitive keys
+// (tokens, passwords, secrets, keys, webhooks) and notification URLs replaced
+func RedactConfig(settings map[string]interface{}) ([]byte, error) {
+	data, err := yaml.Marshal(redact(settings, false))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return data, nil
+}
+
+// redact copies v with sensitive values replaced; notification sink URLs embed
+// credentials, so every url under notify is treated as sensitive
+func redact(v interface{}, inNotify bool) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			sensitive := isSensitiveKey(k) || (inNotify && strings.EqualFold(k, "url"))
+			if sensitive && item != nil && item != "" {
+				out[k] = Redacted
+				continue
+			}
+			out[k] = redact(item, inNotify || strings.EqualFold(k, "notify"))
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = redact(item, inNotify)
+		}
+		return out
+	}
+	return v
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range []string{"token", "password", "secret", "key", "credential", "webhook", "auth", "private", "dsn"} {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
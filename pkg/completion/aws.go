@@ -0,0 +1,41 @@
+package completion
+
+import (
+	"context"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/aws"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// ECRRepositoryCompletion provides completion for ECR repository names
+func ECRRepositoryCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	profile, _ := cmd.Flags().GetString("aws-profile")
+	if profile == "" {
+		profile = viper.GetString("aws.profile")
+	}
+	region, _ := cmd.Flags().GetString("region")
+	if region == "" {
+		region = viper.GetString("aws.region")
+	}
+
+	client, err := aws.NewClient(profile, region)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	repos, err := client.ListRepositories(context.Background())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, r := range repos {
+		if strings.HasPrefix(r.Name, toComplete) {
+			completions = append(completions, r.Name)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
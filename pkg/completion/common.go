@@ -1,9 +1,11 @@
 package completion
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // ComplianceTargetCompletion provides completion for compliance check/report targets
@@ -37,6 +39,8 @@ func OutputFormatCompletion(cmd *cobra.Command, args []string, toComplete string
 		"table\tConsole table output",
 		"json\tJSON format",
 		"yaml\tYAML format",
+		"custom-columns=\tSelected fields, e.g. custom-columns=NAME:.name,STATUS:.status",
+		"go-template=\tGo template over the JSON fields",
 	}
 
 	var completions []string
@@ -57,6 +61,103 @@ func ReportFormatCompletion(cmd *cobra.Command, args []string, toComplete string
 		"json\tJSON format for programmatic use",
 		"junit\tJUnit XML format for CI integration",
 		"html\tHTML report for sharing",
+		"markdown\tMarkdown for wikis and merge requests",
+	}
+
+	var completions []string
+	for _, format := range formats {
+		parts := strings.Split(format, "\t")
+		if strings.HasPrefix(parts[0], toComplete) {
+			completions = append(completions, format)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// PlanFormatCompletion provides completion for terraform plan summary formats
+func PlanFormatCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	formats := []string{
+		"table\tConsole summary",
+		"markdown\tMarkdown for merge request comments",
+		"junit\tJUnit XML format for CI integration",
+	}
+
+	var completions []string
+	for _, format := range formats {
+		parts := strings.Split(format, "\t")
+		if strings.HasPrefix(parts[0], toComplete) {
+			completions = append(completions, format)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// TimelineFormatCompletion provides completion for k8s timeline --format
+func TimelineFormatCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	formats := []string{
+		"table\tConsole timeline",
+		"markdown\tMarkdown for post-incident reviews",
+	}
+
+	var completions []string
+	for _, format := range formats {
+		parts := strings.Split(format, "\t")
+		if strings.HasPrefix(parts[0], toComplete) {
+			completions = append(completions, format)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// TopSortCompletion provides completion for k8s top --sort-by
+func TopSortCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	fields := []string{
+		"cpu\tCPU usage percentage",
+		"memory\tMemory usage percentage",
+		"pods\tNumber of pods",
+		"name\tNode name",
+	}
+
+	var completions []string
+	for _, field := range fields {
+		parts := strings.Split(field, "\t")
+		if strings.HasPrefix(parts[0], toComplete) {
+			completions = append(completions, field)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// TopPodsViewCompletion provides completion for k8s resources --view
+func TopPodsViewCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	views := []string{
+		"all\tEvery view below",
+		"usage\tTop pods by CPU and memory usage",
+		"over-requested\tMost requested capacity left unused",
+		"no-requests\tPods missing CPU or memory requests",
+	}
+
+	var completions []string
+	for _, view := range views {
+		parts := strings.Split(view, "\t")
+		if strings.HasPrefix(parts[0], toComplete) {
+			completions = append(completions, view)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// ResourcesFormatCompletion provides completion for k8s resources --format
+func ResourcesFormatCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	formats := []string{
+		"table\tConsole tables",
+		"csv\tPer-namespace report for spreadsheets",
+		"json\tPer-namespace report as JSON",
 	}
 
 	var completions []string
@@ -90,6 +191,27 @@ func SeverityCompletion(cmd *cobra.Command, args []string, toComplete string) ([
 	return completions, cobra.ShellCompDirectiveNoFileComp
 }
 
+// FailOnCompletion provides completion for the compliance --fail-on flag
+func FailOnCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	levels := []string{
+		"none\tNever fail on findings",
+		"low\tFail on low severity and above",
+		"medium\tFail on medium severity and above",
+		"high\tFail on high severity and above",
+		"critical\tFail on critical severity only",
+	}
+
+	var completions []string
+	for _, level := range levels {
+		parts := strings.Split(level, "\t")
+		if strings.HasPrefix(parts[0], toComplete) {
+			completions = append(completions, level)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
 // PipelineStatusCompletion provides completion for GitLab pipeline status
 func PipelineStatusCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	statuses := []string{
@@ -157,3 +279,36 @@ func NoFileCompletion(cmd *cobra.Command, args []string, toComplete string) ([]s
 	return nil, cobra.ShellCompDirectiveNoFileComp
 }
 
+// ProfileCompletion provides completion for profiles defined in the config file
+func ProfileCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	for name := range viper.GetStringMap("profiles") {
+		if strings.HasPrefix(name, toComplete) {
+			completions = append(completions, name)
+		}
+	}
+	sort.Strings(completions)
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+
+// CheckStatusCompletion provides completion for --fail-on of endpoint checks
+func CheckStatusCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	statuses := []string{
+		"none\tNever fail",
+		"warning\tFail on warnings or worse",
+		"critical\tFail on critical findings or worse",
+		"error\tFail only when an endpoint cannot be checked",
+	}
+
+	var completions []string
+	for _, status := range statuses {
+		parts := strings.Split(status, "\t")
+		if strings.HasPrefix(parts[0], toComplete) {
+			completions = append(completions, status)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
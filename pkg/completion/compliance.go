@@ -0,0 +1,48 @@
+package completion
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/compliance"
+	"github.com/spf13/cobra"
+)
+
+// RuleCompletion provides completion for built-in compliance rule IDs
+func RuleCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// Complete only the last entry of a comma-separated list
+	prefix, term := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, term = toComplete[:i+1], toComplete[i+1:]
+	}
+
+	var completions []string
+	for _, policy := range compliance.GetBuiltinPolicies() {
+		if strings.HasPrefix(policy.ID, strings.ToUpper(term)) {
+			completions = append(completions, prefix+policy.ID+"\t"+policy.Name)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// PolicyCategoryCompletion provides completion for compliance policy categories
+func PolicyCategoryCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	seen := make(map[string]bool)
+	var completions []string
+	for _, policy := range compliance.GetBuiltinPolicies() {
+		if !seen[policy.Category] && strings.HasPrefix(strings.ToLower(policy.Category), strings.ToLower(toComplete)) {
+			seen[policy.Category] = true
+			completions = append(completions, policy.Category)
+		}
+	}
+	sort.Strings(completions)
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// DirectoryCompletion restricts completion to directories
+func DirectoryCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveFilterDirs
+}
+
@@ -190,3 +190,98 @@ func LogLevelCompletion(cmd *cobra.Command, args []string, toComplete string) ([
 	return completions, cobra.ShellCompDirectiveNoFileComp
 }
 
+// LogExportFormatCompletion provides docker logs --format completion
+func LogExportFormatCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	formats := []string{
+		"jsonl\tOne JSON object per line",
+		"text\tTimestamp, stream and message per line",
+	}
+
+	var completions []string
+	for _, f := range formats {
+		if strings.HasPrefix(f, toComplete) {
+			completions = append(completions, f)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// ContainerFilterCompletion completes docker container filters: the filter
+// keys first, then values for status, label, ancestor and name
+func ContainerFilterCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// Complete only the last term of a comma-separated filter
+	prefix, term := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, term = toComplete[:i+1], toComplete[i+1:]
+	}
+
+	key, _, hasValue := strings.Cut(term, "=")
+	if !hasValue {
+		keys := []string{
+			"status=\tcreated, restarting, running, removing, paused, exited, dead",
+			"name=\tContainer name",
+			"label=\tLabel key or key=value",
+			"ancestor=\tImage the container was created from",
+			"health=\tstarting, healthy, unhealthy, none",
+			"network=\tNetwork name or ID",
+			"volume=\tVolume name or mount point",
+		}
+
+		var completions []string
+		for _, k := range keys {
+			parts := strings.Split(k, "\t")
+			if strings.HasPrefix(parts[0], term) {
+				completions = append(completions, prefix+k)
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+
+	var values []string
+	switch key {
+	case "status":
+		values = []string{"created", "restarting", "running", "removing", "paused", "exited", "dead"}
+	case "health":
+		values = []string{"starting", "healthy", "unhealthy", "none"}
+	case "name", "label", "ancestor":
+		cli, err := getDockerClient()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		defer cli.Close()
+
+		containers, err := cli.ContainerList(context.Background(), container.ListOptions{All: true})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		for _, c := range containers {
+			switch key {
+			case "name":
+				for _, name := range c.Names {
+					values = append(values, strings.TrimPrefix(name, "/"))
+				}
+			case "label":
+				for k, v := range c.Labels {
+					values = append(values, k, k+"="+v)
+				}
+			case "ancestor":
+				values = append(values, c.Image)
+			}
+		}
+	}
+
+	var completions []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		candidate := key + "=" + value
+		if strings.HasPrefix(candidate, term) && !seen[candidate] {
+			completions = append(completions, prefix+candidate)
+			seen[candidate] = true
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
@@ -0,0 +1,116 @@
+package completion
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/secrets"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// gitlabSetting resolves a GitLab setting from its flag, environment variable or config key
+func gitlabSetting(cmd *cobra.Command, flag, env, key string) string {
+	if f := cmd.Flag(flag); f != nil && f.Changed {
+		return f.Value.String()
+	}
+	if value := os.Getenv(env); value != "" {
+		return value
+	}
+	return viper.GetString(key)
+}
+
+// getGitLabClient creates a GitLab client and project ID for completion
+func getGitLabClient(cmd *cobra.Command) (*gitlabclient.Client, string, bool) {
+	token := gitlabSetting(cmd, "token", "GITLAB_TOKEN", "gitlab.token")
+	projectID := gitlabSetting(cmd, "project", "GITLAB_PROJECT", "gitlab.project")
+	if token == "" || projectID == "" {
+		return nil, "", false
+	}
+	token, err := secrets.Resolve(token)
+	if err != nil {
+		return nil, "", false
+	}
+
+	url := gitlabSetting(cmd, "url", "GITLAB_URL", "gitlab.url")
+	if url == "" {
+		url = "https://gitlab.com"
+	}
+
+	client, err := gitlabclient.NewClient(url, token)
+	if err != nil {
+		return nil, "", false
+	}
+	return client, projectID, true
+}
+
+// BranchCompletion provides GitLab branch name completion
+func BranchCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client, projectID, ok := getGitLabClient(cmd)
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	branches, err := client.ListBranches(context.Background(), projectID, toComplete)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, branch := range branches {
+		if strings.HasPrefix(branch, toComplete) {
+			completions = append(completions, branch)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// EnvironmentCompletion provides GitLab environment name completion
+func EnvironmentCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client, projectID, ok := getGitLabClient(cmd)
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	envs, err := client.ListEnvironments(context.Background(), projectID)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, env := range envs {
+		if strings.HasPrefix(env.Name, toComplete) {
+			completions = append(completions, env.Name)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// JobStatusCompletion provides completion for GitLab job status
+func JobStatusCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	statuses := []string{
+		"created\tNot yet scheduled",
+		"pending\tWaiting for a runner",
+		"running\tCurrently running",
+		"success\tCompleted successfully",
+		"failed\tFailed execution",
+		"canceled\tCanceled by user",
+		"skipped\tSkipped execution",
+		"manual\tWaiting for manual action",
+	}
+
+	var completions []string
+	for _, status := range statuses {
+		parts := strings.Split(status, "\t")
+		if strings.HasPrefix(parts[0], toComplete) {
+			completions = append(completions, status)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
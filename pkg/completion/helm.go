@@ -0,0 +1,46 @@
+package completion
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HelmReleaseCompletion provides Helm release name completion for the first argument
+func HelmReleaseCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	client, err := getK8sClient(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	namespace := "default"
+	if ns := cmd.Flag("namespace"); ns != nil && ns.Value.String() != "" {
+		namespace = ns.Value.String()
+	}
+
+	// Helm labels every release record with its release name
+	secrets, err := client.CoreV1().Secrets(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: "owner=helm"})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	seen := make(map[string]bool)
+	var completions []string
+	for _, s := range secrets.Items {
+		name := s.Labels["name"]
+		if name != "" && !seen[name] && strings.HasPrefix(name, toComplete) {
+			seen[name] = true
+			completions = append(completions, name)
+		}
+	}
+	sort.Strings(completions)
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
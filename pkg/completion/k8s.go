@@ -2,8 +2,7 @@ package completion
 
 import (
 	"context"
-	"os"
-	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -12,15 +11,26 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 )
 
-// getK8sClient creates a Kubernetes client for completion
-func getK8sClient() (*kubernetes.Clientset, error) {
-	kubeconfigPath := os.Getenv("KUBECONFIG")
-	if kubeconfigPath == "" {
-		home, _ := os.UserHomeDir()
-		kubeconfigPath = filepath.Join(home, ".kube", "config")
+// loadingRules resolves kubeconfig files like kubectl: --kubeconfig, then KUBECONFIG, then ~/.kube/config
+func loadingRules(cmd *cobra.Command) *clientcmd.ClientConfigLoadingRules {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if flag := cmd.Flag("kubeconfig"); flag != nil && flag.Value.String() != "" {
+		rules.ExplicitPath = flag.Value.String()
+	}
+	return rules
+}
+
+// getK8sClient creates a Kubernetes client for completion, honouring --kubeconfig and --context
+func getK8sClient(cmd *cobra.Command) (*kubernetes.Clientset, error) {
+	overrides := &clientcmd.ConfigOverrides{}
+	if flag := cmd.Flag("context"); flag != nil {
+		overrides.CurrentContext = flag.Value.String()
 	}
 
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules(cmd),
+		overrides,
+	).ClientConfig()
 	if err != nil {
 		return nil, err
 	}
@@ -30,7 +40,7 @@ func getK8sClient() (*kubernetes.Clientset, error) {
 
 // NamespaceCompletion provides namespace completion
 func NamespaceCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	client, err := getK8sClient()
+	client, err := getK8sClient(cmd)
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -52,7 +62,7 @@ func NamespaceCompletion(cmd *cobra.Command, args []string, toComplete string) (
 
 // PodCompletion provides pod name completion
 func PodCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	client, err := getK8sClient()
+	client, err := getK8sClient(cmd)
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -90,7 +100,7 @@ func ContainerInPodCompletion(cmd *cobra.Command, args []string, toComplete stri
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	client, err := getK8sClient()
+	client, err := getK8sClient(cmd)
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -134,7 +144,7 @@ func ContainerInPodCompletion(cmd *cobra.Command, args []string, toComplete stri
 
 // NodeCompletion provides node name completion
 func NodeCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	client, err := getK8sClient()
+	client, err := getK8sClient(cmd)
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -156,7 +166,7 @@ func NodeCompletion(cmd *cobra.Command, args []string, toComplete string) ([]str
 
 // DeploymentCompletion provides deployment name completion
 func DeploymentCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	client, err := getK8sClient()
+	client, err := getK8sClient(cmd)
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -187,7 +197,7 @@ func DeploymentCompletion(cmd *cobra.Command, args []string, toComplete string)
 
 // ServiceCompletion provides service name completion
 func ServiceCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	client, err := getK8sClient()
+	client, err := getK8sClient(cmd)
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -218,13 +228,7 @@ func ServiceCompletion(cmd *cobra.Command, args []string, toComplete string) ([]
 
 // ContextCompletion provides kubernetes context completion
 func ContextCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	kubeconfigPath := os.Getenv("KUBECONFIG")
-	if kubeconfigPath == "" {
-		home, _ := os.UserHomeDir()
-		kubeconfigPath = filepath.Join(home, ".kube", "config")
-	}
-
-	config, err := clientcmd.LoadFromFile(kubeconfigPath)
+	config, err := loadingRules(cmd).Load()
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -270,3 +274,73 @@ func ResourceTypeCompletion(cmd *cobra.Command, args []string, toComplete string
 	return completions, cobra.ShellCompDirectiveNoFileComp
 }
 
+// LabelCompletion completes label selectors from the labels of pods in the
+// selected namespace: keys first, then key=value once a key is typed
+func LabelCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client, err := getK8sClient(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	namespace := ""
+	if ns := cmd.Flag("namespace"); ns != nil && ns.Value.String() != "" {
+		namespace = ns.Value.String()
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Complete only the last term of a comma-separated selector
+	prefix, term := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, term = toComplete[:i+1], toComplete[i+1:]
+	}
+	key, _, hasValue := strings.Cut(term, "=")
+
+	seen := make(map[string]bool)
+	var completions []string
+	for _, pod := range pods.Items {
+		for k, v := range pod.Labels {
+			candidate := k
+			if hasValue {
+				if k != key {
+					continue
+				}
+				candidate = k + "=" + v
+			}
+			if !seen[candidate] && strings.HasPrefix(candidate, term) {
+				seen[candidate] = true
+				completions = append(completions, prefix+candidate)
+			}
+		}
+	}
+	sort.Strings(completions)
+
+	directive := cobra.ShellCompDirectiveNoFileComp
+	if !hasValue {
+		// Keys are followed by =value, so don't append a space
+		directive |= cobra.ShellCompDirectiveNoSpace
+	}
+	return completions, directive
+}
+
+// EventTypeCompletion provides completion for Kubernetes event types
+func EventTypeCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	types := []string{
+		"Normal\tRoutine events",
+		"Warning\tEvents that may need attention",
+	}
+
+	var completions []string
+	for _, t := range types {
+		parts := strings.Split(t, "\t")
+		if strings.HasPrefix(parts[0], toComplete) {
+			completions = append(completions, t)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
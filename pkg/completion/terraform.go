@@ -0,0 +1,42 @@
+package completion
+
+import (
+	"os"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/terraform"
+	"github.com/spf13/cobra"
+)
+
+// TerraformAddressCompletion provides completion for resource addresses in state
+func TerraformAddressCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var (
+		state *terraform.State
+		err   error
+	)
+	if file, _ := cmd.Flags().GetString("file"); file != "" {
+		var data []byte
+		if data, err = os.ReadFile(file); err == nil {
+			state, err = terraform.LoadState(data)
+		}
+	} else {
+		dir, _ := cmd.Flags().GetString("dir")
+		state, err = terraform.ReadState(cmd.Context(), dir)
+	}
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, r := range state.Resources {
+		if strings.HasPrefix(r.Address, toComplete) {
+			completions = append(completions, r.Address+"\t"+r.Type)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,129 @@
+package compliance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/resultcache"
+)
+
+// fileCacheVersion is bumped whenever file rule logic changes so stale results
+// are discarded. Changes to the rule set itself are caught by ruleSetFingerprint.
+const fileCacheVersion = 3
+
+// fileCache stores file checker results keyed by absolute path and content hash
+type fileCache struct {
+	Version int                       `json:"version"`
+	Rules   string                    `json:"rules"`
+	Entries map[string]fileCacheEntry `json:"entries"`
+
+	path  string
+	dirty bool
+}
+
+type fileCacheEntry struct {
+	Hash    string        `json:"hash"`
+	Results []CheckResult `json:"results"`
+}
+
+// fileCachePath returns the location of the file result cache
+func fileCachePath() (string, error) {
+	dir, err := resultcache.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "compliance-files.json"), nil
+}
+
+// loadFileCache reads the cache from disk, returning an empty cache if it is missing or stale
+func loadFileCache() *fileCache {
+	cache := &fileCache{Version: fileCacheVersion, Rules: ruleSetFingerprint(), Entries: make(map[string]fileCacheEntry)}
+
+	path, err := fileCachePath()
+	if err != nil {
+		return cache
+	}
+	cache.path = path
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+
+	var stored fileCache
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Version != fileCacheVersion || stored.Rules != cache.Rules || stored.Entries == nil {
+		cache.dirty = true
+		return cache
+	}
+
+	cache.Entries = stored.Entries
+	return cache
+}
+
+// ruleSetFingerprint hashes the built-in policy definitions so adding, removing
+// or re-grading a rule invalidates cached results without a version bump
+func ruleSetFingerprint() string {
+	data, err := json.Marshal(GetBuiltinPolicies())
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// lookup returns cached results for a file if its content hash is unchanged
+func (c *fileCache) lookup(key, hash string) ([]CheckResult, bool) {
+	entry, ok := c.Entries[key]
+	if !ok || entry.Hash != hash {
+		return nil, false
+	}
+	return entry.Results, true
+}
+
+// store records the results for a file
+func (c *fileCache) store(key, hash string, results []CheckResult) {
+	c.Entries[key] = fileCacheEntry{Hash: hash, Results: results}
+	c.dirty = true
+}
+
+// save prunes entries for deleted files and writes the cache to disk
+func (c *fileCache) save() error {
+	for key := range c.Entries {
+		if _, err := os.Stat(key); err != nil {
+			delete(c.Entries, key)
+			c.dirty = true
+		}
+	}
+
+	if !c.dirty || c.path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+// hashFile returns the hex-encoded SHA-256 of a file's contents
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,50 @@
+package compliance
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFileCacheDiscardsStaleEntries(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	path, err := fileCachePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := map[string]fileCacheEntry{"/tmp/pod.yaml": {Hash: "abc"}}
+	tests := []struct {
+		name     string
+		stored   fileCache
+		wantKept bool
+	}{
+		{"current", fileCache{Version: fileCacheVersion, Rules: ruleSetFingerprint(), Entries: entries}, true},
+		{"old version", fileCache{Version: fileCacheVersion - 1, Rules: ruleSetFingerprint(), Entries: entries}, false},
+		{"rule set changed", fileCache{Version: fileCacheVersion, Rules: "0123456789abcdef", Entries: entries}, false},
+		{"no fingerprint", fileCache{Version: fileCacheVersion, Entries: entries}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.stored)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cache := loadFileCache()
+			if _, kept := cache.lookup("/tmp/pod.yaml", "abc"); kept != tt.wantKept {
+				t.Errorf("entry kept = %v, want %v", kept, tt.wantKept)
+			}
+			if cache.Rules != ruleSetFingerprint() {
+				t.Errorf("cache fingerprint = %q, want the current rule set", cache.Rules)
+			}
+		})
+	}
+}
@@ -2,10 +2,16 @@ package compliance
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
 	"strings"
+	"time"
 
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/client"
 )
 
@@ -15,6 +21,12 @@ type DockerChecker struct {
 	client *client.Client
 }
 
+// ErrDockerUnavailable is returned by DockerChecker.Run when the daemon does not answer
+var ErrDockerUnavailable = errors.New("docker daemon unavailable")
+
+// dockerPingTimeout bounds the reachability check before the Docker checks run
+const dockerPingTimeout = 5 * time.Second
+
 // NewDockerChecker creates a new Docker checker
 func NewDockerChecker(opts CheckOptions) *DockerChecker {
 	return &DockerChecker{opts: opts}
@@ -29,6 +41,13 @@ func (c *DockerChecker) Run(ctx context.Context) ([]CheckResult, error) {
 	c.client = cli
 	defer cli.Close()
 
+	// Fail fast with a recognizable error instead of every check failing on its own
+	pingCtx, cancel := context.WithTimeout(ctx, dockerPingTimeout)
+	defer cancel()
+	if _, err := cli.Ping(pingCtx); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDockerUnavailable, err)
+	}
+
 	var results []CheckResult
 
 	// If a specific image is provided, only check that image
@@ -37,7 +56,7 @@ func (c *DockerChecker) Run(ctx context.Context) ([]CheckResult, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to check image %s: %w", c.opts.Image, err)
 		}
-		return imageResults, nil
+		return filterResults(imageResults, c.opts), nil
 	}
 
 	// Otherwise, check all running containers
@@ -46,7 +65,15 @@ func (c *DockerChecker) Run(ctx context.Context) ([]CheckResult, error) {
 		results = append(results, containerResults...)
 	}
 
-	return results, nil
+	if c.opts.AllImages {
+		imageResults, err := c.checkRunningImages(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check running images: %w", err)
+		}
+		results = append(results, imageResults...)
+	}
+
+	return filterResults(results, c.opts), nil
 }
 
 func (c *DockerChecker) checkContainerSecurity(ctx context.Context) ([]CheckResult, error) {
@@ -57,8 +84,9 @@ func (c *DockerChecker) checkContainerSecurity(ctx context.Context) ([]CheckResu
 		return nil, err
 	}
 
-	for _, cont := range containers {
+	for i, cont := range containers {
 		name := strings.TrimPrefix(cont.Names[0], "/")
+		c.opts.progress("Docker: container %d/%d (%s)", i+1, len(containers), name)
 
 		// Inspect container for detailed info
 		inspect, err := c.client.ContainerInspect(ctx, cont.ID)
@@ -66,177 +94,308 @@ func (c *DockerChecker) checkContainerSecurity(ctx context.Context) ([]CheckResu
 			continue
 		}
 
-		// Check privileged mode
-		if inspect.HostConfig.Privileged {
+		contResults := containerResults(name, inspect)
+		contResults = append(contResults, c.driftResults(ctx, name, inspect.ID)...)
+		setGroup(contResults, cont.Labels[c.groupLabel()])
+		results = append(results, contResults...)
+	}
+
+	return results, nil
+}
+
+// SkippedDockerResults reports every Docker rule as skipped, so a run without
+// a daemon shows what was not checked instead of silently checking less
+func SkippedDockerResults(opts CheckOptions, reason string) []CheckResult {
+	var results []CheckResult
+	for _, p := range GetBuiltinPolicies() {
+		if !strings.HasPrefix(p.ID, "DOCKER-") {
+			continue
+		}
+		results = append(results, CheckResult{
+			RuleID:   p.ID,
+			RuleName: p.Name,
+			Category: p.Category,
+			Severity: p.Severity,
+			Status:   StatusSkipped,
+			Resource: "docker",
+			Message:  reason,
+		})
+	}
+	return filterResults(results, opts)
+}
+
+// composeProjectLabel is set by docker compose on every container it creates
+const composeProjectLabel = "com.docker.compose.project"
+
+func (c *DockerChecker) groupLabel() string {
+	if c.opts.GroupLabel != "" {
+		return c.opts.GroupLabel
+	}
+	return composeProjectLabel
+}
+
+// containerResults runs the container rules against one inspected container
+func containerResults(name string, inspect types.ContainerJSON) []CheckResult {
+	var results []CheckResult
+
+	// Check privileged mode
+	if inspect.HostConfig.Privileged {
+		results = append(results, CheckResult{
+			RuleID:      "DOCKER-SEC-001",
+			RuleName:    "No Privileged Containers",
+			Category:    "Docker Security",
+			Severity:    "critical",
+			Status:      StatusFailed,
+			Resource:    name,
+			Message:     "Container is running in privileged mode",
+			Remediation: "Remove --privileged flag",
+		})
+	} else {
+		results = append(results, CheckResult{
+			RuleID:   "DOCKER-SEC-001",
+			RuleName: "No Privileged Containers",
+			Category: "Docker Security",
+			Severity: "critical",
+			Status:   StatusPassed,
+			Resource: name,
+			Message:  "Container is not running in privileged mode",
+		})
+	}
+
+	// Check user namespace
+	if inspect.HostConfig.UsernsMode == "" || inspect.HostConfig.UsernsMode == "host" {
+		// Check if running as root
+		if inspect.Config.User == "" || inspect.Config.User == "root" || inspect.Config.User == "0" {
 			results = append(results, CheckResult{
-				RuleID:      "DOCKER-SEC-001",
-				RuleName:    "No Privileged Containers",
+				RuleID:      "DOCKER-SEC-002",
+				RuleName:    "Non-Root User",
 				Category:    "Docker Security",
-				Severity:    "critical",
+				Severity:    "high",
 				Status:      StatusFailed,
 				Resource:    name,
-				Message:     "Container is running in privileged mode",
-				Remediation: "Remove --privileged flag",
-			})
-		} else {
-			results = append(results, CheckResult{
-				RuleID:   "DOCKER-SEC-001",
-				RuleName: "No Privileged Containers",
-				Category: "Docker Security",
-				Severity: "critical",
-				Status:   StatusPassed,
-				Resource: name,
-				Message:  "Container is not running in privileged mode",
+				Message:     "Container is running as root",
+				Remediation: "Use USER directive in Dockerfile or --user flag",
 			})
 		}
+	}
 
-		// Check user namespace
-		if inspect.HostConfig.UsernsMode == "" || inspect.HostConfig.UsernsMode == "host" {
-			// Check if running as root
-			if inspect.Config.User == "" || inspect.Config.User == "root" || inspect.Config.User == "0" {
+	// Check host network
+	if inspect.HostConfig.NetworkMode == "host" {
+		results = append(results, CheckResult{
+			RuleID:      "DOCKER-SEC-003",
+			RuleName:    "No Host Network",
+			Category:    "Docker Security",
+			Severity:    "high",
+			Status:      StatusFailed,
+			Resource:    name,
+			Message:     "Container is using host network",
+			Remediation: "Use bridge or custom network",
+		})
+	}
+
+	// Check host PID
+	if inspect.HostConfig.PidMode == "host" {
+		results = append(results, CheckResult{
+			RuleID:      "DOCKER-SEC-004",
+			RuleName:    "No Host PID",
+			Category:    "Docker Security",
+			Severity:    "high",
+			Status:      StatusFailed,
+			Resource:    name,
+			Message:     "Container is using host PID namespace",
+			Remediation: "Remove --pid=host flag",
+		})
+	}
+
+	// Check capabilities
+	if len(inspect.HostConfig.CapAdd) > 0 {
+		for _, cap := range inspect.HostConfig.CapAdd {
+			if isDangerousCap(cap) {
 				results = append(results, CheckResult{
-					RuleID:      "DOCKER-SEC-002",
-					RuleName:    "Non-Root User",
+					RuleID:      "DOCKER-SEC-005",
+					RuleName:    "No Dangerous Capabilities",
 					Category:    "Docker Security",
 					Severity:    "high",
 					Status:      StatusFailed,
 					Resource:    name,
-					Message:     "Container is running as root",
-					Remediation: "Use USER directive in Dockerfile or --user flag",
+					Message:     fmt.Sprintf("Container has dangerous capability: %s", cap),
+					Remediation: "Remove unnecessary capabilities",
 				})
 			}
 		}
+	}
+
+	// Check memory limits
+	if inspect.HostConfig.Memory == 0 {
+		results = append(results, CheckResult{
+			RuleID:      "DOCKER-RES-001",
+			RuleName:    "Memory Limits",
+			Category:    "Docker Resources",
+			Severity:    "medium",
+			Status:      StatusFailed,
+			Resource:    name,
+			Message:     "Container has no memory limit",
+			Remediation: "Set --memory flag",
+		})
+	}
 
-		// Check host network
-		if inspect.HostConfig.NetworkMode == "host" {
+	// Check CPU limits
+	if inspect.HostConfig.CPUQuota == 0 && inspect.HostConfig.NanoCPUs == 0 {
+		results = append(results, CheckResult{
+			RuleID:      "DOCKER-RES-002",
+			RuleName:    "CPU Limits",
+			Category:    "Docker Resources",
+			Severity:    "low",
+			Status:      StatusFailed,
+			Resource:    name,
+			Message:     "Container has no CPU limit",
+			Remediation: "Set --cpus or --cpu-quota flag",
+		})
+	}
+
+	// Check restart policy
+	if inspect.HostConfig.RestartPolicy.Name == "" || inspect.HostConfig.RestartPolicy.Name == "no" {
+		results = append(results, CheckResult{
+			RuleID:      "DOCKER-CFG-001",
+			RuleName:    "Restart Policy",
+			Category:    "Docker Configuration",
+			Severity:    "low",
+			Status:      StatusFailed,
+			Resource:    name,
+			Message:     "Container has no restart policy",
+			Remediation: "Set --restart=unless-stopped or similar",
+		})
+	}
+
+	// Check health check
+	if inspect.Config.Healthcheck == nil || len(inspect.Config.Healthcheck.Test) == 0 {
+		results = append(results, CheckResult{
+			RuleID:      "DOCKER-CFG-002",
+			RuleName:    "Health Check",
+			Category:    "Docker Configuration",
+			Severity:    "medium",
+			Status:      StatusFailed,
+			Resource:    name,
+			Message:     "Container has no health check",
+			Remediation: "Add HEALTHCHECK in Dockerfile or --health-cmd flag",
+		})
+	}
+
+	// Check read-only root filesystem
+	if !inspect.HostConfig.ReadonlyRootfs {
+		results = append(results, CheckResult{
+			RuleID:      "DOCKER-SEC-006",
+			RuleName:    "Read-Only Root Filesystem",
+			Category:    "Docker Security",
+			Severity:    "medium",
+			Status:      StatusFailed,
+			Resource:    name,
+			Message:     "Container has writable root filesystem",
+			Remediation: "Use --read-only flag",
+		})
+	}
+
+	// Check host mounts
+	for _, m := range inspect.Mounts {
+		if m.Type != mount.TypeBind {
+			continue
+		}
+		mode := "read-only"
+		if m.RW {
+			mode = "read-write"
+		}
+		if isDockerSocket(m.Source) {
 			results = append(results, CheckResult{
-				RuleID:      "DOCKER-SEC-003",
-				RuleName:    "No Host Network",
+				RuleID:      "DOCKER-SEC-007",
+				RuleName:    "No Docker Socket Mount",
 				Category:    "Docker Security",
-				Severity:    "high",
+				Severity:    "critical",
 				Status:      StatusFailed,
 				Resource:    name,
-				Message:     "Container is using host network",
-				Remediation: "Use bridge or custom network",
+				Message:     fmt.Sprintf("Container mounts the Docker socket %s (%s)", m.Source, mode),
+				Remediation: "Remove the docker.sock mount or use a socket proxy that allows only the needed API calls",
 			})
-		}
-
-		// Check host PID
-		if inspect.HostConfig.PidMode == "host" {
+		} else if isSensitiveHostPath(m.Source) {
 			results = append(results, CheckResult{
-				RuleID:      "DOCKER-SEC-004",
-				RuleName:    "No Host PID",
+				RuleID:      "DOCKER-SEC-008",
+				RuleName:    "No Sensitive Host Paths",
 				Category:    "Docker Security",
 				Severity:    "high",
 				Status:      StatusFailed,
 				Resource:    name,
-				Message:     "Container is using host PID namespace",
-				Remediation: "Remove --pid=host flag",
+				Message:     fmt.Sprintf("Container mounts host path %s (%s)", m.Source, mode),
+				Remediation: "Mount only the specific files or directories needed, read-only",
 			})
 		}
+	}
 
-		// Check capabilities
-		if len(inspect.HostConfig.CapAdd) > 0 {
-			for _, cap := range inspect.HostConfig.CapAdd {
-				if isDangerousCap(cap) {
-					results = append(results, CheckResult{
-						RuleID:      "DOCKER-SEC-005",
-						RuleName:    "No Dangerous Capabilities",
-						Category:    "Docker Security",
-						Severity:    "high",
-						Status:      StatusFailed,
-						Resource:    name,
-						Message:     fmt.Sprintf("Container has dangerous capability: %s", cap),
-						Remediation: "Remove unnecessary capabilities",
-					})
-				}
-			}
-		}
+	return results
+}
 
-		// Check memory limits
-		if inspect.HostConfig.Memory == 0 {
-			results = append(results, CheckResult{
-				RuleID:      "DOCKER-RES-001",
-				RuleName:    "Memory Limits",
-				Category:    "Docker Resources",
-				Severity:    "medium",
-				Status:      StatusFailed,
-				Resource:    name,
-				Message:     "Container has no memory limit",
-				Remediation: "Set --memory flag",
-			})
-		}
+// driftResults flags modified binaries and new setuid files in a container.
+// Containers the daemon cannot diff, such as on Windows, are not reported.
+func (c *DockerChecker) driftResults(ctx context.Context, name, id string) []CheckResult {
+	changes, _, err := docker.ContainerDrift(ctx, c.client, id)
+	if err != nil {
+		return nil
+	}
 
-		// Check CPU limits
-		if inspect.HostConfig.CPUQuota == 0 && inspect.HostConfig.NanoCPUs == 0 {
-			results = append(results, CheckResult{
-				RuleID:      "DOCKER-RES-002",
-				RuleName:    "CPU Limits",
-				Category:    "Docker Resources",
-				Severity:    "low",
-				Status:      StatusFailed,
-				Resource:    name,
-				Message:     "Container has no CPU limit",
-				Remediation: "Set --cpus or --cpu-quota flag",
-			})
+	var indicators []docker.FileChange
+	for _, ch := range changes {
+		if ch.Indicator != "" {
+			indicators = append(indicators, ch)
 		}
+	}
+	if len(indicators) == 0 {
+		return nil
+	}
 
-		// Check restart policy
-		if inspect.HostConfig.RestartPolicy.Name == "" || inspect.HostConfig.RestartPolicy.Name == "no" {
-			results = append(results, CheckResult{
-				RuleID:      "DOCKER-CFG-001",
-				RuleName:    "Restart Policy",
-				Category:    "Docker Configuration",
-				Severity:    "low",
-				Status:      StatusFailed,
-				Resource:    name,
-				Message:     "Container has no restart policy",
-				Remediation: "Set --restart=unless-stopped or similar",
-			})
-		}
+	msg := fmt.Sprintf("%s: %s", indicators[0].Indicator, indicators[0].Path)
+	if len(indicators) > 1 {
+		msg += fmt.Sprintf(" and %d more", len(indicators)-1)
+	}
+	return []CheckResult{{
+		RuleID:      "DOCKER-SEC-009",
+		RuleName:    "No Filesystem Drift Indicators",
+		Category:    "Docker Security",
+		Severity:    "high",
+		Status:      StatusFailed,
+		Resource:    name,
+		Message:     msg,
+		Remediation: fmt.Sprintf("Run devops-toolkit docker drift %s to review the changes and redeploy from a rebuilt image", name),
+	}}
+}
 
-		// Check health check
-		if inspect.Config.Healthcheck == nil || len(inspect.Config.Healthcheck.Test) == 0 {
-			results = append(results, CheckResult{
-				RuleID:      "DOCKER-CFG-002",
-				RuleName:    "Health Check",
-				Category:    "Docker Configuration",
-				Severity:    "medium",
-				Status:      StatusFailed,
-				Resource:    name,
-				Message:     "Container has no health check",
-				Remediation: "Add HEALTHCHECK in Dockerfile or --health-cmd flag",
-			})
-		}
+// isDockerSocket reports whether a host path is the Docker daemon socket
+func isDockerSocket(source string) bool {
+	return path.Base(source) == "docker.sock"
+}
 
-		// Check read-only root filesystem
-		if !inspect.HostConfig.ReadonlyRootfs {
-			results = append(results, CheckResult{
-				RuleID:      "DOCKER-SEC-006",
-				RuleName:    "Read-Only Root Filesystem",
-				Category:    "Docker Security",
-				Severity:    "medium",
-				Status:      StatusFailed,
-				Resource:    name,
-				Message:     "Container has writable root filesystem",
-				Remediation: "Use --read-only flag",
-			})
-		}
+// isSensitiveHostPath reports whether a host path gives access to the host root,
+// its configuration or its processes
+func isSensitiveHostPath(source string) bool {
+	switch source = path.Clean(source); {
+	case source == "/", source == "/etc":
+		return true
+	case source == "/proc", strings.HasPrefix(source, "/proc/"):
+		return true
 	}
-
-	return results, nil
+	return false
 }
 
 func (c *DockerChecker) checkImage(ctx context.Context, imageName string) ([]CheckResult, error) {
-	var results []CheckResult
-
 	// Inspect image
 	inspect, _, err := c.client.ImageInspectWithRaw(ctx, imageName)
 	if err != nil {
 		return nil, err
 	}
 
-	resource := imageName
+	return imageResults(imageName, inspect), nil
+}
+
+// imageResults runs the image rules against one inspected image
+func imageResults(resource string, inspect types.ImageInspect) []CheckResult {
+	var results []CheckResult
 
 	// Check for latest tag
 	for _, tag := range inspect.RepoTags {
@@ -303,7 +462,7 @@ func (c *DockerChecker) checkImage(ctx context.Context, imageName string) ([]Che
 		}
 	}
 
-	return results, nil
+	return results
 }
 
 func isDangerousCap(cap string) bool {
@@ -326,3 +485,92 @@ func isDangerousCap(cap string) bool {
 	return false
 }
 
+// checkRunningImages runs the image rules once per image used by running
+// containers and by opts.PodImages. Images are deduplicated by digest, so a
+// tag and a digest reference to the same image are checked once.
+func (c *DockerChecker) checkRunningImages(ctx context.Context) ([]CheckResult, error) {
+	containers, err := c.client.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []CheckResult
+	checked := make(map[string]bool)
+	// check inspects lookup and runs the image rules unless the same image
+	// was checked before; it reports whether the image exists locally
+	check := func(lookup, resource string) bool {
+		inspect, _, err := c.client.ImageInspectWithRaw(ctx, lookup)
+		if err != nil {
+			return false
+		}
+		if !checked[inspect.ID] {
+			results = append(results, imageResults(resource, inspect)...)
+		}
+		checked[inspect.ID] = true
+		for _, repoDigest := range inspect.RepoDigests {
+			if ref, err := parseImageRef(repoDigest); err == nil {
+				checked[ref.Digest] = true
+			}
+		}
+		return true
+	}
+
+	for i, cont := range containers {
+		c.opts.progress("Docker: image %d/%d (%s)", i+1, len(containers), cont.Image)
+		// Look up by ID: the name a container was started with may have moved
+		if !checked[cont.ImageID] {
+			check(cont.ImageID, cont.Image)
+		}
+	}
+
+	for _, img := range c.opts.PodImages {
+		if (img.Digest != "" && checked[img.Digest]) || check(img.Image, img.Image) {
+			continue
+		}
+		// Not pulled on this host: only the reference itself can be checked
+		if img.Digest != "" {
+			checked[img.Digest] = true
+		}
+		results = append(results, referenceResults(img.Image)...)
+	}
+
+	return results, nil
+}
+
+// referenceResults runs the image rules that need only the reference and
+// reports the ones that need the image config as skipped
+func referenceResults(image string) []CheckResult {
+	var results []CheckResult
+
+	ref, err := parseImageRef(image)
+	if err != nil {
+		results = append(results, unparseableImageResult("DOCKER-IMG-001", "No Latest Tag", "Docker Images", image, err))
+	} else if ref.Digest == "" && (ref.Tag == "" || ref.Tag == "latest") {
+		results = append(results, CheckResult{
+			RuleID:      "DOCKER-IMG-001",
+			RuleName:    "No Latest Tag",
+			Category:    "Docker Images",
+			Severity:    "medium",
+			Status:      StatusFailed,
+			Resource:    image,
+			Message:     "Image uses 'latest' tag",
+			Remediation: "Use specific version tags",
+		})
+	}
+
+	for _, rule := range []struct{ id, name, severity string }{
+		{"DOCKER-IMG-002", "Image Size", "low"},
+		{"DOCKER-IMG-003", "Non-Root User in Image", "medium"},
+	} {
+		results = append(results, CheckResult{
+			RuleID:   rule.id,
+			RuleName: rule.name,
+			Category: "Docker Images",
+			Severity: rule.severity,
+			Status:   StatusSkipped,
+			Resource: image,
+			Message:  "Image is not present on this Docker host; pull it to check",
+		})
+	}
+	return results
+}
@@ -0,0 +1,221 @@
+package compliance
+
+import (
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/moby/buildkit/frontend/dockerfile/shell"
+)
+
+// dockerInstruction is a single logical Dockerfile instruction
+type dockerInstruction struct {
+	Cmd     string   // upper-cased instruction keyword
+	Flags   []string // leading --flags, e.g. --mount=type=cache
+	Args    string   // remaining arguments with continuations joined
+	Heredoc string   // heredoc body, if any
+	Line    int      // 1-based line where the instruction starts
+}
+
+// dockerStage is a build stage starting at a FROM instruction
+type dockerStage struct {
+	Index        int
+	Name         string
+	Image        string // base image with build args expanded
+	Line         int
+	Instructions []dockerInstruction
+}
+
+// dockerfile is a parsed Dockerfile
+type dockerfile struct {
+	GlobalArgs map[string]string
+	Stages     []*dockerStage
+
+	lex *shell.Lex
+}
+
+var shellSeparatorRe = regexp.MustCompile(`&&|\|\||;`)
+
+// parseDockerfileFile reads and parses the Dockerfile at path
+func parseDockerfileFile(path string) (*dockerfile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseDockerfile(f)
+}
+
+// parseDockerfile parses Dockerfile content into stages with the BuildKit
+// parser, which handles the escape directive, line continuations, comments
+// inside continuations and heredocs the same way docker build does
+func parseDockerfile(r io.Reader) (*dockerfile, error) {
+	result, err := parser.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	df := &dockerfile{GlobalArgs: make(map[string]string), lex: shell.NewLex(result.EscapeToken)}
+	var current *dockerStage
+	for _, node := range result.AST.Children {
+		inst := newDockerInstruction(node)
+
+		if inst.Cmd == "FROM" {
+			fields := nodeArgs(node)
+			current = &dockerStage{Index: len(df.Stages), Line: inst.Line}
+			if len(fields) > 0 {
+				current.Image = df.expandArgs(fields[0])
+			}
+			if len(fields) >= 3 && strings.EqualFold(fields[1], "AS") {
+				current.Name = strings.ToLower(fields[2])
+			}
+			df.Stages = append(df.Stages, current)
+			continue
+		}
+
+		if current == nil {
+			if inst.Cmd == "ARG" {
+				for _, arg := range nodeArgs(node) {
+					name, value, _ := strings.Cut(arg, "=")
+					df.GlobalArgs[name] = strings.Trim(value, `"'`)
+				}
+			}
+			continue
+		}
+		current.Instructions = append(current.Instructions, inst)
+	}
+
+	return df, nil
+}
+
+func newDockerInstruction(node *parser.Node) dockerInstruction {
+	inst := dockerInstruction{
+		Cmd:   strings.ToUpper(node.Value),
+		Flags: node.Flags,
+		Args:  strings.Join(nodeArgs(node), " "),
+		Line:  node.StartLine,
+	}
+
+	var bodies []string
+	for _, heredoc := range node.Heredocs {
+		bodies = append(bodies, strings.TrimSuffix(heredoc.Content, "\n"))
+	}
+	inst.Heredoc = strings.Join(bodies, "\n")
+
+	return inst
+}
+
+// nodeArgs returns the arguments of an instruction node; shell-form commands
+// are a single argument and exec-form commands one per array element
+func nodeArgs(node *parser.Node) []string {
+	var args []string
+	for n := node.Next; n != nil; n = n.Next {
+		args = append(args, n.Value)
+	}
+	return args
+}
+
+// expandArgs substitutes global build args (with ${VAR:-default} fallbacks) in s
+func (df *dockerfile) expandArgs(s string) string {
+	expanded, err := df.lex.ProcessWordWithMap(s, df.GlobalArgs)
+	if err != nil {
+		return s
+	}
+	return expanded
+}
+
+// stage returns the stage with the given name, if any
+func (df *dockerfile) stage(name string) *dockerStage {
+	name = strings.ToLower(name)
+	for _, s := range df.Stages {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// isStageRef reports whether a FROM image refers to an earlier build stage
+func (df *dockerfile) isStageRef(s *dockerStage) bool {
+	for _, prev := range df.Stages[:s.Index] {
+		if prev.Name != "" && prev.Name == strings.ToLower(s.Image) {
+			return true
+		}
+	}
+	return false
+}
+
+// lastInstruction returns the last instruction of cmd in the stage, following
+// FROM references to earlier stages when the stage does not set it itself
+func (df *dockerfile) lastInstruction(s *dockerStage, cmd string) *dockerInstruction {
+	for depth := 0; s != nil && depth <= len(df.Stages); depth++ {
+		for i := len(s.Instructions) - 1; i >= 0; i-- {
+			if s.Instructions[i].Cmd == cmd {
+				return &s.Instructions[i]
+			}
+		}
+		if !df.isStageRef(s) {
+			return nil
+		}
+		s = df.stage(s.Image)
+	}
+	return nil
+}
+
+// shellCommands splits a RUN command into individual commands on && ; and ||
+func shellCommands(run string) []string {
+	var cmds []string
+	for _, part := range shellSeparatorRe.Split(run, -1) {
+		if part = strings.TrimSpace(part); part != "" {
+			cmds = append(cmds, part)
+		}
+	}
+	return cmds
+}
+
+// unpinnedPackages returns packages installed by apt-get, apk or pip without a version pin
+func unpinnedPackages(cmd string) []string {
+	fields := strings.Fields(cmd)
+
+	var (
+		args []string
+		sep  string
+	)
+	for i := 0; i+1 < len(fields); i++ {
+		switch {
+		case (fields[i] == "apt-get" || fields[i] == "apt") && fields[i+1] == "install":
+			args, sep = fields[i+2:], "="
+		case fields[i] == "apk" && fields[i+1] == "add":
+			args, sep = fields[i+2:], "="
+		case (fields[i] == "pip" || fields[i] == "pip3") && fields[i+1] == "install":
+			args, sep = fields[i+2:], "=="
+		default:
+			continue
+		}
+		break
+	}
+
+	var unpinned []string
+	skipNext := false
+	for _, arg := range args {
+		if skipNext {
+			skipNext = false
+			continue
+		}
+		if arg == "|" || arg == ">" {
+			break
+		}
+		if strings.HasPrefix(arg, "-") {
+			// pip -r/-c and apk -X take a value
+			skipNext = arg == "-r" || arg == "-c" || arg == "-X" || arg == "--repository"
+			continue
+		}
+		if strings.Contains(arg, sep) || strings.ContainsAny(arg, "<>~/$") || strings.HasSuffix(arg, ".deb") || strings.HasSuffix(arg, ".whl") {
+			continue
+		}
+		unpinned = append(unpinned, arg)
+	}
+	return unpinned
+}
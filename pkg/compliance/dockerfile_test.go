@@ -0,0 +1,62 @@
+package compliance
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDockerfile(t *testing.T) {
+	content := "# escape=`\n" +
+		"ARG BASE=golang\n" +
+		"ARG VERSION=\"1.22\"\n" +
+		"FROM ${BASE}:${VERSION} AS build\n" +
+		"RUN --mount=type=cache,target=/go/pkg/mod apt-get update && `\n" +
+		"    # comments inside a continuation are skipped\n" +
+		"    apt-get install -y curl\n" +
+		"RUN <<EOF\n" +
+		"set -e\n" +
+		"go build ./...\n" +
+		"EOF\n" +
+		"FROM build\n" +
+		"USER app\n"
+
+	df, err := parseDockerfile(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("parseDockerfile: %v", err)
+	}
+	if len(df.Stages) != 2 {
+		t.Fatalf("got %d stages, want 2", len(df.Stages))
+	}
+
+	build := df.Stages[0]
+	if build.Image != "golang:1.22" || build.Name != "build" || build.Line != 4 {
+		t.Errorf("first stage = %q as %q at line %d, want golang:1.22 as build at line 4", build.Image, build.Name, build.Line)
+	}
+	if len(build.Instructions) != 2 {
+		t.Fatalf("got %d instructions in first stage, want 2", len(build.Instructions))
+	}
+
+	run := build.Instructions[0]
+	if run.Cmd != "RUN" || run.Line != 5 {
+		t.Errorf("instruction = %s at line %d, want RUN at line 5", run.Cmd, run.Line)
+	}
+	if len(run.Flags) != 1 || run.Flags[0] != "--mount=type=cache,target=/go/pkg/mod" {
+		t.Errorf("flags = %q, want the cache mount", run.Flags)
+	}
+	if !strings.Contains(run.Args, "apt-get update") || !strings.Contains(run.Args, "apt-get install -y curl") {
+		t.Errorf("continuation not joined: %q", run.Args)
+	}
+
+	heredoc := build.Instructions[1]
+	if heredoc.Heredoc != "set -e\ngo build ./..." {
+		t.Errorf("heredoc = %q", heredoc.Heredoc)
+	}
+
+	final := df.Stages[1]
+	if !df.isStageRef(final) {
+		t.Error("FROM build should refer to the earlier stage")
+	}
+	if user := df.lastInstruction(final, "USER"); user == nil || user.Args != "app" {
+		t.Errorf("USER = %+v, want app", user)
+	}
+}
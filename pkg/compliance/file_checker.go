@@ -12,56 +12,161 @@ import (
 
 // FileChecker checks configuration files for compliance
 type FileChecker struct {
-	opts CheckOptions
+	opts   CheckOptions
+	images *imagePolicy
 }
 
 // NewFileChecker creates a new file checker
 func NewFileChecker(opts CheckOptions) *FileChecker {
-	return &FileChecker{opts: opts}
+	return &FileChecker{opts: opts, images: newImagePolicy(opts)}
 }
 
 // Run runs the file compliance checks
 func (c *FileChecker) Run(ctx context.Context) ([]CheckResult, error) {
 	var results []CheckResult
 
+	excludes := append(loadIgnoreFile(c.opts.Path), c.opts.Exclude...)
+
+	// Provenance lookups depend on registry state, so they always run uncached
+	var cache *fileCache
+	if !c.opts.NoCache && !c.opts.VerifyProvenance {
+		cache = loadFileCache()
+	}
+
 	// Walk through files
+	var paths []string
 	err := filepath.Walk(c.opts.Path, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
 
-		if info.IsDir() {
+		rel, relErr := filepath.Rel(c.opts.Path, path)
+		if relErr != nil {
 			return nil
 		}
-
-		// Check Kubernetes manifests
-		if isKubernetesManifest(path) {
-			fileResults, err := c.checkKubernetesManifest(path)
-			if err == nil {
-				results = append(results, fileResults...)
+		if rel == "." {
+			// The walk root is never excluded, but a single-file --path is still checked
+			if info.IsDir() {
+				return nil
 			}
+			rel = filepath.Base(path)
 		}
+		rel = filepath.ToSlash(rel)
 
-		// Check Dockerfiles
-		if isDockerfile(path) {
-			fileResults, err := c.checkDockerfile(path)
-			if err == nil {
-				results = append(results, fileResults...)
+		if matchesAny(excludes, rel, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
 			}
+			return nil
 		}
 
-		// Check docker-compose files
-		if isDockerCompose(path) {
-			fileResults, err := c.checkDockerCompose(path)
-			if err == nil {
-				results = append(results, fileResults...)
-			}
+		if info.IsDir() {
+			return nil
+		}
+
+		if len(c.opts.Include) > 0 && !matchesAny(c.opts.Include, rel, false) {
+			return nil
+		}
+
+		if isCheckableFile(path) {
+			paths = append(paths, path)
 		}
 
 		return nil
 	})
 
-	return results, err
+	// Files are collected first so progress can show the total
+	for i, path := range paths {
+		c.opts.progress("Files: %d/%d scanned (%s)", i+1, len(paths), filepath.Base(path))
+		results = append(results, c.checkFileCached(ctx, cache, path)...)
+	}
+
+	if cache != nil {
+		_ = cache.save()
+	}
+
+	return filterResults(results, c.opts), err
+}
+
+// checkFileCached returns the results for a single file, reusing cached results
+// when the file content has not changed since the last run
+func (c *FileChecker) checkFileCached(ctx context.Context, cache *fileCache, path string) []CheckResult {
+	if !isCheckableFile(path) {
+		return nil
+	}
+	if cache == nil {
+		return c.checkFile(ctx, path)
+	}
+
+	key, err := filepath.Abs(path)
+	if err != nil {
+		return c.checkFile(ctx, path)
+	}
+	hash, err := hashFile(path)
+	if err != nil {
+		return c.checkFile(ctx, path)
+	}
+	// Registry findings depend on the allowlist, so it is part of the cache key
+	if len(c.opts.AllowedRegistries) > 0 {
+		hash += "|" + strings.Join(c.opts.AllowedRegistries, ",")
+	}
+
+	// Cached resources are stored relative to the file path (e.g. ":12") so
+	// results stay correct when the checker runs from a different directory
+	if cached, ok := cache.lookup(key, hash); ok {
+		results := make([]CheckResult, len(cached))
+		for i, r := range cached {
+			r.Resource = path + r.Resource
+			results[i] = r
+		}
+		return results
+	}
+
+	results := c.checkFile(ctx, path)
+	stored := make([]CheckResult, len(results))
+	for i, r := range results {
+		r.Resource = strings.TrimPrefix(r.Resource, path)
+		stored[i] = r
+	}
+	cache.store(key, hash, stored)
+	return results
+}
+
+// checkFile runs every applicable file check against path
+func (c *FileChecker) checkFile(ctx context.Context, path string) []CheckResult {
+	var results []CheckResult
+
+	// Check Kubernetes manifests
+	if isKubernetesManifest(path) {
+		fileResults, err := c.checkKubernetesManifest(ctx, path)
+		if err == nil {
+			results = append(results, fileResults...)
+		}
+	}
+
+	// Check Dockerfiles
+	if isDockerfile(path) {
+		fileResults, err := c.checkDockerfile(ctx, path)
+		if err == nil {
+			results = append(results, fileResults...)
+		}
+	}
+
+	// Check docker-compose files
+	if isDockerCompose(path) {
+		fileResults, err := c.checkDockerCompose(ctx, path)
+		if err == nil {
+			results = append(results, fileResults...)
+		}
+	}
+
+	return results
+}
+
+// isCheckableFile reports whether any file check could apply to path
+func isCheckableFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml" || isDockerfile(path)
 }
 
 func isKubernetesManifest(path string) bool {
@@ -91,7 +196,7 @@ func isDockerCompose(path string) bool {
 		name == "compose.yml" || name == "compose.yaml"
 }
 
-func (c *FileChecker) checkKubernetesManifest(path string) ([]CheckResult, error) {
+func (c *FileChecker) checkKubernetesManifest(ctx context.Context, path string) ([]CheckResult, error) {
 	var results []CheckResult
 	resource := path
 
@@ -127,6 +232,7 @@ func (c *FileChecker) checkKubernetesManifest(path string) ([]CheckResult, error
 		}
 
 		// Check containers
+		images := c.images
 		containers, _ := spec["containers"].([]interface{})
 		for _, c := range containers {
 			container, _ := c.(map[string]interface{})
@@ -147,6 +253,12 @@ func (c *FileChecker) checkKubernetesManifest(path string) ([]CheckResult, error
 				})
 			}
 
+			// Check digest pinning, registry allowlist and provenance
+			if image != "" {
+				results = append(results, images.check(ctx, fileImageRules, "File Compliance", resource,
+					fmt.Sprintf("Container '%s'", containerName), image)...)
+			}
+
 			// Check resources
 			resources, _ := container["resources"].(map[string]interface{})
 			if resources == nil {
@@ -210,117 +322,206 @@ func (c *FileChecker) checkKubernetesManifest(path string) ([]CheckResult, error
 	return results, nil
 }
 
-func (c *FileChecker) checkDockerfile(path string) ([]CheckResult, error) {
+func (c *FileChecker) checkDockerfile(ctx context.Context, path string) ([]CheckResult, error) {
 	var results []CheckResult
-	resource := path
 
-	data, err := os.ReadFile(path)
+	df, err := parseDockerfileFile(path)
 	if err != nil {
 		return nil, err
 	}
+	if len(df.Stages) == 0 {
+		return results, nil
+	}
 
-	content := string(data)
-	lines := strings.Split(content, "\n")
-
-	hasUser := false
-	hasHealthcheck := false
-	usesLatest := false
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		upperLine := strings.ToUpper(line)
-
-		// Check USER directive
-		if strings.HasPrefix(upperLine, "USER ") {
-			hasUser = true
-		}
-
-		// Check HEALTHCHECK
-		if strings.HasPrefix(upperLine, "HEALTHCHECK ") {
-			hasHealthcheck = true
-		}
-
-		// Check FROM with latest
-		if strings.HasPrefix(upperLine, "FROM ") {
-			if strings.HasSuffix(line, ":latest") || !strings.Contains(line, ":") {
-				usesLatest = true
-			}
-		}
-
-		// Check for ADD when COPY could be used
-		if strings.HasPrefix(upperLine, "ADD ") && !strings.Contains(line, "http") && !strings.Contains(line, ".tar") {
-			results = append(results, CheckResult{
-				RuleID:      "FILE-DOCKER-001",
-				RuleName:    "Use COPY Instead of ADD",
-				Category:    "File Compliance",
-				Severity:    "low",
-				Status:      StatusFailed,
-				Resource:    resource,
-				Message:     "Use COPY instead of ADD for local files",
-				Remediation: "Replace ADD with COPY for local files",
-			})
-		}
+	at := func(line int) string {
+		return fmt.Sprintf("%s:%d", path, line)
+	}
 
-		// Check for curl/wget without cleanup
-		if strings.Contains(line, "curl") || strings.Contains(line, "wget") {
-			if !strings.Contains(line, "&&") || !strings.Contains(line, "rm") {
+	for _, stage := range df.Stages {
+		// Check base image tag and provenance, skipping references to earlier stages
+		if !df.isStageRef(stage) && stage.Image != "scratch" && !strings.Contains(stage.Image, "$") {
+			// Unparseable references are reported by the image rules below
+			ref, err := parseImageRef(stage.Image)
+			if err == nil && ref.Digest == "" && (ref.Tag == "" || ref.Tag == "latest") {
 				results = append(results, CheckResult{
-					RuleID:      "FILE-DOCKER-002",
-					RuleName:    "Clean Up Downloads",
+					RuleID:      "FILE-DOCKER-005",
+					RuleName:    "Specific Base Image Tag",
 					Category:    "File Compliance",
-					Severity:    "low",
+					Severity:    "medium",
 					Status:      StatusFailed,
-					Resource:    resource,
-					Message:     "Downloaded files should be cleaned up in same layer",
-					Remediation: "Combine download and cleanup in single RUN command",
+					Resource:    at(stage.Line),
+					Message:     fmt.Sprintf("Base image uses 'latest' or no tag: %s", stage.Image),
+					Remediation: "Use specific version tag for base image",
 				})
 			}
+
+			results = append(results, c.images.check(ctx, fileImageRules, "File Compliance", at(stage.Line),
+				"Base", stage.Image)...)
+		}
+
+		for _, inst := range stage.Instructions {
+			switch inst.Cmd {
+			case "ADD":
+				// Check for ADD when COPY could be used
+				if !strings.Contains(inst.Args, "://") && !strings.Contains(inst.Args, "git@") && !strings.Contains(inst.Args, ".tar") {
+					results = append(results, CheckResult{
+						RuleID:      "FILE-DOCKER-001",
+						RuleName:    "Use COPY Instead of ADD",
+						Category:    "File Compliance",
+						Severity:    "low",
+						Status:      StatusFailed,
+						Resource:    at(inst.Line),
+						Message:     "Use COPY instead of ADD for local files",
+						Remediation: "Replace ADD with COPY for local files",
+					})
+				}
+
+			case "RUN":
+				results = append(results, checkDockerRun(inst, at(inst.Line))...)
+			}
 		}
 	}
 
-	if !hasUser {
+	// USER and HEALTHCHECK only matter for the final (runtime) stage
+	final := df.Stages[len(df.Stages)-1]
+
+	if user := df.lastInstruction(final, "USER"); user == nil {
 		results = append(results, CheckResult{
 			RuleID:      "FILE-DOCKER-003",
 			RuleName:    "USER Directive",
 			Category:    "File Compliance",
 			Severity:    "high",
 			Status:      StatusFailed,
-			Resource:    resource,
-			Message:     "Dockerfile has no USER directive",
+			Resource:    at(final.Line),
+			Message:     "Final stage has no USER directive",
 			Remediation: "Add USER directive to run as non-root",
 		})
+	} else if isRootUser(user.Args) {
+		results = append(results, CheckResult{
+			RuleID:      "FILE-DOCKER-003",
+			RuleName:    "USER Directive",
+			Category:    "File Compliance",
+			Severity:    "high",
+			Status:      StatusFailed,
+			Resource:    at(user.Line),
+			Message:     "Final stage runs as root",
+			Remediation: "Switch to a non-root USER at the end of the final stage",
+		})
 	}
 
-	if !hasHealthcheck {
+	if hc := df.lastInstruction(final, "HEALTHCHECK"); hc == nil || strings.EqualFold(strings.TrimSpace(hc.Args), "NONE") {
 		results = append(results, CheckResult{
 			RuleID:      "FILE-DOCKER-004",
 			RuleName:    "HEALTHCHECK Directive",
 			Category:    "File Compliance",
 			Severity:    "medium",
 			Status:      StatusFailed,
-			Resource:    resource,
-			Message:     "Dockerfile has no HEALTHCHECK",
+			Resource:    at(final.Line),
+			Message:     "Final stage has no HEALTHCHECK",
 			Remediation: "Add HEALTHCHECK directive",
 		})
 	}
 
-	if usesLatest {
+	return results, nil
+}
+
+// checkDockerRun evaluates a RUN instruction for download cleanup, apt cache and package pinning
+func checkDockerRun(inst dockerInstruction, resource string) []CheckResult {
+	var results []CheckResult
+
+	script := inst.Args
+	if inst.Heredoc != "" {
+		script += "\n" + inst.Heredoc
+	}
+	commands := shellCommands(strings.ReplaceAll(script, "\n", ";"))
+
+	cacheMount := false
+	for _, flag := range inst.Flags {
+		if strings.HasPrefix(flag, "--mount=") && strings.Contains(flag, "type=cache") {
+			cacheMount = true
+		}
+	}
+
+	// Check for downloaded files without cleanup in the same layer
+	if downloadsToFile(commands) && !strings.Contains(script, "rm ") {
 		results = append(results, CheckResult{
-			RuleID:      "FILE-DOCKER-005",
-			RuleName:    "Specific Base Image Tag",
+			RuleID:      "FILE-DOCKER-002",
+			RuleName:    "Clean Up Downloads",
 			Category:    "File Compliance",
-			Severity:    "medium",
+			Severity:    "low",
 			Status:      StatusFailed,
 			Resource:    resource,
-			Message:     "Base image uses 'latest' or no tag",
-			Remediation: "Use specific version tag for base image",
+			Message:     "Downloaded files should be cleaned up in same layer",
+			Remediation: "Combine download and cleanup in single RUN command",
 		})
 	}
 
-	return results, nil
+	// Check apt cache cleanup
+	if (strings.Contains(script, "apt-get install") || strings.Contains(script, "apt install")) &&
+		!cacheMount && !strings.Contains(script, "/var/lib/apt/lists") {
+		results = append(results, CheckResult{
+			RuleID:      "FILE-DOCKER-006",
+			RuleName:    "Clean Up Package Cache",
+			Category:    "File Compliance",
+			Severity:    "low",
+			Status:      StatusFailed,
+			Resource:    resource,
+			Message:     "apt-get install without removing /var/lib/apt/lists",
+			Remediation: "Append '&& rm -rf /var/lib/apt/lists/*' or use a cache mount",
+		})
+	}
+
+	// Check pinned package versions
+	var unpinned []string
+	for _, cmd := range commands {
+		unpinned = append(unpinned, unpinnedPackages(cmd)...)
+	}
+	if len(unpinned) > 0 {
+		results = append(results, CheckResult{
+			RuleID:      "FILE-DOCKER-007",
+			RuleName:    "Pinned Package Versions",
+			Category:    "File Compliance",
+			Severity:    "low",
+			Status:      StatusFailed,
+			Resource:    resource,
+			Message:     fmt.Sprintf("Packages installed without pinned versions: %s", strings.Join(unpinned, ", ")),
+			Remediation: "Pin versions, e.g. curl=7.88.1-10 (apt/apk) or requests==2.31.0 (pip)",
+		})
+	}
+
+	return results
+}
+
+// downloadsToFile reports whether any command saves a curl or wget download to disk
+func downloadsToFile(commands []string) bool {
+	for _, cmd := range commands {
+		fields := strings.Fields(cmd)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "curl":
+			for _, f := range fields[1:] {
+				if f == "-o" || f == "-O" || f == "--output" || f == "--remote-name" {
+					return true
+				}
+			}
+		case "wget":
+			if !strings.Contains(cmd, "-O-") && !strings.Contains(cmd, "-qO-") && !strings.Contains(cmd, "-O -") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isRootUser reports whether a USER argument names the root user
+func isRootUser(user string) bool {
+	name, _, _ := strings.Cut(strings.TrimSpace(user), ":")
+	return name == "root" || name == "0"
 }
 
-func (c *FileChecker) checkDockerCompose(path string) ([]CheckResult, error) {
+func (c *FileChecker) checkDockerCompose(ctx context.Context, path string) ([]CheckResult, error) {
 	var results []CheckResult
 	resource := path
 
@@ -382,6 +583,9 @@ func (c *FileChecker) checkDockerCompose(path string) ([]CheckResult, error) {
 
 		// Check image tag
 		if image, ok := service["image"].(string); ok {
+			results = append(results, c.images.check(ctx, fileImageRules, "File Compliance", resource,
+				fmt.Sprintf("Service '%s'", serviceName), image)...)
+
 			if strings.HasSuffix(image, ":latest") || !strings.Contains(image, ":") {
 				results = append(results, CheckResult{
 					RuleID:      "FILE-COMPOSE-004",
@@ -0,0 +1,60 @@
+package compliance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const latestPodManifest = `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+    - name: web
+      image: nginx:latest
+`
+
+func TestFileCheckerSingleFilePath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pod.yaml")
+	if err := os.WriteFile(path, []byte(latestPodManifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     CheckOptions
+		wantRule bool
+	}{
+		{"checked", CheckOptions{}, true},
+		{"excluded by base name", CheckOptions{Exclude: []string{"pod.yaml"}}, false},
+		{"included by base name", CheckOptions{Include: []string{"*.yaml"}}, true},
+		{"not included", CheckOptions{Include: []string{"*.yml"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.Path = path
+			opts.NoCache = true
+			results, err := NewFileChecker(opts).Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if got := hasResult(results, "FILE-K8S-001", StatusFailed); got != tt.wantRule {
+				t.Errorf("FILE-K8S-001 failed = %v, want %v (results: %+v)", got, tt.wantRule, results)
+			}
+		})
+	}
+}
+
+func hasResult(results []CheckResult, ruleID string, status CheckStatus) bool {
+	for _, r := range results {
+		if r.RuleID == ruleID && r.Status == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package compliance
+
+// filterResults applies the skip, only and minimum severity options to results
+func filterResults(results []CheckResult, opts CheckOptions) []CheckResult {
+	if len(opts.SkipRules) == 0 && len(opts.OnlyRules) == 0 && opts.MinSeverity == "" {
+		return results
+	}
+
+	var filtered []CheckResult
+	for _, r := range results {
+		// Skip rules
+		skip := false
+		for _, skipRule := range opts.SkipRules {
+			if r.RuleID == skipRule {
+				skip = true
+				break
+			}
+		}
+		if skip {
+			continue
+		}
+
+		// Only rules
+		if len(opts.OnlyRules) > 0 {
+			found := false
+			for _, onlyRule := range opts.OnlyRules {
+				if r.RuleID == onlyRule {
+					found = true
+					break
+				}
+			}
+			if !found {
+				continue
+			}
+		}
+
+		// Min severity
+		if opts.MinSeverity != "" && !MeetsMinSeverity(r.Severity, opts.MinSeverity) {
+			continue
+		}
+
+		filtered = append(filtered, r)
+	}
+
+	return filtered
+}
+
+// MeetsMinSeverity reports whether severity is at or above minSeverity
+func MeetsMinSeverity(severity, minSeverity string) bool {
+	levels := map[string]int{
+		"low":      1,
+		"medium":   2,
+		"high":     3,
+		"critical": 4,
+	}
+
+	return levels[severity] >= levels[minSeverity]
+}
@@ -0,0 +1,260 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/gitlabclient"
+)
+
+// GitLab role levels as used by protected branch access settings
+const (
+	gitlabDeveloper  = 30
+	gitlabMaintainer = 40
+)
+
+// tokenMaxLifetime is how far ahead a token may expire before it is flagged
+const tokenMaxLifetime = 365 * 24 * time.Hour
+
+// GitLabChecker checks GitLab project settings for compliance
+type GitLabChecker struct {
+	opts     CheckOptions
+	client   *gitlabclient.Client
+	projects []string
+}
+
+// NewGitLabChecker creates a new GitLab checker for the given projects
+func NewGitLabChecker(client *gitlabclient.Client, projects []string, opts CheckOptions) *GitLabChecker {
+	return &GitLabChecker{opts: opts, client: client, projects: projects}
+}
+
+// Run checks branch protection, approvals, push rules and tokens of each project
+func (c *GitLabChecker) Run(ctx context.Context) ([]CheckResult, error) {
+	var results []CheckResult
+
+	for _, project := range c.projects {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		sec, err := c.client.GetProjectSecurity(ctx, project)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, gitlabProjectResults(sec, time.Now())...)
+	}
+
+	// The token in use is only checked when it is a personal or project access token
+	if token, err := c.client.CurrentToken(ctx); err == nil {
+		results = append(results, tokenResults(*token, "current token", time.Now())...)
+	}
+
+	return filterResults(results, c.opts), nil
+}
+
+// gitlabProjectResults evaluates the settings of one project
+func gitlabProjectResults(sec *gitlabclient.ProjectSecurity, now time.Time) []CheckResult {
+	var results []CheckResult
+	result := func(id string, status CheckStatus, resource, message string) {
+		policy := gitlabPolicy(id)
+		r := CheckResult{
+			RuleID:   id,
+			RuleName: policy.Name,
+			Category: policy.Category,
+			Severity: policy.Severity,
+			Status:   status,
+			Resource: resource,
+			Message:  message,
+		}
+		if status == StatusFailed {
+			r.Remediation = policy.Remediation
+		}
+		results = append(results, r)
+	}
+
+	branch := sec.DefaultBranch
+	branchResource := sec.Project + "@" + branch
+
+	// Default branch protection
+	if reason, ok := sec.Unavailable["protected_branches"]; ok {
+		result("GITLAB-SEC-001", StatusSkipped, branchResource, "Protected branches unavailable: "+reason)
+	} else if protection := protectionFor(sec.ProtectedBranches, branch); protection == nil {
+		result("GITLAB-SEC-001", StatusFailed, branchResource, fmt.Sprintf("%s is not a protected branch", branch))
+	} else {
+		result("GITLAB-SEC-001", StatusPassed, branchResource, fmt.Sprintf("%s is protected by %s", branch, protection.Name))
+
+		if protection.AllowForcePush {
+			result("GITLAB-SEC-002", StatusFailed, branchResource, fmt.Sprintf("%s allows force push", branch))
+		} else {
+			result("GITLAB-SEC-002", StatusPassed, branchResource, fmt.Sprintf("%s does not allow force push", branch))
+		}
+
+		if protection.PushAccessLevel != 0 && protection.PushAccessLevel < gitlabMaintainer {
+			result("GITLAB-SEC-003", StatusFailed, branchResource,
+				fmt.Sprintf("%s allows direct push by %s", branch, roleName(protection.PushAccessLevel)))
+		} else {
+			result("GITLAB-SEC-003", StatusPassed, branchResource, fmt.Sprintf("Direct push to %s is restricted", branch))
+		}
+
+		if protection.CodeOwnerApprovalRequired {
+			result("GITLAB-SEC-005", StatusPassed, branchResource, "CODEOWNERS approval required")
+		} else {
+			result("GITLAB-SEC-005", StatusFailed, branchResource, "No CODEOWNERS approval required")
+		}
+	}
+
+	// Approvals
+	if sec.Approvals == nil {
+		reason := sec.Unavailable["approvals"]
+		for _, id := range []string{"GITLAB-SEC-004", "GITLAB-SEC-006", "GITLAB-SEC-007"} {
+			result(id, StatusSkipped, sec.Project, "Approval settings unavailable: "+reason)
+		}
+	} else {
+		required := requiredApprovals(sec.Approvals, branch)
+		if required == 0 {
+			result("GITLAB-SEC-004", StatusFailed, branchResource, fmt.Sprintf("No approvals required to merge into %s", branch))
+		} else {
+			result("GITLAB-SEC-004", StatusPassed, branchResource, fmt.Sprintf("%d approvals required to merge into %s", required, branch))
+		}
+
+		switch {
+		case sec.Approvals.AuthorCanApprove:
+			result("GITLAB-SEC-006", StatusFailed, sec.Project, "Merge request authors can approve their own changes")
+		case sec.Approvals.CommittersCanApprove:
+			result("GITLAB-SEC-006", StatusFailed, sec.Project, "Committers can approve merge requests they contributed to")
+		default:
+			result("GITLAB-SEC-006", StatusPassed, sec.Project, "Authors and committers cannot approve")
+		}
+
+		switch {
+		case !sec.Approvals.ResetApprovalsOnPush:
+			result("GITLAB-SEC-007", StatusFailed, sec.Project, "Approvals are kept when new commits are pushed")
+		case sec.Approvals.OverridableApprovers:
+			result("GITLAB-SEC-007", StatusFailed, sec.Project, "Approval rules can be edited per merge request")
+		default:
+			result("GITLAB-SEC-007", StatusPassed, sec.Project, "Approvals reset on push and cannot be overridden")
+		}
+	}
+
+	// Push rules
+	if sec.PushRules == nil {
+		result("GITLAB-SEC-008", StatusSkipped, sec.Project, "Push rules unavailable: "+sec.Unavailable["push_rules"])
+	} else if sec.PushRules.PreventSecrets {
+		result("GITLAB-SEC-008", StatusPassed, sec.Project, "Pushing secret files is rejected")
+	} else {
+		result("GITLAB-SEC-008", StatusFailed, sec.Project, "Push rules do not reject secret files")
+	}
+
+	// Project access tokens
+	for _, token := range sec.Tokens {
+		results = append(results, tokenResults(token, fmt.Sprintf("%s token %s", sec.Project, token.Name), now)...)
+	}
+
+	return results
+}
+
+// tokenResults evaluates the scopes and lifetime of a token
+func tokenResults(token gitlabclient.TokenInfo, resource string, now time.Time) []CheckResult {
+	var results []CheckResult
+	result := func(id string, status CheckStatus, message string) {
+		policy := gitlabPolicy(id)
+		r := CheckResult{
+			RuleID:   id,
+			RuleName: policy.Name,
+			Category: policy.Category,
+			Severity: policy.Severity,
+			Status:   status,
+			Resource: resource,
+			Message:  message,
+		}
+		if status == StatusFailed {
+			r.Remediation = policy.Remediation
+		}
+		results = append(results, r)
+	}
+
+	var broad []string
+	for _, scope := range token.Scopes {
+		switch scope {
+		case "api", "sudo", "admin_mode", "write_repository", "write_registry":
+			broad = append(broad, scope)
+		}
+	}
+	switch {
+	case len(broad) > 0 && token.Kind == "project" && token.AccessLevel >= gitlabMaintainer:
+		result("GITLAB-TOKEN-001", StatusFailed,
+			fmt.Sprintf("Token has write scopes at maintainer level or above: %s", strings.Join(broad, ", ")))
+	case containsString(broad, "sudo") || containsString(broad, "admin_mode"):
+		result("GITLAB-TOKEN-001", StatusFailed, "Token has admin scopes: "+strings.Join(broad, ", "))
+	case len(broad) > 0:
+		result("GITLAB-TOKEN-001", StatusWarning, "Token has write scopes: "+strings.Join(broad, ", "))
+	default:
+		result("GITLAB-TOKEN-001", StatusPassed, "Token is read-only: "+strings.Join(token.Scopes, ", "))
+	}
+
+	switch {
+	case token.ExpiresAt == nil:
+		result("GITLAB-TOKEN-002", StatusFailed, "Token never expires")
+	case token.ExpiresAt.Sub(now) > tokenMaxLifetime:
+		result("GITLAB-TOKEN-002", StatusFailed, fmt.Sprintf("Token expires in more than a year (%s)", token.ExpiresAt.Format("2006-01-02")))
+	default:
+		result("GITLAB-TOKEN-002", StatusPassed, "Token expires "+token.ExpiresAt.Format("2006-01-02"))
+	}
+
+	return results
+}
+
+// protectionFor returns the protected branch entry matching branch, exact names first
+func protectionFor(branches []gitlabclient.ProtectedBranchInfo, branch string) *gitlabclient.ProtectedBranchInfo {
+	for i := range branches {
+		if branches[i].Name == branch {
+			return &branches[i]
+		}
+	}
+	for i := range branches {
+		if matched, _ := path.Match(branches[i].Name, branch); matched {
+			return &branches[i]
+		}
+	}
+	return nil
+}
+
+// requiredApprovals returns the approvals needed to merge into branch
+func requiredApprovals(approvals *gitlabclient.ApprovalSettings, branch string) int {
+	required := approvals.ApprovalsBeforeMerge
+	for _, rule := range approvals.Rules {
+		applies := rule.AllBranches
+		for _, b := range rule.ProtectedBranches {
+			if matched, _ := path.Match(b, branch); matched || b == branch {
+				applies = true
+			}
+		}
+		if applies && rule.ApprovalsRequired > required {
+			required = rule.ApprovalsRequired
+		}
+	}
+	return required
+}
+
+func roleName(level int) string {
+	switch {
+	case level >= 50:
+		return "owners"
+	case level >= gitlabMaintainer:
+		return "maintainers"
+	case level >= gitlabDeveloper:
+		return "developers"
+	default:
+		return "guests and reporters"
+	}
+}
+
+// gitlabPolicy returns the built-in policy for a GitLab rule ID
+func gitlabPolicy(id string) Policy {
+	if policy, ok := LookupPolicy(id); ok {
+		return policy
+	}
+	return Policy{ID: id, Name: id, Category: "GitLab", Severity: "medium"}
+}
@@ -0,0 +1,93 @@
+package compliance
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFileName is the name of the ignore file read from the root of a file check
+const IgnoreFileName = ".complianceignore"
+
+// loadIgnoreFile reads exclude patterns from a .complianceignore file.
+// Blank lines and lines starting with '#' are skipped.
+func loadIgnoreFile(root string) []string {
+	f, err := os.Open(filepath.Join(root, IgnoreFileName))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
+
+// matchesAny reports whether the slash-separated relative path matches any pattern
+func matchesAny(patterns []string, rel string, isDir bool) bool {
+	for _, p := range patterns {
+		if matchPattern(p, rel, isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern matches a gitignore-style glob against a relative path.
+// Patterns without a slash match any single path element; patterns with a
+// slash are anchored at the root and also match everything beneath them.
+// A trailing slash restricts the pattern to directories, and a trailing
+// "/**" matches everything inside a directory.
+func matchPattern(pattern, rel string, isDir bool) bool {
+	pattern = filepath.ToSlash(pattern)
+	// Anchoring is decided before "/**" is stripped so "deploy/**" stays rooted
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/**")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if pattern == "" {
+		return false
+	}
+
+	elements := strings.Split(rel, "/")
+
+	if !anchored {
+		for i, elem := range elements {
+			// The last element is only a directory when the path itself is one
+			if dirOnly && i == len(elements)-1 && !isDir {
+				continue
+			}
+			if ok, _ := filepath.Match(pattern, elem); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	anyDepth := strings.HasPrefix(pattern, "**/")
+	pattern = strings.TrimPrefix(pattern, "**/")
+	for i := range elements {
+		for j := i + 1; j <= len(elements); j++ {
+			if dirOnly && j == len(elements) && !isDir {
+				continue
+			}
+			if ok, _ := filepath.Match(pattern, strings.Join(elements[i:j], "/")); ok {
+				return true
+			}
+		}
+		// Anchored patterns only match from the root
+		if !anyDepth {
+			break
+		}
+	}
+	return false
+}
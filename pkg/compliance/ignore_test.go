@@ -0,0 +1,30 @@
+package compliance
+
+import "testing"
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		rel     string
+		isDir   bool
+		want    bool
+	}{
+		{"*.yaml", "a/b/deploy.yaml", false, true},
+		{"vendor", "charts/vendor/x.yaml", false, true},
+		{"fixtures/", "test/fixtures", true, true},
+		{"fixtures/", "test/fixtures", false, false},
+		{"deploy/**", "deploy/x.yaml", false, true},
+		{"deploy/**", "a/deploy/x", false, false},
+		{"/deploy", "deploy/x.yaml", false, true},
+		{"/deploy", "a/deploy/x.yaml", false, false},
+		{"charts/*/templates", "charts/app/templates/svc.yaml", false, true},
+		{"**/generated", "a/b/generated/x.yaml", false, true},
+		{"", "x.yaml", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := matchPattern(tt.pattern, tt.rel, tt.isDir); got != tt.want {
+			t.Errorf("matchPattern(%q, %q, %v) = %v, want %v", tt.pattern, tt.rel, tt.isDir, got, tt.want)
+		}
+	}
+}
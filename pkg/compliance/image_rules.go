@@ -0,0 +1,292 @@
+package compliance
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+)
+
+// imageRef is a parsed container image reference
+type imageRef struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// parseImageRef splits an image reference into registry, repository, tag and
+// digest with Docker's normalization, so "nginx" is docker.io/library/nginx
+func parseImageRef(image string) (imageRef, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return imageRef{}, fmt.Errorf("unparseable image reference %q: %w", image, err)
+	}
+
+	ref := imageRef{Registry: reference.Domain(named), Repository: reference.Path(named)}
+	if tagged, ok := named.(reference.Tagged); ok {
+		ref.Tag = tagged.Tag()
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		ref.Digest = digested.Digest().String()
+	}
+	return ref, nil
+}
+
+// unparseableImageResult reports an image reference the rules cannot evaluate,
+// rather than judging it by an empty registry or tag
+func unparseableImageResult(ruleID, ruleName, category, resource string, err error) CheckResult {
+	return CheckResult{
+		RuleID:      ruleID,
+		RuleName:    ruleName,
+		Category:    category,
+		Severity:    "medium",
+		Status:      StatusFailed,
+		Resource:    resource,
+		Message:     err.Error(),
+		Remediation: "Use a valid image reference such as registry/name:tag or name@sha256:<digest>",
+	}
+}
+
+// imageRuleIDs holds the rule IDs used by a checker for the image provenance rules
+type imageRuleIDs struct {
+	Digest     string
+	Registry   string
+	Provenance string
+}
+
+var (
+	k8sImageRules  = imageRuleIDs{Digest: "K8S-IMG-003", Registry: "K8S-IMG-004", Provenance: "K8S-IMG-005"}
+	fileImageRules = imageRuleIDs{Digest: "FILE-IMG-001", Registry: "FILE-IMG-002", Provenance: "FILE-IMG-003"}
+)
+
+// imagePolicy evaluates digest pinning, registry allowlist and provenance rules
+type imagePolicy struct {
+	allowed  []string
+	verify   bool
+	creds    map[string]RegistryCredential
+	http     *http.Client
+	verified map[string]bool
+}
+
+func newImagePolicy(opts CheckOptions) *imagePolicy {
+	return &imagePolicy{
+		allowed:  opts.AllowedRegistries,
+		verify:   opts.VerifyProvenance,
+		creds:    opts.RegistryCredentials,
+		http:     &http.Client{Timeout: 10 * time.Second},
+		verified: make(map[string]bool),
+	}
+}
+
+// check evaluates image against the image rules and returns any findings
+func (p *imagePolicy) check(ctx context.Context, ids imageRuleIDs, category, resource, subject, image string) []CheckResult {
+	var results []CheckResult
+	ref, err := parseImageRef(image)
+	if err != nil {
+		return []CheckResult{unparseableImageResult(ids.Digest, "Image Pinned by Digest", category, resource, err)}
+	}
+
+	if ref.Digest == "" {
+		results = append(results, CheckResult{
+			RuleID:      ids.Digest,
+			RuleName:    "Image Pinned by Digest",
+			Category:    category,
+			Severity:    "medium",
+			Status:      StatusFailed,
+			Resource:    resource,
+			Message:     fmt.Sprintf("%s image is not pinned by digest: %s", subject, image),
+			Remediation: "Reference the image as name@sha256:<digest>",
+		})
+	}
+
+	if len(p.allowed) > 0 && !p.registryAllowed(ref) {
+		results = append(results, CheckResult{
+			RuleID:      ids.Registry,
+			RuleName:    "Approved Registry",
+			Category:    category,
+			Severity:    "high",
+			Status:      StatusFailed,
+			Resource:    resource,
+			Message:     fmt.Sprintf("%s image comes from unapproved registry %s: %s", subject, ref.Registry, image),
+			Remediation: fmt.Sprintf("Mirror the image into an approved registry (%s)", strings.Join(p.allowed, ", ")),
+		})
+	}
+
+	if p.verify && ref.Digest != "" && !p.hasAttestation(ctx, ref) {
+		results = append(results, CheckResult{
+			RuleID:      ids.Provenance,
+			RuleName:    "Image Provenance",
+			Category:    category,
+			Severity:    "medium",
+			Status:      StatusFailed,
+			Resource:    resource,
+			Message:     fmt.Sprintf("%s image has no signature or attestation: %s", subject, image),
+			Remediation: "Sign the image and attach provenance with cosign",
+		})
+	}
+
+	return results
+}
+
+// registryAllowed reports whether the image matches an allowlist entry.
+// Entries may be a bare registry ("ghcr.io") or a registry path prefix ("ghcr.io/myorg").
+func (p *imagePolicy) registryAllowed(ref imageRef) bool {
+	full := ref.Registry + "/" + ref.Repository
+	for _, entry := range p.allowed {
+		entry = strings.TrimSuffix(strings.TrimSpace(entry), "/")
+		if entry == "" {
+			continue
+		}
+		if entry == ref.Registry || strings.HasPrefix(full, entry+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAttestation looks for cosign signature or attestation tags for a pinned image
+func (p *imagePolicy) hasAttestation(ctx context.Context, ref imageRef) bool {
+	key := ref.Registry + "/" + ref.Repository + "@" + ref.Digest
+	if found, ok := p.verified[key]; ok {
+		return found
+	}
+
+	found := false
+	prefix := strings.Replace(ref.Digest, ":", "-", 1)
+	for _, suffix := range []string{".att", ".sig"} {
+		if p.manifestExists(ctx, ref, prefix+suffix) {
+			found = true
+			break
+		}
+	}
+
+	p.verified[key] = found
+	return found
+}
+
+// manifestExists checks a registry for a tag, authenticating when challenged
+// (configured credentials, or an anonymous bearer token)
+func (p *imagePolicy) manifestExists(ctx context.Context, ref imageRef, tag string) bool {
+	host := ref.Registry
+	if host == "docker.io" {
+		host = "registry-1.docker.io"
+	}
+	url := fmt.Sprintf("https://%s/v2/%s/manifests/%s", host, ref.Repository, tag)
+
+	resp, err := p.headManifest(ctx, url, "")
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		auth := p.authorization(ctx, ref.Registry, resp.Header.Get("WWW-Authenticate"))
+		if auth == "" {
+			return false
+		}
+		resp, err = p.headManifest(ctx, url, auth)
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+	}
+
+	return resp.StatusCode == http.StatusOK
+}
+
+func (p *imagePolicy) headManifest(ctx context.Context, url, auth string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join([]string{
+		"application/vnd.oci.image.manifest.v1+json",
+		"application/vnd.oci.image.index.v1+json",
+		"application/vnd.docker.distribution.manifest.v2+json",
+	}, ", "))
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	return p.http.Do(req)
+}
+
+// authorization answers a WWW-Authenticate challenge with an Authorization header value
+func (p *imagePolicy) authorization(ctx context.Context, registry, challenge string) string {
+	cred, hasCred := p.creds[registry]
+
+	if strings.HasPrefix(challenge, "Basic ") {
+		if !hasCred {
+			return ""
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(cred.Username+":"+cred.Password))
+	}
+
+	var credential *RegistryCredential
+	if hasCred {
+		credential = &cred
+	}
+	token := p.bearerToken(ctx, challenge, credential)
+	if token == "" {
+		return ""
+	}
+	return "Bearer " + token
+}
+
+var challengeParamRe = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// bearerToken requests a pull token from the realm named in a Bearer challenge,
+// anonymously when cred is nil
+func (p *imagePolicy) bearerToken(ctx context.Context, challenge string, cred *RegistryCredential) string {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return ""
+	}
+
+	params := make(map[string]string)
+	for _, m := range challengeParamRe.FindAllStringSubmatch(challenge, -1) {
+		params[m[1]] = m[2]
+	}
+	if params["realm"] == "" {
+		return ""
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, params["realm"], nil)
+	if err != nil {
+		return ""
+	}
+	q := req.URL.Query()
+	if params["service"] != "" {
+		q.Set("service", params["service"])
+	}
+	if params["scope"] != "" {
+		q.Set("scope", params["scope"])
+	}
+	req.URL.RawQuery = q.Encode()
+	if cred != nil {
+		req.SetBasicAuth(cred.Username, cred.Password)
+	}
+
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return ""
+	}
+	if body.Token != "" {
+		return body.Token
+	}
+	return body.AccessToken
+}
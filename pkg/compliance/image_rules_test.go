@@ -0,0 +1,53 @@
+package compliance
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseImageRef(t *testing.T) {
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	tests := []struct {
+		image string
+		want  imageRef
+	}{
+		{"nginx", imageRef{Registry: "docker.io", Repository: "library/nginx"}},
+		{"nginx:1.25", imageRef{Registry: "docker.io", Repository: "library/nginx", Tag: "1.25"}},
+		{"index.docker.io/org/app:v1", imageRef{Registry: "docker.io", Repository: "org/app", Tag: "v1"}},
+		{"registry.local:5000/team/app", imageRef{Registry: "registry.local:5000", Repository: "team/app"}},
+		{"registry.local:5000/team/app:2.0", imageRef{Registry: "registry.local:5000", Repository: "team/app", Tag: "2.0"}},
+		{"localhost:5000/app@" + digest, imageRef{Registry: "localhost:5000", Repository: "app", Digest: digest}},
+		{"ghcr.io/org/app:v1@" + digest, imageRef{Registry: "ghcr.io", Repository: "org/app", Tag: "v1", Digest: digest}},
+	}
+
+	for _, tt := range tests {
+		got, err := parseImageRef(tt.image)
+		if err != nil {
+			t.Errorf("parseImageRef(%q): %v", tt.image, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseImageRef(%q) = %+v, want %+v", tt.image, got, tt.want)
+		}
+	}
+
+	for _, image := range []string{"${BASE_IMAGE}", "Nginx:latest", "app:tag:extra", ""} {
+		if _, err := parseImageRef(image); err == nil {
+			t.Errorf("parseImageRef(%q) succeeded, want an error", image)
+		}
+	}
+}
+
+func TestImagePolicyUnparseableReference(t *testing.T) {
+	policy := newImagePolicy(CheckOptions{AllowedRegistries: []string{"ghcr.io"}})
+
+	results := policy.check(context.Background(), k8sImageRules, "Kubernetes Images", "default/web", "Container", "${IMAGE}")
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want a single unparseable reference finding: %+v", len(results), results)
+	}
+	if r := results[0]; r.RuleID != "K8S-IMG-003" || r.Status != StatusFailed || !strings.Contains(r.Message, "unparseable image reference") {
+		t.Errorf("result = %+v", r)
+	}
+}
@@ -3,87 +3,75 @@ package compliance
 import (
 	"context"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 // K8sChecker checks Kubernetes resources for compliance
 type K8sChecker struct {
 	opts      CheckOptions
-	clientset *kubernetes.Clientset
+	clientset kubernetes.Interface
+	// step names the running check in progress updates
+	step string
 }
 
-// NewK8sChecker creates a new Kubernetes checker
-func NewK8sChecker(opts CheckOptions) *K8sChecker {
-	return &K8sChecker{opts: opts}
+// NewK8sChecker creates a Kubernetes checker that uses client, so it honours
+// the same kubeconfig, context and in-cluster config as other commands
+func NewK8sChecker(client *k8s.Client, opts CheckOptions) *K8sChecker {
+	return &K8sChecker{opts: opts, clientset: client.Clientset()}
 }
 
 // Run runs the Kubernetes compliance checks
 func (c *K8sChecker) Run(ctx context.Context) ([]CheckResult, error) {
-	if err := c.initClient(); err != nil {
-		return nil, err
+	checks := []struct {
+		name string
+		run  func(context.Context) ([]CheckResult, error)
+	}{
+		{"pod security", c.checkPodSecurity},
+		{"containers", c.checkContainers},
+		{"resource limits", c.checkResourceLimits},
+		{"network policies", c.checkNetworkPolicies},
+		{"RBAC", c.checkRBAC},
+		{"secrets", c.checkSecrets},
 	}
 
 	var results []CheckResult
-
-	// Pod security checks
-	podResults, err := c.checkPodSecurity(ctx)
-	if err == nil {
-		results = append(results, podResults...)
-	}
-
-	// Container checks
-	containerResults, err := c.checkContainers(ctx)
-	if err == nil {
-		results = append(results, containerResults...)
-	}
-
-	// Resource limit checks
-	resourceResults, err := c.checkResourceLimits(ctx)
-	if err == nil {
-		results = append(results, resourceResults...)
-	}
-
-	// Network policy checks
-	networkResults, err := c.checkNetworkPolicies(ctx)
-	if err == nil {
-		results = append(results, networkResults...)
-	}
-
-	// RBAC checks
-	rbacResults, err := c.checkRBAC(ctx)
-	if err == nil {
-		results = append(results, rbacResults...)
+	for i, check := range checks {
+		c.step = fmt.Sprintf("%s (%d/%d)", check.name, i+1, len(checks))
+		c.opts.progress("Kubernetes: %s...", c.step)
+		checkResults, err := check.run(ctx)
+		if err == nil {
+			results = append(results, checkResults...)
+		}
 	}
 
-	return c.filterResults(results), nil
+	return filterResults(results, c.opts), nil
 }
 
-func (c *K8sChecker) initClient() error {
-	kubeconfig := os.Getenv("KUBECONFIG")
-	if kubeconfig == "" {
-		home, _ := os.UserHomeDir()
-		kubeconfig = filepath.Join(home, ".kube", "config")
-	}
-
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
-	if err != nil {
-		return err
+// namespaceProgress returns a function to call for each pod in turn that
+// reports how many of the namespaces in scope have been reached; pods are
+// listed in namespace order
+func (c *K8sChecker) namespaceProgress(pods []corev1.Pod) func(namespace string) {
+	total := make(map[string]bool)
+	for _, pod := range pods {
+		if c.opts.Namespaces.Matches(pod.Namespace) {
+			total[pod.Namespace] = true
+		}
 	}
-
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		return err
+	seen := make(map[string]bool)
+	return func(namespace string) {
+		if seen[namespace] {
+			return
+		}
+		seen[namespace] = true
+		c.opts.progress("Kubernetes: %s, namespace %d/%d (%s)", c.step, len(seen), len(total), namespace)
 	}
-
-	c.clientset = clientset
-	return nil
 }
 
 func (c *K8sChecker) checkPodSecurity(ctx context.Context) ([]CheckResult, error) {
@@ -94,8 +82,14 @@ func (c *K8sChecker) checkPodSecurity(ctx context.Context) ([]CheckResult, error
 		return nil, err
 	}
 
+	progress := c.namespaceProgress(pods.Items)
 	for _, pod := range pods.Items {
+		if !c.opts.Namespaces.Matches(pod.Namespace) {
+			continue
+		}
+		progress(pod.Namespace)
 		resource := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+		start := len(results)
 
 		// Check for privileged containers
 		for _, container := range pod.Spec.Containers {
@@ -178,6 +172,7 @@ func (c *K8sChecker) checkPodSecurity(ctx context.Context) ([]CheckResult, error
 				Remediation: "Set hostPID to false",
 			})
 		}
+		setGroup(results[start:], pod.Namespace)
 	}
 
 	return results, nil
@@ -191,8 +186,16 @@ func (c *K8sChecker) checkContainers(ctx context.Context) ([]CheckResult, error)
 		return nil, err
 	}
 
+	images := newImagePolicy(c.opts)
+
+	progress := c.namespaceProgress(pods.Items)
 	for _, pod := range pods.Items {
+		if !c.opts.Namespaces.Matches(pod.Namespace) {
+			continue
+		}
+		progress(pod.Namespace)
 		resource := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+		start := len(results)
 
 		for _, container := range pod.Spec.Containers {
 			// Check for latest tag
@@ -209,6 +212,10 @@ func (c *K8sChecker) checkContainers(ctx context.Context) ([]CheckResult, error)
 				})
 			}
 
+			// Check digest pinning, registry allowlist and provenance
+			results = append(results, images.check(ctx, k8sImageRules, "Kubernetes Supply Chain", resource,
+				fmt.Sprintf("Container '%s'", container.Name), container.Image)...)
+
 			// Check image pull policy
 			if container.ImagePullPolicy == corev1.PullAlways {
 				results = append(results, CheckResult{
@@ -250,6 +257,7 @@ func (c *K8sChecker) checkContainers(ctx context.Context) ([]CheckResult, error)
 				})
 			}
 		}
+		setGroup(results[start:], pod.Namespace)
 	}
 
 	return results, nil
@@ -263,8 +271,14 @@ func (c *K8sChecker) checkResourceLimits(ctx context.Context) ([]CheckResult, er
 		return nil, err
 	}
 
+	progress := c.namespaceProgress(pods.Items)
 	for _, pod := range pods.Items {
+		if !c.opts.Namespaces.Matches(pod.Namespace) {
+			continue
+		}
+		progress(pod.Namespace)
 		resource := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+		start := len(results)
 
 		for _, container := range pod.Spec.Containers {
 			// Check CPU limits
@@ -323,6 +337,7 @@ func (c *K8sChecker) checkResourceLimits(ctx context.Context) ([]CheckResult, er
 				})
 			}
 		}
+		setGroup(results[start:], pod.Namespace)
 	}
 
 	return results, nil
@@ -337,18 +352,24 @@ func (c *K8sChecker) checkNetworkPolicies(ctx context.Context) ([]CheckResult, e
 		return nil, err
 	}
 
+	var inScope []string
 	for _, ns := range namespaces.Items {
 		// Skip system namespaces
 		if strings.HasPrefix(ns.Name, "kube-") {
 			continue
 		}
 
-		if c.opts.Namespace != "" && ns.Name != c.opts.Namespace {
+		if (c.opts.Namespace != "" && ns.Name != c.opts.Namespace) || !c.opts.Namespaces.Matches(ns.Name) {
 			continue
 		}
+		inScope = append(inScope, ns.Name)
+	}
+
+	for i, ns := range inScope {
+		c.opts.progress("Kubernetes: %s, namespace %d/%d (%s)", c.step, i+1, len(inScope), ns)
 
 		// Check if namespace has network policies
-		policies, err := c.clientset.NetworkingV1().NetworkPolicies(ns.Name).List(ctx, metav1.ListOptions{})
+		policies, err := c.clientset.NetworkingV1().NetworkPolicies(ns).List(ctx, metav1.ListOptions{})
 		if err != nil {
 			continue
 		}
@@ -360,8 +381,9 @@ func (c *K8sChecker) checkNetworkPolicies(ctx context.Context) ([]CheckResult, e
 				Category:    "Kubernetes Network",
 				Severity:    "medium",
 				Status:      StatusFailed,
-				Resource:    ns.Name,
-				Message:     fmt.Sprintf("Namespace '%s' has no NetworkPolicies", ns.Name),
+				Resource:    ns,
+				Group:       ns,
+				Message:     fmt.Sprintf("Namespace '%s' has no NetworkPolicies", ns),
 				Remediation: "Define NetworkPolicies to restrict pod traffic",
 			})
 		} else {
@@ -371,8 +393,9 @@ func (c *K8sChecker) checkNetworkPolicies(ctx context.Context) ([]CheckResult, e
 				Category: "Kubernetes Network",
 				Severity: "medium",
 				Status:   StatusPassed,
-				Resource: ns.Name,
-				Message:  fmt.Sprintf("Namespace '%s' has %d NetworkPolicies", ns.Name, len(policies.Items)),
+				Resource: ns,
+				Group:    ns,
+				Message:  fmt.Sprintf("Namespace '%s' has %d NetworkPolicies", ns, len(policies.Items)),
 			})
 		}
 	}
@@ -380,6 +403,62 @@ func (c *K8sChecker) checkNetworkPolicies(ctx context.Context) ([]CheckResult, e
 	return results, nil
 }
 
+// secretExpiryWarning is how close to expiry a TLS secret fails K8S-SECRET-001
+const secretExpiryWarning = 30 * 24 * time.Hour
+
+func (c *K8sChecker) checkSecrets(ctx context.Context) ([]CheckResult, error) {
+	secrets, err := k8s.CollectSecretUsage(ctx, c.clientset, c.opts.Namespace, c.opts.Namespaces.Matches)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []CheckResult
+	for _, secret := range secrets {
+		resource := secret.Namespace + "/" + secret.Name
+		start := len(results)
+
+		if !secret.TLSNotAfter.IsZero() {
+			result := CheckResult{
+				RuleID:   "K8S-SECRET-001",
+				RuleName: "TLS Certificate Expiry",
+				Category: "Kubernetes Secrets",
+				Severity: "high",
+				Status:   StatusPassed,
+				Resource: resource,
+				Message:  fmt.Sprintf("Certificate valid until %s", secret.TLSNotAfter.Format("2006-01-02")),
+			}
+			switch {
+			case time.Now().After(secret.TLSNotAfter):
+				result.Status = StatusFailed
+				result.Severity = "critical"
+				result.Message = fmt.Sprintf("Certificate expired on %s", secret.TLSNotAfter.Format("2006-01-02"))
+				result.Remediation = "Renew the certificate or let cert-manager manage the secret"
+			case secret.TLSExpiresWithin(secretExpiryWarning):
+				result.Status = StatusFailed
+				result.Message = fmt.Sprintf("Certificate expires in %d days", int(time.Until(secret.TLSNotAfter).Hours()/24))
+				result.Remediation = "Renew the certificate or let cert-manager manage the secret"
+			}
+			results = append(results, result)
+		}
+
+		if secret.Unreferenced {
+			results = append(results, CheckResult{
+				RuleID:      "K8S-SECRET-002",
+				RuleName:    "Unreferenced Secrets",
+				Category:    "Kubernetes Secrets",
+				Severity:    "low",
+				Status:      StatusWarning,
+				Resource:    resource,
+				Message:     fmt.Sprintf("Secret '%s' (%s) is not referenced by any pod, service account or ingress", secret.Name, secret.Type),
+				Remediation: "Delete the secret or document the external consumer",
+			})
+		}
+		setGroup(results[start:], secret.Namespace)
+	}
+
+	return results, nil
+}
+
 func (c *K8sChecker) checkRBAC(ctx context.Context) ([]CheckResult, error) {
 	var results []CheckResult
 
@@ -409,61 +488,212 @@ func (c *K8sChecker) checkRBAC(ctx context.Context) ([]CheckResult, error) {
 		}
 	}
 
-	return results, nil
-}
+	// Binding analysis needs every binding plus the rules of the roles they
+	// reference. Rules whose inputs cannot be listed are reported as skipped
+	// rather than evaluated against partial data.
+	roleBindings, err := c.clientset.RbacV1().RoleBindings(c.opts.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return append(results, skippedRBACResults("rolebindings", err, nil,
+			"K8S-RBAC-002", "K8S-RBAC-003", "K8S-RBAC-004", "K8S-RBAC-005")...), nil
+	}
+
+	skipped := make(map[string]bool)
+	var skippedResults []CheckResult
 
-func (c *K8sChecker) filterResults(results []CheckResult) []CheckResult {
-	if len(c.opts.SkipRules) == 0 && len(c.opts.OnlyRules) == 0 && c.opts.MinSeverity == "" {
-		return results
+	clusterRoleRules := make(map[string][]rbacv1.PolicyRule)
+	clusterRoles, err := c.clientset.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		skippedResults = append(skippedResults, skippedRBACResults("clusterroles", err, skipped,
+			"K8S-RBAC-002", "K8S-RBAC-003", "K8S-RBAC-004")...)
+	} else {
+		for _, role := range clusterRoles.Items {
+			clusterRoleRules[role.Name] = role.Rules
+		}
 	}
 
-	var filtered []CheckResult
-	for _, r := range results {
-		// Skip rules
-		skip := false
-		for _, skipRule := range c.opts.SkipRules {
-			if r.RuleID == skipRule {
-				skip = true
-				break
-			}
+	// Cluster-wide secrets access (K8S-RBAC-003) only comes from ClusterRoles
+	roleRules := make(map[string][]rbacv1.PolicyRule)
+	roles, err := c.clientset.RbacV1().Roles(c.opts.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		skippedResults = append(skippedResults, skippedRBACResults("roles", err, skipped,
+			"K8S-RBAC-002", "K8S-RBAC-004")...)
+	} else {
+		for _, role := range roles.Items {
+			roleRules[role.Namespace+"/"+role.Name] = role.Rules
 		}
-		if skip {
+	}
+
+	var analyzed []CheckResult
+
+	// Cluster-wide bindings
+	for _, binding := range bindings.Items {
+		if strings.HasPrefix(binding.Name, "system:") || binding.RoleRef.Name == "cluster-admin" {
 			continue
 		}
 
-		// Only rules
-		if len(c.opts.OnlyRules) > 0 {
-			found := false
-			for _, onlyRule := range c.opts.OnlyRules {
-				if r.RuleID == onlyRule {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
+		rules := clusterRoleRules[binding.RoleRef.Name]
+		analyzed = append(analyzed, analyzeRBACBinding(binding.Name, "ClusterRoleBinding", binding.RoleRef, binding.Subjects, rules, true)...)
+	}
+
+	// Namespaced bindings
+	for _, binding := range roleBindings.Items {
+		if !c.opts.Namespaces.Matches(binding.Namespace) {
+			continue
+		}
+		if strings.HasPrefix(binding.Name, "system:") || binding.RoleRef.Name == "cluster-admin" {
+			continue
+		}
+
+		var rules []rbacv1.PolicyRule
+		if binding.RoleRef.Kind == "ClusterRole" {
+			rules = clusterRoleRules[binding.RoleRef.Name]
+		} else {
+			rules = roleRules[binding.Namespace+"/"+binding.RoleRef.Name]
+		}
+
+		resource := fmt.Sprintf("%s/%s", binding.Namespace, binding.Name)
+		bindingResults := analyzeRBACBinding(resource, "RoleBinding", binding.RoleRef, binding.Subjects, rules, false)
+		setGroup(bindingResults, binding.Namespace)
+		analyzed = append(analyzed, bindingResults...)
+	}
+
+	// A skipped rule never also reports findings from the partial data
+	for _, r := range analyzed {
+		if !skipped[r.RuleID] {
+			results = append(results, r)
+		}
+	}
+	return append(results, skippedResults...), nil
+}
+
+// skippedRBACResults reports the given binding analysis rules as skipped when
+// the roles or bindings they need cannot be listed, so a Forbidden list is not
+// mistaken for a clean cluster. Rules already in skipped are not repeated and
+// newly skipped rules are added to it when it is non-nil.
+func skippedRBACResults(resource string, err error, skipped map[string]bool, ruleIDs ...string) []CheckResult {
+	var results []CheckResult
+	for _, p := range GetBuiltinPolicies() {
+		if !contains(ruleIDs, p.ID) || skipped[p.ID] {
+			continue
+		}
+		if skipped != nil {
+			skipped[p.ID] = true
+		}
+		results = append(results, CheckResult{
+			RuleID:   p.ID,
+			RuleName: p.Name,
+			Category: p.Category,
+			Severity: p.Severity,
+			Status:   StatusSkipped,
+			Resource: resource,
+			Message:  fmt.Sprintf("Could not list %s: %v", resource, err),
+		})
+	}
+	return results
+}
+
+// analyzeRBACBinding inspects the rules granted through a single binding
+func analyzeRBACBinding(resource, kind string, roleRef rbacv1.RoleRef, subjects []rbacv1.Subject, rules []rbacv1.PolicyRule, clusterWide bool) []CheckResult {
+	var results []CheckResult
+	role := fmt.Sprintf("%s '%s'", roleRef.Kind, roleRef.Name)
+
+	wildcard := false
+	secretsAccess := false
+	var escalation []string
+
+	for _, rule := range rules {
+		if contains(rule.Verbs, "*") || contains(rule.Resources, "*") {
+			wildcard = true
+		}
+
+		if clusterWide && ruleGrantsResource(rule, "secrets") &&
+			(contains(rule.Verbs, "get") || contains(rule.Verbs, "list") || contains(rule.Verbs, "*")) {
+			secretsAccess = true
+		}
+
+		for _, verb := range []string{"escalate", "bind", "impersonate"} {
+			if contains(rule.Verbs, verb) && !contains(escalation, verb) {
+				escalation = append(escalation, verb)
 			}
 		}
+	}
+
+	if wildcard {
+		results = append(results, CheckResult{
+			RuleID:      "K8S-RBAC-002",
+			RuleName:    "Wildcard Permissions",
+			Category:    "Kubernetes RBAC",
+			Severity:    "high",
+			Status:      StatusFailed,
+			Resource:    resource,
+			Message:     fmt.Sprintf("%s '%s' grants wildcard verbs or resources via %s", kind, resource, role),
+			Remediation: fmt.Sprintf("Replace '*' in %s with the explicit verbs and resources the subjects need", role),
+		})
+	}
+
+	if secretsAccess {
+		results = append(results, CheckResult{
+			RuleID:      "K8S-RBAC-003",
+			RuleName:    "Cluster-Wide Secrets Access",
+			Category:    "Kubernetes RBAC",
+			Severity:    "high",
+			Status:      StatusFailed,
+			Resource:    resource,
+			Message:     fmt.Sprintf("%s '%s' allows reading secrets in all namespaces", kind, resource),
+			Remediation: fmt.Sprintf("Replace '%s' with namespaced RoleBindings, or restrict %s with resourceNames", resource, role),
+		})
+	}
+
+	if len(escalation) > 0 {
+		results = append(results, CheckResult{
+			RuleID:      "K8S-RBAC-004",
+			RuleName:    "Privilege Escalation Verbs",
+			Category:    "Kubernetes RBAC",
+			Severity:    "critical",
+			Status:      StatusFailed,
+			Resource:    resource,
+			Message:     fmt.Sprintf("%s '%s' grants %s via %s", kind, resource, strings.Join(escalation, ", "), role),
+			Remediation: fmt.Sprintf("Remove the %s verbs from %s unless the subjects manage RBAC", strings.Join(escalation, "/"), role),
+		})
+	}
 
-		// Min severity
-		if c.opts.MinSeverity != "" && !meetsMinSeverity(r.Severity, c.opts.MinSeverity) {
+	for _, subject := range subjects {
+		if subject.Kind != rbacv1.GroupKind || strings.HasPrefix(subject.Name, "system:") {
 			continue
 		}
 
-		filtered = append(filtered, r)
+		results = append(results, CheckResult{
+			RuleID:      "K8S-RBAC-005",
+			RuleName:    "External Group Subjects",
+			Category:    "Kubernetes RBAC",
+			Severity:    "medium",
+			Status:      StatusFailed,
+			Resource:    resource,
+			Message:     fmt.Sprintf("%s '%s' binds external group '%s' to %s", kind, resource, subject.Name, role),
+			Remediation: fmt.Sprintf("Confirm membership of group '%s' is managed by your identity provider, or bind individual users in '%s'", subject.Name, resource),
+		})
 	}
 
-	return filtered
+	return results
 }
 
-func meetsMinSeverity(severity, minSeverity string) bool {
-	levels := map[string]int{
-		"low":      1,
-		"medium":   2,
-		"high":     3,
-		"critical": 4,
-	}
+func ruleGrantsResource(rule rbacv1.PolicyRule, resource string) bool {
+	coreGroup := len(rule.APIGroups) == 0 || contains(rule.APIGroups, "") || contains(rule.APIGroups, "*")
+	return coreGroup && (contains(rule.Resources, resource) || contains(rule.Resources, "*"))
+}
 
-	return levels[severity] >= levels[minSeverity]
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
 }
 
+// setGroup records the namespace or project that owns results
+func setGroup(results []CheckResult, group string) {
+	for i := range results {
+		results[i].Group = group
+	}
+}
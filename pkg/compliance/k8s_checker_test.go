@@ -0,0 +1,107 @@
+package compliance
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// rbacFixtures binds an external group to cluster-admin, a wildcard Role and an
+// escalating ClusterRole. The cluster-admin binding is only reported by K8S-RBAC-001.
+func rbacFixtures() []runtime.Object {
+	wildcard := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"*"}, Verbs: []string{"*"}}}
+	escalate := []rbacv1.PolicyRule{{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles"}, Verbs: []string{"bind"}}}
+	group := []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "platform-team"}}
+
+	return []runtime.Object{
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "binder"}, Rules: escalate},
+		&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: "admin", Namespace: "apps"}, Rules: wildcard},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "ops-admin"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects:   group,
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "binders"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "binder"},
+			Subjects:   group,
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "apps-admin", Namespace: "apps"},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "admin"},
+			Subjects:   group,
+		},
+	}
+}
+
+func forbidList(resource string) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gr := schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: resource}
+		return true, nil, apierrors.NewForbidden(gr, "", errors.New("RBAC: access denied"))
+	}
+}
+
+func TestCheckRBACPartialAccess(t *testing.T) {
+	tests := []struct {
+		name      string
+		forbidden string
+		want      []string
+	}{
+		{
+			name: "full access",
+			want: []string{"K8S-RBAC-001 failed", "K8S-RBAC-002 failed", "K8S-RBAC-004 failed",
+				"K8S-RBAC-005 failed", "K8S-RBAC-005 failed"},
+		},
+		{
+			name:      "roles forbidden",
+			forbidden: "roles",
+			want: []string{"K8S-RBAC-001 failed", "K8S-RBAC-002 skipped", "K8S-RBAC-004 skipped",
+				"K8S-RBAC-005 failed", "K8S-RBAC-005 failed"},
+		},
+		{
+			name:      "cluster roles forbidden",
+			forbidden: "clusterroles",
+			want: []string{"K8S-RBAC-001 failed", "K8S-RBAC-002 skipped", "K8S-RBAC-003 skipped",
+				"K8S-RBAC-004 skipped", "K8S-RBAC-005 failed", "K8S-RBAC-005 failed"},
+		},
+		{
+			name:      "role bindings forbidden",
+			forbidden: "rolebindings",
+			want: []string{"K8S-RBAC-001 failed", "K8S-RBAC-002 skipped", "K8S-RBAC-003 skipped",
+				"K8S-RBAC-004 skipped", "K8S-RBAC-005 skipped"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(rbacFixtures()...)
+			if tt.forbidden != "" {
+				clientset.PrependReactor("list", tt.forbidden, forbidList(tt.forbidden))
+			}
+			c := &K8sChecker{clientset: clientset}
+
+			results, err := c.checkRBAC(context.Background())
+			if err != nil {
+				t.Fatalf("checkRBAC: %v", err)
+			}
+
+			var got []string
+			for _, r := range results {
+				got = append(got, r.RuleID+" "+string(r.Status))
+			}
+			sort.Strings(got)
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("results = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
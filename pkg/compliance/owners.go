@@ -0,0 +1,117 @@
+package compliance
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Group-by modes for GroupResults
+const (
+	GroupByNamespace = "namespace"
+	GroupByTeam      = "team"
+)
+
+// Team is an owner of namespaces or compose projects
+type Team struct {
+	// SlackChannel is the channel failures are posted to, e.g. "#payments"
+	SlackChannel string `yaml:"slack_channel"`
+	// SlackWebhookURL overrides notify.slack_webhook_url for this team;
+	// secret references are accepted
+	SlackWebhookURL string `yaml:"slack_webhook_url"`
+}
+
+// Owners maps namespaces and compose projects to the teams that own them
+type Owners struct {
+	// Groups maps a namespace or compose project, globs allowed, to a team
+	Groups map[string]string `yaml:"groups"`
+	Teams  map[string]Team   `yaml:"teams"`
+
+	patterns []string
+}
+
+// LoadOwners reads and validates an ownership file
+func LoadOwners(file string) (*Owners, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read owners file: %w", err)
+	}
+
+	var owners Owners
+	if err := yaml.Unmarshal(data, &owners); err != nil {
+		return nil, fmt.Errorf("failed to parse owners file %s: %w", file, err)
+	}
+
+	// Teams need no entry under teams: to be grouped, only to be notified
+	for pattern := range owners.Groups {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern %q: %w", file, pattern, err)
+		}
+		owners.patterns = append(owners.patterns, pattern)
+	}
+	// Longest pattern first so "payments-*" beats "*"
+	sort.Slice(owners.patterns, func(i, j int) bool {
+		if len(owners.patterns[i]) != len(owners.patterns[j]) {
+			return len(owners.patterns[i]) > len(owners.patterns[j])
+		}
+		return owners.patterns[i] < owners.patterns[j]
+	})
+
+	return &owners, nil
+}
+
+// TeamFor returns the team owning group, or "" if nobody does
+func (o *Owners) TeamFor(group string) string {
+	if o == nil || group == "" {
+		return ""
+	}
+	if team, ok := o.Groups[group]; ok {
+		return team
+	}
+	for _, pattern := range o.patterns {
+		if ok, _ := path.Match(pattern, group); ok {
+			return o.Groups[pattern]
+		}
+	}
+	return ""
+}
+
+// GroupSummary is the score of one namespace, compose project or team
+type GroupSummary struct {
+	Name    string        `json:"name"`
+	Team    string        `json:"team,omitempty"`
+	Summary ReportSummary `json:"summary"`
+}
+
+// GroupResults summarizes results per namespace or compose project
+// (GroupByNamespace) or per owning team (GroupByTeam), lowest score first.
+// Results without a group or owner are summarized under "".
+func GroupResults(results []CheckResult, by string, owners *Owners) []GroupSummary {
+	byName := make(map[string][]CheckResult)
+	for _, r := range results {
+		name := r.Group
+		if by == GroupByTeam {
+			name = owners.TeamFor(r.Group)
+		}
+		byName[name] = append(byName[name], r)
+	}
+
+	groups := make([]GroupSummary, 0, len(byName))
+	for name, groupResults := range byName {
+		g := GroupSummary{Name: name, Summary: Summarize(groupResults)}
+		if by == GroupByNamespace {
+			g.Team = owners.TeamFor(name)
+		}
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Summary.Score != groups[j].Summary.Score {
+			return groups[i].Summary.Score < groups[j].Summary.Score
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
@@ -1,5 +1,17 @@
 package compliance
 
+import "strings"
+
+// LookupPolicy returns the built-in policy with the given rule ID, ignoring case
+func LookupPolicy(id string) (Policy, bool) {
+	for _, policy := range GetBuiltinPolicies() {
+		if strings.EqualFold(policy.ID, id) {
+			return policy, true
+		}
+	}
+	return Policy{}, false
+}
+
 // GetBuiltinPolicies returns all built-in compliance policies
 func GetBuiltinPolicies() []Policy {
 	return []Policy{
@@ -54,6 +66,32 @@ func GetBuiltinPolicies() []Policy {
 			Description: "Images should use specific tags instead of 'latest'",
 			Remediation: "Use specific version tags for container images",
 		},
+
+		// Kubernetes Supply Chain
+		{
+			ID:          "K8S-IMG-003",
+			Name:        "Image Pinned by Digest",
+			Category:    "Kubernetes Supply Chain",
+			Severity:    "medium",
+			Description: "Container images should be referenced by immutable digest",
+			Remediation: "Reference images as name@sha256:<digest>",
+		},
+		{
+			ID:          "K8S-IMG-004",
+			Name:        "Approved Registry",
+			Category:    "Kubernetes Supply Chain",
+			Severity:    "high",
+			Description: "Container images should come from an approved registry (--allowed-registries)",
+			Remediation: "Mirror images into an approved registry",
+		},
+		{
+			ID:          "K8S-IMG-005",
+			Name:        "Image Provenance",
+			Category:    "Kubernetes Supply Chain",
+			Severity:    "medium",
+			Description: "Pinned images should carry a signature or attestation (--verify-provenance)",
+			Remediation: "Sign images and attach provenance with cosign",
+		},
 		{
 			ID:          "K8S-PROBE-001",
 			Name:        "Liveness Probe",
@@ -108,6 +146,56 @@ func GetBuiltinPolicies() []Policy {
 			Description: "Avoid granting cluster-admin role to non-system users",
 			Remediation: "Use more restrictive roles",
 		},
+		{
+			ID:          "K8S-RBAC-002",
+			Name:        "Wildcard Permissions",
+			Category:    "Kubernetes RBAC",
+			Severity:    "high",
+			Description: "Roles should not grant wildcard verbs or resources",
+			Remediation: "List explicit verbs and resources in role rules",
+		},
+		{
+			ID:          "K8S-RBAC-003",
+			Name:        "Cluster-Wide Secrets Access",
+			Category:    "Kubernetes RBAC",
+			Severity:    "high",
+			Description: "Avoid granting get/list on secrets across all namespaces",
+			Remediation: "Use namespaced RoleBindings or resourceNames",
+		},
+		{
+			ID:          "K8S-RBAC-004",
+			Name:        "Privilege Escalation Verbs",
+			Category:    "Kubernetes RBAC",
+			Severity:    "critical",
+			Description: "Roles should not grant escalate, bind or impersonate",
+			Remediation: "Remove escalate, bind and impersonate verbs",
+		},
+		{
+			ID:          "K8S-RBAC-005",
+			Name:        "External Group Subjects",
+			Category:    "Kubernetes RBAC",
+			Severity:    "medium",
+			Description: "Bindings to external groups delegate access to the identity provider",
+			Remediation: "Review group membership or bind individual users",
+		},
+
+		// Kubernetes Secrets
+		{
+			ID:          "K8S-SECRET-001",
+			Name:        "TLS Certificate Expiry",
+			Category:    "Kubernetes Secrets",
+			Severity:    "high",
+			Description: "TLS secrets should not hold expired or soon-to-expire certificates",
+			Remediation: "Renew the certificate or let cert-manager manage the secret",
+		},
+		{
+			ID:          "K8S-SECRET-002",
+			Name:        "Unreferenced Secrets",
+			Category:    "Kubernetes Secrets",
+			Severity:    "low",
+			Description: "Secrets that no workload, service account or ingress references should be removed",
+			Remediation: "Delete the secret or document the external consumer",
+		},
 
 		// Docker Security
 		{
@@ -158,6 +246,30 @@ func GetBuiltinPolicies() []Policy {
 			Description: "Container root filesystem should be read-only",
 			Remediation: "Use --read-only flag",
 		},
+		{
+			ID:          "DOCKER-SEC-007",
+			Name:        "No Docker Socket Mount",
+			Category:    "Docker Security",
+			Severity:    "critical",
+			Description: "Containers should not mount the Docker socket, which grants root on the host",
+			Remediation: "Remove the docker.sock mount or use a socket proxy",
+		},
+		{
+			ID:          "DOCKER-SEC-008",
+			Name:        "No Sensitive Host Paths",
+			Category:    "Docker Security",
+			Severity:    "high",
+			Description: "Containers should not bind-mount /, /etc or /proc from the host",
+			Remediation: "Mount only the specific paths needed, read-only",
+		},
+		{
+			ID:          "DOCKER-SEC-009",
+			Name:        "No Filesystem Drift Indicators",
+			Category:    "Docker Security",
+			Severity:    "high",
+			Description: "Containers should not have modified binaries or new setuid files compared to their image",
+			Remediation: "Investigate with devops-toolkit docker drift and redeploy from a rebuilt image",
+		},
 
 		// Docker Resources
 		{
@@ -246,6 +358,30 @@ func GetBuiltinPolicies() []Policy {
 			Description: "Kubernetes manifests should define security context",
 			Remediation: "Add securityContext",
 		},
+		{
+			ID:          "FILE-IMG-001",
+			Name:        "Image Pinned by Digest in Files",
+			Category:    "File Compliance",
+			Severity:    "medium",
+			Description: "Manifests, Dockerfiles and Compose files should pin images by digest",
+			Remediation: "Reference images as name@sha256:<digest>",
+		},
+		{
+			ID:          "FILE-IMG-002",
+			Name:        "Approved Registry in Files",
+			Category:    "File Compliance",
+			Severity:    "high",
+			Description: "Images in files should come from an approved registry (--allowed-registries)",
+			Remediation: "Mirror images into an approved registry",
+		},
+		{
+			ID:          "FILE-IMG-003",
+			Name:        "Image Provenance in Files",
+			Category:    "File Compliance",
+			Severity:    "medium",
+			Description: "Pinned images in files should carry a signature or attestation (--verify-provenance)",
+			Remediation: "Sign images and attach provenance with cosign",
+		},
 		{
 			ID:          "FILE-DOCKER-003",
 			Name:        "USER in Dockerfile",
@@ -262,6 +398,22 @@ func GetBuiltinPolicies() []Policy {
 			Description: "Dockerfiles should define a HEALTHCHECK",
 			Remediation: "Add HEALTHCHECK directive",
 		},
+		{
+			ID:          "FILE-DOCKER-006",
+			Name:        "Package Cache Cleanup in Dockerfile",
+			Category:    "File Compliance",
+			Severity:    "low",
+			Description: "apt-get install layers should remove /var/lib/apt/lists or use a cache mount",
+			Remediation: "Append '&& rm -rf /var/lib/apt/lists/*' to the RUN instruction",
+		},
+		{
+			ID:          "FILE-DOCKER-007",
+			Name:        "Pinned Package Versions in Dockerfile",
+			Category:    "File Compliance",
+			Severity:    "low",
+			Description: "Packages installed with apt-get, apk or pip should pin versions",
+			Remediation: "Pin package versions for reproducible builds",
+		},
 		{
 			ID:          "FILE-COMPOSE-001",
 			Name:        "No Privileged in Compose",
@@ -270,6 +422,90 @@ func GetBuiltinPolicies() []Policy {
 			Description: "Docker Compose services should not be privileged",
 			Remediation: "Remove privileged: true",
 		},
+
+		// GitLab Security
+		{
+			ID:          "GITLAB-SEC-001",
+			Name:        "Default Branch Protected",
+			Category:    "GitLab Security",
+			Severity:    "critical",
+			Description: "The default branch should be a protected branch",
+			Remediation: "Protect the default branch under Settings > Repository > Protected branches",
+		},
+		{
+			ID:          "GITLAB-SEC-002",
+			Name:        "No Force Push",
+			Category:    "GitLab Security",
+			Severity:    "high",
+			Description: "Protected branches should not allow force push",
+			Remediation: "Disable 'Allowed to force push' on the protected branch",
+		},
+		{
+			ID:          "GITLAB-SEC-003",
+			Name:        "Restricted Push Access",
+			Category:    "GitLab Security",
+			Severity:    "medium",
+			Description: "Only maintainers should push directly to the default branch",
+			Remediation: "Set 'Allowed to push and merge' to Maintainers or No one",
+		},
+		{
+			ID:          "GITLAB-SEC-004",
+			Name:        "Required Approvals",
+			Category:    "GitLab Security",
+			Severity:    "high",
+			Description: "Merge requests into the default branch should require approval",
+			Remediation: "Add an approval rule requiring at least one approval",
+		},
+		{
+			ID:          "GITLAB-SEC-005",
+			Name:        "Code Owner Approval",
+			Category:    "GitLab Security",
+			Severity:    "medium",
+			Description: "Changes to owned paths should require CODEOWNERS approval",
+			Remediation: "Enable 'Code owner approval' on the protected branch and add a CODEOWNERS file",
+		},
+		{
+			ID:          "GITLAB-SEC-006",
+			Name:        "No Self-Approval",
+			Category:    "GitLab Security",
+			Severity:    "medium",
+			Description: "Merge request authors and committers should not approve their own changes",
+			Remediation: "Disable author and committer approval in merge request approval settings",
+		},
+		{
+			ID:          "GITLAB-SEC-007",
+			Name:        "Approval Integrity",
+			Category:    "GitLab Security",
+			Severity:    "low",
+			Description: "Approvals should reset on new commits and not be editable per merge request",
+			Remediation: "Enable 'Remove all approvals when commits are added' and 'Prevent editing approval rules in merge requests'",
+		},
+		{
+			ID:          "GITLAB-SEC-008",
+			Name:        "Secret Push Rule",
+			Category:    "GitLab Security",
+			Severity:    "medium",
+			Description: "Push rules should reject files that are likely to contain secrets",
+			Remediation: "Enable 'Prevent pushing secret files' in push rules",
+		},
+
+		// GitLab Tokens
+		{
+			ID:          "GITLAB-TOKEN-001",
+			Name:        "Token Scopes",
+			Category:    "GitLab Tokens",
+			Severity:    "high",
+			Description: "Access tokens should have the narrowest scopes and role they need",
+			Remediation: "Recreate the token with read-only scopes or a lower role",
+		},
+		{
+			ID:          "GITLAB-TOKEN-002",
+			Name:        "Token Expiry",
+			Category:    "GitLab Tokens",
+			Severity:    "medium",
+			Description: "Access tokens should expire within a year",
+			Remediation: "Recreate the token with an expiry date and rotate it regularly",
+		},
 	}
 }
 
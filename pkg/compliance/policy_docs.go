@@ -0,0 +1,741 @@
+package compliance
+
+import "fmt"
+
+// PolicyDoc is the extended documentation of a policy shown by compliance explain
+type PolicyDoc struct {
+	Rationale  string            `yaml:"rationale" json:"rationale"`
+	Examples   []PolicyExample   `yaml:"examples,omitempty" json:"examples,omitempty"`
+	References []PolicyReference `yaml:"references,omitempty" json:"references,omitempty"`
+}
+
+// PolicyExample is a compliant snippet; Language is yaml, dockerfile or shell
+type PolicyExample struct {
+	Title    string `yaml:"title" json:"title"`
+	Language string `yaml:"language" json:"language"`
+	Code     string `yaml:"code" json:"code"`
+}
+
+// PolicyReference links a policy to a benchmark section or vendor documentation
+type PolicyReference struct {
+	Title string `yaml:"title" json:"title"`
+	URL   string `yaml:"url" json:"url"`
+}
+
+const (
+	cisKubernetesURL = "https://www.cisecurity.org/benchmark/kubernetes"
+	cisDockerURL     = "https://www.cisecurity.org/benchmark/docker"
+)
+
+func cisKubernetes(section, title string) PolicyReference {
+	return PolicyReference{Title: fmt.Sprintf("CIS Kubernetes Benchmark v1.8, %s %s", section, title), URL: cisKubernetesURL}
+}
+
+func cisDocker(section, title string) PolicyReference {
+	return PolicyReference{Title: fmt.Sprintf("CIS Docker Benchmark v1.2.0, %s %s", section, title), URL: cisDockerURL}
+}
+
+func nsaKubernetes(section string) PolicyReference {
+	return PolicyReference{
+		Title: "NSA/CISA Kubernetes Hardening Guide v1.2, " + section,
+		URL:   "https://media.defense.gov/2022/Aug/29/2003066362/-1/-1/0/CTR_KUBERNETES_HARDENING_GUIDANCE_1.2_20220829.PDF",
+	}
+}
+
+var (
+	refPodSecurityStandards = PolicyReference{Title: "Kubernetes: Pod Security Standards", URL: "https://kubernetes.io/docs/concepts/security/pod-security-standards/"}
+	refSecurityContext      = PolicyReference{Title: "Kubernetes: Configure a Security Context", URL: "https://kubernetes.io/docs/tasks/configure-pod-container/security-context/"}
+	refImages               = PolicyReference{Title: "Kubernetes: Images", URL: "https://kubernetes.io/docs/concepts/containers/images/"}
+	refProbes               = PolicyReference{Title: "Kubernetes: Configure Liveness, Readiness and Startup Probes", URL: "https://kubernetes.io/docs/tasks/configure-pod-container/configure-liveness-readiness-startup-probes/"}
+	refResources            = PolicyReference{Title: "Kubernetes: Resource Management for Pods and Containers", URL: "https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/"}
+	refNetworkPolicies      = PolicyReference{Title: "Kubernetes: Network Policies", URL: "https://kubernetes.io/docs/concepts/services-networking/network-policies/"}
+	refRBACGoodPractices    = PolicyReference{Title: "Kubernetes: Role Based Access Control Good Practices", URL: "https://kubernetes.io/docs/concepts/security/rbac-good-practices/"}
+	refSecretsGoodPractices = PolicyReference{Title: "Kubernetes: Good practices for Kubernetes Secrets", URL: "https://kubernetes.io/docs/concepts/security/secrets-good-practices/"}
+	refSigstore             = PolicyReference{Title: "Sigstore: Signing containers with cosign", URL: "https://docs.sigstore.dev/cosign/signing/signing_with_containers/"}
+	refSLSA                 = PolicyReference{Title: "SLSA: Supply-chain Levels for Software Artifacts", URL: "https://slsa.dev/"}
+	refDockerSecurity       = PolicyReference{Title: "Docker: Engine security", URL: "https://docs.docker.com/engine/security/"}
+	refDockerResources      = PolicyReference{Title: "Docker: Resource constraints", URL: "https://docs.docker.com/engine/containers/resource_constraints/"}
+	refDockerfileBest       = PolicyReference{Title: "Docker: Building best practices", URL: "https://docs.docker.com/build/building/best-practices/"}
+	refDockerfileReference  = PolicyReference{Title: "Docker: Dockerfile reference", URL: "https://docs.docker.com/reference/dockerfile/"}
+	refGitLabProtected      = PolicyReference{Title: "GitLab: Protected branches", URL: "https://docs.gitlab.com/ee/user/project/protected_branches.html"}
+	refGitLabApprovals      = PolicyReference{Title: "GitLab: Merge request approval settings", URL: "https://docs.gitlab.com/ee/user/project/merge_requests/approvals/settings.html"}
+	refGitLabTokens         = PolicyReference{Title: "GitLab: Token overview", URL: "https://docs.gitlab.com/ee/security/token_overview.html"}
+)
+
+// Snippets shared by the Kubernetes runtime and manifest rules
+const (
+	securityContextYAML = `spec:
+  securityContext:
+    runAsNonRoot: true
+    runAsUser: 10001
+    seccompProfile:
+      type: RuntimeDefault
+  containers:
+    - name: app
+      securityContext:
+        privileged: false
+        allowPrivilegeEscalation: false
+        readOnlyRootFilesystem: true
+        capabilities:
+          drop: ["ALL"]`
+
+	resourcesYAML = `containers:
+  - name: app
+    resources:
+      requests:
+        cpu: 250m
+        memory: 256Mi
+      limits:
+        cpu: "1"
+        memory: 256Mi`
+
+	digestYAML = `containers:
+  - name: app
+    image: registry.example.com/shop/api:1.4.2@sha256:3f1c...e9a0`
+
+	cosignShell = `cosign sign --key cosign.key registry.example.com/shop/api@sha256:3f1c...e9a0
+cosign attest --key cosign.key --type slsaprovenance --predicate provenance.json \
+  registry.example.com/shop/api@sha256:3f1c...e9a0`
+
+	nonRootDockerfile = `FROM debian:12-slim
+RUN useradd --system --uid 10001 app
+COPY --chown=app:app ./bin/server /app/server
+USER 10001
+ENTRYPOINT ["/app/server"]`
+)
+
+// policyDocs is the extended policy metadata store, keyed by rule ID
+var policyDocs = map[string]PolicyDoc{
+	"K8S-SEC-001": {
+		Rationale: "A privileged container has every Linux capability and access to all host devices, so a compromise of the application is a compromise of the node and everything scheduled on it. The Baseline Pod Security Standard forbids it.",
+		Examples: []PolicyExample{
+			{Title: "Drop privileges and capabilities", Language: "yaml", Code: securityContextYAML},
+		},
+		References: []PolicyReference{
+			cisKubernetes("5.2.2", "Minimize the admission of privileged containers"),
+			nsaKubernetes("Pod security: Pod security enforcement"),
+			refPodSecurityStandards,
+		},
+	},
+	"K8S-SEC-002": {
+		Rationale: "Processes running as UID 0 in a container are root on the host if they break out of the container, and can modify anything in the image. runAsNonRoot makes the kubelet refuse to start a container whose user is root.",
+		Examples: []PolicyExample{
+			{Title: "Run as a fixed non-root user", Language: "yaml", Code: securityContextYAML},
+		},
+		References: []PolicyReference{
+			cisKubernetes("5.2.7", "Minimize the admission of root containers"),
+			nsaKubernetes("Pod security: Non-root containers and rootless container engines"),
+			refSecurityContext,
+		},
+	},
+	"K8S-SEC-003": {
+		Rationale: "A writable root filesystem lets an attacker drop tools or modify binaries in a running container. With a read-only root, writes go only to explicitly mounted volumes such as an emptyDir for /tmp.",
+		Examples: []PolicyExample{
+			{Title: "Read-only root with a writable /tmp", Language: "yaml", Code: `containers:
+  - name: app
+    securityContext:
+      readOnlyRootFilesystem: true
+    volumeMounts:
+      - name: tmp
+        mountPath: /tmp
+volumes:
+  - name: tmp
+    emptyDir: {}`},
+		},
+		References: []PolicyReference{
+			nsaKubernetes("Pod security: Immutable container file systems"),
+			refSecurityContext,
+		},
+	},
+	"K8S-SEC-004": {
+		Rationale: "A pod on the host network sees every interface of the node, can bind its ports and can reach services listening on localhost, including the kubelet. NetworkPolicies do not apply to host-network pods.",
+		Examples: []PolicyExample{
+			{Title: "Use the pod network and a Service", Language: "yaml", Code: `spec:
+  hostNetwork: false
+  containers:
+    - name: app
+      ports:
+        - containerPort: 8080`},
+		},
+		References: []PolicyReference{
+			cisKubernetes("5.2.5", "Minimize the admission of containers wishing to share the host network namespace"),
+			refPodSecurityStandards,
+		},
+	},
+	"K8S-SEC-005": {
+		Rationale: "Sharing the host PID namespace lets a container see and signal every process on the node and read their environment and memory through /proc.",
+		Examples: []PolicyExample{
+			{Title: "Keep the pod's own PID namespace", Language: "yaml", Code: `spec:
+  hostPID: false
+  hostIPC: false`},
+		},
+		References: []PolicyReference{
+			cisKubernetes("5.2.3", "Minimize the admission of containers wishing to share the host process ID namespace"),
+			refPodSecurityStandards,
+		},
+	},
+	"K8S-IMG-001": {
+		Rationale: "A mutable tag such as latest can point to a different image on every pull, so replicas of one Deployment may run different code and a rollback does not restore the previous version.",
+		Examples: []PolicyExample{
+			{Title: "Use a release tag", Language: "yaml", Code: `containers:
+  - name: app
+    image: registry.example.com/shop/api:1.4.2
+    imagePullPolicy: IfNotPresent`},
+		},
+		References: []PolicyReference{refImages},
+	},
+	"K8S-IMG-003": {
+		Rationale: "Tags can be moved; a digest identifies exactly one image. Pinning by digest makes deployments reproducible and prevents a compromised registry from serving different content under the same tag.",
+		Examples: []PolicyExample{
+			{Title: "Tag and digest", Language: "yaml", Code: digestYAML},
+		},
+		References: []PolicyReference{
+			cisKubernetes("5.5.1", "Configure Image Provenance using ImagePolicyWebhook admission controller"),
+			refImages,
+		},
+	},
+	"K8S-IMG-004": {
+		Rationale: "Images pulled straight from public registries bypass vulnerability scanning and mirroring, and are exposed to typosquatting and rate limits. Restricting sources to approved registries keeps the supply chain reviewable.",
+		Examples: []PolicyExample{
+			{Title: "Check against an allowlist", Language: "shell", Code: `devops-toolkit compliance check k8s --allowed-registries registry.example.com,ghcr.io/example`},
+		},
+		References: []PolicyReference{
+			cisKubernetes("5.5.1", "Configure Image Provenance using ImagePolicyWebhook admission controller"),
+			nsaKubernetes("Building secure container images"),
+		},
+	},
+	"K8S-IMG-005": {
+		Rationale: "A signature or provenance attestation shows who built an image and from which source, so tampered or unofficial builds can be rejected before they run.",
+		Examples: []PolicyExample{
+			{Title: "Sign and attest an image", Language: "shell", Code: cosignShell},
+		},
+		References: []PolicyReference{refSigstore, refSLSA},
+	},
+	"K8S-PROBE-001": {
+		Rationale: "Without a liveness probe a deadlocked process keeps running and serving errors until someone restarts it by hand. Keep the probe cheap and independent of downstream services, or it will restart healthy pods during outages elsewhere.",
+		Examples: []PolicyExample{
+			{Title: "HTTP liveness probe", Language: "yaml", Code: `containers:
+  - name: app
+    livenessProbe:
+      httpGet:
+        path: /healthz
+        port: 8080
+      periodSeconds: 10
+      failureThreshold: 3`},
+		},
+		References: []PolicyReference{refProbes},
+	},
+	"K8S-PROBE-002": {
+		Rationale: "Without a readiness probe a pod receives traffic as soon as its container starts, before it can serve requests, and keeps receiving it while overloaded. Rolling updates also rely on readiness to pace themselves.",
+		Examples: []PolicyExample{
+			{Title: "HTTP readiness probe", Language: "yaml", Code: `containers:
+  - name: app
+    readinessProbe:
+      httpGet:
+        path: /ready
+        port: 8080
+      periodSeconds: 5
+      failureThreshold: 2`},
+		},
+		References: []PolicyReference{refProbes},
+	},
+	"K8S-RES-001": {
+		Rationale: "A container without a CPU limit can use every idle core on the node and starve its neighbours when load spikes. Some teams deliberately leave CPU unlimited and rely on requests; skip this rule in that case.",
+		Examples: []PolicyExample{
+			{Title: "Requests and limits", Language: "yaml", Code: resourcesYAML},
+		},
+		References: []PolicyReference{
+			nsaKubernetes("Network separation and hardening: Resource policies"),
+			refResources,
+		},
+	},
+	"K8S-RES-002": {
+		Rationale: "A container without a memory limit can push the node into memory pressure, where the kubelet evicts other pods and the kernel OOM killer may pick an unrelated process. Setting the limit equal to the request avoids overcommit.",
+		Examples: []PolicyExample{
+			{Title: "Requests and limits", Language: "yaml", Code: resourcesYAML},
+		},
+		References: []PolicyReference{
+			nsaKubernetes("Network separation and hardening: Resource policies"),
+			refResources,
+		},
+	},
+	"K8S-NET-001": {
+		Rationale: "Pods accept traffic from anywhere in the cluster until a NetworkPolicy selects them, so one compromised pod can reach every database and internal API. A default-deny policy per namespace makes allowed flows explicit.",
+		Examples: []PolicyExample{
+			{Title: "Default deny with an explicit allow", Language: "yaml", Code: `apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: default-deny
+spec:
+  podSelector: {}
+  policyTypes: ["Ingress"]
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: allow-frontend
+spec:
+  podSelector:
+    matchLabels:
+      app: api
+  ingress:
+    - from:
+        - podSelector:
+            matchLabels:
+              app: frontend`},
+		},
+		References: []PolicyReference{
+			cisKubernetes("5.3.2", "Ensure that all Namespaces have Network Policies defined"),
+			nsaKubernetes("Network separation and hardening: Network policies"),
+			refNetworkPolicies,
+		},
+	},
+	"K8S-RBAC-001": {
+		Rationale: "cluster-admin can do anything to any resource, including reading every secret and granting itself more access. Bindings to people or service accounts outside the system components should be replaced by scoped roles.",
+		Examples: []PolicyExample{
+			{Title: "Namespaced edit access instead of cluster-admin", Language: "yaml", Code: `apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: shop-developers
+  namespace: shop
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: edit
+subjects:
+  - kind: Group
+    name: shop-developers
+    apiGroup: rbac.authorization.k8s.io`},
+		},
+		References: []PolicyReference{
+			cisKubernetes("5.1.1", "Ensure that the cluster-admin role is only used where required"),
+			nsaKubernetes("Authentication and authorization"),
+			refRBACGoodPractices,
+		},
+	},
+	"K8S-RBAC-002": {
+		Rationale: "Wildcards grant access to every current and future resource or verb, including ones added later by CRDs, so the effective permissions of a role silently grow.",
+		Examples: []PolicyExample{
+			{Title: "Explicit verbs and resources", Language: "yaml", Code: `rules:
+  - apiGroups: ["apps"]
+    resources: ["deployments"]
+    verbs: ["get", "list", "watch", "patch"]`},
+		},
+		References: []PolicyReference{
+			cisKubernetes("5.1.3", "Minimize wildcard use in Roles and ClusterRoles"),
+			refRBACGoodPractices,
+		},
+	},
+	"K8S-RBAC-003": {
+		Rationale: "Reading secrets in every namespace exposes every service account token and credential in the cluster. list and watch return the secret data too, not only names.",
+		Examples: []PolicyExample{
+			{Title: "Read one named secret in one namespace", Language: "yaml", Code: `apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: read-db-credentials
+  namespace: shop
+rules:
+  - apiGroups: [""]
+    resources: ["secrets"]
+    resourceNames: ["db-credentials"]
+    verbs: ["get"]`},
+		},
+		References: []PolicyReference{
+			cisKubernetes("5.1.2", "Minimize access to secrets"),
+			refSecretsGoodPractices,
+		},
+	},
+	"K8S-RBAC-004": {
+		Rationale: "escalate and bind let a subject create roles or bindings with more permissions than it holds, and impersonate lets it act as any user or group. Each is equivalent to cluster-admin in practice.",
+		Examples: []PolicyExample{
+			{Title: "Find who holds the verbs", Language: "shell", Code: `kubectl get clusterroles -o json | jq -r '.items[] | select(any(.rules[]?; .verbs[]? | IN("escalate","bind","impersonate"))) | .metadata.name'`},
+		},
+		References: []PolicyReference{
+			cisKubernetes("5.1.8", "Limit use of the Bind, Impersonate and Escalate permissions in the Kubernetes cluster"),
+			refRBACGoodPractices,
+		},
+	},
+	"K8S-RBAC-005": {
+		Rationale: "Bindings to identity provider groups give access to whoever the IdP puts in the group, so cluster access changes without any change in the cluster. Review group ownership or bind named users for sensitive roles.",
+		References: []PolicyReference{
+			nsaKubernetes("Authentication and authorization"),
+			refRBACGoodPractices,
+		},
+	},
+	"K8S-SECRET-001": {
+		Rationale: "An expired certificate breaks TLS for every client at once, usually outside working hours. Renewing 30 days ahead leaves time to fix failed automation.",
+		Examples: []PolicyExample{
+			{Title: "Let cert-manager renew the secret", Language: "yaml", Code: `apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: shop-tls
+  namespace: shop
+spec:
+  secretName: shop-tls
+  dnsNames: ["shop.example.com"]
+  renewBefore: 720h
+  issuerRef:
+    kind: ClusterIssuer
+    name: letsencrypt`},
+		},
+		References: []PolicyReference{
+			{Title: "cert-manager: Certificate resource", URL: "https://cert-manager.io/docs/usage/certificate/"},
+		},
+	},
+	"K8S-SECRET-002": {
+		Rationale: "Secrets nothing references are often leftovers holding credentials that were never revoked. They widen what a leaked read permission exposes.",
+		Examples: []PolicyExample{
+			{Title: "Review unreferenced secrets", Language: "shell", Code: `devops-toolkit k8s secrets-report -n shop`},
+		},
+		References: []PolicyReference{
+			cisKubernetes("5.4.1", "Prefer using Secrets as files over Secrets as environment variables"),
+			refSecretsGoodPractices,
+		},
+	},
+
+	"DOCKER-SEC-001": {
+		Rationale: "--privileged gives the container all capabilities and devices and disables seccomp and AppArmor confinement; a process in it can mount the host disk and load kernel modules.",
+		Examples: []PolicyExample{
+			{Title: "Grant only the capability needed", Language: "shell", Code: `docker run --cap-drop ALL --cap-add NET_BIND_SERVICE --security-opt no-new-privileges myapp:1.4.2`},
+		},
+		References: []PolicyReference{
+			cisDocker("5.4", "Ensure that privileged containers are not used"),
+			refDockerSecurity,
+		},
+	},
+	"DOCKER-SEC-002": {
+		Rationale: "Root in a container is root on the host unless user namespaces are enabled, so a container escape or a writable host mount gives full host access.",
+		Examples: []PolicyExample{
+			{Title: "Non-root user in the image", Language: "dockerfile", Code: nonRootDockerfile},
+			{Title: "Override at run time", Language: "shell", Code: `docker run --user 10001:10001 myapp:1.4.2`},
+		},
+		References: []PolicyReference{
+			cisDocker("4.1", "Ensure that a user for the container has been created"),
+			refDockerSecurity,
+		},
+	},
+	"DOCKER-SEC-003": {
+		Rationale: "With --network host the container shares the host's network stack: it can bind any port, sniff traffic on host interfaces and reach services listening on localhost.",
+		Examples: []PolicyExample{
+			{Title: "Publish only the ports needed", Language: "shell", Code: `docker network create app
+docker run --network app -p 8080:8080 myapp:1.4.2`},
+		},
+		References: []PolicyReference{
+			cisDocker("5.9", "Ensure that the host's network namespace is not shared"),
+		},
+	},
+	"DOCKER-SEC-004": {
+		Rationale: "With --pid host the container sees every host process and, with enough privileges, can signal or trace them.",
+		References: []PolicyReference{
+			cisDocker("5.15", "Ensure that the host's process namespace is not shared"),
+		},
+	},
+	"DOCKER-SEC-005": {
+		Rationale: "Capabilities such as SYS_ADMIN, SYS_PTRACE, SYS_MODULE and NET_ADMIN each break an important part of container isolation; SYS_ADMIN alone is close to --privileged.",
+		Examples: []PolicyExample{
+			{Title: "Drop everything, add back what is needed", Language: "shell", Code: `docker run --cap-drop ALL --cap-add NET_BIND_SERVICE myapp:1.4.2`},
+		},
+		References: []PolicyReference{
+			cisDocker("5.3", "Ensure that Linux kernel capabilities are restricted within containers"),
+			refDockerSecurity,
+		},
+	},
+	"DOCKER-SEC-006": {
+		Rationale: "A read-only root filesystem stops an attacker from writing tools or modifying binaries inside the container; writable paths are limited to explicit volumes and tmpfs mounts.",
+		Examples: []PolicyExample{
+			{Title: "Read-only root with a tmpfs", Language: "shell", Code: `docker run --read-only --tmpfs /tmp:rw,noexec,nosuid myapp:1.4.2`},
+		},
+		References: []PolicyReference{
+			cisDocker("5.12", "Ensure that the container's root filesystem is mounted as read only"),
+		},
+	},
+	"DOCKER-SEC-007": {
+		Rationale: "Anyone who can talk to /var/run/docker.sock can start a privileged container with the host filesystem mounted, which is root on the host. A socket proxy can expose only the read-only API calls a tool needs.",
+		Examples: []PolicyExample{
+			{Title: "Read-only API access through a socket proxy", Language: "yaml", Code: `services:
+  socket-proxy:
+    image: tecnativa/docker-socket-proxy:0.2
+    environment:
+      CONTAINERS: 1
+      POST: 0
+    volumes:
+      - /var/run/docker.sock:/var/run/docker.sock:ro
+  monitor:
+    image: monitor:1.0
+    environment:
+      DOCKER_HOST: tcp://socket-proxy:2375`},
+		},
+		References: []PolicyReference{
+			cisDocker("5.31", "Ensure that the Docker socket is not mounted inside any containers"),
+			refDockerSecurity,
+		},
+	},
+	"DOCKER-SEC-008": {
+		Rationale: "Bind-mounting /, /etc or /proc gives the container the host's credentials, configuration and kernel interfaces; with write access it can change them.",
+		Examples: []PolicyExample{
+			{Title: "Mount only what is needed, read-only", Language: "shell", Code: `docker run -v /srv/app/config:/etc/app:ro myapp:1.4.2`},
+		},
+		References: []PolicyReference{
+			cisDocker("5.5", "Ensure sensitive host system directories are not mounted on containers"),
+		},
+	},
+	"DOCKER-SEC-009": {
+		Rationale: "Containers are meant to be immutable. Binaries changed after start, new setuid files or new shared libraries are common signs of an intrusion or of hot-patching that will be lost on the next deploy.",
+		Examples: []PolicyExample{
+			{Title: "Inspect the changes", Language: "shell", Code: `devops-toolkit docker drift api --indicators-only`},
+		},
+		References: []PolicyReference{
+			cisDocker("5.12", "Ensure that the container's root filesystem is mounted as read only"),
+		},
+	},
+	"DOCKER-RES-001": {
+		Rationale: "Without a memory limit a leaking container can exhaust host memory, and the kernel OOM killer may kill other containers or the Docker daemon instead.",
+		Examples: []PolicyExample{
+			{Title: "Memory limit", Language: "shell", Code: `docker run --memory 512m --memory-swap 512m myapp:1.4.2`},
+		},
+		References: []PolicyReference{
+			cisDocker("5.10", "Ensure that the memory usage for containers is limited"),
+			refDockerResources,
+		},
+	},
+	"DOCKER-RES-002": {
+		Rationale: "Without a CPU limit one busy container can slow every other container on the host.",
+		Examples: []PolicyExample{
+			{Title: "CPU limit", Language: "shell", Code: `docker run --cpus 1.5 myapp:1.4.2`},
+		},
+		References: []PolicyReference{
+			cisDocker("5.11", "Ensure that CPU priority is set appropriately on containers"),
+			refDockerResources,
+		},
+	},
+	"DOCKER-CFG-001": {
+		Rationale: "Without a restart policy a crashed container stays down until someone notices. on-failure with a retry cap avoids endless restart loops.",
+		Examples: []PolicyExample{
+			{Title: "Restart on failure", Language: "shell", Code: `docker run --restart on-failure:5 myapp:1.4.2`},
+		},
+		References: []PolicyReference{
+			cisDocker("5.14", "Ensure that the 'on-failure' container restart policy is set to '5'"),
+		},
+	},
+	"DOCKER-CFG-002": {
+		Rationale: "A running process is not a working service. A health check lets Docker, Compose and orchestrators see when a container stops responding.",
+		Examples: []PolicyExample{
+			{Title: "HEALTHCHECK in the image", Language: "dockerfile", Code: `HEALTHCHECK --interval=30s --timeout=3s --retries=3 \
+  CMD wget -qO- http://localhost:8080/healthz || exit 1`},
+		},
+		References: []PolicyReference{
+			cisDocker("4.6", "Ensure that HEALTHCHECK instructions have been added to container images"),
+			refDockerfileReference,
+		},
+	},
+	"DOCKER-IMG-001": {
+		Rationale: "latest is whatever was pushed most recently, so two hosts running the same command can run different code, and there is nothing to roll back to.",
+		Examples: []PolicyExample{
+			{Title: "Pin a release tag", Language: "dockerfile", Code: `FROM node:20.11-alpine3.19`},
+		},
+		References: []PolicyReference{refDockerfileBest},
+	},
+	"DOCKER-IMG-002": {
+		Rationale: "Large images pull slowly, which slows scaling and recovery, and usually carry compilers and packages the application never uses but scanners must still track.",
+		Examples: []PolicyExample{
+			{Title: "Multi-stage build", Language: "dockerfile", Code: `FROM golang:1.22 AS build
+WORKDIR /src
+COPY . .
+RUN CGO_ENABLED=0 go build -o /out/server ./cmd/server
+
+FROM gcr.io/distroless/static-debian12:nonroot
+COPY --from=build /out/server /server
+ENTRYPOINT ["/server"]`},
+		},
+		References: []PolicyReference{refDockerfileBest},
+	},
+	"DOCKER-IMG-003": {
+		Rationale: "An image without a USER runs as root unless every caller remembers --user. Setting it in the image makes non-root the default everywhere the image runs.",
+		Examples: []PolicyExample{
+			{Title: "Non-root user in the image", Language: "dockerfile", Code: nonRootDockerfile},
+		},
+		References: []PolicyReference{
+			cisDocker("4.1", "Ensure that a user for the container has been created"),
+			refDockerfileReference,
+		},
+	},
+
+	"FILE-K8S-001": {
+		Rationale: "Manifests with latest deploy whatever the tag points to at apply time, so the same commit can produce different clusters and GitOps diffs do not show image changes.",
+		Examples: []PolicyExample{
+			{Title: "Use a release tag", Language: "yaml", Code: `containers:
+  - name: app
+    image: registry.example.com/shop/api:1.4.2`},
+		},
+		References: []PolicyReference{refImages},
+	},
+	"FILE-K8S-002": {
+		Rationale: "Catching missing resources in review is cheaper than finding them as evictions and noisy neighbours in production.",
+		Examples: []PolicyExample{
+			{Title: "Requests and limits", Language: "yaml", Code: resourcesYAML},
+		},
+		References: []PolicyReference{
+			nsaKubernetes("Network separation and hardening: Resource policies"),
+			refResources,
+		},
+	},
+	"FILE-K8S-003": {
+		Rationale: "Without a securityContext a workload gets the runtime defaults: it may run as root, escalate privileges and write to its root filesystem. Namespaces enforcing the Restricted Pod Security Standard reject such pods.",
+		Examples: []PolicyExample{
+			{Title: "Restricted security context", Language: "yaml", Code: securityContextYAML},
+		},
+		References: []PolicyReference{
+			cisKubernetes("5.2.7", "Minimize the admission of root containers"),
+			nsaKubernetes("Pod security"),
+			refPodSecurityStandards,
+		},
+	},
+	"FILE-IMG-001": {
+		Rationale: "Pinning by digest in source makes builds and deployments reproducible and lets tools such as Renovate propose digest updates as reviewable changes.",
+		Examples: []PolicyExample{
+			{Title: "Kubernetes manifest", Language: "yaml", Code: digestYAML},
+			{Title: "Dockerfile", Language: "dockerfile", Code: `FROM debian:12-slim@sha256:7b3f...c2d1`},
+		},
+		References: []PolicyReference{refImages, refDockerfileBest},
+	},
+	"FILE-IMG-002": {
+		Rationale: "Catching images from unapproved registries in review keeps unscanned or unmirrored images out of every environment, not only the one that was scanned.",
+		Examples: []PolicyExample{
+			{Title: "Check files against an allowlist", Language: "shell", Code: `devops-toolkit compliance check files --path ./deploy --allowed-registries registry.example.com`},
+		},
+		References: []PolicyReference{nsaKubernetes("Building secure container images")},
+	},
+	"FILE-IMG-003": {
+		Rationale: "Checking provenance for pinned images in source shows, before deploy, whether each image can be traced to a trusted build.",
+		Examples: []PolicyExample{
+			{Title: "Sign and attest an image", Language: "shell", Code: cosignShell},
+		},
+		References: []PolicyReference{refSigstore, refSLSA},
+	},
+	"FILE-DOCKER-003": {
+		Rationale: "A Dockerfile without USER produces an image that runs as root by default wherever it is used.",
+		Examples: []PolicyExample{
+			{Title: "Create and switch to a non-root user", Language: "dockerfile", Code: nonRootDockerfile},
+		},
+		References: []PolicyReference{
+			cisDocker("4.1", "Ensure that a user for the container has been created"),
+			refDockerfileReference,
+		},
+	},
+	"FILE-DOCKER-004": {
+		Rationale: "A HEALTHCHECK in the image lets Docker and Compose detect a hung service without every deployment defining its own check.",
+		Examples: []PolicyExample{
+			{Title: "HTTP health check", Language: "dockerfile", Code: `HEALTHCHECK --interval=30s --timeout=3s --retries=3 \
+  CMD wget -qO- http://localhost:8080/healthz || exit 1`},
+		},
+		References: []PolicyReference{
+			cisDocker("4.6", "Ensure that HEALTHCHECK instructions have been added to container images"),
+			refDockerfileReference,
+		},
+	},
+	"FILE-DOCKER-006": {
+		Rationale: "apt package lists left in a layer add tens of megabytes to every image and stale metadata that scanners may flag. Removing them must happen in the same RUN, or the layer keeps them.",
+		Examples: []PolicyExample{
+			{Title: "Clean up in the same layer", Language: "dockerfile", Code: `RUN apt-get update \
+ && apt-get install -y --no-install-recommends ca-certificates=20230311 \
+ && rm -rf /var/lib/apt/lists/*`},
+			{Title: "Or use a cache mount", Language: "dockerfile", Code: `RUN --mount=type=cache,target=/var/cache/apt \
+    --mount=type=cache,target=/var/lib/apt \
+    apt-get update && apt-get install -y --no-install-recommends curl`},
+		},
+		References: []PolicyReference{refDockerfileBest},
+	},
+	"FILE-DOCKER-007": {
+		Rationale: "Unpinned packages install whatever version the mirror has on build day, so rebuilding the same Dockerfile can change behaviour or pull in a compromised release.",
+		Examples: []PolicyExample{
+			{Title: "Pinned versions", Language: "dockerfile", Code: `RUN apk add --no-cache curl=8.5.0-r0
+RUN pip install --no-cache-dir requests==2.31.0`},
+		},
+		References: []PolicyReference{refDockerfileBest},
+	},
+	"FILE-COMPOSE-001": {
+		Rationale: "privileged: true in Compose has the same effect as docker run --privileged: the service gets every capability and device on the host.",
+		Examples: []PolicyExample{
+			{Title: "Add only the capability needed", Language: "yaml", Code: `services:
+  app:
+    image: myapp:1.4.2
+    cap_drop: ["ALL"]
+    cap_add: ["NET_BIND_SERVICE"]
+    security_opt:
+      - no-new-privileges:true`},
+		},
+		References: []PolicyReference{
+			cisDocker("5.4", "Ensure that privileged containers are not used"),
+		},
+	},
+
+	"GITLAB-SEC-001": {
+		Rationale:  "An unprotected default branch can be pushed to, force-pushed and deleted by any developer, bypassing review and the pipelines that deploy from it.",
+		References: []PolicyReference{refGitLabProtected},
+	},
+	"GITLAB-SEC-002": {
+		Rationale:  "Force pushes rewrite history, which can hide a malicious change or silently drop reviewed commits from the branch that is deployed.",
+		References: []PolicyReference{refGitLabProtected},
+	},
+	"GITLAB-SEC-003": {
+		Rationale:  "Direct pushes skip merge requests, so changes land without review or approval. Limiting pushes to maintainers, or no one, makes merge requests the only path.",
+		References: []PolicyReference{refGitLabProtected},
+	},
+	"GITLAB-SEC-004": {
+		Rationale: "Without a required approval a single developer can merge any change to the default branch, including pipeline changes that reach production credentials.",
+		References: []PolicyReference{
+			refGitLabApprovals,
+			{Title: "GitLab: Merge request approval rules", URL: "https://docs.gitlab.com/ee/user/project/merge_requests/approvals/rules.html"},
+		},
+	},
+	"GITLAB-SEC-005": {
+		Rationale: "Code owner approval makes sure the team responsible for sensitive paths, such as CI configuration and deployment manifests, reviews changes to them.",
+		Examples: []PolicyExample{
+			{Title: "CODEOWNERS", Language: "shell", Code: `# .gitlab/CODEOWNERS
+/.gitlab-ci.yml   @platform-team
+/deploy/          @platform-team
+/src/payments/    @payments-team`},
+		},
+		References: []PolicyReference{
+			{Title: "GitLab: Code Owners", URL: "https://docs.gitlab.com/ee/user/project/codeowners/"},
+			refGitLabProtected,
+		},
+	},
+	"GITLAB-SEC-006": {
+		Rationale:  "If authors and committers can approve their own merge requests, the approval requirement no longer means a second person reviewed the change.",
+		References: []PolicyReference{refGitLabApprovals},
+	},
+	"GITLAB-SEC-007": {
+		Rationale:  "Approvals that survive new commits let a change be swapped after review, and rules editable per merge request let the author lower the bar for their own change.",
+		References: []PolicyReference{refGitLabApprovals},
+	},
+	"GITLAB-SEC-008": {
+		Rationale: "Keys and credential files pushed to a repository stay in its history and every clone even after deletion. Rejecting them at push time is far cheaper than rotating them afterwards.",
+		References: []PolicyReference{
+			{Title: "GitLab: Push rules", URL: "https://docs.gitlab.com/ee/user/project/repository/push_rules.html"},
+		},
+	},
+	"GITLAB-TOKEN-001": {
+		Rationale: "A leaked token grants everything its scopes and role allow. api scope and Maintainer or Owner roles let it change protected branches, CI variables and members.",
+		Examples: []PolicyExample{
+			{Title: "Create a read-only project token", Language: "shell", Code: `curl --request POST --header "PRIVATE-TOKEN: $GITLAB_TOKEN" \
+  --data "name=ci-reader" --data "scopes[]=read_api" --data "access_level=20" \
+  --data "expires_at=2026-06-30" \
+  "https://gitlab.example.com/api/v4/projects/42/access_tokens"`},
+		},
+		References: []PolicyReference{
+			refGitLabTokens,
+			{Title: "GitLab: Personal access token scopes", URL: "https://docs.gitlab.com/ee/user/profile/personal_access_tokens.html"},
+		},
+	},
+	"GITLAB-TOKEN-002": {
+		Rationale:  "Tokens without an expiry, or with a very long one, keep working long after the person or system that used them is gone. Short lifetimes force regular rotation.",
+		References: []PolicyReference{refGitLabTokens},
+	},
+}
+
+// PolicyDocumentation returns the extended documentation of a built-in policy
+func PolicyDocumentation(id string) (PolicyDoc, bool) {
+	doc, ok := policyDocs[id]
+	return doc, ok
+}
@@ -0,0 +1,206 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture types accepted by PolicyTest.Type
+const (
+	FixtureManifest   = "manifest"
+	FixtureDockerfile = "dockerfile"
+	FixtureCompose    = "compose"
+)
+
+// PolicyTest checks one fixture and the findings it must produce
+type PolicyTest struct {
+	Name string `yaml:"name"`
+	// Fixture is the file to check, relative to the test file
+	Fixture string `yaml:"fixture"`
+	// Type is manifest, dockerfile or compose; by default it is detected
+	// from the fixture name as compliance check files does
+	Type string `yaml:"type"`
+	// Fail lists rules that must report a failure, Pass rules that must not
+	Fail []string `yaml:"fail"`
+	Pass []string `yaml:"pass"`
+	// Exact fails the test on any failing rule not listed in Fail
+	Exact bool `yaml:"exact"`
+	// AllowedRegistries configures the registry rules for this fixture
+	AllowedRegistries []string `yaml:"allowed_registries"`
+}
+
+// PolicyTestResult is the outcome of one PolicyTest
+type PolicyTestResult struct {
+	File     string   `json:"file"`
+	Name     string   `json:"name"`
+	Passed   bool     `json:"passed"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// policyTestFile is a *_test.yaml file
+type policyTestFile struct {
+	Tests []PolicyTest `yaml:"tests"`
+}
+
+// RunPolicyTests runs every test in the *_test.yaml files under dir whose
+// name matches run (all when run is nil)
+func RunPolicyTests(ctx context.Context, dir string, run *regexp.Regexp) ([]PolicyTestResult, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name := info.Name()
+		if !info.IsDir() && (strings.HasSuffix(name, "_test.yaml") || strings.HasSuffix(name, "_test.yml")) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy directory: %w", err)
+	}
+	sort.Strings(files)
+
+	var results []PolicyTestResult
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		var suite policyTestFile
+		if err := yaml.Unmarshal(data, &suite); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+
+		for i, test := range suite.Tests {
+			if test.Name == "" {
+				test.Name = fmt.Sprintf("test %d", i+1)
+			}
+			if run != nil && !run.MatchString(test.Name) {
+				continue
+			}
+			results = append(results, runPolicyTest(ctx, file, test))
+		}
+	}
+	return results, nil
+}
+
+func runPolicyTest(ctx context.Context, file string, test PolicyTest) PolicyTestResult {
+	result := PolicyTestResult{File: file, Name: test.Name}
+	fail := func(format string, args ...interface{}) PolicyTestResult {
+		result.Problems = append(result.Problems, fmt.Sprintf(format, args...))
+		return result
+	}
+
+	if test.Fixture == "" {
+		return fail("no fixture")
+	}
+	if len(test.Fail) == 0 && len(test.Pass) == 0 && !test.Exact {
+		return fail("no expectations (set fail, pass or exact)")
+	}
+
+	// Only the built-in file rules run against fixtures, so any other rule
+	// would pass or fail vacuously
+	for _, rule := range append(append([]string{}, test.Fail...), test.Pass...) {
+		if !strings.HasPrefix(rule, "FILE-") {
+			result.Problems = append(result.Problems, fmt.Sprintf("%s is not a built-in file rule (custom and Rego rules are not loaded)", rule))
+		}
+	}
+	if len(result.Problems) > 0 {
+		return result
+	}
+
+	fixture := filepath.Join(filepath.Dir(file), test.Fixture)
+	if _, err := os.Stat(fixture); err != nil {
+		return fail("fixture %s: %v", test.Fixture, err)
+	}
+
+	checker := NewFileChecker(CheckOptions{AllowedRegistries: test.AllowedRegistries})
+	findings, err := checker.checkFixture(ctx, fixture, test.Type)
+	if err != nil {
+		return fail("fixture %s: %v", test.Fixture, err)
+	}
+
+	failed := make(map[string]bool)
+	for _, r := range findings {
+		if r.Status == StatusFailed {
+			failed[r.RuleID] = true
+		}
+	}
+
+	expected := make(map[string]bool)
+	for _, rule := range test.Fail {
+		expected[rule] = true
+		if !failed[rule] {
+			result.Problems = append(result.Problems, fmt.Sprintf("expected %s to fail", rule))
+		}
+	}
+	for _, rule := range test.Pass {
+		if failed[rule] {
+			result.Problems = append(result.Problems, fmt.Sprintf("expected %s not to fail", rule))
+		}
+	}
+	if test.Exact {
+		var unexpected []string
+		for rule := range failed {
+			if !expected[rule] {
+				unexpected = append(unexpected, rule)
+			}
+		}
+		sort.Strings(unexpected)
+		for _, rule := range unexpected {
+			result.Problems = append(result.Problems, fmt.Sprintf("unexpected failure %s", rule))
+		}
+	}
+
+	result.Passed = len(result.Problems) == 0
+	return result
+}
+
+// UnloadedPolicyFiles lists the Rego policies under dir. compliance test
+// only runs the built-in file rules, so these are reported rather than
+// silently ignored.
+func UnloadedPolicyFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.HasSuffix(info.Name(), ".rego") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy directory: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// checkFixture runs the file checks for kind against path, detecting the
+// kind from the file name when it is empty
+func (c *FileChecker) checkFixture(ctx context.Context, path, kind string) ([]CheckResult, error) {
+	switch kind {
+	case "":
+		if !isKubernetesManifest(path) && !isDockerfile(path) && !isDockerCompose(path) {
+			return nil, fmt.Errorf("cannot tell the fixture type from its name, set type (manifest, dockerfile, compose)")
+		}
+		return c.checkFile(ctx, path), nil
+	case FixtureManifest:
+		return c.checkKubernetesManifest(ctx, path)
+	case FixtureDockerfile:
+		return c.checkDockerfile(ctx, path)
+	case FixtureCompose:
+		return c.checkDockerCompose(ctx, path)
+	default:
+		return nil, fmt.Errorf("invalid fixture type %q (valid: manifest, dockerfile, compose)", kind)
+	}
+}
@@ -0,0 +1,66 @@
+package compliance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePolicyDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRunPolicyTestsRejectsNonFileRules(t *testing.T) {
+	dir := writePolicyDir(t, map[string]string{
+		"fixtures/Dockerfile": "FROM alpine:latest\nRUN apk add curl\n",
+		"docker_test.yaml": `tests:
+  - name: latest base image
+    fixture: fixtures/Dockerfile
+    fail: [FILE-DOCKER-005]
+  - name: custom rule
+    fixture: fixtures/Dockerfile
+    fail: [CUSTOM-001]
+  - name: cluster rule
+    fixture: fixtures/Dockerfile
+    pass: [K8S-SEC-001]
+`,
+		"custom/deny.rego": "package main\n",
+	})
+
+	results, err := RunPolicyTests(context.Background(), dir, nil)
+	if err != nil {
+		t.Fatalf("RunPolicyTests: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	if !results[0].Passed {
+		t.Errorf("built-in rule test failed: %v", results[0].Problems)
+	}
+	for _, r := range results[1:] {
+		if r.Passed || len(r.Problems) != 1 || !strings.Contains(r.Problems[0], "not a built-in file rule") {
+			t.Errorf("%s: passed=%v problems=%v, want a not-a-file-rule failure", r.Name, r.Passed, r.Problems)
+		}
+	}
+
+	unloaded, err := UnloadedPolicyFiles(dir)
+	if err != nil {
+		t.Fatalf("UnloadedPolicyFiles: %v", err)
+	}
+	if len(unloaded) != 1 || filepath.Base(unloaded[0]) != "deny.rego" {
+		t.Errorf("unloaded = %v, want custom/deny.rego", unloaded)
+	}
+}
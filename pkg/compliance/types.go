@@ -1,6 +1,11 @@
 package compliance
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+)
 
 // CheckStatus represents the status of a compliance check
 type CheckStatus string
@@ -22,6 +27,9 @@ type CheckResult struct {
 	Resource    string      `json:"resource"`
 	Message     string      `json:"message"`
 	Remediation string      `json:"remediation,omitempty"`
+	// Group is the namespace (Kubernetes) or compose project (Docker) that
+	// owns the resource, empty for cluster-scoped resources
+	Group string `json:"group,omitempty"`
 }
 
 // CheckOptions contains options for compliance checks
@@ -29,9 +37,44 @@ type CheckOptions struct {
 	Namespace   string
 	Image       string
 	Path        string
+	Include     []string
+	Exclude     []string
 	SkipRules   []string
 	OnlyRules   []string
 	MinSeverity string
+	NoCache     bool
+
+	// AllowedRegistries restricts image sources; empty disables the registry rule
+	AllowedRegistries []string
+	// VerifyProvenance queries registries for image signatures and attestations
+	VerifyProvenance bool
+	// RegistryCredentials authenticate provenance lookups, keyed by registry host
+	RegistryCredentials map[string]RegistryCredential
+	// Namespaces narrows Kubernetes checks to (or away from) a set of namespaces
+	Namespaces k8s.NamespaceSelector
+	// AllImages runs the image rules against every image used by running
+	// containers and PodImages, not only Image
+	AllImages bool
+	PodImages []k8s.PodImage
+	// GroupLabel is the container label Docker results are grouped by;
+	// empty means the compose project
+	GroupLabel string
+
+	// Progress, when set, is called as a checker advances with a short
+	// status such as "Files: 120/800 scanned"
+	Progress func(step string)
+}
+
+func (o CheckOptions) progress(format string, args ...interface{}) {
+	if o.Progress != nil {
+		o.Progress(fmt.Sprintf(format, args...))
+	}
+}
+
+// RegistryCredential is a username and password (or token) for a private registry
+type RegistryCredential struct {
+	Username string
+	Password string
 }
 
 // Policy represents a compliance policy
@@ -50,6 +93,9 @@ type Report struct {
 	GeneratedAt time.Time     `json:"generated_at"`
 	Summary     ReportSummary `json:"summary"`
 	Results     []CheckResult `json:"results"`
+	// GroupBy and Groups are set when the report is grouped by namespace or team
+	GroupBy string         `json:"group_by,omitempty"`
+	Groups  []GroupSummary `json:"groups,omitempty"`
 }
 
 // ReportSummary contains report summary statistics
@@ -60,4 +106,3 @@ type ReportSummary struct {
 	Skipped int     `json:"skipped"`
 	Score   float64 `json:"score"`
 }
-
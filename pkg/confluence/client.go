@@ -0,0 +1,142 @@
+package confluence
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/logging"
+)
+
+// Client updates pages through the Confluence REST API
+type Client struct {
+	baseURL    string
+	user       string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the Confluence site at baseURL (for Cloud,
+// https://<site>.atlassian.net/wiki). With a user the token is sent as basic
+// auth (Cloud API tokens), otherwise as a bearer token (Server and Data
+// Center personal access tokens).
+func NewClient(baseURL, user, token string) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid Confluence URL %q", baseURL)
+	}
+
+	transport := http.DefaultTransport
+	if logging.Enabled() {
+		transport = logging.Transport(transport)
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		user:       user,
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second, Transport: transport},
+	}, nil
+}
+
+// content is the subset of a Confluence content object we read and write
+type content struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Title   string `json:"title"`
+	Version struct {
+		Number  int    `json:"number"`
+		Message string `json:"message,omitempty"`
+	} `json:"version"`
+	Body  *body `json:"body,omitempty"`
+	Links struct {
+		Base  string `json:"base"`
+		WebUI string `json:"webui"`
+	} `json:"_links"`
+}
+
+type body struct {
+	Storage storage `json:"storage"`
+}
+
+type storage struct {
+	Value          string `json:"value"`
+	Representation string `json:"representation"`
+}
+
+// UpdatePage replaces the body of an existing page with storage-format
+// XHTML, keeping its title unless title is set, and returns the page URL
+func (c *Client) UpdatePage(ctx context.Context, pageID, title, xhtml, message string) (string, error) {
+	var page content
+	if err := c.do(ctx, http.MethodGet, "/rest/api/content/"+url.PathEscape(pageID)+"?expand=version", nil, &page); err != nil {
+		return "", fmt.Errorf("failed to get page %s: %w", pageID, err)
+	}
+
+	update := content{ID: page.ID, Type: "page", Title: page.Title}
+	if title != "" {
+		update.Title = title
+	}
+	update.Version.Number = page.Version.Number + 1
+	update.Version.Message = message
+	update.Body = &body{Storage: storage{Value: xhtml, Representation: "storage"}}
+
+	var updated content
+	if err := c.do(ctx, http.MethodPut, "/rest/api/content/"+url.PathEscape(pageID), update, &updated); err != nil {
+		return "", fmt.Errorf("failed to update page %s: %w", pageID, err)
+	}
+
+	base := updated.Links.Base
+	if base == "" {
+		base = c.baseURL
+	}
+	return base + updated.Links.WebUI, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var reqBody io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.token)
+	} else if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Confluence: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("Confluence returned HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse Confluence response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/registry"
+)
+
+// dockerHubAuthKey is the key Docker Hub credentials are stored under in config.json
+const dockerHubAuthKey = "https://index.docker.io/v1/"
+
+// dockerConfig is the subset of ~/.docker/config.json used for registry auth
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth          string `json:"auth"`
+		IdentityToken string `json:"identitytoken"`
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// RegistryHost returns the registry host of an image reference, e.g. docker.io or ghcr.io
+func RegistryHost(ref string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid image reference %q: %w", ref, err)
+	}
+	return reference.Domain(named), nil
+}
+
+// RegistryAuth returns the encoded X-Registry-Auth value for the registry of an image
+// reference, read from the Docker CLI config (credHelpers, auths, then credsStore).
+// Without stored credentials an anonymous auth is returned.
+func RegistryAuth(ctx context.Context, ref string) (string, error) {
+	host, err := RegistryHost(ref)
+	if err != nil {
+		return "", err
+	}
+
+	auth, err := lookupRegistryAuth(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	return registry.EncodeAuthConfig(auth)
+}
+
+func lookupRegistryAuth(ctx context.Context, host string) (registry.AuthConfig, error) {
+	serverAddress := host
+	if host == "docker.io" {
+		serverAddress = dockerHubAuthKey
+	}
+
+	config, err := loadDockerConfig()
+	if err != nil || config == nil {
+		return registry.AuthConfig{ServerAddress: serverAddress}, err
+	}
+
+	if helper, ok := config.CredHelpers[host]; ok {
+		return credentialHelperAuth(ctx, helper, serverAddress)
+	}
+
+	for _, key := range []string{serverAddress, host, "https://" + host} {
+		entry, ok := config.Auths[key]
+		if !ok || (entry.Auth == "" && entry.IdentityToken == "") {
+			continue
+		}
+		auth := registry.AuthConfig{ServerAddress: serverAddress, IdentityToken: entry.IdentityToken}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return auth, fmt.Errorf("failed to decode docker credentials for %s: %w", host, err)
+			}
+			auth.Username, auth.Password, _ = strings.Cut(string(decoded), ":")
+		}
+		return auth, nil
+	}
+
+	if config.CredsStore != "" {
+		return credentialHelperAuth(ctx, config.CredsStore, serverAddress)
+	}
+	return registry.AuthConfig{ServerAddress: serverAddress}, nil
+}
+
+// loadDockerConfig reads the Docker CLI config, returning nil when there is none
+func loadDockerConfig() (*dockerConfig, error) {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docker config: %w", err)
+	}
+
+	var config dockerConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse docker config: %w", err)
+	}
+	return &config, nil
+}
+
+// credentialHelperAuth asks a docker-credential-<helper> binary for a registry's credentials
+func credentialHelperAuth(ctx context.Context, helper, serverAddress string) (registry.AuthConfig, error) {
+	auth := registry.AuthConfig{ServerAddress: serverAddress}
+
+	cmd := exec.CommandContext(ctx, "docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(serverAddress)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		// A missing helper binary or missing credentials mean anonymous access
+		if errors.Is(err, exec.ErrNotFound) || strings.Contains(string(out)+stderr.String(), "credentials not found") {
+			return auth, nil
+		}
+		return auth, fmt.Errorf("credential helper %s failed: %w", helper, err)
+	}
+
+	var creds struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(out, &creds); err != nil {
+		return auth, fmt.Errorf("failed to parse credential helper output: %w", err)
+	}
+
+	// Identity tokens are returned with a placeholder username
+	if creds.Username == "<token>" {
+		auth.IdentityToken = creds.Secret
+	} else {
+		auth.Username, auth.Password = creds.Username, creds.Secret
+	}
+	return auth, nil
+}
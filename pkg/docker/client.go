@@ -6,10 +6,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"regexp"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/batch"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/logging"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/loglevel"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
@@ -19,7 +23,7 @@ import (
 
 // Client wraps the Docker client
 type Client struct {
-	cli *client.Client
+	cli API
 }
 
 // NewClient creates a new Docker client
@@ -29,6 +33,21 @@ func NewClient() (*Client, error) {
 		return nil, fmt.Errorf("failed to create docker client: %w", err)
 	}
 
+	if logging.Enabled() {
+		logging.Debug("resolved docker daemon", "host", cli.DaemonHost(), "docker_host_env", os.Getenv("DOCKER_HOST"))
+
+		// Rebuild the client around a logging transport; the copied HTTP client
+		// keeps the socket dialer and TLS settings configured from the environment
+		httpClient := cli.HTTPClient()
+		httpClient.Transport = logging.Transport(httpClient.Transport)
+		cli.Close()
+
+		cli, err = client.NewClientWithOpts(client.FromEnv, client.WithHTTPClient(httpClient), client.WithAPIVersionNegotiation())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create docker client: %w", err)
+		}
+	}
+
 	return &Client{cli: cli}, nil
 }
 
@@ -37,31 +56,45 @@ func (c *Client) Close() error {
 	return c.cli.Close()
 }
 
+// Host returns the docker daemon address the client talks to
+func (c *Client) Host() string {
+	return c.cli.DaemonHost()
+}
+
 // PortMapping represents a port mapping
 type PortMapping struct {
-	IP          string
-	PrivatePort uint16
-	PublicPort  uint16
-	Type        string
+	IP          string `json:"ip"`
+	PrivatePort uint16 `json:"private_port"`
+	PublicPort  uint16 `json:"public_port"`
+	Type        string `json:"type"`
 }
 
 // ContainerInfo contains container information
 type ContainerInfo struct {
-	ID      string
-	Name    string
-	Image   string
-	Command string
-	Created string
-	Status  string
-	State   string
-	Health  string
-	Ports   []PortMapping
-	Size    string
-}
-
-// ListContainers lists containers
-func (c *Client) ListContainers(ctx context.Context, all bool) ([]ContainerInfo, error) {
-	containers, err := c.cli.ContainerList(ctx, container.ListOptions{All: all})
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Image   string            `json:"image"`
+	Command string            `json:"command"`
+	Created string            `json:"created"`
+	Status  string            `json:"status"`
+	State   string            `json:"state"`
+	Health  string            `json:"health"`
+	Ports   []PortMapping     `json:"ports"`
+	Size    string            `json:"size"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	// GPU is only set by callers that inspect containers, see GPUAllocations
+	GPU *GPUAllocation `json:"gpu,omitempty"`
+}
+
+// ListContainers lists containers, optionally filtered by a comma-separated
+// list of docker filters such as "status=running,label=app=web"
+func (c *Client) ListContainers(ctx context.Context, all bool, filter string) ([]ContainerInfo, error) {
+	args, err := parseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := c.cli.ContainerList(ctx, container.ListOptions{All: all, Filters: args})
 	if err != nil {
 		return nil, err
 	}
@@ -72,9 +105,10 @@ func (c *Client) ListContainers(ctx context.Context, all bool) ([]ContainerInfo,
 			ID:      cont.ID,
 			Image:   cont.Image,
 			Command: cont.Command,
-			Created: formatTime(time.Unix(cont.Created, 0)),
+			Created: format.Ago(time.Unix(cont.Created, 0)),
 			Status:  cont.Status,
 			State:   cont.State,
+			Labels:  cont.Labels,
 		}
 
 		if len(cont.Names) > 0 {
@@ -83,10 +117,11 @@ func (c *Client) ListContainers(ctx context.Context, all bool) ([]ContainerInfo,
 
 		// Health status
 		if cont.Status != "" && strings.Contains(cont.Status, "(") {
-			if strings.Contains(cont.Status, "healthy") {
-				info.Health = "healthy"
-			} else if strings.Contains(cont.Status, "unhealthy") {
+			// Check unhealthy first since it contains "healthy"
+			if strings.Contains(cont.Status, "unhealthy") {
 				info.Health = "unhealthy"
+			} else if strings.Contains(cont.Status, "healthy") {
+				info.Health = "healthy"
 			} else if strings.Contains(cont.Status, "starting") {
 				info.Health = "starting"
 			}
@@ -110,14 +145,31 @@ func (c *Client) ListContainers(ctx context.Context, all bool) ([]ContainerInfo,
 
 // ImageInfo contains image information
 type ImageInfo struct {
-	ID         string
-	Repository string
-	Tag        string
-	Digest     string
-	Created    string
-	CreatedAt  time.Time
-	Size       int64
-	Dangling   bool
+	ID         string    `json:"id"`
+	Repository string    `json:"repository"`
+	Tag        string    `json:"tag"`
+	Digest     string    `json:"digest"`
+	Created    string    `json:"created"`
+	CreatedAt  time.Time `json:"created_at"`
+	Size       int64     `json:"size"`
+	Dangling   bool      `json:"dangling"`
+}
+
+// parseFilter converts "key=value,key=value" into docker filter arguments
+func parseFilter(filter string) (filters.Args, error) {
+	args := filters.NewArgs()
+	if filter == "" {
+		return args, nil
+	}
+
+	for _, term := range strings.Split(filter, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(term), "=")
+		if !ok || key == "" {
+			return args, fmt.Errorf("invalid filter %q (expected key=value)", term)
+		}
+		args.Add(key, value)
+	}
+	return args, nil
 }
 
 // ListImages lists Docker images
@@ -140,7 +192,7 @@ func (c *Client) ListImages(ctx context.Context, all, danglingOnly bool) ([]Imag
 			ID:        strings.TrimPrefix(img.ID, "sha256:"),
 			Size:      img.Size,
 			CreatedAt: time.Unix(img.Created, 0),
-			Created:   formatTime(time.Unix(img.Created, 0)),
+			Created:   format.Ago(time.Unix(img.Created, 0)),
 			Dangling:  len(img.RepoTags) == 0,
 		}
 
@@ -167,23 +219,40 @@ func (c *Client) ListImages(ctx context.Context, all, danglingOnly bool) ([]Imag
 
 // ContainerStats contains container statistics
 type ContainerStats struct {
-	ID            string
-	Name          string
-	CPUPercent    float64
-	MemoryUsage   int64
-	MemoryLimit   int64
-	MemoryPercent float64
-	NetInput      int64
-	NetOutput     int64
-	BlockInput    int64
-	BlockOutput   int64
-	PIDs          uint64
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	CPUPercent    float64        `json:"cpu_percent"`
+	MemoryUsage   int64          `json:"memory_usage"`
+	MemoryLimit   int64          `json:"memory_limit"`
+	MemoryPercent float64        `json:"memory_percent"`
+	NetInput      int64          `json:"net_input"`
+	NetOutput     int64          `json:"net_output"`
+	BlockInput    int64          `json:"block_input"`
+	BlockOutput   int64          `json:"block_output"`
+	PIDs          uint64         `json:"pids"`
+	CPUP95        float64        `json:"cpu_p95_percent,omitempty"`
+	MemoryP95     int64          `json:"memory_p95,omitempty"`
+	GPU           *GPUAllocation `json:"gpu,omitempty"`
 }
 
 // GetContainerStats gets statistics for containers
 func (c *Client) GetContainerStats(ctx context.Context, containers []ContainerInfo) ([]ContainerStats, error) {
 	var result []ContainerStats
 
+	// The host CPU count is only needed when a sample lacks its own, so it is
+	// looked up at most once
+	var ncpu uint32
+	var looked bool
+	hostCPUs := func() uint32 {
+		if !looked {
+			looked = true
+			if info, err := c.cli.Info(ctx); err == nil && info.NCPU > 0 {
+				ncpu = uint32(info.NCPU)
+			}
+		}
+		return ncpu
+	}
+
 	for _, cont := range containers {
 		stats, err := c.cli.ContainerStats(ctx, cont.ID, false)
 		if err != nil {
@@ -198,42 +267,9 @@ func (c *Client) GetContainerStats(ctx context.Context, containers []ContainerIn
 		}
 		stats.Body.Close()
 
-		cs := ContainerStats{
-			ID:   cont.ID,
-			Name: cont.Name,
-			PIDs: statsJSON.PidsStats.Current,
-		}
-
-		// Calculate CPU percent
-		cpuDelta := float64(statsJSON.CPUStats.CPUUsage.TotalUsage - statsJSON.PreCPUStats.CPUUsage.TotalUsage)
-		systemDelta := float64(statsJSON.CPUStats.SystemUsage - statsJSON.PreCPUStats.SystemUsage)
-		if systemDelta > 0 && cpuDelta > 0 {
-			cs.CPUPercent = (cpuDelta / systemDelta) * float64(statsJSON.CPUStats.OnlineCPUs) * 100.0
-		}
-
-		// Memory
-		cs.MemoryUsage = int64(statsJSON.MemoryStats.Usage)
-		cs.MemoryLimit = int64(statsJSON.MemoryStats.Limit)
-		if cs.MemoryLimit > 0 {
-			cs.MemoryPercent = float64(cs.MemoryUsage) / float64(cs.MemoryLimit) * 100.0
-		}
-
-		// Network I/O
-		for _, netStats := range statsJSON.Networks {
-			cs.NetInput += int64(netStats.RxBytes)
-			cs.NetOutput += int64(netStats.TxBytes)
-		}
-
-		// Block I/O
-		for _, bioEntry := range statsJSON.BlkioStats.IoServiceBytesRecursive {
-			switch bioEntry.Op {
-			case "Read", "read":
-				cs.BlockInput += int64(bioEntry.Value)
-			case "Write", "write":
-				cs.BlockOutput += int64(bioEntry.Value)
-			}
-		}
-
+		cs := containerStats(stats.OSType, &statsJSON, hostCPUs)
+		cs.ID = cont.ID
+		cs.Name = cont.Name
 		result = append(result, cs)
 	}
 
@@ -242,44 +278,56 @@ func (c *Client) GetContainerStats(ctx context.Context, containers []ContainerIn
 
 // MountInfo contains mount information
 type MountInfo struct {
-	Type        string
-	Name        string
-	Source      string
-	Destination string
-	Driver      string
-	Mode        string
-	RW          bool
+	Type        string `json:"type"`
+	Name        string `json:"name,omitempty"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Driver      string `json:"driver,omitempty"`
+	Mode        string `json:"mode,omitempty"`
+	RW          bool   `json:"rw"`
 }
 
 // NetworkInfo contains network information
 type NetworkInfo struct {
-	NetworkID  string
-	IPAddress  string
-	Gateway    string
-	MacAddress string
+	NetworkID  string `json:"network_id"`
+	IPAddress  string `json:"ip_address"`
+	Gateway    string `json:"gateway"`
+	MacAddress string `json:"mac_address"`
+}
+
+// ResourceLimits contains the resource limits and restart policy of a container
+type ResourceLimits struct {
+	Memory            int64          `json:"memory"`
+	MemoryReservation int64          `json:"memory_reservation"`
+	NanoCPUs          int64          `json:"nano_cpus"`
+	CPUShares         int64          `json:"cpu_shares"`
+	PidsLimit         int64          `json:"pids_limit"`
+	RestartPolicy     string         `json:"restart_policy"`
+	GPU               *GPUAllocation `json:"gpu,omitempty"`
 }
 
 // ContainerDetails contains detailed container information
 type ContainerDetails struct {
-	ID           string
-	Name         string
-	Image        string
-	Created      string
-	StartedAt    string
-	FinishedAt   string
-	State        string
-	Status       string
-	Health       string
-	HealthLog    string
-	RestartCount int
-	Platform     string
-	Command      string
-	Entrypoint   string
-	Env          []string
-	Ports        []PortMapping
-	Mounts       []MountInfo
-	Networks     map[string]NetworkInfo
-	Labels       map[string]string
+	ID           string                 `json:"id"`
+	Name         string                 `json:"name"`
+	Image        string                 `json:"image"`
+	Created      string                 `json:"created"`
+	StartedAt    string                 `json:"started_at"`
+	FinishedAt   string                 `json:"finished_at,omitempty"`
+	State        string                 `json:"state"`
+	Status       string                 `json:"status"`
+	Health       string                 `json:"health,omitempty"`
+	HealthLog    string                 `json:"health_log,omitempty"`
+	RestartCount int                    `json:"restart_count"`
+	Platform     string                 `json:"platform"`
+	Command      string                 `json:"command"`
+	Entrypoint   string                 `json:"entrypoint,omitempty"`
+	Env          []string               `json:"env"`
+	Ports        []PortMapping          `json:"ports"`
+	Mounts       []MountInfo            `json:"mounts"`
+	Networks     map[string]NetworkInfo `json:"networks"`
+	Labels       map[string]string      `json:"labels"`
+	Limits       ResourceLimits         `json:"limits"`
 }
 
 // InspectContainer inspects a container
@@ -313,6 +361,22 @@ func (c *Client) InspectContainer(ctx context.Context, containerID string) (*Con
 		details.Entrypoint = strings.Join(inspect.Config.Entrypoint, " ")
 	}
 
+	// Limits
+	if inspect.HostConfig != nil {
+		resources := inspect.HostConfig.Resources
+		details.Limits = ResourceLimits{
+			Memory:            resources.Memory,
+			MemoryReservation: resources.MemoryReservation,
+			NanoCPUs:          resources.NanoCPUs,
+			CPUShares:         resources.CPUShares,
+			RestartPolicy:     string(inspect.HostConfig.RestartPolicy.Name),
+			GPU:               gpuAllocation(inspect.HostConfig, inspect.Config.Env),
+		}
+		if resources.PidsLimit != nil {
+			details.Limits.PidsLimit = *resources.PidsLimit
+		}
+	}
+
 	// Health
 	if inspect.State.Health != nil {
 		details.Health = inspect.State.Health.Status
@@ -373,10 +437,10 @@ type LogOptions struct {
 
 // LogLine represents a log line
 type LogLine struct {
-	Timestamp string
-	Stream    string
-	Content   string
-	Level     string
+	Timestamp string `json:"timestamp,omitempty"`
+	Stream    string `json:"stream"`
+	Content   string `json:"content"`
+	Level     string `json:"level,omitempty"`
 }
 
 // StreamLogs streams container logs
@@ -388,6 +452,9 @@ func (c *Client) StreamLogs(ctx context.Context, containerID string, opts LogOpt
 		Follow:     opts.Follow,
 		Tail:       fmt.Sprintf("%d", opts.Tail),
 	}
+	if opts.Tail <= 0 {
+		options.Tail = "all"
+	}
 
 	if opts.Since != "" {
 		options.Since = opts.Since
@@ -445,10 +512,10 @@ func (c *Client) StreamLogs(ctx context.Context, containerID string, opts LogOpt
 		}
 
 		// Detect log level
-		line.Level = detectLogLevel(line.Content)
+		line.Level = loglevel.Detect(line.Content)
 
 		// Filter by level if specified
-		if opts.Level != "" && !matchesLevel(line.Level, opts.Level) {
+		if opts.Level != "" && !loglevel.Matches(line.Level, opts.Level) {
 			continue
 		}
 
@@ -458,50 +525,6 @@ func (c *Client) StreamLogs(ctx context.Context, containerID string, opts LogOpt
 	return nil
 }
 
-func detectLogLevel(content string) string {
-	lower := strings.ToLower(content)
-
-	patterns := map[string]*regexp.Regexp{
-		"error": regexp.MustCompile(`\b(error|err|fatal|panic|exception)\b`),
-		"warn":  regexp.MustCompile(`\b(warn|warning)\b`),
-		"info":  regexp.MustCompile(`\b(info)\b`),
-		"debug": regexp.MustCompile(`\b(debug|trace)\b`),
-	}
-
-	for level, pattern := range patterns {
-		if pattern.MatchString(lower) {
-			return level
-		}
-	}
-
-	return ""
-}
-
-func matchesLevel(detected, filter string) bool {
-	filter = strings.ToLower(filter)
-	detected = strings.ToLower(detected)
-
-	if filter == detected {
-		return true
-	}
-
-	// Include higher severity levels
-	levels := []string{"debug", "info", "warn", "error"}
-	filterIdx := -1
-	detectedIdx := -1
-
-	for i, l := range levels {
-		if l == filter {
-			filterIdx = i
-		}
-		if l == detected {
-			detectedIdx = i
-		}
-	}
-
-	return detectedIdx >= filterIdx
-}
-
 // NetworkDetails contains network details
 type NetworkDetails struct {
 	ID   string
@@ -516,7 +539,7 @@ type VolumeDetails struct {
 
 // FindStoppedContainers finds stopped containers
 func (c *Client) FindStoppedContainers(ctx context.Context) ([]ContainerInfo, error) {
-	containers, err := c.ListContainers(ctx, true)
+	containers, err := c.ListContainers(ctx, true, "")
 	if err != nil {
 		return nil, err
 	}
@@ -532,14 +555,12 @@ func (c *Client) FindStoppedContainers(ctx context.Context) ([]ContainerInfo, er
 
 // RemoveContainers removes containers
 func (c *Client) RemoveContainers(ctx context.Context, containers []ContainerInfo) (int, int64, error) {
-	deleted := 0
-	for _, cont := range containers {
-		err := c.cli.ContainerRemove(ctx, cont.ID, container.RemoveOptions{})
-		if err == nil {
-			deleted++
-		}
-	}
-	return deleted, 0, nil
+	names := make([]string, len(containers))
+	errs := batch.Run(len(containers), batch.DefaultWorkers, func(i int) error {
+		names[i] = containers[i].Name
+		return ignoreNotFound(c.cli.ContainerRemove(ctx, containers[i].ID, container.RemoveOptions{}))
+	})
+	return batch.Succeeded(errs), 0, batch.Collect(names, errs)
 }
 
 // FindUnusedImages finds unused images
@@ -549,17 +570,32 @@ func (c *Client) FindUnusedImages(ctx context.Context, all bool) ([]ImageInfo, e
 
 // RemoveImages removes images
 func (c *Client) RemoveImages(ctx context.Context, images []ImageInfo) (int, int64, error) {
-	deleted := 0
-	var spaceReclaimed int64
+	names := make([]string, len(images))
+	errs := batch.Run(len(images), batch.DefaultWorkers, func(i int) error {
+		names[i] = imageLabel(images[i])
+		_, err := c.cli.ImageRemove(ctx, images[i].ID, types.ImageRemoveOptions{})
+		return ignoreNotFound(err)
+	})
 
-	for _, img := range images {
-		_, err := c.cli.ImageRemove(ctx, img.ID, types.ImageRemoveOptions{})
+	var spaceReclaimed int64
+	for i, err := range errs {
 		if err == nil {
-			deleted++
-			spaceReclaimed += img.Size
+			spaceReclaimed += images[i].Size
 		}
 	}
-	return deleted, spaceReclaimed, nil
+	return batch.Succeeded(errs), spaceReclaimed, batch.Collect(names, errs)
+}
+
+// imageLabel names an image by repository and tag, or by short ID when untagged
+func imageLabel(img ImageInfo) string {
+	if img.Repository != "" && img.Repository != "<none>" {
+		return img.Repository + ":" + img.Tag
+	}
+	id := strings.TrimPrefix(img.ID, "sha256:")
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return id
 }
 
 // FindUnusedNetworks finds unused networks
@@ -595,14 +631,12 @@ func (c *Client) FindUnusedNetworks(ctx context.Context) ([]NetworkDetails, erro
 
 // RemoveNetworks removes networks
 func (c *Client) RemoveNetworks(ctx context.Context, networks []NetworkDetails) (int, error) {
-	deleted := 0
-	for _, net := range networks {
-		err := c.cli.NetworkRemove(ctx, net.ID)
-		if err == nil {
-			deleted++
-		}
-	}
-	return deleted, nil
+	names := make([]string, len(networks))
+	errs := batch.Run(len(networks), batch.DefaultWorkers, func(i int) error {
+		names[i] = networks[i].Name
+		return ignoreNotFound(c.cli.NetworkRemove(ctx, networks[i].ID))
+	})
+	return batch.Succeeded(errs), batch.Collect(names, errs)
 }
 
 // FindUnusedVolumes finds unused volumes
@@ -627,17 +661,27 @@ func (c *Client) FindUnusedVolumes(ctx context.Context) ([]VolumeDetails, error)
 
 // RemoveVolumes removes volumes
 func (c *Client) RemoveVolumes(ctx context.Context, volumes []VolumeDetails) (int, int64, error) {
-	deleted := 0
-	var spaceReclaimed int64
+	names := make([]string, len(volumes))
+	errs := batch.Run(len(volumes), batch.DefaultWorkers, func(i int) error {
+		names[i] = volumes[i].Name
+		return ignoreNotFound(c.cli.VolumeRemove(ctx, volumes[i].Name, false))
+	})
 
-	for _, vol := range volumes {
-		err := c.cli.VolumeRemove(ctx, vol.Name, false)
+	var spaceReclaimed int64
+	for i, err := range errs {
 		if err == nil {
-			deleted++
-			spaceReclaimed += vol.Size
+			spaceReclaimed += volumes[i].Size
 		}
 	}
-	return deleted, spaceReclaimed, nil
+	return batch.Succeeded(errs), spaceReclaimed, batch.Collect(names, errs)
+}
+
+// ignoreNotFound treats objects that are already gone as removed
+func ignoreNotFound(err error) error {
+	if client.IsErrNotFound(err) {
+		return nil
+	}
+	return err
 }
 
 // GetBuildCacheSize gets build cache size
@@ -666,20 +710,3 @@ func (c *Client) PruneBuildCache(ctx context.Context) (int64, error) {
 	return int64(report.SpaceReclaimed), nil
 }
 
-func formatTime(t time.Time) string {
-	d := time.Since(t)
-
-	switch {
-	case d < time.Minute:
-		return fmt.Sprintf("%d seconds ago", int(d.Seconds()))
-	case d < time.Hour:
-		return fmt.Sprintf("%d minutes ago", int(d.Minutes()))
-	case d < 24*time.Hour:
-		return fmt.Sprintf("%d hours ago", int(d.Hours()))
-	case d < 7*24*time.Hour:
-		return fmt.Sprintf("%d days ago", int(d.Hours()/24))
-	default:
-		return fmt.Sprintf("%d weeks ago", int(d.Hours()/(24*7)))
-	}
-}
-
@@ -0,0 +1,191 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// multiplexed frames lines the way the daemon does for containers without a TTY
+func multiplexed(t *testing.T, frames ...[2]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	stdout := stdcopy.NewStdWriter(&buf, stdcopy.Stdout)
+	stderr := stdcopy.NewStdWriter(&buf, stdcopy.Stderr)
+	for _, f := range frames {
+		w := stdout
+		if f[0] == "stderr" {
+			w = stderr
+		}
+		if _, err := io.WriteString(w, f[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestStreamLogs(t *testing.T) {
+	logs := [][2]string{
+		{"stdout", "2024-03-01T12:00:00.000000000Z GET /health 200\n"},
+		{"stderr", "2024-03-01T12:00:01.000000000Z ERROR database unreachable\n"},
+		{"stdout", "2024-03-01T12:00:02.000000000Z WARN slow query took 2.3s\n"},
+	}
+
+	tests := []struct {
+		name string
+		opts LogOptions
+		want []LogLine
+	}{
+		{
+			name: "demultiplexes both streams",
+			opts: LogOptions{},
+			want: []LogLine{
+				{Stream: "stdout", Content: "2024-03-01T12:00:00.000000000Z GET /health 200"},
+				{Stream: "stderr", Content: "2024-03-01T12:00:01.000000000Z ERROR database unreachable", Level: "error"},
+				{Stream: "stdout", Content: "2024-03-01T12:00:02.000000000Z WARN slow query took 2.3s", Level: "warn"},
+			},
+		},
+		{
+			name: "splits timestamps",
+			opts: LogOptions{Timestamps: true, Level: "error"},
+			want: []LogLine{
+				{Timestamp: "2024-03-01T12:00:01.000000000Z", Stream: "stderr", Content: "ERROR database unreachable", Level: "error"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, api := newFakeClient(t)
+			api.logs = multiplexed(t, logs...)
+
+			var got []LogLine
+			if err := c.StreamLogs(context.Background(), "web", tt.opts, func(l LogLine) { got = append(got, l) }); err != nil {
+				t.Fatalf("StreamLogs: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("line %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStreamLogsTruncatedFrame(t *testing.T) {
+	c, api := newFakeClient(t)
+	frame := multiplexed(t, [2]string{"stdout", "connection reset by peer\n"})
+	api.logs = frame[:len(frame)-4]
+
+	var lines int
+	err := c.StreamLogs(context.Background(), "web", LogOptions{}, func(LogLine) { lines++ })
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("err = %v, want io.ErrUnexpectedEOF", err)
+	}
+	if lines != 0 {
+		t.Errorf("got %d lines from a truncated frame", lines)
+	}
+}
+
+func TestListContainers(t *testing.T) {
+	c, _ := newFakeClient(t)
+	containers, err := c.ListContainers(context.Background(), true, "")
+	if err != nil {
+		t.Fatalf("ListContainers: %v", err)
+	}
+
+	health := make(map[string]string)
+	for _, cont := range containers {
+		health[cont.Name] = cont.Health
+	}
+	if health["web"] != "healthy" || health["worker"] != "unhealthy" || health["migrate"] != "" {
+		t.Errorf("health = %v, want web healthy and worker unhealthy", health)
+	}
+	if web := containers[0]; len(web.Ports) != 1 || web.Ports[0].PublicPort != 8080 {
+		t.Errorf("web ports = %+v, want 8080->80", web.Ports)
+	}
+
+	if _, err := c.ListContainers(context.Background(), true, "status"); err == nil {
+		t.Error("ListContainers accepted a filter without a value")
+	}
+}
+
+func TestCleanupContainers(t *testing.T) {
+	c, api := newFakeClient(t)
+
+	stopped, err := c.FindStoppedContainers(context.Background())
+	if err != nil {
+		t.Fatalf("FindStoppedContainers: %v", err)
+	}
+	var names []string
+	for _, cont := range stopped {
+		names = append(names, cont.Name)
+	}
+	if strings.Join(names, ",") != "migrate,cron" {
+		t.Fatalf("stopped containers = %v, want exited and dead ones", names)
+	}
+
+	// Already removed containers count as cleaned up; other failures are reported by name
+	api.failures[stopped[0].ID] = errdefs.NotFound(errors.New("no such container"))
+	api.failures[stopped[1].ID] = errdefs.Conflict(errors.New("removal already in progress"))
+
+	removed, _, err := c.RemoveContainers(context.Background(), stopped)
+	if removed != 1 {
+		t.Errorf("removed = %d, want 1", removed)
+	}
+	if err == nil || !strings.Contains(err.Error(), "cron") || strings.Contains(err.Error(), "migrate") {
+		t.Errorf("err = %v, want only the cron failure", err)
+	}
+}
+
+func TestCleanupNetworks(t *testing.T) {
+	c, api := newFakeClient(t)
+
+	networks, err := c.FindUnusedNetworks(context.Background())
+	if err != nil {
+		t.Fatalf("FindUnusedNetworks: %v", err)
+	}
+	if len(networks) != 1 || networks[0].Name != "legacy_default" {
+		t.Fatalf("unused networks = %+v, want only legacy_default", networks)
+	}
+
+	removed, err := c.RemoveNetworks(context.Background(), networks)
+	if err != nil || removed != 1 || len(api.removed) != 1 || api.removed[0] != networks[0].ID {
+		t.Errorf("RemoveNetworks = %d, %v (removed %v), want legacy_default removed", removed, err, api.removed)
+	}
+}
+
+func TestCleanupVolumes(t *testing.T) {
+	c, api := newFakeClient(t)
+
+	volumes, err := c.FindUnusedVolumes(context.Background())
+	if err != nil {
+		t.Fatalf("FindUnusedVolumes: %v", err)
+	}
+	if len(volumes) != 2 {
+		t.Fatalf("unused volumes = %+v, want 2", volumes)
+	}
+
+	api.failures["shop_pgdata"] = errdefs.Conflict(errors.New("volume is in use"))
+	removed, reclaimed, err := c.RemoveVolumes(context.Background(), volumes)
+	if removed != 1 || reclaimed != 1048576 {
+		t.Errorf("RemoveVolumes = %d removed, %d bytes, want 1 and only the removed volume's size", removed, reclaimed)
+	}
+	if err == nil || !strings.Contains(err.Error(), "shop_pgdata") {
+		t.Errorf("err = %v, want the shop_pgdata failure", err)
+	}
+
+	sort.Strings(api.removed)
+	if strings.Join(api.removed, ",") != "3c1d7e0b2a9f" {
+		t.Errorf("removed = %v, want only the anonymous volume", api.removed)
+	}
+}
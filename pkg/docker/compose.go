@@ -0,0 +1,148 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Compose labels set by docker compose on the containers it creates
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+	composeOneoffLabel  = "com.docker.compose.oneoff"
+)
+
+// Compose service health states, worst first
+const (
+	ServiceUnhealthy = "unhealthy"
+	ServiceStarting  = "starting"
+	ServiceHealthy   = "healthy"
+)
+
+// ComposeService summarizes the containers of one compose service
+type ComposeService struct {
+	Project    string   `json:"project"`
+	Service    string   `json:"service"`
+	Replicas   int      `json:"replicas"`
+	Running    int      `json:"running"`
+	Healthy    int      `json:"healthy"`
+	Unhealthy  int      `json:"unhealthy"`
+	Starting   int      `json:"starting"`
+	Completed  int      `json:"completed"`
+	Failed     int      `json:"failed"`
+	Endpoints  []string `json:"endpoints,omitempty"`
+	Containers []string `json:"containers"`
+	Status     string   `json:"status"`
+}
+
+// ComposeServices groups compose-managed containers by project and service.
+// One-off containers from docker compose run are skipped. An empty project
+// includes all projects.
+func (c *Client) ComposeServices(ctx context.Context, project string) ([]ComposeService, error) {
+	filter := "label=" + composeProjectLabel
+	if project != "" {
+		filter += "=" + project
+	}
+	containers, err := c.ListContainers(ctx, true, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list compose containers: %w", err)
+	}
+
+	services := make(map[string]*ComposeService)
+	for _, cont := range containers {
+		if strings.EqualFold(cont.Labels[composeOneoffLabel], "true") {
+			continue
+		}
+		key := cont.Labels[composeProjectLabel] + "/" + cont.Labels[composeServiceLabel]
+		svc, ok := services[key]
+		if !ok {
+			svc = &ComposeService{
+				Project: cont.Labels[composeProjectLabel],
+				Service: cont.Labels[composeServiceLabel],
+			}
+			services[key] = svc
+		}
+		svc.add(cont)
+	}
+
+	result := make([]ComposeService, 0, len(services))
+	for _, svc := range services {
+		svc.Status = svc.status()
+		sort.Strings(svc.Endpoints)
+		sort.Strings(svc.Containers)
+		result = append(result, *svc)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Project != result[j].Project {
+			return result[i].Project < result[j].Project
+		}
+		return result[i].Service < result[j].Service
+	})
+	return result, nil
+}
+
+// add counts a container towards the service's replica and health totals
+func (s *ComposeService) add(cont ContainerInfo) {
+	s.Replicas++
+	s.Containers = append(s.Containers, cont.Name)
+
+	switch {
+	case cont.State == "running":
+		s.Running++
+		switch cont.Health {
+		case "healthy":
+			s.Healthy++
+		case "unhealthy":
+			s.Unhealthy++
+		case "starting":
+			s.Starting++
+		}
+	case cont.State == "exited" && strings.HasPrefix(cont.Status, "Exited (0)"):
+		// Init and migration services exit cleanly by design
+		s.Completed++
+	case cont.State == "created":
+		s.Starting++
+	default:
+		s.Failed++
+	}
+
+	for _, port := range cont.Ports {
+		if port.PublicPort == 0 {
+			continue
+		}
+		ip := port.IP
+		if ip == "" || ip == "0.0.0.0" || ip == "::" {
+			ip = "localhost"
+		}
+		if strings.Contains(ip, ":") {
+			ip = "[" + ip + "]"
+		}
+		endpoint := fmt.Sprintf("%s:%d/%s", ip, port.PublicPort, port.Type)
+		if !containsString(s.Endpoints, endpoint) {
+			s.Endpoints = append(s.Endpoints, endpoint)
+		}
+	}
+}
+
+// status rolls replica states up into a single service health
+func (s *ComposeService) status() string {
+	switch {
+	case s.Unhealthy > 0 || s.Failed > 0:
+		return ServiceUnhealthy
+	case s.Starting > 0:
+		return ServiceStarting
+	default:
+		return ServiceHealthy
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
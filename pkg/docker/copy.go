@@ -0,0 +1,238 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// CopyStats summarizes a completed copy
+type CopyStats struct {
+	Files int   `json:"files"`
+	Dirs  int   `json:"dirs"`
+	Bytes int64 `json:"bytes"`
+}
+
+// CopyProgress reports bytes copied so far; total is 0 when the size is not known up front
+type CopyProgress func(copied, total int64)
+
+// CopyFromContainer copies a file or directory out of a container to a local path.
+// If dstPath is an existing directory the source is copied into it, otherwise it is
+// copied as dstPath, mirroring docker cp.
+func (c *Client) CopyFromContainer(ctx context.Context, containerID, srcPath, dstPath string, onProgress CopyProgress) (CopyStats, error) {
+	reader, stat, err := c.cli.CopyFromContainer(ctx, containerID, srcPath)
+	if err != nil {
+		return CopyStats{}, fmt.Errorf("failed to copy from container: %w", err)
+	}
+	defer reader.Close()
+
+	var total int64
+	if !stat.Mode.IsDir() {
+		total = stat.Size
+	}
+
+	// The archive root is the source base name; rename it unless copying into a directory
+	destDir, rootName := dstPath, ""
+	if info, err := os.Stat(dstPath); err != nil || !info.IsDir() {
+		destDir, rootName = filepath.Dir(dstPath), filepath.Base(dstPath)
+	}
+
+	var stats CopyStats
+	tr := tar.NewReader(reader)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return stats, fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		name := path.Clean(hdr.Name)
+		if rootName != "" {
+			if i := strings.Index(name, "/"); i >= 0 {
+				name = rootName + name[i:]
+			} else {
+				name = rootName
+			}
+		}
+		target := filepath.Join(destDir, filepath.FromSlash(name))
+		if rel, err := filepath.Rel(destDir, target); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+			return stats, fmt.Errorf("refusing to write %s outside %s", hdr.Name, destDir)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, hdr.FileInfo().Mode().Perm()|0o700); err != nil {
+				return stats, fmt.Errorf("failed to create directory: %w", err)
+			}
+			stats.Dirs++
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return stats, fmt.Errorf("failed to create directory: %w", err)
+			}
+			n, err := writeFile(target, tr, hdr.FileInfo().Mode().Perm(), func(n int64) {
+				if onProgress != nil {
+					onProgress(stats.Bytes+n, total)
+				}
+			})
+			if err != nil {
+				return stats, err
+			}
+			stats.Files++
+			stats.Bytes += n
+		case tar.TypeSymlink:
+			_ = os.Remove(target)
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return stats, fmt.Errorf("failed to create symlink: %w", err)
+			}
+			stats.Files++
+		}
+	}
+
+	return stats, nil
+}
+
+// CopyToContainer copies a local file or directory into a container. If dstPath is an
+// existing directory in the container the source is copied into it, otherwise it is
+// copied as dstPath.
+func (c *Client) CopyToContainer(ctx context.Context, containerID, srcPath, dstPath string, onProgress CopyProgress) (CopyStats, error) {
+	info, err := os.Stat(srcPath)
+	if err != nil {
+		return CopyStats{}, fmt.Errorf("failed to stat %s: %w", srcPath, err)
+	}
+
+	destDir, rootName := dstPath, filepath.Base(srcPath)
+	if stat, err := c.cli.ContainerStatPath(ctx, containerID, dstPath); err != nil || !stat.Mode.IsDir() {
+		destDir, rootName = path.Dir(dstPath), path.Base(dstPath)
+	}
+
+	var stats CopyStats
+	var total int64
+	err = filepath.Walk(srcPath, func(_ string, fi os.FileInfo, err error) error {
+		if err == nil && fi.Mode().IsRegular() {
+			total += fi.Size()
+		}
+		return err
+	})
+	if err != nil {
+		return stats, fmt.Errorf("failed to read %s: %w", srcPath, err)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeArchive(pw, srcPath, rootName, info, &stats, func(n int64) {
+			if onProgress != nil {
+				onProgress(n, total)
+			}
+		}))
+	}()
+
+	err = c.cli.CopyToContainer(ctx, containerID, destDir, pr, types.CopyToContainerOptions{})
+	pr.Close()
+	if err != nil {
+		return stats, fmt.Errorf("failed to copy to container: %w", err)
+	}
+	return stats, nil
+}
+
+// writeArchive tars srcPath with its root renamed to rootName
+func writeArchive(w io.Writer, srcPath, rootName string, info os.FileInfo, stats *CopyStats, progress func(int64)) error {
+	tw := tar.NewWriter(w)
+
+	walk := func(file string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcPath, file)
+		if err != nil {
+			return err
+		}
+		name := path.Join(rootName, filepath.ToSlash(rel))
+
+		link := ""
+		if fi.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(fi, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = name
+		if fi.IsDir() {
+			hdr.Name += "/"
+			stats.Dirs++
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			if !fi.IsDir() {
+				stats.Files++
+			}
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		base := stats.Bytes
+		n, err := io.Copy(tw, &progressReader{r: f, onRead: func(n int64) { progress(base + n) }})
+		stats.Files++
+		stats.Bytes += n
+		return err
+	}
+
+	var err error
+	if info.IsDir() {
+		err = filepath.Walk(srcPath, walk)
+	} else {
+		err = walk(srcPath, info, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", srcPath, err)
+	}
+	return tw.Close()
+}
+
+func writeFile(target string, r io.Reader, mode os.FileMode, progress func(int64)) (int64, error) {
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", target, err)
+	}
+	defer f.Close()
+
+	n, err := io.Copy(f, &progressReader{r: r, onRead: progress})
+	if err != nil {
+		return n, fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return n, nil
+}
+
+// progressReader reports the running byte count as it is read
+type progressReader struct {
+	r      io.Reader
+	n      int64
+	onRead func(int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.n += int64(n)
+	if n > 0 && p.onRead != nil {
+		p.onRead(p.n)
+	}
+	return n, err
+}
@@ -0,0 +1,186 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+)
+
+// Drift change kinds
+const (
+	DriftAdded   = "added"
+	DriftChanged = "changed"
+	DriftDeleted = "deleted"
+)
+
+// maxDriftStats caps the stat calls made to find setuid files, so a container
+// that rewrote a whole tree does not take minutes to analyze
+const maxDriftStats = 2000
+
+// binaryDirs hold executables; any file added or changed in them is suspect
+var binaryDirs = []string{"/bin/", "/sbin/", "/usr/bin/", "/usr/sbin/", "/usr/local/bin/", "/usr/local/sbin/"}
+
+// libraryDirs hold shared libraries; only .so files in them are flagged, as
+// interpreters also write caches there
+var libraryDirs = []string{"/lib/", "/lib64/", "/usr/lib/", "/usr/lib64/", "/usr/local/lib/"}
+
+// DriftAPI is the part of the Docker API drift analysis needs. The Docker SDK
+// client and API both satisfy it.
+type DriftAPI interface {
+	ContainerDiff(ctx context.Context, container string) ([]container.FilesystemChange, error)
+	ContainerStatPath(ctx context.Context, container, path string) (types.ContainerPathStat, error)
+}
+
+// FileChange is a path that differs from the container's image
+type FileChange struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+	Mode string `json:"mode,omitempty"`
+	// Indicator says why the change may be a sign of compromise
+	Indicator string `json:"indicator,omitempty"`
+}
+
+// DriftReport lists a container's filesystem changes relative to its image
+type DriftReport struct {
+	Container  string       `json:"container"`
+	ID         string       `json:"id"`
+	Image      string       `json:"image"`
+	Changes    []FileChange `json:"changes"`
+	Added      int          `json:"added"`
+	Changed    int          `json:"changed"`
+	Deleted    int          `json:"deleted"`
+	Indicators int          `json:"indicators"`
+	// Unchecked counts added or changed paths past the stat limit, which
+	// were not checked for setuid bits
+	Unchecked int `json:"unchecked,omitempty"`
+}
+
+// Drift reports the filesystem changes of a container since it was created
+func (c *Client) Drift(ctx context.Context, nameOrID string) (*DriftReport, error) {
+	inspect, err := c.cli.ContainerInspect(ctx, nameOrID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container %s: %w", nameOrID, err)
+	}
+
+	changes, unchecked, err := ContainerDrift(ctx, c.cli, inspect.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DriftReport{
+		Container: strings.TrimPrefix(inspect.Name, "/"),
+		ID:        inspect.ID,
+		Image:     inspect.Config.Image,
+		Changes:   changes,
+		Unchecked: unchecked,
+	}
+	for _, ch := range changes {
+		switch ch.Kind {
+		case DriftAdded:
+			report.Added++
+		case DriftChanged:
+			report.Changed++
+		case DriftDeleted:
+			report.Deleted++
+		}
+		if ch.Indicator != "" {
+			report.Indicators++
+		}
+	}
+	return report, nil
+}
+
+// ContainerDrift diffs a container against its image and flags modified
+// binaries and new setuid or setgid files. It also returns how many paths
+// were not stat'ed because of the stat limit.
+func ContainerDrift(ctx context.Context, api DriftAPI, containerID string) ([]FileChange, int, error) {
+	diff, err := api.ContainerDiff(ctx, containerID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to diff container %s: %w", containerID, err)
+	}
+
+	changes := make([]FileChange, 0, len(diff))
+	stats, unchecked := 0, 0
+	for _, d := range diff {
+		ch := FileChange{Path: d.Path, Kind: driftKind(d.Kind)}
+		if ch.Kind == DriftDeleted {
+			changes = append(changes, ch)
+			continue
+		}
+
+		var mode os.FileMode
+		statted := false
+		if stats < maxDriftStats {
+			stats++
+			if stat, err := api.ContainerStatPath(ctx, containerID, d.Path); err == nil {
+				mode, statted = stat.Mode, true
+				ch.Mode = stat.Mode.String()
+			}
+		} else {
+			unchecked++
+		}
+
+		if !statted || !mode.IsDir() {
+			ch.Indicator = driftIndicator(ch, mode)
+		}
+		changes = append(changes, ch)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, unchecked, nil
+}
+
+func driftKind(kind container.ChangeType) string {
+	switch kind {
+	case container.ChangeAdd:
+		return DriftAdded
+	case container.ChangeDelete:
+		return DriftDeleted
+	default:
+		return DriftChanged
+	}
+}
+
+// driftIndicator explains why an added or changed file is suspicious, or
+// returns "" when it is not
+func driftIndicator(ch FileChange, mode os.FileMode) string {
+	switch {
+	case mode&os.ModeSetuid != 0 && ch.Kind == DriftAdded:
+		return "new setuid file"
+	case mode&os.ModeSetuid != 0:
+		return "modified setuid file"
+	case mode&os.ModeSetgid != 0 && ch.Kind == DriftAdded:
+		return "new setgid file"
+	case mode&os.ModeSetgid != 0:
+		return "modified setgid file"
+	case hasAnyPrefix(ch.Path, binaryDirs) && ch.Kind == DriftAdded:
+		return "new binary"
+	case hasAnyPrefix(ch.Path, binaryDirs):
+		return "modified binary"
+	case hasAnyPrefix(ch.Path, libraryDirs) && isSharedLibrary(ch.Path):
+		return "modified shared library"
+	default:
+		return ""
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSharedLibrary matches libfoo.so and versioned names such as libfoo.so.1.2
+func isSharedLibrary(p string) bool {
+	return strings.HasSuffix(p, ".so") || strings.Contains(p, ".so.")
+}
@@ -0,0 +1,19 @@
+package docker
+
+import (
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/docker/docker/client"
+)
+
+func init() {
+	exitcode.RegisterClassifier(classifyError)
+}
+
+// classifyError marks an unreachable daemon as a connection error; the SDK
+// replaces the underlying network error, so it is not caught as a net.Error
+func classifyError(err error) error {
+	if client.IsErrConnectionFailed(err) {
+		return exitcode.ConnectionError(err)
+	}
+	return nil
+}
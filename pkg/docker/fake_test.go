@@ -0,0 +1,102 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+)
+
+// fakeAPI serves recorded daemon responses from testdata. Methods the tests
+// do not override fall through to the nil embedded API and panic.
+type fakeAPI struct {
+	API
+
+	t        *testing.T
+	logs     []byte
+	failures map[string]error
+
+	mu      sync.Mutex
+	removed []string
+}
+
+func newFakeClient(t *testing.T) (*Client, *fakeAPI) {
+	api := &fakeAPI{t: t, failures: make(map[string]error)}
+	return NewClientWithAPI(api), api
+}
+
+// fixture decodes a recorded API response from testdata into v
+func (f *fakeAPI) fixture(name string, v interface{}) {
+	f.t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		f.t.Fatalf("failed to decode %s: %v", name, err)
+	}
+}
+
+func (f *fakeAPI) remove(id string) error {
+	if err := f.failures[id]; err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.removed = append(f.removed, id)
+	return nil
+}
+
+func (f *fakeAPI) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
+	var containers []types.Container
+	f.fixture("containers.json", &containers)
+	return containers, nil
+}
+
+func (f *fakeAPI) ContainerLogs(ctx context.Context, container string, options container.LogsOptions) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(f.logs)), nil
+}
+
+func (f *fakeAPI) ContainerRemove(ctx context.Context, container string, options container.RemoveOptions) error {
+	return f.remove(container)
+}
+
+func (f *fakeAPI) NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	var networks []types.NetworkResource
+	f.fixture("networks.json", &networks)
+	return networks, nil
+}
+
+func (f *fakeAPI) NetworkInspect(ctx context.Context, network string, options types.NetworkInspectOptions) (types.NetworkResource, error) {
+	networks, _ := f.NetworkList(ctx, types.NetworkListOptions{})
+	for _, n := range networks {
+		if n.ID == network {
+			return n, nil
+		}
+	}
+	return types.NetworkResource{}, errdefs.NotFound(errors.New("network " + network + " not found"))
+}
+
+func (f *fakeAPI) NetworkRemove(ctx context.Context, network string) error {
+	return f.remove(network)
+}
+
+func (f *fakeAPI) VolumeList(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error) {
+	var volumes volume.ListResponse
+	f.fixture("volumes.json", &volumes)
+	return volumes, nil
+}
+
+func (f *fakeAPI) VolumeRemove(ctx context.Context, volumeID string, force bool) error {
+	return f.remove(volumeID)
+}
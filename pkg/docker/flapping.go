@@ -0,0 +1,199 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/batch"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// FlappingOptions configures restart loop detection
+type FlappingOptions struct {
+	// Window is how far back container die events are counted
+	Window time.Duration
+	// MinRestarts is the number of restarts within the window that counts as flapping
+	MinRestarts int
+	// StderrLines is how many trailing stderr lines to collect per container
+	StderrLines int
+}
+
+// FlappingContainer is a container that keeps exiting and being restarted
+type FlappingContainer struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Image         string    `json:"image"`
+	State         string    `json:"state"`
+	RestartPolicy string    `json:"restart_policy"`
+	RestartCount  int       `json:"restart_count"`
+	Dies          int       `json:"dies_in_window"`
+	ExitCodes     []int     `json:"exit_codes"`
+	LastExitCode  int       `json:"last_exit_code"`
+	OOMKilled     bool      `json:"oom_killed"`
+	Error         string    `json:"error,omitempty"`
+	StartedAt     time.Time `json:"started_at"`
+	FinishedAt    time.Time `json:"finished_at"`
+	LastStderr    []string  `json:"last_stderr,omitempty"`
+}
+
+// containerDies counts die events for one container and the distinct exit codes seen
+type containerDies struct {
+	count     int
+	exitCodes []int
+}
+
+// FindFlapping finds containers in restart loops from die events within the window,
+// the restart count and the time since the last start
+func (c *Client) FindFlapping(ctx context.Context, opts FlappingOptions) ([]FlappingContainer, error) {
+	dies, err := c.dieEvents(ctx, opts.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := c.ListContainers(ctx, true, "")
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().Add(-opts.Window)
+	found := make([]*FlappingContainer, len(containers))
+	errs := batch.Run(len(containers), batch.DefaultWorkers, func(i int) error {
+		inspect, err := c.cli.ContainerInspect(ctx, containers[i].ID)
+		if err != nil {
+			return err
+		}
+		d := dies[inspect.ID]
+		startedAt, _ := time.Parse(time.RFC3339Nano, inspect.State.StartedAt)
+		finishedAt, _ := time.Parse(time.RFC3339Nano, inspect.State.FinishedAt)
+
+		// The restart count is cumulative, so only trust it when the container restarted recently
+		flapping := inspect.State.Restarting ||
+			d.count >= opts.MinRestarts ||
+			(inspect.RestartCount >= opts.MinRestarts && startedAt.After(since))
+		if !flapping {
+			return nil
+		}
+
+		fc := &FlappingContainer{
+			ID:           inspect.ID,
+			Name:         strings.TrimPrefix(inspect.Name, "/"),
+			Image:        inspect.Config.Image,
+			State:        inspect.State.Status,
+			RestartCount: inspect.RestartCount,
+			Dies:         d.count,
+			ExitCodes:    d.exitCodes,
+			LastExitCode: inspect.State.ExitCode,
+			OOMKilled:    inspect.State.OOMKilled,
+			Error:        inspect.State.Error,
+			StartedAt:    startedAt,
+			FinishedAt:   finishedAt,
+		}
+		if inspect.HostConfig != nil {
+			fc.RestartPolicy = string(inspect.HostConfig.RestartPolicy.Name)
+		}
+		if opts.StderrLines > 0 {
+			fc.LastStderr, _ = c.lastStderr(ctx, inspect.ID, opts.StderrLines)
+		}
+		found[i] = fc
+		return nil
+	})
+	names := make([]string, len(containers))
+	for i, cont := range containers {
+		names[i] = cont.Name
+	}
+	if err := batch.Collect(names, errs); err != nil {
+		return nil, fmt.Errorf("failed to inspect containers: %w", err)
+	}
+
+	var result []FlappingContainer
+	for _, fc := range found {
+		if fc != nil {
+			result = append(result, *fc)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Dies != result[j].Dies {
+			return result[i].Dies > result[j].Dies
+		}
+		return result[i].RestartCount > result[j].RestartCount
+	})
+	return result, nil
+}
+
+// dieEvents reads container die events from the daemon's event history within the window
+func (c *Client) dieEvents(ctx context.Context, window time.Duration) (map[string]containerDies, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	now := time.Now()
+	messages, errs := c.cli.Events(ctx, types.EventsOptions{
+		Since: strconv.FormatInt(now.Add(-window).Unix(), 10),
+		Until: strconv.FormatInt(now.Unix(), 10),
+		Filters: filters.NewArgs(
+			filters.Arg("type", "container"),
+			filters.Arg("event", "die"),
+		),
+	})
+
+	dies := make(map[string]containerDies)
+	for {
+		select {
+		case msg := <-messages:
+			d := dies[msg.Actor.ID]
+			d.count++
+			if code, err := strconv.Atoi(msg.Actor.Attributes["exitCode"]); err == nil {
+				d.exitCodes = appendUniqueInt(d.exitCodes, code)
+			}
+			dies[msg.Actor.ID] = d
+		case err := <-errs:
+			if err == nil || errors.Is(err, io.EOF) {
+				return dies, nil
+			}
+			return nil, fmt.Errorf("failed to read container events: %w", err)
+		}
+	}
+}
+
+// lastStderr returns the last n lines a container wrote to stderr
+func (c *Client) lastStderr(ctx context.Context, containerID string, n int) ([]string, error) {
+	logs, err := c.cli.ContainerLogs(ctx, containerID, container.LogsOptions{
+		ShowStderr: true,
+		Tail:       strconv.Itoa(n),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer logs.Close()
+
+	var stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(io.Discard, &stderr, logs); err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(stderr.String(), "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+func appendUniqueInt(values []int, value int) []int {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,123 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/batch"
+	"github.com/docker/docker/api/types/container"
+)
+
+// GPUAllocation describes the GPUs a container was given
+type GPUAllocation struct {
+	// Source is how the GPUs were requested: "device-request" (--gpus) or "runtime" (--runtime nvidia)
+	Source string `json:"source"`
+	Driver string `json:"driver,omitempty"`
+	// Count is the number of GPUs requested, -1 for all
+	Count        int      `json:"count"`
+	DeviceIDs    []string `json:"device_ids,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+// String formats the allocation as "all", "2" or the device IDs
+func (g *GPUAllocation) String() string {
+	if g == nil {
+		return "-"
+	}
+	switch {
+	case len(g.DeviceIDs) > 0:
+		return strings.Join(g.DeviceIDs, ",")
+	case g.Count < 0:
+		return "all"
+	default:
+		return fmt.Sprintf("%d", g.Count)
+	}
+}
+
+// gpuAllocation detects GPUs from --gpus device requests or the nvidia runtime.
+// It returns nil when the container has no GPU access.
+func gpuAllocation(hostConfig *container.HostConfig, env []string) *GPUAllocation {
+	if hostConfig == nil {
+		return nil
+	}
+
+	for _, req := range hostConfig.DeviceRequests {
+		var caps []string
+		for _, set := range req.Capabilities {
+			caps = append(caps, set...)
+		}
+		if req.Driver != "nvidia" && !containsString(caps, "gpu") {
+			continue
+		}
+		return &GPUAllocation{
+			Source:       "device-request",
+			Driver:       valueOrDefault(req.Driver, "nvidia"),
+			Count:        req.Count,
+			DeviceIDs:    req.DeviceIDs,
+			Capabilities: caps,
+		}
+	}
+
+	// The legacy nvidia runtime selects devices through NVIDIA_VISIBLE_DEVICES
+	if hostConfig.Runtime != "nvidia" {
+		return nil
+	}
+	visible := ""
+	for _, e := range env {
+		if value, ok := strings.CutPrefix(e, "NVIDIA_VISIBLE_DEVICES="); ok {
+			visible = value
+		}
+	}
+	gpu := &GPUAllocation{Source: "runtime", Driver: "nvidia"}
+	switch visible {
+	case "", "none", "void":
+		return nil
+	case "all":
+		gpu.Count = -1
+	default:
+		gpu.DeviceIDs = strings.Split(visible, ",")
+		gpu.Count = len(gpu.DeviceIDs)
+	}
+	return gpu
+}
+
+// GPUAllocations inspects containers and returns their GPU allocations by container ID.
+// Containers without GPU access are omitted.
+func (c *Client) GPUAllocations(ctx context.Context, containers []ContainerInfo) (map[string]*GPUAllocation, error) {
+	gpus := make([]*GPUAllocation, len(containers))
+	errs := batch.Run(len(containers), batch.DefaultWorkers, func(i int) error {
+		inspect, err := c.cli.ContainerInspect(ctx, containers[i].ID)
+		if err != nil {
+			return err
+		}
+		var env []string
+		if inspect.Config != nil {
+			env = inspect.Config.Env
+		}
+		gpus[i] = gpuAllocation(inspect.HostConfig, env)
+		return nil
+	})
+	names := make([]string, len(containers))
+	for i, cont := range containers {
+		names[i] = cont.Name
+	}
+	if err := batch.Collect(names, errs); err != nil {
+		return nil, fmt.Errorf("failed to inspect containers: %w", err)
+	}
+
+	result := make(map[string]*GPUAllocation)
+	for i, gpu := range gpus {
+		if gpu != nil {
+			result[containers[i].ID] = gpu
+		}
+	}
+	return result, nil
+}
+
+func valueOrDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
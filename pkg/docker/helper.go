@@ -0,0 +1,101 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// DefaultHelperImage provides the shell tools run by helper containers
+const DefaultHelperImage = "alpine:3.20"
+
+// runHelper runs a short-lived container with the given mounts, streaming stdin into
+// it and its stdout out of it, and removes the container afterwards
+func (c *Client) runHelper(ctx context.Context, image string, mounts []mount.Mount, cmd, env []string, stdin io.Reader, stdout io.Writer) error {
+	if _, _, err := c.cli.ImageInspectWithRaw(ctx, image); err != nil {
+		if !client.IsErrNotFound(err) {
+			return fmt.Errorf("failed to inspect helper image: %w", err)
+		}
+		if _, err := c.PullImage(ctx, image, PullOptions{}, nil); err != nil {
+			return err
+		}
+	}
+
+	created, err := c.cli.ContainerCreate(ctx,
+		&container.Config{
+			Image:        image,
+			Cmd:          cmd,
+			Env:          env,
+			AttachStdin:  stdin != nil,
+			OpenStdin:    stdin != nil,
+			StdinOnce:    stdin != nil,
+			AttachStdout: true,
+			AttachStderr: true,
+			Labels:       map[string]string{"devops-toolkit.helper": "true"},
+		},
+		&container.HostConfig{
+			Mounts:      mounts,
+			NetworkMode: "none",
+		},
+		nil, nil, "")
+	if err != nil {
+		return fmt.Errorf("failed to create helper container: %w", err)
+	}
+	defer func() {
+		_ = c.cli.ContainerRemove(context.Background(), created.ID, container.RemoveOptions{Force: true})
+	}()
+
+	attach, err := c.cli.ContainerAttach(ctx, created.ID, container.AttachOptions{
+		Stream: true,
+		Stdin:  stdin != nil,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to attach to helper container: %w", err)
+	}
+	defer attach.Close()
+
+	if err := c.cli.ContainerStart(ctx, created.ID, container.StartOptions{}); err != nil {
+		return fmt.Errorf("failed to start helper container: %w", err)
+	}
+
+	stdinErr := make(chan error, 1)
+	if stdin != nil {
+		go func() {
+			_, err := io.Copy(attach.Conn, stdin)
+			_ = attach.CloseWrite()
+			stdinErr <- err
+		}()
+	} else {
+		stdinErr <- nil
+	}
+
+	var stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(stdout, &stderr, attach.Reader); err != nil {
+		return fmt.Errorf("failed to read helper output: %w", err)
+	}
+
+	waitC, errC := c.cli.ContainerWait(ctx, created.ID, container.WaitConditionNotRunning)
+	select {
+	case err := <-errC:
+		return fmt.Errorf("failed to wait for helper container: %w", err)
+	case result := <-waitC:
+		if result.StatusCode != 0 {
+			return fmt.Errorf("helper container exited with code %d: %s", result.StatusCode, strings.TrimSpace(stderr.String()))
+		}
+	}
+
+	// A failed exit explains a broken stdin pipe better, so only report it on success
+	if err := <-stdinErr; err != nil {
+		return fmt.Errorf("failed to stream archive: %w", err)
+	}
+	return nil
+}
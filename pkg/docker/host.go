@@ -0,0 +1,101 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/batch"
+)
+
+// HostInfo summarizes the Docker daemon and the load its containers put on the host
+type HostInfo struct {
+	Host           string   `json:"host"`
+	ServerVersion  string   `json:"server_version"`
+	APIVersion     string   `json:"api_version"`
+	OS             string   `json:"os"`
+	Kernel         string   `json:"kernel"`
+	Architecture   string   `json:"architecture"`
+	StorageDriver  string   `json:"storage_driver"`
+	RootDir        string   `json:"root_dir"`
+	CgroupDriver   string   `json:"cgroup_driver"`
+	CgroupVersion  string   `json:"cgroup_version"`
+	DefaultRuntime string   `json:"default_runtime"`
+	LoggingDriver  string   `json:"logging_driver"`
+	LiveRestore    bool     `json:"live_restore"`
+	Containers     int      `json:"containers"`
+	Running        int      `json:"running"`
+	Paused         int      `json:"paused"`
+	Stopped        int      `json:"stopped"`
+	Images         int      `json:"images"`
+	NCPU           int      `json:"ncpu"`
+	MemTotal       int64    `json:"mem_total"`
+	SecurityOpts   []string `json:"security_options"`
+	Warnings       []string `json:"warnings,omitempty"`
+
+	// CPUPercent is the combined CPU of running containers as a share of host capacity
+	CPUPercent float64 `json:"cpu_percent"`
+	// MemoryUsage is the combined memory of running containers
+	MemoryUsage   int64   `json:"memory_usage"`
+	MemoryPercent float64 `json:"memory_percent"`
+}
+
+// GetHostInfo reads daemon info and samples running containers for host CPU and memory pressure
+func (c *Client) GetHostInfo(ctx context.Context) (*HostInfo, error) {
+	info, err := c.cli.Info(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daemon info: %w", err)
+	}
+
+	host := &HostInfo{
+		Host:           c.cli.DaemonHost(),
+		ServerVersion:  info.ServerVersion,
+		APIVersion:     c.cli.ClientVersion(),
+		OS:             info.OperatingSystem,
+		Kernel:         info.KernelVersion,
+		Architecture:   info.Architecture,
+		StorageDriver:  info.Driver,
+		RootDir:        info.DockerRootDir,
+		CgroupDriver:   info.CgroupDriver,
+		CgroupVersion:  info.CgroupVersion,
+		DefaultRuntime: info.DefaultRuntime,
+		LoggingDriver:  info.LoggingDriver,
+		LiveRestore:    info.LiveRestoreEnabled,
+		Containers:     info.Containers,
+		Running:        info.ContainersRunning,
+		Paused:         info.ContainersPaused,
+		Stopped:        info.ContainersStopped,
+		Images:         info.Images,
+		NCPU:           info.NCPU,
+		MemTotal:       info.MemTotal,
+		SecurityOpts:   info.SecurityOptions,
+		Warnings:       info.Warnings,
+	}
+
+	running, err := c.ListContainers(ctx, false, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Each stats call samples for about a second, so sample containers concurrently
+	stats := make([]ContainerStats, len(running))
+	batch.Run(len(running), batch.DefaultWorkers, func(i int) error {
+		sampled, err := c.GetContainerStats(ctx, running[i:i+1])
+		if err == nil && len(sampled) == 1 {
+			stats[i] = sampled[0]
+		}
+		return err
+	})
+
+	for _, s := range stats {
+		host.CPUPercent += s.CPUPercent
+		host.MemoryUsage += s.MemoryUsage
+	}
+	if host.NCPU > 0 {
+		host.CPUPercent /= float64(host.NCPU)
+	}
+	if host.MemTotal > 0 {
+		host.MemoryPercent = float64(host.MemoryUsage) / float64(host.MemTotal) * 100
+	}
+
+	return host, nil
+}
@@ -0,0 +1,135 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/batch"
+	"github.com/docker/docker/api/types/mount"
+)
+
+// helperContainersPath is where the helper container mounts the daemon's containers directory
+const helperContainersPath = "/containers"
+
+// LogUsage describes a container's log driver, rotation settings and log size on disk
+type LogUsage struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	State   string `json:"state"`
+	Driver  string `json:"driver"`
+	MaxSize string `json:"max_size,omitempty"`
+	MaxFile string `json:"max_file,omitempty"`
+	Size    int64  `json:"size"`
+	Files   int    `json:"files"`
+}
+
+// Unrotated reports whether the container writes json-file logs without a size cap
+func (u LogUsage) Unrotated() bool {
+	return u.Driver == "json-file" && u.MaxSize == ""
+}
+
+// LogUsageReport lists log usage for all containers
+type LogUsageReport struct {
+	Containers []LogUsage `json:"containers"`
+	// SizeError explains why sizes on disk could not be read; log settings are still reported
+	SizeError string `json:"size_error,omitempty"`
+}
+
+// GetLogUsage reports each container's log settings and the size of its log files.
+// Sizes are read by a helper container that mounts the daemon's containers directory
+// read-only, so this also works against remote daemons.
+func (c *Client) GetLogUsage(ctx context.Context, image string) (*LogUsageReport, error) {
+	containers, err := c.ListContainers(ctx, true, "")
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([]LogUsage, len(containers))
+	errs := batch.Run(len(containers), batch.DefaultWorkers, func(i int) error {
+		inspect, err := c.cli.ContainerInspect(ctx, containers[i].ID)
+		if err != nil {
+			return err
+		}
+		usage := LogUsage{
+			ID:    inspect.ID,
+			Name:  strings.TrimPrefix(inspect.Name, "/"),
+			State: inspect.State.Status,
+		}
+		if inspect.HostConfig != nil {
+			usage.Driver = inspect.HostConfig.LogConfig.Type
+			usage.MaxSize = inspect.HostConfig.LogConfig.Config["max-size"]
+			usage.MaxFile = inspect.HostConfig.LogConfig.Config["max-file"]
+		}
+		usages[i] = usage
+		return nil
+	})
+	names := make([]string, len(containers))
+	for i, cont := range containers {
+		names[i] = cont.Name
+	}
+	if err := batch.Collect(names, errs); err != nil {
+		return nil, fmt.Errorf("failed to inspect containers: %w", err)
+	}
+
+	report := &LogUsageReport{Containers: usages}
+
+	sizes, files, err := c.logFileSizes(ctx, image)
+	if err != nil {
+		report.SizeError = err.Error()
+	}
+	for i := range report.Containers {
+		report.Containers[i].Size = sizes[report.Containers[i].ID]
+		report.Containers[i].Files = files[report.Containers[i].ID]
+	}
+
+	sort.SliceStable(report.Containers, func(i, j int) bool {
+		return report.Containers[i].Size > report.Containers[j].Size
+	})
+	return report, nil
+}
+
+// logFileSizes sums json-file and local driver log files per container ID, including rotated files
+func (c *Client) logFileSizes(ctx context.Context, image string) (map[string]int64, map[string]int, error) {
+	sizes := make(map[string]int64)
+	files := make(map[string]int)
+
+	info, err := c.cli.Info(ctx)
+	if err != nil {
+		return sizes, files, fmt.Errorf("failed to get daemon info: %w", err)
+	}
+
+	script := `find ` + helperContainersPath + ` -maxdepth 3 -type f \( -name '*-json.log*' -o -path '*/local-logs/*' \) -exec stat -c '%s %n' {} +`
+	mounts := []mount.Mount{{
+		Type:     mount.TypeBind,
+		Source:   path.Join(info.DockerRootDir, "containers"),
+		Target:   helperContainersPath,
+		ReadOnly: true,
+	}}
+
+	var out bytes.Buffer
+	if err := c.runHelper(ctx, image, mounts, []string{"sh", "-c", script}, nil, nil, &out); err != nil {
+		return sizes, files, fmt.Errorf("failed to read log sizes: %w", err)
+	}
+
+	for _, line := range strings.Split(out.String(), "\n") {
+		sizeField, file, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		size, err := strconv.ParseInt(sizeField, 10, 64)
+		if err != nil {
+			continue
+		}
+		// Paths look like /containers/<id>/<id>-json.log[.N] or /containers/<id>/local-logs/container.log
+		rel := strings.TrimPrefix(file, helperContainersPath+"/")
+		id, _, _ := strings.Cut(rel, "/")
+		sizes[id] += size
+		files[id]++
+	}
+	return sizes, files, nil
+}
@@ -0,0 +1,149 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// PromoteOptions configures an image promotion
+type PromoteOptions struct {
+	// Pull always pulls the source; otherwise a local image is used when present
+	Pull bool
+	// Verify checks that the registry serves the pushed digest for the target
+	Verify bool
+}
+
+// PromoteResult describes a promoted image
+type PromoteResult struct {
+	Source       string `json:"source"`
+	Target       string `json:"target"`
+	Pulled       bool   `json:"pulled"`
+	SourceDigest string `json:"source_digest,omitempty"`
+	Digest       string `json:"digest"`
+	Pinned       string `json:"pinned"`
+	Verified     bool   `json:"verified"`
+}
+
+// PromoteImage retags source as target and pushes it, pulling source first when needed.
+// Registry credentials come from the Docker CLI config (see RegistryAuth).
+func (c *Client) PromoteImage(ctx context.Context, source, target string, opts PromoteOptions, onStatus func(string)) (*PromoteResult, error) {
+	status := func(msg string) {
+		if onStatus != nil {
+			onStatus(msg)
+		}
+	}
+
+	targetNamed, err := reference.ParseNormalizedNamed(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target reference %q: %w", target, err)
+	}
+	if _, ok := targetNamed.(reference.Digested); ok {
+		return nil, fmt.Errorf("target %q must be a tag, not a digest", target)
+	}
+	targetNamed = reference.TagNameOnly(targetNamed)
+
+	result := &PromoteResult{Source: source, Target: reference.FamiliarString(targetNamed)}
+
+	pull := opts.Pull
+	if !pull {
+		if _, _, err := c.cli.ImageInspectWithRaw(ctx, source); err != nil {
+			if !client.IsErrNotFound(err) {
+				return nil, fmt.Errorf("failed to inspect %s: %w", source, err)
+			}
+			pull = true
+		}
+	}
+	if pull {
+		status(fmt.Sprintf("Pulling %s", source))
+		sourceAuth, err := RegistryAuth(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+		pulled, err := c.PullImage(ctx, source, PullOptions{RegistryAuth: sourceAuth}, nil)
+		if err != nil {
+			return nil, err
+		}
+		result.Pulled = true
+		result.SourceDigest = pulled.Digest
+	}
+
+	status(fmt.Sprintf("Tagging %s as %s", source, result.Target))
+	if err := c.cli.ImageTag(ctx, source, targetNamed.String()); err != nil {
+		return nil, fmt.Errorf("failed to tag image: %w", err)
+	}
+
+	targetAuth, err := RegistryAuth(ctx, targetNamed.String())
+	if err != nil {
+		return nil, err
+	}
+
+	status(fmt.Sprintf("Pushing %s", result.Target))
+	stream, err := c.cli.ImagePush(ctx, targetNamed.String(), types.ImagePushOptions{RegistryAuth: targetAuth})
+	if err != nil {
+		return nil, fmt.Errorf("failed to push image: %w", err)
+	}
+	defer stream.Close()
+
+	if result.Digest, err = pushDigest(stream, status); err != nil {
+		return nil, err
+	}
+	if result.Digest == "" {
+		return nil, fmt.Errorf("registry did not report a digest for %s", result.Target)
+	}
+	result.Pinned = reference.FamiliarName(targetNamed) + "@" + result.Digest
+
+	if opts.Verify {
+		status(fmt.Sprintf("Verifying %s", result.Target))
+		remote, err := c.cli.DistributionInspect(ctx, targetNamed.String(), targetAuth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify pushed image: %w", err)
+		}
+		if got := remote.Descriptor.Digest.String(); got != result.Digest {
+			return nil, fmt.Errorf("digest mismatch for %s: pushed %s, registry serves %s", result.Target, result.Digest, got)
+		}
+		result.Verified = true
+	}
+
+	return result, nil
+}
+
+// pushDigest reads a push progress stream and returns the digest the registry assigned
+func pushDigest(stream io.Reader, status func(string)) (string, error) {
+	var digest string
+	decoder := json.NewDecoder(stream)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return digest, nil
+			}
+			return "", fmt.Errorf("failed to read push progress: %w", err)
+		}
+		if msg.Error != nil {
+			return "", fmt.Errorf("failed to push image: %s", msg.Error.Message)
+		}
+
+		if msg.Aux != nil {
+			var aux types.PushResult
+			if err := json.Unmarshal(*msg.Aux, &aux); err == nil && aux.Digest != "" {
+				digest = aux.Digest
+			}
+		}
+		// Older daemons only report "<tag>: digest: sha256:... size: N"
+		if _, rest, ok := strings.Cut(msg.Status, "digest: "); ok && digest == "" {
+			digest, _, _ = strings.Cut(rest, " ")
+		}
+		if msg.ID != "" && msg.Status != "" {
+			status(fmt.Sprintf("Pushing layer %s: %s", msg.ID, msg.Status))
+		}
+	}
+}
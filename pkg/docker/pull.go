@@ -0,0 +1,142 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// PullLayer tracks the progress of one image layer during a pull
+type PullLayer struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Current int64  `json:"current"`
+	Total   int64  `json:"total"`
+}
+
+// Done reports whether the layer has finished downloading or was already present
+func (l PullLayer) Done() bool {
+	switch l.Status {
+	case "Pull complete", "Already exists", "Download complete":
+		return true
+	}
+	return false
+}
+
+// PullOptions configures an image pull
+type PullOptions struct {
+	// Platform selects a platform of a multi-arch image, e.g. linux/arm64
+	Platform string
+	// RegistryAuth is an encoded X-Registry-Auth value, see RegistryAuth
+	RegistryAuth string
+}
+
+// PullResult describes a pulled image and its resolved digest
+type PullResult struct {
+	Reference string      `json:"reference"`
+	ID        string      `json:"id"`
+	Digest    string      `json:"digest"`
+	Pinned    string      `json:"pinned"`
+	Size      int64       `json:"size"`
+	Layers    []PullLayer `json:"layers"`
+	UpToDate  bool        `json:"up_to_date"`
+}
+
+// PullImage pulls an image, reporting layer progress through onProgress as the daemon streams it
+func (c *Client) PullImage(ctx context.Context, ref string, opts PullOptions, onProgress func([]PullLayer)) (*PullResult, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image reference %q: %w", ref, err)
+	}
+	named = reference.TagNameOnly(named)
+
+	stream, err := c.cli.ImagePull(ctx, named.String(), types.ImagePullOptions{
+		Platform:     opts.Platform,
+		RegistryAuth: opts.RegistryAuth,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull image: %w", err)
+	}
+	defer stream.Close()
+
+	result := &PullResult{Reference: reference.FamiliarString(named)}
+	index := make(map[string]int)
+
+	decoder := json.NewDecoder(stream)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to read pull progress: %w", err)
+		}
+		if msg.Error != nil {
+			return nil, fmt.Errorf("failed to pull image: %s", msg.Error.Message)
+		}
+
+		switch {
+		case strings.HasPrefix(msg.Status, "Digest: "):
+			result.Digest = strings.TrimPrefix(msg.Status, "Digest: ")
+		case strings.HasPrefix(msg.Status, "Status: Image is up to date"):
+			result.UpToDate = true
+		}
+
+		// Layer messages carry a short layer ID; "Pulling from" uses the tag instead
+		if msg.ID == "" || strings.HasPrefix(msg.Status, "Pulling from") {
+			continue
+		}
+		i, ok := index[msg.ID]
+		if !ok {
+			i = len(result.Layers)
+			index[msg.ID] = i
+			result.Layers = append(result.Layers, PullLayer{ID: msg.ID})
+		}
+		layer := &result.Layers[i]
+		layer.Status = msg.Status
+		if msg.Progress != nil && msg.Progress.Total > 0 && msg.Status == "Downloading" {
+			layer.Current = msg.Progress.Current
+			layer.Total = msg.Progress.Total
+		}
+		if layer.Done() && layer.Total > 0 {
+			layer.Current = layer.Total
+		}
+
+		if onProgress != nil {
+			onProgress(result.Layers)
+		}
+	}
+
+	inspect, _, err := c.cli.ImageInspectWithRaw(ctx, named.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect pulled image: %w", err)
+	}
+	result.ID = strings.TrimPrefix(inspect.ID, "sha256:")
+	result.Size = inspect.Size
+
+	// Older daemons omit the digest line; fall back to the repo digest of the same repository
+	if result.Digest == "" {
+		for _, repoDigest := range inspect.RepoDigests {
+			canonical, err := reference.ParseNormalizedNamed(repoDigest)
+			if err != nil || canonical.Name() != named.Name() {
+				continue
+			}
+			if digested, ok := canonical.(reference.Digested); ok {
+				result.Digest = digested.Digest().String()
+				break
+			}
+		}
+	}
+	if result.Digest != "" {
+		result.Pinned = reference.FamiliarName(named) + "@" + result.Digest
+	}
+
+	return result, nil
+}
@@ -0,0 +1,100 @@
+package docker
+
+import (
+	"github.com/docker/docker/api/types"
+)
+
+// containerStats converts one stats sample into ContainerStats. osType is the
+// daemon platform from the stats response; hostCPUs is used when a Linux
+// sample does not say how many CPUs the container could use.
+func containerStats(osType string, s *types.StatsJSON, hostCPUs func() uint32) ContainerStats {
+	cs := ContainerStats{PIDs: s.PidsStats.Current}
+
+	if osType == "windows" {
+		cs.CPUPercent = windowsCPUPercent(s)
+		// Windows has no memory limit in stats; the private working set is
+		// what Task Manager and docker stats show
+		cs.MemoryUsage = int64(s.MemoryStats.PrivateWorkingSet)
+		cs.BlockInput = int64(s.StorageStats.ReadSizeBytes)
+		cs.BlockOutput = int64(s.StorageStats.WriteSizeBytes)
+	} else {
+		cs.CPUPercent = linuxCPUPercent(s, hostCPUs)
+		cs.MemoryUsage = int64(s.MemoryStats.Usage)
+		cs.MemoryLimit = int64(s.MemoryStats.Limit)
+		for _, bioEntry := range s.BlkioStats.IoServiceBytesRecursive {
+			switch bioEntry.Op {
+			case "Read", "read":
+				cs.BlockInput += int64(bioEntry.Value)
+			case "Write", "write":
+				cs.BlockOutput += int64(bioEntry.Value)
+			}
+		}
+	}
+
+	if cs.MemoryLimit > 0 {
+		cs.MemoryPercent = float64(cs.MemoryUsage) / float64(cs.MemoryLimit) * 100.0
+	}
+
+	for _, netStats := range s.Networks {
+		cs.NetInput += int64(netStats.RxBytes)
+		cs.NetOutput += int64(netStats.TxBytes)
+	}
+
+	return cs
+}
+
+// linuxCPUPercent is the container's share of host CPU time between the two
+// samples, scaled so one busy core is 100%
+func linuxCPUPercent(s *types.StatsJSON, hostCPUs func() uint32) float64 {
+	// Without a previous sample the counters are totals since the start
+	if s.PreCPUStats.SystemUsage == 0 {
+		return 0
+	}
+	cpuDelta, ok := delta(s.CPUStats.CPUUsage.TotalUsage, s.PreCPUStats.CPUUsage.TotalUsage)
+	if !ok {
+		return 0
+	}
+	systemDelta, ok := delta(s.CPUStats.SystemUsage, s.PreCPUStats.SystemUsage)
+	if !ok {
+		return 0
+	}
+
+	// online_cpus is missing on older daemons and on some ARM kernels;
+	// cgroup v1 still lists per-CPU usage, otherwise fall back to the host
+	cpus := s.CPUStats.OnlineCPUs
+	if cpus == 0 {
+		cpus = uint32(len(s.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpus == 0 && hostCPUs != nil {
+		cpus = hostCPUs()
+	}
+	if cpus == 0 {
+		cpus = 1
+	}
+
+	return float64(cpuDelta) / float64(systemDelta) * float64(cpus) * 100.0
+}
+
+// windowsCPUPercent uses the 100ns intervals Windows reports: the time used
+// against the time available on every processor between the two reads
+func windowsCPUPercent(s *types.StatsJSON) float64 {
+	if s.PreRead.IsZero() || !s.Read.After(s.PreRead) || s.NumProcs == 0 {
+		return 0
+	}
+	used, ok := delta(s.CPUStats.CPUUsage.TotalUsage, s.PreCPUStats.CPUUsage.TotalUsage)
+	if !ok {
+		return 0
+	}
+
+	possible := float64(s.Read.Sub(s.PreRead).Nanoseconds()) / 100 * float64(s.NumProcs)
+	return float64(used) / possible * 100.0
+}
+
+// delta returns cur-pre, or false when there is no positive difference, as
+// with an idle container or counters reset by a restart
+func delta(cur, pre uint64) (uint64, bool) {
+	if cur <= pre {
+		return 0, false
+	}
+	return cur - pre, true
+}
@@ -0,0 +1,201 @@
+package docker
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+// loadStats reads a stats sample in the daemon's stats endpoint format.
+// stats_linux.json and stats_windows.json are built from the Engine API schema
+// with round numbers so the expected percentages are easy to check by hand;
+// stats_linux_percpu.json is the stats response documented in the Engine API
+// spec (api/swagger.yaml) without online_cpus, as older daemons and some ARM
+// kernels send it.
+func loadStats(t *testing.T, name string) *types.StatsJSON {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s types.StatsJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatalf("failed to decode %s: %v", name, err)
+	}
+	return &s
+}
+
+func TestContainerStatsLinux(t *testing.T) {
+	cs := containerStats("linux", loadStats(t, "stats_linux.json"), nil)
+
+	want := ContainerStats{
+		CPUPercent:    100,
+		MemoryUsage:   104857600,
+		MemoryLimit:   419430400,
+		MemoryPercent: 25,
+		NetInput:      1500,
+		NetOutput:     2000,
+		BlockInput:    5120,
+		BlockOutput:   8192,
+		PIDs:          12,
+	}
+	if !statsEqual(cs, want) {
+		t.Errorf("containerStats = %+v, want %+v", cs, want)
+	}
+}
+
+func TestContainerStatsPerCPU(t *testing.T) {
+	s := loadStats(t, "stats_linux_percpu.json")
+	if s.CPUStats.OnlineCPUs != 0 || len(s.CPUStats.CPUUsage.PercpuUsage) != 4 {
+		t.Fatalf("sample has online_cpus %d and %d per-CPU counters, want 0 and 4",
+			s.CPUStats.OnlineCPUs, len(s.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	// The host count must not be used while per-CPU usage is available
+	cs := containerStats("linux", s, func() uint32 { return 64 })
+
+	wantCPU := float64(100215355-100093996) / float64(739306590000000-9492140000000) * 4 * 100
+	if math.Abs(cs.CPUPercent-wantCPU) > wantCPU*1e-9 {
+		t.Errorf("CPUPercent = %g, want %g from 4 per-CPU counters", cs.CPUPercent, wantCPU)
+	}
+	want := ContainerStats{
+		CPUPercent:    cs.CPUPercent,
+		MemoryUsage:   6537216,
+		MemoryLimit:   67108864,
+		MemoryPercent: float64(6537216) / float64(67108864) * 100,
+		NetInput:      5338 + 4641,
+		NetOutput:     648 + 690,
+		PIDs:          3,
+	}
+	if !statsEqual(cs, want) {
+		t.Errorf("containerStats = %+v, want %+v", cs, want)
+	}
+}
+
+func TestContainerStatsWindows(t *testing.T) {
+	cs := containerStats("windows", loadStats(t, "stats_windows.json"), nil)
+
+	want := ContainerStats{
+		CPUPercent:  50,
+		MemoryUsage: 52428800,
+		NetInput:    3000,
+		NetOutput:   1500,
+		BlockInput:  1024,
+		BlockOutput: 2048,
+	}
+	if !statsEqual(cs, want) {
+		t.Errorf("containerStats = %+v, want %+v", cs, want)
+	}
+}
+
+func TestLinuxCPUPercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(s *types.StatsJSON)
+		hostCPUs func() uint32
+		want     float64
+	}{
+		{"online cpus", nil, nil, 100},
+		{
+			name: "missing online cpus uses per-cpu usage",
+			mutate: func(s *types.StatsJSON) {
+				s.CPUStats.OnlineCPUs = 0
+				s.CPUStats.CPUUsage.PercpuUsage = []uint64{1000000000, 1000000000}
+			},
+			want: 50,
+		},
+		{
+			name:     "missing online cpus falls back to the host",
+			mutate:   func(s *types.StatsJSON) { s.CPUStats.OnlineCPUs = 0 },
+			hostCPUs: func() uint32 { return 8 },
+			want:     200,
+		},
+		{
+			name:   "missing online cpus without a host count",
+			mutate: func(s *types.StatsJSON) { s.CPUStats.OnlineCPUs = 0 },
+			want:   25,
+		},
+		{
+			name:   "first sample",
+			mutate: func(s *types.StatsJSON) { s.PreCPUStats = types.CPUStats{} },
+			want:   0,
+		},
+		{
+			name: "idle container",
+			mutate: func(s *types.StatsJSON) {
+				s.CPUStats.CPUUsage.TotalUsage = s.PreCPUStats.CPUUsage.TotalUsage
+			},
+			want: 0,
+		},
+		{
+			name:   "zero system delta",
+			mutate: func(s *types.StatsJSON) { s.CPUStats.SystemUsage = s.PreCPUStats.SystemUsage },
+			want:   0,
+		},
+		{
+			name:   "counters reset by a restart",
+			mutate: func(s *types.StatsJSON) { s.CPUStats.CPUUsage.TotalUsage = 1000 },
+			want:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := loadStats(t, "stats_linux.json")
+			if tt.mutate != nil {
+				tt.mutate(s)
+			}
+			if got := linuxCPUPercent(s, tt.hostCPUs); !approx(got, tt.want) {
+				t.Errorf("linuxCPUPercent = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindowsCPUPercent(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(s *types.StatsJSON)
+		want   float64
+	}{
+		{"two processors", nil, 50},
+		{"one processor", func(s *types.StatsJSON) { s.NumProcs = 1 }, 100},
+		{"first sample", func(s *types.StatsJSON) { s.PreRead = time.Time{} }, 0},
+		{"reads out of order", func(s *types.StatsJSON) { s.PreRead = s.Read }, 0},
+		{"unknown processor count", func(s *types.StatsJSON) { s.NumProcs = 0 }, 0},
+		{
+			name: "idle container",
+			mutate: func(s *types.StatsJSON) {
+				s.CPUStats.CPUUsage.TotalUsage = s.PreCPUStats.CPUUsage.TotalUsage
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := loadStats(t, "stats_windows.json")
+			if tt.mutate != nil {
+				tt.mutate(s)
+			}
+			if got := windowsCPUPercent(s); !approx(got, tt.want) {
+				t.Errorf("windowsCPUPercent = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+func statsEqual(a, b ContainerStats) bool {
+	return approx(a.CPUPercent, b.CPUPercent) && approx(a.MemoryPercent, b.MemoryPercent) &&
+		a.MemoryUsage == b.MemoryUsage && a.MemoryLimit == b.MemoryLimit &&
+		a.NetInput == b.NetInput && a.NetOutput == b.NetOutput &&
+		a.BlockInput == b.BlockInput && a.BlockOutput == b.BlockOutput &&
+		a.PIDs == b.PIDs
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
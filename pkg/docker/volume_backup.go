@@ -0,0 +1,204 @@
+package docker
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+)
+
+// helperVolumePath is where the helper container mounts the volume
+const helperVolumePath = "/volume"
+
+// VolumeArchive describes a volume backup archive
+type VolumeArchive struct {
+	Volume string `json:"volume"`
+	File   string `json:"file"`
+	SHA256 string `json:"sha256"`
+	Bytes  int64  `json:"bytes"`
+	Files  int    `json:"files"`
+	// Verified is false when a restored archive had no checksum file to verify against
+	Verified bool `json:"verified"`
+}
+
+// BackupVolume writes a gzipped tar of a volume to file, along with a file.sha256 checksum
+func (c *Client) BackupVolume(ctx context.Context, volumeName, file, image string, onProgress func(int64)) (*VolumeArchive, error) {
+	if _, err := c.cli.VolumeInspect(ctx, volumeName); err != nil {
+		return nil, fmt.Errorf("failed to inspect volume %s: %w", volumeName, err)
+	}
+
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", file, err)
+	}
+
+	hash := sha256.New()
+	counter := &progressWriter{onWrite: onProgress}
+	err = c.runHelper(ctx, image, []mount.Mount{volumeMount(volumeName, true)},
+		[]string{"tar", "-czf", "-", "-C", helperVolumePath, "."}, nil,
+		nil, io.MultiWriter(f, hash, counter))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file)
+		return nil, fmt.Errorf("failed to back up volume %s: %w", volumeName, err)
+	}
+
+	archive := &VolumeArchive{
+		Volume:   volumeName,
+		File:     file,
+		SHA256:   hex.EncodeToString(hash.Sum(nil)),
+		Bytes:    counter.n,
+		Verified: true,
+	}
+
+	// Read the archive back to make sure it is complete before reporting success
+	if archive.Files, err = countArchiveFiles(file); err != nil {
+		return nil, fmt.Errorf("backup archive %s is not readable: %w", file, err)
+	}
+
+	checksum := fmt.Sprintf("%s  %s\n", archive.SHA256, filepath.Base(file))
+	if err := os.WriteFile(file+".sha256", []byte(checksum), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write checksum: %w", err)
+	}
+	return archive, nil
+}
+
+// RestoreVolume extracts a backup archive into a volume, creating the volume if needed.
+// The archive is verified against file.sha256 when present. Restoring into a volume
+// that already has data requires force.
+func (c *Client) RestoreVolume(ctx context.Context, volumeName, file, image string, force bool, onProgress func(int64, int64)) (*VolumeArchive, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", file, err)
+	}
+
+	sum, err := fileSHA256(file)
+	if err != nil {
+		return nil, err
+	}
+	archive := &VolumeArchive{Volume: volumeName, File: file, SHA256: sum, Bytes: info.Size()}
+
+	if expected, err := os.ReadFile(file + ".sha256"); err == nil {
+		fields := strings.Fields(string(expected))
+		if len(fields) == 0 || fields[0] != sum {
+			return nil, fmt.Errorf("checksum mismatch for %s: archive is corrupt or was modified", file)
+		}
+		archive.Verified = true
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read checksum: %w", err)
+	}
+
+	if _, err := c.cli.VolumeInspect(ctx, volumeName); err != nil {
+		if !client.IsErrNotFound(err) {
+			return nil, fmt.Errorf("failed to inspect volume %s: %w", volumeName, err)
+		}
+		if _, err := c.cli.VolumeCreate(ctx, volume.CreateOptions{Name: volumeName}); err != nil {
+			return nil, fmt.Errorf("failed to create volume %s: %w", volumeName, err)
+		}
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	defer f.Close()
+
+	env := []string{"FORCE=0"}
+	if force {
+		env = []string{"FORCE=1"}
+	}
+	script := `if [ "$FORCE" != 1 ] && [ -n "$(ls -A ` + helperVolumePath + `)" ]; then
+  echo "volume is not empty (use --force to restore over existing data)" >&2; exit 3
+fi
+tar -xzf - -C ` + helperVolumePath
+
+	stdin := &progressReader{r: f, onRead: func(n int64) {
+		if onProgress != nil {
+			onProgress(n, info.Size())
+		}
+	}}
+	if err := c.runHelper(ctx, image, []mount.Mount{volumeMount(volumeName, false)}, []string{"sh", "-c", script}, env, stdin, io.Discard); err != nil {
+		return nil, fmt.Errorf("failed to restore volume %s: %w", volumeName, err)
+	}
+	return archive, nil
+}
+
+func volumeMount(volumeName string, readOnly bool) mount.Mount {
+	return mount.Mount{
+		Type:     mount.TypeVolume,
+		Source:   volumeName,
+		Target:   helperVolumePath,
+		ReadOnly: readOnly,
+	}
+}
+
+// countArchiveFiles reads a gzipped tar to the end and counts its regular files
+func countArchiveFiles(file string) (int, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return 0, err
+	}
+	defer gz.Close()
+
+	files := 0
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return files, err
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			files++
+		}
+	}
+}
+
+func fileSHA256(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// progressWriter counts bytes written and reports the running total
+type progressWriter struct {
+	n       int64
+	onWrite func(int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	p.n += int64(len(b))
+	if p.onWrite != nil {
+		p.onWrite(p.n)
+	}
+	return len(b), nil
+}
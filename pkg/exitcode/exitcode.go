@@ -0,0 +1,172 @@
+package exitcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/batch"
+)
+
+// Exit codes returned by every command
+const (
+	OK           = 0
+	General      = 1
+	Config       = 2
+	Connection   = 3
+	ChecksFailed = 4
+	Partial      = 5
+	Timeout      = 124
+	Interrupted  = 130
+)
+
+// Failure classes reported alongside the exit code in structured output
+const (
+	ClassGeneral      = "error"
+	ClassConfig       = "config"
+	ClassConnection   = "connection"
+	ClassChecksFailed = "checks_failed"
+	ClassPartial      = "partial"
+	ClassTimeout      = "timeout"
+	ClassInterrupted  = "interrupted"
+)
+
+var classes = map[int]string{
+	General:      ClassGeneral,
+	Config:       ClassConfig,
+	Connection:   ClassConnection,
+	ChecksFailed: ClassChecksFailed,
+	Partial:      ClassPartial,
+	Timeout:      ClassTimeout,
+	Interrupted:  ClassInterrupted,
+}
+
+// Error attaches an exit code to an error
+type Error struct {
+	Code int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Class returns the failure class name for the error's code
+func (e *Error) Class() string {
+	if class, ok := classes[e.Code]; ok {
+		return class
+	}
+	return ClassGeneral
+}
+
+// ConfigError marks err as caused by invalid flags, config files or credentials
+func ConfigError(err error) error {
+	return wrap(Config, err)
+}
+
+// ConnectionError marks err as caused by an unreachable cluster, daemon or API
+func ConnectionError(err error) error {
+	return wrap(Connection, err)
+}
+
+// PartialError marks err as some items of a bulk operation failing while others succeeded
+func PartialError(err error) error {
+	return wrap(Partial, err)
+}
+
+// ChecksFailedf reports that a command ran but its checks found problems
+func ChecksFailedf(format string, args ...interface{}) error {
+	return &Error{Code: ChecksFailed, Err: fmt.Errorf(format, args...)}
+}
+
+// CommandFailed reports that a command run on the user's behalf, such as a
+// job or container, exited with code; the toolkit exits with the same code
+func CommandFailed(code int, err error) error {
+	return &Error{Code: code, Err: err}
+}
+
+func wrap(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	var existing *Error
+	if errors.As(err, &existing) {
+		return err
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Classifier marks errors from one client library with the exit code they map
+// to, returning err wrapped with ConfigError or ConnectionError, or nil when it
+// does not recognize err. Client packages register one for their SDK so this
+// package stays free of SDK dependencies.
+type Classifier func(err error) error
+
+var (
+	classifiersMu sync.RWMutex
+	classifiers   []Classifier
+)
+
+// RegisterClassifier adds a classifier consulted by Classify for errors that
+// were not marked explicitly
+func RegisterClassifier(c Classifier) {
+	classifiersMu.Lock()
+	defer classifiersMu.Unlock()
+	classifiers = append(classifiers, c)
+}
+
+// Classify returns err as an *Error, inferring the code for errors that were not
+// marked explicitly from cancellation, registered client classifiers and
+// network errors
+func Classify(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded
+	}
+	return &Error{Code: infer(err), Err: err}
+}
+
+func infer(err error) int {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return Timeout
+	case errors.Is(err, context.Canceled):
+		return Interrupted
+	}
+
+	if code, ok := classifyRegistered(err); ok {
+		return code
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return Connection
+	}
+
+	var failures batch.Errors
+	if errors.As(err, &failures) {
+		return Partial
+	}
+	return General
+}
+
+func classifyRegistered(err error) (int, bool) {
+	classifiersMu.RLock()
+	defer classifiersMu.RUnlock()
+	for _, classify := range classifiers {
+		var coded *Error
+		if errors.As(classify(err), &coded) {
+			return coded.Code, true
+		}
+	}
+	return 0, false
+}
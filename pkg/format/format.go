@@ -0,0 +1,182 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Size unit systems
+const (
+	UnitsBinary = "binary" // powers of 1024: KiB, MiB, GiB
+	UnitsSI     = "si"     // powers of 1000: kB, MB, GB
+)
+
+var (
+	useUTC       bool
+	absoluteTime bool
+	sizeUnits    = UnitsBinary
+)
+
+// Configure sets how timestamps and sizes are shown: utc prints timestamps in
+// UTC instead of local time, absolute replaces relative ages such as "5m" with
+// timestamps, and units selects binary or SI sizes for Size
+func Configure(utc, absolute bool, units string) error {
+	switch strings.ToLower(units) {
+	case "", UnitsBinary:
+		sizeUnits = UnitsBinary
+	case UnitsSI:
+		sizeUnits = UnitsSI
+	default:
+		return fmt.Errorf("invalid size units %q (valid: binary, si)", units)
+	}
+	useUTC, absoluteTime = utc, absolute
+	return nil
+}
+
+// Size formats a byte count in the configured unit system, e.g. "1.5 GiB"
+func Size(bytes int64) string {
+	if sizeUnits == UnitsSI {
+		return SISize(bytes)
+	}
+	return BinarySize(bytes)
+}
+
+// BinarySize formats a byte count in powers of 1024, e.g. "1.5 GiB"
+func BinarySize(bytes int64) string {
+	return scale(bytes, 1024, []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}, " ")
+}
+
+// SISize formats a byte count in powers of 1000, e.g. "1.6 GB"
+func SISize(bytes int64) string {
+	return scale(bytes, 1000, []string{"B", "kB", "MB", "GB", "TB", "PB"}, " ")
+}
+
+// Quantity formats a byte count like a Kubernetes resource quantity, e.g. "1.5Gi"
+func Quantity(bytes int64) string {
+	return scale(bytes, 1024, []string{"B", "Ki", "Mi", "Gi", "Ti", "Pi"}, "")
+}
+
+func scale(bytes int64, base float64, units []string, sep string) string {
+	value := float64(bytes)
+	sign := ""
+	if value < 0 {
+		sign, value = "-", -value
+	}
+	if value < base {
+		return fmt.Sprintf("%s%d%s%s", sign, int64(value), sep, units[0])
+	}
+
+	i := 0
+	for value >= base && i < len(units)-1 {
+		value /= base
+		i++
+	}
+	if value >= 100 {
+		return fmt.Sprintf("%s%.0f%s%s", sign, value, sep, units[i])
+	}
+	return fmt.Sprintf("%s%.1f%s%s", sign, value, sep, units[i])
+}
+
+// Age formats the time since t compactly, e.g. "45s", "5m", "3h", "12d", or as a
+// timestamp with --absolute-time. Zero times are shown as "-".
+func Age(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	if absoluteTime {
+		return Timestamp(t)
+	}
+
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// Ago formats the time since t in words, e.g. "5 minutes ago", or as a
+// timestamp with --absolute-time. Zero times are shown as "-".
+func Ago(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	if absoluteTime {
+		return Timestamp(t)
+	}
+
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return plural(int(d.Seconds()), "second") + " ago"
+	case d < time.Hour:
+		return plural(int(d.Minutes()), "minute") + " ago"
+	case d < 24*time.Hour:
+		return plural(int(d.Hours()), "hour") + " ago"
+	case d < 7*24*time.Hour:
+		return plural(int(d.Hours()/24), "day") + " ago"
+	default:
+		return plural(int(d.Hours()/(24*7)), "week") + " ago"
+	}
+}
+
+// Timestamp formats t with seconds and zone, in local time or UTC with --utc
+func Timestamp(t time.Time) string {
+	return TimestampLayout(t, "2006-01-02 15:04:05 MST")
+}
+
+// TimestampLayout formats t with layout, in local time or UTC with --utc
+func TimestampLayout(t time.Time, layout string) string {
+	if t.IsZero() {
+		return "-"
+	}
+	if useUTC {
+		return t.UTC().Format(layout)
+	}
+	return t.Local().Format(layout)
+}
+
+// Duration formats d for run times, e.g. "45s", "2m5s", "1h12m"; zero or
+// negative durations are shown as "-"
+func Duration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%ds", int(d.Minutes()), int(d.Seconds())%60)
+	default:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+// Seconds formats a duration given in seconds, as the GitLab API reports them
+func Seconds(seconds float64) string {
+	return Duration(time.Duration(seconds * float64(time.Second)))
+}
+
+// Days formats d in whole days, e.g. "1 day", "30 days"; shorter durations
+// are shown as is
+func Days(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	if days == 0 && d > 0 {
+		return d.String()
+	}
+	return plural(days, "day")
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,150 @@
+package format
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "-"},
+		{-time.Second, "-"},
+		{45 * time.Second, "45s"},
+		{1500 * time.Millisecond, "2s"},
+		{2*time.Minute + 5*time.Second, "2m5s"},
+		{time.Hour + 12*time.Minute + 30*time.Second, "1h12m"},
+		{26 * time.Hour, "26h0m"},
+	}
+	for _, tt := range tests {
+		if got := Duration(tt.in); got != tt.want {
+			t.Errorf("Duration(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSeconds(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{0, "-"},
+		{59.6, "1m0s"},
+		{125, "2m5s"},
+		{3600, "1h0m"},
+	}
+	for _, tt := range tests {
+		if got := Seconds(tt.in); got != tt.want {
+			t.Errorf("Seconds(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDays(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0 days"},
+		{90 * time.Minute, "1h30m0s"},
+		{24 * time.Hour, "1 day"},
+		{30 * 24 * time.Hour, "30 days"},
+	}
+	for _, tt := range tests {
+		if got := Days(tt.in); got != tt.want {
+			t.Errorf("Days(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSizes(t *testing.T) {
+	tests := []struct {
+		in                   int64
+		binary, si, quantity string
+	}{
+		{0, "0 B", "0 B", "0B"},
+		{512, "512 B", "512 B", "512B"},
+		{1536, "1.5 KiB", "1.5 kB", "1.5Ki"},
+		{1_500_000_000, "1.4 GiB", "1.5 GB", "1.4Gi"},
+		{200 << 20, "200 MiB", "210 MB", "200Mi"},
+		{-2048, "-2.0 KiB", "-2.0 kB", "-2.0Ki"},
+	}
+	for _, tt := range tests {
+		if got := BinarySize(tt.in); got != tt.binary {
+			t.Errorf("BinarySize(%d) = %q, want %q", tt.in, got, tt.binary)
+		}
+		if got := SISize(tt.in); got != tt.si {
+			t.Errorf("SISize(%d) = %q, want %q", tt.in, got, tt.si)
+		}
+		if got := Quantity(tt.in); got != tt.quantity {
+			t.Errorf("Quantity(%d) = %q, want %q", tt.in, got, tt.quantity)
+		}
+	}
+}
+
+func TestSizeUnits(t *testing.T) {
+	t.Cleanup(func() { _ = Configure(false, false, UnitsBinary) })
+
+	if err := Configure(false, false, "si"); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	if got := Size(1536); got != "1.5 kB" {
+		t.Errorf("Size with SI units = %q, want 1.5 kB", got)
+	}
+	if err := Configure(false, false, "decimal"); err == nil {
+		t.Error("Configure accepted unknown size units")
+	}
+}
+
+func TestAge(t *testing.T) {
+	t.Cleanup(func() { _ = Configure(false, false, UnitsBinary) })
+
+	at := time.Date(2024, 3, 1, 12, 30, 45, 0, time.FixedZone("CET", 3600))
+	tests := []struct {
+		name          string
+		utc, absolute bool
+		in            time.Time
+		want          string
+	}{
+		{"zero", false, false, time.Time{}, "-"},
+		{"seconds", false, false, time.Now().Add(-45 * time.Second), "45s"},
+		{"minutes", false, false, time.Now().Add(-5 * time.Minute), "5m"},
+		{"hours", false, false, time.Now().Add(-3 * time.Hour), "3h"},
+		{"days", false, false, time.Now().Add(-12 * 24 * time.Hour), "12d"},
+		{"absolute utc", true, true, at, "2024-03-01 11:30:45 UTC"},
+		{"absolute local", false, true, at, at.Local().Format("2006-01-02 15:04:05 MST")},
+		{"utc keeps relative age", true, false, time.Now().Add(-5 * time.Minute), "5m"},
+		{"absolute zero", true, true, time.Time{}, "-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Configure(tt.utc, tt.absolute, UnitsBinary); err != nil {
+				t.Fatalf("Configure: %v", err)
+			}
+			if got := Age(tt.in); got != tt.want {
+				t.Errorf("Age = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimestampLayout(t *testing.T) {
+	t.Cleanup(func() { _ = Configure(false, false, UnitsBinary) })
+
+	at := time.Date(2024, 3, 1, 12, 30, 45, 123e6, time.FixedZone("CET", 3600))
+	if err := Configure(true, false, UnitsBinary); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	if got := TimestampLayout(at, "2006-01-02 15:04:05.000"); got != "2024-03-01 11:30:45.123" {
+		t.Errorf("TimestampLayout with --utc = %q", got)
+	}
+
+	if err := Configure(true, true, UnitsBinary); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	if got := Ago(at); got != "2024-03-01 11:30:45 UTC" {
+		t.Errorf("Ago with --absolute-time = %q", got)
+	}
+}
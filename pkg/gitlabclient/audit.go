@@ -0,0 +1,248 @@
+package gitlabclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// ProtectedBranchInfo contains the protection settings of a branch or wildcard
+type ProtectedBranchInfo struct {
+	Name                      string `json:"name"`
+	AllowForcePush            bool   `json:"allow_force_push"`
+	CodeOwnerApprovalRequired bool   `json:"code_owner_approval_required"`
+	// PushAccessLevel and MergeAccessLevel are the lowest role allowed, 0 when no one is
+	PushAccessLevel  int `json:"push_access_level"`
+	MergeAccessLevel int `json:"merge_access_level"`
+}
+
+// ApprovalRuleInfo contains a merge request approval rule
+type ApprovalRuleInfo struct {
+	Name              string   `json:"name"`
+	ApprovalsRequired int      `json:"approvals_required"`
+	ProtectedBranches []string `json:"protected_branches,omitempty"`
+	AllBranches       bool     `json:"all_branches"`
+}
+
+// ApprovalSettings contains the project-wide merge request approval settings
+type ApprovalSettings struct {
+	ApprovalsBeforeMerge     int                `json:"approvals_before_merge"`
+	ResetApprovalsOnPush     bool               `json:"reset_approvals_on_push"`
+	AuthorCanApprove         bool               `json:"author_can_approve"`
+	CommittersCanApprove     bool               `json:"committers_can_approve"`
+	OverridableApprovers     bool               `json:"overridable_approvers"`
+	RequirePasswordToApprove bool               `json:"require_password_to_approve"`
+	Rules                    []ApprovalRuleInfo `json:"rules,omitempty"`
+}
+
+// PushRuleSettings contains the push rules of a project
+type PushRuleSettings struct {
+	PreventSecrets        bool `json:"prevent_secrets"`
+	MemberCheck           bool `json:"member_check"`
+	RejectUnsignedCommits bool `json:"reject_unsigned_commits"`
+	CommitterCheck        bool `json:"commit_committer_check"`
+	DenyDeleteTag         bool `json:"deny_delete_tag"`
+}
+
+// TokenInfo contains an access token's scopes and lifetime
+type TokenInfo struct {
+	Name string `json:"name"`
+	// Kind is "personal" for the token in use or "project" for project access tokens
+	Kind        string     `json:"kind"`
+	Scopes      []string   `json:"scopes"`
+	AccessLevel int        `json:"access_level,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+}
+
+// ProjectSecurity contains the security-relevant settings of a project.
+// Settings the token may not read, or that the GitLab tier lacks, are nil.
+type ProjectSecurity struct {
+	Project           string                `json:"project"`
+	DefaultBranch     string                `json:"default_branch"`
+	ProtectedBranches []ProtectedBranchInfo `json:"protected_branches"`
+	Approvals         *ApprovalSettings     `json:"approvals,omitempty"`
+	PushRules         *PushRuleSettings     `json:"push_rules,omitempty"`
+	Tokens            []TokenInfo           `json:"tokens,omitempty"`
+	// Unavailable lists settings that could not be read, with the reason
+	Unavailable map[string]string `json:"unavailable,omitempty"`
+}
+
+// GetProjectSecurity reads protected branches, approval settings, push rules and
+// project access tokens. Only failing to read the project itself is an error.
+func (c *Client) GetProjectSecurity(ctx context.Context, projectID string) (*ProjectSecurity, error) {
+	project, _, err := c.client.Projects.GetProject(projectID, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project %s: %w", projectID, err)
+	}
+
+	sec := &ProjectSecurity{
+		Project:       project.PathWithNamespace,
+		DefaultBranch: project.DefaultBranch,
+		Unavailable:   make(map[string]string),
+	}
+
+	branches, _, err := c.client.ProtectedBranches.ListProtectedBranches(projectID, &gitlab.ListProtectedBranchesOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		sec.Unavailable["protected_branches"] = unavailableReason(err)
+	}
+	for _, b := range branches {
+		sec.ProtectedBranches = append(sec.ProtectedBranches, ProtectedBranchInfo{
+			Name:                      b.Name,
+			AllowForcePush:            b.AllowForcePush,
+			CodeOwnerApprovalRequired: b.CodeOwnerApprovalRequired,
+			PushAccessLevel:           lowestAccessLevel(b.PushAccessLevels),
+			MergeAccessLevel:          lowestAccessLevel(b.MergeAccessLevels),
+		})
+	}
+
+	if approvals, _, err := c.client.Projects.GetApprovalConfiguration(projectID, gitlab.WithContext(ctx)); err != nil {
+		sec.Unavailable["approvals"] = unavailableReason(err)
+	} else {
+		sec.Approvals = &ApprovalSettings{
+			ApprovalsBeforeMerge:     approvals.ApprovalsBeforeMerge,
+			ResetApprovalsOnPush:     approvals.ResetApprovalsOnPush,
+			AuthorCanApprove:         approvals.MergeRequestsAuthorApproval,
+			CommittersCanApprove:     !approvals.MergeRequestsDisableCommittersApproval,
+			OverridableApprovers:     !approvals.DisableOverridingApproversPerMergeRequest,
+			RequirePasswordToApprove: approvals.RequirePasswordToApprove,
+		}
+		rules, _, err := c.client.Projects.GetProjectApprovalRules(projectID, nil, gitlab.WithContext(ctx))
+		if err != nil {
+			sec.Unavailable["approval_rules"] = unavailableReason(err)
+		}
+		for _, r := range rules {
+			rule := ApprovalRuleInfo{
+				Name:              r.Name,
+				ApprovalsRequired: r.ApprovalsRequired,
+				AllBranches:       r.AppliesToAllProtectedBranches || len(r.ProtectedBranches) == 0,
+			}
+			for _, b := range r.ProtectedBranches {
+				rule.ProtectedBranches = append(rule.ProtectedBranches, b.Name)
+			}
+			sec.Approvals.Rules = append(sec.Approvals.Rules, rule)
+		}
+	}
+
+	if rules, _, err := c.client.Projects.GetProjectPushRules(projectID, gitlab.WithContext(ctx)); err != nil {
+		sec.Unavailable["push_rules"] = unavailableReason(err)
+	} else if rules != nil {
+		sec.PushRules = &PushRuleSettings{
+			PreventSecrets:        rules.PreventSecrets,
+			MemberCheck:           rules.MemberCheck,
+			RejectUnsignedCommits: rules.RejectUnsignedCommits,
+			CommitterCheck:        rules.CommitCommitterCheck,
+			DenyDeleteTag:         rules.DenyDeleteTag,
+		}
+	} else {
+		// Projects without push rules return an empty body
+		sec.PushRules = &PushRuleSettings{}
+	}
+
+	tokens, _, err := c.client.ProjectAccessTokens.ListProjectAccessTokens(projectID, &gitlab.ListProjectAccessTokensOptions{
+		PerPage: 100,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		sec.Unavailable["project_access_tokens"] = unavailableReason(err)
+	}
+	for _, t := range tokens {
+		if !t.Active || t.Revoked {
+			continue
+		}
+		sec.Tokens = append(sec.Tokens, TokenInfo{
+			Name:        t.Name,
+			Kind:        "project",
+			Scopes:      t.Scopes,
+			AccessLevel: int(t.AccessLevel),
+			ExpiresAt:   isoTime(t.ExpiresAt),
+			LastUsedAt:  t.LastUsedAt,
+		})
+	}
+
+	return sec, nil
+}
+
+// CurrentToken returns the scopes and lifetime of the token the client authenticates with
+func (c *Client) CurrentToken(ctx context.Context) (*TokenInfo, error) {
+	token, _, err := c.client.PersonalAccessTokens.GetSinglePersonalAccessToken(gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current token: %w", err)
+	}
+	return &TokenInfo{
+		Name:       token.Name,
+		Kind:       "personal",
+		Scopes:     token.Scopes,
+		ExpiresAt:  isoTime(token.ExpiresAt),
+		LastUsedAt: token.LastUsedAt,
+	}, nil
+}
+
+// ListGroupProjects returns the paths of the non-archived projects in a group and its subgroups
+func (c *Client) ListGroupProjects(ctx context.Context, group string) ([]string, error) {
+	archived := false
+	includeSubGroups := true
+	opts := &gitlab.ListGroupProjectsOptions{
+		ListOptions:      gitlab.ListOptions{PerPage: 100},
+		Archived:         &archived,
+		IncludeSubGroups: &includeSubGroups,
+	}
+
+	var result []string
+	for {
+		projects, resp, err := c.client.Groups.ListGroupProjects(group, opts, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list projects of group %s: %w", group, err)
+		}
+		for _, p := range projects {
+			result = append(result, p.PathWithNamespace)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return result, nil
+}
+
+// lowestAccessLevel returns the lowest role level in the descriptions, ignoring
+// user and group grants; 0 means no role is allowed
+func lowestAccessLevel(levels []*gitlab.BranchAccessDescription) int {
+	lowest := 0
+	for _, l := range levels {
+		if l.UserID != 0 || l.GroupID != 0 || l.AccessLevel == gitlab.NoPermissions {
+			continue
+		}
+		if lowest == 0 || int(l.AccessLevel) < lowest {
+			lowest = int(l.AccessLevel)
+		}
+	}
+	return lowest
+}
+
+// unavailableReason explains why a setting could not be read
+func unavailableReason(err error) string {
+	var errResp *gitlab.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		switch errResp.Response.StatusCode {
+		case http.StatusForbidden:
+			return "token lacks permission"
+		case http.StatusNotFound:
+			return "not available on this GitLab tier"
+		}
+	}
+	return err.Error()
+}
+
+func isoTime(t *gitlab.ISOTime) *time.Time {
+	if t == nil {
+		return nil
+	}
+	tt := time.Time(*t)
+	return &tt
+}
@@ -1,36 +1,56 @@
 package gitlabclient
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"time"
 
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/logging"
 	"github.com/xanzy/go-gitlab"
 )
 
 // Client wraps the GitLab client
 type Client struct {
 	client *gitlab.Client
+	// tokenKey identifies the token in caches without storing it
+	tokenKey string
 }
 
 // NewClient creates a new GitLab client
 func NewClient(url, token string) (*Client, error) {
-	client, err := gitlab.NewClient(token, gitlab.WithBaseURL(url))
+	opts := []gitlab.ClientOptionFunc{gitlab.WithBaseURL(url)}
+	if logging.Enabled() {
+		logging.Debug("using gitlab instance", "url", url)
+		opts = append(opts,
+			gitlab.WithHTTPClient(&http.Client{Transport: logging.Transport(nil)}),
+			gitlab.WithCustomLeveledLogger(logging.Logger()),
+		)
+	}
+
+	client, err := gitlab.NewClient(token, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gitlab client: %w", err)
 	}
 
-	return &Client{client: client}, nil
+	return &Client{client: client, tokenKey: tokenKey(url, token)}, nil
+}
+
+// Host returns the GitLab instance host the client talks to
+func (c *Client) Host() string {
+	return c.client.BaseURL().Host
 }
 
 // PipelineInfo contains pipeline information
 type PipelineInfo struct {
-	ID        int
-	Status    string
-	Ref       string
-	SHA       string
-	WebURL    string
-	CreatedAt string
-	Duration  string
+	ID        int    `json:"id"`
+	Status    string `json:"status"`
+	Ref       string `json:"ref"`
+	SHA       string `json:"sha"`
+	WebURL    string `json:"web_url"`
+	CreatedAt string `json:"created_at"`
+	Duration  string `json:"duration"`
 }
 
 // PipelineFilter contains filter options
@@ -41,7 +61,7 @@ type PipelineFilter struct {
 }
 
 // ListPipelines lists pipelines
-func (c *Client) ListPipelines(projectID string, filter PipelineFilter) ([]PipelineInfo, error) {
+func (c *Client) ListPipelines(ctx context.Context, projectID string, filter PipelineFilter) ([]PipelineInfo, error) {
 	opts := &gitlab.ListProjectPipelinesOptions{
 		ListOptions: gitlab.ListOptions{
 			PerPage: filter.Limit,
@@ -56,7 +76,7 @@ func (c *Client) ListPipelines(projectID string, filter PipelineFilter) ([]Pipel
 		opts.Ref = &filter.Ref
 	}
 
-	pipelines, _, err := c.client.Pipelines.ListProjectPipelines(projectID, opts)
+	pipelines, _, err := c.client.Pipelines.ListProjectPipelines(projectID, opts, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -72,13 +92,14 @@ func (c *Client) ListPipelines(projectID string, filter PipelineFilter) ([]Pipel
 		}
 
 		if pl.CreatedAt != nil {
-			info.CreatedAt = formatTime(*pl.CreatedAt)
+			info.CreatedAt = format.Ago(*pl.CreatedAt)
 		}
 
-		// Get duration from detailed pipeline info
-		detailed, _, err := c.client.Pipelines.GetPipeline(projectID, pl.ID)
-		if err == nil && detailed.Duration > 0 {
-			info.Duration = formatDuration(float64(detailed.Duration))
+		// Get duration from detailed pipeline info; running pipelines show "-"
+		info.Duration = format.Seconds(0)
+		detailed, _, err := c.client.Pipelines.GetPipeline(projectID, pl.ID, gitlab.WithContext(ctx))
+		if err == nil {
+			info.Duration = format.Seconds(float64(detailed.Duration))
 		}
 
 		result = append(result, info)
@@ -89,13 +110,13 @@ func (c *Client) ListPipelines(projectID string, filter PipelineFilter) ([]Pipel
 
 // JobInfo contains job information
 type JobInfo struct {
-	ID        int
-	Name      string
-	Stage     string
-	Status    string
-	Duration  string
-	StartedAt string
-	WebURL    string
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Stage     string `json:"stage"`
+	Status    string `json:"status"`
+	Duration  string `json:"duration"`
+	StartedAt string `json:"started_at"`
+	WebURL    string `json:"web_url"`
 }
 
 // JobFilter contains job filter options
@@ -105,10 +126,10 @@ type JobFilter struct {
 }
 
 // ListPipelineJobs lists pipeline jobs
-func (c *Client) ListPipelineJobs(projectID string, pipelineID int, filter JobFilter) ([]JobInfo, error) {
+func (c *Client) ListPipelineJobs(ctx context.Context, projectID string, pipelineID int, filter JobFilter) ([]JobInfo, error) {
 	opts := &gitlab.ListJobsOptions{}
 
-	jobs, _, err := c.client.Jobs.ListPipelineJobs(projectID, pipelineID, opts)
+	jobs, _, err := c.client.Jobs.ListPipelineJobs(projectID, pipelineID, opts, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -132,11 +153,11 @@ func (c *Client) ListPipelineJobs(projectID string, pipelineID int, filter JobFi
 		}
 
 		if job.Duration > 0 {
-			info.Duration = formatDuration(float64(job.Duration))
+			info.Duration = format.Seconds(float64(job.Duration))
 		}
 
 		if job.StartedAt != nil {
-			info.StartedAt = formatTime(*job.StartedAt)
+			info.StartedAt = format.Ago(*job.StartedAt)
 		}
 
 		result = append(result, info)
@@ -146,7 +167,7 @@ func (c *Client) ListPipelineJobs(projectID string, pipelineID int, filter JobFi
 }
 
 // TriggerPipeline triggers a new pipeline
-func (c *Client) TriggerPipeline(projectID, ref string, variables map[string]string) (*PipelineInfo, error) {
+func (c *Client) TriggerPipeline(ctx context.Context, projectID, ref string, variables map[string]string) (*PipelineInfo, error) {
 	opts := &gitlab.CreatePipelineOptions{
 		Ref: &ref,
 	}
@@ -165,7 +186,7 @@ func (c *Client) TriggerPipeline(projectID, ref string, variables map[string]str
 		opts.Variables = &vars
 	}
 
-	pipeline, _, err := c.client.Pipelines.CreatePipeline(projectID, opts)
+	pipeline, _, err := c.client.Pipelines.CreatePipeline(projectID, opts, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -180,9 +201,9 @@ func (c *Client) TriggerPipeline(projectID, ref string, variables map[string]str
 }
 
 // WaitForPipeline waits for pipeline to complete
-func (c *Client) WaitForPipeline(projectID string, pipelineID int) (*PipelineInfo, error) {
+func (c *Client) WaitForPipeline(ctx context.Context, projectID string, pipelineID int) (*PipelineInfo, error) {
 	for {
-		pipeline, _, err := c.client.Pipelines.GetPipeline(projectID, pipelineID)
+		pipeline, _, err := c.client.Pipelines.GetPipeline(projectID, pipelineID, gitlab.WithContext(ctx))
 		if err != nil {
 			return nil, err
 		}
@@ -196,26 +217,30 @@ func (c *Client) WaitForPipeline(projectID string, pipelineID int) (*PipelineInf
 				Ref:      pipeline.Ref,
 				SHA:      pipeline.SHA,
 				WebURL:   pipeline.WebURL,
-				Duration: formatDuration(float64(pipeline.Duration)),
+				Duration: format.Seconds(float64(pipeline.Duration)),
 			}, nil
 		}
 
-		time.Sleep(5 * time.Second)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
 	}
 }
 
 // ArtifactInfo contains artifact information
 type ArtifactInfo struct {
-	JobID    int
-	JobName  string
-	Filename string
-	Size     int64
-	ExpireAt string
+	JobID    int    `json:"job_id"`
+	JobName  string `json:"job_name"`
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+	ExpireAt string `json:"expire_at"`
 }
 
 // GetJobArtifacts gets artifacts for a job
-func (c *Client) GetJobArtifacts(projectID string, jobID int) (*ArtifactInfo, error) {
-	job, _, err := c.client.Jobs.GetJob(projectID, jobID)
+func (c *Client) GetJobArtifacts(ctx context.Context, projectID string, jobID int) (*ArtifactInfo, error) {
+	job, _, err := c.client.Jobs.GetJob(projectID, jobID, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -235,15 +260,15 @@ func (c *Client) GetJobArtifacts(projectID string, jobID int) (*ArtifactInfo, er
 	}
 
 	if job.ArtifactsExpireAt != nil {
-		info.ExpireAt = formatTime(*job.ArtifactsExpireAt)
+		info.ExpireAt = format.Ago(*job.ArtifactsExpireAt)
 	}
 
 	return info, nil
 }
 
 // ListPipelineArtifacts lists all artifacts from a pipeline
-func (c *Client) ListPipelineArtifacts(projectID string, pipelineID int) ([]ArtifactInfo, error) {
-	jobs, _, err := c.client.Jobs.ListPipelineJobs(projectID, pipelineID, nil)
+func (c *Client) ListPipelineArtifacts(ctx context.Context, projectID string, pipelineID int) ([]ArtifactInfo, error) {
+	jobs, _, err := c.client.Jobs.ListPipelineJobs(projectID, pipelineID, nil, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -259,7 +284,7 @@ func (c *Client) ListPipelineArtifacts(projectID string, pipelineID int) ([]Arti
 					Size:     int64(art.Size),
 				}
 				if job.ArtifactsExpireAt != nil {
-					info.ExpireAt = formatTime(*job.ArtifactsExpireAt)
+					info.ExpireAt = format.Ago(*job.ArtifactsExpireAt)
 				}
 				result = append(result, info)
 			}
@@ -279,8 +304,8 @@ type ProjectInfo struct {
 }
 
 // GetProject gets project information
-func (c *Client) GetProject(projectID string) (*ProjectInfo, error) {
-	project, _, err := c.client.Projects.GetProject(projectID, nil)
+func (c *Client) GetProject(ctx context.Context, projectID string) (*ProjectInfo, error) {
+	project, _, err := c.client.Projects.GetProject(projectID, nil, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -295,7 +320,7 @@ func (c *Client) GetProject(projectID string) (*ProjectInfo, error) {
 }
 
 // GetLatestPipeline gets the latest pipeline for a ref
-func (c *Client) GetLatestPipeline(projectID, ref string) (*PipelineInfo, error) {
+func (c *Client) GetLatestPipeline(ctx context.Context, projectID, ref string) (*PipelineInfo, error) {
 	opts := &gitlab.ListProjectPipelinesOptions{
 		Ref: &ref,
 		ListOptions: gitlab.ListOptions{
@@ -303,7 +328,7 @@ func (c *Client) GetLatestPipeline(projectID, ref string) (*PipelineInfo, error)
 		},
 	}
 
-	pipelines, _, err := c.client.Pipelines.ListProjectPipelines(projectID, opts)
+	pipelines, _, err := c.client.Pipelines.ListProjectPipelines(projectID, opts, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -313,7 +338,7 @@ func (c *Client) GetLatestPipeline(projectID, ref string) (*PipelineInfo, error)
 	}
 
 	pl := pipelines[0]
-	detailed, _, err := c.client.Pipelines.GetPipeline(projectID, pl.ID)
+	detailed, _, err := c.client.Pipelines.GetPipeline(projectID, pl.ID, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -324,7 +349,7 @@ func (c *Client) GetLatestPipeline(projectID, ref string) (*PipelineInfo, error)
 		Ref:      detailed.Ref,
 		SHA:      detailed.SHA,
 		WebURL:   detailed.WebURL,
-		Duration: formatDuration(float64(detailed.Duration)),
+		Duration: format.Seconds(float64(detailed.Duration)),
 	}, nil
 }
 
@@ -337,7 +362,7 @@ type PipelineStats struct {
 }
 
 // GetPipelineStats gets pipeline statistics
-func (c *Client) GetPipelineStats(projectID string) (*PipelineStats, error) {
+func (c *Client) GetPipelineStats(ctx context.Context, projectID string) (*PipelineStats, error) {
 	// Get pipelines from last 30 days
 	since := time.Now().AddDate(0, 0, -30)
 	opts := &gitlab.ListProjectPipelinesOptions{
@@ -347,7 +372,7 @@ func (c *Client) GetPipelineStats(projectID string) (*PipelineStats, error) {
 		},
 	}
 
-	pipelines, _, err := c.client.Pipelines.ListProjectPipelines(projectID, opts)
+	pipelines, _, err := c.client.Pipelines.ListProjectPipelines(projectID, opts, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -367,7 +392,7 @@ func (c *Client) GetPipelineStats(projectID string) (*PipelineStats, error) {
 		}
 
 		// Get duration
-		detailed, _, err := c.client.Pipelines.GetPipeline(projectID, pl.ID)
+		detailed, _, err := c.client.Pipelines.GetPipeline(projectID, pl.ID, gitlab.WithContext(ctx))
 		if err == nil && detailed.Duration > 0 {
 			totalDuration += float64(detailed.Duration)
 			durationCount++
@@ -376,7 +401,7 @@ func (c *Client) GetPipelineStats(projectID string) (*PipelineStats, error) {
 
 	if durationCount > 0 {
 		avgDuration := totalDuration / float64(durationCount)
-		stats.AvgDuration = formatDuration(avgDuration)
+		stats.AvgDuration = format.Seconds(avgDuration)
 	}
 
 	return stats, nil
@@ -392,8 +417,8 @@ type EnvironmentInfo struct {
 }
 
 // ListEnvironments lists project environments
-func (c *Client) ListEnvironments(projectID string) ([]EnvironmentInfo, error) {
-	envs, _, err := c.client.Environments.ListEnvironments(projectID, nil)
+func (c *Client) ListEnvironments(ctx context.Context, projectID string) ([]EnvironmentInfo, error) {
+	envs, _, err := c.client.Environments.ListEnvironments(projectID, nil, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -410,7 +435,7 @@ func (c *Client) ListEnvironments(projectID string) ([]EnvironmentInfo, error) {
 		// Get last deployment
 		if env.LastDeployment != nil {
 			if env.LastDeployment.CreatedAt != nil {
-				info.LastDeployment = formatTime(*env.LastDeployment.CreatedAt)
+				info.LastDeployment = format.Ago(*env.LastDeployment.CreatedAt)
 			}
 		}
 
@@ -420,30 +445,75 @@ func (c *Client) ListEnvironments(projectID string) ([]EnvironmentInfo, error) {
 	return result, nil
 }
 
-func formatTime(t time.Time) string {
-	d := time.Since(t)
+// ListBranches lists branch names matching search (all branches when empty)
+func (c *Client) ListBranches(ctx context.Context, projectID, search string) ([]string, error) {
+	opts := &gitlab.ListBranchesOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+	}
+	if search != "" {
+		opts.Search = &search
+	}
 
-	switch {
-	case d < time.Minute:
-		return fmt.Sprintf("%d seconds ago", int(d.Seconds()))
-	case d < time.Hour:
-		return fmt.Sprintf("%d minutes ago", int(d.Minutes()))
-	case d < 24*time.Hour:
-		return fmt.Sprintf("%d hours ago", int(d.Hours()))
-	default:
-		return fmt.Sprintf("%d days ago", int(d.Hours()/24))
+	branches, _, err := c.client.Branches.ListBranches(projectID, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, b := range branches {
+		result = append(result, b.Name)
 	}
+
+	return result, nil
+}
+
+// DeploymentInfo contains deployment information
+type DeploymentInfo struct {
+	ID          int       `json:"id"`
+	Environment string    `json:"environment"`
+	Ref         string    `json:"ref"`
+	SHA         string    `json:"sha"`
+	Status      string    `json:"status"`
+	User        string    `json:"user"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
-func formatDuration(seconds float64) string {
-	d := time.Duration(seconds) * time.Second
+// ListDeployments lists deployments updated since the given time, newest first
+func (c *Client) ListDeployments(ctx context.Context, projectID string, since time.Time) ([]DeploymentInfo, error) {
+	orderBy := "updated_at"
+	sortDesc := "desc"
+	opts := &gitlab.ListProjectDeploymentsOptions{
+		ListOptions:  gitlab.ListOptions{PerPage: 100},
+		OrderBy:      &orderBy,
+		Sort:         &sortDesc,
+		UpdatedAfter: &since,
+	}
 
-	if d < time.Minute {
-		return fmt.Sprintf("%ds", int(d.Seconds()))
+	deployments, _, err := c.client.Deployments.ListProjectDeployments(projectID, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
 	}
-	if d < time.Hour {
-		return fmt.Sprintf("%dm %ds", int(d.Minutes()), int(d.Seconds())%60)
+
+	var result []DeploymentInfo
+	for _, d := range deployments {
+		if d.CreatedAt == nil || d.CreatedAt.Before(since) {
+			continue
+		}
+		info := DeploymentInfo{
+			ID:        d.ID,
+			Ref:       d.Ref,
+			SHA:       d.SHA,
+			Status:    d.Status,
+			CreatedAt: *d.CreatedAt,
+		}
+		if d.Environment != nil {
+			info.Environment = d.Environment.Name
+		}
+		if d.User != nil {
+			info.User = d.User.Username
+		}
+		result = append(result, info)
 	}
-	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
-}
 
+	return result, nil
+}
@@ -0,0 +1,175 @@
+package gitlabclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/batch"
+	"github.com/xanzy/go-gitlab"
+)
+
+// CommitInfo contains commit information
+type CommitInfo struct {
+	SHA       string    `json:"sha"`
+	Title     string    `json:"title"`
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+	WebURL    string    `json:"web_url"`
+}
+
+// MergeRequestRef identifies a merged merge request
+type MergeRequestRef struct {
+	IID      int        `json:"iid"`
+	Title    string     `json:"title"`
+	Author   string     `json:"author"`
+	MergedAt *time.Time `json:"merged_at,omitempty"`
+	WebURL   string     `json:"web_url"`
+}
+
+// EnvironmentDiff lists what is deployed to one environment but not to another
+type EnvironmentDiff struct {
+	Source DeploymentInfo `json:"source"`
+	Target DeploymentInfo `json:"target"`
+	// Pending are commits deployed to source but not to target, newest first
+	Pending []CommitInfo `json:"pending"`
+	// MergeRequests are the merged merge requests that introduced the pending commits
+	MergeRequests []MergeRequestRef `json:"merge_requests"`
+	// TargetOnly are commits deployed to target but not to source, such as hotfixes
+	TargetOnly []CommitInfo `json:"target_only,omitempty"`
+}
+
+// LatestDeployment returns the newest successful deployment to an environment
+func (c *Client) LatestDeployment(ctx context.Context, projectID, environment string) (*DeploymentInfo, error) {
+	orderBy := "id"
+	sortDesc := "desc"
+	status := "success"
+	deployments, _, err := c.client.Deployments.ListProjectDeployments(projectID, &gitlab.ListProjectDeploymentsOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 1},
+		OrderBy:     &orderBy,
+		Sort:        &sortDesc,
+		Environment: &environment,
+		Status:      &status,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments to %s: %w", environment, err)
+	}
+	if len(deployments) == 0 {
+		return nil, fmt.Errorf("no successful deployment to environment %s", environment)
+	}
+
+	d := deployments[0]
+	info := &DeploymentInfo{
+		ID:          d.ID,
+		Environment: environment,
+		Ref:         d.Ref,
+		SHA:         d.SHA,
+		Status:      d.Status,
+	}
+	if d.User != nil {
+		info.User = d.User.Username
+	}
+	if d.CreatedAt != nil {
+		info.CreatedAt = *d.CreatedAt
+	}
+	return info, nil
+}
+
+// DiffEnvironments compares the commits deployed to source and target and looks up
+// the merge requests of the commits that have not reached target yet
+func (c *Client) DiffEnvironments(ctx context.Context, projectID, source, target string) (*EnvironmentDiff, error) {
+	src, err := c.LatestDeployment(ctx, projectID, source)
+	if err != nil {
+		return nil, err
+	}
+	tgt, err := c.LatestDeployment(ctx, projectID, target)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &EnvironmentDiff{Source: *src, Target: *tgt}
+	if src.SHA == tgt.SHA {
+		return diff, nil
+	}
+
+	diff.Pending, err = c.compareCommits(ctx, projectID, tgt.SHA, src.SHA)
+	if err != nil {
+		return nil, err
+	}
+	diff.TargetOnly, err = c.compareCommits(ctx, projectID, src.SHA, tgt.SHA)
+	if err != nil {
+		return nil, err
+	}
+
+	// Squash and fast-forward merges leave no merge commit, so every commit is looked up
+	mrs := make([][]*gitlab.MergeRequest, len(diff.Pending))
+	errs := batch.Run(len(diff.Pending), batch.DefaultWorkers, func(i int) error {
+		found, _, err := c.client.Commits.ListMergeRequestsByCommit(projectID, diff.Pending[i].SHA, gitlab.WithContext(ctx))
+		mrs[i] = found
+		return err
+	})
+	shas := make([]string, len(diff.Pending))
+	for i, commit := range diff.Pending {
+		shas[i] = commit.SHA
+	}
+	if err := batch.Collect(shas, errs); err != nil {
+		return nil, fmt.Errorf("failed to look up merge requests: %w", err)
+	}
+
+	seen := make(map[int]bool)
+	for _, found := range mrs {
+		for _, mr := range found {
+			if mr.State != "merged" || seen[mr.IID] {
+				continue
+			}
+			seen[mr.IID] = true
+			ref := MergeRequestRef{
+				IID:      mr.IID,
+				Title:    mr.Title,
+				MergedAt: mr.MergedAt,
+				WebURL:   mr.WebURL,
+			}
+			if mr.Author != nil {
+				ref.Author = mr.Author.Username
+			}
+			diff.MergeRequests = append(diff.MergeRequests, ref)
+		}
+	}
+	sort.Slice(diff.MergeRequests, func(i, j int) bool {
+		return diff.MergeRequests[i].IID > diff.MergeRequests[j].IID
+	})
+
+	return diff, nil
+}
+
+// compareCommits returns the commits reachable from to but not from from, newest first
+func (c *Client) compareCommits(ctx context.Context, projectID, from, to string) ([]CommitInfo, error) {
+	cmp, _, err := c.client.Repositories.Compare(projectID, &gitlab.CompareOptions{From: &from, To: &to}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s...%s: %w", shortRef(from), shortRef(to), err)
+	}
+
+	commits := make([]CommitInfo, 0, len(cmp.Commits))
+	for i := len(cmp.Commits) - 1; i >= 0; i-- {
+		commit := cmp.Commits[i]
+		info := CommitInfo{
+			SHA:    commit.ID,
+			Title:  commit.Title,
+			Author: commit.AuthorName,
+			WebURL: commit.WebURL,
+		}
+		if commit.CreatedAt != nil {
+			info.CreatedAt = *commit.CreatedAt
+		}
+		commits = append(commits, info)
+	}
+	return commits, nil
+}
+
+func shortRef(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
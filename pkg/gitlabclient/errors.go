@@ -0,0 +1,23 @@
+package gitlabclient
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/xanzy/go-gitlab"
+)
+
+func init() {
+	exitcode.RegisterClassifier(classifyError)
+}
+
+// classifyError marks a rejected token as a config error, since it needs a
+// new token rather than a retry
+func classifyError(err error) error {
+	var glErr *gitlab.ErrorResponse
+	if errors.As(err, &glErr) && glErr.Response != nil && glErr.Response.StatusCode == http.StatusUnauthorized {
+		return exitcode.ConfigError(err)
+	}
+	return nil
+}
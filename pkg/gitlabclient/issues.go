@@ -0,0 +1,180 @@
+package gitlabclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// IssueInfo contains issue information
+type IssueInfo struct {
+	IID       int       `json:"iid"`
+	Title     string    `json:"title"`
+	Type      string    `json:"type"`
+	State     string    `json:"state"`
+	Labels    []string  `json:"labels,omitempty"`
+	Assignees []string  `json:"assignees,omitempty"`
+	Milestone string    `json:"milestone,omitempty"`
+	Author    string    `json:"author"`
+	Severity  string    `json:"severity,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	WebURL    string    `json:"web_url"`
+}
+
+// IssueFilter contains issue filter options
+type IssueFilter struct {
+	// State is opened, closed or all; empty means opened
+	State  string
+	Labels []string
+	// Assignee is a username, or "none" or "any"
+	Assignee  string
+	Milestone string
+	// Type is issue, incident or test_case; empty means all types
+	Type  string
+	Limit int
+}
+
+// incidentSeverities maps severity labels to their rank, most severe first
+var incidentSeverities = []string{"critical", "high", "medium", "low", "unknown"}
+
+// ListIssues lists project issues, newest first
+func (c *Client) ListIssues(ctx context.Context, projectID string, filter IssueFilter) ([]IssueInfo, error) {
+	state := filter.State
+	if state == "" {
+		state = "opened"
+	}
+	orderBy := "created_at"
+	sortDesc := "desc"
+	opts := &gitlab.ListProjectIssuesOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+		OrderBy:     &orderBy,
+		Sort:        &sortDesc,
+	}
+	if state != "all" {
+		opts.State = &state
+	}
+	if len(filter.Labels) > 0 {
+		labels := gitlab.LabelOptions(filter.Labels)
+		opts.Labels = &labels
+	}
+	switch strings.ToLower(filter.Assignee) {
+	case "":
+	case "none":
+		opts.AssigneeID = gitlab.AssigneeID(gitlab.UserIDNone)
+	case "any":
+		opts.AssigneeID = gitlab.AssigneeID(gitlab.UserIDAny)
+	default:
+		opts.AssigneeUsername = &filter.Assignee
+	}
+	if filter.Milestone != "" {
+		opts.Milestone = &filter.Milestone
+	}
+	if filter.Type != "" {
+		opts.IssueType = &filter.Type
+	}
+
+	var result []IssueInfo
+	for {
+		issues, resp, err := c.client.Issues.ListProjectIssues(projectID, opts, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issues: %w", err)
+		}
+		for _, issue := range issues {
+			result = append(result, issueInfo(issue))
+			if filter.Limit > 0 && len(result) >= filter.Limit {
+				return result, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
+
+// ListIncidents lists open incidents, most severe and then oldest first
+func (c *Client) ListIncidents(ctx context.Context, projectID string, filter IssueFilter) ([]IssueInfo, error) {
+	filter.Type = "incident"
+	incidents, err := c.ListIssues(ctx, projectID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(incidents, func(i, j int) bool {
+		ri, rj := SeverityRank(incidents[i].Severity), SeverityRank(incidents[j].Severity)
+		if ri != rj {
+			return ri < rj
+		}
+		return incidents[i].CreatedAt.Before(incidents[j].CreatedAt)
+	})
+	return incidents, nil
+}
+
+// SeverityRank orders incident severities, 0 being the most severe
+func SeverityRank(severity string) int {
+	for i, s := range incidentSeverities {
+		if s == severity {
+			return i
+		}
+	}
+	return len(incidentSeverities)
+}
+
+func issueInfo(issue *gitlab.Issue) IssueInfo {
+	info := IssueInfo{
+		IID:    issue.IID,
+		Title:  issue.Title,
+		Type:   "issue",
+		State:  issue.State,
+		Labels: issue.Labels,
+		WebURL: issue.WebURL,
+	}
+	if issue.IssueType != nil {
+		info.Type = *issue.IssueType
+	}
+	for _, a := range issue.Assignees {
+		info.Assignees = append(info.Assignees, a.Username)
+	}
+	if issue.Milestone != nil {
+		info.Milestone = issue.Milestone.Title
+	}
+	if issue.Author != nil {
+		info.Author = issue.Author.Username
+	}
+	if issue.CreatedAt != nil {
+		info.CreatedAt = *issue.CreatedAt
+	}
+	if issue.UpdatedAt != nil {
+		info.UpdatedAt = *issue.UpdatedAt
+	}
+	if info.Type == "incident" {
+		info.Severity = labelSeverity(issue.Labels)
+	}
+	return info
+}
+
+// labelSeverity reads an incident's severity from a severity::<level> or S1-S4 label,
+// since the REST API does not expose the severity field
+func labelSeverity(labels []string) string {
+	for _, label := range labels {
+		value := strings.TrimPrefix(strings.ToLower(label), "severity::")
+		switch value {
+		case "s1", "1", "critical":
+			return "critical"
+		case "s2", "2", "high":
+			return "high"
+		case "s3", "3", "medium":
+			return "medium"
+		case "s4", "4", "low":
+			return "low"
+		}
+	}
+	return "unknown"
+}
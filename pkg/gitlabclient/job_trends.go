@@ -0,0 +1,192 @@
+package gitlabclient
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/batch"
+	"github.com/xanzy/go-gitlab"
+)
+
+// JobRun is one finished run of a job
+type JobRun struct {
+	Job        string    `json:"job"`
+	JobID      int       `json:"job_id"`
+	PipelineID int       `json:"pipeline_id"`
+	Status     string    `json:"status"`
+	Ref        string    `json:"ref"`
+	Duration   float64   `json:"duration_seconds"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// JobRuns returns the finished runs of jobs in the last pipelines finished pipelines,
+// grouped by job name and ordered newest first. With names, only those jobs are kept.
+// Retried jobs only count their last attempt.
+func (c *Client) JobRuns(ctx context.Context, projectID, ref string, pipelines int, names []string) (map[string][]JobRun, error) {
+	scope := "finished"
+	opts := &gitlab.ListProjectPipelinesOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+		Scope:       &scope,
+	}
+	if ref != "" {
+		opts.Ref = &ref
+	}
+
+	var ids []int
+	for len(ids) < pipelines {
+		page, resp, err := c.client.Pipelines.ListProjectPipelines(projectID, opts, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pipelines: %w", err)
+		}
+		for _, pl := range page {
+			if len(ids) < pipelines {
+				ids = append(ids, pl.ID)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, n := range names {
+		wanted[n] = true
+	}
+
+	scopes := []gitlab.BuildStateValue{gitlab.Success, gitlab.Failed}
+	perPipeline := make([][]JobRun, len(ids))
+	errs := batch.Run(len(ids), batch.DefaultWorkers, func(i int) error {
+		jobs, _, err := c.client.Jobs.ListPipelineJobs(projectID, ids[i], &gitlab.ListJobsOptions{
+			ListOptions: gitlab.ListOptions{PerPage: 100},
+			Scope:       &scopes,
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		for _, job := range jobs {
+			if len(wanted) > 0 && !wanted[job.Name] {
+				continue
+			}
+			if job.FinishedAt == nil || job.Duration <= 0 {
+				continue
+			}
+			perPipeline[i] = append(perPipeline[i], JobRun{
+				Job:        job.Name,
+				JobID:      job.ID,
+				PipelineID: ids[i],
+				Status:     job.Status,
+				Ref:        job.Ref,
+				Duration:   job.Duration,
+				FinishedAt: *job.FinishedAt,
+			})
+		}
+		return nil
+	})
+	items := make([]string, len(ids))
+	for i, id := range ids {
+		items[i] = "pipeline #" + strconv.Itoa(id)
+	}
+	if err := batch.Collect(items, errs); err != nil {
+		return nil, fmt.Errorf("failed to list pipeline jobs: %w", err)
+	}
+
+	result := make(map[string][]JobRun)
+	for _, runs := range perPipeline {
+		for _, run := range runs {
+			result[run.Job] = append(result[run.Job], run)
+		}
+	}
+	for _, runs := range result {
+		sort.Slice(runs, func(i, j int) bool {
+			return runs[i].FinishedAt.After(runs[j].FinishedAt)
+		})
+	}
+	return result, nil
+}
+
+// JobTrend summarizes the durations of a job's successful runs and compares the
+// most recent runs with the ones before them
+type JobTrend struct {
+	Job      string  `json:"job"`
+	Runs     int     `json:"runs"`
+	Failed   int     `json:"failed"`
+	Median   float64 `json:"median_seconds"`
+	P95      float64 `json:"p95_seconds"`
+	Fastest  float64 `json:"fastest_seconds"`
+	Slowest  float64 `json:"slowest_seconds"`
+	Recent   float64 `json:"recent_median_seconds"`
+	Baseline float64 `json:"baseline_median_seconds"`
+	// Change is the recent median relative to the baseline median, in percent
+	Change float64 `json:"change_percent"`
+	// Slower is set when Change reaches the regression threshold
+	Slower bool `json:"slower"`
+	// Durations are the successful run durations, newest first
+	Durations []float64 `json:"durations_seconds"`
+}
+
+// NewJobTrend computes duration statistics for runs ordered newest first. The
+// newest recent successful runs are compared with the rest; a job is flagged
+// slower when its recent median is at least threshold percent above the baseline.
+func NewJobTrend(job string, runs []JobRun, recent int, threshold float64) JobTrend {
+	trend := JobTrend{Job: job}
+	for _, r := range runs {
+		if r.Status != "success" {
+			trend.Failed++
+			continue
+		}
+		trend.Durations = append(trend.Durations, r.Duration)
+	}
+	trend.Runs = len(trend.Durations)
+	if trend.Runs == 0 {
+		return trend
+	}
+
+	sorted := append([]float64(nil), trend.Durations...)
+	sort.Float64s(sorted)
+	trend.Median = percentile(sorted, 50)
+	trend.P95 = percentile(sorted, 95)
+	trend.Fastest = sorted[0]
+	trend.Slowest = sorted[len(sorted)-1]
+
+	// A trend needs a baseline at least as large as the recent window
+	if recent <= 0 || trend.Runs < 2*recent {
+		return trend
+	}
+	trend.Recent = median(trend.Durations[:recent])
+	trend.Baseline = median(trend.Durations[recent:])
+	if trend.Baseline > 0 {
+		trend.Change = (trend.Recent - trend.Baseline) / trend.Baseline * 100
+		trend.Slower = trend.Change >= threshold
+	}
+	return trend
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
@@ -0,0 +1,41 @@
+package gitlabclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// LintResult contains the outcome of validating a CI configuration
+type LintResult struct {
+	Valid      bool     `json:"valid"`
+	Errors     []string `json:"errors,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+	MergedYAML string   `json:"merged_yaml,omitempty"`
+}
+
+// LintConfig validates CI configuration content in the context of a project, so
+// local, project and template includes are resolved. With dryRun a pipeline
+// creation is simulated on ref, which also catches rules and needs errors.
+func (c *Client) LintConfig(ctx context.Context, projectID, content, ref string, dryRun bool) (*LintResult, error) {
+	opts := &gitlab.ProjectNamespaceLintOptions{
+		Content: &content,
+		DryRun:  &dryRun,
+	}
+	if ref != "" {
+		opts.Ref = &ref
+	}
+
+	result, _, err := c.client.Validate.ProjectNamespaceLint(projectID, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lint CI configuration: %w", err)
+	}
+
+	return &LintResult{
+		Valid:      result.Valid,
+		Errors:     result.Errors,
+		Warnings:   result.Warnings,
+		MergedYAML: result.MergedYaml,
+	}, nil
+}
@@ -0,0 +1,156 @@
+package gitlabclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// MinutesUsage contains the CI minutes a project used on shared runners
+type MinutesUsage struct {
+	Project string `json:"project"`
+	// MonthToDate is minutes used since the start of the current month
+	MonthToDate float64 `json:"month_to_date"`
+	// Trailing is minutes used in the trailing window
+	Trailing float64 `json:"trailing"`
+	// Jobs counts shared runner jobs in either period
+	Jobs int `json:"jobs"`
+}
+
+// MinutesQuota is the monthly shared runner minutes quota of a top-level namespace
+type MinutesQuota struct {
+	Namespace string `json:"namespace"`
+	// Limit is the monthly quota including purchased minutes, 0 when unlimited or unknown
+	Limit int `json:"limit"`
+	Extra int `json:"extra"`
+}
+
+// ProjectMinutes sums the duration of finished jobs that ran on shared runners since
+// the start of the trailing window and since the start of the month, multiplied by
+// the runner cost factor
+func (c *Client) ProjectMinutes(ctx context.Context, projectID string, windowStart, monthStart time.Time, costFactor float64) (*MinutesUsage, error) {
+	since := windowStart
+	if monthStart.Before(since) {
+		since = monthStart
+	}
+
+	scopes := []gitlab.BuildStateValue{gitlab.Success, gitlab.Failed, gitlab.Canceled}
+	opts := &gitlab.ListJobsOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+		Scope:       &scopes,
+	}
+
+	usage := &MinutesUsage{Project: projectID}
+	for {
+		jobs, resp, err := c.client.Jobs.ListProjectJobs(projectID, opts, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list jobs of %s: %w", projectID, err)
+		}
+
+		// Jobs are returned newest first, so stop at the first one before the window
+		done := false
+		for _, job := range jobs {
+			if job.CreatedAt != nil && job.CreatedAt.Before(since) {
+				done = true
+				break
+			}
+			if job.FinishedAt == nil || job.FinishedAt.Before(since) || !job.Runner.IsShared {
+				continue
+			}
+			minutes := job.Duration / 60 * costFactor
+			usage.Jobs++
+			if !job.FinishedAt.Before(windowStart) {
+				usage.Trailing += minutes
+			}
+			if !job.FinishedAt.Before(monthStart) {
+				usage.MonthToDate += minutes
+			}
+		}
+		if done || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return usage, nil
+}
+
+// NamespaceQuota returns the minutes quota of the top-level group that owns a project or group.
+// Projects in personal namespaces have no group quota and return a zero limit.
+func (c *Client) NamespaceQuota(ctx context.Context, projectID, group string) (*MinutesQuota, error) {
+	fullPath := group
+	if fullPath == "" {
+		project, _, err := c.client.Projects.GetProject(projectID, nil, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project %s: %w", projectID, err)
+		}
+		if project.Namespace == nil || project.Namespace.Kind != "group" {
+			return &MinutesQuota{Namespace: project.PathWithNamespace}, nil
+		}
+		fullPath = project.Namespace.FullPath
+	}
+
+	// Quotas are set on the top-level group and shared by its subgroups
+	root, _, _ := strings.Cut(fullPath, "/")
+	g, _, err := c.client.Groups.GetGroup(root, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group %s: %w", root, err)
+	}
+
+	return &MinutesQuota{
+		Namespace: g.FullPath,
+		Limit:     g.SharedRunnersMinutesLimit + g.ExtraSharedRunnersMinutesLimit,
+		Extra:     g.ExtraSharedRunnersMinutesLimit,
+	}, nil
+}
+
+// MinutesReport combines usage of one or more projects with the namespace quota and
+// forecasts when the quota runs out at the trailing daily rate
+type MinutesReport struct {
+	Quota        *MinutesQuota  `json:"quota"`
+	Projects     []MinutesUsage `json:"projects"`
+	MonthToDate  float64        `json:"month_to_date"`
+	Trailing     float64        `json:"trailing"`
+	TrailingDays int            `json:"trailing_days"`
+	DailyRate    float64        `json:"daily_rate"`
+	Remaining    float64        `json:"remaining,omitempty"`
+	ResetsAt     time.Time      `json:"resets_at"`
+	// ExhaustedAt is the forecast date the quota runs out, nil when it lasts until the reset
+	ExhaustedAt *time.Time `json:"exhausted_at,omitempty"`
+}
+
+// NewMinutesReport totals project usage and forecasts quota exhaustion
+func NewMinutesReport(quota *MinutesQuota, usages []MinutesUsage, trailingDays int, now time.Time) *MinutesReport {
+	report := &MinutesReport{
+		Quota:        quota,
+		Projects:     usages,
+		TrailingDays: trailingDays,
+		ResetsAt:     time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, now.Location()),
+	}
+	for _, u := range usages {
+		report.MonthToDate += u.MonthToDate
+		report.Trailing += u.Trailing
+	}
+	if trailingDays > 0 {
+		report.DailyRate = report.Trailing / float64(trailingDays)
+	}
+
+	if quota == nil || quota.Limit == 0 {
+		return report
+	}
+	report.Remaining = float64(quota.Limit) - report.MonthToDate
+	switch {
+	case report.Remaining <= 0:
+		report.Remaining = 0
+		report.ExhaustedAt = &now
+	case report.DailyRate > 0:
+		exhausted := now.Add(time.Duration(report.Remaining / report.DailyRate * float64(24*time.Hour)))
+		if exhausted.Before(report.ResetsAt) {
+			report.ExhaustedAt = &exhausted
+		}
+	}
+	return report
+}
@@ -0,0 +1,164 @@
+package gitlabclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/resultcache"
+	"github.com/xanzy/go-gitlab"
+)
+
+// Token scopes required by commands
+const (
+	ScopeReadAPI = "read_api"
+	ScopeAPI     = "api"
+)
+
+// tokenCacheTTL is how long a token check is reused across invocations
+const tokenCacheTTL = time.Hour
+
+// tokenCacheEntry is a cached token check; the token itself is never stored
+type tokenCacheEntry struct {
+	Token     TokenInfo `json:"token"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+var (
+	tokenCacheMu sync.Mutex
+	tokenChecks  = make(map[string]tokenCacheEntry)
+)
+
+// ErrTokenUncheckable is returned when the token cannot describe itself, such as
+// OAuth and CI job tokens or instances older than GitLab 15.5
+var ErrTokenUncheckable = errors.New("token cannot be inspected")
+
+// CheckToken validates the token's scopes and expiry before a command runs. It
+// returns an error when the token is invalid or lacks the required scope, and
+// warnings when it expires within warnWithin. Results are cached for an hour.
+func (c *Client) CheckToken(ctx context.Context, required string, warnWithin time.Duration) ([]string, error) {
+	token, err := c.cachedToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasScope(token.Scopes, required) {
+		return nil, exitcode.ConfigError(fmt.Errorf("token %q lacks the %s scope this command needs (has %s)",
+			token.Name, required, strings.Join(token.Scopes, ", ")))
+	}
+
+	var warnings []string
+	if token.ExpiresAt != nil {
+		left := time.Until(*token.ExpiresAt)
+		if left <= warnWithin {
+			warnings = append(warnings, fmt.Sprintf("GitLab token %q expires on %s (%d days left)",
+				token.Name, token.ExpiresAt.Format("2006-01-02"), int(left.Hours()/24)))
+		}
+	}
+	return warnings, nil
+}
+
+// cachedToken returns the token details from the in-memory or on-disk cache,
+// or asks GitLab and caches the answer
+func (c *Client) cachedToken(ctx context.Context) (*TokenInfo, error) {
+	tokenCacheMu.Lock()
+	defer tokenCacheMu.Unlock()
+
+	if entry, ok := tokenChecks[c.tokenKey]; ok && time.Since(entry.CheckedAt) < tokenCacheTTL {
+		return &entry.Token, nil
+	}
+
+	stored := loadTokenCache()
+	if entry, ok := stored[c.tokenKey]; ok && time.Since(entry.CheckedAt) < tokenCacheTTL {
+		tokenChecks[c.tokenKey] = entry
+		return &entry.Token, nil
+	}
+
+	token, err := c.CurrentToken(ctx)
+	if err != nil {
+		var errResp *gitlab.ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusUnauthorized {
+			return nil, exitcode.ConfigError(fmt.Errorf("GitLab token is invalid, expired or revoked"))
+		}
+		return nil, fmt.Errorf("%w: %v", ErrTokenUncheckable, err)
+	}
+
+	entry := tokenCacheEntry{Token: *token, CheckedAt: time.Now()}
+	tokenChecks[c.tokenKey] = entry
+	stored[c.tokenKey] = entry
+	saveTokenCache(stored)
+	return token, nil
+}
+
+// hasScope reports whether scopes grant required; api implies read_api
+func hasScope(scopes []string, required string) bool {
+	for _, s := range scopes {
+		if s == required || (required == ScopeReadAPI && s == ScopeAPI) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenKey hashes the instance URL and token into a cache key
+func tokenKey(url, token string) string {
+	sum := sha256.Sum256([]byte(url + "\x00" + token))
+	return hex.EncodeToString(sum[:])
+}
+
+// tokenCachePath returns the location of the token check cache
+func tokenCachePath() (string, error) {
+	dir, err := resultcache.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gitlab-tokens.json"), nil
+}
+
+// loadTokenCache reads cached token checks, dropping expired entries
+func loadTokenCache() map[string]tokenCacheEntry {
+	entries := make(map[string]tokenCacheEntry)
+	path, err := tokenCachePath()
+	if err != nil {
+		return entries
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entries
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return make(map[string]tokenCacheEntry)
+	}
+	for key, entry := range entries {
+		if time.Since(entry.CheckedAt) >= tokenCacheTTL {
+			delete(entries, key)
+		}
+	}
+	return entries
+}
+
+// saveTokenCache writes token checks; failures only cost a lookup next time
+func saveTokenCache(entries map[string]tokenCacheEntry) {
+	path, err := tokenCachePath()
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,157 @@
+package gitlabclient
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// Webhook event kinds
+const (
+	EventPipeline   = "pipeline"
+	EventJob        = "job"
+	EventDeployment = "deployment"
+)
+
+// maxWebhookPayload bounds the size of a webhook request body
+const maxWebhookPayload = 5 << 20
+
+// ErrUnsupportedEvent is returned for webhook events other than pipeline, job and deployment
+var ErrUnsupportedEvent = errors.New("unsupported event type")
+
+// WebhookEvent is a pipeline, job or deployment webhook reduced to what a feed shows
+type WebhookEvent struct {
+	Kind    string `json:"kind"`
+	Project string `json:"project"`
+	ID      int    `json:"id"`
+	// Name is the job name or environment; empty for pipelines
+	Name       string    `json:"name,omitempty"`
+	Stage      string    `json:"stage,omitempty"`
+	Status     string    `json:"status"`
+	Ref        string    `json:"ref"`
+	SHA        string    `json:"sha"`
+	User       string    `json:"user,omitempty"`
+	Duration   float64   `json:"duration_seconds,omitempty"`
+	Reason     string    `json:"failure_reason,omitempty"`
+	URL        string    `json:"url,omitempty"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// Finished reports whether the event carries a final status
+func (e WebhookEvent) Finished() bool {
+	switch e.Status {
+	case "success", "failed", "canceled", "skipped":
+		return true
+	}
+	return false
+}
+
+// WebhookHandler returns an HTTP handler that verifies the X-Gitlab-Token header
+// against secret, when set, and passes pipeline, job and deployment events to handle
+func WebhookHandler(secret string, handle func(WebhookEvent)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if secret != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Gitlab-Token")), []byte(secret)) != 1 {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookPayload))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+
+		event, err := ParseWebhookEvent(gitlab.HookEventType(r), payload)
+		if errors.Is(err, ErrUnsupportedEvent) {
+			// Acknowledge so GitLab does not disable the hook for failing deliveries
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		handle(*event)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// ParseWebhookEvent parses a pipeline, job or deployment webhook payload
+func ParseWebhookEvent(eventType gitlab.EventType, payload []byte) (*WebhookEvent, error) {
+	switch eventType {
+	case gitlab.EventTypePipeline, gitlab.EventTypeJob, gitlab.EventTypeDeployment:
+	default:
+		return nil, ErrUnsupportedEvent
+	}
+
+	parsed, err := gitlab.ParseWebhook(eventType, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s payload: %w", eventType, err)
+	}
+
+	event := &WebhookEvent{ReceivedAt: time.Now()}
+	switch e := parsed.(type) {
+	case *gitlab.PipelineEvent:
+		event.Kind = EventPipeline
+		event.Project = e.Project.PathWithNamespace
+		event.ID = e.ObjectAttributes.ID
+		event.Status = e.ObjectAttributes.Status
+		event.Ref = e.ObjectAttributes.Ref
+		event.SHA = e.ObjectAttributes.SHA
+		event.Duration = float64(e.ObjectAttributes.Duration)
+		event.URL = e.ObjectAttributes.URL
+		if e.User != nil {
+			event.User = e.User.Username
+		}
+	case *gitlab.JobEvent:
+		event.Kind = EventJob
+		event.Project = e.ProjectName
+		event.ID = e.BuildID
+		event.Name = e.BuildName
+		event.Stage = e.BuildStage
+		event.Status = e.BuildStatus
+		event.Ref = e.Ref
+		event.SHA = e.SHA
+		event.Duration = e.BuildDuration
+		if e.BuildStatus == "failed" {
+			event.Reason = e.BuildFailureReason
+		}
+		if e.Repository != nil {
+			if e.Repository.PathWithNamespace != "" {
+				event.Project = e.Repository.PathWithNamespace
+			}
+			if e.Repository.Homepage != "" {
+				event.URL = fmt.Sprintf("%s/-/jobs/%d", e.Repository.Homepage, e.BuildID)
+			}
+		}
+		if e.User != nil {
+			event.User = e.User.Username
+		}
+	case *gitlab.DeploymentEvent:
+		event.Kind = EventDeployment
+		event.Project = e.Project.PathWithNamespace
+		event.ID = e.DeploymentID
+		event.Name = e.Environment
+		event.Status = e.Status
+		event.Ref = e.Ref
+		event.SHA = e.ShortSHA
+		event.URL = e.DeployableURL
+		if e.User != nil {
+			event.User = e.User.Username
+		}
+	default:
+		return nil, ErrUnsupportedEvent
+	}
+
+	return event, nil
+}
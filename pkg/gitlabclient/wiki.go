@@ -0,0 +1,51 @@
+package gitlabclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// PublishWikiPage creates or replaces the Markdown wiki page at slug (e.g.
+// compliance/weekly). GitLab derives the slug from the page title, so the
+// slug doubles as the title to keep the page at the same address.
+func (c *Client) PublishWikiPage(ctx context.Context, projectID, slug, content string) error {
+	format := gitlab.WikiFormatMarkdown
+
+	_, _, err := c.client.Wikis.GetWikiPage(projectID, slug, nil, gitlab.WithContext(ctx))
+	var errResp *gitlab.ErrorResponse
+	switch {
+	case err == nil:
+		_, _, err = c.client.Wikis.EditWikiPage(projectID, slug, &gitlab.EditWikiPageOptions{
+			Content: &content,
+			Format:  &format,
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to update wiki page %s: %w", slug, err)
+		}
+	case errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound:
+		_, _, err = c.client.Wikis.CreateWikiPage(projectID, &gitlab.CreateWikiPageOptions{
+			Title:   &slug,
+			Content: &content,
+			Format:  &format,
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to create wiki page %s: %w", slug, err)
+		}
+	default:
+		return fmt.Errorf("failed to get wiki page %s: %w", slug, err)
+	}
+	return nil
+}
+
+// WikiPageURL returns the browser URL of a project's wiki page
+func (c *Client) WikiPageURL(ctx context.Context, projectID, slug string) (string, error) {
+	project, _, err := c.client.Projects.GetProject(projectID, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to get project: %w", err)
+	}
+	return project.WebURL + "/-/wikis/" + slug, nil
+}
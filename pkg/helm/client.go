@@ -0,0 +1,228 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/logging"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+)
+
+// Release statuses as reported by Helm
+const (
+	StatusDeployed        = "deployed"
+	StatusFailed          = "failed"
+	StatusSuperseded      = "superseded"
+	StatusUninstalling    = "uninstalling"
+	StatusPendingInstall  = "pending-install"
+	StatusPendingUpgrade  = "pending-upgrade"
+	StatusPendingRollback = "pending-rollback"
+)
+
+// Client reads Helm releases through the Helm SDK, using the storage driver
+// selected by HELM_DRIVER like the helm CLI does
+type Client struct {
+	dynamic    dynamic.Interface
+	mapper     meta.RESTMapper
+	server     string
+	kubeconfig string
+	context    string
+}
+
+// NewClient creates a Helm client on top of a Kubernetes client
+func NewClient(kc *k8s.Client, kubeconfig, context string) (*Client, error) {
+	dyn, err := dynamic.NewForConfig(kc.RESTConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	cached := memory.NewMemCacheClient(kc.Clientset().Discovery())
+
+	return &Client{
+		dynamic:    dyn,
+		mapper:     restmapper.NewDeferredDiscoveryRESTMapper(cached),
+		server:     kc.Server(),
+		kubeconfig: kubeconfig,
+		context:    context,
+	}, nil
+}
+
+// actionConfig returns a Helm action configuration for namespace; an empty
+// namespace is used for listing across all namespaces
+func (c *Client) actionConfig(namespace string) (*action.Configuration, error) {
+	flags := genericclioptions.NewConfigFlags(true)
+	flags.Namespace = &namespace
+	if c.kubeconfig != "" {
+		flags.KubeConfig = &c.kubeconfig
+	}
+	if c.context != "" {
+		flags.Context = &c.context
+	}
+
+	cfg := new(action.Configuration)
+	debug := func(format string, v ...interface{}) {
+		logging.Debug(fmt.Sprintf(format, v...))
+	}
+	if err := cfg.Init(flags, namespace, os.Getenv("HELM_DRIVER"), debug); err != nil {
+		return nil, fmt.Errorf("failed to initialize helm: %w", err)
+	}
+	return cfg, nil
+}
+
+// Server returns the API server URL the client talks to
+func (c *Client) Server() string {
+	return c.server
+}
+
+// Release is one revision of a Helm release
+type Release struct {
+	Name         string                 `json:"name"`
+	Namespace    string                 `json:"namespace"`
+	Revision     int                    `json:"revision"`
+	Status       string                 `json:"status"`
+	Chart        string                 `json:"chart"`
+	ChartVersion string                 `json:"chart_version"`
+	AppVersion   string                 `json:"app_version"`
+	Updated      time.Time              `json:"updated"`
+	Description  string                 `json:"description"`
+	Values       map[string]interface{} `json:"values,omitempty"`
+	Manifest     string                 `json:"-"`
+}
+
+// IsPending reports whether the release is stuck mid-operation
+func (r Release) IsPending() bool {
+	switch r.Status {
+	case StatusPendingInstall, StatusPendingUpgrade, StatusPendingRollback, StatusUninstalling:
+		return true
+	}
+	return false
+}
+
+// IsProblem reports whether the release is failed or pending
+func (r Release) IsProblem() bool {
+	return r.Status == StatusFailed || r.IsPending()
+}
+
+// fromRelease converts a Helm SDK release record
+func fromRelease(r *release.Release) Release {
+	rel := Release{
+		Name:      r.Name,
+		Namespace: r.Namespace,
+		Revision:  r.Version,
+		Values:    r.Config,
+		Manifest:  r.Manifest,
+	}
+	if r.Info != nil {
+		rel.Status = r.Info.Status.String()
+		rel.Updated = r.Info.LastDeployed.Time
+		rel.Description = r.Info.Description
+	}
+	if r.Chart != nil && r.Chart.Metadata != nil {
+		rel.Chart = r.Chart.Metadata.Name
+		rel.ChartVersion = r.Chart.Metadata.Version
+		rel.AppVersion = r.Chart.Metadata.AppVersion
+	}
+	return rel
+}
+
+// ListReleases returns the latest revision of each release, sorted by namespace and name
+func (c *Client) ListReleases(ctx context.Context, namespace string) ([]Release, error) {
+	cfg, err := c.actionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	list := action.NewList(cfg)
+	list.AllNamespaces = namespace == ""
+	// Failed and stuck releases are what callers look for, so no status is filtered out
+	list.StateMask = action.ListAll
+
+	releases, err := list.Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	result := make([]Release, 0, len(releases))
+	for _, r := range releases {
+		result = append(result, fromRelease(r))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// FindProblemReleases returns releases whose latest revision is failed or stuck pending
+func (c *Client) FindProblemReleases(ctx context.Context, namespace string) ([]Release, error) {
+	releases, err := c.ListReleases(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []Release
+	for _, rel := range releases {
+		if rel.IsProblem() {
+			problems = append(problems, rel)
+		}
+	}
+	return problems, nil
+}
+
+// History returns all revisions of a release, oldest first
+func (c *Client) History(ctx context.Context, namespace, name string) ([]Release, error) {
+	cfg, err := c.actionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := action.NewHistory(cfg).Run(name)
+	if errors.Is(err, driver.ErrReleaseNotFound) || (err == nil && len(revisions) == 0) {
+		return nil, fmt.Errorf("release %q not found in namespace %q", name, namespace)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release history: %w", err)
+	}
+
+	result := make([]Release, 0, len(revisions))
+	for _, r := range revisions {
+		result = append(result, fromRelease(r))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Revision < result[j].Revision
+	})
+	return result, nil
+}
+
+// GetRevision returns one revision of a release; revision 0 means the latest
+func (c *Client) GetRevision(ctx context.Context, namespace, name string, revision int) (*Release, error) {
+	history, err := c.History(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if revision == 0 {
+		return &history[len(history)-1], nil
+	}
+	for i := range history {
+		if history[i].Revision == revision {
+			return &history[i], nil
+		}
+	}
+	return nil, fmt.Errorf("revision %d of release %q not found", revision, name)
+}
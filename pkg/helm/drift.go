@@ -0,0 +1,207 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DriftItem is a rendered resource whose live state no longer matches the release
+type DriftItem struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Issue     string `json:"issue"`
+}
+
+// manifestObjects splits a release manifest into objects. Each document goes
+// through JSON so values have the types unstructured helpers expect.
+func manifestObjects(manifest string) ([]map[string]interface{}, error) {
+	var objects []map[string]interface{}
+	dec := yaml.NewDecoder(bytes.NewReader([]byte(manifest)))
+	for {
+		var doc map[string]interface{}
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse manifest: %w", err)
+		}
+		if doc == nil || doc["kind"] == nil {
+			continue
+		}
+
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse manifest: %w", err)
+		}
+		var obj map[string]interface{}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest: %w", err)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// CheckDrift compares the resources rendered in a release with the cluster.
+// It reports resources that were deleted and workloads whose container images
+// were changed outside Helm.
+func (c *Client) CheckDrift(ctx context.Context, rel Release) ([]DriftItem, error) {
+	objects, err := manifestObjects(rel.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	var drift []DriftItem
+	for _, obj := range objects {
+		u := unstructured.Unstructured{Object: obj}
+		gvk := schema.FromAPIVersionAndKind(u.GetAPIVersion(), u.GetKind())
+
+		mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			// CRD removed or API version no longer served
+			drift = append(drift, DriftItem{Kind: u.GetKind(), Name: u.GetName(), Namespace: u.GetNamespace(), Issue: "API no longer served"})
+			continue
+		}
+
+		namespace := u.GetNamespace()
+		if namespace == "" && mapping.Scope.Name() == "namespace" {
+			namespace = rel.Namespace
+		}
+
+		live, err := c.dynamic.Resource(mapping.Resource).Namespace(namespace).Get(ctx, u.GetName(), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			drift = append(drift, DriftItem{Kind: u.GetKind(), Name: u.GetName(), Namespace: namespace, Issue: "missing from cluster"})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s %s: %w", u.GetKind(), u.GetName(), err)
+		}
+
+		for _, issue := range imageDrift(u.Object, live.Object) {
+			drift = append(drift, DriftItem{Kind: u.GetKind(), Name: u.GetName(), Namespace: namespace, Issue: issue})
+		}
+	}
+
+	return drift, nil
+}
+
+// imageDrift compares pod template container images of a rendered and live workload
+func imageDrift(rendered, live map[string]interface{}) []string {
+	want := containerImages(rendered)
+	got := containerImages(live)
+	if len(want) == 0 || reflect.DeepEqual(want, got) {
+		return nil
+	}
+
+	var issues []string
+	for _, name := range sortedKeys(want) {
+		if got[name] != want[name] {
+			issues = append(issues, fmt.Sprintf("container %s image %s (release has %s)", name, orNone(got[name]), want[name]))
+		}
+	}
+	return issues
+}
+
+// containerImages returns container name to image for workloads with a pod template
+func containerImages(obj map[string]interface{}) map[string]string {
+	images := make(map[string]string)
+	for _, path := range [][]string{
+		{"spec", "template", "spec", "containers"},
+		{"spec", "template", "spec", "initContainers"},
+		{"spec", "jobTemplate", "spec", "template", "spec", "containers"},
+	} {
+		containers, _, _ := unstructured.NestedSlice(obj, path...)
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := container["name"].(string)
+			image, _ := container["image"].(string)
+			images[name] = image
+		}
+	}
+	return images
+}
+
+// ValueChange is one difference between the user-supplied values of two revisions
+type ValueChange struct {
+	Key  string      `json:"key"`
+	Type string      `json:"type"` // added, removed, changed
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// DiffValues compares the user-supplied values of two revisions by dotted key
+func DiffValues(from, to map[string]interface{}) []ValueChange {
+	old := make(map[string]interface{})
+	flattenValues("", from, old)
+	cur := make(map[string]interface{})
+	flattenValues("", to, cur)
+
+	var changes []ValueChange
+	for _, key := range sortedKeys(old) {
+		newValue, ok := cur[key]
+		switch {
+		case !ok:
+			changes = append(changes, ValueChange{Key: key, Type: "removed", Old: old[key]})
+		case !reflect.DeepEqual(old[key], newValue):
+			changes = append(changes, ValueChange{Key: key, Type: "changed", Old: old[key], New: newValue})
+		}
+	}
+	for _, key := range sortedKeys(cur) {
+		if _, ok := old[key]; !ok {
+			changes = append(changes, ValueChange{Key: key, Type: "added", New: cur[key]})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// flattenValues flattens nested maps into dotted keys; lists are compared whole
+func flattenValues(prefix string, values map[string]interface{}, out map[string]interface{}) {
+	for k, v := range values {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenValues(key, nested, out)
+			continue
+		}
+		out[key] = v
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
@@ -0,0 +1,36 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+)
+
+// defaultRollbackTimeout matches the helm CLI's --timeout default
+const defaultRollbackTimeout = 5 * time.Minute
+
+// Rollback rolls a release back to a revision (0 means the previous one) and
+// waits for its resources to become ready. The SDK call is not cancellable,
+// so a context deadline bounds the wait instead.
+func (c *Client) Rollback(ctx context.Context, namespace, name string, revision int, dryRun bool) error {
+	cfg, err := c.actionConfig(namespace)
+	if err != nil {
+		return err
+	}
+
+	rollback := action.NewRollback(cfg)
+	rollback.Version = revision
+	rollback.DryRun = dryRun
+	rollback.Wait = true
+	rollback.Timeout = defaultRollbackTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		rollback.Timeout = time.Until(deadline)
+	}
+
+	if err := rollback.Run(name); err != nil {
+		return fmt.Errorf("helm rollback failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package k8s
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// auditEvent is the part of an audit.k8s.io/v1 Event the analysis reads
+type auditEvent struct {
+	Kind  string       `json:"kind"`
+	Items []auditEvent `json:"items"`
+
+	Stage string `json:"stage"`
+	Verb  string `json:"verb"`
+	User  struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	ImpersonatedUser *struct {
+		Username string `json:"username"`
+	} `json:"impersonatedUser"`
+	ObjectRef *struct {
+		Resource    string `json:"resource"`
+		Subresource string `json:"subresource"`
+		Namespace   string `json:"namespace"`
+		Name        string `json:"name"`
+		APIGroup    string `json:"apiGroup"`
+	} `json:"objectRef"`
+	ResponseStatus *struct {
+		Code int `json:"code"`
+	} `json:"responseStatus"`
+	RequestReceivedTimestamp time.Time `json:"requestReceivedTimestamp"`
+}
+
+// AuditLogOptions filter the events AnalyzeAuditLog counts
+type AuditLogOptions struct {
+	// Since keeps events this recent, measured back from the newest event in
+	// the logs rather than from now, so old logs can be investigated
+	Since time.Duration
+	// User matches the authenticated or the impersonated user
+	User      string
+	Namespace string
+	Top       int
+}
+
+// AuditCount is how often a verb, user or resource appears
+type AuditCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// AuditAccess is a user's requests for one verb and object
+type AuditAccess struct {
+	User      string    `json:"user"`
+	Verb      string    `json:"verb"`
+	Resource  string    `json:"resource"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Count     int       `json:"count"`
+	Last      time.Time `json:"last"`
+}
+
+// AuditLogSummary summarizes API server audit logs
+type AuditLogSummary struct {
+	Files  []string  `json:"files"`
+	Events int       `json:"events"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	// Malformed counts lines that were not audit events
+	Malformed int          `json:"malformed,omitempty"`
+	Verbs     []AuditCount `json:"verbs"`
+	Users     []AuditCount `json:"users"`
+	Resources []AuditCount `json:"resources"`
+	// Denials are requests answered with 403 Forbidden
+	Denials []AuditAccess `json:"denials"`
+	// SecretAccess lists reads and writes of secrets
+	SecretAccess []AuditAccess `json:"secret_access"`
+}
+
+// AnalyzeAuditLog summarizes JSON-lines audit logs, as written by the API
+// server's log backend. Files may be gzip-compressed; "-" reads stdin.
+// Requests logged at several stages are counted once, at ResponseComplete.
+func AnalyzeAuditLog(files []string, opts AuditLogOptions) (*AuditLogSummary, error) {
+	summary := &AuditLogSummary{Files: files}
+
+	var events []auditEvent
+	for _, file := range files {
+		fileEvents, malformed, err := readAuditLog(file)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, fileEvents...)
+		summary.Malformed += malformed
+	}
+
+	var newest time.Time
+	for _, e := range events {
+		if e.RequestReceivedTimestamp.After(newest) {
+			newest = e.RequestReceivedTimestamp
+		}
+	}
+
+	verbs := make(map[string]int)
+	users := make(map[string]int)
+	resources := make(map[string]int)
+	denials := make(map[AuditAccess]*AuditAccess)
+	secrets := make(map[AuditAccess]*AuditAccess)
+
+	for _, e := range events {
+		if opts.Since > 0 && e.RequestReceivedTimestamp.Before(newest.Add(-opts.Since)) {
+			continue
+		}
+		user := auditUser(e)
+		if opts.User != "" && e.User.Username != opts.User && (e.ImpersonatedUser == nil || e.ImpersonatedUser.Username != opts.User) {
+			continue
+		}
+		access := AuditAccess{User: user, Verb: e.Verb}
+		if e.ObjectRef != nil {
+			access.Resource = auditResource(e)
+			access.Namespace = e.ObjectRef.Namespace
+			access.Name = e.ObjectRef.Name
+		}
+		if opts.Namespace != "" && access.Namespace != opts.Namespace {
+			continue
+		}
+
+		summary.Events++
+		if summary.From.IsZero() || e.RequestReceivedTimestamp.Before(summary.From) {
+			summary.From = e.RequestReceivedTimestamp
+		}
+		if e.RequestReceivedTimestamp.After(summary.To) {
+			summary.To = e.RequestReceivedTimestamp
+		}
+
+		verbs[e.Verb]++
+		users[user]++
+		if access.Resource != "" {
+			resources[access.Resource]++
+		}
+
+		if e.ResponseStatus != nil && e.ResponseStatus.Code == 403 {
+			countAccess(denials, access, e.RequestReceivedTimestamp)
+		}
+		if e.ObjectRef != nil && e.ObjectRef.Resource == "secrets" && (e.ObjectRef.APIGroup == "" || e.ObjectRef.APIGroup == "core") {
+			countAccess(secrets, access, e.RequestReceivedTimestamp)
+		}
+	}
+
+	summary.Verbs = topCounts(verbs, opts.Top)
+	summary.Users = topCounts(users, opts.Top)
+	summary.Resources = topCounts(resources, opts.Top)
+	summary.Denials = sortedAccess(denials)
+	summary.SecretAccess = sortedAccess(secrets)
+	return summary, nil
+}
+
+// readAuditLog returns the completed requests in one log file and the number
+// of lines that could not be parsed
+func readAuditLog(file string) ([]auditEvent, int, error) {
+	var r io.Reader
+	if file == "-" {
+		r = os.Stdin
+	} else {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to open audit log: %w", err)
+		}
+		defer f.Close()
+		r = f
+
+		if strings.HasSuffix(file, ".gz") {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			defer gz.Close()
+			r = gz
+		}
+	}
+
+	// Lines logged at the RequestResponse level can be megabytes long, so
+	// read whole lines rather than use a size-limited scanner
+	reader := bufio.NewReader(r)
+	var events []auditEvent
+	malformed := 0
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(strings.TrimSpace(string(line))) > 0 {
+			var e auditEvent
+			if jsonErr := json.Unmarshal(line, &e); jsonErr != nil {
+				malformed++
+			} else if e.Kind == "EventList" {
+				for _, item := range e.Items {
+					if completedStage(item.Stage) {
+						events = append(events, item)
+					}
+				}
+			} else if e.Verb == "" {
+				malformed++
+			} else if completedStage(e.Stage) {
+				events = append(events, e)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+	}
+	return events, malformed, nil
+}
+
+// completedStage keeps one event per request; logs without stages count all
+func completedStage(stage string) bool {
+	return stage == "" || stage == "ResponseComplete" || stage == "Panic"
+}
+
+// auditUser names the effective user, noting impersonation
+func auditUser(e auditEvent) string {
+	if e.ImpersonatedUser != nil && e.ImpersonatedUser.Username != "" {
+		return fmt.Sprintf("%s (as %s)", e.User.Username, e.ImpersonatedUser.Username)
+	}
+	return e.User.Username
+}
+
+// auditResource formats the resource as resource[/subresource][.group]
+func auditResource(e auditEvent) string {
+	resource := e.ObjectRef.Resource
+	if e.ObjectRef.Subresource != "" {
+		resource += "/" + e.ObjectRef.Subresource
+	}
+	if e.ObjectRef.APIGroup != "" && e.ObjectRef.APIGroup != "core" {
+		resource += "." + e.ObjectRef.APIGroup
+	}
+	return resource
+}
+
+func countAccess(accesses map[AuditAccess]*AuditAccess, key AuditAccess, at time.Time) {
+	a, ok := accesses[key]
+	if !ok {
+		a = &AuditAccess{User: key.User, Verb: key.Verb, Resource: key.Resource, Namespace: key.Namespace, Name: key.Name}
+		accesses[key] = a
+	}
+	a.Count++
+	if at.After(a.Last) {
+		a.Last = at
+	}
+}
+
+// sortedAccess orders accesses by count, then by most recent
+func sortedAccess(accesses map[AuditAccess]*AuditAccess) []AuditAccess {
+	list := make([]AuditAccess, 0, len(accesses))
+	for _, a := range accesses {
+		list = append(list, *a)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Last.After(list[j].Last)
+	})
+	return list
+}
+
+// topCounts returns the n most frequent names, all when n is 0
+func topCounts(counts map[string]int, n int) []AuditCount {
+	list := make([]AuditCount, 0, len(counts))
+	for name, count := range counts {
+		list = append(list, AuditCount{Name: name, Count: count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Name < list[j].Name
+	})
+	if n > 0 && len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
@@ -0,0 +1,494 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Node autoscalers detected by GetAutoscaling
+const (
+	AutoscalerClusterAutoscaler = "cluster-autoscaler"
+	AutoscalerKarpenter         = "karpenter"
+)
+
+// caStatusConfigMap is where cluster-autoscaler publishes its status
+const caStatusConfigMap = "cluster-autoscaler-status"
+
+// nodeClaimStuckAfter is how long a NodeClaim may take to become ready
+// before it is reported as a blocker
+const nodeClaimStuckAfter = 10 * time.Minute
+
+// nodeClaimPaths are the NodeClaim APIs of Karpenter v1 and v0.32-v0.37
+var nodeClaimPaths = []string{"/apis/karpenter.sh/v1/nodeclaims", "/apis/karpenter.sh/v1beta1/nodeclaims"}
+
+// scaleEventReasons are the event reasons cluster-autoscaler and Karpenter
+// record on pods, nodes and node claims
+var scaleEventReasons = map[string]bool{
+	// cluster-autoscaler
+	"TriggeredScaleUp":     true,
+	"NotTriggerScaleUp":    true,
+	"FailedToScaleUpGroup": true,
+	"ScaledUpGroup":        true,
+	"ScaleDown":            true,
+	"ScaleDownEmpty":       true,
+	"ScaleDownFailed":      true,
+	// Karpenter
+	"Nominated":                  true,
+	"Launched":                   true,
+	"Registered":                 true,
+	"Initialized":                true,
+	"InsufficientCapacityError":  true,
+	"FailedLaunch":               true,
+	"DisruptionBlocked":          true,
+	"DisruptionLaunching":        true,
+	"DisruptionTerminating":      true,
+	"NoCompatibleInstanceTypes":  true,
+	"FailedConsistencyCheck":     true,
+	"UnregisteredTaintMissing":   true,
+	"NodePoolLimitsExceeded":     true,
+	"NodeClaimNotReady":          true,
+	"TerminationGracePeriodHold": true,
+}
+
+// AutoscalingReport shows what node autoscaling is doing and what blocks it
+type AutoscalingReport struct {
+	// Autoscalers lists the node autoscalers found in the cluster
+	Autoscalers       []string                 `json:"autoscalers"`
+	ClusterAutoscaler *ClusterAutoscalerStatus `json:"cluster_autoscaler,omitempty"`
+	NodeClaims        []NodeClaimInfo          `json:"node_claims,omitempty"`
+	PendingPods       []PendingPod             `json:"pending_pods"`
+	Events            []EventInfo              `json:"events"`
+	// Blockers explain why scale-up is not happening or not finishing
+	Blockers []string `json:"blockers"`
+}
+
+// ClusterAutoscalerStatus is the cluster-autoscaler status config map
+type ClusterAutoscalerStatus struct {
+	Time       string           `json:"time,omitempty"`
+	Health     string           `json:"health"`
+	ScaleUp    string           `json:"scale_up"`
+	ScaleDown  string           `json:"scale_down"`
+	NodeGroups []NodeGroupState `json:"node_groups"`
+}
+
+// NodeGroupState is one node group as cluster-autoscaler sees it
+type NodeGroupState struct {
+	Name    string `json:"name"`
+	Health  string `json:"health"`
+	ScaleUp string `json:"scale_up"`
+	Ready   int    `json:"ready"`
+	Target  int    `json:"target"`
+	MinSize int    `json:"min_size"`
+	MaxSize int    `json:"max_size"`
+	// Backoff is the cloud provider error that put the group in backoff
+	Backoff string `json:"backoff,omitempty"`
+}
+
+// AtMax reports whether the group cannot grow any further
+func (g NodeGroupState) AtMax() bool {
+	return g.MaxSize > 0 && g.Target >= g.MaxSize
+}
+
+// NodeClaimInfo is a Karpenter NodeClaim and how far its node got
+type NodeClaimInfo struct {
+	Name         string    `json:"name"`
+	NodePool     string    `json:"node_pool"`
+	InstanceType string    `json:"instance_type,omitempty"`
+	Node         string    `json:"node,omitempty"`
+	Created      time.Time `json:"created"`
+	// Phase is the last of Launched, Registered, Initialized and Ready reached
+	Phase   string `json:"phase"`
+	Ready   bool   `json:"ready"`
+	Message string `json:"message,omitempty"`
+}
+
+// PendingPod is a pod the scheduler could not place
+type PendingPod struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Since     time.Time `json:"since"`
+	Reason    string    `json:"reason"`
+	// Autoscaler is the latest autoscaler event about the pod, if any
+	Autoscaler string `json:"autoscaler,omitempty"`
+}
+
+// GetAutoscaling reads cluster-autoscaler status and Karpenter NodeClaims,
+// and correlates unschedulable pods in namespace (all when empty) with the
+// autoscaler events about them from the last since
+func (c *Client) GetAutoscaling(ctx context.Context, namespace string, since time.Duration) (*AutoscalingReport, error) {
+	report := &AutoscalingReport{}
+
+	ca, err := c.clusterAutoscalerStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ca != nil {
+		report.Autoscalers = append(report.Autoscalers, AutoscalerClusterAutoscaler)
+		report.ClusterAutoscaler = ca
+	}
+
+	claims, found, err := c.nodeClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		report.Autoscalers = append(report.Autoscalers, AutoscalerKarpenter)
+		report.NodeClaims = claims
+	}
+
+	events, err := c.listEvents(ctx, "", eventSelector{}, time.Now().Add(-since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	for _, e := range events {
+		if !scaleEventReasons[e.Reason] {
+			continue
+		}
+		// Node and NodeClaim events are kept; pod events follow the scope
+		if e.Kind != "Pod" || ((namespace == "" || e.Namespace == namespace) && c.inScope(e.Namespace)) {
+			report.Events = append(report.Events, e)
+		}
+	}
+
+	report.PendingPods, err = c.pendingPods(ctx, namespace, report.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Blockers = autoscalingBlockers(report)
+	return report, nil
+}
+
+// clusterAutoscalerStatus returns nil when cluster-autoscaler is not installed
+func (c *Client) clusterAutoscalerStatus(ctx context.Context) (*ClusterAutoscalerStatus, error) {
+	cm, err := c.clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, caStatusConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", caStatusConfigMap, err)
+	}
+	return parseCAStatus(cm.Data["status"]), nil
+}
+
+// caStatusYAML is the structured status format of cluster-autoscaler 1.30+
+type caStatusYAML struct {
+	Time        string `yaml:"time"`
+	ClusterWide struct {
+		Health    caCondition `yaml:"health"`
+		ScaleUp   caCondition `yaml:"scaleUp"`
+		ScaleDown caCondition `yaml:"scaleDown"`
+	} `yaml:"clusterWide"`
+	NodeGroups []struct {
+		Name    string      `yaml:"name"`
+		Health  caCondition `yaml:"health"`
+		ScaleUp caCondition `yaml:"scaleUp"`
+	} `yaml:"nodeGroups"`
+}
+
+type caCondition struct {
+	Status     string `yaml:"status"`
+	NodeCounts struct {
+		Registered struct {
+			Ready int `yaml:"ready"`
+		} `yaml:"registered"`
+	} `yaml:"nodeCounts"`
+	CloudProviderTarget int `yaml:"cloudProviderTarget"`
+	MinSize             int `yaml:"minSize"`
+	MaxSize             int `yaml:"maxSize"`
+	BackoffInfo         struct {
+		ErrorCode    string `yaml:"errorCode"`
+		ErrorMessage string `yaml:"errorMessage"`
+	} `yaml:"backoffInfo"`
+}
+
+// parseCAStatus reads the YAML status of recent releases and the text
+// status of older ones
+func parseCAStatus(data string) *ClusterAutoscalerStatus {
+	if !strings.HasPrefix(strings.TrimSpace(data), "Cluster-autoscaler status") {
+		var doc caStatusYAML
+		if err := yaml.Unmarshal([]byte(data), &doc); err == nil && doc.ClusterWide.Health.Status != "" {
+			status := &ClusterAutoscalerStatus{
+				Time:      doc.Time,
+				Health:    doc.ClusterWide.Health.Status,
+				ScaleUp:   doc.ClusterWide.ScaleUp.Status,
+				ScaleDown: doc.ClusterWide.ScaleDown.Status,
+			}
+			for _, ng := range doc.NodeGroups {
+				group := NodeGroupState{
+					Name:    ng.Name,
+					Health:  ng.Health.Status,
+					ScaleUp: ng.ScaleUp.Status,
+					Ready:   ng.Health.NodeCounts.Registered.Ready,
+					Target:  ng.Health.CloudProviderTarget,
+					MinSize: ng.Health.MinSize,
+					MaxSize: ng.Health.MaxSize,
+				}
+				if info := ng.ScaleUp.BackoffInfo; info.ErrorCode != "" || info.ErrorMessage != "" {
+					group.Backoff = strings.TrimSpace(info.ErrorCode + ": " + info.ErrorMessage)
+				}
+				status.NodeGroups = append(status.NodeGroups, group)
+			}
+			return status
+		}
+	}
+	return parseCAStatusText(data)
+}
+
+// caTextField matches "  Health:      Healthy (ready=3 ...)" lines
+var caTextField = regexp.MustCompile(`^\s*(Name|Health|ScaleUp|ScaleDown):\s+(\S+)\s*(.*)$`)
+
+// caTextCount matches key=value counts in the text status
+var caTextCount = regexp.MustCompile(`(\w+)=(\d+)`)
+
+// parseCAStatusText reads the text status written before cluster-autoscaler 1.30:
+//
+//	Cluster-wide:
+//	  Health:      Healthy (ready=3 unready=0 ...)
+//	  ScaleUp:     NoActivity (ready=3 registered=3)
+//	NodeGroups:
+//	  Name:        ng-1
+//	  Health:      Healthy (ready=3 ... cloudProviderTarget=3 (minSize=1, maxSize=10))
+func parseCAStatusText(data string) *ClusterAutoscalerStatus {
+	status := &ClusterAutoscalerStatus{}
+	var group *NodeGroupState
+	for _, line := range strings.Split(data, "\n") {
+		if strings.HasPrefix(line, "Cluster-autoscaler status at ") {
+			status.Time = strings.TrimSuffix(strings.TrimPrefix(line, "Cluster-autoscaler status at "), ":")
+			continue
+		}
+		m := caTextField.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		key, value, detail := m[1], m[2], m[3]
+
+		if key == "Name" {
+			status.NodeGroups = append(status.NodeGroups, NodeGroupState{Name: value})
+			group = &status.NodeGroups[len(status.NodeGroups)-1]
+			continue
+		}
+		if group == nil {
+			switch key {
+			case "Health":
+				status.Health = value
+			case "ScaleUp":
+				status.ScaleUp = value
+			case "ScaleDown":
+				status.ScaleDown = value
+			}
+			continue
+		}
+
+		switch key {
+		case "Health":
+			group.Health = value
+			for _, count := range caTextCount.FindAllStringSubmatch(detail, -1) {
+				n, _ := strconv.Atoi(count[2])
+				switch count[1] {
+				case "ready":
+					group.Ready = n
+				case "cloudProviderTarget":
+					group.Target = n
+				case "minSize":
+					group.MinSize = n
+				case "maxSize":
+					group.MaxSize = n
+				}
+			}
+		case "ScaleUp":
+			group.ScaleUp = value
+		}
+	}
+	return status
+}
+
+// nodeClaimList is the part of a Karpenter NodeClaim list the report reads
+type nodeClaimList struct {
+	Items []struct {
+		Metadata struct {
+			Name              string            `json:"name"`
+			Labels            map[string]string `json:"labels"`
+			CreationTimestamp time.Time         `json:"creationTimestamp"`
+		} `json:"metadata"`
+		Status struct {
+			NodeName   string `json:"nodeName"`
+			Conditions []struct {
+				Type    string `json:"type"`
+				Status  string `json:"status"`
+				Reason  string `json:"reason"`
+				Message string `json:"message"`
+			} `json:"conditions"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// nodeClaims lists Karpenter NodeClaims, newest first. found is false when
+// Karpenter is not installed.
+func (c *Client) nodeClaims(ctx context.Context) ([]NodeClaimInfo, bool, error) {
+	var data []byte
+	var err error
+	for _, path := range nodeClaimPaths {
+		data, err = c.clientset.CoreV1().RESTClient().Get().AbsPath(path).DoRaw(ctx)
+		if err == nil || !apierrors.IsNotFound(err) {
+			break
+		}
+	}
+	if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list NodeClaims: %w", err)
+	}
+
+	var list nodeClaimList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, false, fmt.Errorf("failed to parse NodeClaims: %w", err)
+	}
+
+	claims := make([]NodeClaimInfo, 0, len(list.Items))
+	for _, item := range list.Items {
+		claim := NodeClaimInfo{
+			Name:         item.Metadata.Name,
+			NodePool:     item.Metadata.Labels["karpenter.sh/nodepool"],
+			InstanceType: item.Metadata.Labels["node.kubernetes.io/instance-type"],
+			Node:         item.Status.NodeName,
+			Created:      item.Metadata.CreationTimestamp,
+			Phase:        "Pending",
+		}
+		for _, phase := range []string{"Launched", "Registered", "Initialized", "Ready"} {
+			for _, cond := range item.Status.Conditions {
+				if cond.Type != phase {
+					continue
+				}
+				if cond.Status == "True" {
+					claim.Phase = phase
+				} else if claim.Message == "" && cond.Message != "" {
+					claim.Message = cond.Message
+				}
+			}
+		}
+		claim.Ready = claim.Phase == "Ready"
+		if claim.Ready {
+			claim.Message = ""
+		}
+		claims = append(claims, claim)
+	}
+
+	sort.Slice(claims, func(i, j int) bool {
+		return claims[i].Created.After(claims[j].Created)
+	})
+	return claims, true, nil
+}
+
+// pendingPods lists unschedulable pods with the latest autoscaler event about each
+func (c *Client) pendingPods(ctx context.Context, namespace string, events []EventInfo) ([]PendingPod, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase=Pending",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	// events are sorted newest first, so the first match is the latest
+	latest := make(map[string]EventInfo)
+	for _, e := range events {
+		if e.Kind != "Pod" {
+			continue
+		}
+		key := e.Namespace + "/" + e.Object
+		if _, ok := latest[key]; !ok {
+			latest[key] = e
+		}
+	}
+
+	var result []PendingPod
+	for _, pod := range pods.Items {
+		if !c.inScope(pod.Namespace) {
+			continue
+		}
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type != corev1.PodScheduled || cond.Status != corev1.ConditionFalse || cond.Reason != corev1.PodReasonUnschedulable {
+				continue
+			}
+			pending := PendingPod{
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				Since:     cond.LastTransitionTime.Time,
+				Reason:    cond.Message,
+			}
+			if e, ok := latest[pod.Namespace+"/"+pod.Name]; ok {
+				pending.Autoscaler = e.Reason + ": " + e.Message
+			}
+			result = append(result, pending)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Since.Before(result[j].Since)
+	})
+	return result, nil
+}
+
+// autoscalingBlockers explains why pending pods are not getting nodes
+func autoscalingBlockers(r *AutoscalingReport) []string {
+	var blockers []string
+	if len(r.Autoscalers) == 0 && len(r.PendingPods) > 0 {
+		blockers = append(blockers, fmt.Sprintf("%d pods are unschedulable and no cluster-autoscaler status or Karpenter NodeClaims were found", len(r.PendingPods)))
+	}
+
+	if ca := r.ClusterAutoscaler; ca != nil {
+		if ca.Health != "" && ca.Health != "Healthy" {
+			blockers = append(blockers, fmt.Sprintf("cluster-autoscaler is %s", ca.Health))
+		}
+		for _, g := range ca.NodeGroups {
+			switch {
+			case g.Backoff != "":
+				blockers = append(blockers, fmt.Sprintf("node group %s is in backoff: %s", g.Name, g.Backoff))
+			case g.ScaleUp == "Backoff":
+				blockers = append(blockers, fmt.Sprintf("node group %s is in scale-up backoff", g.Name))
+			}
+			if g.AtMax() && len(r.PendingPods) > 0 {
+				blockers = append(blockers, fmt.Sprintf("node group %s is at its max size (%d)", g.Name, g.MaxSize))
+			}
+			if g.Health != "" && g.Health != "Healthy" {
+				blockers = append(blockers, fmt.Sprintf("node group %s is %s", g.Name, g.Health))
+			}
+		}
+	}
+
+	for _, claim := range r.NodeClaims {
+		if !claim.Ready && time.Since(claim.Created) > nodeClaimStuckAfter {
+			msg := fmt.Sprintf("NodeClaim %s (%s) stuck at %s for %s", claim.Name, claim.NodePool, claim.Phase, time.Since(claim.Created).Round(time.Minute))
+			if claim.Message != "" {
+				msg += ": " + claim.Message
+			}
+			blockers = append(blockers, msg)
+		}
+	}
+
+	// One line per distinct refusal, e.g. NotTriggerScaleUp for many pods
+	seen := make(map[string]bool)
+	for _, e := range r.Events {
+		if e.Type != "Warning" && e.Reason != "NotTriggerScaleUp" {
+			continue
+		}
+		msg := e.Reason + ": " + e.Message
+		if !seen[msg] {
+			seen[msg] = true
+			blockers = append(blockers, msg)
+		}
+	}
+	return blockers
+}
@@ -9,7 +9,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/batch"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/logging"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -18,18 +23,24 @@ import (
 
 // Client wraps the Kubernetes clientset
 type Client struct {
-	clientset *kubernetes.Clientset
-	config    *rest.Config
+	clientset   kubernetes.Interface
+	config      *rest.Config
+	contextName string
+	kubeconfig  string
+	namespaces  NamespaceSelector
 }
 
 // NewClient creates a new Kubernetes client
 func NewClient(kubeconfigPath, context string) (*Client, error) {
 	var config *rest.Config
+	var contextName, kubeconfig string
 	var err error
 
 	// Try in-cluster config first
 	config, err = rest.InClusterConfig()
-	if err != nil {
+	if err == nil {
+		logging.Debug("using in-cluster kubernetes config", "server", config.Host)
+	} else {
 		// Fall back to kubeconfig
 		if kubeconfigPath == "" {
 			kubeconfigPath = os.Getenv("KUBECONFIG")
@@ -48,26 +59,74 @@ func NewClient(kubeconfigPath, context string) (*Client, error) {
 		kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
 		config, err = kubeConfig.ClientConfig()
 		if err != nil {
-			return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+			return nil, exitcode.ConfigError(fmt.Errorf("failed to load kubeconfig: %w", err))
+		}
+
+		contextName = context
+		kubeconfig = kubeconfigPath
+		if rawConfig, err := kubeConfig.RawConfig(); err == nil && contextName == "" {
+			contextName = rawConfig.CurrentContext
+		}
+		if logging.Enabled() {
+			logging.Debug("loaded kubeconfig", "path", kubeconfigPath, "context", contextName, "server", config.Host)
 		}
 	}
 
+	if logging.Enabled() {
+		config.WrapTransport = logging.Transport
+	}
+
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create clientset: %w", err)
 	}
 
 	return &Client{
-		clientset: clientset,
-		config:    config,
+		clientset:   clientset,
+		config:      config,
+		contextName: contextName,
+		kubeconfig:  kubeconfig,
 	}, nil
 }
 
+// NewClientFromClientset wraps an existing clientset, such as a fake from
+// k8s.io/client-go/kubernetes/fake; a nil config is treated as empty
+func NewClientFromClientset(clientset kubernetes.Interface, config *rest.Config) *Client {
+	if config == nil {
+		config = &rest.Config{}
+	}
+	return &Client{clientset: clientset, config: config}
+}
+
+// Server returns the API server URL the client talks to
+func (c *Client) Server() string {
+	return c.config.Host
+}
+
+// Context returns the kubeconfig context in use, empty for in-cluster config
+func (c *Client) Context() string {
+	return c.contextName
+}
+
+// Clientset returns the underlying Kubernetes clientset
+func (c *Client) Clientset() kubernetes.Interface {
+	return c.clientset
+}
+
+// RESTConfig returns the REST config the client was built from
+func (c *Client) RESTConfig() *rest.Config {
+	return c.config
+}
+
 // ClusterInfo contains cluster information
 type ClusterInfo struct {
 	Name       string
 	Server     string
 	K8sVersion string
+	Provider   string
+	NodePools  []NodePool
+	Cloud      *CloudDetails
+	CloudError string
 }
 
 // GetClusterInfo returns cluster information
@@ -77,11 +136,19 @@ func (c *Client) GetClusterInfo(ctx context.Context) (*ClusterInfo, error) {
 		return nil, err
 	}
 
-	return &ClusterInfo{
+	info := &ClusterInfo{
 		Name:       c.config.Host,
 		Server:     c.config.Host,
 		K8sVersion: version.GitVersion,
-	}, nil
+	}
+
+	// Managed cluster detection is best effort; RBAC may hide nodes
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err == nil {
+		c.detectCloud(ctx, info, nodes.Items)
+	}
+
+	return info, nil
 }
 
 // NodeHealth contains node health information
@@ -130,11 +197,14 @@ func (c *Client) GetPodHealth(ctx context.Context, namespace string) (*PodHealth
 		return nil, err
 	}
 
-	health := &PodHealth{
-		Total: len(pods.Items),
-	}
+	health := &PodHealth{}
 
 	for _, pod := range pods.Items {
+		if !c.inScope(pod.Namespace) {
+			continue
+		}
+		health.Total++
+
 		switch pod.Status.Phase {
 		case corev1.PodRunning:
 			health.Running++
@@ -162,11 +232,14 @@ func (c *Client) GetPVCHealth(ctx context.Context, namespace string) (*PVCHealth
 		return nil, err
 	}
 
-	health := &PVCHealth{
-		Total: len(pvcs.Items),
-	}
+	health := &PVCHealth{}
 
 	for _, pvc := range pvcs.Items {
+		if !c.inScope(pvc.Namespace) {
+			continue
+		}
+		health.Total++
+
 		switch pvc.Status.Phase {
 		case corev1.ClaimBound:
 			health.Bound++
@@ -178,11 +251,36 @@ func (c *Client) GetPVCHealth(ctx context.Context, namespace string) (*PVCHealth
 	return health, nil
 }
 
+// Deployment states reported by GetDeploymentHealth
+const (
+	DeploymentHealthy     = "Healthy"
+	DeploymentProgressing = "Progressing"
+	DeploymentDegraded    = "Degraded"
+	DeploymentPaused      = "Paused"
+	DeploymentStalled     = "ProgressDeadlineExceeded"
+)
+
+// DeploymentStatus contains the health of a single deployment
+type DeploymentStatus struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Desired     int32  `json:"desired"`
+	Ready       int32  `json:"ready"`
+	Updated     int32  `json:"updated"`
+	Available   int32  `json:"available"`
+	Unavailable int32  `json:"unavailable"`
+	State       string `json:"state"`
+	Message     string `json:"message,omitempty"`
+}
+
 // DeploymentHealth contains deployment health information
 type DeploymentHealth struct {
 	Total       int
 	Ready       int
 	Unavailable int
+	Paused      int
+	Stalled     int
+	Deployments []DeploymentStatus
 }
 
 // GetDeploymentHealth returns deployment health status
@@ -192,20 +290,77 @@ func (c *Client) GetDeploymentHealth(ctx context.Context, namespace string) (*De
 		return nil, err
 	}
 
-	health := &DeploymentHealth{
-		Total: len(deployments.Items),
-	}
+	health := &DeploymentHealth{}
 
 	for _, dep := range deployments.Items {
-		if dep.Status.ReadyReplicas == *dep.Spec.Replicas {
+		if !c.inScope(dep.Namespace) {
+			continue
+		}
+		health.Total++
+
+		status := deploymentStatus(dep)
+		health.Deployments = append(health.Deployments, status)
+
+		switch status.State {
+		case DeploymentPaused:
+			// Paused on purpose, not counted against availability
+			health.Paused++
+			continue
+		case DeploymentHealthy:
 			health.Ready++
+		case DeploymentStalled:
+			health.Stalled++
 		}
-		health.Unavailable += int(dep.Status.UnavailableReplicas)
+		health.Unavailable += int(status.Unavailable)
 	}
 
 	return health, nil
 }
 
+// deploymentStatus classifies a deployment; spec.replicas defaults to 1 when unset
+func deploymentStatus(dep appsv1.Deployment) DeploymentStatus {
+	desired := int32(1)
+	if dep.Spec.Replicas != nil {
+		desired = *dep.Spec.Replicas
+	}
+
+	status := DeploymentStatus{
+		Name:        dep.Name,
+		Namespace:   dep.Namespace,
+		Desired:     desired,
+		Ready:       dep.Status.ReadyReplicas,
+		Updated:     dep.Status.UpdatedReplicas,
+		Available:   dep.Status.AvailableReplicas,
+		Unavailable: dep.Status.UnavailableReplicas,
+	}
+
+	var progressing *appsv1.DeploymentCondition
+	for i := range dep.Status.Conditions {
+		if dep.Status.Conditions[i].Type == appsv1.DeploymentProgressing {
+			progressing = &dep.Status.Conditions[i]
+		}
+	}
+
+	switch {
+	case dep.Spec.Paused:
+		status.State = DeploymentPaused
+		status.Message = "rollout paused"
+	case progressing != nil && progressing.Reason == "ProgressDeadlineExceeded":
+		status.State = DeploymentStalled
+		status.Message = progressing.Message
+	case status.Ready >= desired && status.Updated >= desired:
+		status.State = DeploymentHealthy
+	case status.Updated < desired || dep.Status.ObservedGeneration < dep.Generation:
+		status.State = DeploymentProgressing
+		status.Message = fmt.Sprintf("%d of %d replicas updated", status.Updated, desired)
+	default:
+		status.State = DeploymentDegraded
+		status.Message = fmt.Sprintf("%d of %d replicas ready", status.Ready, desired)
+	}
+
+	return status
+}
+
 // ServiceHealth contains service health information
 type ServiceHealth struct {
 	ClusterIP    int
@@ -221,11 +376,14 @@ func (c *Client) GetServiceHealth(ctx context.Context, namespace string) (*Servi
 		return nil, err
 	}
 
-	health := &ServiceHealth{
-		Total: len(services.Items),
-	}
+	health := &ServiceHealth{}
 
 	for _, svc := range services.Items {
+		if !c.inScope(svc.Namespace) {
+			continue
+		}
+		health.Total++
+
 		switch svc.Spec.Type {
 		case corev1.ServiceTypeClusterIP:
 			health.ClusterIP++
@@ -281,47 +439,33 @@ func (c *Client) GetResourceUtilization(ctx context.Context) (*ResourceUtilizati
 
 // EventInfo contains event information
 type EventInfo struct {
-	Type          string
-	Reason        string
-	Object        string
-	Kind          string
-	Message       string
-	Count         int32
-	LastTimestamp time.Time
+	Namespace     string    `json:"namespace"`
+	Type          string    `json:"type"`
+	Reason        string    `json:"reason"`
+	Object        string    `json:"object"`
+	Kind          string    `json:"kind"`
+	Message       string    `json:"message"`
+	Count         int32     `json:"count"`
+	LastTimestamp time.Time `json:"last_timestamp"`
 }
 
 // GetWarningEvents returns recent warning events
 func (c *Client) GetWarningEvents(ctx context.Context, namespace string, limit int) ([]EventInfo, error) {
-	events, err := c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
-		FieldSelector: "type=Warning",
-	})
+	// Only include recent events (last hour)
+	events, err := c.listEvents(ctx, namespace, eventSelector{Type: "Warning"}, time.Now().Add(-time.Hour))
 	if err != nil {
 		return nil, err
 	}
 
-	// Sort by last timestamp descending
-	sort.Slice(events.Items, func(i, j int) bool {
-		return events.Items[i].LastTimestamp.After(events.Items[j].LastTimestamp.Time)
-	})
-
 	var result []EventInfo
-	for i, event := range events.Items {
-		if i >= limit {
+	for _, event := range events {
+		if len(result) >= limit {
 			break
 		}
-		// Only include recent events (last hour)
-		if time.Since(event.LastTimestamp.Time) > time.Hour {
+		if !c.inScope(event.Namespace) {
 			continue
 		}
-		result = append(result, EventInfo{
-			Type:          event.Type,
-			Reason:        event.Reason,
-			Object:        event.InvolvedObject.Name,
-			Kind:          event.InvolvedObject.Kind,
-			Message:       event.Message,
-			Count:         event.Count,
-			LastTimestamp: event.LastTimestamp.Time,
-		})
+		result = append(result, event)
 	}
 
 	return result, nil
@@ -329,15 +473,15 @@ func (c *Client) GetWarningEvents(ctx context.Context, namespace string, limit i
 
 // PodInfo contains pod information
 type PodInfo struct {
-	Name            string
-	Namespace       string
-	Status          string
-	ReadyContainers int
-	TotalContainers int
-	Restarts        int32
-	Node            string
-	IP              string
-	CreationTime    time.Time
+	Name            string    `json:"name"`
+	Namespace       string    `json:"namespace"`
+	Status          string    `json:"status"`
+	ReadyContainers int       `json:"ready_containers"`
+	TotalContainers int       `json:"total_containers"`
+	Restarts        int32     `json:"restarts"`
+	Node            string    `json:"node"`
+	IP              string    `json:"ip"`
+	CreationTime    time.Time `json:"creation_time"`
 }
 
 // ListPods lists pods with enhanced information
@@ -354,6 +498,10 @@ func (c *Client) ListPods(ctx context.Context, namespace, labelSelector string)
 
 	var result []PodInfo
 	for _, pod := range pods.Items {
+		if !c.inScope(pod.Namespace) {
+			continue
+		}
+
 		info := PodInfo{
 			Name:            pod.Name,
 			Namespace:       pod.Namespace,
@@ -371,49 +519,49 @@ func (c *Client) ListPods(ctx context.Context, namespace, labelSelector string)
 			info.Restarts += cs.RestartCount
 		}
 
-		// Determine status
-		info.Status = string(pod.Status.Phase)
-		for _, cs := range pod.Status.ContainerStatuses {
-			if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
-				info.Status = cs.State.Waiting.Reason
-				break
-			}
-			if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" {
-				info.Status = cs.State.Terminated.Reason
-				break
-			}
-		}
-
-		// Check for eviction
-		if pod.Status.Reason == "Evicted" {
-			info.Status = "Evicted"
-		}
-
+		info.Status = podStatus(pod)
 		result = append(result, info)
 	}
 
 	return result, nil
 }
 
+// podStatus is the pod phase, or the reason a container is waiting or
+// terminated, or Evicted
+func podStatus(pod corev1.Pod) string {
+	if pod.Status.Reason == "Evicted" {
+		return "Evicted"
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			return cs.State.Waiting.Reason
+		}
+		if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" {
+			return cs.State.Terminated.Reason
+		}
+	}
+	return string(pod.Status.Phase)
+}
+
 // NodeInfo contains node information
 type NodeInfo struct {
-	Name               string
-	Ready              bool
-	Roles              string
-	KubeletVersion     string
-	InternalIP         string
-	ExternalIP         string
-	OSImage            string
-	KernelVersion      string
-	ContainerRuntime   string
-	CPUCapacity        int64
-	MemoryCapacity     int64
-	CPUUsagePercent    float64
-	MemoryUsagePercent float64
-	MemoryPressure     bool
-	DiskPressure       bool
-	PIDPressure        bool
-	CreationTime       time.Time
+	Name               string    `json:"name"`
+	Ready              bool      `json:"ready"`
+	Roles              string    `json:"roles"`
+	KubeletVersion     string    `json:"kubelet_version"`
+	InternalIP         string    `json:"internal_ip"`
+	ExternalIP         string    `json:"external_ip"`
+	OSImage            string    `json:"os_image"`
+	KernelVersion      string    `json:"kernel_version"`
+	ContainerRuntime   string    `json:"container_runtime"`
+	CPUCapacity        int64     `json:"cpu_capacity"`
+	MemoryCapacity     int64     `json:"memory_capacity"`
+	CPUUsagePercent    float64   `json:"cpu_usage_percent"`
+	MemoryUsagePercent float64   `json:"memory_usage_percent"`
+	MemoryPressure     bool      `json:"memory_pressure"`
+	DiskPressure       bool      `json:"disk_pressure"`
+	PIDPressure        bool      `json:"pid_pressure"`
+	CreationTime       time.Time `json:"creation_time"`
 }
 
 // ListNodes lists cluster nodes with enhanced information
@@ -529,22 +677,13 @@ func (c *Client) FindEvictedPods(ctx context.Context, namespace string) ([]PodIn
 	return result, nil
 }
 
-// DeletePods deletes the specified pods
-func (c *Client) DeletePods(ctx context.Context, pods []PodInfo) (int, error) {
-	deleted := 0
-	for _, pod := range pods {
-		err := c.clientset.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
-		if err == nil {
-			deleted++
-		}
-	}
-	return deleted, nil
-}
-
 // JobInfo contains job information
 type JobInfo struct {
 	Name      string
 	Namespace string
+	// CronJob is the owning CronJob, empty for standalone Jobs
+	CronJob string
+	HasTTL  bool
 }
 
 // FindCompletedJobs finds completed jobs
@@ -556,11 +695,18 @@ func (c *Client) FindCompletedJobs(ctx context.Context, namespace string) ([]Job
 
 	var result []JobInfo
 	for _, job := range jobs.Items {
-		if job.Status.Succeeded > 0 && job.Status.Active == 0 {
-			result = append(result, JobInfo{
+		if job.Status.Succeeded > 0 && job.Status.Active == 0 && c.inScope(job.Namespace) {
+			info := JobInfo{
 				Name:      job.Name,
 				Namespace: job.Namespace,
-			})
+				HasTTL:    job.Spec.TTLSecondsAfterFinished != nil,
+			}
+			for _, ref := range job.OwnerReferences {
+				if ref.Kind == "CronJob" {
+					info.CronJob = ref.Name
+				}
+			}
+			result = append(result, info)
 		}
 	}
 	return result, nil
@@ -568,17 +714,17 @@ func (c *Client) FindCompletedJobs(ctx context.Context, namespace string) ([]Job
 
 // DeleteJobs deletes the specified jobs
 func (c *Client) DeleteJobs(ctx context.Context, jobs []JobInfo) (int, error) {
-	deleted := 0
 	propagation := metav1.DeletePropagationBackground
-	for _, job := range jobs {
+	names := make([]string, len(jobs))
+	errs := batch.Run(len(jobs), batch.DefaultWorkers, func(i int) error {
+		job := jobs[i]
+		names[i] = job.Namespace + "/" + job.Name
 		err := c.clientset.BatchV1().Jobs(job.Namespace).Delete(ctx, job.Name, metav1.DeleteOptions{
 			PropagationPolicy: &propagation,
 		})
-		if err == nil {
-			deleted++
-		}
-	}
-	return deleted, nil
+		return ignoreNotFound(err)
+	})
+	return batch.Succeeded(errs), batch.Collect(names, errs)
 }
 
 // ReplicaSetInfo contains ReplicaSet information
@@ -597,7 +743,7 @@ func (c *Client) FindOrphanedReplicaSets(ctx context.Context, namespace string)
 	var result []ReplicaSetInfo
 	for _, rs := range replicaSets.Items {
 		// Orphaned RS have 0 replicas and no owner
-		if rs.Status.Replicas == 0 && len(rs.OwnerReferences) == 0 {
+		if rs.Status.Replicas == 0 && len(rs.OwnerReferences) == 0 && c.inScope(rs.Namespace) {
 			result = append(result, ReplicaSetInfo{
 				Name:      rs.Name,
 				Namespace: rs.Namespace,
@@ -609,14 +755,13 @@ func (c *Client) FindOrphanedReplicaSets(ctx context.Context, namespace string)
 
 // DeleteReplicaSets deletes the specified ReplicaSets
 func (c *Client) DeleteReplicaSets(ctx context.Context, replicaSets []ReplicaSetInfo) (int, error) {
-	deleted := 0
-	for _, rs := range replicaSets {
-		err := c.clientset.AppsV1().ReplicaSets(rs.Namespace).Delete(ctx, rs.Name, metav1.DeleteOptions{})
-		if err == nil {
-			deleted++
-		}
-	}
-	return deleted, nil
+	names := make([]string, len(replicaSets))
+	errs := batch.Run(len(replicaSets), batch.DefaultWorkers, func(i int) error {
+		rs := replicaSets[i]
+		names[i] = rs.Namespace + "/" + rs.Name
+		return ignoreNotFound(c.clientset.AppsV1().ReplicaSets(rs.Namespace).Delete(ctx, rs.Name, metav1.DeleteOptions{}))
+	})
+	return batch.Succeeded(errs), batch.Collect(names, errs)
 }
 
 // EventFilter contains event filter options
@@ -624,49 +769,48 @@ type EventFilter struct {
 	Type   string
 	Reason string
 	Object string
-	Limit  int
+	// Exact matches Reason and Object exactly, which lets the API server
+	// filter them; otherwise they are case-insensitive substrings
+	Exact bool
+	// Since drops events last seen longer ago than this
+	Since time.Duration
+	Limit int
 }
 
 // ListEvents lists events with filters
 func (c *Client) ListEvents(ctx context.Context, namespace string, filter EventFilter) ([]EventInfo, error) {
-	opts := metav1.ListOptions{}
-	if filter.Type != "" {
-		opts.FieldSelector = "type=" + filter.Type
+	selector := eventSelector{Type: filter.Type}
+	if filter.Exact {
+		selector.Reason = filter.Reason
+		selector.Object = filter.Object
+	}
+	var since time.Time
+	if filter.Since > 0 {
+		since = time.Now().Add(-filter.Since)
 	}
 
-	events, err := c.clientset.CoreV1().Events(namespace).List(ctx, opts)
+	events, err := c.listEvents(ctx, namespace, selector, since)
 	if err != nil {
 		return nil, err
 	}
 
-	// Sort by last timestamp descending
-	sort.Slice(events.Items, func(i, j int) bool {
-		return events.Items[i].LastTimestamp.After(events.Items[j].LastTimestamp.Time)
-	})
-
 	var result []EventInfo
-	for i, event := range events.Items {
-		if filter.Limit > 0 && i >= filter.Limit {
+	for _, event := range events {
+		if filter.Limit > 0 && len(result) >= filter.Limit {
 			break
 		}
 
-		// Apply filters
-		if filter.Reason != "" && !strings.Contains(strings.ToLower(event.Reason), strings.ToLower(filter.Reason)) {
-			continue
-		}
-		if filter.Object != "" && !strings.Contains(strings.ToLower(event.InvolvedObject.Name), strings.ToLower(filter.Object)) {
-			continue
+		// Apply substring filters the API server cannot
+		if !filter.Exact {
+			if filter.Reason != "" && !strings.Contains(strings.ToLower(event.Reason), strings.ToLower(filter.Reason)) {
+				continue
+			}
+			if filter.Object != "" && !strings.Contains(strings.ToLower(event.Object), strings.ToLower(filter.Object)) {
+				continue
+			}
 		}
 
-		result = append(result, EventInfo{
-			Type:          event.Type,
-			Reason:        event.Reason,
-			Object:        event.InvolvedObject.Name,
-			Kind:          event.InvolvedObject.Kind,
-			Message:       event.Message,
-			Count:         event.Count,
-			LastTimestamp: event.LastTimestamp.Time,
-		})
+		result = append(result, event)
 	}
 
 	return result, nil
@@ -674,14 +818,14 @@ func (c *Client) ListEvents(ctx context.Context, namespace string, filter EventF
 
 // ClusterResources contains cluster resource information
 type ClusterResources struct {
-	CPURequests        int64
-	CPULimits          int64
-	CPUAllocatable     int64
-	MemoryRequests     int64
-	MemoryLimits       int64
-	MemoryAllocatable  int64
-	PodCount           int
-	PodCapacity        int
+	CPURequests       int64
+	CPULimits         int64
+	CPUAllocatable    int64
+	MemoryRequests    int64
+	MemoryLimits      int64
+	MemoryAllocatable int64
+	PodCount          int
+	PodCapacity       int
 }
 
 // GetClusterResources returns cluster resource information
@@ -720,56 +864,203 @@ func (c *Client) GetClusterResources(ctx context.Context) (*ClusterResources, er
 
 // NamespaceResources contains namespace resource information
 type NamespaceResources struct {
-	Namespace      string
-	PodCount       int
-	CPURequests    int64
-	MemoryRequests int64
+	Namespace      string `json:"namespace"`
+	PodCount       int    `json:"pods"`
+	Running        int    `json:"running"`
+	Pending        int    `json:"pending"`
+	Succeeded      int    `json:"succeeded"`
+	Failed         int    `json:"failed"`
+	CPURequests    int64  `json:"cpu_requests_millicores"`
+	CPULimits      int64  `json:"cpu_limits_millicores"`
+	MemoryRequests int64  `json:"memory_requests_bytes"`
+	MemoryLimits   int64  `json:"memory_limits_bytes"`
+	// TopWorkloads are the namespace's largest workloads by CPU requests
+	TopWorkloads []WorkloadResources `json:"top_workloads,omitempty"`
 }
 
-// GetNamespaceResources returns resource usage by namespace
-func (c *Client) GetNamespaceResources(ctx context.Context) ([]NamespaceResources, error) {
-	namespaces, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+// WorkloadResources is the summed requests of the pods owned by one workload
+type WorkloadResources struct {
+	Kind           string `json:"kind"`
+	Name           string `json:"name"`
+	Pods           int    `json:"pods"`
+	CPURequests    int64  `json:"cpu_requests_millicores"`
+	MemoryRequests int64  `json:"memory_requests_bytes"`
+}
+
+// GetNamespaceResources returns resource usage by namespace with up to topWorkloads workloads each
+func (c *Client) GetNamespaceResources(ctx context.Context, topWorkloads int) ([]NamespaceResources, error) {
+	// One cluster-wide list bucketed by namespace; a list per namespace takes
+	// minutes on clusters with hundreds of namespaces. Users whose RBAC only
+	// covers some namespaces fall back to the per-namespace lists.
+	byNamespace, err := c.podsByNamespace(ctx)
+	if apierrors.IsForbidden(err) {
+		logging.Debug("cluster-wide pod list forbidden, listing per namespace", "error", err)
+		byNamespace, err = c.podsPerNamespace(ctx)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	var result []NamespaceResources
+	namespaces := make([]string, 0, len(byNamespace))
+	for ns := range byNamespace {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	result := make([]NamespaceResources, 0, len(namespaces))
+	for _, ns := range namespaces {
+		result = append(result, namespaceResources(ns, byNamespace[ns], topWorkloads))
+	}
+
+	// Sort by CPU requests descending
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CPURequests > result[j].CPURequests
+	})
+
+	return result, nil
+}
+
+// podsByNamespace lists pods cluster-wide and groups the in-scope ones by namespace
+func (c *Client) podsByNamespace(ctx context.Context) (map[string][]corev1.Pod, error) {
+	pods, err := c.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	byNamespace := make(map[string][]corev1.Pod)
+	for _, pod := range pods.Items {
+		if c.inScope(pod.Namespace) {
+			byNamespace[pod.Namespace] = append(byNamespace[pod.Namespace], pod)
+		}
+	}
+	return byNamespace, nil
+}
+
+// podsPerNamespace lists pods one in-scope namespace at a time, skipping the
+// namespaces the user may not list pods in
+func (c *Client) podsPerNamespace(ctx context.Context) (map[string][]corev1.Pod, error) {
+	namespaces, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
 
+	var names []string
 	for _, ns := range namespaces.Items {
-		pods, err := c.clientset.CoreV1().Pods(ns.Name).List(ctx, metav1.ListOptions{})
+		if c.inScope(ns.Name) {
+			names = append(names, ns.Name)
+		}
+	}
+
+	pods := make([][]corev1.Pod, len(names))
+	errs := batch.Run(len(names), batch.DefaultWorkers, func(i int) error {
+		list, err := c.clientset.CoreV1().Pods(names[i]).List(ctx, metav1.ListOptions{})
+		if apierrors.IsForbidden(err) {
+			return nil
+		}
 		if err != nil {
-			continue
+			return err
 		}
+		pods[i] = list.Items
+		return nil
+	})
+	if err := batch.Collect(names, errs); err != nil {
+		return nil, err
+	}
 
-		nsRes := NamespaceResources{
-			Namespace: ns.Name,
-			PodCount:  len(pods.Items),
+	byNamespace := make(map[string][]corev1.Pod)
+	for i, ns := range names {
+		if len(pods[i]) > 0 {
+			byNamespace[ns] = pods[i]
 		}
+	}
+	return byNamespace, nil
+}
 
-		for _, pod := range pods.Items {
-			for _, container := range pod.Spec.Containers {
-				nsRes.CPURequests += container.Resources.Requests.Cpu().MilliValue()
-				nsRes.MemoryRequests += container.Resources.Requests.Memory().Value()
-			}
+// namespaceResources totals the requests and limits of one namespace's pods
+func namespaceResources(namespace string, pods []corev1.Pod, topWorkloads int) NamespaceResources {
+	nsRes := NamespaceResources{
+		Namespace: namespace,
+		PodCount:  len(pods),
+	}
+
+	workloads := make(map[string]*WorkloadResources)
+	var order []string
+	for _, pod := range pods {
+		switch pod.Status.Phase {
+		case corev1.PodRunning:
+			nsRes.Running++
+		case corev1.PodPending:
+			nsRes.Pending++
+		case corev1.PodSucceeded:
+			nsRes.Succeeded++
+		case corev1.PodFailed:
+			nsRes.Failed++
 		}
 
-		if nsRes.PodCount > 0 {
-			result = append(result, nsRes)
+		kind, name := podWorkload(pod)
+		key := kind + "/" + name
+		w, ok := workloads[key]
+		if !ok {
+			w = &WorkloadResources{Kind: kind, Name: name}
+			workloads[key] = w
+			order = append(order, key)
+		}
+		w.Pods++
+
+		for _, container := range pod.Spec.Containers {
+			cpu := container.Resources.Requests.Cpu().MilliValue()
+			mem := container.Resources.Requests.Memory().Value()
+			nsRes.CPURequests += cpu
+			nsRes.MemoryRequests += mem
+			nsRes.CPULimits += container.Resources.Limits.Cpu().MilliValue()
+			nsRes.MemoryLimits += container.Resources.Limits.Memory().Value()
+			w.CPURequests += cpu
+			w.MemoryRequests += mem
 		}
 	}
 
-	// Sort by CPU requests descending
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].CPURequests > result[j].CPURequests
+	for _, key := range order {
+		nsRes.TopWorkloads = append(nsRes.TopWorkloads, *workloads[key])
+	}
+	sort.SliceStable(nsRes.TopWorkloads, func(i, j int) bool {
+		a, b := nsRes.TopWorkloads[i], nsRes.TopWorkloads[j]
+		if a.CPURequests != b.CPURequests {
+			return a.CPURequests > b.CPURequests
+		}
+		return a.MemoryRequests > b.MemoryRequests
 	})
+	if len(nsRes.TopWorkloads) > topWorkloads {
+		nsRes.TopWorkloads = nsRes.TopWorkloads[:max(topWorkloads, 0)]
+	}
+	return nsRes
+}
 
-	return result, nil
+// podWorkload returns the top-level controller of a pod. ReplicaSets created by a
+// Deployment are reported as the Deployment; bare pods as themselves.
+func podWorkload(pod corev1.Pod) (string, string) {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller == nil || !*ref.Controller {
+			continue
+		}
+		if ref.Kind == "ReplicaSet" {
+			if hash := pod.Labels["pod-template-hash"]; hash != "" && strings.HasSuffix(ref.Name, "-"+hash) {
+				return "Deployment", strings.TrimSuffix(ref.Name, "-"+hash)
+			}
+		}
+		return ref.Kind, ref.Name
+	}
+	return "Pod", pod.Name
 }
 
 // TopPods contains top resource consuming pods
 type TopPods struct {
 	ByCPU    []PodResourceUsage
 	ByMemory []PodResourceUsage
+	// OverRequested are the pods reserving the most requested capacity they
+	// don't use; empty unless usage was measured
+	OverRequested []PodResourceUsage
+	// NoRequests are pods missing a CPU or memory request, heaviest first
+	NoRequests []PodResourceUsage
 }
 
 // PodResourceUsage contains pod resource usage
@@ -780,10 +1071,53 @@ type PodResourceUsage struct {
 	CPURequest    int64
 	MemoryUsage   int64
 	MemoryRequest int64
+	CPULimit      int64
+	MemoryLimit   int64
+	// HasUsage is set when usage was measured rather than copied from requests
+	HasUsage bool
+}
+
+// UnusedCPU returns the requested millicores above measured usage
+func (p PodResourceUsage) UnusedCPU() int64 {
+	if !p.HasUsage {
+		return 0
+	}
+	return max(p.CPURequest-p.CPUUsage, 0)
 }
 
-// GetTopPods returns top resource consuming pods
+// UnusedMemory returns the requested bytes above measured usage
+func (p PodResourceUsage) UnusedMemory() int64 {
+	if !p.HasUsage {
+		return 0
+	}
+	return max(p.MemoryRequest-p.MemoryUsage, 0)
+}
+
+// GetTopPods returns top resource consuming pods. Usage comes from
+// metrics-server when it is installed and falls back to requests otherwise.
 func (c *Client) GetTopPods(ctx context.Context, namespace string, limit int) (*TopPods, error) {
+	usage, err := c.ListPodResources(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, err := c.podMetrics(ctx, namespace)
+	if err != nil {
+		logging.Debug("pod metrics unavailable", "error", err)
+	}
+	for i := range usage {
+		if u, ok := metrics[usage[i].Namespace+"/"+usage[i].Name]; ok {
+			usage[i].CPUUsage = u.Cpu().MilliValue()
+			usage[i].MemoryUsage = u.Memory().Value()
+			usage[i].HasUsage = true
+		}
+	}
+	return RankPods(usage, limit), nil
+}
+
+// ListPodResources returns the requests and limits of running pods, with
+// requests standing in for usage until real usage is filled in
+func (c *Client) ListPodResources(ctx context.Context, namespace string) ([]PodResourceUsage, error) {
 	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
 		FieldSelector: "status.phase=Running",
 	})
@@ -794,6 +1128,10 @@ func (c *Client) GetTopPods(ctx context.Context, namespace string, limit int) (*
 	var usage []PodResourceUsage
 
 	for _, pod := range pods.Items {
+		if !c.inScope(pod.Namespace) {
+			continue
+		}
+
 		pu := PodResourceUsage{
 			Name:      pod.Name,
 			Namespace: pod.Namespace,
@@ -802,6 +1140,8 @@ func (c *Client) GetTopPods(ctx context.Context, namespace string, limit int) (*
 		for _, container := range pod.Spec.Containers {
 			pu.CPURequest += container.Resources.Requests.Cpu().MilliValue()
 			pu.MemoryRequest += container.Resources.Requests.Memory().Value()
+			pu.CPULimit += container.Resources.Limits.Cpu().MilliValue()
+			pu.MemoryLimit += container.Resources.Limits.Memory().Value()
 			// Use requests as proxy for usage since we don't have metrics-server integration
 			pu.CPUUsage += container.Resources.Requests.Cpu().MilliValue()
 			pu.MemoryUsage += container.Resources.Requests.Memory().Value()
@@ -810,24 +1150,62 @@ func (c *Client) GetTopPods(ctx context.Context, namespace string, limit int) (*
 		usage = append(usage, pu)
 	}
 
+	return usage, nil
+}
+
+// RankPods returns the top limit pods by CPU and by memory usage
+func RankPods(usage []PodResourceUsage, limit int) *TopPods {
+	sorted := make([]PodResourceUsage, len(usage))
+	copy(sorted, usage)
+
 	result := &TopPods{}
 
 	// Sort by CPU
-	sort.Slice(usage, func(i, j int) bool {
-		return usage[i].CPUUsage > usage[j].CPUUsage
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].CPUUsage > sorted[j].CPUUsage
 	})
-	for i := 0; i < limit && i < len(usage); i++ {
-		result.ByCPU = append(result.ByCPU, usage[i])
+	for i := 0; i < limit && i < len(sorted); i++ {
+		result.ByCPU = append(result.ByCPU, sorted[i])
 	}
 
 	// Sort by Memory
-	sort.Slice(usage, func(i, j int) bool {
-		return usage[i].MemoryUsage > usage[j].MemoryUsage
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MemoryUsage > sorted[j].MemoryUsage
 	})
-	for i := 0; i < limit && i < len(usage); i++ {
-		result.ByMemory = append(result.ByMemory, usage[i])
+	for i := 0; i < limit && i < len(sorted); i++ {
+		result.ByMemory = append(result.ByMemory, sorted[i])
 	}
 
-	return result, nil
+	// Most unused reservation, CPU first
+	var over []PodResourceUsage
+	for _, pod := range usage {
+		if pod.UnusedCPU() > 0 || pod.UnusedMemory() > 0 {
+			over = append(over, pod)
+		}
+	}
+	sort.SliceStable(over, func(i, j int) bool {
+		if over[i].UnusedCPU() != over[j].UnusedCPU() {
+			return over[i].UnusedCPU() > over[j].UnusedCPU()
+		}
+		return over[i].UnusedMemory() > over[j].UnusedMemory()
+	})
+	result.OverRequested = over[:min(limit, len(over))]
+
+	// Missing requests, heaviest users first since the scheduler can't see them
+	var none []PodResourceUsage
+	for _, pod := range usage {
+		if pod.CPURequest == 0 || pod.MemoryRequest == 0 {
+			none = append(none, pod)
+		}
+	}
+	sort.SliceStable(none, func(i, j int) bool {
+		if none[i].CPUUsage != none[j].CPUUsage {
+			return none[i].CPUUsage > none[j].CPUUsage
+		}
+		return none[i].MemoryUsage > none[j].MemoryUsage
+	})
+	result.NoRequests = none[:min(limit, len(none))]
+
+	return result
 }
 
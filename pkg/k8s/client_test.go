@@ -0,0 +1,494 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newFakeClient(objects ...runtime.Object) (*Client, *fake.Clientset) {
+	clientset := fake.NewSimpleClientset(objects...)
+	return NewClientFromClientset(clientset, nil), clientset
+}
+
+func pod(namespace, name string, phase corev1.PodPhase, cpu, memory string) *corev1.Pod {
+	p := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "app",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{},
+				Limits:   corev1.ResourceList{},
+			},
+		}}},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+	if cpu != "" {
+		p.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU] = resource.MustParse(cpu)
+		p.Spec.Containers[0].Resources.Limits[corev1.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if memory != "" {
+		p.Spec.Containers[0].Resources.Requests[corev1.ResourceMemory] = resource.MustParse(memory)
+	}
+	return p
+}
+
+func node(name string, ready bool, cpu, memory string) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	capacity := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Capacity:    capacity,
+			Allocatable: capacity,
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func int32Ptr(n int32) *int32 { return &n }
+
+func TestHealthChecks(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newFakeClient(
+		node("node-1", true, "4", "16Gi"),
+		node("node-2", false, "4", "16Gi"),
+		pod("default", "web", corev1.PodRunning, "", ""),
+		pod("default", "batch", corev1.PodPending, "", ""),
+		pod("kube-system", "dns", corev1.PodFailed, "", ""),
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default"},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "scratch", Namespace: "default"},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+		},
+	)
+
+	nodes, err := c.GetNodeHealth(ctx)
+	if err != nil {
+		t.Fatalf("GetNodeHealth: %v", err)
+	}
+	if nodes.Total != 2 || nodes.Ready != 1 || nodes.Healthy {
+		t.Errorf("node health = %+v, want 1 of 2 ready and unhealthy", nodes)
+	}
+
+	pods, err := c.GetPodHealth(ctx, "")
+	if err != nil {
+		t.Fatalf("GetPodHealth: %v", err)
+	}
+	if *pods != (PodHealth{Running: 1, Pending: 1, Failed: 1, Total: 3}) {
+		t.Errorf("pod health = %+v", pods)
+	}
+
+	c.SetNamespaceSelector(NamespaceSelector{Exclude: []string{"kube-*"}})
+	pods, err = c.GetPodHealth(ctx, "")
+	if err != nil {
+		t.Fatalf("GetPodHealth: %v", err)
+	}
+	if pods.Total != 2 || pods.Failed != 0 {
+		t.Errorf("pod health with kube-* excluded = %+v", pods)
+	}
+
+	pvcs, err := c.GetPVCHealth(ctx, "default")
+	if err != nil {
+		t.Fatalf("GetPVCHealth: %v", err)
+	}
+	if *pvcs != (PVCHealth{Bound: 1, Pending: 1, Total: 2}) {
+		t.Errorf("pvc health = %+v", pvcs)
+	}
+
+	services, err := c.GetServiceHealth(ctx, "default")
+	if err != nil {
+		t.Fatalf("GetServiceHealth: %v", err)
+	}
+	if *services != (ServiceHealth{ClusterIP: 1, LoadBalancer: 1, Total: 2}) {
+		t.Errorf("service health = %+v", services)
+	}
+}
+
+func TestDeploymentHealth(t *testing.T) {
+	deployment := func(name string, replicas int32, status appsv1.DeploymentStatus, mutate func(*appsv1.Deployment)) *appsv1.Deployment {
+		d := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(replicas)},
+			Status:     status,
+		}
+		if mutate != nil {
+			mutate(d)
+		}
+		return d
+	}
+
+	c, _ := newFakeClient(
+		deployment("healthy", 2, appsv1.DeploymentStatus{ReadyReplicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}, nil),
+		deployment("rolling", 3, appsv1.DeploymentStatus{ReadyReplicas: 3, UpdatedReplicas: 1, UnavailableReplicas: 1}, nil),
+		deployment("degraded", 2, appsv1.DeploymentStatus{ReadyReplicas: 1, UpdatedReplicas: 2, UnavailableReplicas: 1}, nil),
+		deployment("paused", 1, appsv1.DeploymentStatus{UnavailableReplicas: 1}, func(d *appsv1.Deployment) {
+			d.Spec.Paused = true
+		}),
+		deployment("stalled", 1, appsv1.DeploymentStatus{UnavailableReplicas: 1, Conditions: []appsv1.DeploymentCondition{{
+			Type:    appsv1.DeploymentProgressing,
+			Reason:  "ProgressDeadlineExceeded",
+			Message: `ReplicaSet "stalled-5d8f" has timed out progressing.`,
+		}}}, nil),
+		deployment("default-replicas", 0, appsv1.DeploymentStatus{ReadyReplicas: 1, UpdatedReplicas: 1}, func(d *appsv1.Deployment) {
+			d.Spec.Replicas = nil
+		}),
+	)
+
+	health, err := c.GetDeploymentHealth(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetDeploymentHealth: %v", err)
+	}
+	if health.Total != 6 || health.Ready != 2 || health.Paused != 1 || health.Stalled != 1 || health.Unavailable != 3 {
+		t.Errorf("deployment health = %+v", health)
+	}
+
+	want := map[string]string{
+		"healthy":          DeploymentHealthy,
+		"rolling":          DeploymentProgressing,
+		"degraded":         DeploymentDegraded,
+		"paused":           DeploymentPaused,
+		"stalled":          DeploymentStalled,
+		"default-replicas": DeploymentHealthy,
+	}
+	for _, d := range health.Deployments {
+		if d.State != want[d.Name] {
+			t.Errorf("%s state = %s, want %s", d.Name, d.State, want[d.Name])
+		}
+	}
+}
+
+func TestCleanupFinders(t *testing.T) {
+	ctx := context.Background()
+	evicted := pod("default", "evicted", corev1.PodFailed, "", "")
+	evicted.Status.Reason = "Evicted"
+	crashing := pod("default", "crashing", corev1.PodRunning, "", "")
+	crashing.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "app",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+	}}
+	errored := pod("default", "errored", corev1.PodFailed, "", "")
+	errored.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "app",
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error"}},
+	}}
+	controller := true
+
+	c, _ := newFakeClient(
+		pod("default", "done", corev1.PodSucceeded, "", ""),
+		pod("default", "web", corev1.PodRunning, "", ""),
+		evicted, crashing, errored,
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "default"},
+			Status:     batchv1.JobStatus{Succeeded: 1},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly-28490", Namespace: "default", OwnerReferences: []metav1.OwnerReference{
+				{Kind: "CronJob", Name: "nightly", Controller: &controller},
+			}},
+			Spec:   batchv1.JobSpec{TTLSecondsAfterFinished: int32Ptr(3600)},
+			Status: batchv1.JobStatus{Succeeded: 1},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "default"},
+			Status:     batchv1.JobStatus{Succeeded: 1, Active: 1},
+		},
+		&appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "orphan-1a2b", Namespace: "default"},
+		},
+		&appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-7c9d", Namespace: "default", OwnerReferences: []metav1.OwnerReference{
+				{Kind: "Deployment", Name: "web", Controller: &controller},
+			}},
+		},
+		&appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "scaled-3e4f", Namespace: "default"},
+			Status:     appsv1.ReplicaSetStatus{Replicas: 2},
+		},
+	)
+
+	completed, err := c.FindCompletedPods(ctx, "default")
+	if err != nil {
+		t.Fatalf("FindCompletedPods: %v", err)
+	}
+	if names := podNames(completed); names != "done" {
+		t.Errorf("completed pods = %s, want done", names)
+	}
+
+	failed, err := c.FindFailedPods(ctx, "default")
+	if err != nil {
+		t.Fatalf("FindFailedPods: %v", err)
+	}
+	if names := podNames(failed); names != "crashing,errored" {
+		t.Errorf("failed pods = %s, want crashing,errored", names)
+	}
+
+	evictedPods, err := c.FindEvictedPods(ctx, "default")
+	if err != nil {
+		t.Fatalf("FindEvictedPods: %v", err)
+	}
+	if names := podNames(evictedPods); names != "evicted" {
+		t.Errorf("evicted pods = %s, want evicted", names)
+	}
+
+	jobs, err := c.FindCompletedJobs(ctx, "default")
+	if err != nil {
+		t.Fatalf("FindCompletedJobs: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("completed jobs = %+v, want migrate and nightly-28490", jobs)
+	}
+	for _, job := range jobs {
+		if job.Name == "nightly-28490" && (job.CronJob != "nightly" || !job.HasTTL) {
+			t.Errorf("cron job = %+v, want owner nightly with a TTL", job)
+		}
+	}
+
+	replicaSets, err := c.FindOrphanedReplicaSets(ctx, "default")
+	if err != nil {
+		t.Fatalf("FindOrphanedReplicaSets: %v", err)
+	}
+	if len(replicaSets) != 1 || replicaSets[0].Name != "orphan-1a2b" {
+		t.Errorf("orphaned replica sets = %+v, want orphan-1a2b", replicaSets)
+	}
+}
+
+func TestCleanupFindersReturnListErrors(t *testing.T) {
+	c, clientset := newFakeClient()
+	clientset.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+
+	ctx := context.Background()
+	if _, err := c.FindCompletedPods(ctx, ""); err == nil {
+		t.Error("FindCompletedPods swallowed the list error")
+	}
+	if _, err := c.FindCompletedJobs(ctx, ""); err == nil {
+		t.Error("FindCompletedJobs swallowed the list error")
+	}
+	if _, err := c.FindOrphanedReplicaSets(ctx, ""); err == nil {
+		t.Error("FindOrphanedReplicaSets swallowed the list error")
+	}
+}
+
+func TestResourceSummaries(t *testing.T) {
+	ctx := context.Background()
+	controller := true
+	web1 := pod("shop", "web-7c9d-abcde", corev1.PodRunning, "500m", "256Mi")
+	web2 := pod("shop", "web-7c9d-fghij", corev1.PodRunning, "500m", "256Mi")
+	for _, p := range []*corev1.Pod{web1, web2} {
+		p.Labels = map[string]string{"pod-template-hash": "7c9d"}
+		p.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-7c9d", Controller: &controller}}
+	}
+	db := pod("shop", "db-0", corev1.PodRunning, "2", "4Gi")
+	db.OwnerReferences = []metav1.OwnerReference{{Kind: "StatefulSet", Name: "db", Controller: &controller}}
+
+	c, _ := newFakeClient(
+		node("node-1", true, "4", "16Gi"),
+		node("node-2", true, "4", "16Gi"),
+		web1, web2, db,
+		pod("tools", "debug", corev1.PodPending, "100m", ""),
+	)
+
+	cluster, err := c.GetClusterResources(ctx)
+	if err != nil {
+		t.Fatalf("GetClusterResources: %v", err)
+	}
+	if cluster.CPUAllocatable != 8000 || cluster.PodCapacity != 220 || cluster.PodCount != 4 {
+		t.Errorf("cluster capacity = %+v", cluster)
+	}
+	if cluster.CPURequests != 3100 || cluster.CPULimits != 3100 || cluster.MemoryRequests != 4608<<20 {
+		t.Errorf("cluster requests = %+v", cluster)
+	}
+
+	namespaces, err := c.GetNamespaceResources(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetNamespaceResources: %v", err)
+	}
+	if len(namespaces) != 2 || namespaces[0].Namespace != "shop" || namespaces[1].Namespace != "tools" {
+		t.Fatalf("namespaces = %+v, want shop then tools", namespaces)
+	}
+	shop := namespaces[0]
+	if shop.PodCount != 3 || shop.Running != 3 || shop.CPURequests != 3000 {
+		t.Errorf("shop = %+v", shop)
+	}
+	if len(shop.TopWorkloads) != 1 || shop.TopWorkloads[0] != (WorkloadResources{
+		Kind: "StatefulSet", Name: "db", Pods: 1, CPURequests: 2000, MemoryRequests: 4 << 30,
+	}) {
+		t.Errorf("shop top workloads = %+v, want the db StatefulSet", shop.TopWorkloads)
+	}
+	if namespaces[1].Pending != 1 {
+		t.Errorf("tools = %+v, want one pending pod", namespaces[1])
+	}
+
+	all, err := c.GetNamespaceResources(ctx, 5)
+	if err != nil {
+		t.Fatalf("GetNamespaceResources: %v", err)
+	}
+	web := all[0].TopWorkloads[1]
+	if web.Kind != "Deployment" || web.Name != "web" || web.Pods != 2 || web.CPURequests != 1000 {
+		t.Errorf("web workload = %+v, want the Deployment with both pods", web)
+	}
+
+	usage, err := c.ListPodResources(ctx, "shop")
+	if err != nil {
+		t.Fatalf("ListPodResources: %v", err)
+	}
+	if len(usage) != 3 {
+		t.Fatalf("pod resources = %+v, want 3 pods", usage)
+	}
+	for _, u := range usage {
+		if u.HasUsage || u.CPUUsage != u.CPURequest {
+			t.Errorf("%s usage = %+v, want requests standing in for usage", u.Name, u)
+		}
+	}
+}
+
+func namespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+// forbidPods rejects pod lists cluster-wide and in the given namespaces, as
+// RBAC does for a user bound to a few namespaces only
+func forbidPods(namespaces ...string) k8stesting.ReactionFunc {
+	forbidden := map[string]bool{"": true}
+	for _, ns := range namespaces {
+		forbidden[ns] = true
+	}
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if !forbidden[action.GetNamespace()] {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("RBAC: access denied"))
+	}
+}
+
+func TestGetNamespaceResourcesForbiddenFallback(t *testing.T) {
+	c, clientset := newFakeClient(
+		namespace("shop"), namespace("tools"), namespace("payments"), namespace("empty"),
+		pod("shop", "web", corev1.PodRunning, "500m", "256Mi"),
+		pod("tools", "debug", corev1.PodPending, "100m", ""),
+		pod("payments", "api", corev1.PodRunning, "1", "1Gi"),
+	)
+	clientset.PrependReactor("list", "pods", forbidPods("payments"))
+
+	namespaces, err := c.GetNamespaceResources(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("GetNamespaceResources: %v", err)
+	}
+	if len(namespaces) != 2 || namespaces[0].Namespace != "shop" || namespaces[1].Namespace != "tools" {
+		t.Fatalf("namespaces = %+v, want shop and tools", namespaces)
+	}
+	if namespaces[0].CPURequests != 500 || namespaces[1].Pending != 1 {
+		t.Errorf("namespaces = %+v", namespaces)
+	}
+
+	c.SetNamespaceSelector(NamespaceSelector{Include: []string{"tools"}})
+	namespaces, err = c.GetNamespaceResources(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("GetNamespaceResources: %v", err)
+	}
+	if len(namespaces) != 1 || namespaces[0].Namespace != "tools" {
+		t.Errorf("namespaces = %+v, want only tools", namespaces)
+	}
+}
+
+func TestGetNamespaceResourcesFallbackErrors(t *testing.T) {
+	c, clientset := newFakeClient(namespace("shop"), pod("shop", "web", corev1.PodRunning, "", ""))
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "" {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("RBAC: access denied"))
+		}
+		return true, nil, apierrors.NewServiceUnavailable("etcd leader changed")
+	})
+
+	if _, err := c.GetNamespaceResources(context.Background(), 5); err == nil {
+		t.Error("GetNamespaceResources swallowed a per-namespace list error")
+	}
+
+	clientset.PrependReactor("list", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "", errors.New("RBAC: access denied"))
+	})
+	if _, err := c.GetNamespaceResources(context.Background(), 5); !apierrors.IsForbidden(err) {
+		t.Errorf("err = %v, want forbidden when namespaces cannot be listed either", err)
+	}
+}
+
+// benchmarkCluster builds namespaces with podsPerNamespace running pods each
+func benchmarkCluster(namespaces, podsPerNamespace int) []runtime.Object {
+	var objects []runtime.Object
+	for i := 0; i < namespaces; i++ {
+		ns := fmt.Sprintf("team-%03d", i)
+		objects = append(objects, namespace(ns))
+		for j := 0; j < podsPerNamespace; j++ {
+			objects = append(objects, pod(ns, fmt.Sprintf("app-%d", j), corev1.PodRunning, "100m", "128Mi"))
+		}
+	}
+	return objects
+}
+
+func BenchmarkGetNamespaceResources(b *testing.B) {
+	objects := benchmarkCluster(200, 10)
+
+	b.Run("cluster-wide", func(b *testing.B) {
+		c, _ := newFakeClient(objects...)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := c.GetNamespaceResources(context.Background(), 5); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("per-namespace", func(b *testing.B) {
+		c, clientset := newFakeClient(objects...)
+		clientset.PrependReactor("list", "pods", forbidPods())
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := c.GetNamespaceResources(context.Background(), 5); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func podNames(pods []PodInfo) string {
+	var names []string
+	for _, p := range pods {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
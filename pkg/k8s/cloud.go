@@ -0,0 +1,329 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/logging"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Managed Kubernetes providers
+const (
+	ProviderEKS = "eks"
+	ProviderGKE = "gke"
+	ProviderAKS = "aks"
+)
+
+// nodePoolLabels are the labels managed providers put the node pool name in, by provider
+var nodePoolLabels = map[string][]string{
+	ProviderEKS: {"eks.amazonaws.com/nodegroup", "karpenter.sh/nodepool", "alpha.eksctl.io/nodegroup-name"},
+	ProviderGKE: {"cloud.google.com/gke-nodepool"},
+	ProviderAKS: {"kubernetes.azure.com/agentpool", "agentpool"},
+}
+
+// NodePool groups the nodes of one managed node group / node pool
+type NodePool struct {
+	Name          string   `json:"name"`
+	Nodes         int      `json:"nodes"`
+	Ready         int      `json:"ready"`
+	Versions      []string `json:"versions"`
+	InstanceTypes []string `json:"instance_types"`
+	Zones         []string `json:"zones"`
+}
+
+// CloudRef identifies a managed cluster in its cloud provider
+type CloudRef struct {
+	Provider      string `json:"provider"`
+	Name          string `json:"name"`
+	Location      string `json:"location"`
+	Project       string `json:"project,omitempty"`
+	ResourceGroup string `json:"resource_group,omitempty"`
+}
+
+// CloudNodePool is a node pool as reported by the cloud provider
+type CloudNodePool struct {
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	InstanceType string `json:"instance_type"`
+	Size         int    `json:"size"`
+	Status       string `json:"status"`
+	AutoUpgrade  bool   `json:"auto_upgrade"`
+}
+
+// CloudDetails is managed-cluster metadata fetched from the cloud provider
+type CloudDetails struct {
+	Ref                 CloudRef        `json:"ref"`
+	ControlPlaneVersion string          `json:"control_plane_version"`
+	LatestVersion       string          `json:"latest_version"`
+	Channel             string          `json:"channel,omitempty"`
+	MaintenanceWindow   string          `json:"maintenance_window,omitempty"`
+	AutoUpgrade         string          `json:"auto_upgrade,omitempty"`
+	AutoUpgradeAt       time.Time       `json:"auto_upgrade_at,omitempty"`
+	NodePools           []CloudNodePool `json:"node_pools"`
+}
+
+// UpgradeAvailable reports whether a newer control plane version is offered
+func (d *CloudDetails) UpgradeAvailable() bool {
+	return d.LatestVersion != "" && CompareVersions(d.LatestVersion, d.ControlPlaneVersion) > 0
+}
+
+// cloudEnricher fetches cluster details from the provider: EKS through the AWS
+// SDK, GKE and AKS through the gcloud and az CLIs. Enrichers are compiled in
+// with the eks, gke and aks build tags.
+type cloudEnricher func(ctx context.Context, ref CloudRef) (*CloudDetails, error)
+
+var cloudEnrichers = map[string]cloudEnricher{}
+
+// detectCloud fills the provider, node pools and (if compiled in) cloud details
+func (c *Client) detectCloud(ctx context.Context, info *ClusterInfo, nodes []corev1.Node) {
+	info.Provider = detectProvider(c.config.Host, nodes)
+	if info.Provider == "" {
+		return
+	}
+	info.NodePools = groupNodePools(info.Provider, nodes)
+
+	ref := cloudRef(info.Provider, c.contextName, c.config.Host, nodes)
+	if ref.Name != "" {
+		info.Name = ref.Name
+	}
+
+	enrich, ok := cloudEnrichers[info.Provider]
+	if !ok {
+		return
+	}
+	if ref.Name == "" {
+		info.CloudError = "could not determine the cluster name from the kubeconfig context"
+		return
+	}
+
+	details, err := enrich(ctx, ref)
+	if err != nil {
+		logging.Debug("cloud enrichment failed", "provider", info.Provider, "error", err)
+		info.CloudError = err.Error()
+		return
+	}
+	info.Cloud = details
+}
+
+// detectProvider identifies the managed Kubernetes service from node metadata
+func detectProvider(server string, nodes []corev1.Node) string {
+	host := server
+	if u, err := url.Parse(server); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+
+	switch {
+	case strings.HasSuffix(host, ".eks.amazonaws.com"):
+		return ProviderEKS
+	case strings.HasSuffix(host, ".azmk8s.io"):
+		return ProviderAKS
+	}
+
+	for _, node := range nodes {
+		switch {
+		case strings.HasPrefix(node.Spec.ProviderID, "aws://") && hasAnyLabel(node, "eks.amazonaws.com/nodegroup", "eks.amazonaws.com/compute-type", "alpha.eksctl.io/cluster-name"):
+			return ProviderEKS
+		case strings.HasPrefix(node.Spec.ProviderID, "gce://") && hasAnyLabel(node, "cloud.google.com/gke-nodepool"):
+			return ProviderGKE
+		case strings.HasPrefix(node.Spec.ProviderID, "azure://") && hasAnyLabel(node, "kubernetes.azure.com/cluster"):
+			return ProviderAKS
+		}
+	}
+	return ""
+}
+
+func hasAnyLabel(node corev1.Node, labels ...string) bool {
+	for _, l := range labels {
+		if _, ok := node.Labels[l]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// groupNodePools groups nodes by their provider node pool label
+func groupNodePools(provider string, nodes []corev1.Node) []NodePool {
+	pools := make(map[string]*NodePool)
+	versions := make(map[string]map[string]bool)
+	types := make(map[string]map[string]bool)
+	zones := make(map[string]map[string]bool)
+
+	for _, node := range nodes {
+		name := "<none>"
+		for _, l := range nodePoolLabels[provider] {
+			if v := node.Labels[l]; v != "" {
+				name = v
+				break
+			}
+		}
+
+		pool, ok := pools[name]
+		if !ok {
+			pool = &NodePool{Name: name}
+			pools[name] = pool
+			versions[name] = make(map[string]bool)
+			types[name] = make(map[string]bool)
+			zones[name] = make(map[string]bool)
+		}
+
+		pool.Nodes++
+		for _, cond := range node.Status.Conditions {
+			if cond.Type == corev1.NodeReady && cond.Status == corev1.ConditionTrue {
+				pool.Ready++
+			}
+		}
+		versions[name][node.Status.NodeInfo.KubeletVersion] = true
+		if t := node.Labels["node.kubernetes.io/instance-type"]; t != "" {
+			types[name][t] = true
+		}
+		if z := node.Labels["topology.kubernetes.io/zone"]; z != "" {
+			zones[name][z] = true
+		}
+	}
+
+	result := make([]NodePool, 0, len(pools))
+	for name, pool := range pools {
+		pool.Versions = setKeys(versions[name])
+		pool.InstanceTypes = setKeys(types[name])
+		pool.Zones = setKeys(zones[name])
+		result = append(result, *pool)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func setKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// cloudRef works out the cluster's cloud identity from the context name and node metadata
+func cloudRef(provider, contextName, server string, nodes []corev1.Node) CloudRef {
+	ref := CloudRef{Provider: provider}
+
+	var sample corev1.Node
+	if len(nodes) > 0 {
+		sample = nodes[0]
+	}
+
+	switch provider {
+	case ProviderEKS:
+		// arn:aws:eks:<region>:<account>:cluster/<name> (aws eks update-kubeconfig)
+		// <user>@<name>.<region>.eksctl.io (eksctl)
+		switch {
+		case strings.HasPrefix(contextName, "arn:aws:eks:"):
+			parts := strings.Split(contextName, ":")
+			if len(parts) >= 6 {
+				ref.Location = parts[3]
+				ref.Name = strings.TrimPrefix(parts[5], "cluster/")
+			}
+		case strings.HasSuffix(contextName, ".eksctl.io"):
+			rest := contextName[strings.Index(contextName, "@")+1:]
+			parts := strings.Split(strings.TrimSuffix(rest, ".eksctl.io"), ".")
+			if len(parts) == 2 {
+				ref.Name, ref.Location = parts[0], parts[1]
+			}
+		}
+		if ref.Name == "" {
+			ref.Name = sample.Labels["alpha.eksctl.io/cluster-name"]
+		}
+		if ref.Location == "" {
+			ref.Location = eksRegion(server)
+		}
+	case ProviderGKE:
+		// gke_<project>_<location>_<name> (gcloud container clusters get-credentials)
+		if parts := strings.SplitN(contextName, "_", 4); len(parts) == 4 && parts[0] == "gke" {
+			ref.Project, ref.Location, ref.Name = parts[1], parts[2], parts[3]
+		}
+		if ref.Project == "" {
+			// gce://<project>/<zone>/<instance>
+			if parts := strings.Split(strings.TrimPrefix(sample.Spec.ProviderID, "gce://"), "/"); len(parts) == 3 {
+				ref.Project = parts[0]
+			}
+		}
+	case ProviderAKS:
+		// Node resource group MC_<resource group>_<name>_<location>; the context is the cluster name
+		ref.Name = strings.TrimSuffix(contextName, "-admin")
+		if mc := sample.Labels["kubernetes.azure.com/cluster"]; strings.HasPrefix(mc, "MC_") {
+			parts := strings.Split(strings.TrimPrefix(mc, "MC_"), "_")
+			if len(parts) >= 3 {
+				ref.Location = parts[len(parts)-1]
+				if ref.Name == "" {
+					ref.Name = parts[len(parts)-2]
+				}
+				suffix := "_" + ref.Name + "_" + ref.Location
+				ref.ResourceGroup = strings.TrimSuffix(strings.TrimPrefix(mc, "MC_"), suffix)
+			}
+		}
+	}
+
+	return ref
+}
+
+// eksRegion extracts the region from an EKS endpoint such as https://X.gr7.us-east-1.eks.amazonaws.com
+func eksRegion(server string) string {
+	u, err := url.Parse(server)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(strings.TrimSuffix(u.Hostname(), ".eks.amazonaws.com"), ".")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[len(parts)-1]
+}
+
+// CompareVersions compares Kubernetes versions such as v1.29.3-eks-1 and 1.30,
+// ignoring provider suffixes. It returns -1, 0 or 1.
+func CompareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < 3; i++ {
+		if pa[i] != pb[i] {
+			if pa[i] < pb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) [3]int {
+	var parts [3]int
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	for i, p := range strings.SplitN(v, ".", 3) {
+		n, _ := strconv.Atoi(p)
+		parts[i] = n
+	}
+	return parts
+}
+
+// latestVersion returns the highest of versions
+func latestVersion(versions []string) string {
+	latest := ""
+	for _, v := range versions {
+		if latest == "" || CompareVersions(v, latest) > 0 {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// MinorVersion trims a version to major.minor
+func MinorVersion(v string) string {
+	p := versionParts(v)
+	return fmt.Sprintf("%d.%d", p[0], p[1])
+}
@@ -0,0 +1,125 @@
+//go:build aks
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+func init() {
+	cloudEnrichers[ProviderAKS] = describeAKS
+}
+
+// describeAKS fetches control plane, agent pool and maintenance details from AKS
+func describeAKS(ctx context.Context, ref CloudRef) (*CloudDetails, error) {
+	if ref.ResourceGroup == "" {
+		return nil, fmt.Errorf("could not determine the resource group of AKS cluster %s", ref.Name)
+	}
+	scope := []string{"--name", ref.Name, "--resource-group", ref.ResourceGroup, "--output", "json"}
+
+	var cluster struct {
+		CurrentKubernetesVersion string `json:"currentKubernetesVersion"`
+		AutoUpgradeProfile       *struct {
+			UpgradeChannel       string `json:"upgradeChannel"`
+			NodeOSUpgradeChannel string `json:"nodeOsUpgradeChannel"`
+		} `json:"autoUpgradeProfile"`
+		AgentPoolProfiles []struct {
+			Name                       string `json:"name"`
+			CurrentOrchestratorVersion string `json:"currentOrchestratorVersion"`
+			VMSize                     string `json:"vmSize"`
+			Count                      int    `json:"count"`
+			ProvisioningState          string `json:"provisioningState"`
+		} `json:"agentPoolProfiles"`
+	}
+	args := append([]string{"aks", "show"}, scope...)
+	if err := runCloudCLI(ctx, &cluster, "az", args...); err != nil {
+		return nil, fmt.Errorf("failed to describe AKS cluster: %w", err)
+	}
+
+	var upgrades struct {
+		ControlPlaneProfile struct {
+			Upgrades []struct {
+				KubernetesVersion string `json:"kubernetesVersion"`
+				IsPreview         bool   `json:"isPreview"`
+			} `json:"upgrades"`
+		} `json:"controlPlaneProfile"`
+	}
+	args = append([]string{"aks", "get-upgrades"}, scope...)
+	if err := runCloudCLI(ctx, &upgrades, "az", args...); err != nil {
+		return nil, fmt.Errorf("failed to get AKS upgrades: %w", err)
+	}
+
+	details := &CloudDetails{
+		Ref:                 ref,
+		ControlPlaneVersion: cluster.CurrentKubernetesVersion,
+	}
+
+	var offered []string
+	for _, u := range upgrades.ControlPlaneProfile.Upgrades {
+		if !u.IsPreview {
+			offered = append(offered, u.KubernetesVersion)
+		}
+	}
+	details.LatestVersion = latestVersion(offered)
+
+	if p := cluster.AutoUpgradeProfile; p != nil && p.UpgradeChannel != "" && p.UpgradeChannel != "none" {
+		details.Channel = p.UpgradeChannel
+		details.AutoUpgrade = fmt.Sprintf("auto-upgrade channel %s (node OS: %s)", p.UpgradeChannel, p.NodeOSUpgradeChannel)
+	}
+
+	var configs []struct {
+		Name              string `json:"name"`
+		MaintenanceWindow *struct {
+			StartTime     string `json:"startTime"`
+			DurationHours int    `json:"durationHours"`
+			UTCOffset     string `json:"utcOffset"`
+			Schedule      struct {
+				Weekly *struct {
+					DayOfWeek     string `json:"dayOfWeek"`
+					IntervalWeeks int    `json:"intervalWeeks"`
+				} `json:"weekly"`
+				Daily *struct {
+					IntervalDays int `json:"intervalDays"`
+				} `json:"daily"`
+			} `json:"schedule"`
+		} `json:"maintenanceWindow"`
+	}
+	args = []string{"aks", "maintenanceconfiguration", "list",
+		"--cluster-name", ref.Name, "--resource-group", ref.ResourceGroup, "--output", "json"}
+	if err := runCloudCLI(ctx, &configs, "az", args...); err != nil {
+		return nil, fmt.Errorf("failed to list AKS maintenance configurations: %w", err)
+	}
+
+	var windows []string
+	for _, c := range configs {
+		w := c.MaintenanceWindow
+		if w == nil {
+			windows = append(windows, c.Name)
+			continue
+		}
+		when := "custom schedule"
+		switch {
+		case w.Schedule.Weekly != nil:
+			when = fmt.Sprintf("every %d week(s) on %s", w.Schedule.Weekly.IntervalWeeks, w.Schedule.Weekly.DayOfWeek)
+		case w.Schedule.Daily != nil:
+			when = fmt.Sprintf("every %d day(s)", w.Schedule.Daily.IntervalDays)
+		}
+		windows = append(windows, fmt.Sprintf("%s: %s at %s %s (%dh)", c.Name, when, w.StartTime, w.UTCOffset, w.DurationHours))
+	}
+	details.MaintenanceWindow = strings.Join(windows, "; ")
+
+	for _, ap := range cluster.AgentPoolProfiles {
+		details.NodePools = append(details.NodePools, CloudNodePool{
+			Name:         ap.Name,
+			Version:      ap.CurrentOrchestratorVersion,
+			InstanceType: ap.VMSize,
+			Size:         ap.Count,
+			Status:       strings.ToLower(ap.ProvisioningState),
+			AutoUpgrade:  details.Channel != "",
+		})
+	}
+
+	return details, nil
+}
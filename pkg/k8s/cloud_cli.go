@@ -0,0 +1,36 @@
+//go:build gke || aks
+
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// runCloudCLI runs a cloud provider CLI and decodes its JSON output into v
+func runCloudCLI(ctx context.Context, v interface{}, name string, args ...string) error {
+	if _, err := exec.LookPath(name); err != nil {
+		return fmt.Errorf("%s CLI not found in PATH", name)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s %s: %s", name, strings.Join(args[:2], " "), msg)
+		}
+		return fmt.Errorf("%s %s: %w", name, strings.Join(args[:2], " "), err)
+	}
+
+	if err := json.Unmarshal(out, v); err != nil {
+		return fmt.Errorf("failed to parse %s output: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+//go:build eks
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/aws"
+)
+
+func init() {
+	cloudEnrichers[ProviderEKS] = describeEKS
+}
+
+// eksAPI is the part of the AWS client the EKS enricher uses
+type eksAPI interface {
+	DescribeEKSCluster(ctx context.Context, name string) (*aws.EKSCluster, error)
+	ListEKSVersions(ctx context.Context) ([]aws.EKSVersion, error)
+	ListEKSNodegroups(ctx context.Context, cluster string) ([]aws.EKSNodegroup, error)
+}
+
+// describeEKS fetches control plane, node group and support window details from EKS
+func describeEKS(ctx context.Context, ref CloudRef) (*CloudDetails, error) {
+	client, err := aws.NewClient("", ref.Location)
+	if err != nil {
+		return nil, err
+	}
+	return eksDetails(ctx, client, ref)
+}
+
+func eksDetails(ctx context.Context, client eksAPI, ref CloudRef) (*CloudDetails, error) {
+	cluster, err := client.DescribeEKSCluster(ctx, ref.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe EKS cluster: %w", err)
+	}
+
+	details := &CloudDetails{
+		Ref:                 ref,
+		ControlPlaneVersion: cluster.Version,
+		Channel:             strings.ToLower(cluster.SupportType),
+	}
+
+	versions, err := client.ListEKSVersions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list EKS versions: %w", err)
+	}
+
+	var offered []string
+	for _, v := range versions {
+		offered = append(offered, v.Version)
+		if v.Version != MinorVersion(cluster.Version) {
+			continue
+		}
+
+		// EKS force-upgrades clusters once their support period ends
+		end, period := v.EndOfExtendedSupportDate, "extended"
+		if cluster.SupportType == "STANDARD" {
+			end, period = v.EndOfStandardSupportDate, "standard"
+		}
+		if !end.IsZero() {
+			details.AutoUpgradeAt = end
+			details.AutoUpgrade = fmt.Sprintf("forced upgrade at end of %s support", period)
+		}
+	}
+	details.LatestVersion = latestVersion(offered)
+
+	nodegroups, err := client.ListEKSNodegroups(ctx, ref.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list node groups: %w", err)
+	}
+	for _, ng := range nodegroups {
+		details.NodePools = append(details.NodePools, CloudNodePool{
+			Name:         ng.Name,
+			Version:      ng.Version,
+			InstanceType: strings.Join(ng.InstanceTypes, ","),
+			Size:         ng.DesiredSize,
+			Status:       strings.ToLower(ng.Status),
+		})
+	}
+
+	return details, nil
+}
@@ -0,0 +1,63 @@
+//go:build eks
+
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/aws"
+)
+
+type fakeEKS struct {
+	cluster aws.EKSCluster
+}
+
+func (f *fakeEKS) DescribeEKSCluster(ctx context.Context, name string) (*aws.EKSCluster, error) {
+	return &f.cluster, nil
+}
+
+func (f *fakeEKS) ListEKSVersions(ctx context.Context) ([]aws.EKSVersion, error) {
+	return []aws.EKSVersion{
+		{Version: "1.30", EndOfStandardSupportDate: time.Date(2025, 7, 23, 0, 0, 0, 0, time.UTC), EndOfExtendedSupportDate: time.Date(2026, 7, 23, 0, 0, 0, 0, time.UTC)},
+		{Version: "1.31", Default: true},
+		{Version: "1.32"},
+	}, nil
+}
+
+func (f *fakeEKS) ListEKSNodegroups(ctx context.Context, cluster string) ([]aws.EKSNodegroup, error) {
+	return []aws.EKSNodegroup{
+		{Name: "system", Version: "1.30", InstanceTypes: []string{"m6i.large", "m5.large"}, DesiredSize: 3, Status: "ACTIVE"},
+	}, nil
+}
+
+func TestEKSDetails(t *testing.T) {
+	tests := []struct {
+		supportType string
+		wantAt      time.Time
+		wantPeriod  string
+	}{
+		{"STANDARD", time.Date(2025, 7, 23, 0, 0, 0, 0, time.UTC), "forced upgrade at end of standard support"},
+		{"EXTENDED", time.Date(2026, 7, 23, 0, 0, 0, 0, time.UTC), "forced upgrade at end of extended support"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.supportType, func(t *testing.T) {
+			client := &fakeEKS{cluster: aws.EKSCluster{Name: "prod", Version: "1.30", SupportType: tt.supportType}}
+			details, err := eksDetails(context.Background(), client, CloudRef{Provider: ProviderEKS, Name: "prod"})
+			if err != nil {
+				t.Fatalf("eksDetails: %v", err)
+			}
+
+			if details.ControlPlaneVersion != "1.30" || details.LatestVersion != "1.32" || !details.UpgradeAvailable() {
+				t.Errorf("versions = %s -> %s, want 1.30 -> 1.32", details.ControlPlaneVersion, details.LatestVersion)
+			}
+			if !details.AutoUpgradeAt.Equal(tt.wantAt) || details.AutoUpgrade != tt.wantPeriod {
+				t.Errorf("auto upgrade = %q at %s, want %q at %s", details.AutoUpgrade, details.AutoUpgradeAt, tt.wantPeriod, tt.wantAt)
+			}
+			if len(details.NodePools) != 1 || details.NodePools[0].InstanceType != "m6i.large,m5.large" || details.NodePools[0].Status != "active" {
+				t.Errorf("node pools = %+v", details.NodePools)
+			}
+		})
+	}
+}
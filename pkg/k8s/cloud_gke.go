@@ -0,0 +1,115 @@
+//go:build gke
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+func init() {
+	cloudEnrichers[ProviderGKE] = describeGKE
+}
+
+// describeGKE fetches control plane, node pool and maintenance details from GKE
+func describeGKE(ctx context.Context, ref CloudRef) (*CloudDetails, error) {
+	scope := []string{"--location", ref.Location, "--format", "json"}
+	if ref.Project != "" {
+		scope = append(scope, "--project", ref.Project)
+	}
+
+	var cluster struct {
+		CurrentMasterVersion string `json:"currentMasterVersion"`
+		ReleaseChannel       struct {
+			Channel string `json:"channel"`
+		} `json:"releaseChannel"`
+		MaintenancePolicy struct {
+			Window struct {
+				DailyMaintenanceWindow *struct {
+					StartTime string `json:"startTime"`
+					Duration  string `json:"duration"`
+				} `json:"dailyMaintenanceWindow"`
+				RecurringWindow *struct {
+					Window struct {
+						StartTime string `json:"startTime"`
+						EndTime   string `json:"endTime"`
+					} `json:"window"`
+					Recurrence string `json:"recurrence"`
+				} `json:"recurringWindow"`
+			} `json:"window"`
+		} `json:"maintenancePolicy"`
+		NodePools []struct {
+			Name             string `json:"name"`
+			Version          string `json:"version"`
+			Status           string `json:"status"`
+			InitialNodeCount int    `json:"initialNodeCount"`
+			Config           struct {
+				MachineType string `json:"machineType"`
+			} `json:"config"`
+			Management struct {
+				AutoUpgrade bool `json:"autoUpgrade"`
+			} `json:"management"`
+		} `json:"nodePools"`
+	}
+	args := append([]string{"container", "clusters", "describe", ref.Name}, scope...)
+	if err := runCloudCLI(ctx, &cluster, "gcloud", args...); err != nil {
+		return nil, fmt.Errorf("failed to describe GKE cluster: %w", err)
+	}
+
+	var serverConfig struct {
+		ValidMasterVersions []string `json:"validMasterVersions"`
+		Channels            []struct {
+			Channel        string   `json:"channel"`
+			DefaultVersion string   `json:"defaultVersion"`
+			ValidVersions  []string `json:"validVersions"`
+		} `json:"channels"`
+	}
+	args = append([]string{"container", "get-server-config"}, scope...)
+	if err := runCloudCLI(ctx, &serverConfig, "gcloud", args...); err != nil {
+		return nil, fmt.Errorf("failed to get GKE server config: %w", err)
+	}
+
+	details := &CloudDetails{
+		Ref:                 ref,
+		ControlPlaneVersion: cluster.CurrentMasterVersion,
+		Channel:             strings.ToLower(cluster.ReleaseChannel.Channel),
+		LatestVersion:       latestVersion(serverConfig.ValidMasterVersions),
+	}
+
+	// Clusters on a release channel are upgraded to the channel default automatically
+	for _, ch := range serverConfig.Channels {
+		if ch.Channel != cluster.ReleaseChannel.Channel {
+			continue
+		}
+		details.LatestVersion = latestVersion(ch.ValidVersions)
+		if CompareVersions(ch.DefaultVersion, cluster.CurrentMasterVersion) > 0 {
+			details.AutoUpgrade = fmt.Sprintf("auto-upgrade to %s in the next maintenance window", ch.DefaultVersion)
+		}
+	}
+
+	window := cluster.MaintenancePolicy.Window
+	switch {
+	case window.RecurringWindow != nil:
+		details.MaintenanceWindow = fmt.Sprintf("%s from %s to %s",
+			window.RecurringWindow.Recurrence, window.RecurringWindow.Window.StartTime, window.RecurringWindow.Window.EndTime)
+	case window.DailyMaintenanceWindow != nil:
+		details.MaintenanceWindow = fmt.Sprintf("daily at %s UTC (%s)",
+			window.DailyMaintenanceWindow.StartTime, window.DailyMaintenanceWindow.Duration)
+	default:
+		details.MaintenanceWindow = "any time"
+	}
+
+	for _, np := range cluster.NodePools {
+		details.NodePools = append(details.NodePools, CloudNodePool{
+			Name:         np.Name,
+			Version:      np.Version,
+			InstanceType: np.Config.MachineType,
+			Size:         np.InitialNodeCount,
+			Status:       strings.ToLower(np.Status),
+			AutoUpgrade:  np.Management.AutoUpgrade,
+		})
+	}
+
+	return details, nil
+}
@@ -0,0 +1,91 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ManagedByLabel marks resources created by the toolkit
+const ManagedByLabel = "app.kubernetes.io/managed-by"
+
+// debugPodLifetime makes the kubelet kill a debug pod that was never cleaned up
+const debugPodLifetime int64 = 3600
+
+// CreateDebugPod starts a sleeping pod with the given labels and waits until it is running
+func (c *Client) CreateDebugPod(ctx context.Context, namespace, image string, labels map[string]string) (string, error) {
+	podLabels := map[string]string{ManagedByLabel: "devops-toolkit"}
+	for k, v := range labels {
+		podLabels[k] = v
+	}
+
+	lifetime := debugPodLifetime
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "devops-toolkit-debug-",
+			Namespace:    namespace,
+			Labels:       podLabels,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			ActiveDeadlineSeconds:         &lifetime,
+			TerminationGracePeriodSeconds: new(int64),
+			Containers: []corev1.Container{{
+				Name:    "debug",
+				Image:   image,
+				Command: []string{"sleep", fmt.Sprintf("%d", lifetime)},
+			}},
+		},
+	}
+
+	created, err := c.clientset.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create debug pod: %w", err)
+	}
+
+	if err := c.waitForPodRunning(ctx, namespace, created.Name, 2*time.Minute); err != nil {
+		_ = c.DeletePod(context.Background(), namespace, created.Name)
+		return "", err
+	}
+	return created.Name, nil
+}
+
+// DeletePod deletes a pod immediately
+func (c *Client) DeletePod(ctx context.Context, namespace, name string) error {
+	grace := int64(0)
+	return c.clientset.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{GracePeriodSeconds: &grace})
+}
+
+func (c *Client) waitForPodRunning(ctx context.Context, namespace, name string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			switch pod.Status.Phase {
+			case corev1.PodRunning:
+				return nil
+			case corev1.PodFailed, corev1.PodSucceeded:
+				return fmt.Errorf("debug pod %s/%s exited (%s)", namespace, name, pod.Status.Phase)
+			}
+			for _, cs := range pod.Status.ContainerStatuses {
+				if w := cs.State.Waiting; w != nil && (w.Reason == "ImagePullBackOff" || w.Reason == "ErrImagePull") {
+					return fmt.Errorf("debug pod %s/%s cannot pull its image: %s", namespace, name, w.Message)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("debug pod %s/%s not running after %s", namespace, name, timeout)
+		case <-ticker.C:
+		}
+	}
+}
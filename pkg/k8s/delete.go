@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/batch"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeleteOptions control how pods are removed
+type DeleteOptions struct {
+	// Evict goes through the Eviction API so PodDisruptionBudgets are respected
+	Evict bool
+	// GracePeriod overrides the pod's terminationGracePeriodSeconds when non-nil
+	GracePeriod *int64
+}
+
+// DeletePods deletes or evicts the specified pods concurrently; failures are returned as batch.Errors
+func (c *Client) DeletePods(ctx context.Context, pods []PodInfo, opts DeleteOptions) (int, error) {
+	names := make([]string, len(pods))
+	errs := batch.Run(len(pods), batch.DefaultWorkers, func(i int) error {
+		names[i] = pods[i].Namespace + "/" + pods[i].Name
+		return c.deletePod(ctx, pods[i], opts)
+	})
+	return batch.Succeeded(errs), batch.Collect(names, errs)
+}
+
+func (c *Client) deletePod(ctx context.Context, pod PodInfo, opts DeleteOptions) error {
+	deleteOpts := metav1.DeleteOptions{GracePeriodSeconds: opts.GracePeriod}
+
+	var err error
+	if opts.Evict {
+		err = c.clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, &policyv1.Eviction{
+			ObjectMeta:    metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+			DeleteOptions: &deleteOpts,
+		})
+	} else {
+		err = c.clientset.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, deleteOpts)
+	}
+
+	if apierrors.IsTooManyRequests(err) {
+		return fmt.Errorf("eviction blocked by a PodDisruptionBudget")
+	}
+	return ignoreNotFound(err)
+}
+
+// ignoreNotFound treats objects that are already gone as deleted
+func ignoreNotFound(err error) error {
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,146 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DebugContainerOptions describe an ephemeral debug container
+type DebugContainerOptions struct {
+	Namespace string
+	Pod       string
+	Image     string
+	// Name defaults to debugger-<random>
+	Name string
+	// Target is the container whose process namespace is shared; the pod's
+	// first container when empty
+	Target  string
+	Command []string
+}
+
+// DebugContainer is an ephemeral container added to a pod
+type DebugContainer struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Name      string `json:"name"`
+	Target    string `json:"target"`
+	Image     string `json:"image"`
+}
+
+// AddDebugContainer adds an ephemeral container that shares the target
+// container's process namespace, and the pod's network and IPC namespaces,
+// and waits until it is running
+func (c *Client) AddDebugContainer(ctx context.Context, opts DebugContainerOptions) (*DebugContainer, error) {
+	pod, err := c.clientset.CoreV1().Pods(opts.Namespace).Get(ctx, opts.Pod, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s: %w", opts.Pod, err)
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return nil, fmt.Errorf("pod %s is %s; ephemeral containers need a running pod", opts.Pod, pod.Status.Phase)
+	}
+
+	target := opts.Target
+	if target == "" {
+		target = pod.Spec.Containers[0].Name
+	}
+	found := false
+	for _, container := range pod.Spec.Containers {
+		found = found || container.Name == target
+	}
+	if !found {
+		return nil, fmt.Errorf("pod %s has no container %s", opts.Pod, target)
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = fmt.Sprintf("debugger-%05d", rand.Intn(100000))
+	}
+	command := opts.Command
+	if len(command) == 0 {
+		command = []string{"sh"}
+	}
+
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:                     name,
+			Image:                    opts.Image,
+			Command:                  command,
+			Stdin:                    true,
+			TTY:                      true,
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+			ImagePullPolicy:          corev1.PullIfNotPresent,
+		},
+		TargetContainerName: target,
+	})
+
+	_, err = c.clientset.CoreV1().Pods(opts.Namespace).UpdateEphemeralContainers(ctx, opts.Pod, pod, metav1.UpdateOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("the cluster does not support ephemeral containers (Kubernetes 1.23+ required)")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to add debug container: %w", err)
+	}
+
+	if err := c.waitForEphemeralContainer(ctx, opts.Namespace, opts.Pod, name, 2*time.Minute); err != nil {
+		return nil, err
+	}
+	return &DebugContainer{Namespace: opts.Namespace, Pod: opts.Pod, Name: name, Target: target, Image: opts.Image}, nil
+}
+
+func (c *Client) waitForEphemeralContainer(ctx context.Context, namespace, pod, name string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		p, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, pod, metav1.GetOptions{})
+		if err == nil {
+			for _, cs := range p.Status.EphemeralContainerStatuses {
+				if cs.Name != name {
+					continue
+				}
+				switch {
+				case cs.State.Running != nil:
+					return nil
+				case cs.State.Terminated != nil:
+					return fmt.Errorf("debug container %s exited (%s)", name, cs.State.Terminated.Reason)
+				case cs.State.Waiting != nil && podStartErrors[cs.State.Waiting.Reason]:
+					return fmt.Errorf("debug container %s cannot start: %s: %s", name, cs.State.Waiting.Reason, cs.State.Waiting.Message)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("debug container %s not running after %s", name, timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// AttachDebugContainer attaches the terminal to a debug container; the
+// container stops when its shell exits
+func (c *Client) AttachDebugContainer(ctx context.Context, dc *DebugContainer, tty bool) error {
+	return c.interactive(ctx, "attach", dc.Namespace, dc.Pod, &corev1.PodAttachOptions{
+		Container: dc.Name,
+		Stdin:     true,
+		Stdout:    true,
+		Stderr:    !tty,
+		TTY:       tty,
+	}, tty)
+}
+
+// AttachCommand is the kubectl command that re-attaches to a debug container
+func (c *Client) AttachCommand(dc *DebugContainer) string {
+	args := c.kubectlArgs("attach", "-it", "-n", dc.Namespace, dc.Pod, "-c", dc.Name)
+	return "kubectl " + strings.Join(args, " ")
+}
@@ -0,0 +1,19 @@
+package k8s
+
+import (
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func init() {
+	exitcode.RegisterClassifier(classifyError)
+}
+
+// classifyError marks rejected cluster credentials as a config error, since
+// they need a kubeconfig change rather than a retry
+func classifyError(err error) error {
+	if apierrors.IsUnauthorized(err) {
+		return exitcode.ConfigError(err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// etcdSelector matches the static etcd pods created by kubeadm and most self-hosted installers
+const etcdSelector = "component=etcd"
+
+// EtcdPod is an etcd member running as a pod, with the client TLS settings taken from its flags
+type EtcdPod struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Node      string `json:"node"`
+	Container string `json:"container"`
+	Ready     bool   `json:"ready"`
+	Endpoint  string `json:"endpoint"`
+	DataDir   string `json:"data_dir"`
+	CACert    string `json:"cacert"`
+	Cert      string `json:"cert"`
+	Key       string `json:"key"`
+}
+
+// ListEtcdPods finds etcd members running in kube-system
+func (c *Client) ListEtcdPods(ctx context.Context) ([]EtcdPod, error) {
+	pods, err := c.clientset.CoreV1().Pods("kube-system").List(ctx, metav1.ListOptions{
+		LabelSelector: etcdSelector,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []EtcdPod
+	for _, pod := range pods.Items {
+		if len(pod.Spec.Containers) == 0 {
+			continue
+		}
+		container := pod.Spec.Containers[0]
+		flags := parseFlags(append(container.Command, container.Args...))
+
+		member := EtcdPod{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+			Node:      pod.Spec.NodeName,
+			Container: container.Name,
+			Ready:     isPodReady(pod),
+			Endpoint:  localClientURL(flags["listen-client-urls"]),
+			DataDir:   flags["data-dir"],
+			CACert:    flags["trusted-ca-file"],
+			Cert:      flags["cert-file"],
+			Key:       flags["key-file"],
+		}
+		if member.DataDir == "" {
+			member.DataDir = "/var/lib/etcd"
+		}
+		result = append(result, member)
+	}
+
+	return result, nil
+}
+
+// CopyFromPod copies a file out of a pod through kubectl cp, which needs tar in the container
+func (c *Client) CopyFromPod(ctx context.Context, namespace, pod, container, src, dst string) error {
+	kubectl, err := exec.LookPath("kubectl")
+	if err != nil {
+		return fmt.Errorf("kubectl not found in PATH")
+	}
+
+	args := c.kubectlArgs("cp", fmt.Sprintf("%s/%s:%s", namespace, pod, src), dst)
+	if container != "" {
+		args = append(args, "-c", container)
+	}
+
+	out, err := exec.CommandContext(ctx, kubectl, args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("kubectl cp: %s", msg)
+		}
+		return fmt.Errorf("kubectl cp: %w", err)
+	}
+	return nil
+}
+
+// parseFlags collects --name=value flags from a container command line
+func parseFlags(args []string) map[string]string {
+	flags := make(map[string]string)
+	for _, arg := range args {
+		name, value, ok := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if ok && strings.HasPrefix(arg, "--") {
+			flags[name] = value
+		}
+	}
+	return flags
+}
+
+// localClientURL picks the loopback client URL, which is always covered by the server certificate
+func localClientURL(urls string) string {
+	var first string
+	for _, u := range strings.Split(urls, ",") {
+		u = strings.TrimSpace(u)
+		if u == "" {
+			continue
+		}
+		if strings.Contains(u, "127.0.0.1") || strings.Contains(u, "localhost") {
+			return u
+		}
+		if first == "" {
+			first = u
+		}
+	}
+	if first == "" {
+		return "https://127.0.0.1:2379"
+	}
+	return first
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
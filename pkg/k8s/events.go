@@ -0,0 +1,170 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// eventSelector holds the exact-match filters the API server applies, so
+// busy clusters don't send every event only for the client to drop most
+type eventSelector struct {
+	Type   string
+	Reason string
+	Object string
+}
+
+// fieldSelector builds the selector for the events.k8s.io (v1) or core API,
+// which name the involved object differently
+func (s eventSelector) fieldSelector(v1 bool) string {
+	set := fields.Set{}
+	if s.Type != "" {
+		set["type"] = s.Type
+	}
+	if s.Reason != "" {
+		set["reason"] = s.Reason
+	}
+	if s.Object != "" {
+		if v1 {
+			set["regarding.name"] = s.Object
+		} else {
+			set["involvedObject.name"] = s.Object
+		}
+	}
+	return fields.SelectorFromSet(set).String()
+}
+
+// listEvents lists events from events.k8s.io/v1, falling back to the core v1
+// API on clusters or RBAC roles that only serve the deprecated one. Events
+// last seen before since are dropped when since is set. Repeated events for
+// the same object, reason and message are merged and the result is sorted
+// newest first.
+func (c *Client) listEvents(ctx context.Context, namespace string, selector eventSelector, since time.Time) ([]EventInfo, error) {
+	var result []EventInfo
+	events, err := c.clientset.EventsV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: selector.fieldSelector(true),
+	})
+	switch {
+	case err == nil:
+		for _, event := range events.Items {
+			result = append(result, eventFromV1(event))
+		}
+	case apierrors.IsNotFound(err) || apierrors.IsForbidden(err):
+		coreEvents, err := c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: selector.fieldSelector(false),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range coreEvents.Items {
+			result = append(result, eventFromCore(event))
+		}
+	default:
+		return nil, err
+	}
+
+	if !since.IsZero() {
+		recent := result[:0]
+		for _, event := range result {
+			if !event.LastTimestamp.Before(since) {
+				recent = append(recent, event)
+			}
+		}
+		result = recent
+	}
+
+	result = dedupEvents(result)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].LastTimestamp.After(result[j].LastTimestamp)
+	})
+	return result, nil
+}
+
+func eventFromV1(event eventsv1.Event) EventInfo {
+	info := EventInfo{
+		Namespace: event.Namespace,
+		Type:      event.Type,
+		Reason:    event.Reason,
+		Object:    event.Regarding.Name,
+		Kind:      event.Regarding.Kind,
+		Message:   event.Note,
+		Count:     event.DeprecatedCount,
+	}
+
+	// Series replaces count and lastTimestamp for events recorded by the new API
+	var series time.Time
+	if event.Series != nil {
+		info.Count = event.Series.Count
+		series = event.Series.LastObservedTime.Time
+	}
+	if info.Count == 0 {
+		info.Count = 1
+	}
+	info.LastTimestamp = latest(series, event.DeprecatedLastTimestamp.Time,
+		event.EventTime.Time, event.DeprecatedFirstTimestamp.Time, event.CreationTimestamp.Time)
+	return info
+}
+
+func eventFromCore(event corev1.Event) EventInfo {
+	info := EventInfo{
+		Namespace: event.Namespace,
+		Type:      event.Type,
+		Reason:    event.Reason,
+		Object:    event.InvolvedObject.Name,
+		Kind:      event.InvolvedObject.Kind,
+		Message:   event.Message,
+		Count:     event.Count,
+	}
+
+	var series time.Time
+	if event.Series != nil {
+		info.Count = event.Series.Count
+		series = event.Series.LastObservedTime.Time
+	}
+	if info.Count == 0 {
+		info.Count = 1
+	}
+	info.LastTimestamp = latest(series, event.LastTimestamp.Time,
+		event.EventTime.Time, event.FirstTimestamp.Time, event.CreationTimestamp.Time)
+	return info
+}
+
+// latest returns the first non-zero time. Events recorded through the new
+// API leave lastTimestamp empty, which made them sort last and show no age.
+func latest(times ...time.Time) time.Time {
+	for _, t := range times {
+		if !t.IsZero() {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dedupEvents merges events about the same object with the same reason and
+// message, which controllers emit as separate objects once a series expires
+func dedupEvents(events []EventInfo) []EventInfo {
+	type key struct{ namespace, kind, object, eventType, reason, message string }
+
+	index := make(map[key]int)
+	var result []EventInfo
+	for _, event := range events {
+		k := key{event.Namespace, event.Kind, event.Object, event.Type, event.Reason, event.Message}
+		i, ok := index[k]
+		if !ok {
+			index[k] = len(result)
+			result = append(result, event)
+			continue
+		}
+		result[i].Count += event.Count
+		if event.LastTimestamp.After(result[i].LastTimestamp) {
+			result[i].LastTimestamp = event.LastTimestamp
+		}
+	}
+	return result
+}
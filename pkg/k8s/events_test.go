@@ -0,0 +1,150 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var eventsResource = schema.GroupResource{Group: "events.k8s.io", Resource: "events"}
+
+func coreEvent(name, object, reason, message string, count int32, last time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: object, Namespace: "default"},
+		Type:           "Warning",
+		Reason:         reason,
+		Message:        message,
+		Count:          count,
+		LastTimestamp:  metav1.NewTime(last),
+	}
+}
+
+// failEventsV1 makes events.k8s.io list calls fail while core events still work
+func failEventsV1(err error) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetResource().Group == eventsResource.Group {
+			return true, nil, err
+		}
+		return false, nil, nil
+	}
+}
+
+func TestListEventsV1(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	c, _ := newFakeClient(
+		&eventsv1.Event{
+			ObjectMeta: metav1.ObjectMeta{Name: "web.1", Namespace: "default"},
+			Regarding:  corev1.ObjectReference{Kind: "Pod", Name: "web"},
+			Type:       "Warning",
+			Reason:     "BackOff",
+			Note:       "Back-off restarting failed container",
+			EventTime:  metav1.NewMicroTime(now.Add(-10 * time.Minute)),
+			Series:     &eventsv1.EventSeries{Count: 7, LastObservedTime: metav1.NewMicroTime(now.Add(-time.Minute))},
+		},
+		&eventsv1.Event{
+			ObjectMeta:              metav1.ObjectMeta{Name: "db.1", Namespace: "default"},
+			Regarding:               corev1.ObjectReference{Kind: "Pod", Name: "db"},
+			Type:                    "Warning",
+			Reason:                  "FailedMount",
+			Note:                    "volume not attached",
+			DeprecatedCount:         2,
+			DeprecatedLastTimestamp: metav1.NewTime(now.Add(-5 * time.Minute)),
+		},
+	)
+
+	events, err := c.ListEvents(context.Background(), "default", EventFilter{})
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if e := events[0]; e.Object != "web" || e.Count != 7 || !e.LastTimestamp.Equal(now.Add(-time.Minute)) {
+		t.Errorf("newest event = %+v, want the web series seen a minute ago", e)
+	}
+	if e := events[1]; e.Object != "db" || e.Count != 2 || e.Message != "volume not attached" {
+		t.Errorf("second event = %+v, want the db mount failure", e)
+	}
+}
+
+func TestListEventsFallsBackToCore(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	for _, tt := range []struct {
+		name string
+		err  error
+	}{
+		{"not served", apierrors.NewNotFound(eventsResource, "")},
+		{"forbidden", apierrors.NewForbidden(eventsResource, "", errors.New("RBAC: access denied"))},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, clientset := newFakeClient(
+				coreEvent("web.1", "web", "BackOff", "Back-off restarting failed container", 3, now.Add(-2*time.Minute)),
+			)
+			clientset.PrependReactor("list", "events", failEventsV1(tt.err))
+
+			events, err := c.ListEvents(context.Background(), "default", EventFilter{})
+			if err != nil {
+				t.Fatalf("ListEvents: %v", err)
+			}
+			if len(events) != 1 || events[0].Object != "web" || events[0].Count != 3 {
+				t.Errorf("events = %+v, want the core web event", events)
+			}
+		})
+	}
+}
+
+func TestListEventsReturnsOtherErrors(t *testing.T) {
+	c, clientset := newFakeClient(coreEvent("web.1", "web", "BackOff", "", 1, time.Now()))
+	clientset.PrependReactor("list", "events", failEventsV1(apierrors.NewServiceUnavailable("etcd leader changed")))
+
+	if _, err := c.ListEvents(context.Background(), "default", EventFilter{}); !apierrors.IsServiceUnavailable(err) {
+		t.Errorf("err = %v, want the service unavailable error instead of a fallback", err)
+	}
+}
+
+func TestListEventsDedup(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	c, clientset := newFakeClient(
+		coreEvent("web.1", "web", "BackOff", "Back-off restarting failed container", 4, now.Add(-30*time.Minute)),
+		coreEvent("web.2", "web", "BackOff", "Back-off restarting failed container", 2, now.Add(-3*time.Minute)),
+		coreEvent("web.3", "web", "Unhealthy", "Liveness probe failed", 1, now.Add(-10*time.Minute)),
+		coreEvent("api.1", "api", "BackOff", "Back-off restarting failed container", 1, now.Add(-5*time.Minute)),
+		coreEvent("old.1", "old", "BackOff", "Back-off restarting failed container", 9, now.Add(-3*time.Hour)),
+	)
+	clientset.PrependReactor("list", "events", failEventsV1(apierrors.NewNotFound(eventsResource, "")))
+
+	events, err := c.ListEvents(context.Background(), "default", EventFilter{Since: time.Hour})
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+
+	want := []struct {
+		object, reason string
+		count          int32
+		last           time.Time
+	}{
+		{"web", "BackOff", 6, now.Add(-3 * time.Minute)},
+		{"api", "BackOff", 1, now.Add(-5 * time.Minute)},
+		{"web", "Unhealthy", 1, now.Add(-10 * time.Minute)},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.Object != w.object || e.Reason != w.reason || e.Count != w.count || !e.LastTimestamp.Equal(w.last) {
+			t.Errorf("event %d = %s/%s x%d at %s, want %s/%s x%d at %s", i,
+				e.Object, e.Reason, e.Count, e.LastTimestamp, w.object, w.reason, w.count, w.last)
+		}
+	}
+}
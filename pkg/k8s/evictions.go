@@ -0,0 +1,222 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Eviction reasons
+const (
+	EvictionNodePressure = "NodePressure"
+	EvictionPreemption   = "Preemption"
+	EvictionAPI          = "EvictionAPI"
+	EvictionTaint        = "NoExecuteTaint"
+	EvictionOther        = "Evicted"
+)
+
+// EvictionRecord is a pod that was evicted or preempted
+type EvictionRecord struct {
+	Time      time.Time `json:"time"`
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	Node      string    `json:"node,omitempty"`
+	Reason    string    `json:"reason"`
+	Resource  string    `json:"resource,omitempty"`
+	Message   string    `json:"message"`
+	// NodeConditions are the node's unhealthy conditions at the time of the eviction
+	NodeConditions []string `json:"node_conditions,omitempty"`
+}
+
+// Evictions returns pods evicted or preempted since the given time, from pod status and events.
+// Events usually expire after an hour, so older preemptions are only found while the pod object remains.
+func (c *Client) Evictions(ctx context.Context, namespace string, since time.Time) ([]EvictionRecord, error) {
+	records := make(map[string]EvictionRecord)
+
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		if !c.inScope(pod.Namespace) {
+			continue
+		}
+		if rec, ok := podEviction(pod); ok && !rec.Time.Before(since) {
+			records[rec.Namespace+"/"+rec.Pod] = rec
+		}
+	}
+
+	events, err := c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "involvedObject.kind=Pod",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	for _, event := range events.Items {
+		if !c.inScope(event.InvolvedObject.Namespace) {
+			continue
+		}
+		rec, ok := eventEviction(event)
+		if !ok || rec.Time.Before(since) {
+			continue
+		}
+		key := rec.Namespace + "/" + rec.Pod
+		if existing, found := records[key]; found {
+			// Pod status has the final reason; events may know the node of a deleted pod
+			if existing.Node == "" {
+				existing.Node = rec.Node
+				records[key] = existing
+			}
+			continue
+		}
+		records[key] = rec
+	}
+
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	nodeConditions := make(map[string][]corev1.NodeCondition)
+	for _, node := range nodes.Items {
+		nodeConditions[node.Name] = node.Status.Conditions
+	}
+
+	result := make([]EvictionRecord, 0, len(records))
+	for _, rec := range records {
+		rec.NodeConditions = conditionsAt(nodeConditions[rec.Node], rec.Time)
+		result = append(result, rec)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time.After(result[j].Time)
+	})
+	return result, nil
+}
+
+// podEviction classifies a pod that the kubelet evicted or that carries a DisruptionTarget condition
+func podEviction(pod corev1.Pod) (EvictionRecord, bool) {
+	rec := EvictionRecord{
+		Namespace: pod.Namespace,
+		Pod:       pod.Name,
+		Node:      pod.Spec.NodeName,
+		Message:   pod.Status.Message,
+		Time:      pod.CreationTimestamp.Time,
+	}
+
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type != corev1.DisruptionTarget || cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		rec.Time = cond.LastTransitionTime.Time
+		if rec.Message == "" {
+			rec.Message = cond.Message
+		}
+		switch cond.Reason {
+		case "PreemptionByScheduler", "PreemptionByKubeScheduler":
+			rec.Reason = EvictionPreemption
+		case "TerminationByKubelet":
+			rec.Reason = EvictionNodePressure
+		case "EvictionByEvictionAPI":
+			rec.Reason = EvictionAPI
+		case "DeletionByTaintManager":
+			rec.Reason = EvictionTaint
+		default:
+			rec.Reason = EvictionOther
+		}
+	}
+
+	if pod.Status.Reason == "Evicted" {
+		rec.Resource = pressureResource(pod.Status.Message)
+		if rec.Reason == "" || rec.Reason == EvictionOther {
+			rec.Reason = EvictionOther
+			if rec.Resource != "" {
+				rec.Reason = EvictionNodePressure
+			}
+		}
+		if t := lastTransition(pod.Status.Conditions); t.After(rec.Time) {
+			rec.Time = t
+		}
+	}
+
+	return rec, rec.Reason != ""
+}
+
+// eventEviction classifies kubelet Evicted and scheduler Preempted events
+func eventEviction(event corev1.Event) (EvictionRecord, bool) {
+	rec := EvictionRecord{
+		Time:      eventTime(event),
+		Namespace: event.InvolvedObject.Namespace,
+		Pod:       event.InvolvedObject.Name,
+		Message:   event.Message,
+	}
+
+	switch event.Reason {
+	case "Evicted":
+		rec.Reason = EvictionOther
+		rec.Node = event.Source.Host
+		if rec.Resource = pressureResource(event.Message); rec.Resource != "" {
+			rec.Reason = EvictionNodePressure
+		}
+	case "Preempted":
+		rec.Reason = EvictionPreemption
+		// "Preempted by <namespace>/<pod> on node <node>"
+		if _, node, ok := strings.Cut(event.Message, " on node "); ok {
+			rec.Node = strings.TrimSpace(node)
+		}
+	default:
+		return rec, false
+	}
+
+	return rec, true
+}
+
+// pressureResource extracts the starved resource from a kubelet eviction message
+func pressureResource(message string) string {
+	const prefix = "The node was low on resource: "
+	_, rest, ok := strings.Cut(message, prefix)
+	if !ok {
+		return ""
+	}
+	resource, _, _ := strings.Cut(rest, ".")
+	return strings.TrimSpace(resource)
+}
+
+func lastTransition(conditions []corev1.PodCondition) time.Time {
+	var latest time.Time
+	for _, cond := range conditions {
+		if cond.LastTransitionTime.After(latest) {
+			latest = cond.LastTransitionTime.Time
+		}
+	}
+	return latest
+}
+
+// conditionsAt reconstructs which node conditions were unhealthy at a point in time.
+// A condition that last transitioned after t held the opposite status at t.
+func conditionsAt(conditions []corev1.NodeCondition, t time.Time) []string {
+	var unhealthy []string
+	for _, cond := range conditions {
+		status := cond.Status
+		if cond.LastTransitionTime.Time.After(t) {
+			switch status {
+			case corev1.ConditionTrue:
+				status = corev1.ConditionFalse
+			case corev1.ConditionFalse:
+				status = corev1.ConditionTrue
+			}
+		}
+
+		switch {
+		case cond.Type == corev1.NodeReady && status != corev1.ConditionTrue:
+			unhealthy = append(unhealthy, "NotReady")
+		case cond.Type != corev1.NodeReady && status == corev1.ConditionTrue:
+			unhealthy = append(unhealthy, string(cond.Type))
+		}
+	}
+	return unhealthy
+}
@@ -0,0 +1,105 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+	"k8s.io/kubectl/pkg/util/term"
+)
+
+// Exec runs a command in a pod and returns its combined output and exit code
+func (c *Client) Exec(ctx context.Context, namespace, pod, container string, command []string) (string, int, error) {
+	var out bytes.Buffer
+	err := c.stream(ctx, "exec", namespace, pod, &corev1.PodExecOptions{
+		Container: container,
+		Command:   command,
+		Stdout:    true,
+		Stderr:    true,
+	}, remotecommand.StreamOptions{Stdout: &out, Stderr: &out})
+
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) && exitErr.Exited() {
+		return strings.TrimSpace(out.String()), exitErr.ExitStatus(), nil
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("exec in %s/%s failed: %w", namespace, pod, err)
+	}
+	return strings.TrimSpace(out.String()), 0, nil
+}
+
+// interactive connects the terminal's stdio to a pod's exec or attach
+// subresource, putting the terminal in raw mode and forwarding resizes when
+// tty is set; the exit status of the remote command is not an error
+func (c *Client) interactive(ctx context.Context, subresource, namespace, pod string, opts runtime.Object, tty bool) error {
+	t := term.TTY{In: os.Stdin, Out: os.Stdout, Raw: tty}
+	streams := remotecommand.StreamOptions{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr, Tty: tty}
+	if tty {
+		// The API server merges stderr into stdout for a TTY
+		streams.Stderr = nil
+		streams.TerminalSizeQueue = t.MonitorSize(t.GetSize())
+	}
+
+	err := t.Safe(func() error {
+		return c.stream(ctx, subresource, namespace, pod, opts, streams)
+	})
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s to %s/%s failed: %w", subresource, namespace, pod, err)
+	}
+	return nil
+}
+
+// stream runs a pod's exec or attach subresource over WebSocket, falling back
+// to SPDY on API servers that cannot upgrade the connection
+func (c *Client) stream(ctx context.Context, subresource, namespace, pod string, opts runtime.Object, streams remotecommand.StreamOptions) error {
+	if c.config == nil {
+		return fmt.Errorf("%s needs a REST config for the cluster", subresource)
+	}
+
+	req := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource(subresource).
+		VersionedParams(opts, scheme.ParameterCodec)
+
+	spdy, err := remotecommand.NewSPDYExecutor(c.config, http.MethodPost, req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to create SPDY executor: %w", err)
+	}
+	websocket, err := remotecommand.NewWebSocketExecutor(c.config, http.MethodGet, req.URL().String())
+	if err != nil {
+		return fmt.Errorf("failed to create WebSocket executor: %w", err)
+	}
+	executor, err := remotecommand.NewFallbackExecutor(websocket, spdy, httpstream.IsUpgradeFailure)
+	if err != nil {
+		return err
+	}
+	return executor.StreamWithContext(ctx, streams)
+}
+
+// kubectlArgs prefixes args with the client's kubeconfig and context
+func (c *Client) kubectlArgs(args ...string) []string {
+	var prefix []string
+	if c.kubeconfig != "" {
+		prefix = append(prefix, "--kubeconfig", c.kubeconfig)
+	}
+	if c.contextName != "" {
+		prefix = append(prefix, "--context", c.contextName)
+	}
+	return append(prefix, args...)
+}
@@ -0,0 +1,71 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodImage is an image run by pod containers
+type PodImage struct {
+	// Image is the reference as written in the pod spec
+	Image string `json:"image"`
+	// Digest is the sha256 digest the kubelet resolved, empty until a
+	// container using the image has started
+	Digest string `json:"digest,omitempty"`
+}
+
+// ListPodImages returns the distinct images of the containers and init
+// containers of running and pending pods
+func (c *Client) ListPodImages(ctx context.Context, namespace string) ([]PodImage, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	seen := make(map[PodImage]bool)
+	var images []PodImage
+	for _, pod := range pods.Items {
+		if !c.inScope(pod.Namespace) {
+			continue
+		}
+
+		// Status image IDs carry the digest, keyed by container name
+		digests := make(map[string]string)
+		for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+			for _, cs := range statuses {
+				digests[cs.Name] = imageDigest(cs.ImageID)
+			}
+		}
+
+		for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+			for _, container := range containers {
+				img := PodImage{Image: container.Image, Digest: digests[container.Name]}
+				if !seen[img] {
+					seen[img] = true
+					images = append(images, img)
+				}
+			}
+		}
+	}
+
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Image < images[j].Image
+	})
+	return images, nil
+}
+
+// imageDigest extracts sha256:... from a container status image ID such as
+// docker-pullable://nginx@sha256:... or docker.io/library/nginx@sha256:...
+func imageDigest(imageID string) string {
+	if i := strings.LastIndex(imageID, "sha256:"); i >= 0 {
+		return imageID[i:]
+	}
+	return ""
+}
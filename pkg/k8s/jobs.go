@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultSuccessfulJobsHistoryLimit is the CronJob default for successfulJobsHistoryLimit
+const defaultSuccessfulJobsHistoryLimit = 3
+
+// JobTTLSuggestion recommends settings that stop completed Jobs from accumulating
+type JobTTLSuggestion struct {
+	Kind          string `json:"kind"`
+	Namespace     string `json:"namespace"`
+	Name          string `json:"name,omitempty"`
+	CompletedJobs int    `json:"completed_jobs"`
+	Issue         string `json:"issue"`
+	Remediation   string `json:"remediation"`
+	Patch         string `json:"patch,omitempty"`
+}
+
+// SuggestJobTTL inspects completed Jobs and their CronJobs and suggests history limits or
+// ttlSecondsAfterFinished where at least threshold completed Jobs have piled up
+func (c *Client) SuggestJobTTL(ctx context.Context, jobs []JobInfo, threshold int, ttlSeconds int) ([]JobTTLSuggestion, error) {
+	cronJobs := make(map[string]int)
+	standalone := make(map[string]int)
+	for _, job := range jobs {
+		switch {
+		case job.CronJob != "":
+			cronJobs[job.Namespace+"/"+job.CronJob]++
+		case !job.HasTTL:
+			standalone[job.Namespace]++
+		}
+	}
+
+	var result []JobTTLSuggestion
+	for _, key := range sortedKeys(cronJobs) {
+		count := cronJobs[key]
+		if count < threshold {
+			continue
+		}
+		namespace, name, _ := strings.Cut(key, "/")
+
+		cj, err := c.clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			// The CronJob is gone; its Jobs are orphans and cleanup removes them
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cronjob %s: %w", key, err)
+		}
+
+		limit := defaultSuccessfulJobsHistoryLimit
+		if cj.Spec.SuccessfulJobsHistoryLimit != nil {
+			limit = int(*cj.Spec.SuccessfulJobsHistoryLimit)
+		}
+		hasTTL := cj.Spec.JobTemplate.Spec.TTLSecondsAfterFinished != nil
+		if limit <= defaultSuccessfulJobsHistoryLimit && hasTTL {
+			continue
+		}
+
+		issue := fmt.Sprintf("successfulJobsHistoryLimit is %d", limit)
+		if !hasTTL {
+			issue += ", jobTemplate has no ttlSecondsAfterFinished"
+		}
+		result = append(result, JobTTLSuggestion{
+			Kind:          "CronJob",
+			Namespace:     namespace,
+			Name:          name,
+			CompletedJobs: count,
+			Issue:         issue,
+			Remediation: fmt.Sprintf("Set successfulJobsHistoryLimit to %d and jobTemplate.spec.ttlSecondsAfterFinished to %d",
+				defaultSuccessfulJobsHistoryLimit, ttlSeconds),
+			Patch: fmt.Sprintf(`kubectl patch cronjob %s -n %s --type merge -p '{"spec":{"successfulJobsHistoryLimit":%d,"jobTemplate":{"spec":{"ttlSecondsAfterFinished":%d}}}}'`,
+				name, namespace, defaultSuccessfulJobsHistoryLimit, ttlSeconds),
+		})
+	}
+
+	for _, namespace := range sortedKeys(standalone) {
+		count := standalone[namespace]
+		if count < threshold {
+			continue
+		}
+		result = append(result, JobTTLSuggestion{
+			Kind:          "Job",
+			Namespace:     namespace,
+			CompletedJobs: count,
+			Issue:         "completed Jobs have no ttlSecondsAfterFinished",
+			Remediation: fmt.Sprintf("Set spec.ttlSecondsAfterFinished: %d in the manifests or controller that create these Jobs",
+				ttlSeconds),
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CompletedJobs > result[j].CompletedJobs
+	})
+	return result, nil
+}
@@ -0,0 +1,317 @@
+package k8s
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Kubeconfig finding severities
+const (
+	FindingCritical = "critical"
+	FindingWarning  = "warning"
+	FindingInfo     = "info"
+)
+
+// longLivedCert is the validity beyond which a client certificate counts as long-lived
+const longLivedCert = 365 * 24 * time.Hour
+
+// KubeconfigFinding is a single hygiene issue in a kubeconfig file
+type KubeconfigFinding struct {
+	File     string `json:"file"`
+	Severity string `json:"severity"`
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Check    string `json:"check"`
+	Message  string `json:"message"`
+	Cleanup  string `json:"cleanup,omitempty"`
+}
+
+// KubeconfigAuditOptions control the kubeconfig audit
+type KubeconfigAuditOptions struct {
+	WarnDays          int
+	CheckConnectivity bool
+	Timeout           time.Duration
+}
+
+// KubeconfigPaths returns the files kubectl would load: KUBECONFIG entries or ~/.kube/config
+func KubeconfigPaths() []string {
+	if env := os.Getenv("KUBECONFIG"); env != "" {
+		var paths []string
+		for _, p := range filepath.SplitList(env) {
+			if p != "" {
+				paths = append(paths, p)
+			}
+		}
+		return paths
+	}
+	home, _ := os.UserHomeDir()
+	return []string{filepath.Join(home, ".kube", "config")}
+}
+
+// AuditKubeconfig inspects a kubeconfig file for expiring certificates, plaintext credentials,
+// duplicate or orphaned entries and unreachable servers
+func AuditKubeconfig(ctx context.Context, path string, opts KubeconfigAuditOptions) ([]KubeconfigFinding, error) {
+	config, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	if err := clientcmd.ResolveLocalPaths(config); err != nil {
+		return nil, fmt.Errorf("failed to resolve paths in %s: %w", path, err)
+	}
+
+	a := &kubeconfigAuditor{path: path, config: config, opts: opts}
+	a.checkContexts()
+	a.checkClusters()
+	a.checkUsers()
+	if opts.CheckConnectivity {
+		a.checkReachability(ctx)
+	}
+
+	sort.SliceStable(a.findings, func(i, j int) bool {
+		return findingRank(a.findings[i].Severity) > findingRank(a.findings[j].Severity)
+	})
+	return a.findings, nil
+}
+
+type kubeconfigAuditor struct {
+	path     string
+	config   *clientcmdapi.Config
+	opts     KubeconfigAuditOptions
+	findings []KubeconfigFinding
+}
+
+func (a *kubeconfigAuditor) add(severity, kind, name, check, message, cleanup string) {
+	a.findings = append(a.findings, KubeconfigFinding{
+		File:     a.path,
+		Severity: severity,
+		Kind:     kind,
+		Name:     name,
+		Check:    check,
+		Message:  message,
+		Cleanup:  cleanup,
+	})
+}
+
+func (a *kubeconfigAuditor) checkContexts() {
+	if a.config.CurrentContext != "" && a.config.Contexts[a.config.CurrentContext] == nil {
+		a.add(FindingWarning, "context", a.config.CurrentContext, "missing-current-context",
+			"current-context points to a context that does not exist", "kubectl config unset current-context")
+	}
+
+	for _, name := range sortedKeys(a.config.Contexts) {
+		ctx := a.config.Contexts[name]
+		if a.config.Clusters[ctx.Cluster] == nil {
+			a.add(FindingWarning, "context", name, "dangling-context",
+				fmt.Sprintf("references missing cluster %q", ctx.Cluster), "kubectl config delete-context "+name)
+			continue
+		}
+		if ctx.AuthInfo != "" && a.config.AuthInfos[ctx.AuthInfo] == nil {
+			a.add(FindingWarning, "context", name, "dangling-context",
+				fmt.Sprintf("references missing user %q", ctx.AuthInfo), "kubectl config delete-context "+name)
+		}
+	}
+}
+
+func (a *kubeconfigAuditor) checkClusters() {
+	used := make(map[string]bool)
+	for _, ctx := range a.config.Contexts {
+		used[ctx.Cluster] = true
+	}
+
+	byServer := make(map[string][]string)
+	for _, name := range sortedKeys(a.config.Clusters) {
+		cluster := a.config.Clusters[name]
+		server := strings.TrimSuffix(cluster.Server, "/")
+		byServer[server] = append(byServer[server], name)
+
+		if !used[name] {
+			a.add(FindingInfo, "cluster", name, "orphaned-cluster",
+				"not used by any context", "kubectl config delete-cluster "+name)
+		}
+		if cluster.InsecureSkipTLSVerify {
+			a.add(FindingWarning, "cluster", name, "insecure-tls",
+				"insecure-skip-tls-verify disables server certificate checks", "")
+		}
+		if strings.HasPrefix(cluster.Server, "http://") {
+			a.add(FindingCritical, "cluster", name, "plaintext-server",
+				"API server is reached over plain HTTP", "")
+		}
+	}
+
+	for _, server := range sortedKeys(byServer) {
+		names := byServer[server]
+		if server == "" || len(names) < 2 {
+			continue
+		}
+		for _, name := range names[1:] {
+			a.add(FindingWarning, "cluster", name, "duplicate-cluster",
+				fmt.Sprintf("same server as %q (%s)", names[0], server), "kubectl config delete-cluster "+name)
+		}
+	}
+}
+
+func (a *kubeconfigAuditor) checkUsers() {
+	used := make(map[string]bool)
+	for _, ctx := range a.config.Contexts {
+		used[ctx.AuthInfo] = true
+	}
+
+	for _, name := range sortedKeys(a.config.AuthInfos) {
+		user := a.config.AuthInfos[name]
+
+		if !used[name] {
+			a.add(FindingInfo, "user", name, "orphaned-user",
+				"not used by any context", "kubectl config unset users."+name)
+		}
+		if user.Token != "" {
+			a.add(FindingWarning, "user", name, "plaintext-token",
+				"bearer token is stored in plaintext; prefer an exec credential plugin", "")
+		}
+		if user.Password != "" {
+			a.add(FindingCritical, "user", name, "basic-auth",
+				"username/password stored in plaintext (basic auth is removed from Kubernetes)", "kubectl config unset users."+name)
+		}
+		if user.AuthProvider != nil {
+			a.add(FindingInfo, "user", name, "auth-provider",
+				fmt.Sprintf("deprecated %s auth-provider; migrate to an exec plugin", user.AuthProvider.Name), "")
+		}
+
+		certData := user.ClientCertificateData
+		if len(certData) == 0 && user.ClientCertificate != "" {
+			data, err := os.ReadFile(user.ClientCertificate)
+			if err != nil {
+				a.add(FindingWarning, "user", name, "missing-cert",
+					fmt.Sprintf("client certificate %s is unreadable", user.ClientCertificate), "")
+				continue
+			}
+			certData = data
+		}
+		if len(certData) > 0 {
+			a.checkClientCert(name, certData, len(user.ClientKeyData) > 0)
+		}
+	}
+}
+
+func (a *kubeconfigAuditor) checkClientCert(name string, data []byte, embeddedKey bool) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		a.add(FindingWarning, "user", name, "invalid-cert", "client certificate is not valid PEM", "")
+		return
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		a.add(FindingWarning, "user", name, "invalid-cert", "client certificate cannot be parsed", "")
+		return
+	}
+
+	remaining := time.Until(cert.NotAfter)
+	days := int(remaining.Hours() / 24)
+	switch {
+	case remaining <= 0:
+		a.add(FindingCritical, "user", name, "cert-expired",
+			fmt.Sprintf("client certificate expired on %s", cert.NotAfter.Format("2006-01-02")), "kubectl config unset users."+name)
+	case days <= a.opts.WarnDays:
+		a.add(FindingWarning, "user", name, "cert-expiring",
+			fmt.Sprintf("client certificate expires in %d days (%s)", days, cert.NotAfter.Format("2006-01-02")), "")
+	}
+
+	if cert.NotAfter.Sub(cert.NotBefore) > longLivedCert && embeddedKey {
+		a.add(FindingInfo, "user", name, "long-lived-cert",
+			fmt.Sprintf("embedded client certificate for %q is valid for %d days and cannot be revoked",
+				cert.Subject.CommonName, int(cert.NotAfter.Sub(cert.NotBefore).Hours()/24)), "")
+	}
+}
+
+func (a *kubeconfigAuditor) checkReachability(ctx context.Context) {
+	type probe struct {
+		name string
+		err  error
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results []probe
+	)
+	for _, name := range sortedKeys(a.config.Clusters) {
+		server := a.config.Clusters[name].Server
+		if server == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(name, server string) {
+			defer wg.Done()
+			err := dialServer(ctx, server, a.opts.Timeout)
+			mu.Lock()
+			results = append(results, probe{name: name, err: err})
+			mu.Unlock()
+		}(name, server)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].name < results[j].name })
+	for _, r := range results {
+		if r.err != nil {
+			a.add(FindingWarning, "cluster", r.name, "unreachable",
+				fmt.Sprintf("server unreachable: %v", r.err), "kubectl config delete-cluster "+r.name)
+		}
+	}
+}
+
+// dialServer checks that the API server accepts TCP connections
+func dialServer(ctx context.Context, server string, timeout time.Duration) error {
+	u, err := url.Parse(server)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid server URL %q", server)
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		port := "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func findingRank(severity string) int {
+	switch severity {
+	case FindingCritical:
+		return 2
+	case FindingWarning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,196 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/logging"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// metricsAPIPath is the metrics-server API root
+const metricsAPIPath = "/apis/metrics.k8s.io/v1beta1"
+
+// nodeMetricsPath is the metrics-server endpoint for node usage
+const nodeMetricsPath = metricsAPIPath + "/nodes"
+
+// NodeUsage is live node usage from the metrics API compared to allocatable
+type NodeUsage struct {
+	Name              string  `json:"name"`
+	Ready             bool    `json:"ready"`
+	Unschedulable     bool    `json:"unschedulable"`
+	HasMetrics        bool    `json:"has_metrics"`
+	CPUUsage          int64   `json:"cpu_usage_millicores"`
+	CPUAllocatable    int64   `json:"cpu_allocatable_millicores"`
+	CPUPercent        float64 `json:"cpu_percent"`
+	MemoryUsage       int64   `json:"memory_usage_bytes"`
+	MemoryAllocatable int64   `json:"memory_allocatable_bytes"`
+	MemoryPercent     float64 `json:"memory_percent"`
+	Pods              int     `json:"pods"`
+	PodCapacity       int64   `json:"pod_capacity"`
+	MemoryPressure    bool    `json:"memory_pressure"`
+	DiskPressure      bool    `json:"disk_pressure"`
+	PIDPressure       bool    `json:"pid_pressure"`
+}
+
+// podMetricsList is the subset of metrics.k8s.io PodMetricsList we read
+type podMetricsList struct {
+	Items []struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+		Containers []struct {
+			Usage map[string]string `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// nodeMetricsList is the subset of metrics.k8s.io NodeMetricsList we read
+type nodeMetricsList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Usage map[string]string `json:"usage"`
+	} `json:"items"`
+}
+
+// TopNodes returns per-node usage, pod counts and pressure conditions.
+// Usage is left empty (HasMetrics false) when metrics-server is not installed.
+func (c *Client) TopNodes(ctx context.Context, labelSelector string) ([]NodeUsage, error) {
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := c.nodeMetrics(ctx)
+	if err != nil {
+		logging.Debug("node metrics unavailable", "error", err)
+	}
+
+	pods, err := c.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		return nil, err
+	}
+	podsPerNode := make(map[string]int)
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != "" {
+			podsPerNode[pod.Spec.NodeName]++
+		}
+	}
+
+	var result []NodeUsage
+	for _, node := range nodes.Items {
+		info := NodeUsage{
+			Name:              node.Name,
+			Unschedulable:     node.Spec.Unschedulable,
+			CPUAllocatable:    node.Status.Allocatable.Cpu().MilliValue(),
+			MemoryAllocatable: node.Status.Allocatable.Memory().Value(),
+			Pods:              podsPerNode[node.Name],
+			PodCapacity:       node.Status.Allocatable.Pods().Value(),
+		}
+
+		for _, cond := range node.Status.Conditions {
+			isTrue := cond.Status == corev1.ConditionTrue
+			switch cond.Type {
+			case corev1.NodeReady:
+				info.Ready = isTrue
+			case corev1.NodeMemoryPressure:
+				info.MemoryPressure = isTrue
+			case corev1.NodeDiskPressure:
+				info.DiskPressure = isTrue
+			case corev1.NodePIDPressure:
+				info.PIDPressure = isTrue
+			}
+		}
+
+		if u, ok := usage[node.Name]; ok {
+			info.HasMetrics = true
+			info.CPUUsage = u.Cpu().MilliValue()
+			info.MemoryUsage = u.Memory().Value()
+			if info.CPUAllocatable > 0 {
+				info.CPUPercent = float64(info.CPUUsage) / float64(info.CPUAllocatable) * 100
+			}
+			if info.MemoryAllocatable > 0 {
+				info.MemoryPercent = float64(info.MemoryUsage) / float64(info.MemoryAllocatable) * 100
+			}
+		}
+
+		result = append(result, info)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// nodeMetrics reads current node usage from metrics-server
+func (c *Client) nodeMetrics(ctx context.Context) (map[string]corev1.ResourceList, error) {
+	data, err := c.clientset.CoreV1().RESTClient().Get().AbsPath(nodeMetricsPath).DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var list nodeMetricsList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse node metrics: %w", err)
+	}
+
+	result := make(map[string]corev1.ResourceList)
+	for _, item := range list.Items {
+		usage := corev1.ResourceList{}
+		for name, value := range item.Usage {
+			q, err := resource.ParseQuantity(value)
+			if err != nil {
+				continue
+			}
+			usage[corev1.ResourceName(name)] = q
+		}
+		result[item.Metadata.Name] = usage
+	}
+	return result, nil
+}
+
+// podMetrics reads current pod usage from metrics-server, summed over
+// containers and keyed by namespace/name
+func (c *Client) podMetrics(ctx context.Context, namespace string) (map[string]corev1.ResourceList, error) {
+	path := metricsAPIPath + "/pods"
+	if namespace != "" {
+		path = metricsAPIPath + "/namespaces/" + namespace + "/pods"
+	}
+	data, err := c.clientset.CoreV1().RESTClient().Get().AbsPath(path).DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var list podMetricsList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse pod metrics: %w", err)
+	}
+
+	result := make(map[string]corev1.ResourceList)
+	for _, item := range list.Items {
+		var cpu, mem resource.Quantity
+		for _, container := range item.Containers {
+			if q, err := resource.ParseQuantity(container.Usage["cpu"]); err == nil {
+				cpu.Add(q)
+			}
+			if q, err := resource.ParseQuantity(container.Usage["memory"]); err == nil {
+				mem.Add(q)
+			}
+		}
+		result[item.Metadata.Namespace+"/"+item.Metadata.Name] = corev1.ResourceList{
+			corev1.ResourceCPU:    cpu,
+			corev1.ResourceMemory: mem,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"path"
+	"strings"
+)
+
+// NamespaceSelector restricts cluster-wide operations to a set of namespaces.
+// Entries may be glob patterns such as "team-*".
+type NamespaceSelector struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// IsEmpty reports whether the selector matches every namespace
+func (s NamespaceSelector) IsEmpty() bool {
+	return len(s.Include) == 0 && len(s.Exclude) == 0
+}
+
+// Matches reports whether a namespace is in scope
+func (s NamespaceSelector) Matches(namespace string) bool {
+	for _, pattern := range s.Exclude {
+		if matchNamespace(pattern, namespace) {
+			return false
+		}
+	}
+	if len(s.Include) == 0 {
+		return true
+	}
+	for _, pattern := range s.Include {
+		if matchNamespace(pattern, namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// String describes the selector for headers and logs
+func (s NamespaceSelector) String() string {
+	var parts []string
+	if len(s.Include) > 0 {
+		parts = append(parts, "namespaces "+strings.Join(s.Include, ","))
+	}
+	if len(s.Exclude) > 0 {
+		parts = append(parts, "excluding "+strings.Join(s.Exclude, ","))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func matchNamespace(pattern, namespace string) bool {
+	if pattern == namespace {
+		return true
+	}
+	matched, err := path.Match(pattern, namespace)
+	return err == nil && matched
+}
+
+// SetNamespaceSelector limits the client's cluster-wide listings to the selected namespaces
+func (c *Client) SetNamespaceSelector(selector NamespaceSelector) {
+	c.namespaces = selector
+}
+
+// inScope reports whether an object in the namespace passes the client's namespace selector
+func (c *Client) inScope(namespace string) bool {
+	return c.namespaces.Matches(namespace)
+}
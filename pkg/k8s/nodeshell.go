@@ -0,0 +1,70 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// nodeShellCommand enters every namespace of the node's init process
+var nodeShellCommand = []string{"nsenter", "--target", "1", "--mount", "--uts", "--ipc", "--net", "--pid", "--", "sh", "-l"}
+
+// CreateNodeShellPod starts a privileged pod pinned to node that shares the
+// host's PID, network and IPC namespaces, and waits until it is running.
+// It tolerates every taint so it also lands on tainted and cordoned nodes
+func (c *Client) CreateNodeShellPod(ctx context.Context, namespace, node, image string) (string, error) {
+	if _, err := c.clientset.CoreV1().Nodes().Get(ctx, node, metav1.GetOptions{}); err != nil {
+		return "", fmt.Errorf("failed to get node %s: %w", node, err)
+	}
+
+	lifetime := debugPodLifetime
+	privileged := true
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "devops-toolkit-node-shell-",
+			Namespace:    namespace,
+			Labels:       map[string]string{ManagedByLabel: "devops-toolkit"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:                      node,
+			HostPID:                       true,
+			HostNetwork:                   true,
+			HostIPC:                       true,
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			ActiveDeadlineSeconds:         &lifetime,
+			TerminationGracePeriodSeconds: new(int64),
+			Tolerations:                   []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			Containers: []corev1.Container{{
+				Name:            "shell",
+				Image:           image,
+				Command:         []string{"sleep", fmt.Sprintf("%d", lifetime)},
+				SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+			}},
+		},
+	}
+
+	created, err := c.clientset.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create node shell pod: %w", err)
+	}
+
+	if err := c.waitForPodRunning(ctx, namespace, created.Name, 2*time.Minute); err != nil {
+		_ = c.DeletePod(context.Background(), namespace, created.Name)
+		return "", err
+	}
+	return created.Name, nil
+}
+
+// NodeShell opens a login shell in the host namespaces of the node shell pod
+func (c *Client) NodeShell(ctx context.Context, namespace, pod string, tty bool) error {
+	return c.interactive(ctx, "exec", namespace, pod, &corev1.PodExecOptions{
+		Command: nodeShellCommand,
+		Stdin:   true,
+		Stdout:  true,
+		Stderr:  !tty,
+		TTY:     tty,
+	}, tty)
+}
@@ -0,0 +1,119 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// noPriorityClass labels pods without a priorityClassName
+const noPriorityClass = "<none>"
+
+// QoSSummary counts a namespace's running and pending pods by QoS class and priority class
+type QoSSummary struct {
+	Namespace       string         `json:"namespace"`
+	Pods            int            `json:"pods"`
+	Guaranteed      int            `json:"guaranteed"`
+	Burstable       int            `json:"burstable"`
+	BestEffort      int            `json:"best_effort"`
+	PriorityClasses map[string]int `json:"priority_classes"`
+	// MaxPriority is the highest pod priority value in the namespace
+	MaxPriority int32 `json:"max_priority"`
+}
+
+// BestEffortPercent is the share of pods without any requests or limits
+func (s QoSSummary) BestEffortPercent() float64 {
+	if s.Pods == 0 {
+		return 0
+	}
+	return float64(s.BestEffort) / float64(s.Pods) * 100
+}
+
+// QoSDistribution summarizes pod QoS classes and priority classes per namespace
+func (c *Client) QoSDistribution(ctx context.Context, namespace string) ([]QoSSummary, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	summaries := make(map[string]*QoSSummary)
+	for _, pod := range pods.Items {
+		if !c.inScope(pod.Namespace) {
+			continue
+		}
+
+		s, ok := summaries[pod.Namespace]
+		if !ok {
+			s = &QoSSummary{Namespace: pod.Namespace, PriorityClasses: make(map[string]int)}
+			summaries[pod.Namespace] = s
+		}
+
+		s.Pods++
+		switch podQoSClass(pod) {
+		case corev1.PodQOSGuaranteed:
+			s.Guaranteed++
+		case corev1.PodQOSBurstable:
+			s.Burstable++
+		default:
+			s.BestEffort++
+		}
+
+		class := pod.Spec.PriorityClassName
+		if class == "" {
+			class = noPriorityClass
+		}
+		s.PriorityClasses[class]++
+		if pod.Spec.Priority != nil && *pod.Spec.Priority > s.MaxPriority {
+			s.MaxPriority = *pod.Spec.Priority
+		}
+	}
+
+	result := make([]QoSSummary, 0, len(summaries))
+	for _, s := range summaries {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Namespace < result[j].Namespace
+	})
+	return result, nil
+}
+
+// podQoSClass returns the QoS class the API server assigned, computing it for pods
+// that have not been admitted yet
+func podQoSClass(pod corev1.Pod) corev1.PodQOSClass {
+	if pod.Status.QOSClass != "" {
+		return pod.Status.QOSClass
+	}
+
+	guaranteed, hasResources := true, false
+	for _, container := range pod.Spec.Containers {
+		requests, limits := container.Resources.Requests, container.Resources.Limits
+		if len(requests) > 0 || len(limits) > 0 {
+			hasResources = true
+		}
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			limit, ok := limits[name]
+			if !ok {
+				guaranteed = false
+				continue
+			}
+			if request, ok := requests[name]; ok && request.Cmp(limit) != 0 {
+				guaranteed = false
+			}
+		}
+	}
+
+	switch {
+	case !hasResources:
+		return corev1.PodQOSBestEffort
+	case guaranteed:
+		return corev1.PodQOSGuaranteed
+	default:
+		return corev1.PodQOSBurstable
+	}
+}
@@ -0,0 +1,177 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RestartStormOptions controls restart storm detection
+type RestartStormOptions struct {
+	// Since is how far back container terminations are considered
+	Since time.Time
+	// Window is the span in which restarts must cluster to count as a storm
+	Window time.Duration
+	// MinPods is the number of distinct pods that must restart within the window
+	MinPods int
+}
+
+// RestartStorm is a burst of pod restarts within one namespace
+type RestartStorm struct {
+	Namespace string    `json:"namespace"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Pods      []string  `json:"pods"`
+	Restarts  int       `json:"restarts"`
+	Nodes     []string  `json:"nodes"`
+	Triggers  []string  `json:"triggers,omitempty"`
+}
+
+// containerRestart is the most recent termination of one container
+type containerRestart struct {
+	pod  string
+	node string
+	time time.Time
+}
+
+// DetectRestartStorms finds namespaces where many pods restarted within a short window
+// and hints at the likely trigger from node condition changes and recent rollouts
+func (c *Client) DetectRestartStorms(ctx context.Context, namespace string, opts RestartStormOptions) ([]RestartStorm, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	byNamespace := make(map[string][]containerRestart)
+	for _, pod := range pods.Items {
+		if !c.inScope(pod.Namespace) {
+			continue
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			terminated := cs.LastTerminationState.Terminated
+			if terminated == nil || terminated.FinishedAt.Time.Before(opts.Since) {
+				continue
+			}
+			byNamespace[pod.Namespace] = append(byNamespace[pod.Namespace], containerRestart{
+				pod:  pod.Name,
+				node: pod.Spec.NodeName,
+				time: terminated.FinishedAt.Time,
+			})
+		}
+	}
+
+	var storms []RestartStorm
+	for ns, restarts := range byNamespace {
+		if storm, ok := findRestartStorm(restarts, opts.Window, opts.MinPods); ok {
+			storm.Namespace = ns
+			storms = append(storms, storm)
+		}
+	}
+	if len(storms) == 0 {
+		return nil, nil
+	}
+
+	rollouts, err := c.timelineRollouts(ctx, namespace, opts.Since.Add(-opts.Window))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replicasets: %w", err)
+	}
+	nodeChanges, err := c.timelineNodes(ctx, opts.Since.Add(-opts.Window))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	for i := range storms {
+		storms[i].Triggers = stormTriggers(storms[i], opts.Window, rollouts, nodeChanges)
+	}
+
+	sort.Slice(storms, func(i, j int) bool {
+		return len(storms[i].Pods) > len(storms[j].Pods)
+	})
+	return storms, nil
+}
+
+// findRestartStorm slides the window over the restarts and returns the span with the most distinct pods
+func findRestartStorm(restarts []containerRestart, window time.Duration, minPods int) (RestartStorm, bool) {
+	sort.Slice(restarts, func(i, j int) bool {
+		return restarts[i].time.Before(restarts[j].time)
+	})
+
+	podCounts := make(map[string]int)
+	bestStart, bestEnd, bestPods := 0, -1, 0
+	start := 0
+	for end, r := range restarts {
+		podCounts[r.pod]++
+		for r.time.Sub(restarts[start].time) > window {
+			old := restarts[start].pod
+			podCounts[old]--
+			if podCounts[old] == 0 {
+				delete(podCounts, old)
+			}
+			start++
+		}
+		if len(podCounts) > bestPods {
+			bestStart, bestEnd, bestPods = start, end, len(podCounts)
+		}
+	}
+
+	if bestPods < minPods {
+		return RestartStorm{}, false
+	}
+
+	storm := RestartStorm{
+		Start:    restarts[bestStart].time,
+		End:      restarts[bestEnd].time,
+		Restarts: bestEnd - bestStart + 1,
+	}
+	seenPods := make(map[string]bool)
+	seenNodes := make(map[string]bool)
+	for _, r := range restarts[bestStart : bestEnd+1] {
+		if !seenPods[r.pod] {
+			seenPods[r.pod] = true
+			storm.Pods = append(storm.Pods, r.pod)
+		}
+		if r.node != "" && !seenNodes[r.node] {
+			seenNodes[r.node] = true
+			storm.Nodes = append(storm.Nodes, r.node)
+		}
+	}
+	sort.Strings(storm.Pods)
+	sort.Strings(storm.Nodes)
+	return storm, true
+}
+
+// stormTriggers lists rollouts in the namespace and unhealthy node transitions
+// on the affected nodes shortly before or during the storm
+func stormTriggers(storm RestartStorm, window time.Duration, rollouts, nodeChanges []TimelineEntry) []string {
+	from := storm.Start.Add(-window)
+	during := func(t time.Time) bool {
+		return !t.Before(from) && !t.After(storm.End)
+	}
+
+	affected := make(map[string]bool)
+	for _, node := range storm.Nodes {
+		affected[node] = true
+	}
+
+	var triggers []string
+	for _, entry := range nodeChanges {
+		if entry.Warning && affected[entry.Object] && during(entry.Time) {
+			condition, _, _ := strings.Cut(entry.Message, ":")
+			triggers = append(triggers, fmt.Sprintf("node %s %s at %s", entry.Object, condition, entry.Time.Format("15:04:05")))
+		}
+	}
+	for _, entry := range rollouts {
+		if entry.Namespace == storm.Namespace && during(entry.Time) {
+			triggers = append(triggers, fmt.Sprintf("rollout of deployment %s at %s", entry.Object, entry.Time.Format("15:04:05")))
+		}
+	}
+	if len(triggers) == 0 && len(storm.Nodes) == 1 {
+		triggers = append(triggers, fmt.Sprintf("all restarts on node %s", storm.Nodes[0]))
+	}
+
+	return triggers
+}
@@ -0,0 +1,438 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Progressive delivery controllers
+const (
+	RolloutControllerArgo    = "argo-rollouts"
+	RolloutControllerFlagger = "flagger"
+)
+
+// Rollout strategies
+const (
+	StrategyCanary    = "canary"
+	StrategyBlueGreen = "blueGreen"
+)
+
+const (
+	argoRolloutsAPI = "/apis/argoproj.io/v1alpha1"
+	flaggerAPI      = "/apis/flagger.app/v1beta1"
+)
+
+// RolloutInfo is an Argo Rollout or Flagger Canary
+type RolloutInfo struct {
+	Controller string `json:"controller"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	Strategy   string `json:"strategy"`
+	Phase      string `json:"phase"`
+	Message    string `json:"message,omitempty"`
+	// Weight is the share of traffic sent to the canary, in percent
+	Weight int `json:"weight"`
+	// Step and Steps are the current and total canary steps (Argo), or the
+	// analysis iteration (Flagger)
+	Step  int `json:"step"`
+	Steps int `json:"steps"`
+	// Paused is true while the rollout waits for a promote
+	Paused  bool `json:"paused"`
+	Aborted bool `json:"aborted"`
+	Ready   int  `json:"ready"`
+	Desired int  `json:"desired"`
+	// FailedChecks counts failed analysis checks (Flagger)
+	FailedChecks int `json:"failed_checks,omitempty"`
+	// Analysis is filled in by GetRollout
+	Analysis []AnalysisRunInfo `json:"analysis,omitempty"`
+}
+
+// AnalysisRunInfo is an Argo Rollouts AnalysisRun and its metric results
+type AnalysisRunInfo struct {
+	Name    string         `json:"name"`
+	Phase   string         `json:"phase"`
+	Started time.Time      `json:"started"`
+	Message string         `json:"message,omitempty"`
+	Metrics []MetricResult `json:"metrics"`
+}
+
+// MetricResult is one metric of an AnalysisRun
+type MetricResult struct {
+	Name         string `json:"name"`
+	Phase        string `json:"phase"`
+	Successful   int    `json:"successful"`
+	Failed       int    `json:"failed"`
+	Inconclusive int    `json:"inconclusive,omitempty"`
+	Error        int    `json:"error,omitempty"`
+	// Value is the latest measurement
+	Value string `json:"value,omitempty"`
+}
+
+// argoRollout is the part of an Argo Rollout the toolkit reads
+type argoRollout struct {
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		Replicas *int `json:"replicas"`
+		Paused   bool `json:"paused"`
+		Strategy struct {
+			Canary *struct {
+				Steps []struct {
+					SetWeight *int `json:"setWeight"`
+				} `json:"steps"`
+			} `json:"canary"`
+			BlueGreen *struct{} `json:"blueGreen"`
+		} `json:"strategy"`
+	} `json:"spec"`
+	Status struct {
+		Phase            string            `json:"phase"`
+		Message          string            `json:"message"`
+		Abort            bool              `json:"abort"`
+		CurrentStepIndex *int              `json:"currentStepIndex"`
+		PauseConditions  []json.RawMessage `json:"pauseConditions"`
+		ReadyReplicas    int               `json:"readyReplicas"`
+		CurrentPodHash   string            `json:"currentPodHash"`
+		StableRS         string            `json:"stableRS"`
+		Canary           struct {
+			Weights *struct {
+				Canary struct {
+					Weight int `json:"weight"`
+				} `json:"canary"`
+			} `json:"weights"`
+		} `json:"canary"`
+	} `json:"status"`
+}
+
+// flaggerCanary is the part of a Flagger Canary the toolkit reads
+type flaggerCanary struct {
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		Analysis struct {
+			Iterations int `json:"iterations"`
+			MaxWeight  int `json:"maxWeight"`
+			StepWeight int `json:"stepWeight"`
+		} `json:"analysis"`
+	} `json:"spec"`
+	Status struct {
+		Phase        string `json:"phase"`
+		CanaryWeight int    `json:"canaryWeight"`
+		FailedChecks int    `json:"failedChecks"`
+		Iterations   int    `json:"iterations"`
+		Conditions   []struct {
+			Message string `json:"message"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+// ListRollouts lists Argo Rollouts and Flagger Canaries in namespace (all
+// when empty). Controllers that are not installed are skipped.
+func (c *Client) ListRollouts(ctx context.Context, namespace string) ([]RolloutInfo, error) {
+	var rollouts []RolloutInfo
+
+	var argo struct {
+		Items []argoRollout `json:"items"`
+	}
+	found, err := c.getCustom(ctx, namespacedPath(argoRolloutsAPI, namespace, "rollouts", ""), &argo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Argo Rollouts: %w", err)
+	}
+	if found {
+		for _, r := range argo.Items {
+			if c.inScope(r.Metadata.Namespace) {
+				rollouts = append(rollouts, argoRolloutInfo(r))
+			}
+		}
+	}
+
+	var flagger struct {
+		Items []flaggerCanary `json:"items"`
+	}
+	found, err = c.getCustom(ctx, namespacedPath(flaggerAPI, namespace, "canaries", ""), &flagger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Flagger canaries: %w", err)
+	}
+	if found {
+		for _, cn := range flagger.Items {
+			if c.inScope(cn.Metadata.Namespace) {
+				rollouts = append(rollouts, flaggerCanaryInfo(cn))
+			}
+		}
+	}
+
+	sort.Slice(rollouts, func(i, j int) bool {
+		if rollouts[i].Namespace != rollouts[j].Namespace {
+			return rollouts[i].Namespace < rollouts[j].Namespace
+		}
+		return rollouts[i].Name < rollouts[j].Name
+	})
+	return rollouts, nil
+}
+
+// GetRollout returns one Argo Rollout with its AnalysisRuns, newest first,
+// or a Flagger Canary of that name
+func (c *Client) GetRollout(ctx context.Context, namespace, name string) (*RolloutInfo, error) {
+	var r argoRollout
+	found, err := c.getCustom(ctx, namespacedPath(argoRolloutsAPI, namespace, "rollouts", name), &r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rollout %s: %w", name, err)
+	}
+	if found {
+		info := argoRolloutInfo(r)
+		info.Analysis, err = c.analysisRuns(ctx, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		return &info, nil
+	}
+
+	var cn flaggerCanary
+	found, err = c.getCustom(ctx, namespacedPath(flaggerAPI, namespace, "canaries", name), &cn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get canary %s: %w", name, err)
+	}
+	if found {
+		info := flaggerCanaryInfo(cn)
+		return &info, nil
+	}
+	return nil, fmt.Errorf("no Argo Rollout or Flagger Canary named %s in namespace %s", name, namespace)
+}
+
+// PromoteRollout resumes a paused Argo Rollout, or skips the current canary
+// step when it is not paused. full skips all remaining steps and analysis.
+func (c *Client) PromoteRollout(ctx context.Context, namespace, name string, full bool) error {
+	var r argoRollout
+	found, err := c.getCustom(ctx, namespacedPath(argoRolloutsAPI, namespace, "rollouts", name), &r)
+	if err != nil {
+		return fmt.Errorf("failed to get rollout %s: %w", name, err)
+	}
+	if !found {
+		return fmt.Errorf("no Argo Rollout named %s in namespace %s (Flagger canaries are promoted through their webhooks)", name, namespace)
+	}
+
+	path := namespacedPath(argoRolloutsAPI, namespace, "rollouts", name)
+	if r.Spec.Paused {
+		if err := c.patchCustom(ctx, path, `{"spec":{"paused":false}}`); err != nil {
+			return fmt.Errorf("failed to resume rollout %s: %w", name, err)
+		}
+	}
+
+	var status string
+	switch {
+	case full:
+		status = `{"status":{"promoteFull":true}}`
+	case len(r.Status.PauseConditions) > 0:
+		status = `{"status":{"pauseConditions":null}}`
+	case r.Spec.Strategy.Canary != nil && r.Status.CurrentStepIndex != nil && *r.Status.CurrentStepIndex < len(r.Spec.Strategy.Canary.Steps):
+		status = fmt.Sprintf(`{"status":{"currentStepIndex":%d}}`, *r.Status.CurrentStepIndex+1)
+	case r.Spec.Paused:
+		return nil
+	default:
+		return fmt.Errorf("rollout %s is not paused and has no step left to skip", name)
+	}
+	if err := c.patchCustom(ctx, path+"/status", status); err != nil {
+		return fmt.Errorf("failed to promote rollout %s: %w", name, err)
+	}
+	return nil
+}
+
+// AbortRollout aborts an Argo Rollout, sending all traffic back to the stable version
+func (c *Client) AbortRollout(ctx context.Context, namespace, name string) error {
+	path := namespacedPath(argoRolloutsAPI, namespace, "rollouts", name)
+	if err := c.patchCustom(ctx, path+"/status", `{"status":{"abort":true}}`); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("no Argo Rollout named %s in namespace %s (Flagger canaries are rolled back through their webhooks)", name, namespace)
+		}
+		return fmt.Errorf("failed to abort rollout %s: %w", name, err)
+	}
+	return nil
+}
+
+// analysisRun is the part of an AnalysisRun the toolkit reads
+type analysisRun struct {
+	Metadata struct {
+		Name              string    `json:"name"`
+		CreationTimestamp time.Time `json:"creationTimestamp"`
+		OwnerReferences   []struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+		} `json:"ownerReferences"`
+	} `json:"metadata"`
+	Status struct {
+		Phase         string `json:"phase"`
+		Message       string `json:"message"`
+		MetricResults []struct {
+			Name         string `json:"name"`
+			Phase        string `json:"phase"`
+			Successful   int    `json:"successful"`
+			Failed       int    `json:"failed"`
+			Inconclusive int    `json:"inconclusive"`
+			Error        int    `json:"error"`
+			Measurements []struct {
+				Value string `json:"value"`
+			} `json:"measurements"`
+		} `json:"metricResults"`
+	} `json:"status"`
+}
+
+// analysisRuns returns the AnalysisRuns owned by a rollout, newest first
+func (c *Client) analysisRuns(ctx context.Context, namespace, rollout string) ([]AnalysisRunInfo, error) {
+	var list struct {
+		Items []analysisRun `json:"items"`
+	}
+	if _, err := c.getCustom(ctx, namespacedPath(argoRolloutsAPI, namespace, "analysisruns", ""), &list); err != nil {
+		return nil, fmt.Errorf("failed to list analysis runs: %w", err)
+	}
+
+	var runs []AnalysisRunInfo
+	for _, run := range list.Items {
+		owned := false
+		for _, ref := range run.Metadata.OwnerReferences {
+			owned = owned || (ref.Kind == "Rollout" && ref.Name == rollout)
+		}
+		if !owned {
+			continue
+		}
+
+		info := AnalysisRunInfo{
+			Name:    run.Metadata.Name,
+			Phase:   run.Status.Phase,
+			Started: run.Metadata.CreationTimestamp,
+			Message: run.Status.Message,
+		}
+		for _, m := range run.Status.MetricResults {
+			result := MetricResult{
+				Name:         m.Name,
+				Phase:        m.Phase,
+				Successful:   m.Successful,
+				Failed:       m.Failed,
+				Inconclusive: m.Inconclusive,
+				Error:        m.Error,
+			}
+			if n := len(m.Measurements); n > 0 {
+				result.Value = m.Measurements[n-1].Value
+			}
+			info.Metrics = append(info.Metrics, result)
+		}
+		runs = append(runs, info)
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].Started.After(runs[j].Started)
+	})
+	return runs, nil
+}
+
+func argoRolloutInfo(r argoRollout) RolloutInfo {
+	info := RolloutInfo{
+		Controller: RolloutControllerArgo,
+		Namespace:  r.Metadata.Namespace,
+		Name:       r.Metadata.Name,
+		Strategy:   StrategyBlueGreen,
+		Phase:      r.Status.Phase,
+		Message:    r.Status.Message,
+		Paused:     r.Spec.Paused || len(r.Status.PauseConditions) > 0,
+		Aborted:    r.Status.Abort,
+		Ready:      r.Status.ReadyReplicas,
+		Desired:    1,
+	}
+	if r.Spec.Replicas != nil {
+		info.Desired = *r.Spec.Replicas
+	}
+
+	if canary := r.Spec.Strategy.Canary; canary != nil {
+		info.Strategy = StrategyCanary
+		info.Steps = len(canary.Steps)
+		if r.Status.CurrentStepIndex != nil {
+			info.Step = *r.Status.CurrentStepIndex
+		}
+
+		// Traffic-routed canaries report their weight; otherwise it is the
+		// last setWeight step reached, or 100 once the rollout completed
+		switch {
+		case r.Status.Abort:
+			info.Weight = 0
+		case r.Status.Canary.Weights != nil:
+			info.Weight = r.Status.Canary.Weights.Canary.Weight
+		case info.Step >= info.Steps || r.Status.CurrentPodHash == r.Status.StableRS:
+			info.Weight = 100
+		default:
+			for _, step := range canary.Steps[:info.Step] {
+				if step.SetWeight != nil {
+					info.Weight = *step.SetWeight
+				}
+			}
+		}
+	}
+	return info
+}
+
+func flaggerCanaryInfo(cn flaggerCanary) RolloutInfo {
+	info := RolloutInfo{
+		Controller:   RolloutControllerFlagger,
+		Namespace:    cn.Metadata.Namespace,
+		Name:         cn.Metadata.Name,
+		Strategy:     StrategyCanary,
+		Phase:        cn.Status.Phase,
+		Weight:       cn.Status.CanaryWeight,
+		Step:         cn.Status.Iterations,
+		Steps:        cn.Spec.Analysis.Iterations,
+		Paused:       cn.Status.Phase == "WaitingPromotion" || cn.Status.Phase == "Waiting",
+		Aborted:      cn.Status.Phase == "Failed",
+		FailedChecks: cn.Status.FailedChecks,
+	}
+	if info.Steps == 0 && cn.Spec.Analysis.StepWeight > 0 {
+		info.Steps = cn.Spec.Analysis.MaxWeight / cn.Spec.Analysis.StepWeight
+		info.Step = cn.Status.CanaryWeight / cn.Spec.Analysis.StepWeight
+	}
+	if n := len(cn.Status.Conditions); n > 0 {
+		info.Message = cn.Status.Conditions[n-1].Message
+	}
+	return info
+}
+
+// namespacedPath builds a custom resource path, cluster-wide when namespace is empty
+func namespacedPath(api, namespace, resource, name string) string {
+	path := api
+	if namespace != "" {
+		path += "/namespaces/" + namespace
+	}
+	path += "/" + resource
+	if name != "" {
+		path += "/" + name
+	}
+	return path
+}
+
+// getCustom reads a custom resource into v. found is false when the CRD is
+// not installed or the object does not exist.
+func (c *Client) getCustom(ctx context.Context, path string, v interface{}) (bool, error) {
+	data, err := c.clientset.CoreV1().RESTClient().Get().AbsPath(path).DoRaw(ctx)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", strings.TrimPrefix(path, "/apis/"), err)
+	}
+	return true, nil
+}
+
+// patchCustom applies a JSON merge patch to a custom resource
+func (c *Client) patchCustom(ctx context.Context, path, patch string) error {
+	_, err := c.clientset.CoreV1().RESTClient().Patch(types.MergePatchType).AbsPath(path).Body([]byte(patch)).DoRaw(ctx)
+	return err
+}
@@ -0,0 +1,315 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// jobContainer is the container name of jobs created by RunJob
+const jobContainer = "task"
+
+// podStartErrors are waiting reasons a job pod will not recover from
+var podStartErrors = map[string]bool{
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+}
+
+// RunJobOptions describe an ad-hoc job
+type RunJobOptions struct {
+	Namespace string
+	Name      string
+	Image     string
+	// Args are passed to the image entrypoint, or replace it when Command is set
+	Args    []string
+	Command bool
+	Env     map[string]string
+	// CPU and Memory are requests; Memory is also the limit
+	CPU            string
+	Memory         string
+	BackoffLimit   int32
+	TTL            time.Duration
+	ActiveDeadline time.Duration
+	ServiceAccount string
+}
+
+// JobResult is the outcome of a job run by RunJob
+type JobResult struct {
+	Namespace string        `json:"namespace"`
+	Name      string        `json:"name"`
+	Pods      []string      `json:"pods"`
+	Succeeded bool          `json:"succeeded"`
+	ExitCode  int           `json:"exit_code"`
+	Reason    string        `json:"reason,omitempty"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// CreateJob creates a Job from opts and returns its name
+func (c *Client) CreateJob(ctx context.Context, opts RunJobOptions) (string, error) {
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{},
+		Limits:   corev1.ResourceList{},
+	}
+	if opts.CPU != "" {
+		q, err := resource.ParseQuantity(opts.CPU)
+		if err != nil {
+			return "", fmt.Errorf("invalid cpu %q: %w", opts.CPU, err)
+		}
+		resources.Requests[corev1.ResourceCPU] = q
+	}
+	if opts.Memory != "" {
+		q, err := resource.ParseQuantity(opts.Memory)
+		if err != nil {
+			return "", fmt.Errorf("invalid memory %q: %w", opts.Memory, err)
+		}
+		resources.Requests[corev1.ResourceMemory] = q
+		resources.Limits[corev1.ResourceMemory] = q
+	}
+
+	var env []corev1.EnvVar
+	for name, value := range opts.Env {
+		env = append(env, corev1.EnvVar{Name: name, Value: value})
+	}
+	sort.Slice(env, func(i, j int) bool {
+		return env[i].Name < env[j].Name
+	})
+
+	container := corev1.Container{
+		Name:      jobContainer,
+		Image:     opts.Image,
+		Env:       env,
+		Resources: resources,
+	}
+	if opts.Command {
+		container.Command = opts.Args
+	} else {
+		container.Args = opts.Args
+	}
+
+	labels := map[string]string{ManagedByLabel: "devops-toolkit"}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: opts.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &opts.BackoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: opts.ServiceAccount,
+					Containers:         []corev1.Container{container},
+				},
+			},
+		},
+	}
+	if opts.Name != "" {
+		job.Name = opts.Name
+	} else {
+		job.GenerateName = "devops-toolkit-job-"
+	}
+	if opts.TTL > 0 {
+		ttl := int32(opts.TTL.Seconds())
+		job.Spec.TTLSecondsAfterFinished = &ttl
+	}
+	if opts.ActiveDeadline > 0 {
+		deadline := int64(opts.ActiveDeadline.Seconds())
+		job.Spec.ActiveDeadlineSeconds = &deadline
+	}
+
+	created, err := c.clientset.BatchV1().Jobs(opts.Namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create job: %w", err)
+	}
+	return created.Name, nil
+}
+
+// FollowJob streams the logs of each pod the job starts to w, in order, and
+// returns once the job has succeeded or failed
+func (c *Client) FollowJob(ctx context.Context, namespace, name string, w io.Writer) (*JobResult, error) {
+	result := &JobResult{Namespace: namespace, Name: name, ExitCode: -1}
+	start := time.Now()
+	streamed := make(map[string]bool)
+
+	for {
+		pod, err := c.nextJobPod(ctx, namespace, name, streamed)
+		if err != nil {
+			return nil, err
+		}
+		if pod == "" {
+			break
+		}
+		streamed[pod] = true
+		result.Pods = append(result.Pods, pod)
+
+		if err := c.streamPodLogs(ctx, namespace, pod, w); err != nil {
+			return nil, err
+		}
+		code, reason, err := c.podExitCode(ctx, namespace, pod)
+		if err != nil {
+			return nil, err
+		}
+		result.ExitCode, result.Reason = code, reason
+	}
+
+	job, err := c.clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s: %w", name, err)
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			result.Succeeded = true
+		case batchv1.JobFailed:
+			// Deadline and backoff failures explain more than the last pod
+			if cond.Reason != "" && cond.Reason != "BackoffLimitExceeded" {
+				result.Reason = cond.Reason
+			}
+		}
+	}
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// nextJobPod waits for a job pod that has not been streamed to start, and
+// returns "" once the job finished without starting another
+func (c *Client) nextJobPod(ctx context.Context, namespace, job string, streamed map[string]bool) (string, error) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: "job-name=" + job,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to list pods of job %s: %w", job, err)
+		}
+
+		sort.Slice(pods.Items, func(i, j int) bool {
+			return pods.Items[i].CreationTimestamp.Before(&pods.Items[j].CreationTimestamp)
+		})
+		for _, pod := range pods.Items {
+			if streamed[pod.Name] {
+				continue
+			}
+			for _, cs := range pod.Status.ContainerStatuses {
+				if cs.State.Running != nil || cs.State.Terminated != nil {
+					return pod.Name, nil
+				}
+				if w := cs.State.Waiting; w != nil && podStartErrors[w.Reason] {
+					return "", fmt.Errorf("job pod %s cannot start: %s: %s", pod.Name, w.Reason, w.Message)
+				}
+			}
+		}
+
+		j, err := c.clientset.BatchV1().Jobs(namespace).Get(ctx, job, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to get job %s: %w", job, err)
+		}
+		if jobFinished(j) {
+			return "", nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// streamPodLogs follows the job container's logs until it exits
+func (c *Client) streamPodLogs(ctx context.Context, namespace, pod string, w io.Writer) error {
+	stream, err := c.clientset.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container: jobContainer,
+		Follow:    true,
+	}).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to stream logs of %s: %w", pod, err)
+	}
+	defer stream.Close()
+
+	if _, err := io.Copy(w, stream); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to stream logs of %s: %w", pod, err)
+	}
+	return ctx.Err()
+}
+
+// podExitCode waits for the job container to terminate; the code is -1 when
+// the pod was deleted first, as on a job deadline
+func (c *Client) podExitCode(ctx context.Context, namespace, pod string) (int, string, error) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		p, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, pod, metav1.GetOptions{})
+		if err != nil {
+			if ignoreNotFound(err) == nil {
+				return -1, "PodDeleted", nil
+			}
+			return 0, "", fmt.Errorf("failed to get pod %s: %w", pod, err)
+		}
+		for _, cs := range p.Status.ContainerStatuses {
+			if t := cs.State.Terminated; t != nil {
+				reason := t.Reason
+				if reason == "Completed" || reason == "Error" {
+					reason = ""
+				}
+				return int(t.ExitCode), reason, nil
+			}
+		}
+		if p.Status.Phase == corev1.PodFailed {
+			return -1, p.Status.Reason, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, "", ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func jobFinished(job *batchv1.Job) bool {
+	for _, cond := range job.Status.Conditions {
+		if (cond.Type == batchv1.JobComplete || cond.Type == batchv1.JobFailed) && cond.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteJob deletes a job and its pods
+func (c *Client) DeleteJob(ctx context.Context, namespace, name string) error {
+	propagation := metav1.DeletePropagationBackground
+	err := c.clientset.BatchV1().Jobs(namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	return ignoreNotFound(err)
+}
+
+// ParseEnv parses KEY=VALUE pairs
+func ParseEnv(pairs []string) (map[string]string, error) {
+	env := make(map[string]string)
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid environment variable %q (expected KEY=VALUE)", pair)
+		}
+		env[name] = value
+	}
+	return env, nil
+}
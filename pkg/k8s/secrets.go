@@ -0,0 +1,203 @@
+package k8s
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/batch"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// SecretUsage describes a secret, what references it and, for TLS secrets, when it expires
+type SecretUsage struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Created   time.Time `json:"created"`
+	Keys      int       `json:"keys"`
+	UsedBy    []string  `json:"used_by,omitempty"`
+	// Managed secrets (service account tokens, Helm release state) are referenced implicitly
+	Managed      bool      `json:"managed"`
+	Unreferenced bool      `json:"unreferenced"`
+	TLSNotAfter  time.Time `json:"tls_not_after,omitempty"`
+	TLSSubject   string    `json:"tls_subject,omitempty"`
+}
+
+// TLSExpiresWithin reports whether the secret holds a certificate expiring within d
+func (s SecretUsage) TLSExpiresWithin(d time.Duration) bool {
+	return !s.TLSNotAfter.IsZero() && time.Until(s.TLSNotAfter) <= d
+}
+
+// managedSecretTypes are referenced by controllers rather than by pods
+var managedSecretTypes = map[corev1.SecretType]bool{
+	corev1.SecretTypeServiceAccountToken: true,
+	corev1.SecretTypeBootstrapToken:      true,
+	"helm.sh/release.v1":                 true,
+}
+
+// SecretsReport lists secrets with the workloads, service accounts and ingresses that reference them
+func (c *Client) SecretsReport(ctx context.Context, namespace string) ([]SecretUsage, error) {
+	return CollectSecretUsage(ctx, c.clientset, namespace, c.inScope)
+}
+
+// CollectSecretUsage builds the secrets report from any clientset; inScope filters namespaces
+func CollectSecretUsage(ctx context.Context, clientset kubernetes.Interface, namespace string, inScope func(string) bool) ([]SecretUsage, error) {
+	secrets, err := clientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	serviceAccounts, err := clientset.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service accounts: %w", err)
+	}
+	ingresses, err := clientset.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingresses: %w", err)
+	}
+
+	refs := make(map[string][]string)
+	addRef := func(ns, secret, user string) {
+		key := ns + "/" + secret
+		for _, existing := range refs[key] {
+			if existing == user {
+				return
+			}
+		}
+		refs[key] = append(refs[key], user)
+	}
+
+	for _, pod := range pods.Items {
+		kind, name := podWorkload(pod)
+		user := strings.ToLower(kind) + "/" + name
+		for _, secret := range podSecretRefs(pod.Spec) {
+			addRef(pod.Namespace, secret, user)
+		}
+	}
+	for _, sa := range serviceAccounts.Items {
+		for _, ref := range sa.ImagePullSecrets {
+			addRef(sa.Namespace, ref.Name, "serviceaccount/"+sa.Name)
+		}
+		for _, ref := range sa.Secrets {
+			addRef(sa.Namespace, ref.Name, "serviceaccount/"+sa.Name)
+		}
+	}
+	for _, ing := range ingresses.Items {
+		for _, tls := range ing.Spec.TLS {
+			if tls.SecretName != "" {
+				addRef(ing.Namespace, tls.SecretName, "ingress/"+ing.Name)
+			}
+		}
+	}
+
+	var result []SecretUsage
+	for _, secret := range secrets.Items {
+		if !inScope(secret.Namespace) {
+			continue
+		}
+
+		usage := SecretUsage{
+			Namespace: secret.Namespace,
+			Name:      secret.Name,
+			Type:      string(secret.Type),
+			Created:   secret.CreationTimestamp.Time,
+			Keys:      len(secret.Data),
+			UsedBy:    refs[secret.Namespace+"/"+secret.Name],
+			Managed:   managedSecretTypes[secret.Type],
+		}
+		usage.Unreferenced = len(usage.UsedBy) == 0 && !usage.Managed
+
+		if cert := secretCertificate(secret); cert != nil {
+			usage.TLSNotAfter = cert.NotAfter
+			usage.TLSSubject = cert.Subject.CommonName
+			if usage.TLSSubject == "" && len(cert.DNSNames) > 0 {
+				usage.TLSSubject = cert.DNSNames[0]
+			}
+		}
+
+		result = append(result, usage)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// podSecretRefs returns the secrets a pod spec mounts, reads into env or pulls images with
+func podSecretRefs(spec corev1.PodSpec) []string {
+	var names []string
+	for _, vol := range spec.Volumes {
+		if vol.Secret != nil {
+			names = append(names, vol.Secret.SecretName)
+		}
+		if vol.Projected != nil {
+			for _, source := range vol.Projected.Sources {
+				if source.Secret != nil {
+					names = append(names, source.Secret.Name)
+				}
+			}
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, container := range containers {
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				names = append(names, env.ValueFrom.SecretKeyRef.Name)
+			}
+		}
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef != nil {
+				names = append(names, envFrom.SecretRef.Name)
+			}
+		}
+	}
+
+	for _, ref := range spec.ImagePullSecrets {
+		names = append(names, ref.Name)
+	}
+	return names
+}
+
+// secretCertificate parses the leaf certificate of a TLS secret
+func secretCertificate(secret corev1.Secret) *x509.Certificate {
+	data, ok := secret.Data[corev1.TLSCertKey]
+	if !ok {
+		return nil
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+	return cert
+}
+
+// DeleteSecrets deletes the specified secrets
+func (c *Client) DeleteSecrets(ctx context.Context, secrets []SecretUsage) (int, error) {
+	names := make([]string, len(secrets))
+	errs := batch.Run(len(secrets), batch.DefaultWorkers, func(i int) error {
+		secret := secrets[i]
+		names[i] = secret.Namespace + "/" + secret.Name
+		err := c.clientset.CoreV1().Secrets(secret.Namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{})
+		return ignoreNotFound(err)
+	})
+	return batch.Succeeded(errs), batch.Collect(names, errs)
+}
@@ -0,0 +1,249 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ServiceAccountKubeconfigOptions describes a scoped ServiceAccount credential
+type ServiceAccountKubeconfigOptions struct {
+	Namespace string
+	Name      string
+	// ClusterRole binds an existing ClusterRole (e.g. edit, view) in the namespace instead of creating a Role
+	ClusterRole string
+	// Verbs and Resources define the Role; resources may be qualified with their API group, e.g. deployments.apps
+	Verbs     []string
+	Resources []string
+	Duration  time.Duration
+	// Force takes over an existing Role or RoleBinding that devops-toolkit did not create
+	Force bool
+}
+
+// ServiceAccountKubeconfig is a generated kubeconfig and what was created for it
+type ServiceAccountKubeconfig struct {
+	Kubeconfig []byte
+	Expires    time.Time
+	Created    []string
+}
+
+// MakeServiceAccountKubeconfig creates (or updates) the ServiceAccount, Role and RoleBinding,
+// requests a token through the TokenRequest API and returns a kubeconfig that uses it.
+// An existing Role or RoleBinding is only changed when it carries the toolkit's
+// managed-by label, unless opts.Force is set.
+func (c *Client) MakeServiceAccountKubeconfig(ctx context.Context, opts ServiceAccountKubeconfigOptions) (*ServiceAccountKubeconfig, error) {
+	result := &ServiceAccountKubeconfig{}
+	labels := map[string]string{ManagedByLabel: "devops-toolkit"}
+
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: opts.Name, Namespace: opts.Namespace, Labels: labels},
+	}
+	_, err := c.clientset.CoreV1().ServiceAccounts(opts.Namespace).Create(ctx, sa, metav1.CreateOptions{})
+	switch {
+	case err == nil:
+		result.Created = append(result.Created, "serviceaccount/"+opts.Name)
+	case !apierrors.IsAlreadyExists(err):
+		return nil, fmt.Errorf("failed to create service account: %w", err)
+	}
+
+	roleRef := rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: opts.ClusterRole}
+	if opts.ClusterRole == "" {
+		role := &rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: opts.Name, Namespace: opts.Namespace, Labels: labels},
+			Rules:      policyRules(opts.Verbs, opts.Resources),
+		}
+		created, err := c.applyRole(ctx, role, opts.Force)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			result.Created = append(result.Created, "role/"+opts.Name)
+		}
+		roleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: opts.Name}
+	}
+
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: opts.Name, Namespace: opts.Namespace, Labels: labels},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      opts.Name,
+			Namespace: opts.Namespace,
+		}},
+		RoleRef: roleRef,
+	}
+	created, err := c.applyRoleBinding(ctx, binding, opts.Force)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		result.Created = append(result.Created, "rolebinding/"+opts.Name)
+	}
+
+	expiration := int64(opts.Duration.Seconds())
+	token, err := c.clientset.CoreV1().ServiceAccounts(opts.Namespace).CreateToken(ctx, opts.Name, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expiration},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to request token: %w", err)
+	}
+	result.Expires = token.Status.ExpirationTimestamp.Time
+
+	result.Kubeconfig, err = c.tokenKubeconfig(opts.Namespace, opts.Name, token.Status.Token)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// applyRole creates the role or replaces the rules of an existing one
+func (c *Client) applyRole(ctx context.Context, role *rbacv1.Role, force bool) (bool, error) {
+	roles := c.clientset.RbacV1().Roles(role.Namespace)
+	_, err := roles.Create(ctx, role, metav1.CreateOptions{})
+	if err == nil {
+		return true, nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return false, fmt.Errorf("failed to create role: %w", err)
+	}
+
+	existing, err := roles.Get(ctx, role.Name, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to get role: %w", err)
+	}
+	if err := checkManaged("role", existing.ObjectMeta, force); err != nil {
+		return false, err
+	}
+	existing.Labels = withManagedBy(existing.Labels)
+	existing.Rules = role.Rules
+	if _, err := roles.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return false, fmt.Errorf("failed to update role: %w", err)
+	}
+	return false, nil
+}
+
+// applyRoleBinding creates the binding, recreating it when the role reference changed
+// (roleRef is immutable)
+func (c *Client) applyRoleBinding(ctx context.Context, binding *rbacv1.RoleBinding, force bool) (bool, error) {
+	bindings := c.clientset.RbacV1().RoleBindings(binding.Namespace)
+	_, err := bindings.Create(ctx, binding, metav1.CreateOptions{})
+	if err == nil {
+		return true, nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return false, fmt.Errorf("failed to create role binding: %w", err)
+	}
+
+	existing, err := bindings.Get(ctx, binding.Name, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to get role binding: %w", err)
+	}
+	if err := checkManaged("rolebinding", existing.ObjectMeta, force); err != nil {
+		return false, err
+	}
+	if existing.RoleRef == binding.RoleRef {
+		existing.Labels = withManagedBy(existing.Labels)
+		existing.Subjects = binding.Subjects
+		if _, err := bindings.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return false, fmt.Errorf("failed to update role binding: %w", err)
+		}
+		return false, nil
+	}
+
+	if err := bindings.Delete(ctx, binding.Name, metav1.DeleteOptions{}); err != nil {
+		return false, fmt.Errorf("failed to replace role binding: %w", err)
+	}
+	if _, err := bindings.Create(ctx, binding, metav1.CreateOptions{}); err != nil {
+		return false, fmt.Errorf("failed to create role binding: %w", err)
+	}
+	return true, nil
+}
+
+// checkManaged refuses to modify an object the toolkit did not create, so an
+// existing Role or RoleBinding with the same name is not silently rewritten
+func checkManaged(kind string, meta metav1.ObjectMeta, force bool) error {
+	if force || meta.Labels[ManagedByLabel] == "devops-toolkit" {
+		return nil
+	}
+	return exitcode.ConfigError(fmt.Errorf("%s %s/%s already exists and is not managed by devops-toolkit; use another --sa name or --force to take it over",
+		kind, meta.Namespace, meta.Name))
+}
+
+// withManagedBy adds the toolkit's managed-by label, so an object taken over
+// with --force is updated without it on later runs
+func withManagedBy(labels map[string]string) map[string]string {
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[ManagedByLabel] = "devops-toolkit"
+	return labels
+}
+
+// policyRules groups resources by API group; "deployments.apps" is deployments in the apps group
+func policyRules(verbs, resources []string) []rbacv1.PolicyRule {
+	var rules []rbacv1.PolicyRule
+	index := make(map[string]int)
+	for _, resource := range resources {
+		name, group, _ := strings.Cut(resource, ".")
+		i, ok := index[group]
+		if !ok {
+			rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{group}, Verbs: verbs})
+			i = len(rules) - 1
+			index[group] = i
+		}
+		rules[i].Resources = append(rules[i].Resources, name)
+	}
+	return rules
+}
+
+// tokenKubeconfig builds a single-context kubeconfig for the current cluster using a bearer token
+func (c *Client) tokenKubeconfig(namespace, name, token string) ([]byte, error) {
+	cluster := clientcmdapi.NewCluster()
+	cluster.Server = c.config.Host
+	cluster.InsecureSkipTLSVerify = c.config.Insecure
+	cluster.CertificateAuthorityData = c.config.CAData
+	if len(cluster.CertificateAuthorityData) == 0 && c.config.CAFile != "" {
+		data, err := os.ReadFile(c.config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cluster CA: %w", err)
+		}
+		cluster.CertificateAuthorityData = data
+	}
+
+	clusterName := c.contextName
+	if clusterName == "" {
+		clusterName = "cluster"
+	}
+	user := fmt.Sprintf("%s-%s", namespace, name)
+	contextName := fmt.Sprintf("%s@%s", user, clusterName)
+
+	authInfo := clientcmdapi.NewAuthInfo()
+	authInfo.Token = token
+
+	kubeContext := clientcmdapi.NewContext()
+	kubeContext.Cluster = clusterName
+	kubeContext.AuthInfo = user
+	kubeContext.Namespace = namespace
+
+	config := clientcmdapi.NewConfig()
+	config.Clusters[clusterName] = cluster
+	config.AuthInfos[user] = authInfo
+	config.Contexts[contextName] = kubeContext
+	config.CurrentContext = contextName
+
+	data, err := clientcmd.Write(*config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode kubeconfig: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,134 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var readPods = []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}}
+
+func existingRole(labels map[string]string) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "apps", Labels: labels},
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}},
+	}
+}
+
+func existingBinding(labels map[string]string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "apps", Labels: labels},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "admin"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "alice"}},
+	}
+}
+
+func isConfigError(err error) bool {
+	var coded *exitcode.Error
+	return errors.As(err, &coded) && coded.Code == exitcode.Config
+}
+
+func TestApplyRoleOwnership(t *testing.T) {
+	managed := map[string]string{ManagedByLabel: "devops-toolkit"}
+	tests := []struct {
+		name      string
+		labels    map[string]string
+		force     bool
+		wantError bool
+	}{
+		{"managed", managed, false, false},
+		{"unmanaged", map[string]string{"team": "platform"}, false, true},
+		{"unlabelled", nil, false, true},
+		{"unmanaged with force", map[string]string{"team": "platform"}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, clientset := newFakeClient(existingRole(tt.labels))
+			role := &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "apps", Labels: managed},
+				Rules:      readPods,
+			}
+
+			created, err := c.applyRole(context.Background(), role, tt.force)
+			if created {
+				t.Error("applyRole reported an existing role as created")
+			}
+
+			got, getErr := clientset.RbacV1().Roles("apps").Get(context.Background(), "ci", metav1.GetOptions{})
+			if getErr != nil {
+				t.Fatal(getErr)
+			}
+			if tt.wantError {
+				if !isConfigError(err) {
+					t.Errorf("err = %v, want a config error", err)
+				}
+				if got.Rules[0].Verbs[0] != "*" {
+					t.Errorf("unmanaged role rules were overwritten: %+v", got.Rules)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyRole: %v", err)
+			}
+			if got.Rules[0].Verbs[0] != "get" || got.Labels[ManagedByLabel] != "devops-toolkit" {
+				t.Errorf("role = %+v, want the new rules and the managed-by label", got)
+			}
+		})
+	}
+}
+
+func TestApplyRoleBindingOwnership(t *testing.T) {
+	managed := map[string]string{ManagedByLabel: "devops-toolkit"}
+	binding := func() *rbacv1.RoleBinding {
+		return &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "apps", Labels: managed},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "ci"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "ci", Namespace: "apps"}},
+		}
+	}
+
+	t.Run("unmanaged", func(t *testing.T) {
+		c, clientset := newFakeClient(existingBinding(nil))
+		if _, err := c.applyRoleBinding(context.Background(), binding(), false); !isConfigError(err) {
+			t.Errorf("err = %v, want a config error", err)
+		}
+		got, err := clientset.RbacV1().RoleBindings("apps").Get(context.Background(), "ci", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.RoleRef.Name != "admin" || got.Subjects[0].Name != "alice" {
+			t.Errorf("unmanaged binding was changed: %+v", got)
+		}
+	})
+
+	for _, tt := range []struct {
+		name   string
+		labels map[string]string
+		force  bool
+	}{
+		{"managed", managed, false},
+		{"unmanaged with force", nil, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, clientset := newFakeClient(existingBinding(tt.labels))
+			created, err := c.applyRoleBinding(context.Background(), binding(), tt.force)
+			if err != nil {
+				t.Fatalf("applyRoleBinding: %v", err)
+			}
+			if !created {
+				t.Error("a binding recreated for a new role reference should be reported as created")
+			}
+			got, err := clientset.RbacV1().RoleBindings("apps").Get(context.Background(), "ci", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.RoleRef.Kind != "Role" || got.Subjects[0].Name != "ci" {
+				t.Errorf("binding = %+v, want the ServiceAccount bound to Role ci", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Timeline entry sources
+const (
+	TimelineEvent   = "event"
+	TimelineRestart = "restart"
+	TimelineRollout = "rollout"
+	TimelineNode    = "node"
+	TimelineDeploy  = "deploy"
+)
+
+// revisionAnnotation is set by the deployment controller on each ReplicaSet
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// TimelineEntry is a single point on an incident timeline
+type TimelineEntry struct {
+	Time      time.Time `json:"time"`
+	Source    string    `json:"source"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace,omitempty"`
+	Object    string    `json:"object"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message"`
+	Warning   bool      `json:"warning"`
+}
+
+// Timeline collects events, restarts, rollouts and node condition changes since the given time
+func (c *Client) Timeline(ctx context.Context, namespace string, since time.Time) ([]TimelineEntry, error) {
+	var entries []TimelineEntry
+
+	events, err := c.timelineEvents(ctx, namespace, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	entries = append(entries, events...)
+
+	restarts, err := c.timelineRestarts(ctx, namespace, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	entries = append(entries, restarts...)
+
+	rollouts, err := c.timelineRollouts(ctx, namespace, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replicasets: %w", err)
+	}
+	entries = append(entries, rollouts...)
+
+	nodes, err := c.timelineNodes(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	entries = append(entries, nodes...)
+
+	SortTimeline(entries)
+	return entries, nil
+}
+
+// SortTimeline orders entries chronologically
+func SortTimeline(entries []TimelineEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+}
+
+func (c *Client) timelineEvents(ctx context.Context, namespace string, since time.Time) ([]TimelineEntry, error) {
+	events, err := c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []TimelineEntry
+	for _, event := range events.Items {
+		ts := eventTime(event)
+		if ts.Before(since) {
+			continue
+		}
+
+		message := event.Message
+		if event.Count > 1 {
+			message = fmt.Sprintf("%s (x%d)", message, event.Count)
+		}
+
+		entries = append(entries, TimelineEntry{
+			Time:      ts,
+			Source:    TimelineEvent,
+			Kind:      event.InvolvedObject.Kind,
+			Namespace: event.InvolvedObject.Namespace,
+			Object:    event.InvolvedObject.Name,
+			Reason:    event.Reason,
+			Message:   message,
+			Warning:   event.Type == corev1.EventTypeWarning,
+		})
+	}
+
+	return entries, nil
+}
+
+// eventTime returns the most recent timestamp recorded on an event
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+func (c *Client) timelineRestarts(ctx context.Context, namespace string, since time.Time) ([]TimelineEntry, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []TimelineEntry
+	for _, pod := range pods.Items {
+		for _, cs := range pod.Status.ContainerStatuses {
+			terminated := cs.LastTerminationState.Terminated
+			if terminated == nil || terminated.FinishedAt.Time.Before(since) {
+				continue
+			}
+
+			message := fmt.Sprintf("container %s exited with code %d (restarts: %d)",
+				cs.Name, terminated.ExitCode, cs.RestartCount)
+			if terminated.Message != "" {
+				message += ": " + strings.TrimSpace(terminated.Message)
+			}
+
+			entries = append(entries, TimelineEntry{
+				Time:      terminated.FinishedAt.Time,
+				Source:    TimelineRestart,
+				Kind:      "Pod",
+				Namespace: pod.Namespace,
+				Object:    pod.Name,
+				Reason:    terminated.Reason,
+				Message:   message,
+				Warning:   terminated.ExitCode != 0,
+			})
+		}
+	}
+
+	return entries, nil
+}
+
+func (c *Client) timelineRollouts(ctx context.Context, namespace string, since time.Time) ([]TimelineEntry, error) {
+	replicaSets, err := c.clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []TimelineEntry
+	for _, rs := range replicaSets.Items {
+		if rs.CreationTimestamp.Time.Before(since) {
+			continue
+		}
+
+		var deployment string
+		for _, ref := range rs.OwnerReferences {
+			if ref.Kind == "Deployment" {
+				deployment = ref.Name
+				break
+			}
+		}
+		if deployment == "" {
+			continue
+		}
+
+		var images []string
+		for _, container := range rs.Spec.Template.Spec.Containers {
+			images = append(images, container.Image)
+		}
+
+		message := "new replicaset " + rs.Name
+		if revision := rs.Annotations[revisionAnnotation]; revision != "" {
+			message = fmt.Sprintf("revision %s (%s)", revision, rs.Name)
+		}
+		if len(images) > 0 {
+			message += ": " + strings.Join(images, ", ")
+		}
+
+		entries = append(entries, TimelineEntry{
+			Time:      rs.CreationTimestamp.Time,
+			Source:    TimelineRollout,
+			Kind:      "Deployment",
+			Namespace: rs.Namespace,
+			Object:    deployment,
+			Reason:    "Rollout",
+			Message:   message,
+		})
+	}
+
+	return entries, nil
+}
+
+func (c *Client) timelineNodes(ctx context.Context, since time.Time) ([]TimelineEntry, error) {
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []TimelineEntry
+	for _, node := range nodes.Items {
+		for _, cond := range node.Status.Conditions {
+			if cond.LastTransitionTime.Time.Before(since) {
+				continue
+			}
+
+			// Ready=True and pressure conditions=False are the healthy states
+			healthy := cond.Status == corev1.ConditionFalse
+			if cond.Type == corev1.NodeReady {
+				healthy = cond.Status == corev1.ConditionTrue
+			}
+
+			message := fmt.Sprintf("%s=%s", cond.Type, cond.Status)
+			if cond.Message != "" {
+				message += ": " + cond.Message
+			}
+
+			entries = append(entries, TimelineEntry{
+				Time:    cond.LastTransitionTime.Time,
+				Source:  TimelineNode,
+				Kind:    "Node",
+				Object:  node.Name,
+				Reason:  cond.Reason,
+				Message: message,
+				Warning: !healthy,
+			})
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,327 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Health of an object in an ownership tree
+const (
+	TreeHealthy  = "healthy"
+	TreeWarning  = "warning"
+	TreeFailed   = "failed"
+	TreeInactive = "inactive"
+)
+
+// treeKinds maps accepted spellings to the kinds GetOwnershipTree loads,
+// in the order children are listed
+var treeKinds = []struct {
+	kind    string
+	aliases []string
+}{
+	{"CronJob", []string{"cronjob", "cronjobs", "cj"}},
+	{"Deployment", []string{"deployment", "deployments", "deploy"}},
+	{"StatefulSet", []string{"statefulset", "statefulsets", "sts"}},
+	{"DaemonSet", []string{"daemonset", "daemonsets", "ds"}},
+	{"ReplicaSet", []string{"replicaset", "replicasets", "rs"}},
+	{"Job", []string{"job", "jobs"}},
+	{"Pod", []string{"pod", "pods", "po"}},
+}
+
+// ObjectNode is an object in an ownership tree with its owned objects
+type ObjectNode struct {
+	Kind     string        `json:"kind"`
+	Name     string        `json:"name"`
+	Status   string        `json:"status"`
+	Health   string        `json:"health"`
+	Created  time.Time     `json:"created,omitempty"`
+	Target   bool          `json:"target,omitempty"`
+	Children []*ObjectNode `json:"children,omitempty"`
+
+	uid    types.UID
+	owners []metav1.OwnerReference
+}
+
+// TreeKind returns the kind for a kubectl-style resource name such as deploy
+// or pods
+func TreeKind(resource string) (string, error) {
+	resource = strings.ToLower(resource)
+	for _, k := range treeKinds {
+		for _, alias := range k.aliases {
+			if resource == alias {
+				return k.kind, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unsupported resource %q (supported: cronjob, deployment, statefulset, daemonset, replicaset, job, pod)", resource)
+}
+
+// GetOwnershipTree follows the controller ownerReferences of an object up to
+// its top-level owner, and returns that owner with everything it owns.
+// Owners of kinds that are not loaded, such as Argo Rollouts, become the root
+// without a status
+func (c *Client) GetOwnershipTree(ctx context.Context, namespace, kind, name string) (*ObjectNode, error) {
+	objects, err := c.treeObjects(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	byUID := make(map[types.UID]*ObjectNode, len(objects))
+	var target *ObjectNode
+	for _, obj := range objects {
+		byUID[obj.uid] = obj
+		if obj.Kind == kind && obj.Name == name {
+			target = obj
+		}
+	}
+	if target == nil {
+		return nil, fmt.Errorf("%s %s not found in namespace %s", strings.ToLower(kind), name, namespace)
+	}
+	target.Target = true
+
+	root := target
+	for seen := map[types.UID]bool{}; !seen[root.uid]; {
+		seen[root.uid] = true
+		ref := controllerRef(root.owners)
+		if ref == nil {
+			break
+		}
+		owner, ok := byUID[ref.UID]
+		if !ok {
+			owner = &ObjectNode{Kind: ref.Kind, Name: ref.Name, Status: "-", uid: ref.UID}
+			byUID[ref.UID] = owner
+			objects = append(objects, owner)
+		}
+		root = owner
+	}
+
+	children := make(map[types.UID][]*ObjectNode)
+	for _, obj := range objects {
+		for _, ref := range obj.owners {
+			children[ref.UID] = append(children[ref.UID], obj)
+		}
+	}
+	attachChildren(root, children, map[types.UID]bool{})
+	return root, nil
+}
+
+func controllerRef(refs []metav1.OwnerReference) *metav1.OwnerReference {
+	for i := range refs {
+		if refs[i].Controller != nil && *refs[i].Controller {
+			return &refs[i]
+		}
+	}
+	return nil
+}
+
+func attachChildren(node *ObjectNode, children map[types.UID][]*ObjectNode, visited map[types.UID]bool) {
+	visited[node.uid] = true
+	for _, child := range children[node.uid] {
+		if !visited[child.uid] {
+			node.Children = append(node.Children, child)
+		}
+	}
+	sort.SliceStable(node.Children, func(i, j int) bool {
+		a, b := node.Children[i], node.Children[j]
+		if a.Kind != b.Kind {
+			return kindOrder(a.Kind) < kindOrder(b.Kind)
+		}
+		// Newest first, so the current ReplicaSet leads its scaled-down predecessors
+		if !a.Created.Equal(b.Created) {
+			return a.Created.After(b.Created)
+		}
+		return a.Name < b.Name
+	})
+	for _, child := range node.Children {
+		attachChildren(child, children, visited)
+	}
+}
+
+func kindOrder(kind string) int {
+	for i, k := range treeKinds {
+		if k.kind == kind {
+			return i
+		}
+	}
+	return len(treeKinds)
+}
+
+// treeObjects loads the workload objects of a namespace
+func (c *Client) treeObjects(ctx context.Context, namespace string) ([]*ObjectNode, error) {
+	var objects []*ObjectNode
+	add := func(kind string, meta metav1.ObjectMeta, status, health string) {
+		objects = append(objects, &ObjectNode{
+			Kind:    kind,
+			Name:    meta.Name,
+			Status:  status,
+			Health:  health,
+			Created: meta.CreationTimestamp.Time,
+			uid:     meta.UID,
+			owners:  meta.OwnerReferences,
+		})
+	}
+
+	cronJobs, err := c.clientset.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs: %w", err)
+	}
+	for _, cj := range cronJobs.Items {
+		if cj.Spec.Suspend != nil && *cj.Spec.Suspend {
+			add("CronJob", cj.ObjectMeta, "Suspended", TreeInactive)
+		} else {
+			add("CronJob", cj.ObjectMeta, "schedule "+cj.Spec.Schedule, TreeHealthy)
+		}
+	}
+
+	deployments, err := c.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, dep := range deployments.Items {
+		s := deploymentStatus(dep)
+		health := TreeHealthy
+		switch s.State {
+		case DeploymentProgressing, DeploymentPaused:
+			health = TreeWarning
+		case DeploymentDegraded, DeploymentStalled:
+			health = TreeFailed
+		}
+		add("Deployment", dep.ObjectMeta, fmt.Sprintf("%s, %d/%d ready", s.State, s.Ready, s.Desired), health)
+	}
+
+	statefulSets, err := c.clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for _, sts := range statefulSets.Items {
+		desired := int32(1)
+		if sts.Spec.Replicas != nil {
+			desired = *sts.Spec.Replicas
+		}
+		status, health := replicasStatus(sts.Status.ReadyReplicas, desired, sts.Status.Replicas)
+		add("StatefulSet", sts.ObjectMeta, status, health)
+	}
+
+	daemonSets, err := c.clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemonsets: %w", err)
+	}
+	for _, ds := range daemonSets.Items {
+		status, health := replicasStatus(ds.Status.NumberReady, ds.Status.DesiredNumberScheduled, ds.Status.CurrentNumberScheduled)
+		add("DaemonSet", ds.ObjectMeta, status, health)
+	}
+
+	replicaSets, err := c.clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replicasets: %w", err)
+	}
+	for _, rs := range replicaSets.Items {
+		status, health := replicaSetStatus(rs)
+		add("ReplicaSet", rs.ObjectMeta, status, health)
+	}
+
+	jobs, err := c.clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	for _, job := range jobs.Items {
+		status, health := jobStatus(job)
+		add("Job", job.ObjectMeta, status, health)
+	}
+
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		status, health := podTreeStatus(pod)
+		add("Pod", pod.ObjectMeta, status, health)
+	}
+
+	return objects, nil
+}
+
+func replicasStatus(ready, desired, current int32) (string, string) {
+	status := fmt.Sprintf("%d/%d ready", ready, desired)
+	switch {
+	case desired == 0 && current == 0:
+		return "scaled down", TreeInactive
+	case ready >= desired:
+		return status, TreeHealthy
+	case ready == 0:
+		return status, TreeFailed
+	default:
+		return status, TreeWarning
+	}
+}
+
+func replicaSetStatus(rs appsv1.ReplicaSet) (string, string) {
+	desired := int32(1)
+	if rs.Spec.Replicas != nil {
+		desired = *rs.Spec.Replicas
+	}
+	status, health := replicasStatus(rs.Status.ReadyReplicas, desired, rs.Status.Replicas)
+	if revision := rs.Annotations["deployment.kubernetes.io/revision"]; revision != "" {
+		status = "rev " + revision + ", " + status
+	}
+	return status, health
+}
+
+func jobStatus(job batchv1.Job) (string, string) {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return "Complete", TreeInactive
+		case batchv1.JobFailed:
+			if cond.Reason != "" {
+				return "Failed: " + cond.Reason, TreeFailed
+			}
+			return "Failed", TreeFailed
+		}
+	}
+	if job.Spec.Suspend != nil && *job.Spec.Suspend {
+		return "Suspended", TreeInactive
+	}
+	return fmt.Sprintf("Running, %d active", job.Status.Active), TreeHealthy
+}
+
+func podTreeStatus(pod corev1.Pod) (string, string) {
+	status := podStatus(pod)
+	ready, restarts := 0, int32(0)
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Ready {
+			ready++
+		}
+		restarts += cs.RestartCount
+	}
+
+	health := TreeWarning
+	switch {
+	case pod.Status.Phase == corev1.PodSucceeded:
+		health = TreeInactive
+	case pod.Status.Phase == corev1.PodFailed, podStartErrors[status], status == "CrashLoopBackOff", status == "OOMKilled", status == "Error":
+		health = TreeFailed
+	case pod.Status.Phase == corev1.PodRunning && ready == len(pod.Spec.Containers):
+		health = TreeHealthy
+	}
+
+	if pod.Status.Phase == corev1.PodRunning || pod.Status.Phase == corev1.PodPending {
+		status = fmt.Sprintf("%s, %d/%d ready", status, ready, len(pod.Spec.Containers))
+	}
+	if restarts > 0 {
+		status = fmt.Sprintf("%s, %d restarts", status, restarts)
+	}
+	return status, health
+}
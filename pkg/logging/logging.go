@@ -0,0 +1,137 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+)
+
+var (
+	logger  = slog.New(slog.DiscardHandler)
+	enabled bool
+)
+
+// Setup configures debug logging. Verbose writes human-readable logs to stderr;
+// logFile additionally appends JSON logs to a file (e.g. for support bundles).
+func Setup(verbose bool, logFile string) error {
+	var handlers []slog.Handler
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+
+	if verbose {
+		handlers = append(handlers, slog.NewTextHandler(os.Stderr, opts))
+	}
+
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		handlers = append(handlers, slog.NewJSONHandler(f, opts))
+	}
+
+	switch len(handlers) {
+	case 0:
+		logger, enabled = slog.New(slog.DiscardHandler), false
+	case 1:
+		logger, enabled = slog.New(handlers[0]), true
+	default:
+		logger, enabled = slog.New(multiHandler(handlers)), true
+	}
+	slog.SetDefault(logger)
+
+	return nil
+}
+
+// Logger returns the configured logger
+func Logger() *slog.Logger {
+	return logger
+}
+
+// Enabled reports whether debug logging is active
+func Enabled() bool {
+	return enabled
+}
+
+// Debug logs a debug message with key/value pairs
+func Debug(msg string, args ...any) {
+	logger.Debug(msg, args...)
+}
+
+// Warn logs a warning with key/value pairs
+func Warn(msg string, args ...any) {
+	logger.Warn(msg, args...)
+}
+
+// Transport wraps an http.RoundTripper so every request is logged with its
+// status and timing. A nil next uses http.DefaultTransport.
+func Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripper{next: next}
+}
+
+type roundTripper struct {
+	next http.RoundTripper
+}
+
+func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := rt.next.RoundTrip(req)
+
+	// Only the path is logged; query strings may carry tokens
+	attrs := []any{
+		"method", req.Method,
+		"host", req.URL.Host,
+		"path", req.URL.Path,
+		"duration", time.Since(start).Round(time.Millisecond),
+	}
+	if err != nil {
+		logger.Debug("api request failed", append(attrs, "error", err)...)
+		return resp, err
+	}
+	logger.Debug("api request", append(attrs, "status", resp.StatusCode)...)
+	return resp, nil
+}
+
+// multiHandler fans records out to several handlers
+type multiHandler []slog.Handler
+
+func (m multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range m {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(multiHandler, len(m))
+	for i, h := range m {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (m multiHandler) WithGroup(name string) slog.Handler {
+	out := make(multiHandler, len(m))
+	for i, h := range m {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
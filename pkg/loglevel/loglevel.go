@@ -0,0 +1,79 @@
+package loglevel
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Log levels, lowest severity first
+const (
+	Debug = "debug"
+	Info  = "info"
+	Warn  = "warn"
+	Error = "error"
+)
+
+var order = []string{Debug, Info, Warn, Error}
+
+// patterns are checked most severe first, so a line mentioning both wins as the worse level
+var patterns = []struct {
+	level   string
+	pattern *regexp.Regexp
+}{
+	{Error, regexp.MustCompile(`\b(error|err|fatal|panic|exception)\b`)},
+	{Warn, regexp.MustCompile(`\b(warn|warning)\b`)},
+	{Info, regexp.MustCompile(`\b(info)\b`)},
+	{Debug, regexp.MustCompile(`\b(debug|trace)\b`)},
+}
+
+// Detect guesses the level of a log line, or returns "" if it has none
+func Detect(content string) string {
+	lower := strings.ToLower(content)
+	for _, p := range patterns {
+		if p.pattern.MatchString(lower) {
+			return p.level
+		}
+	}
+	return ""
+}
+
+// Matches reports whether a detected level is at or above the filter level
+func Matches(detected, filter string) bool {
+	filter = strings.ToLower(filter)
+	detected = strings.ToLower(detected)
+
+	if filter == detected {
+		return true
+	}
+
+	// Include higher severity levels
+	filterIdx := -1
+	detectedIdx := -1
+
+	for i, l := range order {
+		if l == filter {
+			filterIdx = i
+		}
+		if l == detected {
+			detectedIdx = i
+		}
+	}
+
+	return detectedIdx >= filterIdx
+}
+
+// Normalize maps level names such as WARNING or fatal to the toolkit's levels
+func Normalize(level string) string {
+	switch strings.ToLower(level) {
+	case "debug", "trace":
+		return Debug
+	case "info", "information", "notice":
+		return Info
+	case "warn", "warning":
+		return Warn
+	case "error", "err", "fatal", "panic", "critical", "crit":
+		return Error
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,192 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/logging"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/loglevel"
+)
+
+// Client queries the Loki HTTP API
+type Client struct {
+	baseURL    string
+	token      string
+	orgID      string
+	httpClient *http.Client
+}
+
+// Entry is a single log line returned by Loki
+type Entry struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Labels    map[string]string `json:"labels"`
+	Line      string            `json:"line"`
+	Level     string            `json:"level,omitempty"`
+}
+
+// QueryOptions controls a range query
+type QueryOptions struct {
+	Start time.Time
+	End   time.Time
+	Limit int
+	Level string
+}
+
+// NewClient creates a Loki client; token (bearer) and orgID (X-Scope-OrgID) are optional
+func NewClient(baseURL, token, orgID string) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid Loki URL %q", baseURL)
+	}
+
+	transport := http.DefaultTransport
+	if logging.Enabled() {
+		transport = logging.Transport(transport)
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		orgID:      orgID,
+		httpClient: &http.Client{Timeout: 60 * time.Second, Transport: transport},
+	}, nil
+}
+
+// PodSelector builds a LogQL stream selector for a pod's logs, matching the
+// labels set by the Promtail/Alloy Kubernetes discovery
+func PodSelector(namespace, pod, container string) string {
+	matchers := []string{fmt.Sprintf("pod=%q", pod)}
+	if namespace != "" {
+		matchers = append([]string{fmt.Sprintf("namespace=%q", namespace)}, matchers...)
+	}
+	if container != "" {
+		matchers = append(matchers, fmt.Sprintf("container=%q", container))
+	}
+	return "{" + strings.Join(matchers, ", ") + "}"
+}
+
+// QueryRange runs a LogQL log query and returns the most recent entries, oldest first
+func (c *Client) QueryRange(ctx context.Context, query string, opts QueryOptions) ([]Entry, error) {
+	params := url.Values{
+		"query":     {query},
+		"start":     {strconv.FormatInt(opts.Start.UnixNano(), 10)},
+		"end":       {strconv.FormatInt(opts.End.UnixNano(), 10)},
+		"limit":     {strconv.Itoa(opts.Limit)},
+		"direction": {"backward"},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/loki/api/v1/query_range?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.orgID != "" {
+		req.Header.Set("X-Scope-OrgID", c.orgID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Loki: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Loki response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("loki query failed (HTTP %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		Data struct {
+			ResultType string `json:"resultType"`
+			Result     []struct {
+				Stream map[string]string `json:"stream"`
+				Values [][2]string       `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse Loki response: %w", err)
+	}
+	if result.Data.ResultType != "streams" {
+		return nil, fmt.Errorf("expected a log query, got a %s result; metric queries are not supported", result.Data.ResultType)
+	}
+
+	var entries []Entry
+	for _, stream := range result.Data.Result {
+		for _, v := range stream.Values {
+			ns, err := strconv.ParseInt(v[0], 10, 64)
+			if err != nil {
+				continue
+			}
+
+			entry := Entry{
+				Timestamp: time.Unix(0, ns),
+				Labels:    stream.Stream,
+				Line:      v[1],
+				Level:     loglevel.Normalize(stream.Stream["level"]),
+			}
+			if entry.Level == "" {
+				entry.Level = loglevel.Detect(entry.Line)
+			}
+			if opts.Level != "" && !loglevel.Matches(entry.Level, opts.Level) {
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	return entries, nil
+}
+
+// Labels returns the label names Loki has indexed, which also verifies the URL,
+// token and tenant
+func (c *Client) Labels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/loki/api/v1/labels", nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.orgID != "" {
+		req.Header.Set("X-Scope-OrgID", c.orgID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Loki: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Loki response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("loki labels request failed (HTTP %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		Data []string `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse Loki response: %w", err)
+	}
+	return result.Data, nil
+}
@@ -0,0 +1,247 @@
+package netcheck
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"time"
+)
+
+// CertOptions configures a certificate check
+type CertOptions struct {
+	// ServerName overrides the SNI and hostname checked against the SANs
+	ServerName string
+	Timeout    time.Duration
+	WarnDays   int
+	CritDays   int
+}
+
+// CertInfo describes one certificate of the served chain
+type CertInfo struct {
+	Subject            string    `json:"subject"`
+	Issuer             string    `json:"issuer"`
+	NotBefore          time.Time `json:"not_before"`
+	NotAfter           time.Time `json:"not_after"`
+	DaysLeft           int       `json:"days_left"`
+	SignatureAlgorithm string    `json:"signature_algorithm"`
+	PublicKey          string    `json:"public_key"`
+	DNSNames           []string  `json:"dns_names,omitempty"`
+	IsCA               bool      `json:"is_ca"`
+}
+
+// CertResult is the outcome of checking one endpoint
+type CertResult struct {
+	Target     string     `json:"target"`
+	ServerName string     `json:"server_name"`
+	TLSVersion string     `json:"tls_version,omitempty"`
+	Chain      []CertInfo `json:"chain,omitempty"`
+	Trusted    bool       `json:"trusted"`
+	NameMatch  bool       `json:"name_match"`
+	DaysLeft   int        `json:"days_left"`
+	Status     string     `json:"status"`
+	Issues     []Issue    `json:"issues,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// weakSignatures are signature algorithms no longer considered secure
+var weakSignatures = map[x509.SignatureAlgorithm]bool{
+	x509.MD2WithRSA:    true,
+	x509.MD5WithRSA:    true,
+	x509.SHA1WithRSA:   true,
+	x509.DSAWithSHA1:   true,
+	x509.ECDSAWithSHA1: true,
+}
+
+// CheckCert connects to target (host[:port], default 443) and inspects the served certificate chain
+func CheckCert(ctx context.Context, target string, opts CertOptions) CertResult {
+	address := withDefaultPort(target, "443")
+	host, _, _ := net.SplitHostPort(address)
+
+	result := CertResult{Target: address, ServerName: opts.ServerName, Status: StatusOK}
+	if result.ServerName == "" {
+		result.ServerName = host
+	}
+
+	// Verification is done below so that broken chains can still be inspected
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: opts.Timeout},
+		Config: &tls.Config{
+			ServerName:         result.ServerName,
+			InsecureSkipVerify: true,
+		},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		result.Status = StatusError
+		result.Error = err.Error()
+		return result
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	result.TLSVersion = tls.VersionName(state.Version)
+	if len(state.PeerCertificates) == 0 {
+		result.Status = StatusError
+		result.Error = "server presented no certificate"
+		return result
+	}
+
+	now := time.Now()
+	for _, cert := range state.PeerCertificates {
+		result.Chain = append(result.Chain, certInfo(cert, now))
+	}
+	result.DaysLeft = result.Chain[0].DaysLeft
+
+	result.verify(state.PeerCertificates, now)
+	result.checkExpiry(state.PeerCertificates, now, opts)
+	result.checkStrength(state.PeerCertificates)
+	if state.Version < tls.VersionTLS12 {
+		result.addIssue(StatusWarning, fmt.Sprintf("Negotiated %s; TLS 1.2 or newer is recommended", result.TLSVersion))
+	}
+
+	return result
+}
+
+// verify checks the chain against the system roots and the hostname against the SANs
+func (r *CertResult) verify(certs []*x509.Certificate, now time.Time) {
+	leaf := certs[0]
+
+	if err := leaf.VerifyHostname(r.ServerName); err != nil {
+		r.addIssue(StatusCritical, fmt.Sprintf("Certificate does not match %s (SANs: %s)", r.ServerName, strings.Join(leaf.DNSNames, ", ")))
+	} else {
+		r.NameMatch = true
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{Intermediates: intermediates, CurrentTime: now})
+	switch e := err.(type) {
+	case nil:
+		r.Trusted = true
+	case x509.UnknownAuthorityError:
+		if isSelfSigned(leaf) {
+			r.addIssue(StatusCritical, "Certificate is self-signed")
+		} else {
+			r.addIssue(StatusCritical, fmt.Sprintf("Chain does not lead to a trusted root (issuer %s); the server may not send its intermediates", issuerName(leaf)))
+		}
+	case x509.CertificateInvalidError:
+		// Expiry is reported separately with its countdown
+		if e.Reason != x509.Expired {
+			r.addIssue(StatusCritical, "Invalid chain: "+e.Error())
+		}
+	default:
+		r.addIssue(StatusCritical, "Invalid chain: "+err.Error())
+	}
+}
+
+// checkExpiry warns about the leaf and any intermediate nearing expiry
+func (r *CertResult) checkExpiry(certs []*x509.Certificate, now time.Time, opts CertOptions) {
+	for i, cert := range certs {
+		name := "Certificate"
+		if i > 0 {
+			name = fmt.Sprintf("Intermediate %q", cert.Subject.CommonName)
+		}
+		days := r.Chain[i].DaysLeft
+
+		switch {
+		case now.After(cert.NotAfter):
+			r.addIssue(StatusCritical, fmt.Sprintf("%s expired on %s", name, cert.NotAfter.Format("2006-01-02")))
+		case now.Before(cert.NotBefore):
+			r.addIssue(StatusCritical, fmt.Sprintf("%s is not valid until %s", name, cert.NotBefore.Format("2006-01-02")))
+		case days <= opts.CritDays:
+			r.addIssue(StatusCritical, fmt.Sprintf("%s expires in %d days", name, days))
+		case days <= opts.WarnDays:
+			r.addIssue(StatusWarning, fmt.Sprintf("%s expires in %d days", name, days))
+		}
+	}
+}
+
+// checkStrength flags weak signatures and small keys; self-signed roots are exempt from signature checks
+func (r *CertResult) checkStrength(certs []*x509.Certificate) {
+	for i, cert := range certs {
+		name := "Certificate"
+		if i > 0 {
+			name = fmt.Sprintf("Intermediate %q", cert.Subject.CommonName)
+		}
+
+		if weakSignatures[cert.SignatureAlgorithm] && !(i > 0 && isSelfSigned(cert)) {
+			r.addIssue(StatusWarning, fmt.Sprintf("%s uses weak signature algorithm %s", name, cert.SignatureAlgorithm))
+		}
+
+		switch key := cert.PublicKey.(type) {
+		case *rsa.PublicKey:
+			if key.N.BitLen() < 2048 {
+				r.addIssue(StatusWarning, fmt.Sprintf("%s has a %d-bit RSA key (2048+ recommended)", name, key.N.BitLen()))
+			}
+		case *ecdsa.PublicKey:
+			if key.Curve.Params().BitSize < 256 {
+				r.addIssue(StatusWarning, fmt.Sprintf("%s has a %d-bit ECDSA key (256+ recommended)", name, key.Curve.Params().BitSize))
+			}
+		}
+	}
+}
+
+func (r *CertResult) addIssue(severity, message string) {
+	r.Issues = append(r.Issues, Issue{Severity: severity, Message: message})
+	r.Status = worst(r.Status, severity)
+}
+
+func certInfo(cert *x509.Certificate, now time.Time) CertInfo {
+	return CertInfo{
+		Subject:            subjectName(cert),
+		Issuer:             issuerName(cert),
+		NotBefore:          cert.NotBefore,
+		NotAfter:           cert.NotAfter,
+		DaysLeft:           int(math.Floor(cert.NotAfter.Sub(now).Hours() / 24)),
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		PublicKey:          publicKeyDescription(cert),
+		DNSNames:           cert.DNSNames,
+		IsCA:               cert.IsCA,
+	}
+}
+
+func subjectName(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0]
+	}
+	return cert.Subject.String()
+}
+
+func issuerName(cert *x509.Certificate) string {
+	if cert.Issuer.CommonName != "" {
+		return cert.Issuer.CommonName
+	}
+	if len(cert.Issuer.Organization) > 0 {
+		return cert.Issuer.Organization[0]
+	}
+	return cert.Issuer.String()
+}
+
+func publicKeyDescription(cert *x509.Certificate) string {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("ECDSA %s", key.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return "Ed25519"
+	default:
+		return cert.PublicKeyAlgorithm.String()
+	}
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return cert.Subject.String() == cert.Issuer.String() && cert.CheckSignatureFrom(cert) == nil
+}
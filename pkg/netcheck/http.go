@@ -0,0 +1,134 @@
+package netcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxBodyBytes bounds how much of a response is searched for Contains
+const maxBodyBytes = 1 << 20
+
+// HTTPCheck is a synthetic probe of one URL
+type HTTPCheck struct {
+	Name   string            `yaml:"name" mapstructure:"name" json:"name"`
+	URL    string            `yaml:"url" mapstructure:"url" json:"url"`
+	Method string            `yaml:"method" mapstructure:"method" json:"method,omitempty"`
+	Header map[string]string `yaml:"headers" mapstructure:"headers" json:"headers,omitempty"`
+	// ExpectStatus lists accepted status codes; empty accepts 2xx and 3xx
+	ExpectStatus []int `yaml:"expect_status" mapstructure:"expect_status" json:"expect_status,omitempty"`
+	// Contains must appear in the response body
+	Contains string `yaml:"contains" mapstructure:"contains" json:"contains,omitempty"`
+	// MaxLatency marks slower responses as warnings
+	MaxLatency time.Duration `yaml:"max_latency" mapstructure:"max_latency" json:"max_latency,omitempty"`
+	Insecure   bool          `yaml:"insecure" mapstructure:"insecure" json:"insecure,omitempty"`
+}
+
+// HTTPResult is the outcome of an HTTPCheck
+type HTTPResult struct {
+	Name       string        `json:"name"`
+	URL        string        `json:"url"`
+	StatusCode int           `json:"status_code,omitempty"`
+	Latency    time.Duration `json:"latency"`
+	Status     string        `json:"status"`
+	Issues     []Issue       `json:"issues,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	CheckedAt  time.Time     `json:"checked_at"`
+}
+
+// CheckHTTP runs check with the given timeout; redirects are not followed
+func CheckHTTP(ctx context.Context, check HTTPCheck, timeout time.Duration) HTTPResult {
+	result := HTTPResult{Name: check.Name, URL: check.URL, Status: StatusOK, CheckedAt: time.Now()}
+	if result.Name == "" {
+		result.Name = check.URL
+	}
+
+	method := check.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), check.URL, nil)
+	if err != nil {
+		result.Status = StatusError
+		result.Error = err.Error()
+		return result
+	}
+	req.Header.Set("User-Agent", "devops-toolkit-http-check")
+	for k, v := range check.Header {
+		req.Header.Set(k, v)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if check.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Latency = time.Since(start)
+		result.Status = StatusError
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	result.Latency = time.Since(start)
+	result.StatusCode = resp.StatusCode
+	if err != nil {
+		result.addIssue(StatusCritical, fmt.Sprintf("Failed to read body: %v", err))
+	}
+
+	if !statusAccepted(resp.StatusCode, check.ExpectStatus) {
+		result.addIssue(StatusCritical, fmt.Sprintf("Unexpected status %d", resp.StatusCode))
+	}
+	if check.Contains != "" && !strings.Contains(string(body), check.Contains) {
+		result.addIssue(StatusCritical, fmt.Sprintf("Body does not contain %q", check.Contains))
+	}
+	if check.MaxLatency > 0 && result.Latency > check.MaxLatency {
+		result.addIssue(StatusWarning, fmt.Sprintf("Latency %s exceeds %s", result.Latency.Round(time.Millisecond), check.MaxLatency))
+	}
+
+	return result
+}
+
+func (r *HTTPResult) addIssue(severity, message string) {
+	r.Issues = append(r.Issues, Issue{Severity: severity, Message: message})
+	r.Status = worst(r.Status, severity)
+}
+
+// Summary describes what is wrong with the result in one line
+func (r HTTPResult) Summary() string {
+	if r.Error != "" {
+		return r.Error
+	}
+	messages := make([]string, 0, len(r.Issues))
+	for _, issue := range r.Issues {
+		messages = append(messages, issue.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+func statusAccepted(code int, expected []int) bool {
+	if len(expected) == 0 {
+		return code >= 200 && code < 400
+	}
+	for _, e := range expected {
+		if code == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,262 @@
+package netcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+)
+
+// Source kinds
+const (
+	SourceLocal  = "local"
+	SourcePod    = "pod"
+	SourceDebug  = "debug"
+	SourceDocker = "docker"
+)
+
+// DefaultDebugImage provides nc for probes from temporary pods
+const DefaultDebugImage = "busybox:1.36"
+
+// maxConcurrentProbes bounds parallel probes across the matrix
+const maxConcurrentProbes = 10
+
+// exitNoProbeTool is returned by probeScript when neither nc nor bash exists
+const exitNoProbeTool = 3
+
+var (
+	hostPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$|^\[[0-9A-Fa-f:.]+\]$`)
+	portPattern = regexp.MustCompile(`^[0-9]{1,5}$`)
+)
+
+// Source is where connections are made from:
+//
+//	local                              this machine
+//	pod:<namespace>/<pod>[/<container>] an existing pod (Kubernetes exec)
+//	debug:<namespace>[/<k=v,...>]      a temporary pod with these labels, for NetworkPolicy tests
+//	docker:<container>                 a running container (docker exec)
+type Source struct {
+	Spec      string            `json:"spec"`
+	Kind      string            `json:"kind"`
+	Namespace string            `json:"namespace,omitempty"`
+	Name      string            `json:"name,omitempty"`
+	Container string            `json:"container,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// Target is a host:port to connect to
+type Target struct {
+	Host string `json:"host"`
+	Port string `json:"port"`
+}
+
+// String returns host:port
+func (t Target) String() string {
+	return net.JoinHostPort(strings.Trim(t.Host, "[]"), t.Port)
+}
+
+// ProbeResult is the outcome of one source → target connection attempt
+type ProbeResult struct {
+	Source    string        `json:"source"`
+	Target    string        `json:"target"`
+	Reachable bool          `json:"reachable"`
+	Latency   time.Duration `json:"latency,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// Matrix holds results indexed by source, then target
+type Matrix struct {
+	Sources []string        `json:"sources"`
+	Targets []string        `json:"targets"`
+	Results [][]ProbeResult `json:"results"`
+}
+
+// ParseSource parses a source spec
+func ParseSource(spec string) (Source, error) {
+	kind, rest, _ := strings.Cut(spec, ":")
+	src := Source{Spec: spec, Kind: kind}
+
+	switch kind {
+	case SourceLocal:
+		return src, nil
+	case SourcePod:
+		parts := strings.Split(rest, "/")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return src, fmt.Errorf("invalid source %q (use pod:<namespace>/<pod>[/<container>])", spec)
+		}
+		src.Namespace, src.Name = parts[0], parts[1]
+		if len(parts) == 3 {
+			src.Container = parts[2]
+		}
+	case SourceDebug:
+		ns, labels, _ := strings.Cut(rest, "/")
+		if ns == "" {
+			return src, fmt.Errorf("invalid source %q (use debug:<namespace>[/<key=value,...>])", spec)
+		}
+		src.Namespace = ns
+		if labels != "" {
+			src.Labels = make(map[string]string)
+			for _, pair := range strings.Split(labels, ",") {
+				k, v, ok := strings.Cut(pair, "=")
+				if !ok || k == "" {
+					return src, fmt.Errorf("invalid label %q in source %q", pair, spec)
+				}
+				src.Labels[k] = v
+			}
+		}
+	case SourceDocker:
+		if rest == "" {
+			return src, fmt.Errorf("invalid source %q (use docker:<container>)", spec)
+		}
+		src.Name = rest
+	default:
+		return src, fmt.Errorf("unknown source %q (valid: local, pod:, debug:, docker:)", spec)
+	}
+	return src, nil
+}
+
+// ParseTarget parses and validates host:port
+func ParseTarget(spec string) (Target, error) {
+	host, port, err := net.SplitHostPort(spec)
+	if err != nil {
+		return Target{}, fmt.Errorf("invalid target %q (use host:port)", spec)
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	// Targets are interpolated into a shell command in remote sources
+	if !hostPattern.MatchString(host) || !portPattern.MatchString(port) {
+		return Target{}, fmt.Errorf("invalid target %q", spec)
+	}
+	return Target{Host: host, Port: port}, nil
+}
+
+// MatrixRunner probes targets from sources; clients are only needed for their source kinds
+type MatrixRunner struct {
+	K8s        *k8s.Client
+	Docker     *docker.Client
+	DebugImage string
+	Timeout    time.Duration
+}
+
+// Run probes every target from every source. Debug pods are created first and always deleted.
+func (r *MatrixRunner) Run(ctx context.Context, sources []Source, targets []Target) (*Matrix, error) {
+	sources = append([]Source(nil), sources...)
+	m := &Matrix{Results: make([][]ProbeResult, len(sources))}
+	for _, t := range targets {
+		m.Targets = append(m.Targets, t.String())
+	}
+
+	// Resolve debug sources to the pods created for them
+	for i, src := range sources {
+		m.Sources = append(m.Sources, src.Spec)
+		if src.Kind != SourceDebug {
+			continue
+		}
+		image := r.DebugImage
+		if image == "" {
+			image = DefaultDebugImage
+		}
+		name, err := r.K8s.CreateDebugPod(ctx, src.Namespace, image, src.Labels)
+		if err != nil {
+			return nil, err
+		}
+		defer func(ns, name string) {
+			_ = r.K8s.DeletePod(context.Background(), ns, name)
+		}(src.Namespace, name)
+		sources[i].Name = name
+	}
+
+	sem := make(chan struct{}, maxConcurrentProbes)
+	var wg sync.WaitGroup
+	for i, src := range sources {
+		m.Results[i] = make([]ProbeResult, len(targets))
+		for j, target := range targets {
+			wg.Add(1)
+			go func(i, j int, src Source, target Target) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				m.Results[i][j] = r.probe(ctx, src, target)
+			}(i, j, src, target)
+		}
+	}
+	wg.Wait()
+
+	return m, nil
+}
+
+func (r *MatrixRunner) probe(ctx context.Context, src Source, target Target) ProbeResult {
+	result := ProbeResult{Source: src.Spec, Target: target.String()}
+
+	if src.Kind == SourceLocal {
+		start := time.Now()
+		conn, err := (&net.Dialer{Timeout: r.Timeout}).DialContext(ctx, "tcp", target.String())
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		conn.Close()
+		result.Reachable = true
+		result.Latency = time.Since(start)
+		return result
+	}
+
+	command := []string{"sh", "-c", probeScript(target, r.Timeout)}
+	var out string
+	var code int
+	var err error
+	switch src.Kind {
+	case SourceDocker:
+		if r.Docker == nil {
+			err = fmt.Errorf("docker client not configured")
+			break
+		}
+		out, code, err = r.Docker.Exec(ctx, src.Name, command)
+	default:
+		if r.K8s == nil {
+			err = fmt.Errorf("kubernetes client not configured")
+			break
+		}
+		container := src.Container
+		if src.Kind == SourceDebug {
+			container = ""
+		}
+		out, code, err = r.K8s.Exec(ctx, src.Namespace, src.Name, container, command)
+	}
+
+	switch {
+	case err != nil:
+		result.Error = err.Error()
+	case code == 0:
+		result.Reachable = true
+	case code == exitNoProbeTool:
+		result.Error = "no nc or bash in the container to probe with"
+	default:
+		result.Error = "connection failed"
+		if out != "" {
+			result.Error = out
+		}
+	}
+	return result
+}
+
+// probeScript connects with nc, falling back to bash's /dev/tcp
+func probeScript(target Target, timeout time.Duration) string {
+	seconds := int(timeout.Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	host := strings.Trim(target.Host, "[]")
+	return fmt.Sprintf(
+		"if command -v nc >/dev/null 2>&1; then nc -z -w %d %s %s; "+
+			"elif command -v bash >/dev/null 2>&1; then timeout %d bash -c '</dev/tcp/%s/%s'; "+
+			"else exit %d; fi",
+		seconds, host, target.Port, seconds, host, target.Port, exitNoProbeTool)
+}
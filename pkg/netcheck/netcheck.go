@@ -0,0 +1,77 @@
+package netcheck
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// Result statuses, in increasing order of severity
+const (
+	StatusOK       = "ok"
+	StatusWarning  = "warning"
+	StatusCritical = "critical"
+	StatusError    = "error"
+)
+
+// Issue is a problem found by a check
+type Issue struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// statusRank orders statuses for --fail-on thresholds
+var statusRank = map[string]int{
+	StatusOK:       0,
+	StatusWarning:  1,
+	StatusCritical: 2,
+	StatusError:    3,
+}
+
+// AtLeast reports whether status is as severe as threshold ("none" never matches)
+func AtLeast(status, threshold string) bool {
+	if threshold == "none" {
+		return false
+	}
+	return statusRank[status] >= statusRank[threshold]
+}
+
+// worst returns the more severe status
+func worst(a, b string) string {
+	if statusRank[b] > statusRank[a] {
+		return b
+	}
+	return a
+}
+
+// ReadTargets reads one target per line from path; blank lines and # comments are skipped
+func ReadTargets(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open targets file: %w", err)
+	}
+	defer f.Close()
+
+	var targets []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line != "" {
+			targets = append(targets, line)
+		}
+	}
+	return targets, scanner.Err()
+}
+
+// withDefaultPort appends port when address has none
+func withDefaultPort(address, port string) string {
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return address
+	}
+	return net.JoinHostPort(strings.Trim(address, "[]"), port)
+}
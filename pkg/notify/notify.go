@@ -0,0 +1,168 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/logging"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/secrets"
+)
+
+// Message statuses
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+	StatusSuccess  = "success"
+	StatusFailure  = "failure"
+)
+
+// Field is a key metric shown with a message
+type Field struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Message is a notification independent of the destination
+type Message struct {
+	Title     string    `json:"title"`
+	Text      string    `json:"text"`
+	Status    string    `json:"status"`
+	Severity  string    `json:"severity,omitempty"`
+	Link      string    `json:"link,omitempty"`
+	Fields    []Field   `json:"fields,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Sink delivers messages to a destination
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// SinkConfig configures a sink
+type SinkConfig struct {
+	Type string `yaml:"type" mapstructure:"type"`
+	URL  string `yaml:"url" mapstructure:"url"`
+	// Channel overrides the channel of a Slack incoming webhook
+	Channel string `yaml:"channel,omitempty" mapstructure:"channel"`
+}
+
+// Config is the notify section of the config file
+type Config struct {
+	SlackWebhookURL string       `mapstructure:"slack_webhook_url"`
+	TeamsWebhookURL string       `mapstructure:"teams_webhook_url"`
+	WebhookURL      string       `mapstructure:"webhook_url"`
+	Sinks           []SinkConfig `mapstructure:"sinks"`
+}
+
+var config Config
+
+// Configure sets the default sinks; SLACK_WEBHOOK_URL, TEAMS_WEBHOOK_URL and
+// NOTIFY_WEBHOOK_URL override the config file
+func Configure(cfg Config) {
+	if url := os.Getenv("SLACK_WEBHOOK_URL"); url != "" {
+		cfg.SlackWebhookURL = url
+	}
+	if url := os.Getenv("TEAMS_WEBHOOK_URL"); url != "" {
+		cfg.TeamsWebhookURL = url
+	}
+	if url := os.Getenv("NOTIFY_WEBHOOK_URL"); url != "" {
+		cfg.WebhookURL = url
+	}
+	config = cfg
+}
+
+// Sinks returns the configured default sinks
+func Sinks() ([]Sink, error) {
+	configs := append([]SinkConfig{}, config.Sinks...)
+	if config.SlackWebhookURL != "" {
+		configs = append(configs, SinkConfig{Type: "slack", URL: config.SlackWebhookURL})
+	}
+	if config.TeamsWebhookURL != "" {
+		configs = append(configs, SinkConfig{Type: "teams", URL: config.TeamsWebhookURL})
+	}
+	if config.WebhookURL != "" {
+		configs = append(configs, SinkConfig{Type: "webhook", URL: config.WebhookURL})
+	}
+
+	sinks := make([]Sink, 0, len(configs))
+	for _, cfg := range configs {
+		sink, err := NewSink(cfg)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// SlackWebhookURL returns the default Slack webhook, empty if none is configured
+func SlackWebhookURL() string {
+	return config.SlackWebhookURL
+}
+
+// NewSink creates the sink described by cfg
+func NewSink(cfg SinkConfig) (Sink, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("%s sink: url is required", cfg.Type)
+	}
+	url, err := secrets.Resolve(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%s sink: %w", cfg.Type, err)
+	}
+
+	switch cfg.Type {
+	case "slack":
+		return &slackSink{url: url, channel: cfg.Channel, httpClient: newHTTPClient()}, nil
+	case "teams":
+		return &teamsSink{url: url, httpClient: newHTTPClient()}, nil
+	case "webhook":
+		return &webhookSink{url: url, httpClient: newHTTPClient()}, nil
+	default:
+		return nil, fmt.Errorf("unknown sink type %q (valid: slack, teams, webhook)", cfg.Type)
+	}
+}
+
+// Send delivers msg to every sink and joins their errors
+func Send(ctx context.Context, sinks []Sink, msg Message) error {
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+
+	var errs []error
+	for _, s := range sinks {
+		if err := s.Send(ctx, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// tone classifies a message as good, warning, danger or info for sink colors
+func (m Message) tone() string {
+	switch m.Status {
+	case StatusResolved, StatusSuccess:
+		return "good"
+	case StatusFiring:
+		if m.Severity == "critical" {
+			return "danger"
+		}
+		return "warning"
+	case StatusFailure:
+		return "danger"
+	default:
+		return "info"
+	}
+}
+
+func newHTTPClient() *http.Client {
+	transport := http.DefaultTransport
+	if logging.Enabled() {
+		transport = logging.Transport(transport)
+	}
+	return &http.Client{Timeout: 15 * time.Second, Transport: transport}
+}
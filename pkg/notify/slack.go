@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// slackColors are the attachment bar colors by tone
+var slackColors = map[string]string{
+	"good":    "#16A34A",
+	"warning": "#F59E0B",
+	"danger":  "#DC2626",
+	"info":    "#2563EB",
+}
+
+// slackSink posts Block Kit messages to a Slack incoming webhook
+type slackSink struct {
+	url        string
+	channel    string
+	httpClient *http.Client
+}
+
+func (s *slackSink) Name() string {
+	return "slack"
+}
+
+func (s *slackSink) Send(ctx context.Context, msg Message) error {
+	payload := slackPayload(msg)
+	if s.channel != "" {
+		payload["channel"] = s.channel
+	}
+	return postJSON(ctx, s.httpClient, s.url, payload)
+}
+
+// slackPayload wraps the blocks in an attachment so Slack shows the status color bar
+func slackPayload(msg Message) map[string]interface{} {
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]interface{}{"type": "plain_text", "text": truncate(msg.Title, 150)},
+		},
+	}
+
+	if msg.Text != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": msg.Text},
+		})
+	}
+
+	// Slack allows at most 10 fields per section
+	for i := 0; i < len(msg.Fields); i += 10 {
+		end := i + 10
+		if end > len(msg.Fields) {
+			end = len(msg.Fields)
+		}
+		fields := make([]map[string]interface{}, 0, end-i)
+		for _, f := range msg.Fields[i:end] {
+			fields = append(fields, map[string]interface{}{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("*%s*\n%s", f.Name, f.Value),
+			})
+		}
+		blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields})
+	}
+
+	if msg.Link != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "actions",
+			"elements": []map[string]interface{}{{
+				"type": "button",
+				"text": map[string]interface{}{"type": "plain_text", "text": "View details"},
+				"url":  msg.Link,
+			}},
+		})
+	}
+
+	blocks = append(blocks, map[string]interface{}{
+		"type": "context",
+		"elements": []map[string]interface{}{{
+			"type": "mrkdwn",
+			"text": fmt.Sprintf("devops-toolkit • %s", msg.Timestamp.Format("2006-01-02 15:04:05 MST")),
+		}},
+	})
+
+	return map[string]interface{}{
+		// Fallback for notifications and clients without block support
+		"text": msg.Title,
+		"attachments": []map[string]interface{}{{
+			"color":  slackColors[msg.tone()],
+			"blocks": blocks,
+		}},
+	}
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+)
+
+// teamsSink posts Adaptive Cards to a Microsoft Teams webhook (Workflows or legacy connector)
+type teamsSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+func (s *teamsSink) Name() string {
+	return "teams"
+}
+
+func (s *teamsSink) Send(ctx context.Context, msg Message) error {
+	return postJSON(ctx, s.httpClient, s.url, teamsPayload(msg))
+}
+
+func teamsPayload(msg Message) map[string]interface{} {
+	body := []map[string]interface{}{
+		{
+			"type":   "TextBlock",
+			"text":   msg.Title,
+			"size":   "Large",
+			"weight": "Bolder",
+			"color":  teamsColors[msg.tone()],
+			"wrap":   true,
+		},
+	}
+
+	if msg.Text != "" {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": msg.Text, "wrap": true})
+	}
+
+	if len(msg.Fields) > 0 {
+		facts := make([]map[string]interface{}, 0, len(msg.Fields))
+		for _, f := range msg.Fields {
+			facts = append(facts, map[string]interface{}{"title": f.Name, "value": f.Value})
+		}
+		body = append(body, map[string]interface{}{"type": "FactSet", "facts": facts})
+	}
+
+	body = append(body, map[string]interface{}{
+		"type":     "TextBlock",
+		"text":     "devops-toolkit • " + msg.Timestamp.Format("2006-01-02 15:04:05 MST"),
+		"isSubtle": true,
+		"size":     "Small",
+		"wrap":     true,
+	})
+
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if msg.Link != "" {
+		card["actions"] = []map[string]interface{}{{
+			"type":  "Action.OpenUrl",
+			"title": "View details",
+			"url":   msg.Link,
+		}}
+	}
+
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     card,
+		}},
+	}
+}
+
+// teamsColors are the Adaptive Card title colors by tone
+var teamsColors = map[string]string{
+	"good":    "Good",
+	"warning": "Warning",
+	"danger":  "Attention",
+	"info":    "Accent",
+}
@@ -0,0 +1,58 @@
+package output
+
+import (
+	"os"
+
+	"github.com/briandowns/spinner"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"github.com/muesli/termenv"
+)
+
+var (
+	// outFile is the file behind out, used for terminal detection
+	outFile = os.Stdout
+
+	noColorRequested bool
+	colorEnabled     = true
+)
+
+// ConfigureColor disables ANSI colors, spinners and progress bars when noColor
+// is set, NO_COLOR is present, TERM is dumb, or output is not a terminal
+func ConfigureColor(noColor bool) {
+	noColorRequested = noColor
+	applyColor()
+}
+
+// ColorEnabled reports whether colored, animated output is enabled
+func ColorEnabled() bool {
+	return colorEnabled
+}
+
+// IsTerminal reports whether f is an interactive terminal
+func IsTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+func applyColor() {
+	_, noColorEnv := os.LookupEnv("NO_COLOR")
+	colorEnabled = !noColorRequested && !noColorEnv && os.Getenv("TERM") != "dumb" && IsTerminal(outFile)
+
+	color.NoColor = !colorEnabled || themeMono
+	if colorEnabled {
+		lipgloss.SetColorProfile(termenv.NewOutput(outFile).EnvColorProfile())
+		defaultPrinter.spinner.Enable()
+	} else {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		defaultPrinter.spinner.Disable()
+	}
+}
+
+// setWriter redirects human-oriented output and the spinner to f
+func setWriter(f *os.File) {
+	out = f
+	outFile = f
+	spinner.WithWriterFile(f)(defaultPrinter.spinner)
+	applyColor()
+}
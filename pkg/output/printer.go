@@ -2,6 +2,7 @@ package output
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"time"
 
@@ -26,20 +27,23 @@ func NewPrinter() *Printer {
 // Default printer instance
 var defaultPrinter = NewPrinter()
 
+// out receives human-oriented output; it switches to stderr for json/yaml output
+var out io.Writer = os.Stdout
+
 // Print outputs a message
 func Print(msg string) {
-	fmt.Println(msg)
+	fmt.Fprintln(out, msg)
 }
 
 // Printf outputs a formatted message
 func Printf(format string, args ...interface{}) {
-	fmt.Printf(format, args...)
+	fmt.Fprintf(out, format, args...)
 }
 
 // Success prints a success message
 func Success(msg string) {
 	icon := SuccessStyle.Render(IconSuccess)
-	fmt.Printf("%s %s\n", icon, msg)
+	fmt.Fprintf(out, "%s %s\n", icon, msg)
 }
 
 // Successf prints a formatted success message
@@ -50,7 +54,7 @@ func Successf(format string, args ...interface{}) {
 // Warning prints a warning message
 func Warning(msg string) {
 	icon := WarningStyle.Render(IconWarning)
-	fmt.Printf("%s %s\n", icon, msg)
+	fmt.Fprintf(out, "%s %s\n", icon, msg)
 }
 
 // Warningf prints a formatted warning message
@@ -72,7 +76,7 @@ func Errorf(format string, args ...interface{}) {
 // Info prints an info message
 func Info(msg string) {
 	icon := InfoStyle.Render(IconInfo)
-	fmt.Printf("%s %s\n", icon, msg)
+	fmt.Fprintf(out, "%s %s\n", icon, msg)
 }
 
 // Infof prints a formatted info message
@@ -82,25 +86,25 @@ func Infof(format string, args ...interface{}) {
 
 // Muted prints a muted/dim message
 func Muted(msg string) {
-	fmt.Println(MutedStyle.Render(msg))
+	fmt.Fprintln(out, MutedStyle.Render(msg))
 }
 
 // Title prints a title
 func Title(msg string) {
-	fmt.Println()
-	fmt.Println(TitleStyle.Render(msg))
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, TitleStyle.Render(msg))
 }
 
 // Subtitle prints a subtitle
 func Subtitle(msg string) {
-	fmt.Println(SubtitleStyle.Render(msg))
+	fmt.Fprintln(out, SubtitleStyle.Render(msg))
 }
 
 // Header prints a header with box style
 func Header(msg string) {
-	fmt.Println()
-	fmt.Println(HeaderBoxStyle.Render(msg))
-	fmt.Println()
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, HeaderBoxStyle.Render(msg))
+	fmt.Fprintln(out)
 }
 
 // Banner prints an application banner
@@ -118,9 +122,9 @@ func Banner(name, version, description string) {
 		Foreground(SecondaryColor).
 		Italic(true)
 
-	fmt.Println(bannerStyle.Render(name) + " " + versionStyle.Render(version))
-	fmt.Println(descStyle.Render(description))
-	fmt.Println()
+	fmt.Fprintln(out, bannerStyle.Render(name)+" "+versionStyle.Render(version))
+	fmt.Fprintln(out, descStyle.Render(description))
+	fmt.Fprintln(out)
 }
 
 // StartSpinner starts a spinner with message
@@ -129,6 +133,13 @@ func StartSpinner(msg string) {
 	defaultPrinter.spinner.Start()
 }
 
+// UpdateSpinner changes the message of a running spinner
+func UpdateSpinner(msg string) {
+	defaultPrinter.spinner.Lock()
+	defaultPrinter.spinner.Suffix = " " + msg
+	defaultPrinter.spinner.Unlock()
+}
+
 // StopSpinner stops the spinner
 func StopSpinner() {
 	defaultPrinter.spinner.Stop()
@@ -146,12 +157,26 @@ func SpinnerError(msg string) {
 	Error(msg)
 }
 
+// SpinnerWarning prints a warning above a running spinner and keeps it running
+func SpinnerWarning(msg string) {
+	active := defaultPrinter.spinner.Active()
+	defaultPrinter.spinner.Stop()
+	Warning(msg)
+	if active {
+		defaultPrinter.spinner.Start()
+	}
+}
+
 // ProgressBar renders a simple progress bar
 func ProgressBar(current, total int, width int) string {
 	if total == 0 {
 		total = 1
 	}
 	percentage := float64(current) / float64(total)
+
+	if !colorEnabled {
+		return fmt.Sprintf("%3.0f%%", percentage*100)
+	}
 	filled := int(percentage * float64(width))
 	empty := width - filled
 
@@ -177,7 +202,7 @@ func repeatChar(char string, count int) string {
 // List prints a bulleted list
 func List(items []string) {
 	for _, item := range items {
-		fmt.Printf("  %s %s\n", MutedStyle.Render(IconBullet), item)
+		fmt.Fprintf(out, "  %s %s\n", MutedStyle.Render(IconBullet), item)
 	}
 }
 
@@ -185,19 +210,39 @@ func List(items []string) {
 func NumberedList(items []string) {
 	for i, item := range items {
 		num := InfoStyle.Render(fmt.Sprintf("%2d.", i+1))
-		fmt.Printf("  %s %s\n", num, item)
+		fmt.Fprintf(out, "  %s %s\n", num, item)
 	}
 }
 
 // Tree prints items in a tree structure
 func Tree(root string, children []string) {
-	fmt.Printf("  %s\n", root)
+	node := TreeNode{Label: root}
+	for _, child := range children {
+		node.Children = append(node.Children, TreeNode{Label: child})
+	}
+	PrintTree(node)
+}
+
+// TreeNode is a labelled node of a tree printed by PrintTree
+type TreeNode struct {
+	Label    string
+	Children []TreeNode
+}
+
+// PrintTree prints a tree of any depth
+func PrintTree(root TreeNode) {
+	fmt.Fprintf(out, "  %s\n", root.Label)
+	printTreeChildren(root.Children, "  ")
+}
+
+func printTreeChildren(children []TreeNode, indent string) {
 	for i, child := range children {
-		prefix := IconTee
+		prefix, next := IconTee, IconPipe+"  "
 		if i == len(children)-1 {
-			prefix = IconCorner
+			prefix, next = IconCorner, "   "
 		}
-		fmt.Printf("  %s%s %s\n", MutedStyle.Render(prefix), MutedStyle.Render(IconDash), child)
+		fmt.Fprintf(out, "%s%s%s %s\n", indent, MutedStyle.Render(prefix), MutedStyle.Render(IconDash), child.Label)
+		printTreeChildren(child.Children, indent+MutedStyle.Render(next))
 	}
 }
 
@@ -233,16 +278,32 @@ func StatusIcon(status string) string {
 
 // Summary prints a summary box
 func Summary(title string, items map[string]string) {
-	fmt.Println()
-	fmt.Println(HeaderBoxStyle.Render(title))
-	fmt.Println()
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, HeaderBoxStyle.Render(title))
+	fmt.Fprintln(out)
 	for key, value := range items {
-		fmt.Println(KeyValue(key, value))
+		fmt.Fprintln(out, KeyValue(key, value))
 	}
-	fmt.Println()
+	fmt.Fprintln(out)
 }
 
 // Newline prints an empty line
 func Newline() {
-	fmt.Println()
+	fmt.Fprintln(out)
+}
+
+// HighlightLog colors a log line by its detected level
+func HighlightLog(level, content string) string {
+	switch level {
+	case "error", "fatal", "panic":
+		return ErrorStyle.Render(content)
+	case "warn", "warning":
+		return WarningStyle.Render(content)
+	case "info":
+		return InfoStyle.Render(content)
+	case "debug", "trace":
+		return MutedStyle.Render(content)
+	default:
+		return content
+	}
 }
@@ -0,0 +1,264 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the output format selected with the global --output flag
+type Format string
+
+const (
+	FormatTable         Format = "table"
+	FormatJSON          Format = "json"
+	FormatYAML          Format = "yaml"
+	FormatCustomColumns Format = "custom-columns"
+	FormatGoTemplate    Format = "go-template"
+)
+
+// column is a single custom-columns entry, e.g. NAME:.metadata.name
+type column struct {
+	Header string
+	Path   []string
+}
+
+var (
+	currentFormat = FormatTable
+	columns       []column
+	goTemplate    *template.Template
+	quiet         bool
+)
+
+// SetFormat selects the output format. In structured modes (json, yaml,
+// custom-columns=..., go-template=...), decorative output (headers, spinners,
+// tables) moves to stderr so stdout stays parseable.
+func SetFormat(format string) error {
+	format = strings.TrimSpace(format)
+	name, arg, hasArg := strings.Cut(format, "=")
+
+	switch f := Format(strings.ToLower(name)); f {
+	case "", FormatTable:
+		currentFormat = FormatTable
+		setWriter(os.Stdout)
+		return nil
+	case FormatJSON, FormatYAML:
+		currentFormat = f
+	case FormatCustomColumns:
+		cols, err := parseColumns(arg)
+		if err != nil {
+			return err
+		}
+		currentFormat, columns = f, cols
+	case FormatGoTemplate:
+		if !hasArg || arg == "" {
+			return fmt.Errorf("go-template output requires a template, e.g. go-template='{{range .}}{{.name}}{{\"\\n\"}}{{end}}'")
+		}
+		tmpl, err := template.New("output").Parse(arg)
+		if err != nil {
+			return fmt.Errorf("invalid go-template: %w", err)
+		}
+		currentFormat, goTemplate = f, tmpl
+	default:
+		return fmt.Errorf("invalid output format %q (valid: table, json, yaml, custom-columns=..., go-template=...)", format)
+	}
+
+	setWriter(os.Stderr)
+	return nil
+}
+
+// parseColumns parses a custom-columns spec such as "NAME:.name,STATUS:.status"
+func parseColumns(spec string) ([]column, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("custom-columns output requires a spec, e.g. custom-columns=NAME:.name,STATUS:.status")
+	}
+
+	var cols []column
+	for _, part := range strings.Split(spec, ",") {
+		header, path, ok := strings.Cut(part, ":")
+		if !ok || header == "" || !strings.HasPrefix(path, ".") {
+			return nil, fmt.Errorf("invalid custom-columns entry %q (expected HEADER:.field)", part)
+		}
+		cols = append(cols, column{
+			Header: header,
+			Path:   strings.Split(strings.TrimPrefix(path, "."), "."),
+		})
+	}
+	return cols, nil
+}
+
+// GetFormat returns the current output format
+func GetFormat() Format {
+	return currentFormat
+}
+
+// IsStructured reports whether output should be machine-readable rather than a rich table
+func IsStructured() bool {
+	return currentFormat != FormatTable
+}
+
+// SetQuiet switches to identifier-only output for -q/--quiet: spinners, headers
+// and summaries are discarded so stdout carries nothing but PrintIDs output.
+// Errors still go to stderr.
+func SetQuiet() {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		devNull = os.Stderr
+	}
+	quiet = true
+	setWriter(devNull)
+}
+
+// IsQuiet reports whether only identifiers should be printed
+func IsQuiet() bool {
+	return quiet
+}
+
+// PrintIDs writes one identifier per line to stdout
+func PrintIDs(ids []string) error {
+	for _, id := range ids {
+		if _, err := fmt.Fprintln(os.Stdout, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render writes v to stdout in the current structured format
+func Render(v interface{}) error {
+	return RenderTo(os.Stdout, v)
+}
+
+// RenderStale renders a listing like Render; when stale is set, every item
+// gets "stale": true so scripts can tell a cached result from a live one
+func RenderStale(v interface{}, stale bool) error {
+	if !stale {
+		return Render(v)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	var items []interface{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	for _, item := range items {
+		if fields, ok := item.(map[string]interface{}); ok {
+			fields["stale"] = true
+		}
+	}
+	return Render(items)
+}
+
+// RenderTo writes v to w in the current structured format.
+// All formats work from the JSON encoding so they share field names.
+func RenderTo(w io.Writer, v interface{}) error {
+	// Encode empty listings as [] rather than null
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.IsNil() {
+		v = reflect.MakeSlice(rv.Type(), 0, 0).Interface()
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	if currentFormat == FormatJSON || currentFormat == FormatTable {
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	switch currentFormat {
+	case FormatCustomColumns:
+		return renderColumns(w, generic)
+	case FormatGoTemplate:
+		if err := goTemplate.Execute(w, generic); err != nil {
+			return fmt.Errorf("failed to execute go-template: %w", err)
+		}
+		return nil
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(generic); err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	return enc.Close()
+}
+
+// renderColumns writes one aligned row per item using the custom-columns spec
+func renderColumns(w io.Writer, data interface{}) error {
+	items, ok := data.([]interface{})
+	if !ok {
+		items = []interface{}{data}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 3, ' ', 0)
+
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.Header
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, item := range items {
+		cells := make([]string, len(columns))
+		for i, col := range columns {
+			cells[i] = lookupField(item, col.Path)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// lookupField follows a dotted JSON path (numeric segments index arrays) and
+// formats the value, returning <none> when it is missing
+func lookupField(v interface{}, path []string) string {
+	for _, key := range path {
+		if key == "" {
+			continue
+		}
+		switch node := v.(type) {
+		case map[string]interface{}:
+			v = node[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return "<none>"
+			}
+			v = node[i]
+		default:
+			return "<none>"
+		}
+	}
+
+	switch value := v.(type) {
+	case nil:
+		return "<none>"
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(value)
+		return string(data)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,60 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestRenderStale(t *testing.T) {
+	if err := SetFormat("json"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = SetFormat("table") }()
+
+	type item struct {
+		Name string `json:"name"`
+	}
+	items := []item{{Name: "a"}, {Name: "b"}}
+
+	for _, stale := range []bool{false, true} {
+		got := captureStdout(t, func() error { return RenderStale(items, stale) })
+
+		var decoded []map[string]interface{}
+		if err := json.Unmarshal(got, &decoded); err != nil {
+			t.Fatalf("stale=%v: invalid JSON %q: %v", stale, got, err)
+		}
+		if len(decoded) != len(items) {
+			t.Fatalf("stale=%v: got %d items, want %d", stale, len(decoded), len(items))
+		}
+		for _, d := range decoded {
+			if _, ok := d["stale"]; ok != stale {
+				t.Errorf("stale=%v: item %v has stale field = %v", stale, d, ok)
+			}
+		}
+	}
+}
+
+func captureStdout(t *testing.T, fn func() error) []byte {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	runErr := fn()
+	os.Stdout = stdout
+	w.Close()
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	if runErr != nil {
+		t.Fatal(runErr)
+	}
+	return buf.Bytes()
+}
@@ -3,7 +3,6 @@ package output
 import (
 	"fmt"
 	"io"
-	"os"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -48,9 +47,14 @@ func (t *Table) AddColoredRow(row []string, colors []tablewriter.Colors) {
 	t.colors = append(t.colors, colors)
 }
 
-// Render renders the table to stdout
+// Render renders the table to stdout, or stderr when json/yaml output is selected.
+// Rows are filtered and sorted by the global --filter and --sort-by flags, and
+// rows that changed since the last refresh are highlighted under --watch.
+// Long cells are wrapped so the table fits the terminal.
 func (t *Table) Render() {
-	t.RenderTo(os.Stdout)
+	query.apply(t)
+	highlightChanges(t)
+	t.RenderTo(out)
 }
 
 // RenderTo renders the table to the specified writer
@@ -74,9 +78,15 @@ func (t *Table) RenderTo(w io.Writer) {
 
 	if t.config.ShowBorder {
 		table.SetBorder(true)
-		table.SetCenterSeparator("┼")
-		table.SetColumnSeparator("│")
-		table.SetRowSeparator("─")
+		if themeASCII {
+			table.SetCenterSeparator("+")
+			table.SetColumnSeparator("|")
+			table.SetRowSeparator("-")
+		} else {
+			table.SetCenterSeparator("┼")
+			table.SetColumnSeparator("│")
+			table.SetRowSeparator("─")
+		}
 		table.SetHeaderLine(true)
 		table.SetRowLine(false)
 	} else {
@@ -92,15 +102,25 @@ func (t *Table) RenderTo(w io.Writer) {
 	}
 
 	// Header colors
-	headerColors := make([]tablewriter.Colors, len(t.config.Headers))
-	for i := range headerColors {
-		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiMagentaColor}
+	if colorEnabled && !themeMono {
+		headerColors := make([]tablewriter.Colors, len(t.config.Headers))
+		for i := range headerColors {
+			headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiMagentaColor}
+		}
+		table.SetHeaderColor(headerColors...)
+	}
+
+	// Wrap long cells rather than overflow the terminal
+	rows := t.rows
+	if w == out {
+		if width := terminalWidth(); width > 0 {
+			rows = fitColumns(t.config.Headers, rows, width)
+		}
 	}
-	table.SetHeaderColor(headerColors...)
 
 	// Add rows
-	for i, row := range t.rows {
-		if t.colors[i] != nil {
+	for i, row := range rows {
+		if t.colors[i] != nil && colorEnabled && !themeMono {
 			table.Rich(row, t.colors[i])
 		} else {
 			table.Append(row)
@@ -182,7 +202,7 @@ func StatusRow(component, status, details string) ([]string, []tablewriter.Color
 // Panel renders a styled panel/box with content
 func Panel(title, content string) string {
 	style := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(roundedBorder()).
 		BorderForeground(PrimaryColor).
 		Padding(1, 2).
 		Width(60)
@@ -192,7 +212,7 @@ func Panel(title, content string) string {
 		Foreground(PrimaryColor)
 
 	contentStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#E5E7EB"))
+		Foreground(TextColor)
 
 	fullContent := titleStyle.Render(title) + "\n\n" + contentStyle.Render(content)
 	return style.Render(fullContent)
@@ -204,19 +224,19 @@ func KeyValue(key, value string) string {
 		Foreground(MutedColor).
 		Width(20)
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#E5E7EB"))
+		Foreground(TextColor)
 
 	return keyStyle.Render(key+":") + " " + valueStyle.Render(value)
 }
 
 // Divider renders a horizontal divider
 func Divider(width int) string {
-	return MutedStyle.Render(strings.Repeat("─", width))
+	return MutedStyle.Render(strings.Repeat(IconDash, width))
 }
 
 // Section renders a section header
 func Section(title string) string {
-	return "\n" + TitleStyle.Render("▸ "+title) + "\n"
+	return "\n" + TitleStyle.Render(IconSection+" "+title) + "\n"
 }
 
 // SubSection renders a subsection header
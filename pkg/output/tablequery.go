@@ -0,0 +1,278 @@
+package output
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// tableQuery is the --sort-by/--filter selection applied to every rendered table
+type tableQuery struct {
+	sortBy  string
+	desc    bool
+	filters []rowFilter
+
+	// rendered and matched track whether any table had the requested columns
+	rendered bool
+	matched  map[string]bool
+}
+
+// rowFilter is one --filter expression such as status=Running or restarts>5
+type rowFilter struct {
+	column string
+	op     string
+	value  string
+}
+
+var query tableQuery
+
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// filterOps are tried in order so that != is not read as =
+var filterOps = []string{"!=", ">=", "<=", "=", ">", "<"}
+
+// SetTableQuery sets the column to sort tables by and the filter expressions rows
+// must match. A leading "-" on sortBy sorts descending. Filters are column=value
+// (with * and ? wildcards), column!=value, or column>value / column<value for
+// numbers, sizes, percentages and ages. Columns are matched by header name,
+// ignoring case, spaces, dashes and underscores.
+func SetTableQuery(sortBy string, filters []string) error {
+	q := tableQuery{matched: map[string]bool{}}
+
+	if sortBy = strings.TrimSpace(sortBy); sortBy != "" {
+		q.desc = strings.HasPrefix(sortBy, "-")
+		q.sortBy = normalizeColumn(strings.TrimPrefix(sortBy, "-"))
+	}
+
+	for _, expr := range filters {
+		f, err := parseRowFilter(expr)
+		if err != nil {
+			return err
+		}
+		q.filters = append(q.filters, f)
+	}
+
+	query = q
+	return nil
+}
+
+// CheckTableQuery returns an error when tables were rendered but none had a
+// column named by --sort-by or --filter, which usually means a typo
+func CheckTableQuery() error {
+	if !query.rendered {
+		return nil
+	}
+	if query.sortBy != "" && !query.matched[query.sortBy] {
+		return fmt.Errorf("no table column matches --sort-by %q", query.sortBy)
+	}
+	for _, f := range query.filters {
+		if !query.matched[f.column] {
+			return fmt.Errorf("no table column matches --filter %q", f.column)
+		}
+	}
+	return nil
+}
+
+func parseRowFilter(expr string) (rowFilter, error) {
+	for _, op := range filterOps {
+		if i := strings.Index(expr, op); i > 0 {
+			return rowFilter{
+				column: normalizeColumn(expr[:i]),
+				op:     op,
+				value:  strings.TrimSpace(expr[i+len(op):]),
+			}, nil
+		}
+	}
+	return rowFilter{}, fmt.Errorf("invalid --filter %q (use column=value, column!=value, column>value or column<value)", expr)
+}
+
+// normalizeColumn makes "Image ID", "image-id" and "image_id" compare equal
+func normalizeColumn(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(name)
+}
+
+// apply filters and sorts the table rows in place according to the query
+func (q *tableQuery) apply(t *Table) {
+	if q.sortBy == "" && len(q.filters) == 0 {
+		return
+	}
+	q.rendered = true
+
+	index := map[string]int{}
+	for i, h := range t.config.Headers {
+		index[normalizeColumn(h)] = i
+	}
+
+	for _, f := range q.filters {
+		col, ok := index[f.column]
+		if !ok {
+			continue
+		}
+		q.matched[f.column] = true
+
+		var rows [][]string
+		var colors [][]tablewriter.Colors
+		for i, row := range t.rows {
+			if col < len(row) && f.match(cellText(row[col])) {
+				rows = append(rows, row)
+				colors = append(colors, t.colors[i])
+			}
+		}
+		t.rows, t.colors = rows, colors
+	}
+
+	col, ok := index[q.sortBy]
+	if !ok {
+		return
+	}
+	q.matched[q.sortBy] = true
+
+	order := make([]int, len(t.rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		x, y := cellAt(t.rows[order[a]], col), cellAt(t.rows[order[b]], col)
+		_, xok := cellNumber(x)
+		_, yok := cellNumber(y)
+		if q.desc && xok == yok {
+			return compareCells(y, x) < 0
+		}
+		return compareCells(x, y) < 0
+	})
+
+	rows := make([][]string, len(order))
+	colors := make([][]tablewriter.Colors, len(order))
+	for i, j := range order {
+		rows[i], colors[i] = t.rows[j], t.colors[j]
+	}
+	t.rows, t.colors = rows, colors
+}
+
+func (f rowFilter) match(cell string) bool {
+	switch f.op {
+	case "=", "!=":
+		matched := strings.EqualFold(cell, f.value)
+		if !matched {
+			matched, _ = filepath.Match(strings.ToLower(f.value), strings.ToLower(cell))
+		}
+		return matched == (f.op == "=")
+	}
+
+	if _, ok := cellNumber(cell); !ok {
+		return false
+	}
+	cmp := compareCells(cell, f.value)
+	switch f.op {
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	case ">=":
+		return cmp >= 0
+	default:
+		return cmp <= 0
+	}
+}
+
+func cellAt(row []string, col int) string {
+	if col >= len(row) {
+		return ""
+	}
+	return cellText(row[col])
+}
+
+// cellText strips styling and a leading status icon so "✗ failed" matches status=failed
+func cellText(cell string) string {
+	cell = strings.TrimSpace(ansiPattern.ReplaceAllString(cell, ""))
+	for _, icon := range []string{IconSuccess, IconWarning, IconError, IconInfo, IconRunning,
+		IconPending, IconCheck, IconCross, IconStar, IconDot} {
+		if strings.HasPrefix(cell, icon+" ") {
+			return strings.TrimSpace(cell[len(icon):])
+		}
+	}
+	return cell
+}
+
+// compareCells orders numeric-looking cells by value and everything else
+// alphabetically; cells without a number, such as "-", sort last
+func compareCells(a, b string) int {
+	x, xok := cellNumber(a)
+	y, yok := cellNumber(b)
+	switch {
+	case xok && yok:
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case xok:
+		return -1
+	case yok:
+		return 1
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+var (
+	numberPattern   = regexp.MustCompile(`^-?[0-9][0-9,]*(\.[0-9]+)?`)
+	durationPattern = regexp.MustCompile(`^([0-9]+(\.[0-9]+)?(ms|s|m|h|d|w|y))+$`)
+	durationPart    = regexp.MustCompile(`([0-9]+(?:\.[0-9]+)?)(ms|s|m|h|d|w|y)`)
+)
+
+var sizeUnits = map[string]float64{
+	"b": 1, "k": 1e3, "kb": 1e3, "m": 1e6, "mb": 1e6, "g": 1e9, "gb": 1e9, "t": 1e12, "tb": 1e12,
+	"ki": 1 << 10, "kib": 1 << 10, "mi": 1 << 20, "mib": 1 << 20, "gi": 1 << 30, "gib": 1 << 30,
+	"ti": 1 << 40, "tib": 1 << 40,
+}
+
+var durationUnits = map[string]time.Duration{
+	"ms": time.Millisecond, "s": time.Second, "m": time.Minute, "h": time.Hour,
+	"d": 24 * time.Hour, "w": 7 * 24 * time.Hour, "y": 365 * 24 * time.Hour,
+}
+
+// cellNumber reads a cell as a number: plain numbers, percentages, ready
+// counts like 2/3 (by the first number), sizes like 1.2 GB or 512Mi, and
+// ages like 3d4h
+func cellNumber(cell string) (float64, bool) {
+	s := strings.ToLower(strings.TrimSpace(strings.TrimSuffix(cell, " ago")))
+	if s == "" {
+		return 0, false
+	}
+
+	if durationPattern.MatchString(s) {
+		var total time.Duration
+		for _, m := range durationPart.FindAllStringSubmatch(s, -1) {
+			n, _ := strconv.ParseFloat(m[1], 64)
+			total += time.Duration(n * float64(durationUnits[m[2]]))
+		}
+		return float64(total), true
+	}
+
+	num := numberPattern.FindString(s)
+	if num == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(num, ",", ""), 64)
+	if err != nil {
+		return 0, false
+	}
+
+	rest := strings.TrimSpace(s[len(num):])
+	switch {
+	case rest == "" || rest == "%" || strings.HasPrefix(rest, "/"):
+		return n, true
+	case sizeUnits[rest] > 0:
+		return n * sizeUnits[rest], true
+	}
+	return 0, false
+}
@@ -0,0 +1,27 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+)
+
+func TestCellNumberDurations(t *testing.T) {
+	tests := []struct {
+		cell string
+		want time.Duration
+	}{
+		{"45s", 45 * time.Second},
+		{"3d4h", 3*24*time.Hour + 4*time.Hour},
+		{"5m ago", 5 * time.Minute},
+		{format.Duration(2*time.Minute + 5*time.Second), 2*time.Minute + 5*time.Second},
+		{format.Duration(time.Hour + 12*time.Minute), time.Hour + 12*time.Minute},
+	}
+	for _, tt := range tests {
+		got, ok := cellNumber(tt.cell)
+		if !ok || time.Duration(got) != tt.want {
+			t.Errorf("cellNumber(%q) = %v, %v; want %v", tt.cell, time.Duration(got), ok, tt.want)
+		}
+	}
+}
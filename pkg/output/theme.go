@@ -1,6 +1,11 @@
 package output
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/briandowns/spinner"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/fatih/color"
 )
@@ -22,75 +27,31 @@ var (
 	MutedColor      = lipgloss.Color("#6B7280") // Gray
 	BackgroundColor = lipgloss.Color("#1F2937") // Dark gray
 	BorderColor     = lipgloss.Color("#374151") // Medium gray
+	TextColor       = lipgloss.Color("#E5E7EB") // Light gray
 )
 
 // Styled text helpers
 var (
 	// Title styles
-	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(PrimaryColor).
-			MarginBottom(1)
-
-	SubtitleStyle = lipgloss.NewStyle().
-			Foreground(SecondaryColor).
-			Italic(true)
+	TitleStyle    lipgloss.Style
+	SubtitleStyle lipgloss.Style
 
 	// Status styles
-	SuccessStyle = lipgloss.NewStyle().
-			Foreground(SuccessColor).
-			Bold(true)
-
-	WarningStyle = lipgloss.NewStyle().
-			Foreground(WarningColor).
-			Bold(true)
-
-	ErrorStyle = lipgloss.NewStyle().
-			Foreground(ErrorColor).
-			Bold(true)
-
-	InfoStyle = lipgloss.NewStyle().
-			Foreground(InfoColor)
-
-	MutedStyle = lipgloss.NewStyle().
-			Foreground(MutedColor)
+	SuccessStyle lipgloss.Style
+	WarningStyle lipgloss.Style
+	ErrorStyle   lipgloss.Style
+	InfoStyle    lipgloss.Style
+	MutedStyle   lipgloss.Style
 
 	// Box styles
-	BoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(BorderColor).
-			Padding(1, 2)
-
-	HeaderBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.DoubleBorder()).
-			BorderForeground(PrimaryColor).
-			Padding(0, 2).
-			Bold(true)
+	BoxStyle       lipgloss.Style
+	HeaderBoxStyle lipgloss.Style
 
 	// Badge styles
-	SuccessBadge = lipgloss.NewStyle().
-			Background(SuccessColor).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Padding(0, 1).
-			Bold(true)
-
-	WarningBadge = lipgloss.NewStyle().
-			Background(WarningColor).
-			Foreground(lipgloss.Color("#000000")).
-			Padding(0, 1).
-			Bold(true)
-
-	ErrorBadge = lipgloss.NewStyle().
-			Background(ErrorColor).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Padding(0, 1).
-			Bold(true)
-
-	InfoBadge = lipgloss.NewStyle().
-			Background(InfoColor).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Padding(0, 1).
-			Bold(true)
+	SuccessBadge lipgloss.Style
+	WarningBadge lipgloss.Style
+	ErrorBadge   lipgloss.Style
+	InfoBadge    lipgloss.Style
 )
 
 // Color print helpers using fatih/color for table compatibility
@@ -105,23 +66,272 @@ var (
 )
 
 // Status icons
-const (
-	IconSuccess  = "✓"
-	IconWarning  = "⚠"
-	IconError    = "✗"
-	IconInfo     = "ℹ"
-	IconRunning  = "●"
-	IconPending  = "○"
-	IconArrow    = "→"
-	IconBullet   = "•"
-	IconCheck    = "✔"
-	IconCross    = "✘"
-	IconStar     = "★"
-	IconDot      = "·"
-	IconPipe     = "│"
-	IconCorner   = "└"
-	IconTee      = "├"
-	IconDash     = "─"
+var (
+	IconSuccess    = "✓"
+	IconWarning    = "⚠"
+	IconError      = "✗"
+	IconInfo       = "ℹ"
+	IconRunning    = "●"
+	IconPending    = "○"
+	IconArrow      = "→"
+	IconBullet     = "•"
+	IconCheck      = "✔"
+	IconCross      = "✘"
+	IconStar       = "★"
+	IconDot        = "·"
+	IconPipe       = "│"
+	IconCorner     = "└"
+	IconTee        = "├"
+	IconDash       = "─"
 	IconDoubleDash = "═"
+	IconSection    = "▸"
 )
 
+// BadgeColors overrides a badge's foreground and background colors
+type BadgeColors struct {
+	Foreground string `mapstructure:"foreground"`
+	Background string `mapstructure:"background"`
+}
+
+// ThemeConfig is the theme section of the config file
+type ThemeConfig struct {
+	Preset string                 `mapstructure:"preset"` // dark (default), light, mono
+	ASCII  string                 `mapstructure:"ascii"`  // auto (default), true, false
+	Colors map[string]string      `mapstructure:"colors"` // primary, secondary, accent, success, warning, error, info, muted, border, text
+	Icons  map[string]string      `mapstructure:"icons"`  // success, warning, error, info, bullet, ...
+	Badges map[string]BadgeColors `mapstructure:"badges"` // success, warning, error, info
+}
+
+// palette is a full set of theme colors
+type palette struct {
+	Primary, Secondary, Accent      string
+	Success, Warning, Error, Info   string
+	Muted, Background, Border, Text string
+	BadgeText, WarningBadgeText     string
+}
+
+var presets = map[string]palette{
+	"dark": {
+		Primary: "#7C3AED", Secondary: "#06B6D4", Accent: "#F59E0B",
+		Success: "#10B981", Warning: "#F59E0B", Error: "#EF4444", Info: "#3B82F6",
+		Muted: "#6B7280", Background: "#1F2937", Border: "#374151", Text: "#E5E7EB",
+		BadgeText: "#FFFFFF", WarningBadgeText: "#000000",
+	},
+	"light": {
+		Primary: "#6D28D9", Secondary: "#0E7490", Accent: "#B45309",
+		Success: "#047857", Warning: "#B45309", Error: "#B91C1C", Info: "#1D4ED8",
+		Muted: "#4B5563", Background: "#F9FAFB", Border: "#D1D5DB", Text: "#111827",
+		BadgeText: "#FFFFFF", WarningBadgeText: "#FFFFFF",
+	},
+	// mono relies on bold/italic only
+	"mono": {},
+}
+
+var asciiIcons = map[string]string{
+	"success": "+", "warning": "!", "error": "x", "info": "i",
+	"running": "*", "pending": "o", "arrow": "->", "bullet": "*",
+	"check": "+", "cross": "x", "star": "*", "dot": ".",
+	"pipe": "|", "corner": "`", "tee": "|", "dash": "-", "double_dash": "=",
+	"section": ">",
+}
+
+var asciiBorder = lipgloss.Border{
+	Top: "-", Bottom: "-", Left: "|", Right: "|",
+	TopLeft: "+", TopRight: "+", BottomLeft: "+", BottomRight: "+",
+}
+
+var (
+	// themeMono disables table cell colors for the mono preset
+	themeMono bool
+	// themeASCII swaps box-drawing characters for plain ASCII
+	themeASCII bool
+
+	badgeOverrides map[string]BadgeColors
+	badgeText      = lipgloss.Color("#FFFFFF")
+	warnBadgeText  = lipgloss.Color("#000000")
+)
+
+func init() {
+	buildStyles()
+}
+
+// ApplyTheme applies a preset, color/icon/badge overrides and ASCII mode
+func ApplyTheme(cfg ThemeConfig) error {
+	name := strings.ToLower(cfg.Preset)
+	if name == "" {
+		name = "dark"
+	}
+	p, ok := presets[name]
+	if !ok {
+		return fmt.Errorf("unknown theme preset %q (valid: dark, light, mono)", cfg.Preset)
+	}
+
+	for key, value := range cfg.Colors {
+		switch strings.ToLower(key) {
+		case "primary":
+			p.Primary = value
+		case "secondary":
+			p.Secondary = value
+		case "accent":
+			p.Accent = value
+		case "success":
+			p.Success = value
+		case "warning":
+			p.Warning = value
+		case "error":
+			p.Error = value
+		case "info":
+			p.Info = value
+		case "muted":
+			p.Muted = value
+		case "background":
+			p.Background = value
+		case "border":
+			p.Border = value
+		case "text":
+			p.Text = value
+		default:
+			return fmt.Errorf("unknown theme color %q", key)
+		}
+	}
+
+	PrimaryColor, SecondaryColor, AccentColor = lipgloss.Color(p.Primary), lipgloss.Color(p.Secondary), lipgloss.Color(p.Accent)
+	SuccessColor, WarningColor, ErrorColor, InfoColor = lipgloss.Color(p.Success), lipgloss.Color(p.Warning), lipgloss.Color(p.Error), lipgloss.Color(p.Info)
+	MutedColor, BackgroundColor, BorderColor, TextColor = lipgloss.Color(p.Muted), lipgloss.Color(p.Background), lipgloss.Color(p.Border), lipgloss.Color(p.Text)
+	badgeText, warnBadgeText = lipgloss.Color(p.BadgeText), lipgloss.Color(p.WarningBadgeText)
+	badgeOverrides = cfg.Badges
+	themeMono = name == "mono"
+
+	switch strings.ToLower(cfg.ASCII) {
+	case "", "auto":
+		themeASCII = !utf8Locale()
+	case "true", "yes", "on":
+		themeASCII = true
+	case "false", "no", "off":
+		themeASCII = false
+	default:
+		return fmt.Errorf("invalid theme ascii value %q (valid: auto, true, false)", cfg.ASCII)
+	}
+	if themeASCII {
+		for name, icon := range asciiIcons {
+			setIcon(name, icon)
+		}
+		defaultPrinter.spinner.UpdateCharSet(spinner.CharSets[9])
+	}
+
+	for name, icon := range cfg.Icons {
+		if !setIcon(strings.ToLower(name), icon) {
+			return fmt.Errorf("unknown theme icon %q", name)
+		}
+	}
+
+	buildStyles()
+	return nil
+}
+
+// utf8Locale reports whether the terminal locale looks UTF-8 capable.
+// An unset locale is assumed to be UTF-8, as is common in containers.
+func utf8Locale() bool {
+	if os.Getenv("TERM") == "linux" {
+		return false
+	}
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := os.Getenv(key); value != "" {
+			value = strings.ToLower(value)
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+	return true
+}
+
+func setIcon(name, icon string) bool {
+	targets := map[string]*string{
+		"success": &IconSuccess, "warning": &IconWarning, "error": &IconError, "info": &IconInfo,
+		"running": &IconRunning, "pending": &IconPending, "arrow": &IconArrow, "bullet": &IconBullet,
+		"check": &IconCheck, "cross": &IconCross, "star": &IconStar, "dot": &IconDot,
+		"pipe": &IconPipe, "corner": &IconCorner, "tee": &IconTee, "dash": &IconDash,
+		"double_dash": &IconDoubleDash, "section": &IconSection,
+	}
+	target, ok := targets[name]
+	if ok {
+		*target = icon
+	}
+	return ok
+}
+
+// buildStyles derives all lipgloss styles from the current theme colors
+func buildStyles() {
+	doubleBorder := lipgloss.DoubleBorder()
+	if themeASCII {
+		doubleBorder = asciiBorder
+	}
+
+	TitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(PrimaryColor).
+		MarginBottom(1)
+
+	SubtitleStyle = lipgloss.NewStyle().
+		Foreground(SecondaryColor).
+		Italic(true)
+
+	SuccessStyle = lipgloss.NewStyle().
+		Foreground(SuccessColor).
+		Bold(true)
+
+	WarningStyle = lipgloss.NewStyle().
+		Foreground(WarningColor).
+		Bold(true)
+
+	ErrorStyle = lipgloss.NewStyle().
+		Foreground(ErrorColor).
+		Bold(true)
+
+	InfoStyle = lipgloss.NewStyle().
+		Foreground(InfoColor)
+
+	MutedStyle = lipgloss.NewStyle().
+		Foreground(MutedColor)
+
+	BoxStyle = lipgloss.NewStyle().
+		Border(roundedBorder()).
+		BorderForeground(BorderColor).
+		Padding(1, 2)
+
+	HeaderBoxStyle = lipgloss.NewStyle().
+		Border(doubleBorder).
+		BorderForeground(PrimaryColor).
+		Padding(0, 2).
+		Bold(true)
+
+	SuccessBadge = badgeStyle("success", SuccessColor, badgeText)
+	WarningBadge = badgeStyle("warning", WarningColor, warnBadgeText)
+	ErrorBadge = badgeStyle("error", ErrorColor, badgeText)
+	InfoBadge = badgeStyle("info", InfoColor, badgeText)
+}
+
+// roundedBorder returns the box border for the current character set
+func roundedBorder() lipgloss.Border {
+	if themeASCII {
+		return asciiBorder
+	}
+	return lipgloss.RoundedBorder()
+}
+
+func badgeStyle(name string, background, foreground lipgloss.Color) lipgloss.Style {
+	if override, ok := badgeOverrides[name]; ok {
+		if override.Background != "" {
+			background = lipgloss.Color(override.Background)
+		}
+		if override.Foreground != "" {
+			foreground = lipgloss.Color(override.Foreground)
+		}
+	}
+
+	return lipgloss.NewStyle().
+		Background(background).
+		Foreground(foreground).
+		Padding(0, 1).
+		Bold(true)
+}
+
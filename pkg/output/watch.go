@@ -0,0 +1,114 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// DefaultWatchInterval is how often watched commands refresh unless --interval is set
+const DefaultWatchInterval = 5 * time.Second
+
+// watchState remembers the rows of the previous frame so changed rows can be highlighted
+var watchState struct {
+	active bool
+	prev   map[string]bool
+	cur    map[string]bool
+}
+
+// Watchable adds -w/--watch and --interval to cmd. With --watch the command's
+// RunE is rerun every interval until Ctrl+C or --timeout, redrawing the screen
+// and highlighting table rows that changed since the previous refresh.
+func Watchable(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().BoolP("watch", "w", false, "Refresh the output every --interval until interrupted")
+	cmd.Flags().Duration("interval", DefaultWatchInterval, "Refresh interval for --watch")
+
+	run := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if watch, _ := cmd.Flags().GetBool("watch"); !watch {
+			return run(cmd, args)
+		}
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+		return Watch(cmd.Context(), interval, cmd.CommandPath(), func() error {
+			return run(cmd, args)
+		})
+	}
+	return cmd
+}
+
+// Watch calls frame every interval until ctx is done. The first failure is
+// returned; later ones are shown and the next refresh is attempted.
+func Watch(ctx context.Context, interval time.Duration, title string, frame func() error) error {
+	watchState.active = true
+	defer func() { watchState.active = false }()
+
+	for n := 0; ; n++ {
+		watchState.cur = map[string]bool{}
+		if !IsStructured() {
+			if IsTerminal(outFile) {
+				fmt.Fprint(out, "\033[H\033[2J")
+			}
+			Muted(fmt.Sprintf("Every %s: %s    %s    (Ctrl+C to exit)", interval, title, time.Now().Format("15:04:05")))
+			Newline()
+		}
+
+		err := frame()
+		StopSpinner()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			if n == 0 {
+				return err
+			}
+			Error(err.Error())
+		}
+		watchState.prev = watchState.cur
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// highlightChanges marks rows that were not in the previous frame of a watch
+func highlightChanges(t *Table) {
+	if !watchState.active {
+		return
+	}
+
+	for i, row := range t.rows {
+		key := t.config.Title + "\x00" + strings.Join(row, "\x00")
+		watchState.cur[key] = true
+		if watchState.prev == nil || watchState.prev[key] {
+			continue
+		}
+
+		if !colorEnabled || themeMono {
+			marked := append([]string(nil), row...)
+			if len(marked) > 0 {
+				marked[0] = "* " + marked[0]
+			}
+			t.rows[i] = marked
+			continue
+		}
+		colors := make([]tablewriter.Colors, len(row))
+		for c := range colors {
+			if t.colors[i] != nil && c < len(t.colors[i]) {
+				colors[c] = append(tablewriter.Colors{tablewriter.Bold, tablewriter.UnderlineSingle}, t.colors[i][c]...)
+			} else {
+				colors[c] = tablewriter.Colors{tablewriter.Bold, tablewriter.UnderlineSingle}
+			}
+		}
+		t.colors[i] = colors
+	}
+}
@@ -0,0 +1,158 @@
+package output
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"golang.org/x/term"
+)
+
+// minColumnWidth is the narrowest a column is wrapped to, unless its header
+// is narrower
+const minColumnWidth = 8
+
+var noTrunc bool
+
+// SetNoTrunc disables Truncate, so long values are shown in full and wrapped
+// by tables instead of cut
+func SetNoTrunc(v bool) {
+	noTrunc = v
+}
+
+// NoTrunc reports whether --no-trunc is set
+func NoTrunc() bool {
+	return noTrunc
+}
+
+// Truncate shortens s to maxLen characters with an ellipsis, unless
+// --no-trunc is set
+func Truncate(s string, maxLen int) string {
+	if noTrunc || maxLen <= 0 {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	if maxLen <= 3 {
+		return string(runes[:maxLen])
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
+
+// terminalWidth is the width of the terminal output goes to, or 0 when it is
+// not a terminal. COLUMNS overrides the detected width
+func terminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	if !IsTerminal(outFile) {
+		return 0
+	}
+	width, _, err := term.GetSize(int(outFile.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// fitColumns wraps the widest cells of rows until the table fits in width,
+// narrowing the widest column first. Headers are never wrapped
+func fitColumns(headers []string, rows [][]string, width int) [][]string {
+	widths := make([]int, len(headers))
+	floors := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = tablewriter.DisplayWidth(h)
+		floors[i] = max(widths[i], minColumnWidth)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < len(widths) {
+				widths[i] = max(widths[i], cellWidth(cell))
+			}
+		}
+	}
+	for i := range floors {
+		floors[i] = min(floors[i], widths[i])
+	}
+
+	// Each column takes its padding and a separator, plus the outer border
+	budget := width - 3*len(headers) - 1
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+	if total <= budget {
+		return rows
+	}
+	for total > budget {
+		widest := -1
+		for i, w := range widths {
+			if w > floors[i] && (widest < 0 || w > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+
+	fitted := make([][]string, len(rows))
+	for r, row := range rows {
+		fitted[r] = make([]string, len(row))
+		for i, cell := range row {
+			if i < len(widths) {
+				cell = wrapCell(cell, widths[i])
+			}
+			fitted[r][i] = cell
+		}
+	}
+	return fitted
+}
+
+func cellWidth(cell string) int {
+	w := 0
+	for _, line := range strings.Split(cell, "\n") {
+		w = max(w, tablewriter.DisplayWidth(line))
+	}
+	return w
+}
+
+// wrapCell wraps cell at word boundaries to width, splitting words that are
+// longer than width. Cells with escape sequences are left alone
+func wrapCell(cell string, width int) string {
+	if cellWidth(cell) <= width || strings.Contains(cell, "\x1b") {
+		return cell
+	}
+
+	var lines []string
+	for _, para := range strings.Split(cell, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			for tablewriter.DisplayWidth(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				runes := []rune(word)
+				lines = append(lines, string(runes[:width]))
+				word = string(runes[width:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case tablewriter.DisplayWidth(line)+1+tablewriter.DisplayWidth(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,132 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultWindow is the look-back window for historical utilization
+const DefaultWindow = "7d"
+
+// quantile is the percentile used for right-sizing
+const quantile = 0.95
+
+var windowPattern = regexp.MustCompile(`^[0-9]+[smhdwy]$`)
+
+// Usage is historical P95 utilization of a workload
+type Usage struct {
+	CPUMillicores int64 `json:"cpu_millicores"`
+	MemoryBytes   int64 `json:"memory_bytes"`
+}
+
+// PodKey identifies a pod
+type PodKey struct {
+	Namespace string
+	Name      string
+}
+
+// ValidateWindow checks a PromQL range duration such as 7d or 12h
+func ValidateWindow(window string) error {
+	if !windowPattern.MatchString(window) {
+		return fmt.Errorf("invalid window %q (use a Prometheus duration such as 7d or 12h)", window)
+	}
+	return nil
+}
+
+// PodUsage returns the P95 CPU and memory usage of pods over window (cAdvisor metrics)
+func (c *Client) PodUsage(ctx context.Context, namespace, window string) (map[PodKey]Usage, error) {
+	if err := ValidateWindow(window); err != nil {
+		return nil, err
+	}
+
+	selector := `container!="",container!="POD"`
+	if namespace != "" {
+		selector += fmt.Sprintf(`,namespace=%q`, namespace)
+	}
+
+	cpu, err := c.Query(ctx, fmt.Sprintf(
+		`quantile_over_time(%g, sum by (namespace, pod) (rate(container_cpu_usage_seconds_total{%s}[5m]))[%s:5m])`,
+		quantile, selector, window))
+	if err != nil {
+		return nil, err
+	}
+	mem, err := c.Query(ctx, fmt.Sprintf(
+		`quantile_over_time(%g, sum by (namespace, pod) (container_memory_working_set_bytes{%s})[%s:5m])`,
+		quantile, selector, window))
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[PodKey]Usage)
+	for _, s := range cpu {
+		key := PodKey{Namespace: s.Labels["namespace"], Name: s.Labels["pod"]}
+		u := usage[key]
+		u.CPUMillicores = int64(s.Value * 1000)
+		usage[key] = u
+	}
+	for _, s := range mem {
+		key := PodKey{Namespace: s.Labels["namespace"], Name: s.Labels["pod"]}
+		u := usage[key]
+		u.MemoryBytes = int64(s.Value)
+		usage[key] = u
+	}
+	return usage, nil
+}
+
+// ContainerUsage returns the P95 CPU and memory usage of Docker containers by name over window
+func (c *Client) ContainerUsage(ctx context.Context, names []string, window string) (map[string]Usage, error) {
+	if err := ValidateWindow(window); err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return map[string]Usage{}, nil
+	}
+
+	quoted := make([]string, 0, len(names))
+	for _, n := range names {
+		quoted = append(quoted, regexp.QuoteMeta(strings.TrimPrefix(n, "/")))
+	}
+	selector := fmt.Sprintf(`name=~%q`, strings.Join(quoted, "|"))
+
+	cpu, err := c.Query(ctx, fmt.Sprintf(
+		`quantile_over_time(%g, sum by (name) (rate(container_cpu_usage_seconds_total{%s}[5m]))[%s:5m])`,
+		quantile, selector, window))
+	if err != nil {
+		return nil, err
+	}
+	mem, err := c.Query(ctx, fmt.Sprintf(
+		`quantile_over_time(%g, sum by (name) (container_memory_working_set_bytes{%s})[%s:5m])`,
+		quantile, selector, window))
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]Usage)
+	for _, s := range cpu {
+		u := usage[s.Labels["name"]]
+		u.CPUMillicores = int64(s.Value * 1000)
+		usage[s.Labels["name"]] = u
+	}
+	for _, s := range mem {
+		u := usage[s.Labels["name"]]
+		u.MemoryBytes = int64(s.Value)
+		usage[s.Labels["name"]] = u
+	}
+	return usage, nil
+}
+
+// NodeCPUPercent returns the current CPU utilization of each node-exporter instance
+func (c *Client) NodeCPUPercent(ctx context.Context) (map[string]float64, error) {
+	samples, err := c.Query(ctx, `100 * (1 - avg by (instance) (rate(node_cpu_seconds_total{mode="idle"}[5m])))`)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]float64, len(samples))
+	for _, s := range samples {
+		usage[s.Labels["instance"]] = s.Value
+	}
+	return usage, nil
+}
@@ -0,0 +1,123 @@
+package resultcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrMiss is returned when no cached result is available or it is too old
+var ErrMiss = errors.New("no cached result")
+
+type entry struct {
+	SavedAt time.Time       `json:"saved_at"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Key derives a cache key from the command and everything that selects its result,
+// such as the cluster, namespace and filters
+func Key(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Save stores a result under key, replacing any previous one
+func Save(key string, v interface{}) error {
+	path, err := entryPath(key)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode cached result: %w", err)
+	}
+	raw, err := json.Marshal(entry{SavedAt: time.Now(), Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to encode cached result: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write atomically so a concurrent reader never sees a partial file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0600); err != nil {
+		return fmt.Errorf("failed to write cached result: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load decodes the result stored under key into v if it is no older than maxAge,
+// returning when it was saved
+func Load(key string, maxAge time.Duration, v interface{}) (time.Time, error) {
+	path, err := entryPath(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, ErrMiss
+	}
+
+	var e entry
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return time.Time{}, ErrMiss
+	}
+	if time.Since(e.SavedAt) > maxAge {
+		return time.Time{}, ErrMiss
+	}
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return time.Time{}, ErrMiss
+	}
+	return e.SavedAt, nil
+}
+
+// StaleNotice describes a cached result served because the backend failed
+func StaleNotice(savedAt time.Time, cause error) string {
+	age := time.Since(savedAt).Round(time.Second)
+	return fmt.Sprintf("STALE: showing cached result from %s (%s ago); backend error: %v",
+		savedAt.Format("15:04:05"), age, cause)
+}
+
+// Dir returns the toolkit's cache directory: devops-toolkit under
+// $XDG_CACHE_HOME, or under the platform user cache directory
+func Dir() (string, error) {
+	dir := os.Getenv("XDG_CACHE_HOME")
+	if dir == "" {
+		var err error
+		dir, err = os.UserCacheDir()
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, "devops-toolkit"), nil
+}
+
+// entryPath returns the file holding the result for key
+func entryPath(key string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "results", key+".json"), nil
+}
+
+// Fallback loads the result cached under key into v after cause made the live
+// request fail. It returns a notice marking the result stale, or "" when maxAge
+// is zero or nothing recent enough is cached.
+func Fallback(key string, maxAge time.Duration, v interface{}, cause error) string {
+	if maxAge <= 0 {
+		return ""
+	}
+	savedAt, err := Load(key, maxAge, v)
+	if err != nil {
+		return ""
+	}
+	return StaleNotice(savedAt, cause)
+}
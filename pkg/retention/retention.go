@@ -0,0 +1,200 @@
+package retention
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+)
+
+// Tag status selectors
+const (
+	TagStatusAny      = "any"
+	TagStatusTagged   = "tagged"
+	TagStatusUntagged = "untagged"
+)
+
+// Image is a registry image evaluated against retention rules
+type Image struct {
+	Repository string    `json:"repository"`
+	Digest     string    `json:"digest"`
+	Tags       []string  `json:"tags"`
+	PushedAt   time.Time `json:"pushed_at"`
+	Size       int64     `json:"size"`
+}
+
+// Rule selects images and decides which of them to expire.
+//
+// Rules are applied in priority order and each image is claimed by the first
+// rule whose selection matches it, so lower-priority rules never see it again.
+type Rule struct {
+	Priority    int           `json:"priority"`
+	Description string        `json:"description,omitempty"`
+	TagStatus   string        `json:"tag_status"`
+	TagPrefixes []string      `json:"tag_prefixes,omitempty"`
+	TagPatterns []string      `json:"tag_patterns,omitempty"`
+	Keep        bool          `json:"keep,omitempty"`
+	KeepLast    int           `json:"keep_last,omitempty"`
+	MaxAge      time.Duration `json:"max_age,omitempty"`
+}
+
+// Decision is the outcome of evaluating one image
+type Decision struct {
+	Image
+	Expire bool   `json:"expire"`
+	Rule   int    `json:"rule,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Evaluate applies rules to images and returns one decision per image, newest first
+func Evaluate(images []Image, rules []Rule, now time.Time) []Decision {
+	sorted := make([]Image, len(images))
+	copy(sorted, images)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].PushedAt.After(sorted[j].PushedAt)
+	})
+
+	ordered := make([]Rule, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Priority < ordered[j].Priority
+	})
+
+	decisions := make([]Decision, len(sorted))
+	claimed := make([]bool, len(sorted))
+	for i, img := range sorted {
+		decisions[i] = Decision{Image: img, Reason: "not matched by any rule"}
+	}
+
+	for _, rule := range ordered {
+		matched := 0
+		for i, img := range sorted {
+			if claimed[i] || !rule.Matches(img) {
+				continue
+			}
+			claimed[i] = true
+			matched++
+
+			d := &decisions[i]
+			d.Rule = rule.Priority
+			d.Expire, d.Reason = rule.decide(img, matched, now)
+		}
+	}
+
+	return decisions
+}
+
+// Matches reports whether the image falls under the rule's selection
+func (r Rule) Matches(img Image) bool {
+	switch r.TagStatus {
+	case TagStatusUntagged:
+		return len(img.Tags) == 0
+	case TagStatusTagged:
+		if len(img.Tags) == 0 {
+			return false
+		}
+		if len(r.TagPrefixes) == 0 && len(r.TagPatterns) == 0 {
+			return true
+		}
+		for _, tag := range img.Tags {
+			if matchesTag(tag, r.TagPrefixes, r.TagPatterns) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// decide returns whether the n-th newest image matched by the rule expires, and why
+func (r Rule) decide(img Image, n int, now time.Time) (bool, string) {
+	if r.Keep {
+		return false, "protected by rule"
+	}
+
+	overCount := r.KeepLast > 0 && n > r.KeepLast
+	age := now.Sub(img.PushedAt)
+	tooOld := r.MaxAge > 0 && age > r.MaxAge
+
+	switch {
+	case r.KeepLast > 0 && r.MaxAge > 0:
+		if overCount && tooOld {
+			return true, "beyond newest " + strconv.Itoa(r.KeepLast) + " and older than " + format.Days(r.MaxAge)
+		}
+	case r.KeepLast > 0:
+		if overCount {
+			return true, "beyond newest " + strconv.Itoa(r.KeepLast)
+		}
+	case r.MaxAge > 0:
+		if tooOld {
+			return true, "older than " + format.Days(r.MaxAge)
+		}
+	}
+
+	return false, "retained by rule"
+}
+
+func matchesTag(tag string, prefixes, patterns []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(tag, p) {
+			return true
+		}
+	}
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, tag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired returns the decisions that expire images
+func Expired(decisions []Decision) []Decision {
+	var expired []Decision
+	for _, d := range decisions {
+		if d.Expire {
+			expired = append(expired, d)
+		}
+	}
+	return expired
+}
+
+// Policy is a simple retention policy, as used by cleanup commands
+type Policy struct {
+	KeepLast     int
+	MaxAge       time.Duration
+	KeepTags     []string
+	UntaggedOnly bool
+}
+
+// Rules converts the policy into evaluation rules
+func (p Policy) Rules() []Rule {
+	var rules []Rule
+	if len(p.KeepTags) > 0 {
+		rules = append(rules, Rule{
+			Priority:    1,
+			Description: "keep protected tags",
+			TagStatus:   TagStatusTagged,
+			TagPatterns: p.KeepTags,
+			Keep:        true,
+		})
+	}
+
+	tagStatus := TagStatusAny
+	if p.UntaggedOnly {
+		tagStatus = TagStatusUntagged
+	}
+	rules = append(rules, Rule{
+		Priority:    2,
+		Description: "expire stale images",
+		TagStatus:   tagStatus,
+		KeepLast:    p.KeepLast,
+		MaxAge:      p.MaxAge,
+	})
+
+	return rules
+}
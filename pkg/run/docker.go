@@ -0,0 +1,106 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/docker"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/prometheus"
+)
+
+// ContainerOptions selects containers
+type ContainerOptions struct {
+	// All includes stopped containers
+	All bool
+	// Filter is a comma-separated list of docker filters such as "status=exited,label=app=api"
+	Filter string
+	// GPUs inspects each container for GPU allocations, which costs one API call per container
+	GPUs bool
+}
+
+// Containers lists containers matching opts
+func Containers(ctx context.Context, client *docker.Client, opts ContainerOptions) ([]docker.ContainerInfo, error) {
+	containers, err := client.ListContainers(ctx, opts.All, opts.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	if opts.GPUs {
+		gpus, err := client.GPUAllocations(ctx, containers)
+		if err != nil {
+			return nil, err
+		}
+		for i := range containers {
+			containers[i].GPU = gpus[containers[i].ID]
+		}
+	}
+	return containers, nil
+}
+
+// StatsOptions configures a container stats snapshot
+type StatsOptions struct {
+	// Prometheus, when set, adds P95 CPU and memory over Window from cAdvisor metrics
+	Prometheus *prometheus.Client
+	Window     string
+}
+
+// StatsReport is a resource usage snapshot of the running containers
+type StatsReport struct {
+	Stats []docker.ContainerStats `json:"stats"`
+	// Historical is set when the P95 fields were filled from Prometheus
+	Historical bool `json:"historical"`
+	// HasGPUs is set when any container has GPUs allocated
+	HasGPUs bool `json:"has_gpus"`
+}
+
+// Stats takes a usage snapshot of every running container
+func Stats(ctx context.Context, client *docker.Client, opts StatsOptions) (*StatsReport, error) {
+	containers, err := client.ListContainers(ctx, false, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	report := &StatsReport{Stats: []docker.ContainerStats{}}
+	if len(containers) == 0 {
+		return report, nil
+	}
+
+	report.Stats, err = client.GetContainerStats(ctx, containers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container stats: %w", err)
+	}
+
+	// GPU allocations; the stats API does not report GPU utilization
+	gpus, err := client.GPUAllocations(ctx, containers)
+	if err != nil {
+		return nil, err
+	}
+	for i := range report.Stats {
+		if gpu, ok := gpus[report.Stats[i].ID]; ok {
+			report.Stats[i].GPU = gpu
+			report.HasGPUs = true
+		}
+	}
+
+	if opts.Prometheus == nil {
+		return report, nil
+	}
+	names := make([]string, 0, len(report.Stats))
+	for _, stat := range report.Stats {
+		names = append(names, stat.Name)
+	}
+	usage, err := opts.Prometheus.ContainerUsage(ctx, names, opts.Window)
+	if err != nil {
+		return nil, err
+	}
+	for i := range report.Stats {
+		if u, ok := usage[strings.TrimPrefix(report.Stats[i].Name, "/")]; ok {
+			// 100% is one full core, matching docker stats
+			report.Stats[i].CPUP95 = float64(u.CPUMillicores) / 10
+			report.Stats[i].MemoryP95 = u.MemoryBytes
+		}
+	}
+	report.Historical = true
+	return report, nil
+}
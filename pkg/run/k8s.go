@@ -0,0 +1,223 @@
+package run
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/helm"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/k8s"
+)
+
+// PodOptions selects and orders pods
+type PodOptions struct {
+	Namespace     string
+	LabelSelector string
+	// ProblemsOnly keeps pods that are failing, pending, restarting or not ready
+	ProblemsOnly bool
+	// SortBy is name (default), namespace, status, age or restarts
+	SortBy string
+}
+
+// Pods lists pods and applies opts
+func Pods(ctx context.Context, client *k8s.Client, opts PodOptions) ([]k8s.PodInfo, error) {
+	pods, err := client.ListPods(ctx, opts.Namespace, opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	return SelectPods(pods, opts), nil
+}
+
+// SelectPods filters and sorts an already fetched pod list according to opts
+func SelectPods(pods []k8s.PodInfo, opts PodOptions) []k8s.PodInfo {
+	if opts.ProblemsOnly {
+		var filtered []k8s.PodInfo
+		for _, pod := range pods {
+			if IsProblemPod(pod) {
+				filtered = append(filtered, pod)
+			}
+		}
+		pods = filtered
+	}
+	SortPods(pods, opts.SortBy)
+	return pods
+}
+
+// IsProblemPod reports whether a pod is failing, pending, restarting often or not ready
+func IsProblemPod(pod k8s.PodInfo) bool {
+	problemStatuses := []string{
+		"CrashLoopBackOff", "Error", "Failed", "ImagePullBackOff",
+		"ErrImagePull", "Pending", "Unknown", "Terminating",
+	}
+	for _, status := range problemStatuses {
+		if strings.Contains(pod.Status, status) {
+			return true
+		}
+	}
+	return pod.Restarts > 5 || pod.ReadyContainers < pod.TotalContainers
+}
+
+// SortPods sorts pods in place by name, namespace, status, age (newest first)
+// or restarts (most first)
+func SortPods(pods []k8s.PodInfo, sortBy string) {
+	sort.Slice(pods, func(i, j int) bool {
+		switch sortBy {
+		case "status":
+			return pods[i].Status < pods[j].Status
+		case "age":
+			return pods[i].CreationTime.After(pods[j].CreationTime)
+		case "restarts":
+			return pods[i].Restarts > pods[j].Restarts
+		case "namespace":
+			if pods[i].Namespace == pods[j].Namespace {
+				return pods[i].Name < pods[j].Name
+			}
+			return pods[i].Namespace < pods[j].Namespace
+		default: // name
+			return pods[i].Name < pods[j].Name
+		}
+	})
+}
+
+// Health report sections, used as keys of HealthReport.Errors
+const (
+	SectionCluster     = "cluster"
+	SectionNodes       = "nodes"
+	SectionPods        = "pods"
+	SectionPVCs        = "pvcs"
+	SectionDeployments = "deployments"
+	SectionServices    = "services"
+	SectionHelm        = "helm"
+	SectionStorms      = "restart_storms"
+	SectionResources   = "resources"
+	SectionEvents      = "events"
+)
+
+// HealthOptions configures a cluster health check
+type HealthOptions struct {
+	Namespace string
+	// Namespaces narrows Helm releases like the client's namespace selector narrows the rest
+	Namespaces k8s.NamespaceSelector
+	// Kubeconfig and Context locate Helm's storage the same way as the client
+	Kubeconfig string
+	Context    string
+
+	StormWindow  time.Duration
+	StormMinPods int
+	EventLimit   int
+
+	// Progress, when set, is called before each section is gathered
+	Progress func(step string)
+}
+
+// HealthReport is the state of a cluster. Sections that could not be
+// gathered are nil and their error is recorded in Errors.
+type HealthReport struct {
+	Cluster      *k8s.ClusterInfo         `json:"cluster,omitempty"`
+	Nodes        *k8s.NodeHealth          `json:"nodes,omitempty"`
+	Pods         *k8s.PodHealth           `json:"pods,omitempty"`
+	PVCs         *k8s.PVCHealth           `json:"pvcs,omitempty"`
+	Deployments  *k8s.DeploymentHealth    `json:"deployments,omitempty"`
+	Services     *k8s.ServiceHealth       `json:"services,omitempty"`
+	HelmReleases []helm.Release           `json:"helm_releases,omitempty"`
+	Storms       []k8s.RestartStorm       `json:"restart_storms,omitempty"`
+	Resources    *k8s.ResourceUtilization `json:"resources,omitempty"`
+	Events       []k8s.EventInfo          `json:"events,omitempty"`
+	Errors       map[string]string        `json:"errors,omitempty"`
+}
+
+// ProblemReleases returns the Helm releases that failed or are stuck pending
+func (r *HealthReport) ProblemReleases() []helm.Release {
+	var problems []helm.Release
+	for _, rel := range r.HelmReleases {
+		if rel.Status == helm.StatusFailed || rel.IsPending() {
+			problems = append(problems, rel)
+		}
+	}
+	return problems
+}
+
+// Health gathers every section of the cluster health check. Failing sections
+// are recorded in the report rather than aborting the check.
+func Health(ctx context.Context, client *k8s.Client, opts HealthOptions) *HealthReport {
+	report := &HealthReport{Errors: map[string]string{}}
+	progress := func(step string) {
+		if opts.Progress != nil {
+			opts.Progress(step)
+		}
+	}
+	record := func(section string, err error) bool {
+		if err != nil {
+			report.Errors[section] = err.Error()
+			return false
+		}
+		return true
+	}
+
+	var err error
+	progress("Getting cluster info...")
+	report.Cluster, err = client.GetClusterInfo(ctx)
+	record(SectionCluster, err)
+
+	progress("Checking nodes...")
+	report.Nodes, err = client.GetNodeHealth(ctx)
+	record(SectionNodes, err)
+
+	progress("Checking pods...")
+	report.Pods, err = client.GetPodHealth(ctx, opts.Namespace)
+	record(SectionPods, err)
+
+	progress("Checking persistent volumes...")
+	report.PVCs, err = client.GetPVCHealth(ctx, opts.Namespace)
+	record(SectionPVCs, err)
+
+	progress("Checking deployments...")
+	report.Deployments, err = client.GetDeploymentHealth(ctx, opts.Namespace)
+	record(SectionDeployments, err)
+
+	progress("Checking services...")
+	report.Services, err = client.GetServiceHealth(ctx, opts.Namespace)
+	record(SectionServices, err)
+
+	progress("Checking Helm releases...")
+	helmClient, err := helm.NewClient(client, opts.Kubeconfig, opts.Context)
+	if record(SectionHelm, err) {
+		releases, err := helmClient.ListReleases(ctx, opts.Namespace)
+		if record(SectionHelm, err) {
+			report.HelmReleases = filterReleases(releases, opts.Namespaces)
+		}
+	}
+
+	progress("Checking for restart storms...")
+	report.Storms, err = client.DetectRestartStorms(ctx, opts.Namespace, k8s.RestartStormOptions{
+		Since:   time.Now().Add(-time.Hour),
+		Window:  opts.StormWindow,
+		MinPods: opts.StormMinPods,
+	})
+	record(SectionStorms, err)
+
+	progress("Getting resource utilization...")
+	report.Resources, err = client.GetResourceUtilization(ctx)
+	record(SectionResources, err)
+
+	progress("Getting recent events...")
+	report.Events, err = client.GetWarningEvents(ctx, opts.Namespace, opts.EventLimit)
+	record(SectionEvents, err)
+
+	return report
+}
+
+// filterReleases keeps the releases in namespaces matched by the selector
+func filterReleases(releases []helm.Release, selector k8s.NamespaceSelector) []helm.Release {
+	if selector.IsEmpty() {
+		return releases
+	}
+	var result []helm.Release
+	for _, rel := range releases {
+		if selector.Matches(rel.Namespace) {
+			result = append(result, rel)
+		}
+	}
+	return result
+}
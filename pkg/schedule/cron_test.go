@@ -0,0 +1,89 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{"0 7 * * 1", false},
+		{"@daily", false},
+		{"@Weekly", false},
+		{"*/15 9-17 * * mon-fri", false},
+		{"0 0 1,15 jan-jun *", false},
+		{"0 0 * * 7", false},
+		{"0 0 31 2,3 *", false},
+		{"0 0 30 2 mon", false}, // fires on Mondays in February
+		{"0 0 29 2 *", false},   // fires in leap years
+		{"", true},
+		{"* * * *", true},
+		{"* * * * * *", true},
+		{"60 * * * *", true},
+		{"* 24 * * *", true},
+		{"* * 0 * *", true},
+		{"* * 32 * *", true},
+		{"* * * 13 *", true},
+		{"* * * * 8", true},
+		{"*/0 * * * *", true},
+		{"5-1 * * * *", true},
+		{"* * * foo *", true},
+		{"0 0 30 2 *", true},
+		{"0 0 31 apr,jun,sep,nov *", true},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.expr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	at := func(s string) time.Time {
+		t.Helper()
+		v, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	// 2024-03-01 is a Friday
+	tests := []struct {
+		name string
+		expr string
+		from string
+		want string
+	}{
+		{"strictly after", "0 7 * * *", "2024-03-01 07:00", "2024-03-02 07:00"},
+		{"seconds are dropped", "*/15 * * * *", "2024-03-01 10:07", "2024-03-01 10:15"},
+		{"weekday", "0 7 * * 1", "2024-03-01 12:00", "2024-03-04 07:00"},
+		{"sunday as 7", "0 0 * * 7", "2024-03-01 12:00", "2024-03-03 00:00"},
+		{"weekday names", "30 8 * * mon-fri", "2024-03-02 09:00", "2024-03-04 08:30"},
+		{"month names", "0 0 1 jan,jul *", "2024-03-01 00:00", "2024-07-01 00:00"},
+		{"stepped ranges", "0-30/10 9-17/4 * * *", "2024-03-01 09:31", "2024-03-01 13:00"},
+		{"step from a value", "5/20 * * * *", "2024-03-01 10:06", "2024-03-01 10:25"},
+		{"descriptor", "@monthly", "2024-01-31 12:00", "2024-02-01 00:00"},
+		{"skips short months", "0 0 31 * *", "2024-04-01 00:00", "2024-05-31 00:00"},
+		{"day of month or weekday matches weekday", "0 9 1 * mon", "2024-04-23 10:00", "2024-04-29 09:00"},
+		{"day of month or weekday matches day", "0 9 1 * mon", "2024-04-29 10:00", "2024-05-01 09:00"},
+		{"leap day", "0 0 29 2 *", "2024-03-01 00:00", "2028-02-29 00:00"},
+		{"leap day across a skipped century", "0 0 29 2 *", "2096-03-01 00:00", "2104-02-29 00:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			from := at(tt.from).Add(30 * time.Second)
+			if got, want := c.Next(from), at(tt.want); !got.Equal(want) {
+				t.Errorf("Next(%s) = %s, want %s", from, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/exitcode"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/format"
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// runTime is the scheduled time of the run in progress, zero outside a schedule
+var runTime time.Time
+
+// Schedulable adds --schedule to cmd. With it the command stays in the
+// foreground and runs at every time matching the cron expression.
+func Schedulable(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String("schedule", "", `Run in the foreground on a cron schedule in local time, e.g. "0 7 * * 1" (Mondays 07:00)`)
+
+	run := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		expr, _ := cmd.Flags().GetString("schedule")
+		if expr == "" {
+			return run(cmd, args)
+		}
+		cron, err := Parse(expr)
+		if err != nil {
+			return exitcode.ConfigError(err)
+		}
+		return Run(cmd.Context(), cron, cmd.CommandPath(), func() error {
+			return run(cmd, args)
+		})
+	}
+	return cmd
+}
+
+// Run calls job at every time matching cron until ctx is done. A failed run
+// is shown and the schedule carries on, so one unreachable backend doesn't
+// stop next week's report.
+func Run(ctx context.Context, cron *Cron, title string, job func() error) error {
+	defer func() { runTime = time.Time{} }()
+
+	for {
+		next := cron.Next(time.Now())
+		if next.IsZero() {
+			return exitcode.ConfigError(fmt.Errorf("schedule %q never fires", cron))
+		}
+		output.Muted(fmt.Sprintf("%s: next run at %s on schedule %q (Ctrl+C to exit)", title, format.Timestamp(next), cron))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Until(next)):
+		}
+
+		runTime = next
+		output.Newline()
+		output.Header(fmt.Sprintf("Scheduled run %s", format.Timestamp(next)))
+		err := job()
+		output.StopSpinner()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			output.Error(fmt.Sprintf("Scheduled run failed: %v", err))
+		}
+		output.Newline()
+	}
+}
+
+// Stamp inserts the time of the scheduled run into path before its
+// extension (report.html becomes report-20240506-0700.html) so each run
+// keeps its own file. Outside a scheduled run path is returned unchanged.
+func Stamp(path string) string {
+	if runTime.IsZero() || path == "" {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + runTime.Format("-20060102-1504") + ext
+}
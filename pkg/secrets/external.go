@@ -0,0 +1,69 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/SiavashBeheshti/devops-toolkit/pkg/aws"
+	"gopkg.in/yaml.v3"
+)
+
+// resolveAWS reads a Secrets Manager secret using the default AWS profile and region
+// (AWS_PROFILE and AWS_REGION select others)
+func resolveAWS(ctx context.Context, ref Reference) (string, error) {
+	client, err := aws.NewClient("", "")
+	if err != nil {
+		return "", err
+	}
+
+	value, err := client.GetSecretValue(ctx, ref.Path)
+	if err != nil {
+		return "", err
+	}
+	if ref.Key == "" {
+		return value, nil
+	}
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return "", fmt.Errorf("secret is not JSON, so #%s cannot be selected", ref.Key)
+	}
+	return lookup(data, ref.Key)
+}
+
+// resolveSOPS decrypts a YAML or JSON file with the sops CLI
+func resolveSOPS(ctx context.Context, ref Reference) (string, error) {
+	if _, err := exec.LookPath("sops"); err != nil {
+		return "", fmt.Errorf("sops CLI not found in PATH")
+	}
+
+	path := ref.Path
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = home + path[1:]
+		}
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sops", "--decrypt", path)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("sops: %s", msg)
+		}
+		return "", fmt.Errorf("sops: %w", err)
+	}
+
+	// JSON is valid YAML, so one decoder handles both
+	var data interface{}
+	if err := yaml.Unmarshal(out, &data); err != nil {
+		return "", fmt.Errorf("failed to parse decrypted file: %w", err)
+	}
+	return lookup(data, ref.Key)
+}
@@ -0,0 +1,126 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reference schemes
+const (
+	SchemeVault = "vault"
+	SchemeAWS   = "awssm"
+	SchemeSOPS  = "sops"
+)
+
+// resolveTimeout bounds a single secret lookup
+const resolveTimeout = 30 * time.Second
+
+var (
+	cacheMu sync.Mutex
+	cache   = make(map[string]string)
+)
+
+// Reference is a parsed secret reference such as vault:secret/data/ci#gitlab_token
+type Reference struct {
+	Scheme string
+	Path   string
+	Key    string
+}
+
+// Parse splits a reference into scheme, path and key; ok is false for plain values
+func Parse(value string) (ref Reference, ok bool) {
+	scheme, rest, found := strings.Cut(value, ":")
+	if !found {
+		return Reference{}, false
+	}
+	switch scheme {
+	case SchemeVault, SchemeAWS, SchemeSOPS:
+	default:
+		return Reference{}, false
+	}
+
+	ref.Scheme = scheme
+	ref.Path, ref.Key, _ = strings.Cut(rest, "#")
+	return ref, true
+}
+
+// IsReference reports whether value refers to a secret store
+func IsReference(value string) bool {
+	_, ok := Parse(value)
+	return ok
+}
+
+// Resolve returns value unchanged, or the secret it references:
+//
+//	vault:<path>#<key>          HashiCorp Vault KV v1/v2 (VAULT_ADDR, VAULT_TOKEN)
+//	awssm:<secret-id>[#<key>]   AWS Secrets Manager, key of a JSON secret
+//	sops:<file>#<key.path>      SOPS-encrypted YAML/JSON file (sops CLI)
+func Resolve(value string) (string, error) {
+	ref, ok := Parse(value)
+	if !ok {
+		return value, nil
+	}
+
+	cacheMu.Lock()
+	cached, ok := cache[value]
+	cacheMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+
+	var secret string
+	var err error
+	switch ref.Scheme {
+	case SchemeVault:
+		secret, err = resolveVault(ctx, ref)
+	case SchemeAWS:
+		secret, err = resolveAWS(ctx, ref)
+	case SchemeSOPS:
+		secret, err = resolveSOPS(ctx, ref)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s secret %s: %w", ref.Scheme, ref.Path, err)
+	}
+
+	cacheMu.Lock()
+	cache[value] = secret
+	cacheMu.Unlock()
+	return secret, nil
+}
+
+// lookup walks a dotted key path through decoded JSON/YAML
+func lookup(data interface{}, key string) (string, error) {
+	if key == "" {
+		if s, ok := data.(string); ok {
+			return s, nil
+		}
+		return "", fmt.Errorf("secret is structured; add #<key> to the reference")
+	}
+
+	current := data
+	for _, part := range strings.Split(key, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("key %q not found", key)
+		}
+		current, ok = m[part]
+		if !ok {
+			return "", fmt.Errorf("key %q not found", key)
+		}
+	}
+
+	switch v := current.(type) {
+	case string:
+		return v, nil
+	case map[string]interface{}, []interface{}:
+		return "", fmt.Errorf("key %q is not a scalar", key)
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
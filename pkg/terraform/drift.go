@@ -0,0 +1,133 @@
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+)
+
+// AttributeChange is a single attribute that differs between state and reality
+type AttributeChange struct {
+	Path   string      `json:"path"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// DriftedResource is a resource whose real infrastructure no longer matches state
+type DriftedResource struct {
+	Address    string            `json:"address"`
+	Type       string            `json:"type"`
+	Action     string            `json:"action"`
+	Attributes []AttributeChange `json:"attributes,omitempty"`
+}
+
+// Deleted reports whether the resource was removed outside terraform
+func (d DriftedResource) Deleted() bool {
+	return d.Action == ActionDelete
+}
+
+// RefreshOnlyPlan runs `terraform plan -refresh-only` in dir and returns the plan
+func RefreshOnlyPlan(ctx context.Context, dir string) (*Plan, error) {
+	tmp, err := os.MkdirTemp("", "devops-toolkit-tf-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	planFile := filepath.Join(tmp, "refresh.tfplan")
+	if _, err := Run(ctx, dir, "plan", "-refresh-only", "-input=false", "-lock=false", "-no-color", "-out="+planFile); err != nil {
+		return nil, fmt.Errorf("failed to run refresh-only plan: %w", err)
+	}
+
+	out, err := Run(ctx, dir, "show", "-json", planFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render plan: %w", err)
+	}
+	return LoadPlan(ctx, out, "")
+}
+
+// DetectDrift lists the resources terraform found changed outside of state
+func DetectDrift(plan *Plan) []DriftedResource {
+	var drifted []DriftedResource
+	for _, rc := range plan.ResourceDrift {
+		if rc.Mode == "data" {
+			continue
+		}
+		action := rc.Action()
+		if action == ActionNoOp || action == ActionRead {
+			continue
+		}
+
+		d := DriftedResource{
+			Address: rc.Address,
+			Type:    rc.Type,
+			Action:  action,
+		}
+		if action == ActionUpdate {
+			d.Attributes = diffAttributes(rc.Change.Before, rc.Change.After)
+		}
+		drifted = append(drifted, d)
+	}
+
+	sort.Slice(drifted, func(i, j int) bool {
+		return drifted[i].Address < drifted[j].Address
+	})
+	return drifted
+}
+
+// diffAttributes returns the leaf attributes that differ between before and after
+func diffAttributes(before, after interface{}) []AttributeChange {
+	b := make(map[string]interface{})
+	a := make(map[string]interface{})
+	flattenAttributes("", before, b)
+	flattenAttributes("", after, a)
+
+	keys := make(map[string]bool)
+	for k := range b {
+		keys[k] = true
+	}
+	for k := range a {
+		keys[k] = true
+	}
+
+	var changes []AttributeChange
+	for k := range keys {
+		if !reflect.DeepEqual(b[k], a[k]) {
+			changes = append(changes, AttributeChange{Path: k, Before: b[k], After: a[k]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func flattenAttributes(prefix string, v interface{}, out map[string]interface{}) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if len(val) == 0 && prefix != "" {
+			out[prefix] = val
+		}
+		for k, child := range val {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flattenAttributes(key, child, out)
+		}
+	case []interface{}:
+		if len(val) == 0 && prefix != "" {
+			out[prefix] = val
+		}
+		for i, child := range val {
+			flattenAttributes(fmt.Sprintf("%s[%d]", prefix, i), child, out)
+		}
+	default:
+		if prefix != "" {
+			out[prefix] = val
+		}
+	}
+}
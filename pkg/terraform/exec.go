@@ -0,0 +1,34 @@
+package terraform
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Run executes the terraform binary in dir and returns its stdout; the process is
+// killed when ctx is cancelled
+func Run(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath("terraform"); err != nil {
+		return nil, fmt.Errorf("terraform binary not found in PATH")
+	}
+
+	if dir != "" {
+		args = append([]string{"-chdir=" + dir}, args...)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "terraform", args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("terraform %s: %s", strings.Join(args, " "), msg)
+		}
+		return nil, fmt.Errorf("terraform %s: %w", strings.Join(args, " "), err)
+	}
+	return out, nil
+}
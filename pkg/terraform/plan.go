@@ -0,0 +1,206 @@
+package terraform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Change actions as summarized by the toolkit
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionReplace = "replace"
+	ActionRead    = "read"
+	ActionNoOp    = "no-op"
+)
+
+// DefaultRiskyTypes are resource type substrings whose destruction usually loses data
+var DefaultRiskyTypes = []string{
+	"db_instance", "rds_cluster", "database", "sql", "dynamodb_table", "redis",
+	"elasticache", "s3_bucket", "storage_bucket", "storage_account", "ebs_volume",
+	"disk", "efs_file_system", "kms_key", "key_vault", "secret", "route53_zone",
+	"dns_zone", "eks_cluster", "container_cluster", "kubernetes_cluster",
+}
+
+// Plan is the subset of `terraform show -json` output used by the summarizer
+type Plan struct {
+	FormatVersion    string                  `json:"format_version"`
+	TerraformVersion string                  `json:"terraform_version"`
+	ResourceChanges  []ResourceChange        `json:"resource_changes"`
+	ResourceDrift    []ResourceChange        `json:"resource_drift"`
+	OutputChanges    map[string]OutputChange `json:"output_changes"`
+}
+
+// ResourceChange is a planned change of a single resource instance
+type ResourceChange struct {
+	Address      string `json:"address"`
+	Mode         string `json:"mode"`
+	Type         string `json:"type"`
+	Name         string `json:"name"`
+	ProviderName string `json:"provider_name"`
+	Change       struct {
+		Actions []string    `json:"actions"`
+		Before  interface{} `json:"before"`
+		After   interface{} `json:"after"`
+	} `json:"change"`
+	ActionReason string `json:"action_reason,omitempty"`
+}
+
+// OutputChange is a planned change of a root module output
+type OutputChange struct {
+	Actions []string `json:"actions"`
+}
+
+// Action collapses the plan actions into a single toolkit action
+func (rc ResourceChange) Action() string {
+	actions := rc.Change.Actions
+	switch {
+	case len(actions) == 2:
+		return ActionReplace
+	case len(actions) == 1:
+		return actions[0]
+	default:
+		return ActionNoOp
+	}
+}
+
+// TypeSummary counts changes of one resource type
+type TypeSummary struct {
+	Type    string `json:"type"`
+	Create  int    `json:"create"`
+	Update  int    `json:"update"`
+	Delete  int    `json:"delete"`
+	Replace int    `json:"replace"`
+}
+
+// Change is a single resource change in the summary
+type Change struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	Action  string `json:"action"`
+	Reason  string `json:"reason,omitempty"`
+	Risky   bool   `json:"risky"`
+}
+
+// Summary is the condensed view of a plan
+type Summary struct {
+	TerraformVersion string        `json:"terraform_version,omitempty"`
+	Create           int           `json:"create"`
+	Update           int           `json:"update"`
+	Delete           int           `json:"delete"`
+	Replace          int           `json:"replace"`
+	Unchanged        int           `json:"unchanged"`
+	OutputsChanged   int           `json:"outputs_changed"`
+	ByType           []TypeSummary `json:"by_type"`
+	Changes          []Change      `json:"changes"`
+	Risky            []Change      `json:"risky"`
+}
+
+// HasChanges reports whether the plan changes any resource
+func (s Summary) HasChanges() bool {
+	return s.Create+s.Update+s.Delete+s.Replace > 0
+}
+
+// LoadPlan parses plan JSON, rendering binary plan files with `terraform show -json`
+func LoadPlan(ctx context.Context, data []byte, path string) (*Plan, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] != '{' {
+		if path == "" {
+			return nil, fmt.Errorf("input is not plan JSON; pipe the output of 'terraform show -json'")
+		}
+		out, err := Run(ctx, "", "show", "-json", path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render plan: %w", err)
+		}
+		trimmed = out
+	}
+
+	var plan Plan
+	if err := json.Unmarshal(trimmed, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan JSON: %w", err)
+	}
+	if plan.FormatVersion == "" {
+		return nil, fmt.Errorf("input does not look like terraform plan JSON")
+	}
+	return &plan, nil
+}
+
+// Summarize counts the plan's changes and flags risky destroys
+func Summarize(plan *Plan, riskyTypes []string) Summary {
+	summary := Summary{TerraformVersion: plan.TerraformVersion}
+	byType := make(map[string]*TypeSummary)
+
+	for _, rc := range plan.ResourceChanges {
+		if rc.Mode == "data" {
+			continue
+		}
+
+		action := rc.Action()
+		if action == ActionNoOp || action == ActionRead {
+			summary.Unchanged++
+			continue
+		}
+
+		ts, ok := byType[rc.Type]
+		if !ok {
+			ts = &TypeSummary{Type: rc.Type}
+			byType[rc.Type] = ts
+		}
+
+		switch action {
+		case ActionCreate:
+			summary.Create++
+			ts.Create++
+		case ActionUpdate:
+			summary.Update++
+			ts.Update++
+		case ActionDelete:
+			summary.Delete++
+			ts.Delete++
+		case ActionReplace:
+			summary.Replace++
+			ts.Replace++
+		}
+
+		change := Change{
+			Address: rc.Address,
+			Type:    rc.Type,
+			Action:  action,
+			Reason:  strings.ReplaceAll(rc.ActionReason, "_", " "),
+		}
+		if (action == ActionDelete || action == ActionReplace) && isRiskyType(rc.Type, riskyTypes) {
+			change.Risky = true
+			summary.Risky = append(summary.Risky, change)
+		}
+		summary.Changes = append(summary.Changes, change)
+	}
+
+	for _, oc := range plan.OutputChanges {
+		if len(oc.Actions) > 0 && oc.Actions[0] != ActionNoOp {
+			summary.OutputsChanged++
+		}
+	}
+
+	for _, ts := range byType {
+		summary.ByType = append(summary.ByType, *ts)
+	}
+	sort.Slice(summary.ByType, func(i, j int) bool {
+		return summary.ByType[i].Type < summary.ByType[j].Type
+	})
+
+	return summary
+}
+
+func isRiskyType(resourceType string, riskyTypes []string) bool {
+	for _, t := range riskyTypes {
+		if t != "" && strings.Contains(resourceType, t) {
+			return true
+		}
+	}
+	return false
+}
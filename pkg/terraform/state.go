@@ -0,0 +1,181 @@
+package terraform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Resource is a resource instance recorded in state
+type Resource struct {
+	Address  string                 `json:"address"`
+	Module   string                 `json:"module,omitempty"`
+	Mode     string                 `json:"mode"`
+	Type     string                 `json:"type"`
+	Name     string                 `json:"name"`
+	Provider string                 `json:"provider"`
+	Values   map[string]interface{} `json:"values,omitempty"`
+}
+
+// State is a flattened view of terraform state
+type State struct {
+	TerraformVersion string     `json:"terraform_version,omitempty"`
+	Resources        []Resource `json:"resources"`
+}
+
+// Find returns the resource with the given address
+func (s *State) Find(address string) (*Resource, bool) {
+	for i := range s.Resources {
+		if s.Resources[i].Address == address {
+			return &s.Resources[i], true
+		}
+	}
+	return nil, false
+}
+
+// showState mirrors the state section of `terraform show -json`
+type showState struct {
+	FormatVersion    string `json:"format_version"`
+	TerraformVersion string `json:"terraform_version"`
+	Values           *struct {
+		RootModule showModule `json:"root_module"`
+	} `json:"values"`
+}
+
+type showModule struct {
+	Address   string `json:"address"`
+	Resources []struct {
+		Address      string                 `json:"address"`
+		Mode         string                 `json:"mode"`
+		Type         string                 `json:"type"`
+		Name         string                 `json:"name"`
+		ProviderName string                 `json:"provider_name"`
+		Values       map[string]interface{} `json:"values"`
+	} `json:"resources"`
+	ChildModules []showModule `json:"child_modules"`
+}
+
+// rawState mirrors a version 4 .tfstate file
+type rawState struct {
+	Version          int    `json:"version"`
+	TerraformVersion string `json:"terraform_version"`
+	Resources        []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Provider  string `json:"provider"`
+		Instances []struct {
+			IndexKey   interface{}            `json:"index_key"`
+			Attributes map[string]interface{} `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+// ReadState loads the state of the working directory with `terraform show -json`
+func ReadState(ctx context.Context, dir string) (*State, error) {
+	out, err := Run(ctx, dir, "show", "-json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+	return LoadState(out)
+}
+
+// LoadState parses `terraform show -json` output or a raw .tfstate file
+func LoadState(data []byte) (*State, error) {
+	var probe struct {
+		FormatVersion string `json:"format_version"`
+		Version       int    `json:"version"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse state JSON: %w", err)
+	}
+
+	var state *State
+	switch {
+	case probe.FormatVersion != "":
+		var show showState
+		if err := json.Unmarshal(data, &show); err != nil {
+			return nil, fmt.Errorf("failed to parse state JSON: %w", err)
+		}
+		state = &State{TerraformVersion: show.TerraformVersion}
+		if show.Values != nil {
+			state.Resources = flattenModule(show.Values.RootModule)
+		}
+	case probe.Version == 4:
+		var raw rawState
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse state file: %w", err)
+		}
+		state = fromRawState(raw)
+	default:
+		return nil, fmt.Errorf("unsupported state format; use 'terraform show -json' output or a version 4 .tfstate file")
+	}
+
+	sort.Slice(state.Resources, func(i, j int) bool {
+		return state.Resources[i].Address < state.Resources[j].Address
+	})
+	return state, nil
+}
+
+func flattenModule(m showModule) []Resource {
+	var resources []Resource
+	for _, r := range m.Resources {
+		resources = append(resources, Resource{
+			Address:  r.Address,
+			Module:   m.Address,
+			Mode:     r.Mode,
+			Type:     r.Type,
+			Name:     r.Name,
+			Provider: r.ProviderName,
+			Values:   r.Values,
+		})
+	}
+	for _, child := range m.ChildModules {
+		resources = append(resources, flattenModule(child)...)
+	}
+	return resources
+}
+
+func fromRawState(raw rawState) *State {
+	state := &State{TerraformVersion: raw.TerraformVersion}
+	for _, r := range raw.Resources {
+		base := r.Type + "." + r.Name
+		if r.Mode == "data" {
+			base = "data." + base
+		}
+		if r.Module != "" {
+			base = r.Module + "." + base
+		}
+
+		for _, inst := range r.Instances {
+			address := base
+			switch key := inst.IndexKey.(type) {
+			case string:
+				address += fmt.Sprintf("[%q]", key)
+			case float64:
+				address += fmt.Sprintf("[%d]", int(key))
+			}
+
+			state.Resources = append(state.Resources, Resource{
+				Address:  address,
+				Module:   r.Module,
+				Mode:     r.Mode,
+				Type:     r.Type,
+				Name:     r.Name,
+				Provider: providerName(r.Provider),
+				Values:   inst.Attributes,
+			})
+		}
+	}
+	return state
+}
+
+// providerName trims `provider["registry.terraform.io/hashicorp/aws"]` to its source
+func providerName(p string) string {
+	p = strings.TrimPrefix(p, "provider[")
+	p = strings.TrimSuffix(p, "]")
+	return strings.Trim(p, `"`)
+}